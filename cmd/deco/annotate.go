@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupAnnotateCommand declares the annotate flags; the command proposes the @Summary, body @Param and
+// @Response decorators missing from the handlers as a unified diff, or applies them with --write
+func setupAnnotateCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path")
+	write := fs.Bool("write", false, "Insert the decorators in the files instead of printing a diff")
+
+	return func(args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+
+		files := args
+		if len(files) == 0 {
+			config, err := decorators.LoadConfig(*configPath)
+			if err != nil {
+				return fmt.Errorf("error loading configuration: %v", err)
+			}
+			if files, err = config.DiscoverHandlers(wd); err != nil {
+				return fmt.Errorf("error discovering handlers: %v", err)
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no handlers found with configured patterns")
+			}
+		}
+
+		handlers := 0
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			annotations, updated, err := decorators.AnnotateSource(file, content)
+			if err != nil {
+				return err
+			}
+			if len(annotations) == 0 {
+				continue
+			}
+			handlers += len(annotations)
+
+			name := file
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				name = rel
+			}
+			if !*write {
+				fmt.Print(decorators.AnnotationDiff(filepath.ToSlash(name), content, annotations))
+				continue
+			}
+			if err := os.WriteFile(file, updated, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("error writing %s: %v", name, err)
+			}
+			fmt.Fprintf(os.Stderr, "✏️  %s: %d handler(s) annotated\n", name, len(annotations))
+		}
+
+		if handlers == 0 {
+			fmt.Fprintln(os.Stderr, "✅ No missing annotations")
+		} else if !*write {
+			fmt.Fprintf(os.Stderr, "💡 %d handler(s) can be annotated: review the diff, then run with --write or pipe it to git apply\n", handlers)
+		}
+		return nil
+	}
+}
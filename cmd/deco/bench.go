@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupBenchCommand declares the bench flags; the command measures the per-request overhead of
+// decorator chains and fails when an overhead exceeds its budget in bench.budgets
+func setupBenchCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path")
+	duration := fs.String("duration", "", "Measured time per scenario (default: bench.duration or 2s)")
+	concurrency := fs.Int("concurrency", 0, "Concurrent clients (default: bench.concurrency or GOMAXPROCS)")
+	only := fs.String("scenario", "", "Comma-separated scenarios to run (default: all)")
+	format := fs.String("format", "text", "Output format: text or json")
+
+	return func(_ []string) error {
+		if *format != "text" && *format != "json" {
+			return fmt.Errorf("unknown format '%s' (valid: text, json)", *format)
+		}
+
+		config, err := decorators.LoadConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("error loading configuration: %v", err)
+		}
+
+		options := decorators.BenchOptions{Concurrency: config.Bench.Concurrency}
+		if *concurrency > 0 {
+			options.Concurrency = *concurrency
+		}
+		if value := firstNonEmpty(*duration, config.Bench.Duration); value != "" {
+			if options.Duration, err = decorators.ParseDurationLiteral(value); err != nil {
+				return fmt.Errorf("invalid duration: %v", err)
+			}
+		}
+
+		scenarios := decorators.BenchScenariosFromConfig(config.Bench)
+		if *only != "" {
+			selected := make(map[string]bool)
+			for _, name := range strings.Split(*only, ",") {
+				selected[strings.TrimSpace(name)] = true
+			}
+			var filtered []decorators.BenchScenario
+			for _, scenario := range scenarios {
+				if selected[scenario.Name] {
+					filtered = append(filtered, scenario)
+					delete(selected, scenario.Name)
+				}
+			}
+			for name := range selected {
+				return fmt.Errorf("unknown scenario '%s'", name)
+			}
+			scenarios = filtered
+		}
+
+		gin.SetMode(gin.ReleaseMode)
+		decorators.SetLogLevel(decorators.LogLevelSilent)
+		results, err := decorators.RunBenchmarks(scenarios, options)
+		if err != nil {
+			return err
+		}
+		violations, err := decorators.CheckBenchBudgets(results, config.Bench.Budgets)
+		if err != nil {
+			return err
+		}
+
+		if *format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(results); err != nil {
+				return err
+			}
+		} else {
+			printBenchResults(results)
+		}
+
+		if len(violations) > 0 {
+			for _, violation := range violations {
+				fmt.Fprintf(os.Stderr, "❌ %s\n", violation)
+			}
+			return fmt.Errorf("%d scenario(s) over budget", len(violations))
+		}
+		return nil
+	}
+}
+
+// printBenchResults prints the results as a table
+func printBenchResults(results []decorators.BenchResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "SCENARIO\tREQ/S\tLATENCY\tOVERHEAD\tALLOCS/OP\tBUDGET\t")
+	for _, result := range results {
+		budget := "-"
+		if result.Budget > 0 {
+			budget = result.Budget.String()
+			if result.Exceeded {
+				budget += " ❌"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%.0f\t%s\t%s\t%.1f\t%s\t\n", result.Scenario, result.RequestsPerSec,
+			result.Latency.Round(10*time.Nanosecond), result.Overhead.Round(10*time.Nanosecond), result.AllocsPerOp, budget)
+	}
+	_ = w.Flush()
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
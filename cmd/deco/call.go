@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// multiFlag repeatable string flag (-H, -q)
+type multiFlag []string
+
+func (m *multiFlag) String() string { return strings.Join(*m, ", ") }
+
+func (m *multiFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+// callOptions options of the call command
+type callOptions struct {
+	method   string
+	path     string
+	server   string
+	specPath string
+	auth     string
+	data     string
+	headers  map[string]string
+	query    map[string]string
+	timeout  time.Duration
+	prompt   bool
+	verbose  bool
+}
+
+// setupCallCommand declares the call flags; "deco call METHOD PATH [options]"
+func setupCallCommand(fs *flag.FlagSet) func(args []string) error {
+	var headers, query multiFlag
+	server := fs.String("server", envOrDefault("DECO_SERVER", "http://localhost:8080"), "Base URL of the running server (env DECO_SERVER)")
+	specPath := fs.String("spec", "", "OpenAPI JSON file (default: <server>/decorators/openapi.json)")
+	auth := fs.String("auth", "", "Bearer token sent in the Authorization header")
+	data := fs.String("d", "", "Request body (JSON), or @file to read it from a file")
+	timeout := fs.Duration("timeout", 30*time.Second, "Request timeout")
+	noPrompt := fs.Bool("no-prompt", false, "Fail instead of prompting for missing required parameters")
+	verbose := fs.Bool("v", false, "Verbose output")
+	fs.Var(&headers, "H", "Request header 'Name: value' (repeatable)")
+	fs.Var(&query, "q", "Query parameter name=value (repeatable)")
+
+	return func(args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("expected METHOD and PATH, got %d argument(s)", len(args))
+		}
+		options, err := newCallOptions(args[0], args[1], *data, headers, query)
+		if err != nil {
+			return err
+		}
+		options.server = strings.TrimSuffix(*server, "/")
+		options.specPath = *specPath
+		options.auth = *auth
+		options.timeout = *timeout
+		options.prompt = !*noPrompt
+		options.verbose = *verbose
+		return handleCallCommand(options)
+	}
+}
+
+// handleCallCommand calls an endpoint of the running server, guided by its OpenAPI contract
+func handleCallCommand(options *callOptions) error {
+	spec, err := loadCallSpec(options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v; calling without the contract\n", err)
+	}
+
+	var match *decorators.SpecOperationMatch
+	if spec != nil {
+		var found bool
+		match, found = decorators.MatchSpecOperation(spec, options.method, options.path)
+		if !found {
+			return fmt.Errorf("%s %s is not in the API contract", options.method, options.path)
+		}
+		if err := completeCallArgs(options, match, bufio.NewReader(os.Stdin)); err != nil {
+			return err
+		}
+		if options.verbose {
+			fmt.Fprintf(os.Stderr, "📋 %s %s (%s)\n", match.Method, match.Template, match.Operation.OperationID)
+		}
+	}
+
+	return executeCall(options, match)
+}
+
+// newCallOptions builds the request from METHOD, PATH, the body and the -H/-q flags
+func newCallOptions(method, path, data string, headers, query multiFlag) (*callOptions, error) {
+	options := &callOptions{
+		method:  strings.ToUpper(method),
+		path:    path,
+		headers: make(map[string]string),
+		query:   make(map[string]string),
+	}
+	if !strings.HasPrefix(options.path, "/") {
+		options.path = "/" + options.path
+	}
+
+	for _, header := range headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid header '%s', expected 'Name: value'", header)
+		}
+		options.headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	for _, param := range query {
+		name, value, found := strings.Cut(param, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid query parameter '%s', expected name=value", param)
+		}
+		options.query[name] = value
+	}
+
+	// Query parameters may also be written in the path
+	if path, rawQuery, found := strings.Cut(options.path, "?"); found {
+		options.path = path
+		values, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query string: %v", err)
+		}
+		for name := range values {
+			options.query[name] = values.Get(name)
+		}
+	}
+
+	if strings.HasPrefix(data, "@") {
+		body, err := os.ReadFile(strings.TrimPrefix(data, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading body: %v", err)
+		}
+		options.data = string(body)
+	} else {
+		options.data = data
+	}
+
+	return options, nil
+}
+
+// envOrDefault returns an environment variable or a fallback
+func envOrDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// loadCallSpec loads the contract from --spec or from the running server
+func loadCallSpec(options *callOptions) (*decorators.OpenAPISpec, error) {
+	if options.specPath != "" {
+		return decorators.LoadOpenAPISpec(options.specPath)
+	}
+
+	client := &http.Client{Timeout: options.timeout}
+	resp, err := client.Get(options.server + "/decorators/openapi.json")
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the API contract: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch the API contract: %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the API contract: %v", err)
+	}
+	return decorators.ParseOpenAPISpec(data)
+}
+
+// completeCallArgs prompts for the required parameters and body missing from the command line
+func completeCallArgs(options *callOptions, match *decorators.SpecOperationMatch, input *bufio.Reader) error {
+	for _, param := range match.MissingParameters(options.query, options.headers) {
+		value, err := promptValue(options, input, fmt.Sprintf("%s parameter '%s'", param.In, param.Name), param.Description)
+		if err != nil {
+			return err
+		}
+		if param.In == "header" {
+			options.headers[param.Name] = value
+		} else {
+			options.query[param.Name] = value
+		}
+	}
+
+	if match.RequiresBody() && options.data == "" {
+		value, err := promptValue(options, input, "request body (JSON)", "")
+		if err != nil {
+			return err
+		}
+		options.data = value
+	}
+	return nil
+}
+
+// promptValue reads one line from input for a missing value
+func promptValue(options *callOptions, input *bufio.Reader, what, description string) (string, error) {
+	if !options.prompt {
+		return "", fmt.Errorf("missing required %s", what)
+	}
+
+	if description != "" {
+		fmt.Fprintf(os.Stderr, "%s (%s): ", what, description)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", what)
+	}
+	line, err := input.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return "", fmt.Errorf("missing required %s", what)
+	}
+	return line, nil
+}
+
+// executeCall sends the request and pretty-prints the response
+func executeCall(options *callOptions, match *decorators.SpecOperationMatch) error {
+	target := options.server + options.path
+	if len(options.query) > 0 {
+		values := url.Values{}
+		for name, value := range options.query {
+			values.Set(name, value)
+		}
+		target += "?" + values.Encode()
+	}
+
+	var body io.Reader = http.NoBody
+	if options.data != "" {
+		body = strings.NewReader(options.data)
+	}
+	req, err := http.NewRequest(options.method, target, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	if options.data != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "deco-call/1.0.0")
+	if options.auth != "" {
+		req.Header.Set("Authorization", "Bearer "+options.auth)
+	}
+	for name, value := range options.headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := (&http.Client{Timeout: options.timeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %v", err)
+	}
+
+	status := fmt.Sprintf("%s %s (%v)", resp.Proto, resp.Status, time.Since(start).Round(time.Millisecond))
+	if match != nil {
+		if responseType := match.ResponseType(resp.StatusCode); responseType != "" {
+			status += " → " + responseType
+		}
+	}
+	fmt.Fprintln(os.Stderr, status)
+
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, data, "", "  ") == nil {
+		fmt.Println(pretty.String())
+	} else if len(data) > 0 {
+		fmt.Println(string(data))
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// cliVersion version printed by --version, help and man pages
+const cliVersion = "v1.0.0"
+
+// command a deco command. Its flags drive parsing, help, shell completion and man pages, so a
+// new command is discoverable by declaring it in cliCommands.
+type command struct {
+	name        string
+	summary     string
+	usage       string   // arguments after the command path, e.g. "METHOD PATH [options]"
+	details     string   // extra help paragraphs
+	examples    []string // full command lines, "# comment" allowed
+	args        []string // completion candidates for the positional arguments
+	setup       func(fs *flag.FlagSet) func(args []string) error
+	subcommands []*command
+
+	path string // "config print", set by linkCommands
+}
+
+// cliCommands the command tree, in help order; generate runs when no command is given
+func cliCommands() []*command {
+	commands := []*command{
+		{
+			name:    "init",
+			summary: "Create .deco.yaml configuration file",
+			usage:   "[-v] [--output text|json]",
+			examples: []string{
+				"deco init",
+			},
+			setup: setupInitCommand,
+		},
+		{
+			name:    "generate",
+			summary: "Generate code based on configuration (default command)",
+			usage:   "[options]",
+			details: "Without a configuration file, -root, -out and -pkg select the legacy mode.\n" +
+				"With -all, each service of the workspace is generated from its own directory: those listed in\n" +
+				"deco.workspace.yaml, or every .deco.yaml below the current directory.\n" +
+				"With --output json, a report (files, routes, errors with file and line) is printed on stdout.",
+			examples: []string{
+				"deco                                         # Use .deco.yaml",
+				"deco -config custom.yaml                     # Use custom configuration",
+				"deco -root ./handlers -out ./init.go -pkg handlers  # Legacy mode",
+				"deco generate -all                           # Every service of a monorepo",
+				"deco generate --output json                  # Machine-readable report",
+			},
+			setup: setupGenerateCommand,
+		},
+		{
+			name:    "dev",
+			summary: "Start development server with hot reload",
+			usage:   "[--port 8080] [-v] [--output text|json]",
+			details: "With --output json, one report per (re)generation is printed on stdout.",
+			examples: []string{
+				"deco dev --port 3000",
+			},
+			setup: setupDevCommand,
+		},
+		{
+			name:    "routes",
+			summary: "List the routes declared by the handlers",
+			usage:   "[--config file] [--output text|json]",
+			examples: []string{
+				"deco routes",
+				"deco routes --output json | jq '.routes[].path'",
+			},
+			setup: setupRoutesCommand,
+		},
+		{
+			name:    "doctor",
+			summary: "Diagnose the project: configuration, decorators, routes, generated code, Redis, telemetry",
+			usage:   "[--config file] [--timeout 5s] [--output text|json]",
+			details: "Each problem is printed with its file:line and the fix to apply. Checks: .deco.yaml validation,\n" +
+				"decorator errors, duplicate routes, decorators referencing unknown schemas, build of the\n" +
+				"generated file, and connectivity of Redis and the telemetry collector when enabled.\n" +
+				"Exits non-zero when an error is found.",
+			examples: []string{
+				"deco doctor",
+				"deco doctor --output json | jq '.checks[] | select(.status != \"ok\")'",
+			},
+			setup: setupDoctorCommand,
+		},
+		{
+			name:    "lsp",
+			summary: "Start the language server for decorators (stdio)",
+			details: "Speaks the Language Server Protocol on stdin/stdout: diagnostics of the decorators with the\n" +
+				"checks of deco generate, completion of marker names and arguments, and hover documentation.\n" +
+				"Run it for Go files next to gopls.",
+			examples: []string{
+				"deco lsp",
+			},
+			setup: setupLSPCommand,
+		},
+		{
+			name:    "call",
+			summary: "Call an endpoint of the running server using its API contract",
+			usage:   "METHOD PATH [options]",
+			details: "Missing required parameters are prompted for, guided by /decorators/openapi.json.",
+			examples: []string{
+				"deco call GET /users/42 --auth $TOKEN",
+				"deco call POST /users -d @user.json -H 'X-Tenant: acme'",
+			},
+			args:  []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			setup: setupCallCommand,
+		},
+		{
+			name:    "test",
+			summary: "Run the HTTP scenarios of a YAML file against the running server",
+			usage:   "FILE [--target URL] [--var name=value] [--output text|json]",
+			details: "Each scenario runs its steps in order: a request, assertions on the status, headers, body and\n" +
+				"JSON paths, and variables captured for the next steps (${name}). Steps are matched against the\n" +
+				"routes of /decorators/openapi.json and the routes no step called are listed. In Go tests,\n" +
+				"decotest.RunScenarios runs the same file against the engine in process.",
+			examples: []string{
+				"deco test scenarios.yaml",
+				"deco test scenarios.yaml --target http://staging:8080 --var token=$TOKEN",
+			},
+			setup: setupTestCommand,
+		},
+		{
+			name:    "load",
+			summary: "Send load generated from the API contract and report latency percentiles per route",
+			usage:   "[--rps N] [--duration 2m] [--ramp 10s] [--routes \"GET /users\"] [--output text|json]",
+			details: "Requests are built from /decorators/openapi.json (or --spec): path and required query\n" +
+				"parameters and JSON bodies are generated from their schemas (examples, enums, formats and\n" +
+				"bounds). The rate climbs linearly during --ramp and is held whatever the latency; requests\n" +
+				"over --concurrency are dropped and counted. The server histograms on --metrics are read\n" +
+				"before and after the run to print the server-side latency beside the client one.",
+			examples: []string{
+				"deco load --rps 500 --duration 2m --routes \"GET /users\"",
+				"deco load --routes \"GET /users/42,POST /users\" --auth $TOKEN --output json",
+			},
+			setup: setupLoadCommand,
+		},
+		{
+			name:    "mock",
+			summary: "Serve example responses of the API contract, before the handlers are implemented",
+			usage:   "[--port 9090] [--spec file.json] [--seed N] [--cors=false] [--config file]",
+			details: "The contract is generated from the decorators of the handlers (or read from --spec). Each\n" +
+				"operation answers its first 2xx response with the @Response example, or a body generated from\n" +
+				"the registered schema (examples, enums, formats and bounds); path parameters fill the properties\n" +
+				"of the same name. 'Prefer: code=404' selects another declared response and 'Prefer: example=name'\n" +
+				"a named example. Responses carry the X-Deco-Mock header.",
+			examples: []string{
+				"deco mock --port 9090",
+				"curl -H 'Prefer: code=404' localhost:9090/users/42",
+			},
+			setup: setupMockCommand,
+		},
+		{
+			name:    "openapi",
+			summary: "Compare versions of the API contract",
+			subcommands: []*command{
+				{
+					name:    "diff",
+					summary: "Report the changes between two contracts and fail on breaking ones",
+					usage:   "OLD.json [NEW.json] | --base REF [NEW.json] [--fail-on breaking|any|none] [--output text|json]",
+					details: "Without NEW.json the current contract is generated from the handlers; with --base the\n" +
+						"previous one is generated from the handlers at a git ref. Added, removed and changed paths,\n" +
+						"parameters and schemas are listed. Breaking changes (removed endpoint or success response,\n" +
+						"new required parameter or request property, changed type, removed response property,\n" +
+						"authentication added...) exit non-zero, to gate merges.",
+					examples: []string{
+						"deco openapi diff --base origin/main",
+						"deco openapi diff old.json new.json --output json",
+					},
+					setup: setupOpenAPIDiffCommand,
+				},
+			},
+		},
+		{
+			name:    "graph",
+			summary: "Print the route dependency graph (dot or mermaid)",
+			usage:   "[--format dot|mermaid] [-o file]",
+			examples: []string{
+				"deco graph --format dot | dot -Tsvg > routes.svg",
+				"deco graph --format mermaid -o routes.mmd",
+			},
+			setup: setupGraphCommand,
+		},
+		{
+			name:    "annotate",
+			summary: "Propose the @Summary/@Param/@Response decorators missing from handlers",
+			usage:   "[--write] [--config file] [files...]",
+			details: "Statuses are read from c.JSON, c.Status, c.AbortWithStatus... calls and the body type from\n" +
+				"c.ShouldBindJSON; the summary from the doc comment or the function name. Without --write a\n" +
+				"unified diff is printed, ready for git apply.",
+			examples: []string{
+				"deco annotate > annotations.patch && git apply annotations.patch",
+				"deco annotate --write handlers/users.go",
+			},
+			setup: setupAnnotateCommand,
+		},
+		{
+			name:    "learn",
+			summary: "Propose the @Param/@Response/@Schema decorators learned from recorded traffic",
+			usage:   "[--server URL] [--write] [--report] [--config file]",
+			details: "The server must run with dev.learn enabled: it records the shape of the query parameters,\n" +
+				"request bodies and responses of each route (never their values) and serves the decorators\n" +
+				"they lack on /decorators/debug/learn. Without --write a unified diff is printed,\n" +
+				"ready for git apply.",
+			examples: []string{
+				"deco learn > learned.patch && git apply learned.patch",
+				"deco learn --server http://staging:8080 --report",
+			},
+			setup: setupLearnCommand,
+		},
+		{
+			name:    "bench",
+			summary: "Measure middleware overhead and check the budgets in bench.budgets",
+			usage:   "[--duration 2s] [--concurrency N] [--scenario Cache,chain] [--format text|json]",
+			details: "Budgets (max overhead per request over the baseline) are read from .deco.yaml:\n\n" +
+				"  bench:\n    budgets:\n      Cache: 20us\n      chain: 80us",
+			examples: []string{
+				"deco bench --duration 5s",
+				"deco bench --scenario Cache,chain --format json",
+			},
+			setup: setupBenchCommand,
+		},
+		{
+			name:    "config",
+			summary: "Validate, print or describe the configuration",
+			subcommands: []*command{
+				{
+					name:    "validate",
+					summary: "Check .deco.yaml (unknown keys, types, durations) for CI",
+					usage:   "[--config file]",
+					examples: []string{
+						"deco config validate --config .deco.yaml",
+					},
+					setup: setupConfigValidateCommand,
+				},
+				{
+					name:    "print",
+					summary: "Print the configuration (--effective: defaults + file + env + flags)",
+					usage:   "[--effective] [--set key=value] [--format yaml|json]",
+					examples: []string{
+						"deco config print --effective --format json",
+						"deco config print --set redis.enabled=true",
+					},
+					setup: setupConfigPrintCommand,
+				},
+				{
+					name:    "schema",
+					summary: "Print the JSON Schema of .deco.yaml for editors",
+					usage:   "[-o file]",
+					examples: []string{
+						"deco config schema -o .vscode/deco.schema.json",
+					},
+					setup: setupConfigSchemaCommand,
+				},
+			},
+		},
+		{
+			name:    "completion",
+			summary: "Print the shell completion script (bash, zsh or fish)",
+			usage:   "bash|zsh|fish",
+			examples: []string{
+				"source <(deco completion bash)",
+				"deco completion zsh > \"${fpath[1]}/_deco\"",
+				"deco completion fish > ~/.config/fish/completions/deco.fish",
+			},
+			args:  []string{"bash", "zsh", "fish"},
+			setup: setupCompletionCommand,
+		},
+		{
+			name:    "man",
+			summary: "Print the deco(1) man page",
+			usage:   "[-o file]",
+			examples: []string{
+				"deco man -o /usr/local/share/man/man1/deco.1",
+			},
+			setup: setupManCommand,
+		},
+	}
+
+	help := &command{
+		name:    "help",
+		summary: "Show the help of a command",
+		usage:   "[command]",
+		examples: []string{
+			"deco help call",
+		},
+		setup: setupHelpCommand,
+	}
+	for _, cmd := range commands {
+		help.args = append(help.args, cmd.name)
+	}
+	commands = append(commands, help)
+
+	linkCommands(commands, "")
+	return commands
+}
+
+// linkCommands sets the path of each command
+func linkCommands(commands []*command, parent string) {
+	for _, cmd := range commands {
+		cmd.path = strings.TrimSpace(parent + " " + cmd.name)
+		linkCommands(cmd.subcommands, cmd.path)
+	}
+}
+
+// findCommand looks a command up by name
+func findCommand(commands []*command, name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// runCLI runs the command selected by args (os.Args without the program name)
+func runCLI(args []string) error {
+	commands := cliCommands()
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		printRootHelp(os.Stdout, commands)
+		return nil
+	}
+
+	name := "generate"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	cmd := findCommand(commands, name)
+	if cmd == nil {
+		printRootHelp(os.Stderr, commands)
+		return fmt.Errorf("unknown command '%s'", name)
+	}
+	return cmd.execute(args)
+}
+
+// execute parses the flags of the command and runs it; options may come before or after the
+// positional arguments
+func (c *command) execute(args []string) error {
+	if len(c.subcommands) > 0 {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			c.printHelp(os.Stderr)
+			if len(args) == 0 {
+				return fmt.Errorf("usage: deco %s %s", c.path, subcommandNames(c))
+			}
+			return nil
+		}
+		sub := findCommand(c.subcommands, args[0])
+		if sub == nil {
+			return fmt.Errorf("unknown %s subcommand '%s' (valid: %s)", c.path, args[0], strings.ReplaceAll(subcommandNames(c), "|", ", "))
+		}
+		return sub.execute(args[1:])
+	}
+
+	fs := flag.NewFlagSet(c.path, flag.ContinueOnError)
+	run := c.setup(fs)
+	fs.SetOutput(io.Discard) // errors are returned; help is printed below
+	fs.Usage = func() {}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				c.printHelp(os.Stdout)
+				return nil
+			}
+			c.printHelp(os.Stderr)
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if err := run(positional); err != nil {
+		return fmt.Errorf("error in %s command: %v", c.path, err)
+	}
+	return nil
+}
+
+// subcommandNames "validate|print"
+func subcommandNames(c *command) string {
+	names := make([]string, 0, len(c.subcommands))
+	for _, sub := range c.subcommands {
+		names = append(names, sub.name)
+	}
+	return strings.Join(names, "|")
+}
+
+// commandFlags the flags declared by a command, sorted by name
+func commandFlags(c *command) []*flag.Flag {
+	if c.setup == nil {
+		return nil
+	}
+	fs := flag.NewFlagSet(c.path, flag.ContinueOnError)
+	c.setup(fs)
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// hasDefault reports whether the default value of the flag is worth printing
+func hasDefault(f *flag.Flag) bool {
+	return f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && f.DefValue != "0s"
+}
+
+// flagName the flag as typed on the command line: -v, --config
+func flagName(f *flag.Flag) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// printRootHelp lists the commands
+func printRootHelp(w io.Writer, commands []*command) {
+	fmt.Fprintf(w, "deco Code Generator %s\n\n", cliVersion)
+	fmt.Fprintf(w, "Usage: deco [command] [options]\n\nCommands:\n")
+	for _, cmd := range commands {
+		if len(cmd.subcommands) == 0 {
+			fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.summary)
+		}
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(w, "  %-20s %s\n", sub.path, sub.summary)
+		}
+	}
+	fmt.Fprintf(w, "\nRun 'deco help <command>' for the options and examples of a command.\n")
+}
+
+// printHelp prints the usage, options and examples of a command
+func (c *command) printHelp(w io.Writer) {
+	if len(c.subcommands) > 0 {
+		fmt.Fprintf(w, "Usage: deco %s %s [options]\n\n%s\n\nCommands:\n", c.path, subcommandNames(c), c.summary)
+		for _, sub := range c.subcommands {
+			fmt.Fprintf(w, "  %-20s %s\n", sub.name, sub.summary)
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "Usage: deco %s %s\n\n%s\n", c.path, c.usage, c.summary)
+	if c.details != "" {
+		fmt.Fprintf(w, "\n%s\n", c.details)
+	}
+	if flags := commandFlags(c); len(flags) > 0 {
+		fmt.Fprintf(w, "\nOptions:\n")
+		for _, f := range flags {
+			name, usage := flag.UnquoteUsage(f)
+			fmt.Fprintf(w, "  %s", flagName(f))
+			if name != "" {
+				fmt.Fprintf(w, " %s", name)
+			}
+			fmt.Fprintf(w, "\n    \t%s", usage)
+			if hasDefault(f) {
+				if name == "string" {
+					fmt.Fprintf(w, " (default %q)", f.DefValue)
+				} else {
+					fmt.Fprintf(w, " (default %s)", f.DefValue)
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	if len(c.examples) > 0 {
+		fmt.Fprintf(w, "\nExamples:\n")
+		for _, example := range c.examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+}
+
+// setupHelpCommand declares the help command
+func setupHelpCommand(_ *flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		commands := cliCommands()
+		if len(args) == 0 {
+			printRootHelp(os.Stdout, commands)
+			return nil
+		}
+		cmd := findCommand(commands, args[0])
+		for _, name := range args[1:] {
+			if cmd != nil {
+				cmd = findCommand(cmd.subcommands, name)
+			}
+		}
+		if cmd == nil {
+			return fmt.Errorf("unknown command '%s'", strings.Join(args, " "))
+		}
+		cmd.printHelp(os.Stdout)
+		return nil
+	}
+}
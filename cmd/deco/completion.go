@@ -0,0 +1,312 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// setupCompletionCommand declares the completion command, which prints the completion script of a shell
+func setupCompletionCommand(_ *flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("expected a shell (bash, zsh or fish)")
+		}
+		commands := cliCommands()
+		switch args[0] {
+		case "bash":
+			fmt.Print(bashCompletion(commands))
+		case "zsh":
+			fmt.Print(zshCompletion(commands))
+		case "fish":
+			fmt.Print(fishCompletion(commands))
+		default:
+			return fmt.Errorf("unknown shell '%s' (valid: bash, zsh, fish)", args[0])
+		}
+		return nil
+	}
+}
+
+// isBoolFlag reports whether the flag takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// completionWords the flags and positional candidates of a command
+func completionWords(c *command) []string {
+	words := append([]string(nil), c.args...)
+	for _, f := range commandFlags(c) {
+		words = append(words, flagName(f))
+	}
+	return words
+}
+
+// bashCompletion completion script for bash; the words typed so far select the command
+func bashCompletion(commands []*command) string {
+	var b strings.Builder
+	b.WriteString("# bash completion for deco\n")
+	b.WriteString("# source <(deco completion bash)\n\n")
+	b.WriteString("_deco() {\n")
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" words=\"\"\n")
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+
+	var root []string
+	for _, cmd := range commands {
+		root = append(root, cmd.name)
+		if len(cmd.subcommands) == 0 {
+			fmt.Fprintf(&b, "        %s) words=%q ;;\n", cmd.name, strings.Join(completionWords(cmd), " "))
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n            case \"${COMP_WORDS[2]}\" in\n", cmd.name)
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(&b, "                %s) words=%q ;;\n", sub.name, strings.Join(completionWords(sub), " "))
+		}
+		fmt.Fprintf(&b, "                *) words=%q ;;\n            esac ;;\n", strings.ReplaceAll(subcommandNames(cmd), "|", " "))
+	}
+	// Without a command, generate flags apply
+	root = append(root, completionWords(findCommand(commands, "generate"))...)
+	fmt.Fprintf(&b, "        *) words=%q ;;\n", strings.Join(root, " "))
+
+	b.WriteString("    esac\n")
+	b.WriteString("    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n\n")
+	b.WriteString("complete -o default -F _deco deco\n")
+	return b.String()
+}
+
+// zshDescription escapes a description for _arguments specs
+func zshDescription(text string) string {
+	return strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]").Replace(text)
+}
+
+// zshCommandList "name:summary" entries for _describe
+func zshCommandList(w *strings.Builder, variable string, commands []*command) {
+	fmt.Fprintf(w, "    local -a %s\n    %s=(\n", variable, variable)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "        '%s:%s'\n", cmd.name, strings.ReplaceAll(zshDescription(cmd.summary), ":", "\\:"))
+	}
+	w.WriteString("    )\n")
+}
+
+// zshArguments the _arguments call completing the flags and positional arguments of a command
+func zshArguments(c *command, indent string) string {
+	specs := []string{}
+	for _, f := range commandFlags(c) {
+		spec := fmt.Sprintf("'%s[%s]", flagName(f), zshDescription(f.Usage))
+		if !isBoolFlag(f) {
+			name, _ := flag.UnquoteUsage(f)
+			spec += fmt.Sprintf(":%s:_files", name)
+		}
+		specs = append(specs, spec+"'")
+	}
+	if len(c.args) > 0 {
+		specs = append(specs, fmt.Sprintf("'1:argument:(%s)'", strings.Join(c.args, " ")))
+	}
+	specs = append(specs, "'*:file:_files'")
+	return indent + "_arguments \\\n" + indent + "    " + strings.Join(specs, " \\\n"+indent+"    ") + "\n"
+}
+
+// zshCompletion completion script for zsh, with command and flag descriptions
+func zshCompletion(commands []*command) string {
+	var b strings.Builder
+	b.WriteString("#compdef deco\n")
+	b.WriteString("# deco completion zsh > \"${fpath[1]}/_deco\"\n\n")
+	b.WriteString("_deco() {\n")
+	zshCommandList(&b, "commands", commands)
+	b.WriteString("\n    if (( CURRENT == 2 )); then\n")
+	b.WriteString("        _describe -t commands 'deco command' commands\n")
+	b.WriteString("        return\n    fi\n\n")
+	b.WriteString("    local command=\"$words[2]\"\n")
+	b.WriteString("    shift words\n    (( CURRENT-- ))\n")
+	b.WriteString("    case \"$command\" in\n")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        %s)\n", cmd.name)
+		if len(cmd.subcommands) == 0 {
+			b.WriteString(zshArguments(cmd, "            "))
+			b.WriteString("            ;;\n")
+			continue
+		}
+		b.WriteString("            if (( CURRENT == 2 )); then\n")
+		var sb strings.Builder
+		zshCommandList(&sb, "subcommands", cmd.subcommands)
+		b.WriteString(indentLines(sb.String(), "        "))
+		fmt.Fprintf(&b, "                _describe -t commands 'deco %s command' subcommands\n", cmd.name)
+		b.WriteString("                return\n            fi\n")
+		b.WriteString("            local subcommand=\"$words[2]\"\n")
+		b.WriteString("            shift words\n            (( CURRENT-- ))\n")
+		b.WriteString("            case \"$subcommand\" in\n")
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(&b, "                %s)\n", sub.name)
+			b.WriteString(zshArguments(sub, "                    "))
+			b.WriteString("                    ;;\n")
+		}
+		b.WriteString("            esac\n            ;;\n")
+	}
+	b.WriteString("        -*)\n")
+	b.WriteString(zshArguments(findCommand(commands, "generate"), "            "))
+	b.WriteString("            ;;\n")
+	b.WriteString("    esac\n}\n\n")
+	b.WriteString("_deco \"$@\"\n")
+	return b.String()
+}
+
+// indentLines prefixes each non-empty line
+func indentLines(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fishQuote single-quotes a fish word
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(text) + "'"
+}
+
+// fishFlags complete lines of the flags of a command, under condition
+func fishFlags(b *strings.Builder, c *command, condition string) {
+	for _, f := range commandFlags(c) {
+		option := "-l " + f.Name
+		if len(f.Name) == 1 {
+			option = "-s " + f.Name
+		}
+		if !isBoolFlag(f) {
+			option += " -rF"
+		}
+		fmt.Fprintf(b, "complete -c deco -n %s %s -d %s\n", fishQuote(condition), option, fishQuote(f.Usage))
+	}
+	if len(c.args) > 0 {
+		fmt.Fprintf(b, "complete -c deco -n %s -a %s\n", fishQuote(condition), fishQuote(strings.Join(c.args, " ")))
+	}
+}
+
+// fishCompletion completion script for fish
+func fishCompletion(commands []*command) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for deco\n")
+	b.WriteString("# deco completion fish > ~/.config/fish/completions/deco.fish\n\n")
+	b.WriteString("complete -c deco -f\n")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c deco -n '__fish_use_subcommand' -a %s -d %s\n", cmd.name, fishQuote(cmd.summary))
+	}
+	fishFlags(&b, findCommand(commands, "generate"), "__fish_use_subcommand")
+
+	for _, cmd := range commands {
+		b.WriteString("\n")
+		condition := "__fish_seen_subcommand_from " + cmd.name
+		if len(cmd.subcommands) == 0 {
+			fishFlags(&b, cmd, condition)
+			continue
+		}
+		names := strings.ReplaceAll(subcommandNames(cmd), "|", " ")
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(&b, "complete -c deco -n %s -a %s -d %s\n", fishQuote(condition+"; and not __fish_seen_subcommand_from "+names), sub.name, fishQuote(sub.summary))
+		}
+		for _, sub := range cmd.subcommands {
+			fishFlags(&b, sub, condition+"; and __fish_seen_subcommand_from "+sub.name)
+		}
+	}
+	return b.String()
+}
+
+// setupManCommand declares the man command, which prints the deco(1) man page
+func setupManCommand(fs *flag.FlagSet) func(args []string) error {
+	output := fs.String("o", "", "Write the man page to a file instead of stdout")
+	return func(_ []string) error {
+		page := manPage(cliCommands())
+		if *output == "" {
+			fmt.Print(page)
+			return nil
+		}
+		if err := os.WriteFile(*output, []byte(page), 0o644); err != nil { // nolint:gosec // Safe: man pages are world-readable
+			return fmt.Errorf("error writing %s: %v", *output, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Man page written to %s\n", *output)
+		return nil
+	}
+}
+
+// roffText escapes text for roff: backslashes, and control characters at line start
+func roffText(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\e")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffOption escapes an option or command line, where "-" must not become a hyphen
+func roffOption(text string) string {
+	return strings.ReplaceAll(roffText(text), "-", "\\-")
+}
+
+// manCommand the COMMANDS entry of a command
+func manCommand(b *strings.Builder, c *command) {
+	fmt.Fprintf(b, ".SS \"deco %s %s\"\n", c.path, roffOption(c.usage))
+	fmt.Fprintf(b, "%s\n", roffText(c.summary))
+	if c.details != "" {
+		fmt.Fprintf(b, ".PP\n.nf\n%s\n.fi\n", roffText(c.details))
+	}
+	for _, f := range commandFlags(c) {
+		name, usage := flag.UnquoteUsage(f)
+		b.WriteString(".TP\n")
+		if name != "" {
+			fmt.Fprintf(b, ".BI \"%s \" %s\n", roffOption(flagName(f)), name)
+		} else {
+			fmt.Fprintf(b, ".B %s\n", roffOption(flagName(f)))
+		}
+		b.WriteString(roffText(usage))
+		if hasDefault(f) {
+			fmt.Fprintf(b, " (default: %s)", roffOption(f.DefValue))
+		}
+		b.WriteString("\n")
+	}
+	if len(c.examples) > 0 {
+		b.WriteString(".PP\nExamples:\n.PP\n.RS\n.nf\n")
+		for _, example := range c.examples {
+			fmt.Fprintf(b, "%s\n", roffOption(example))
+		}
+		b.WriteString(".fi\n.RE\n")
+	}
+}
+
+// manPage the deco(1) man page, generated from the command tree
+func manPage(commands []*command) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH DECO 1 \"\" \"deco %s\" \"deco manual\"\n", cliVersion)
+	b.WriteString(".SH NAME\ndeco \\- code generator for the deco framework\n")
+	b.WriteString(".SH SYNOPSIS\n.B deco\n[\\fIcommand\\fR] [\\fIoptions\\fR]\n")
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(".B deco\nreads the annotations (@Route, @Cache, ...) of the Gin handlers selected by\n.I .deco.yaml\n")
+	b.WriteString("and generates the code that registers them with their middlewares and documentation.\n")
+	b.WriteString("Without a command it runs\n.BR generate .\n")
+
+	b.WriteString(".SH COMMANDS\n")
+	for _, cmd := range commands {
+		if len(cmd.subcommands) == 0 {
+			manCommand(&b, cmd)
+		}
+		for _, sub := range cmd.subcommands {
+			manCommand(&b, sub)
+		}
+	}
+
+	b.WriteString(".SH ENVIRONMENT\n")
+	b.WriteString(".TP\n.B DECO_CONFIG\nConfiguration file used when \\-\\-config is not given.\n")
+	b.WriteString(".TP\n.B DECO_SERVER\nBase URL of the server called by\n.BR \"deco call\" .\n")
+	b.WriteString(".TP\n.B DECO_<SECTION>_<KEY>\nOverrides a configuration key (DECO_REDIS_ENABLED=true sets redis.enabled).\n")
+	b.WriteString(".SH FILES\n")
+	b.WriteString(".TP\n.I .deco.yaml\nProject configuration, created by\n.BR \"deco init\" .\n")
+	b.WriteString(".TP\n.I .deco/init_decorators.go\nGenerated registration code.\n")
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupConfigValidateCommand declares the config validate flags; the command runs the load-time
+// configuration checks, for CI
+func setupConfigValidateCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path (default: .deco.yaml or $DECO_CONFIG)")
+
+	return func(_ []string) error {
+		path := configFilePath(*configPath)
+		issues, err := decorators.ValidateConfigFile(path)
+		if err != nil {
+			return err
+		}
+		if len(issues) > 0 {
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stderr, "%s\n", issue)
+			}
+			return fmt.Errorf("%s has %d issue(s)", path, len(issues))
+		}
+
+		fmt.Printf("✅ %s is valid\n", path)
+		return nil
+	}
+}
+
+// setupConfigPrintCommand declares the config print flags; the command prints the configuration the
+// application would run with
+func setupConfigPrintCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path (default: .deco.yaml or $DECO_CONFIG)")
+	effective := fs.Bool("effective", false, "Merge defaults, DECO_* environment overrides and --set flags")
+	format := fs.String("format", "yaml", "Output format: yaml or json")
+	var sets multiFlag
+	fs.Var(&sets, "set", "Override a key, e.g. --set redis.enabled=true (repeatable, implies --effective)")
+
+	return func(_ []string) error {
+		if !*effective && len(sets) == 0 {
+			rendered, err := decorators.RenderConfigFile(configFilePath(*configPath), *format)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(rendered)
+			return err
+		}
+
+		overrides := decorators.EnvConfigOverrides()
+		for _, assignment := range sets {
+			path, value, found := strings.Cut(assignment, "=")
+			if !found {
+				return fmt.Errorf("invalid --set '%s' (expected key=value)", assignment)
+			}
+			overrides = append(overrides, decorators.ConfigOverride{Path: strings.TrimSpace(path), Value: value, Source: "flag --set"})
+		}
+
+		config, err := decorators.LoadConfigWithOverrides(*configPath, overrides)
+		if err != nil {
+			return err
+		}
+		rendered, err := decorators.RenderEffectiveConfig(config, overrides, *format)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(rendered)
+		return err
+	}
+}
+
+// setupConfigSchemaCommand declares the config schema flags; the command prints the JSON Schema of the
+// configuration file for editors
+func setupConfigSchemaCommand(fs *flag.FlagSet) func(args []string) error {
+	output := fs.String("o", "", "Write the schema to a file instead of stdout")
+
+	return func(_ []string) error {
+		schema, err := decorators.ConfigJSONSchema()
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = os.Stdout.Write(schema)
+			return err
+		}
+		if err := os.WriteFile(*output, schema, 0o644); err != nil { // nolint:gosec // Safe: the schema is public
+			return fmt.Errorf("error writing %s: %v", *output, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Schema written to %s\n", *output)
+		return nil
+	}
+}
+
+// configFilePath configuration file used when --config is not given
+func configFilePath(path string) string {
+	if path == "" {
+		path = os.Getenv("DECO_CONFIG")
+	}
+	if path == "" {
+		path = ".deco.yaml"
+	}
+	return path
+}
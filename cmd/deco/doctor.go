@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// doctorCheck result of one diagnostic of deco doctor
+type doctorCheck struct {
+	Name     string                     `json:"name"`
+	Status   string                     `json:"status"` // ok, warning, error or skipped
+	Message  string                     `json:"message,omitempty"`
+	Findings []decorators.DoctorFinding `json:"findings,omitempty"`
+}
+
+// newDoctorCheck check whose status follows the most severe finding
+func newDoctorCheck(name, message string, findings []decorators.DoctorFinding) doctorCheck {
+	check := doctorCheck{Name: name, Status: "ok", Message: message, Findings: findings}
+	for _, finding := range findings {
+		if finding.Severity == decorators.DoctorError {
+			check.Status = "error"
+			break
+		}
+		check.Status = "warning"
+	}
+	return check
+}
+
+// skippedDoctorCheck check that does not apply to the project
+func skippedDoctorCheck(name, reason string) doctorCheck {
+	return doctorCheck{Name: name, Status: "skipped", Message: reason}
+}
+
+// setupDoctorCommand declares the doctor flags; the command diagnoses the project (configuration,
+// decorators, routes, schemas, generated code, Redis and telemetry) and prints the fixes
+func setupDoctorCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path (default: .deco.yaml or $DECO_CONFIG)")
+	timeout := fs.Duration("timeout", 5*time.Second, "Timeout of the Redis and telemetry connectivity checks")
+	output := outputFlag(fs)
+
+	return func(_ []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+
+		checks := runDoctor(*configPath, *timeout)
+		errorCount := 0
+		for _, check := range checks {
+			for _, finding := range check.Findings {
+				if finding.Severity == decorators.DoctorError {
+					errorCount++
+				}
+			}
+		}
+		if errorCount > 0 {
+			err = fmt.Errorf("deco doctor found %d error(s)", errorCount)
+		}
+
+		if jsonMode {
+			report := newCLIReport("doctor", "")
+			report.Checks = checks
+			report.write(err)
+			return err
+		}
+		printDoctorChecks(checks)
+		return err
+	}
+}
+
+// runDoctor runs the checks in order; those needing the configuration use the defaults when it is invalid
+func runDoctor(configPath string, timeout time.Duration) []doctorCheck {
+	var checks []doctorCheck
+
+	path := configFilePath(configPath)
+	config, configCheck := doctorConfig(path)
+	checks = append(checks, configCheck)
+
+	routes, decoratorsCheck := doctorDecorators(config)
+	checks = append(checks, decoratorsCheck)
+	if decoratorsCheck.Status == "skipped" {
+		checks = append(checks,
+			skippedDoctorCheck("routes", "no handlers"),
+			skippedDoctorCheck("schemas", "no handlers"))
+	} else {
+		checks = append(checks,
+			newDoctorCheck("routes", fmt.Sprintf("%d route(s)", len(routes)), decorators.DuplicateRouteFindings(routes)),
+			newDoctorCheck("schemas", fmt.Sprintf("%d schema(s)", len(decorators.GetSchemas())), decorators.UnknownSchemaFindings(routes)))
+	}
+
+	checks = append(checks, doctorGenerated(config))
+
+	if config.Redis.Enabled {
+		checks = append(checks, doctorConnectivity("redis", config.Redis.Address, timeout, func(ctx context.Context) error {
+			return decorators.PingRedis(ctx, config.Redis)
+		}, "start Redis or fix redis.address, redis.password and redis.db in "+path))
+	} else {
+		checks = append(checks, skippedDoctorCheck("redis", "redis.enabled is false"))
+	}
+
+	if config.Telemetry.Enabled && config.Telemetry.Endpoint != "" {
+		endpoint := config.Telemetry.Endpoint
+		checks = append(checks, doctorConnectivity("telemetry", endpoint, timeout, func(ctx context.Context) error {
+			return decorators.DialTelemetryEndpoint(ctx, endpoint)
+		}, "start the OTLP collector or fix telemetry.endpoint in "+path))
+	} else {
+		checks = append(checks, skippedDoctorCheck("telemetry", "telemetry is disabled or has no endpoint"))
+	}
+
+	return checks
+}
+
+// doctorConfig validates the configuration file
+func doctorConfig(path string) (*decorators.Config, doctorCheck) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return decorators.DefaultConfig(), newDoctorCheck("config", path+" not found, defaults used", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorWarning,
+			File:     path,
+			Message:  "configuration file not found",
+			Fix:      "run 'deco init' to create it",
+		}})
+	}
+
+	issues, err := decorators.ValidateConfigFile(path)
+	if err != nil {
+		return decorators.DefaultConfig(), newDoctorCheck("config", "", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorError,
+			File:     path,
+			Message:  err.Error(),
+			Fix:      "fix the YAML syntax of the file",
+		}})
+	}
+	if len(issues) > 0 {
+		return decorators.DefaultConfig(), newDoctorCheck("config", path+" is invalid, defaults used for the next checks",
+			decorators.ConfigIssueFindings(issues))
+	}
+
+	config, err := decorators.LoadConfig(path)
+	if err != nil {
+		return decorators.DefaultConfig(), newDoctorCheck("config", "", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorError, File: path, Message: err.Error(),
+		}})
+	}
+	return config, newDoctorCheck("config", path+" is valid", nil)
+}
+
+// doctorDecorators parses the handlers and reports the decorator errors
+func doctorDecorators(config *decorators.Config) ([]*decorators.RouteMeta, doctorCheck) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, newDoctorCheck("decorators", "", []decorators.DoctorFinding{{Severity: decorators.DoctorError, Message: err.Error()}})
+	}
+	handlerFiles, err := config.DiscoverHandlers(wd)
+	if err != nil {
+		return nil, newDoctorCheck("decorators", "", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorError,
+			Message:  fmt.Sprintf("error discovering handlers: %v", err),
+			Fix:      "fix handlers.include and handlers.exclude",
+		}})
+	}
+	if len(handlerFiles) == 0 {
+		return nil, skippedDoctorCheck("decorators", "no handler files match handlers.include")
+	}
+
+	routes, err := decorators.ParseDirectory(findCommonRoot(handlerFiles))
+	var multiErr *decorators.MultipleValidationError
+	switch {
+	case errors.As(err, &multiErr):
+		return routes, newDoctorCheck("decorators", "", decorators.DecoratorErrorFindings(multiErr.Errors))
+	case err != nil:
+		return routes, newDoctorCheck("decorators", "", []decorators.DoctorFinding{{Severity: decorators.DoctorError, Message: err.Error()}})
+	}
+	return routes, newDoctorCheck("decorators", fmt.Sprintf("%d handler file(s) parsed", len(handlerFiles)), nil)
+}
+
+// compilerError "file:line:col: message" line of the go build output
+var compilerError = regexp.MustCompile(`^(\S+\.go):(\d+)(?::\d+)?: (.+)$`)
+
+// doctorGenerated checks that the generated file compiles and is not older than the handlers
+func doctorGenerated(config *decorators.Config) doctorCheck {
+	outputPath := config.Generate.OutputPath()
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return newDoctorCheck("generated", "", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorWarning,
+			File:     outputPath,
+			Message:  "generated file not found",
+			Fix:      "run 'deco generate'",
+		}})
+	}
+
+	var findings []decorators.DoctorFinding
+	message := outputPath + " compiles"
+	cmd := exec.Command("go", "build", "-o", os.DevNull, "./"+filepath.Dir(filepath.Clean(outputPath)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		findings = append(findings, compilerFindings(out, err)...)
+		message = outputPath + " does not build"
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if handlerFiles, err := config.DiscoverHandlers(wd); err == nil {
+			for _, file := range handlerFiles {
+				if handler, err := os.Stat(file); err == nil && handler.ModTime().After(info.ModTime()) {
+					findings = append(findings, decorators.DoctorFinding{
+						Severity: decorators.DoctorWarning,
+						File:     outputPath,
+						Message:  fmt.Sprintf("generated file is older than %s", relativePath(wd, file)),
+						Fix:      "run 'deco generate'",
+					})
+					break
+				}
+			}
+		}
+	}
+	return newDoctorCheck("generated", message, findings)
+}
+
+// compilerFindings one finding per compiler error, the raw output when none has a position
+func compilerFindings(out []byte, err error) []decorators.DoctorFinding {
+	var findings []decorators.DoctorFinding
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		match := compilerError.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[2])
+		findings = append(findings, decorators.DoctorFinding{
+			Severity: decorators.DoctorError,
+			File:     match[1],
+			Line:     line,
+			Message:  match[3],
+			Fix:      "run 'deco generate'; if it persists, fix the handler or decorator the generated line refers to",
+		})
+	}
+	if len(findings) == 0 {
+		message := strings.TrimSpace(string(out))
+		if message == "" {
+			message = err.Error()
+		}
+		findings = append(findings, decorators.DoctorFinding{
+			Severity: decorators.DoctorError,
+			Message:  "generated code does not build: " + message,
+			Fix:      "run 'deco generate' and 'go build ./...'",
+		})
+	}
+	return findings
+}
+
+// doctorConnectivity runs a connectivity check with the timeout
+func doctorConnectivity(name, target string, timeout time.Duration, check func(ctx context.Context) error, fix string) doctorCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		return newDoctorCheck(name, "", []decorators.DoctorFinding{{
+			Severity: decorators.DoctorError,
+			Message:  fmt.Sprintf("%s unreachable: %v", target, err),
+			Fix:      fix,
+		}})
+	}
+	return newDoctorCheck(name, target+" reachable", nil)
+}
+
+// relativePath path relative to the working directory when possible
+func relativePath(wd, path string) string {
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// printDoctorChecks prints the checks with their findings and fixes
+func printDoctorChecks(checks []doctorCheck) {
+	icons := map[string]string{"ok": "✅", "warning": "⚠️ ", "error": "❌", "skipped": "➖"}
+	errorCount, warningCount := 0, 0
+	for _, check := range checks {
+		line := fmt.Sprintf("%s %s", icons[check.Status], check.Name)
+		if check.Message != "" {
+			line += ": " + check.Message
+		}
+		fmt.Println(line)
+		for _, finding := range check.Findings {
+			fmt.Printf("   %s: %s\n", finding.Severity, finding)
+			if finding.Fix != "" {
+				fmt.Printf("      fix: %s\n", finding.Fix)
+			}
+			if finding.Severity == decorators.DoctorError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+	}
+	fmt.Printf("\n%d error(s), %d warning(s)\n", errorCount, warningCount)
+}
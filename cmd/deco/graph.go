@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupGraphCommand declares the graph flags; the command prints the route dependency graph of the
+// handlers discovered by the configuration
+func setupGraphCommand(fs *flag.FlagSet) func(args []string) error {
+	format := fs.String("format", "dot", "Output format: dot or mermaid")
+	configPath := fs.String("config", "", "Configuration file path")
+	output := fs.String("o", "", "Write the graph to a file instead of stdout")
+
+	return func(_ []string) error {
+		config, err := decorators.LoadConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("error loading configuration: %v", err)
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+		handlerFiles, err := config.DiscoverHandlers(wd)
+		if err != nil {
+			return fmt.Errorf("error discovering handlers: %v", err)
+		}
+		if len(handlerFiles) == 0 {
+			return fmt.Errorf("no handlers found with configured patterns")
+		}
+
+		routes, err := decorators.ParseDirectory(findCommonRoot(handlerFiles))
+		if err != nil {
+			return err
+		}
+		graph := decorators.BuildRouteGraph(routes)
+
+		var rendered string
+		switch *format {
+		case "dot":
+			rendered = graph.DOT()
+		case "mermaid":
+			rendered = graph.Mermaid()
+		default:
+			return fmt.Errorf("unknown format '%s' (valid: dot, mermaid)", *format)
+		}
+
+		if *output == "" {
+			fmt.Print(rendered)
+			return nil
+		}
+		if err := os.WriteFile(*output, []byte(rendered), 0o600); err != nil {
+			return fmt.Errorf("error writing %s: %v", *output, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Graph written to %s (%d nodes, %d edges)\n", *output, len(graph.Nodes), len(graph.Edges))
+		return nil
+	}
+}
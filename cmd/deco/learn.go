@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupLearnCommand declares the learn flags; the command fetches the decorators suggested by a server
+// running with dev.learn and proposes them as a unified diff of the handlers, or applies them with --write
+func setupLearnCommand(fs *flag.FlagSet) func(args []string) error {
+	server := fs.String("server", envOrDefault("DECO_SERVER", "http://localhost:8080"), "Base URL of the running server (env DECO_SERVER)")
+	configPath := fs.String("config", "", "Configuration file path")
+	write := fs.Bool("write", false, "Insert the decorators and schemas in the files instead of printing a diff")
+	report := fs.Bool("report", false, "Print the suggestions as JSON instead of a diff")
+	timeout := fs.Duration("timeout", 30*time.Second, "Request timeout")
+
+	return func(_ []string) error {
+		learned, err := fetchLearnReport(strings.TrimSuffix(*server, "/"), *timeout)
+		if err != nil {
+			return err
+		}
+		if *report {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(learned)
+		}
+		if len(learned) == 0 {
+			fmt.Fprintln(os.Stderr, "✅ No suggestions: the recorded traffic matches the declared decorators")
+			return nil
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+		config, err := decorators.LoadConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("error loading configuration: %v", err)
+		}
+		files, err := config.DiscoverHandlers(wd)
+		if err != nil {
+			return fmt.Errorf("error discovering handlers: %v", err)
+		}
+
+		placed := make(map[string]bool)
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			annotations, updated, err := decorators.LearnedAnnotations(file, content, learned)
+			if err != nil {
+				return err
+			}
+			if len(annotations) == 0 {
+				continue
+			}
+			for _, annotation := range annotations {
+				placed[annotation.Handler] = true
+			}
+
+			name := file
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				name = rel
+			}
+			if !*write {
+				fmt.Print(decorators.AnnotationDiff(filepath.ToSlash(name), content, annotations))
+				continue
+			}
+			if err := os.WriteFile(file, updated, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("error writing %s: %v", name, err)
+			}
+			fmt.Fprintf(os.Stderr, "✏️  %s: decorators learned for %d handler(s)\n", name, len(annotations))
+		}
+
+		for _, route := range learned {
+			if route.Handler == "" || !placed[route.Handler] {
+				fmt.Fprintf(os.Stderr, "⚠️  %s %s: handler not found in the project; suggested:\n   %s\n",
+					route.Method, route.Path, strings.Join(route.Annotations, "\n   "))
+			}
+		}
+		if !*write {
+			fmt.Fprintf(os.Stderr, "💡 %d route(s) with suggestions from the recorded traffic: review the diff, then run with --write or pipe it to git apply\n", len(learned))
+		}
+		return nil
+	}
+}
+
+// fetchLearnReport reads the suggestions of the running server
+func fetchLearnReport(server string, timeout time.Duration) ([]decorators.LearnedRoute, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(server + decorators.LearnDebugPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the learned decorators: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s is not served: enable dev.learn in .deco.yaml and restart the server", decorators.LearnDebugPath)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch the learned decorators: %s", resp.Status)
+	}
+	var body struct {
+		Routes []decorators.LearnedRoute `json:"routes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("could not read the learned decorators: %v", err)
+	}
+	return body.Routes, nil
+}
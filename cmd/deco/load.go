@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupLoadCommand declares the load flags; "deco load" sends load generated from the API contract to the
+// running server and reports the latency of each route
+func setupLoadCommand(fs *flag.FlagSet) func(args []string) error {
+	var routes, headers multiFlag
+	server := fs.String("server", envOrDefault("DECO_SERVER", "http://localhost:8080"), "Base URL of the running server (env DECO_SERVER)")
+	specPath := fs.String("spec", "", "OpenAPI JSON file (default: <server>/decorators/openapi.json)")
+	rps := fs.Int("rps", 50, "Requests per second once ramped up, spread over the routes")
+	duration := fs.Duration("duration", 30*time.Second, "Duration of the run, ramp included")
+	ramp := fs.Duration("ramp", 10*time.Second, "Time to climb linearly to --rps")
+	concurrency := fs.Int("concurrency", 0, "Ceiling of in-flight requests; requests over it are dropped (default: --rps)")
+	timeout := fs.Duration("timeout", 10*time.Second, "Request timeout")
+	auth := fs.String("auth", "", "Bearer token sent in the Authorization header")
+	metricsPath := fs.String("metrics", "/metrics", "Prometheus endpoint of the server for the server-side latency (empty: skip)")
+	seed := fs.Int64("seed", 1, "Seed of the generated payloads")
+	fs.Var(&routes, "routes", "Routes to load, 'METHOD /path', comma-separated or repeated (default: every GET)")
+	fs.Var(&headers, "H", "Request header 'Name: value' (repeatable)")
+	output := outputFlag(fs)
+
+	return func(_ []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		report := newCLIReport("load", "")
+
+		options := decorators.LoadOptions{
+			BaseURL:     strings.TrimSuffix(*server, "/"),
+			RPS:         *rps,
+			Duration:    *duration,
+			RampUp:      *ramp,
+			Concurrency: *concurrency,
+			Timeout:     *timeout,
+			Headers:     make(map[string]string),
+			MetricsPath: *metricsPath,
+			Seed:        *seed,
+		}
+		for _, header := range headers {
+			name, value, found := strings.Cut(header, ":")
+			if !found {
+				return finishReportCommand(report, jsonMode, fmt.Errorf("invalid header '%s', expected 'Name: value'", header))
+			}
+			options.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		if *auth != "" {
+			options.Headers["Authorization"] = "Bearer " + *auth
+		}
+
+		spec, err := loadCallSpec(&callOptions{server: options.BaseURL, specPath: *specPath, timeout: *timeout})
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+		var selected []string
+		for _, value := range routes {
+			for _, route := range strings.Split(value, ",") {
+				if route = strings.TrimSpace(route); route != "" {
+					selected = append(selected, route)
+				}
+			}
+		}
+		targets, err := decorators.LoadTargets(spec, selected)
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+
+		// Ctrl+C ends the run early and still reports what was measured
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if !jsonMode {
+			fmt.Printf("🚀 %d route(s), %d req/s for %v (ramp %v) against %s\n", len(targets), *rps, *duration, min(*ramp, *duration), options.BaseURL)
+		}
+		result, err := decorators.RunLoadTest(ctx, targets, spec, options)
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+		report.Load = result
+		if !jsonMode {
+			printLoadReport(result)
+		}
+		return finishReportCommand(report, jsonMode, nil)
+	}
+}
+
+// printLoadReport prints the latency of each route, with the server-side one when the metrics were read
+func printLoadReport(report *decorators.LoadReport) {
+	round := func(d time.Duration) string { return d.Round(10 * time.Microsecond).String() }
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "ROUTE\tREQS\tERRORS\tP50\tP90\tP95\tP99\tMAX\tSERVER P50\tSERVER P99\t")
+	for _, route := range report.Routes {
+		serverP50, serverP99 := "-", "-"
+		if route.Server != nil {
+			serverP50, serverP99 = round(route.Server.P50), round(route.Server.P99)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", route.Route, route.Requests, route.Errors,
+			round(route.Latency.P50), round(route.Latency.P90), round(route.Latency.P95), round(route.Latency.P99),
+			round(route.Latency.Max), serverP50, serverP99)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\n%d requests in %v (%.1f req/s), %d errors, %d dropped\n", report.Requests,
+		report.Duration.Round(time.Millisecond), report.RPS, report.Errors, report.Dropped)
+	if report.Dropped > 0 {
+		fmt.Println("⚠️  Requests were dropped at the concurrency ceiling: the server is slower than the rate, raise --concurrency to queue more")
+	}
+	if report.MetricsError != "" {
+		fmt.Printf("⚠️  No server-side latency: %s\n", report.MetricsError)
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// LSP diagnostic severities and completion item kinds
+const (
+	lspSeverityError      = 1
+	lspSeverityWarning    = 2
+	lspCompletionProperty = 10
+	lspCompletionValue    = 12
+	lspCompletionKeyword  = 14
+	lspMethodNotFound     = -32601
+	lspTextSyncFull       = 1
+)
+
+var (
+	// lspMarkerPrefix "@Na" being typed in a comment
+	lspMarkerPrefix = regexp.MustCompile(`//.*@(\w*)$`)
+	// lspOpenMarker "@Name(args" not closed yet
+	lspOpenMarker = regexp.MustCompile(`//.*@(\w+)\s*\(([^)]*)$`)
+	// lspArgValue `key="val` being typed inside the arguments
+	lspArgValue = regexp.MustCompile(`(?:^|[,(\s])(\w+)\s*=\s*"?(\w*)$`)
+)
+
+// setupLSPCommand declares the lsp flags; the command speaks the Language Server Protocol on stdio
+func setupLSPCommand(_ *flag.FlagSet) func(args []string) error {
+	return func(_ []string) error {
+		decorators.SetVerbose(false)
+		server := &lspServer{out: os.Stdout, documents: make(map[string]string)}
+		return server.serve(os.Stdin)
+	}
+}
+
+// lspMessage JSON-RPC request, response or notification
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDocumentParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Position lspPosition `json:"position"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspMarkup struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspCompletionItem struct {
+	Label         string     `json:"label"`
+	Kind          int        `json:"kind"`
+	Detail        string     `json:"detail,omitempty"`
+	Documentation *lspMarkup `json:"documentation,omitempty"`
+	InsertText    string     `json:"insertText,omitempty"`
+}
+
+// lspServer minimal language server: diagnostics of the decorators, completion of marker names and
+// arguments, and hover documentation of markers
+type lspServer struct {
+	out       io.Writer
+	documents map[string]string // open documents by URI
+	mu        sync.Mutex        // serializes writes
+}
+
+// serve reads messages until exit or the end of input
+func (s *lspServer) serve(in io.Reader) error {
+	reader := textproto.NewReader(bufio.NewReader(in))
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading LSP header: %v", err)
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return fmt.Errorf("invalid Content-Length: %v", err)
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader.R, body); err != nil {
+			return fmt.Errorf("error reading LSP message: %v", err)
+		}
+
+		var msg lspMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			continue
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		s.handle(&msg)
+	}
+}
+
+// handle dispatches a request or notification
+func (s *lspServer) handle(msg *lspMessage) {
+	var params lspDocumentParams
+	if len(msg.Params) > 0 {
+		_ = json.Unmarshal(msg.Params, &params)
+	}
+	uri := params.TextDocument.URI
+
+	switch msg.Method {
+	case "initialize":
+		s.reply(msg.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   lspTextSyncFull,
+				"hoverProvider":      true,
+				"completionProvider": map[string]interface{}{"triggerCharacters": []string{"@", "(", ",", " ", "\""}},
+			},
+			"serverInfo": map[string]string{"name": "deco", "version": cliVersion},
+		})
+	case "shutdown":
+		s.reply(msg.ID, nil)
+	case "textDocument/didOpen":
+		s.documents[uri] = params.TextDocument.Text
+		s.publishDiagnostics(uri)
+	case "textDocument/didChange":
+		if n := len(params.ContentChanges); n > 0 {
+			s.documents[uri] = params.ContentChanges[n-1].Text
+		}
+		s.publishDiagnostics(uri)
+	case "textDocument/didSave":
+		s.publishDiagnostics(uri)
+	case "textDocument/didClose":
+		delete(s.documents, uri)
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": uri, "diagnostics": []lspDiagnostic{}})
+	case "textDocument/completion":
+		s.reply(msg.ID, s.completion(uri, params.Position))
+	case "textDocument/hover":
+		s.reply(msg.ID, s.hover(uri, params.Position))
+	default:
+		if msg.ID != nil {
+			s.write(&lspMessage{JSONRPC: "2.0", ID: msg.ID, Error: &lspError{Code: lspMethodNotFound, Message: "method not supported: " + msg.Method}})
+		}
+	}
+}
+
+// publishDiagnostics checks the decorators of the document
+func (s *lspServer) publishDiagnostics(uri string) {
+	text, ok := s.documents[uri]
+	if !ok || !strings.HasSuffix(uri, ".go") {
+		return
+	}
+	lines := strings.Split(text, "\n")
+
+	diagnostics := []lspDiagnostic{}
+	for _, verr := range decorators.ValidateSource(uriPath(uri), []byte(text)) {
+		severity := lspSeverityError
+		if verr.Code == decorators.UnknownMarkerCode {
+			severity = lspSeverityWarning
+		}
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    decoratorRange(lines, verr.Line-1),
+			Severity: severity,
+			Code:     verr.Code,
+			Source:   "deco",
+			Message:  verr.Message,
+		})
+	}
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": uri, "diagnostics": diagnostics})
+}
+
+// decoratorRange range from the decorator ('@') to the end of the line
+func decoratorRange(lines []string, line int) lspRange {
+	if line < 0 || line >= len(lines) {
+		return lspRange{}
+	}
+	text := strings.TrimRight(lines[line], "\r")
+	start := strings.Index(text, "@")
+	if start < 0 {
+		start = len(text) - len(strings.TrimLeft(text, " \t"))
+	}
+	return lspRange{
+		Start: lspPosition{Line: line, Character: utf16Length(text[:start])},
+		End:   lspPosition{Line: line, Character: utf16Length(text)},
+	}
+}
+
+// completion offers marker names after '@' and argument keys or enum values inside the parentheses
+func (s *lspServer) completion(uri string, pos lspPosition) []lspCompletionItem {
+	prefix := s.linePrefix(uri, pos)
+	items := []lspCompletionItem{}
+
+	if match := lspOpenMarker.FindStringSubmatch(prefix); match != nil {
+		marker, ok := decorators.LookupEditorMarker(match[1])
+		if !ok {
+			return items
+		}
+		if value := lspArgValue.FindStringSubmatch(match[2]); value != nil {
+			for _, arg := range marker.Args {
+				if arg.Name != value[1] {
+					continue
+				}
+				for _, option := range arg.Enum {
+					items = append(items, lspCompletionItem{Label: option, Kind: lspCompletionValue})
+				}
+				if arg.Type == decorators.MarkerArgBool {
+					items = append(items, lspCompletionItem{Label: "true", Kind: lspCompletionValue},
+						lspCompletionItem{Label: "false", Kind: lspCompletionValue})
+				}
+			}
+			return items
+		}
+		for _, arg := range marker.Args {
+			detail := arg.Type
+			if detail == "" {
+				detail = decorators.MarkerArgString
+			}
+			items = append(items, lspCompletionItem{Label: arg.Name, Kind: lspCompletionProperty, Detail: detail, InsertText: arg.Name + "="})
+		}
+		return items
+	}
+
+	if lspMarkerPrefix.MatchString(prefix) {
+		for _, marker := range decorators.EditorMarkers() {
+			items = append(items, lspCompletionItem{
+				Label:         marker.Name,
+				Kind:          lspCompletionKeyword,
+				Detail:        marker.Description,
+				Documentation: &lspMarkup{Kind: "markdown", Value: marker.Markdown()},
+				InsertText:    marker.Name + "(",
+			})
+		}
+	}
+	return items
+}
+
+// hover documents the marker under the cursor
+func (s *lspServer) hover(uri string, pos lspPosition) interface{} {
+	lines := strings.Split(s.documents[uri], "\n")
+	if pos.Line >= len(lines) {
+		return nil
+	}
+	line := lines[pos.Line]
+	if !strings.Contains(line, "//") {
+		return nil
+	}
+
+	offset := byteOffset(line, pos.Character)
+	start, end := offset, offset
+	for start > 0 && isWordByte(line[start-1]) {
+		start--
+	}
+	for end < len(line) && isWordByte(line[end]) {
+		end++
+	}
+	if start == 0 || line[start-1] != '@' || start == end {
+		return nil
+	}
+
+	marker, ok := decorators.LookupEditorMarker(line[start:end])
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		"contents": lspMarkup{Kind: "markdown", Value: marker.Markdown()},
+		"range": lspRange{
+			Start: lspPosition{Line: pos.Line, Character: utf16Length(line[:start-1])},
+			End:   lspPosition{Line: pos.Line, Character: utf16Length(line[:end])},
+		},
+	}
+}
+
+// linePrefix text of the line before the cursor
+func (s *lspServer) linePrefix(uri string, pos lspPosition) string {
+	lines := strings.Split(s.documents[uri], "\n")
+	if pos.Line >= len(lines) {
+		return ""
+	}
+	line := lines[pos.Line]
+	return line[:byteOffset(line, pos.Character)]
+}
+
+// reply answers a request
+func (s *lspServer) reply(id *json.RawMessage, result interface{}) {
+	if id == nil {
+		return
+	}
+	if result == nil {
+		// "result": null must be present in a successful response
+		s.writeRaw(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":null}`, *id))
+		return
+	}
+	s.write(&lspMessage{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// notify sends a notification
+func (s *lspServer) notify(method string, params interface{}) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	s.write(&lspMessage{JSONRPC: "2.0", Method: method, Params: data})
+}
+
+func (s *lspServer) write(msg *lspMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.writeRaw(string(data))
+}
+
+func (s *lspServer) writeRaw(body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// uriPath file path of a file:// URI
+func uriPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return parsed.Path
+}
+
+// byteOffset byte offset of a UTF-16 character position (LSP positions count UTF-16 code units)
+func byteOffset(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// utf16Length length of s in UTF-16 code units
+func utf16Length(s string) int {
+	units := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		units += utf16.RuneLen(r)
+		s = s[size:]
+	}
+	return units
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
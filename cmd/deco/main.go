@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"go/parser"
+	"go/token"
 	"log"
 	"net"
 	"os"
@@ -20,81 +22,106 @@ import (
 )
 
 func main() {
-	// Check for init command before flag parsing
-	if len(os.Args) > 1 && os.Args[1] == "init" {
-		verbose := contains(os.Args, "-v") || contains(os.Args, "--verbose")
-		if err := handleInitCommand(verbose); err != nil {
-			log.Fatalf("❌ Error in init command: %v", err)
-		}
-		return
+	if err := runCLI(os.Args[1:]); err != nil {
+		log.Fatalf("❌ %v", err)
 	}
+}
 
-	// Check for dev command for hot reload
-	if len(os.Args) > 1 && os.Args[1] == "dev" {
-		verbose := contains(os.Args, "-v") || contains(os.Args, "--verbose")
-		port := "8080"
-		if len(os.Args) > 2 && strings.HasPrefix(os.Args[2], "--port=") {
-			port = strings.TrimPrefix(os.Args[2], "--port=")
+// setupGenerateCommand declares the generate flags (also accepted without the command name)
+func setupGenerateCommand(fs *flag.FlagSet) func(args []string) error {
+	var (
+		configPath   = fs.String("config", "", "Configuration file path")
+		rootDir      = fs.String("root", "", "Root directory to search for handlers (overrides config)")
+		outputPath   = fs.String("out", "", "Output file path (overrides config)")
+		packageName  = fs.String("pkg", "", "Package name for the generated file (overrides config)")
+		templatePath = fs.String("template", "", "Path to custom template (overrides config)")
+		validate     = fs.Bool("validate", true, "Validate generated file")
+		all          = fs.Bool("all", false, "Generate every service of the workspace (each .deco.yaml found)")
+		verbose      = fs.Bool("v", false, "Verbose output")
+		version      = fs.Bool("version", false, "Show version")
+		output       = outputFlag(fs)
+	)
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument '%s'", args[0])
 		}
-		if err := handleDevCommand(verbose, port); err != nil {
-			log.Fatalf("❌ Error in dev command: %v", err)
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
 		}
-		return
-	}
 
-	var (
-		// Main flags
-		configPath   = flag.String("config", "", "Configuration file path")
-		rootDir      = flag.String("root", "", "Root directory to search for handlers (overrides config)")
-		outputPath   = flag.String("out", "", "Output file path (overrides config)")
-		packageName  = flag.String("pkg", "", "Package name for the generated file (overrides config)")
-		templatePath = flag.String("template", "", "Path to custom template (overrides config)")
-		validate     = flag.Bool("validate", true, "Validate generated file")
-		verbose      = flag.Bool("v", false, "Verbose output")
-		version      = flag.Bool("version", false, "Show version")
-	)
+		// Version command
+		if *version {
+			fmt.Printf("deco Code Generator %s\n", cliVersion)
+			fmt.Println("Code generator for the deco framework")
+			return nil
+		}
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "deco Code Generator v1.0.0\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s [options] [command]\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Commands:\n")
-		fmt.Fprintf(os.Stderr, "  init                 Create .deco.yaml configuration file\n")
-		fmt.Fprintf(os.Stderr, "  generate (default)   Generate code based on configuration\n")
-		fmt.Fprintf(os.Stderr, "  dev                  Start development server with hot reload\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  %s init                                    # Create default configuration\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s                                         # Use .deco.yaml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -config custom.yaml                     # Use custom configuration\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -root ./handlers -out ./init.go -pkg handlers  # Legacy mode\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s dev                                     # Development mode with hot reload\n", os.Args[0])
-	}
-
-	flag.Parse()
-
-	// Version command
-	if *version {
-		fmt.Println("deco Code Generator v1.0.0")
-		fmt.Println("Code generator for the deco framework")
-		return
+		// Configure logging
+		if *verbose {
+			log.SetFlags(log.LstdFlags | log.Lshortfile)
+		} else {
+			log.SetFlags(0)
+		}
+
+		report := newCLIReport("generate", "")
+		if *all {
+			if *configPath != "" || *rootDir != "" || *outputPath != "" {
+				return fmt.Errorf("-all cannot be combined with -config, -root or -out")
+			}
+			err = handleGenerateAllCommand(*packageName, *templatePath, *validate, *verbose, report)
+			if jsonMode {
+				report.write(err)
+			}
+			return err
+		}
+		err = handleGenerateCommand(*configPath, *rootDir, *outputPath, *packageName, *templatePath, *validate, *verbose)
+		if jsonMode {
+			report.addGenerated(generatedOutputPath(*configPath, *outputPath))
+			report.write(err)
+		}
+		return err
 	}
+}
 
-	// Configure logging
-	if *verbose {
-		log.SetFlags(log.LstdFlags | log.Lshortfile)
-	} else {
-		log.SetFlags(0)
+// setupInitCommand declares the init flags
+func setupInitCommand(fs *flag.FlagSet) func(args []string) error {
+	verbose := fs.Bool("v", false, "Verbose output")
+	fs.BoolVar(verbose, "verbose", false, "Verbose output")
+	output := outputFlag(fs)
+	return func(_ []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		report := newCLIReport("init", "")
+		err = handleInitCommand(*verbose, report)
+		if jsonMode {
+			report.write(err)
+		}
+		return err
 	}
+}
 
-	// Generate command (default)
-	if err := handleGenerateCommand(*configPath, *rootDir, *outputPath, *packageName, *templatePath, *validate, *verbose); err != nil {
-		log.Fatalf("❌ Generation error: %v", err)
+// setupDevCommand declares the dev flags
+func setupDevCommand(fs *flag.FlagSet) func(args []string) error {
+	verbose := fs.Bool("v", false, "Verbose output")
+	fs.BoolVar(verbose, "verbose", false, "Verbose output")
+	port := fs.String("port", "8080", "Port of the development server")
+	output := outputFlag(fs)
+	return func(_ []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		return handleDevCommand(*verbose, *port, jsonMode)
 	}
 }
 
-// handleInitCommand executes the initialization command
-func handleInitCommand(verbose bool) error {
+// handleInitCommand executes the initialization command, recording the files written and the
+// errors of the initial generation in report
+func handleInitCommand(verbose bool, report *cliReport) error {
 	configFile := ".deco.yaml"
 
 	// Check if it already exists
@@ -120,6 +147,7 @@ func handleInitCommand(verbose bool) error {
 	if err := decorators.SaveConfig(config, configFile); err != nil {
 		return fmt.Errorf("error saving configuration: %v", err)
 	}
+	report.addFile(configFile)
 
 	fmt.Printf("✅ Configuration file created: %s\n\n", configFile)
 
@@ -127,8 +155,8 @@ func handleInitCommand(verbose bool) error {
 		fmt.Println("📋 Configuration created with:")
 		fmt.Printf("   - %d include patterns\n", len(config.Handlers.Include))
 		fmt.Printf("   - %d exclude patterns\n", len(config.Handlers.Exclude))
-		fmt.Printf("   - Fixed output: ./.deco/init_decorators.go\n")
-		fmt.Printf("   - Fixed package: deco\n")
+		fmt.Printf("   - Output: %s (generation.output)\n", config.Generate.OutputPath())
+		fmt.Printf("   - Package: %s (generation.package)\n", config.Generate.PackageName())
 		fmt.Println("\n🔧 Configurable features:")
 		fmt.Printf("   - Redis: %v (Address: %s)\n", config.Redis.Enabled, config.Redis.Address)
 		fmt.Printf("   - Cache: %s (TTL: %s)\n", config.Cache.Type, config.Cache.DefaultTTL)
@@ -173,11 +201,13 @@ func handleInitCommand(verbose bool) error {
 	// Run initial generation
 	if err := handleGenerateCommand(configFile, "", "", "", "", true, verbose); err != nil {
 		fmt.Printf("⚠️  Error in initial generation: %v\n", err)
+		report.addError(err)
 		return printNextSteps()
 	}
+	report.addGenerated(config.Generate.OutputPath())
 
 	fmt.Println("\n🎉 Project initialized successfully!")
-	fmt.Println("📁 Generated file: ./.deco/init_decorators.go")
+	fmt.Printf("📁 Generated file: %s\n", config.Generate.OutputPath())
 	fmt.Println("\n🚀 Next steps:")
 	fmt.Println("   1. Import the generated package in your main.go:")
 	fmt.Println("      import _ \"yourmodule/.deco\"")
@@ -255,16 +285,14 @@ func handleGenerateCommand(configPath, rootDir, outputPath, packageName, templat
 		}
 	}
 
-	// Force use of .deco folder in root (not customizable)
-	finalOutput := "./.deco/init_decorators.go"
-	finalPackage := "deco"
-
-	// Ignore user output and package configurations
-	if outputPath != "" && verbose {
-		log.Printf("⚠️  Ignoring -out: always uses ./.deco/init_decorators.go")
+	// generation.output and generation.package, overridden by -out and -pkg
+	finalOutput, finalPackage, err := resolveOutput(config, outputPath, packageName)
+	if err != nil {
+		return err
 	}
-	if packageName != "" && verbose {
-		log.Printf("⚠️  Ignoring -pkg: always uses package deco")
+	handlerFiles, err = excludeGeneratedFiles(handlerFiles, finalOutput, finalPackage)
+	if err != nil {
+		return err
 	}
 
 	finalTemplate := config.Generate.Template
@@ -275,7 +303,7 @@ func handleGenerateCommand(configPath, rootDir, outputPath, packageName, templat
 	// Final logs
 	if verbose {
 		log.Printf("📄 Output file: %s", finalOutput)
-		log.Printf("�� Package name: %s", finalPackage)
+		log.Printf("📦 Package name: %s", finalPackage)
 		if finalTemplate != "" {
 			log.Printf("🎨 Custom template: %s", finalTemplate)
 		}
@@ -303,8 +331,10 @@ func handleLegacyMode(rootDir, outputPath, packageName, templatePath string, val
 		return err
 	}
 
-	// Force use of .deco folder in root (not customizable)
-	outputPath, packageName = setupLegacyPaths(outputPath, packageName, verbose)
+	outputPath, packageName, err := setupLegacyPaths(outputPath, packageName)
+	if err != nil {
+		return err
+	}
 
 	absRootDir, absOutputPath, err := resolveLegacyPaths(rootDir, outputPath)
 	if err != nil {
@@ -321,7 +351,7 @@ func handleLegacyMode(rootDir, outputPath, packageName, templatePath string, val
 		return err
 	}
 
-	if err := validateLegacyFile(validate, verbose); err != nil {
+	if err := validateLegacyFile(absOutputPath, validate, verbose); err != nil {
 		return err
 	}
 
@@ -338,15 +368,83 @@ func validateLegacyArgs(rootDir string) error {
 	return nil
 }
 
-// setupLegacyPaths sets up legacy paths and logs warnings
-func setupLegacyPaths(outputPath, packageName string, verbose bool) (outputPathResult, packageNameResult string) {
-	if outputPath != "" && verbose {
-		log.Printf("⚠️  Ignoring -out: always uses ./.deco/init_decorators.go")
+// setupLegacyPaths resolves the output and package of legacy mode from the flags and the configuration
+func setupLegacyPaths(outputPath, packageName string) (outputPathResult, packageNameResult string, err error) {
+	config, configErr := decorators.LoadConfig("")
+	if configErr != nil {
+		config = decorators.DefaultConfig()
+	}
+	return resolveOutput(config, outputPath, packageName)
+}
+
+// resolveOutput returns the generated file and its package: -out and -pkg when given, the generation
+// section of the configuration otherwise
+func resolveOutput(config *decorators.Config, outputPath, packageName string) (string, string, error) {
+	if outputPath == "" {
+		outputPath = config.Generate.OutputPath()
+	} else if err := decorators.ValidateOutputPath(outputPath); err != nil {
+		return "", "", fmt.Errorf("invalid -out: %v", err)
+	}
+	if packageName == "" {
+		packageName = config.Generate.PackageName()
+	} else if err := decorators.ValidatePackageName(packageName); err != nil {
+		return "", "", fmt.Errorf("invalid -pkg: %v", err)
+	}
+	return outputPath, packageName, nil
+}
+
+// generatedOutputPath the file written by generate, for the reports
+func generatedOutputPath(configPath, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	config, err := decorators.LoadConfig(configPath)
+	if err != nil {
+		return decorators.DefaultOutputPath
+	}
+	return config.Generate.OutputPath()
+}
+
+// excludeGeneratedFiles drops the generated files from the discovered handlers. Handlers sharing the
+// directory of the generated file must be in its package, as Go allows one package per directory.
+func excludeGeneratedFiles(handlerFiles []string, outputPath, packageName string) ([]string, error) {
+	outputDir, err := filepath.Abs(filepath.Dir(outputPath))
+	if err != nil {
+		return nil, err
+	}
+	generated := make(map[string]bool)
+	for _, file := range decorators.GeneratedFiles(outputPath) {
+		if abs, err := filepath.Abs(file); err == nil {
+			generated[abs] = true
+		}
+	}
+
+	files := make([]string, 0, len(handlerFiles))
+	for _, file := range handlerFiles {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		if generated[abs] {
+			continue
+		}
+		if filepath.Dir(abs) == outputDir {
+			if pkg := goPackageName(abs); pkg != "" && pkg != packageName {
+				return nil, fmt.Errorf("handler %s is in package %s, but the generated code of package %s is written to the same directory; change generation.output or generation.package", file, pkg, packageName)
+			}
+		}
+		files = append(files, file)
 	}
-	if packageName != "" && verbose {
-		log.Printf("⚠️  Ignoring -pkg: always uses package deco")
+	return files, nil
+}
+
+// goPackageName package clause of a Go file, "" when it cannot be parsed
+func goPackageName(file string) string {
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return ""
 	}
-	return "./.deco/init_decorators.go", "deco"
+	return parsed.Name.Name
 }
 
 // resolveLegacyPaths resolves absolute paths for legacy mode
@@ -400,7 +498,11 @@ func generateWithCustomTemplate(absRootDir, absOutputPath, packageName, template
 		log.Printf("🎨 Using custom template: %s", absTemplatePath)
 	}
 
-	return decorators.GenerateFromTemplate(absRootDir, absTemplatePath, absOutputPath, packageName)
+	config, configErr := decorators.LoadConfig("")
+	if configErr != nil {
+		config = decorators.DefaultConfig()
+	}
+	return decorators.GenerateFromTemplateWithConfig(absRootDir, absTemplatePath, absOutputPath, packageName, config)
 }
 
 // generateWithDefaultConfig generates file with default configuration
@@ -413,7 +515,7 @@ func generateWithDefaultConfig(absRootDir, absOutputPath, packageName string) er
 }
 
 // validateLegacyFile validates the generated file if needed
-func validateLegacyFile(validate, verbose bool) error {
+func validateLegacyFile(outputPath string, validate, verbose bool) error {
 	if !validate {
 		return nil
 	}
@@ -427,7 +529,7 @@ func validateLegacyFile(validate, verbose bool) error {
 		log.Printf("✅ Validating generated file...")
 	}
 
-	if err := decorators.ValidateGeneration("./.deco/init_decorators.go"); err != nil {
+	if err := decorators.ValidateGeneration(outputPath); err != nil {
 		enhancedErr := enhanceErrorWithSourceInfo(err, ".deco.yaml")
 		return fmt.Errorf("validation failed: %v", enhancedErr)
 	}
@@ -468,7 +570,7 @@ func generateFromFilesWithConfig(handlerFiles []string, outputPath, packageName,
 
 	// Use default generation in root directory
 	if templatePath != "" {
-		return decorators.GenerateFromTemplate(rootDir, templatePath, outputPath, packageName)
+		return decorators.GenerateFromTemplateWithConfig(rootDir, templatePath, outputPath, packageName, config)
 	}
 
 	return decorators.GenerateInitFileWithConfig(rootDir, outputPath, packageName, config)
@@ -499,18 +601,8 @@ func findCommonRoot(files []string) string {
 	return common
 }
 
-// contains checks if slice contains a string
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
 // handleDevCommand executes hot reload development server
-func handleDevCommand(verbose bool, port string) error {
+func handleDevCommand(verbose bool, port string, jsonMode bool) error {
 	// Configure logging based on verbose flag
 	decorators.SetVerbose(verbose)
 
@@ -534,7 +626,13 @@ func handleDevCommand(verbose bool, port string) error {
 	if verbose {
 		fmt.Println("🔄 Generating initial code...")
 	}
-	if err := handleGenerateCommand(configFile, "", "", "", "", true, verbose); err != nil {
+	report := newCLIReport("dev", "generated")
+	err = handleGenerateCommand(configFile, "", "", "", "", true, verbose)
+	if jsonMode {
+		report.addGenerated(config.Generate.OutputPath())
+		report.write(err)
+	}
+	if err != nil {
 		return fmt.Errorf("error in initial generation: %v", err)
 	}
 
@@ -550,6 +648,7 @@ func handleDevCommand(verbose bool, port string) error {
 	devServer := &DevServer{
 		Port:       port,
 		Verbose:    verbose,
+		JSON:       jsonMode,
 		Config:     config,
 		ConfigFile: configFile,
 		ReloadChan: reloadChan,
@@ -581,6 +680,7 @@ func handleDevCommand(verbose bool, port string) error {
 type DevServer struct {
 	Port       string
 	Verbose    bool
+	JSON       bool // one JSON report per regeneration on stdout
 	Config     *decorators.Config
 	ConfigFile string
 	ReloadChan chan bool
@@ -762,7 +862,13 @@ func (ds *DevServer) regenerateCode() {
 	}
 
 	// Regenerate code
-	if err := handleGenerateCommand(ds.ConfigFile, "", "", "", "", true, false); err != nil {
+	report := newCLIReport("dev", "regenerated")
+	err := handleGenerateCommand(ds.ConfigFile, "", "", "", "", true, false)
+	if ds.JSON {
+		report.addGenerated(ds.Config.Generate.OutputPath())
+		report.write(err)
+	}
+	if err != nil {
 		// Enhanced error reporting with source file information
 		enhancedErr := enhanceErrorWithSourceInfo(err, ds.ConfigFile)
 		fmt.Printf("❌ Error in regeneration: %v\n", enhancedErr)
@@ -807,13 +913,14 @@ func (ds *DevServer) shouldProcessEvent(event fsnotify.Event) bool {
 		return false
 	}
 
-	// DO NOT process .deco/init_decorators.go file to avoid infinite loop
-	initDecoratorsPath, err := filepath.Abs("./.deco/init_decorators.go")
-	if err == nil && eventPath == initDecoratorsPath {
-		if ds.Verbose {
-			fmt.Printf("⏭️  Ignoring .deco/init_decorators.go (generated file)\n")
+	// DO NOT process the generated files to avoid an infinite loop
+	for _, generated := range decorators.GeneratedFiles(ds.Config.Generate.OutputPath()) {
+		if generatedPath, err := filepath.Abs(generated); err == nil && eventPath == generatedPath {
+			if ds.Verbose {
+				fmt.Printf("⏭️  Ignoring %s (generated file)\n", generated)
+			}
+			return false
 		}
-		return false
 	}
 
 	// Verify if the file is in the list of monitored handlers
@@ -1111,7 +1218,12 @@ func enhanceErrorWithSourceInfo(err error, configFile string) error {
 	}
 
 	// Check if it's a parsing error during generation
-	if strings.Contains(errStr, "./.deco/init_decorators.go") {
+	if strings.Contains(errStr, "init_decorators.go") {
+		// Use the source map to point at the exact decorator when possible
+		if location := lookupSourceMap(errStr, configFile); location != "" {
+			return fmt.Errorf("❌ Decorator error at %s: %v", location, err)
+		}
+
 		// Extract line information if available
 		if strings.Contains(errStr, "syntax error") || strings.Contains(errStr, "expected") {
 			// Load config to get handler files
@@ -1155,6 +1267,38 @@ func enhanceErrorWithSourceInfo(err error, configFile string) error {
 	return err
 }
 
+// lookupSourceMap maps a generated line referenced in an error to its originating decorator
+func lookupSourceMap(errStr, configFile string) string {
+	outputPath := decorators.DefaultOutputPath
+	if config, err := decorators.LoadConfig(configFile); err == nil {
+		outputPath = config.Generate.OutputPath()
+	}
+
+	// Only match references to the configured output file or its per-package shards, with or without a leading directory
+	base := strings.TrimSuffix(filepath.ToSlash(filepath.Clean(outputPath)), ".go")
+	pattern := `(?:^|[\s/"'])` + regexp.QuoteMeta(base) + `(_\w+_routes)?\.go:(\d+)`
+	match := regexp.MustCompile(pattern).FindStringSubmatch(filepath.ToSlash(errStr))
+	if match == nil {
+		return ""
+	}
+
+	line, err := strconv.Atoi(match[2])
+	if err != nil {
+		return ""
+	}
+
+	sm, err := decorators.LoadSourceMap(decorators.SourceMapPath(outputPath))
+	if err != nil {
+		return ""
+	}
+
+	var shard string
+	if match[1] != "" {
+		shard = filepath.Base(base) + match[1] + ".go"
+	}
+	return sm.DescribeIn(shard, line)
+}
+
 // extractLineInfoFromError extracts line information from error messages
 func extractLineInfoFromError(errStr string) string {
 	// Look for patterns like ":123:" or ":123:123:"
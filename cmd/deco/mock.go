@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupMockCommand declares the mock flags; "deco mock" serves example responses of the API contract
+// generated from the handlers, before they are implemented
+func setupMockCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path")
+	specPath := fs.String("spec", "", "OpenAPI JSON file (default: generated from the handlers)")
+	port := fs.String("port", "9090", "Port of the mock server")
+	seed := fs.Int64("seed", 1, "Seed of the values generated from the schemas")
+	cors := fs.Bool("cors", true, "Allow every origin and answer preflight requests")
+
+	return func(_ []string) error {
+		if !isValidPort(*port) {
+			return fmt.Errorf("invalid port '%s'", *port)
+		}
+
+		var spec *decorators.OpenAPISpec
+		var err error
+		if *specPath != "" {
+			spec, err = decorators.LoadOpenAPISpec(*specPath)
+		} else {
+			spec, err = handlerSpec(*configPath)
+		}
+		if err != nil {
+			return err
+		}
+		operations := 0
+		for _, path := range spec.Paths {
+			operations += len(path)
+		}
+		if operations == 0 {
+			return fmt.Errorf("no operation to mock: the API contract is empty")
+		}
+
+		server := &http.Server{
+			Addr:              net.JoinHostPort("", *port),
+			Handler:           logMockRequests(decorators.NewMockServer(spec, decorators.MockServerOptions{Seed: *seed, CORS: *cors})),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		log.Printf("🎭 Mock server on http://localhost:%s (%d operations, header %s)", *port, operations, decorators.MockHeader)
+		log.Printf("💡 'Prefer: code=404' selects another declared response, 'Prefer: example=name' a named example")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("mock server: %v", err)
+		}
+		return nil
+	}
+}
+
+// mockStatusRecorder records the status written by the mock server
+type mockStatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *mockStatusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logMockRequests prints one line per request served by handler
+func logMockRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &mockStatusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		log.Printf("%s %s → %d", r.Method, r.URL.RequestURI(), recorder.status)
+	})
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupOpenAPIDiffCommand declares the openapi diff flags; the command compares two versions of the API
+// contract and fails on breaking changes, to gate merges
+func setupOpenAPIDiffCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path")
+	base := fs.String("base", "", "Git ref whose handlers give the previous contract, e.g. HEAD or origin/main")
+	failOn := fs.String("fail-on", "breaking", "Exit non-zero on: breaking, any or none")
+	output := outputFlag(fs)
+
+	return func(args []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		report := newCLIReport("openapi diff", "")
+		if *failOn != "breaking" && *failOn != "any" && *failOn != "none" {
+			return finishReportCommand(report, jsonMode, fmt.Errorf("invalid --fail-on '%s', expected breaking, any or none", *failOn))
+		}
+
+		// OLD.json [NEW.json] or --base REF [NEW.json]; without NEW.json the handlers give the current contract
+		var previous, current *decorators.OpenAPISpec
+		switch {
+		case *base != "" && len(args) <= 1:
+			previous, err = handlerSpecAt(*configPath, *base)
+		case *base == "" && (len(args) == 1 || len(args) == 2):
+			previous, err = decorators.LoadOpenAPISpec(args[0])
+			args = args[1:]
+		default:
+			err = errors.New("usage: deco openapi diff OLD.json [NEW.json] | --base REF [NEW.json]")
+		}
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+		if len(args) == 1 {
+			current, err = decorators.LoadOpenAPISpec(args[0])
+		} else {
+			current, err = handlerSpec(*configPath)
+		}
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+
+		changes := decorators.CompareOpenAPISpecs(previous, current)
+		report.OpenAPIDiff = changes
+		if !jsonMode {
+			printSpecChanges(changes)
+		}
+
+		breaking := len(changes.Breaking())
+		switch {
+		case *failOn == "breaking" && breaking > 0:
+			err = fmt.Errorf("%d breaking change(s) in the API contract", breaking)
+		case *failOn == "any" && len(changes.Changes) > 0:
+			err = fmt.Errorf("%d change(s) in the API contract", len(changes.Changes))
+		}
+		return finishReportCommand(report, jsonMode, err)
+	}
+}
+
+// handlerSpec contract generated from the handlers of the working tree
+func handlerSpec(configPath string) (*decorators.OpenAPISpec, error) {
+	config, err := decorators.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading configuration: %v", err)
+	}
+	decorators.ClearSchemas()
+	routes, err := discoverRoutes(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return decorators.GenerateOpenAPISpecFromMeta(config, routes), nil
+}
+
+// handlerSpecAt contract generated from the handlers of the current directory at a git ref, with the
+// configuration of the working tree
+func handlerSpecAt(configPath, ref string) (*decorators.OpenAPISpec, error) {
+	config, err := decorators.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading configuration: %v", err)
+	}
+	dir, err := os.MkdirTemp("", "deco-openapi-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractGitTree(ref, dir); err != nil {
+		return nil, err
+	}
+	decorators.ClearSchemas()
+	routes, err := discoverRoutesIn(config, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ref, err)
+	}
+	return decorators.GenerateOpenAPISpecFromMeta(config, routes), nil
+}
+
+// extractGitTree writes the files of the current directory at ref into dir
+func extractGitTree(ref, dir string) error {
+	location, err := exec.Command("git", "rev-parse", "--show-toplevel", "--show-prefix").Output()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %v", err)
+	}
+	toplevel, prefix, _ := strings.Cut(strings.TrimSpace(string(location)), "\n")
+
+	// Archived from the top level: in a subdirectory, git archive only reads that directory of the ref
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "archive", "--format=tar", ref+":"+prefix)
+	cmd.Dir = toplevel
+	cmd.Stderr = &stderr
+	archive, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("could not read %s: %s", ref, strings.TrimSpace(stderr.String()))
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg || !filepath.IsLocal(header.Name) {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+}
+
+// printSpecChanges prints the breaking changes, then the others
+func printSpecChanges(report *decorators.SpecChangeReport) {
+	if len(report.Changes) == 0 {
+		fmt.Println("✅ No changes in the API contract")
+		return
+	}
+	breaking := report.Breaking()
+	if len(breaking) > 0 {
+		fmt.Printf("❌ %d breaking change(s):\n", len(breaking))
+		for _, change := range breaking {
+			fmt.Printf("   %s: %s\n", change.Location, change.Message)
+		}
+	}
+	if others := len(report.Changes) - len(breaking); others > 0 {
+		fmt.Printf("ℹ️  %d compatible change(s):\n", others)
+		for _, change := range report.Changes {
+			if !change.Breaking {
+				fmt.Printf("   %s: %s\n", change.Location, change.Message)
+			}
+		}
+	}
+}
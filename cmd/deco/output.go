@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// jsonStdout real stdout while a command runs with --output json: the human-readable messages are
+// moved to stderr so stdout only carries the JSON reports
+var jsonStdout io.Writer
+
+// outputFlag declares --output on a command
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("output", "text", "Output format: text or json (machine-readable report on stdout)")
+}
+
+// startOutput checks the --output value and, for json, moves the human-readable output to stderr
+func startOutput(format string) (jsonMode bool, err error) {
+	switch format {
+	case "text":
+		return false, nil
+	case "json":
+		if jsonStdout == nil {
+			jsonStdout = os.Stdout
+			os.Stdout = os.Stderr
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown output '%s' (valid: text, json)", format)
+	}
+}
+
+// cliReport machine-readable result of a command, written as one JSON line per run (per generation in dev)
+type cliReport struct {
+	Command  string     `json:"command"`
+	Event    string     `json:"event,omitempty"`
+	Success  bool       `json:"success"`
+	Duration string     `json:"duration"`
+	Files    []string   `json:"files,omitempty"`
+	Routes   []cliRoute `json:"routes,omitempty"`
+	Errors   []cliError `json:"errors,omitempty"`
+
+	Checks []doctorCheck `json:"checks,omitempty"` // deco doctor
+
+	Scenarios   *decorators.ScenarioReport   `json:"scenarios,omitempty"`    // deco test
+	Load        *decorators.LoadReport       `json:"load,omitempty"`         // deco load
+	OpenAPIDiff *decorators.SpecChangeReport `json:"openapi_diff,omitempty"` // deco openapi diff
+
+	start time.Time
+}
+
+// cliRoute route discovered in the handlers
+type cliRoute struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// cliError error with its source position when known
+type cliError struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// newCLIReport starts the report of a command run
+func newCLIReport(command, event string) *cliReport {
+	return &cliReport{Command: command, Event: event, start: time.Now()}
+}
+
+// addFile records a file written by the run
+func (r *cliReport) addFile(path string) {
+	r.Files = append(r.Files, filepath.Clean(path))
+}
+
+// addGenerated records the generated file and its source map, and the routes listed in the source map
+func (r *cliReport) addGenerated(outputPath string) {
+	info, err := os.Stat(outputPath)
+	if err != nil || info.ModTime().Before(r.start.Add(-time.Second)) {
+		return
+	}
+	for _, file := range decorators.GeneratedFiles(outputPath) {
+		r.addFile(file)
+	}
+
+	sourceMapPath := decorators.SourceMapPath(outputPath)
+	sm, err := decorators.LoadSourceMap(sourceMapPath)
+	if err != nil {
+		return
+	}
+	r.addFile(sourceMapPath)
+	for _, entry := range sm.Entries {
+		if entry.Method == "" {
+			continue
+		}
+		r.Routes = append(r.Routes, cliRoute{
+			Method:  entry.Method,
+			Path:    entry.Path,
+			Handler: entry.FuncName,
+			File:    entry.File,
+			Line:    entry.Line,
+		})
+	}
+}
+
+// addRoutes records parsed routes
+func (r *cliReport) addRoutes(routes []*decorators.RouteMeta) {
+	for _, route := range routes {
+		r.Routes = append(r.Routes, cliRoute{
+			Method:  route.Method,
+			Path:    route.Path,
+			Handler: route.FuncName,
+			File:    route.FilePath,
+			Line:    route.Line,
+		})
+	}
+}
+
+// addError records err, one entry per decorator error
+func (r *cliReport) addError(err error) {
+	var multiErr *decorators.MultipleValidationError
+	var valErr *decorators.ValidationError
+	switch {
+	case errors.As(err, &multiErr):
+		for _, e := range multiErr.Errors {
+			r.Errors = append(r.Errors, cliError{File: e.File, Line: e.Line, Code: e.Code, Message: e.Message})
+		}
+	case errors.As(err, &valErr):
+		r.Errors = append(r.Errors, cliError{File: valErr.File, Line: valErr.Line, Code: valErr.Code, Message: valErr.Message})
+	default:
+		r.Errors = append(r.Errors, cliError{Message: err.Error()})
+	}
+}
+
+// write completes the report with err and writes it to the real stdout
+func (r *cliReport) write(err error) {
+	if err != nil {
+		r.addError(err)
+	}
+	r.Success = len(r.Errors) == 0
+	r.Duration = time.Since(r.start).Round(time.Millisecond).String()
+
+	out := jsonStdout
+	if out == nil {
+		out = os.Stdout
+	}
+	if encodeErr := json.NewEncoder(out).Encode(r); encodeErr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Error writing JSON report: %v\n", encodeErr)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupRoutesCommand declares the routes flags; the command lists the routes declared by the handlers
+// discovered by the configuration, without generating code
+func setupRoutesCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "Configuration file path")
+	output := outputFlag(fs)
+
+	return func(_ []string) error {
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		report := newCLIReport("routes", "")
+		routes, err := discoverRoutes(*configPath)
+		if jsonMode {
+			report.addRoutes(routes)
+			report.write(err)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tSOURCE")
+		for _, route := range routes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s:%d\n", route.Method, route.Path, route.FuncName, route.FilePath, route.Line)
+		}
+		return w.Flush()
+	}
+}
+
+// discoverRoutes parses the handlers discovered with the configuration patterns
+func discoverRoutes(configPath string) ([]*decorators.RouteMeta, error) {
+	config, err := decorators.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading configuration: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %v", err)
+	}
+	return discoverRoutesIn(config, wd)
+}
+
+// discoverRoutesIn parses the handlers discovered under dir with the configuration patterns
+func discoverRoutesIn(config *decorators.Config, dir string) ([]*decorators.RouteMeta, error) {
+	handlerFiles, err := config.DiscoverHandlers(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error discovering handlers: %v", err)
+	}
+	if len(handlerFiles) == 0 {
+		return nil, nil
+	}
+	return decorators.ParseDirectory(findCommonRoot(handlerFiles))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// setupTestCommand declares the test flags; "deco test FILE" runs the scenarios of FILE against the server
+func setupTestCommand(fs *flag.FlagSet) func(args []string) error {
+	var variables multiFlag
+	target := fs.String("target", "", "Base URL of the server (default: target of the file, then $DECO_SERVER or http://localhost:8080)")
+	specPath := fs.String("spec", "", "OpenAPI JSON file with the routes (default: <target>/decorators/openapi.json)")
+	timeout := fs.Duration("timeout", 30*time.Second, "Timeout of each request")
+	fs.Var(&variables, "var", "Variable name=value, over the variables of the file (repeatable)")
+	output := outputFlag(fs)
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("expected the scenarios file, got %d argument(s)", len(args))
+		}
+		jsonMode, err := startOutput(*output)
+		if err != nil {
+			return err
+		}
+		report := newCLIReport("test", "")
+
+		file, err := decorators.LoadScenarioFile(args[0])
+		if err != nil {
+			return finishReportCommand(report, jsonMode, err)
+		}
+		server := firstNonEmpty(*target, file.Target, envOrDefault("DECO_SERVER", "http://localhost:8080"))
+		runner := decorators.NewHTTPScenarioRunner(server, *timeout)
+		runner.Variables = make(map[string]string, len(variables))
+		for _, variable := range variables {
+			name, value, found := strings.Cut(variable, "=")
+			if !found {
+				return finishReportCommand(report, jsonMode, fmt.Errorf("invalid variable '%s', expected name=value", variable))
+			}
+			runner.Variables[name] = value
+		}
+
+		// The routes of the contract tie the steps to the registry of the server
+		spec, specErr := loadCallSpec(&callOptions{server: strings.TrimSuffix(server, "/"), specPath: *specPath, timeout: *timeout})
+		if specErr == nil {
+			runner.Routes = decorators.SpecScenarioRoutes(spec)
+		} else if !jsonMode {
+			fmt.Printf("⚠️  %v; steps are not matched against the routes\n", specErr)
+		}
+
+		result := runner.Run(context.Background(), file)
+		if result.Failed > 0 {
+			err = fmt.Errorf("%d of %d scenario(s) failed", result.Failed, len(result.Scenarios))
+		}
+		report.Scenarios = result
+		if !jsonMode {
+			printScenarioReport(result)
+		}
+		return finishReportCommand(report, jsonMode, err)
+	}
+}
+
+// finishReportCommand writes the JSON report of a run and returns its error
+func finishReportCommand(report *cliReport, jsonMode bool, err error) error {
+	if jsonMode {
+		report.write(err)
+	}
+	return err
+}
+
+// printScenarioReport prints each step with its failures, then the uncovered routes
+func printScenarioReport(report *decorators.ScenarioReport) {
+	for _, scenario := range report.Scenarios {
+		icon := "✅"
+		if !scenario.Passed {
+			icon = "❌"
+		}
+		fmt.Printf("%s %s\n", icon, scenario.Name)
+		for _, step := range scenario.Steps {
+			status := "ok"
+			if len(step.Failures) > 0 {
+				status = "FAIL"
+			}
+			fmt.Printf("   %-4s %s %s %s (%d, %v)\n", status, step.Name, step.Method, step.Path, step.Status, step.Duration.Round(10*time.Microsecond))
+			for _, failure := range step.Failures {
+				fmt.Printf("        %s\n", failure)
+			}
+		}
+	}
+	if len(report.Uncovered) > 0 {
+		fmt.Printf("\n📋 Routes not called by any step (%d):\n", len(report.Uncovered))
+		for _, route := range report.Uncovered {
+			fmt.Printf("   %s\n", route)
+		}
+	}
+	fmt.Printf("\n%d passed, %d failed\n", report.Passed, report.Failed)
+}
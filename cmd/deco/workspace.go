@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	decorators "github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// handleGenerateAllCommand runs the generation of each service of the workspace, from the service
+// directory with its own .deco.yaml: those listed in deco.workspace.yaml, or every .deco.yaml below
+// the current directory. A failing service does not stop the others.
+func handleGenerateAllCommand(packageName, templatePath string, validate, verbose bool, report *cliReport) error {
+	configFiles, err := workspaceConfigFiles()
+	if err != nil {
+		return err
+	}
+	if len(configFiles) == 0 {
+		return fmt.Errorf("no .deco.yaml found in the workspace")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %v", err)
+	}
+
+	failed := 0
+	for _, configFile := range configFiles {
+		dir := filepath.Dir(configFile)
+		name, relErr := filepath.Rel(wd, dir)
+		if relErr != nil {
+			name = dir
+		}
+		log.Printf("📦 %s", name)
+
+		output, err := generateService(dir, packageName, templatePath, validate, verbose)
+		if err != nil {
+			failed++
+			log.Printf("❌ %s: %v", name, err)
+			report.addError(fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		if !filepath.IsAbs(output) {
+			output = filepath.Join(dir, output)
+		}
+		report.addGenerated(output)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d services failed to generate", failed, len(configFiles))
+	}
+	log.Printf("✅ %d services generated", len(configFiles))
+	return nil
+}
+
+// workspaceConfigFiles the .deco.yaml of each service: from the enclosing deco.workspace.yaml when
+// there is one, found below the current directory otherwise
+func workspaceConfigFiles() ([]string, error) {
+	if workspaceFile := decorators.FindWorkspaceFile("."); workspaceFile != "" {
+		workspace, err := decorators.LoadWorkspace(workspaceFile)
+		if err != nil {
+			return nil, err
+		}
+		return workspace.ConfigFiles()
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %v", err)
+	}
+	return decorators.DiscoverConfigFiles(wd)
+}
+
+// generateService generates the service of dir from its directory, as `deco generate` run there,
+// and returns the generated file relative to dir
+func generateService(dir, packageName, templatePath string, validate, verbose bool) (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return "", fmt.Errorf("error entering %s: %v", dir, err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			log.Printf("⚠️  Error returning to %s: %v", wd, err)
+		}
+	}()
+
+	if err := handleGenerateCommand(".deco.yaml", "", "", packageName, templatePath, validate, verbose); err != nil {
+		return "", err
+	}
+	return generatedOutputPath(".deco.yaml", ""), nil
+}
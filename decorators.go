@@ -2,7 +2,12 @@
 package decorators
 
 // Re-exportar as principais funções e tipos para facilitar o uso
-import "github.com/RodolfoBonis/deco/pkg/decorators"
+import (
+	"iter"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+	"github.com/gin-gonic/gin"
+)
 
 // Re-exportar funções principais
 var (
@@ -12,27 +17,89 @@ var (
 	RegisterGroup         = decorators.RegisterGroup
 	Default               = decorators.Default
 	DefaultWithSecurity   = decorators.DefaultWithSecurity
+	NewEngine             = decorators.NewEngine
+	NewEngineWithSecurity = decorators.NewEngineWithSecurity
 	GetRoutes             = decorators.GetRoutes
 	GetGroups             = decorators.GetGroups
 
 	// Funções de markers
-	RegisterMarker = decorators.RegisterMarker
-	GetMarkers     = decorators.GetMarkers
+	RegisterMarker         = decorators.RegisterMarker
+	GetMarkers             = decorators.GetMarkers
+	GetMarker              = decorators.GetMarker
+	LoadMarkerPlugins      = decorators.LoadMarkerPlugins
+	CreateMarkerMiddleware = decorators.CreateMarkerMiddleware
+	MarkerValue            = decorators.MarkerValue
+	MarkerList             = decorators.MarkerList
+	MarkerJSON             = decorators.MarkerJSON
+	ParseDurationLiteral   = decorators.ParseDurationLiteral
+	ParseByteSize          = decorators.ParseByteSize
 
 	// Hooks
 	RegisterParserHook    = decorators.RegisterParserHook
 	RegisterGeneratorHook = decorators.RegisterGeneratorHook
 
 	// Funções de middleware
-	CreateAuthMiddleware           = decorators.CreateAuthMiddleware
-	CreateCacheMiddleware          = decorators.CreateCacheMiddleware
-	CreateRateLimitMiddleware      = decorators.CreateRateLimitMiddleware
-	CreateMetricsMiddleware        = decorators.CreateMetricsMiddleware
-	CreateCORSMiddleware           = decorators.CreateCORSMiddleware
-	CreateWebSocketMiddleware      = decorators.CreateWebSocketMiddleware
-	CreateWebSocketStatsMiddleware = decorators.CreateWebSocketStatsMiddleware
-	CreateProxyMiddleware          = decorators.CreateProxyMiddleware
-	CreateSecurityMiddleware       = decorators.CreateSecurityMiddleware
+	CreateAuthMiddleware            = decorators.CreateAuthMiddleware
+	CreateCacheMiddleware           = decorators.CreateCacheMiddleware
+	CreateRateLimitMiddleware       = decorators.CreateRateLimitMiddleware
+	CreateMetricsMiddleware         = decorators.CreateMetricsMiddleware
+	CreateCORSMiddleware            = decorators.CreateCORSMiddleware
+	CreateWebSocketMiddleware       = decorators.CreateWebSocketMiddleware
+	CreateWebSocketStatsMiddleware  = decorators.CreateWebSocketStatsMiddleware
+	CreateProxyMiddleware           = decorators.CreateProxyMiddleware
+	CreateSecurityMiddleware        = decorators.CreateSecurityMiddleware
+	CreateMaxResponseSizeMiddleware = decorators.CreateMaxResponseSizeMiddleware
+	CreateMaxBodySizeMiddleware     = decorators.CreateMaxBodySizeMiddleware
+	CreateSlowThresholdMiddleware   = decorators.CreateSlowThresholdMiddleware
+	CreateNoAccessLogMiddleware     = decorators.CreateNoAccessLogMiddleware
+	CreateMockMiddleware            = decorators.CreateMockMiddleware
+	MockMiddleware                  = decorators.MockMiddleware
+	CreateDedupeMiddleware          = decorators.CreateDedupeMiddleware
+	DedupeMiddleware                = decorators.DedupeMiddleware
+	CreateSagaStepMiddleware        = decorators.CreateSagaStepMiddleware
+	SagaStepMiddleware              = decorators.SagaStepMiddleware
+	CreateRequireHeaderMiddleware   = decorators.CreateRequireHeaderMiddleware
+	CreateFileParamMiddleware       = decorators.CreateFileParamMiddleware
+	CreateSensitiveMiddleware       = decorators.CreateSensitiveMiddleware
+	CreateFieldACLMiddleware        = decorators.CreateFieldACLMiddleware
+	CreateSSEMiddleware             = decorators.CreateSSEMiddleware
+	CreateCircuitBreakerMiddleware  = decorators.CreateCircuitBreakerMiddleware
+	CircuitBreakerMiddleware        = decorators.CircuitBreakerMiddleware
+	CircuitBreakerStats             = decorators.CircuitBreakerStats
+	CircuitBreakersHandler          = decorators.CircuitBreakersHandler
+	RouteStatsMiddleware            = decorators.RouteStatsMiddleware
+	StatsHandler                    = decorators.StatsHandler
+	CollectStats                    = decorators.CollectStats
+	ProxyManagerStats               = decorators.ProxyManagerStats
+	RateLimitDecisionStats          = decorators.RateLimitDecisionStats
+	CreateDeprecatedMiddleware      = decorators.CreateDeprecatedMiddleware
+	DeprecatedMiddleware            = decorators.DeprecatedMiddleware
+	CreateTransactionalMiddleware   = decorators.CreateTransactionalMiddleware
+	TransactionalMiddleware         = decorators.TransactionalMiddleware
+	CreateCompressMiddleware        = decorators.CreateCompressMiddleware
+	CompressMiddleware              = decorators.CompressMiddleware
+	CreateLogMiddleware             = decorators.CreateLogMiddleware
+	LogMiddleware                   = decorators.LogMiddleware
+	CreateEnvelopeMiddleware        = decorators.CreateEnvelopeMiddleware
+	EnvelopeMiddleware              = decorators.EnvelopeMiddleware
+	CacheWith                       = decorators.CacheWith
+	RateLimitWith                   = decorators.RateLimitWith
+	MaxResponseSizeMiddleware       = decorators.MaxResponseSizeMiddleware
+	MaxBodySizeMiddleware           = decorators.MaxBodySizeMiddleware
+	DefaultMaxBodySizeMiddleware    = decorators.DefaultMaxBodySizeMiddleware
+	SlowThresholdMiddleware         = decorators.SlowThresholdMiddleware
+	RequireHeaderMiddleware         = decorators.RequireHeaderMiddleware
+	FileParamMiddleware             = decorators.FileParamMiddleware
+	FieldACLMiddleware              = decorators.FieldACLMiddleware
+	PathParamsMiddleware            = decorators.PathParamsMiddleware
+	AccessLogMiddleware             = decorators.AccessLogMiddleware
+	NewAccessLogger                 = decorators.NewAccessLogger
+
+	// Route identity of logs and traces
+	RouteIdentityMiddleware  = decorators.RouteIdentityMiddleware
+	GetRouteIdentity         = decorators.GetRouteIdentity
+	RouteIdentityFromContext = decorators.RouteIdentityFromContext
+	RequestLogger            = decorators.RequestLogger
 
 	// Funções de segurança
 	SecureInternalEndpoints = decorators.SecureInternalEndpoints
@@ -45,23 +112,396 @@ var (
 	// Funções de geração
 	GenerateInitFile = decorators.GenerateInitFile
 
+	// Application configuration sections of .deco.yaml
+	BindConfig = decorators.BindConfig
+
 	// Funções de documentação
 	DocsHandler            = decorators.DocsHandler
+	DocsPageHandler        = decorators.DocsPageHandler
+	LoadDocsTranslations   = decorators.LoadDocsTranslations
 	DocsJSONHandler        = decorators.DocsJSONHandler
 	OpenAPIJSONHandler     = decorators.OpenAPIJSONHandler
 	OpenAPIYAMLHandler     = decorators.OpenAPIYAMLHandler
+	OpenAPI31Handler       = decorators.OpenAPI31Handler
+	OpenAPIVersionHandler  = decorators.OpenAPIVersionHandler
+	GenerateOpenAPI31Spec  = decorators.GenerateOpenAPI31Spec
+	ConvertOpenAPI31       = decorators.ConvertOpenAPI31
 	SwaggerUIHandler       = decorators.SwaggerUIHandler
 	SwaggerRedirectHandler = decorators.SwaggerRedirectHandler
+	MiddlewareChainHandler = decorators.MiddlewareChainHandler
 
 	// WebSocket functions
 	RegisterWebSocketHandler         = decorators.RegisterWebSocketHandler
 	RegisterDefaultWebSocketHandlers = decorators.RegisterDefaultWebSocketHandlers
 	GetWebSocketHub                  = decorators.GetWebSocketHub
+	CloseWebSocketHub                = decorators.CloseWebSocketHub
 	WebSocketHandlerWrapper          = decorators.WebSocketHandlerWrapper
+	ConfigureWebSocket               = decorators.ConfigureWebSocket
+	RegisterWebSocketBackplane       = decorators.RegisterWebSocketBackplane
+	NewRedisWebSocketBackplane       = decorators.NewRedisWebSocketBackplane
+
+	// Server-Sent Events functions
+	NewSSEBroker     = decorators.NewSSEBroker
+	GetSSEBroker     = decorators.GetSSEBroker
+	SSEStream        = decorators.SSEStream
+	SSEStreamChannel = decorators.SSEStreamChannel
+	SSEMiddleware    = decorators.SSEMiddleware
+
+	// Body capture and redaction
+	BodyCaptureMiddleware = decorators.BodyCaptureMiddleware
+	CaptureBodies         = decorators.CaptureBodies
+	GetBodyCapture        = decorators.GetBodyCapture
+	ConfigureBodyCapture  = decorators.ConfigureBodyCapture
+	NewRedactor           = decorators.NewRedactor
+
+	// Privacy policy
+	ConfigurePrivacy = decorators.ConfigurePrivacy
+	GetPIIPolicy     = decorators.GetPIIPolicy
+	NewPIIPolicy     = decorators.NewPIIPolicy
+
+	// JWT verification of @Auth
+	ConfigureAuth   = decorators.ConfigureAuth
+	GetJWTVerifier  = decorators.GetJWTVerifier
+	NewJWTVerifier  = decorators.NewJWTVerifier
+	JWTClaimsFrom   = decorators.JWTClaimsFrom
+	ErrInvalidToken = decorators.ErrInvalidToken
+
+	// Auth decision cache and revocation
+	ClearAuthCache    = decorators.ClearAuthCache
+	UseRevocationList = decorators.UseRevocationList
+	GetRevocationList = decorators.GetRevocationList
+
+	// Cache key hardening
+	ConfigureCacheKeys = decorators.ConfigureCacheKeys
+	ConfigureCacheETag = decorators.ConfigureCacheETag
+
+	// Redis of the cache, rate limiting and @Dedupe middlewares
+	ConfigureRedis = decorators.ConfigureRedis
+	GetRedisConfig = decorators.GetRedisConfig
+
+	// Rate limiting shared across replicas
+	ConfigureRateLimit      = decorators.ConfigureRateLimit
+	NewRedisRateLimiterWith = decorators.NewRedisRateLimiterWith
+
+	// Token revocation and issuance
+	ConfigureRevocation     = decorators.ConfigureRevocation
+	NewMemoryRevocationList = decorators.NewMemoryRevocationList
+	NewRedisRevocationList  = decorators.NewRedisRevocationList
+	RevokeToken             = decorators.RevokeToken
+	RevokeSubject           = decorators.RevokeSubject
+	RevokeRequestToken      = decorators.RevokeRequestToken
+	RevokeHandler           = decorators.RevokeHandler
+	TokenID                 = decorators.TokenID
+	ErrRevocationDisabled   = decorators.ErrRevocationDisabled
+	NewTokenIssuer          = decorators.NewTokenIssuer
+	GetTokenIssuer          = decorators.GetTokenIssuer
+	IssueTokens             = decorators.IssueTokens
+	RefreshTokens           = decorators.RefreshTokens
+
+	// Data-subject requests
+	RegisterPrivacyRoutes  = decorators.RegisterPrivacyRoutes
+	RegisterDataDomain     = decorators.RegisterDataDomain
+	StartPrivacyJob        = decorators.StartPrivacyJob
+	GetPrivacyJob          = decorators.GetPrivacyJob
+	SetPrivacyAuditHandler = decorators.SetPrivacyAuditHandler
+
+	// Benchmarks (deco bench)
+	RunBenchmarks         = decorators.RunBenchmarks
+	DefaultBenchScenarios = decorators.DefaultBenchScenarios
+	CheckBenchBudgets     = decorators.CheckBenchBudgets
+
+	// Deployment changelog
+	RecordAPIDeployment = decorators.RecordAPIDeployment
+	ChangelogHandler    = decorators.ChangelogHandler
+
+	// gRPC (@GRPC)
+	RegisterGRPCDescriptor = decorators.RegisterGRPCDescriptor
+	NewGRPCServer          = decorators.NewGRPCServer
+	StartGRPCServer        = decorators.StartGRPCServer
+	StopGRPCServer         = decorators.StopGRPCServer
+	BuildGRPCDescriptor    = decorators.BuildGRPCDescriptor
+	RenderProto            = decorators.RenderProto
+
+	// Profiling functions
+	RegisterProfilingRoutes = decorators.RegisterProfilingRoutes
+	RegisterProfileExporter = decorators.RegisterProfileExporter
+	StartProfiling          = decorators.StartProfiling
+	StopProfiling           = decorators.StopProfiling
+
+	// Transactional outbox
+	NewOutbox            = decorators.NewOutbox
+	NewSQLOutboxStore    = decorators.NewSQLOutboxStore
+	NewMemoryOutboxStore = decorators.NewMemoryOutboxStore
+
+	// Message consumers (@Subscribe)
+	RegisterSubscription = decorators.RegisterSubscription
+	GetSubscriptions     = decorators.GetSubscriptions
+	UseSubscriber        = decorators.UseSubscriber
+	StartSubscriptions   = decorators.StartSubscriptions
+	StopSubscriptions    = decorators.StopSubscriptions
+
+	// Transactions (@Transactional)
+	RegisterTxManager = decorators.RegisterTxManager
+	NewSQLTxManager   = decorators.NewSQLTxManager
+	GetTx             = decorators.GetTx
+	TxFromContext     = decorators.TxFromContext
+	SQLTx             = decorators.SQLTx
+
+	// Response compression (@Compress)
+	RegisterCompressor   = decorators.RegisterCompressor
+	ConfigureCompression = decorators.ConfigureCompression
+
+	// API versioning (@Version)
+	ConfigureVersioning        = decorators.ConfigureVersioning
+	APIVersion                 = decorators.APIVersion
+	APIVersions                = decorators.APIVersions
+	GenerateVersionOpenAPISpec = decorators.GenerateVersionOpenAPISpec
+
+	// JSON engine (serialization.engine)
+	RegisterJSONEngine     = decorators.RegisterJSONEngine
+	ConfigureSerialization = decorators.ConfigureSerialization
+	RenderJSON             = decorators.RenderJSON
+
+	// Structured request logs (@Log)
+	ConfigureLogging    = decorators.ConfigureLogging
+	SetStructuredLogger = decorators.SetStructuredLogger
+	SlogLogger          = decorators.SlogLogger
+
+	// Response envelope (@Envelope)
+	ConfigureEnvelope = decorators.ConfigureEnvelope
+	SetEnvelopeMeta   = decorators.SetEnvelopeMeta
+
+	// Sagas
+	NewSaga      = decorators.NewSaga
+	RegisterSaga = decorators.RegisterSaga
+	GetSaga      = decorators.GetSaga
+
+	// Cache and rate-limit administration
+	RegisterAdminRoutes = decorators.RegisterAdminRoutes
+
+	// CloudEvents
+	ConfigureEvents  = decorators.ConfigureEvents
+	UseEventSink     = decorators.UseEventSink
+	StopEvents       = decorators.StopEvents
+	EmitEvent        = decorators.EmitEvent
+	NewHTTPEventSink = decorators.NewHTTPEventSink
+
+	// HTTP scenarios (deco test)
+	LoadScenarioFile         = decorators.LoadScenarioFile
+	NewHandlerScenarioRunner = decorators.NewHandlerScenarioRunner
+	NewHTTPScenarioRunner    = decorators.NewHTTPScenarioRunner
+
+	// Load testing from the spec (deco load)
+	LoadTargets             = decorators.LoadTargets
+	RunLoadTest             = decorators.RunLoadTest
+	NewSpecPayloadGenerator = decorators.NewSpecPayloadGenerator
+
+	// Breaking changes between contracts (deco openapi diff)
+	CompareOpenAPISpecs = decorators.CompareOpenAPISpecs
+
+	// Mock server of the contract (deco mock)
+	NewMockServer = decorators.NewMockServer
+
+	// Telemetry exporters
+	ShutdownTelemetry = decorators.ShutdownTelemetry
+
+	// Application telemetry events
+	Event          = decorators.Event
+	SetEventLogger = decorators.SetEventLogger
+
+	// Context propagation
+	InjectContext         = decorators.InjectContext
+	ExtractContext        = decorators.ExtractContext
+	SetBaggageAllowlist   = decorators.SetBaggageAllowlist
+	PropagationMiddleware = decorators.PropagationMiddleware
+	PropagatingTransport  = decorators.PropagatingTransport
+	NewHTTPClient         = decorators.NewHTTPClient
+
+	// Response conformance checking
+	ResponseConformanceMiddleware = decorators.ResponseConformanceMiddleware
+	GetConformanceReports         = decorators.GetConformanceReports
+	ConformanceReportsHandler     = decorators.ConformanceReportsHandler
+
+	// Traffic learning (dev.learn, deco learn)
+	TrafficLearningMiddleware = decorators.TrafficLearningMiddleware
+	LearnReport               = decorators.LearnReport
+	LearnReportHandler        = decorators.LearnReportHandler
+	LearnedAnnotations        = decorators.LearnedAnnotations
+
+	// Route dependency graph (deco graph)
+	BuildRouteGraph = decorators.BuildRouteGraph
+
+	// Configuration file validation (deco config validate) and schema (deco config schema)
+	ValidateConfigFile = decorators.ValidateConfigFile
+	ConfigJSONSchema   = decorators.ConfigJSONSchema
+
+	// Editor support (deco lsp)
+	ValidateSource     = decorators.ValidateSource
+	EditorMarkers      = decorators.EditorMarkers
+	LookupEditorMarker = decorators.LookupEditorMarker
+
+	// Decorator back-filling (deco annotate)
+	AnnotateSource = decorators.AnnotateSource
+	AnnotationDiff = decorators.AnnotationDiff
+
+	// Effective configuration (deco config print)
+	LoadConfigWithOverrides = decorators.LoadConfigWithOverrides
+	EnvConfigOverrides      = decorators.EnvConfigOverrides
+	RenderEffectiveConfig   = decorators.RenderEffectiveConfig
+	RenderConfigFile        = decorators.RenderConfigFile
+
+	// Monorepo workspaces (deco.workspace.yaml, deco generate -all)
+	FindWorkspaceFile   = decorators.FindWorkspaceFile
+	LoadWorkspace       = decorators.LoadWorkspace
+	DiscoverConfigFiles = decorators.DiscoverConfigFiles
+
+	// Secrets and encrypted cache entries
+	UseSecretProvider      = decorators.UseSecretProvider
+	GetSecret              = decorators.GetSecret
+	NewEncryptedCacheStore = decorators.NewEncryptedCacheStore
+
+	// Cache backends
+	RegisterCacheBackend = decorators.RegisterCacheBackend
+
+	// Warm-up and readiness
+	RegisterInitializer = decorators.RegisterInitializer
+	RunInitializers     = decorators.RunInitializers
+	InitStatuses        = decorators.InitStatuses
+	ReadinessHandler    = decorators.ReadinessHandler
+
+	// Typed handlers
+	NewHTTPError = decorators.NewHTTPError
+)
+
+// OpenAPI versions (openapi.spec_version)
+const (
+	OpenAPISpecVersion30 = decorators.OpenAPISpecVersion30
+	OpenAPISpecVersion31 = decorators.OpenAPISpecVersion31
+)
+
+// Initializer statuses (InitStatus.Status)
+const (
+	InitPending = decorators.InitPending
+	InitRunning = decorators.InitRunning
+	InitReady   = decorators.InitReady
+	InitFailed  = decorators.InitFailed
+	InitTimeout = decorators.InitTimeout
+)
+
+// CloudEvents types emitted by the framework
+const (
+	EventEngineStarted        = decorators.EventEngineStarted
+	EventEngineStopping       = decorators.EventEngineStopping
+	EventRouteDisabled        = decorators.EventRouteDisabled
+	EventConfigReloaded       = decorators.EventConfigReloaded
+	EventCircuitBreakerOpened = decorators.EventCircuitBreakerOpened
+)
+
+// CircuitBreakersPath lists the circuit breakers with their state
+const CircuitBreakersPath = decorators.CircuitBreakersPath
+
+// StatsPath consolidated statistics of every subsystem
+const StatsPath = decorators.StatsPath
+
+// LearnDebugPath lists the decorators suggested from the recorded traffic
+const LearnDebugPath = decorators.LearnDebugPath
+
+// Cache key limits: deco_cache_keys_rejected_total reasons and the default maximum length
+const (
+	CacheKeyTooLong          = decorators.CacheKeyTooLong
+	CacheKeyCardinality      = decorators.CacheKeyCardinality
+	DefaultCacheKeyMaxLength = decorators.DefaultCacheKeyMaxLength
+)
+
+// Algorithms of the Redis rate limiter (rate_limit.algorithm)
+const (
+	RateLimitSlidingWindow = decorators.RateLimitSlidingWindow
+	RateLimitTokenBucket   = decorators.RateLimitTokenBucket
+)
+
+// DefaultMaxBodySize request body limit of limits.max_body_size by default
+const DefaultMaxBodySize = decorators.DefaultMaxBodySize
+
+// RevokePath revokes a token or every token of a subject (auth.revocation.endpoint)
+const RevokePath = decorators.RevokePath
+
+// Revocation stores (auth.revocation.store)
+const (
+	RevocationStoreMemory = decorators.RevocationStoreMemory
+	RevocationStoreRedis  = decorators.RevocationStoreRedis
+)
+
+// DefaultTxManager transaction manager of @Transactional without manager=
+const DefaultTxManager = decorators.DefaultTxManager
+
+// Content codings of @Compress (compression.encodings)
+const (
+	CompressionBrotli  = decorators.CompressionBrotli
+	CompressionGzip    = decorators.CompressionGzip
+	CompressionDeflate = decorators.CompressionDeflate
+)
+
+// Versioning strategies (versioning.strategy)
+const (
+	VersioningPath   = decorators.VersioningPath
+	VersioningHeader = decorators.VersioningHeader
+	VersioningQuery  = decorators.VersioningQuery
+)
+
+// JSON engines (serialization.engine); sonic and jsoniter are registered by the application
+const (
+	JSONEngineStdlib   = decorators.JSONEngineStdlib
+	JSONEngineSonic    = decorators.JSONEngineSonic
+	JSONEngineJsoniter = decorators.JSONEngineJsoniter
+)
+
+// DefaultWebSocketBackplaneChannel channel of the WebSocket backplane without websocket.backplane.channel
+const DefaultWebSocketBackplaneChannel = decorators.DefaultWebSocketBackplaneChannel
+
+// UnknownMarkerCode code of the diagnostics of misspelled markers
+const UnknownMarkerCode = decorators.UnknownMarkerCode
+
+// DefaultWorkspaceFile file holding the shared defaults and services of a monorepo
+const DefaultWorkspaceFile = decorators.DefaultWorkspaceFile
+
+// ConfigSchemaURL where the JSON Schema of .deco.yaml is published
+const ConfigSchemaURL = decorators.ConfigSchemaURL
+
+// Marker argument types (MarkerArg.Type)
+const (
+	MarkerArgString   = decorators.MarkerArgString
+	MarkerArgInt      = decorators.MarkerArgInt
+	MarkerArgNumber   = decorators.MarkerArgNumber
+	MarkerArgBool     = decorators.MarkerArgBool
+	MarkerArgDuration = decorators.MarkerArgDuration
+	MarkerArgSize     = decorators.MarkerArgSize
+	MarkerArgList     = decorators.MarkerArgList
+	MarkerArgJSON     = decorators.MarkerArgJSON
 )
 
 // Re-exportar tipos principais
 type (
+	// Engine gin.Engine with lifecycle hooks and graceful shutdown, returned by NewEngine
+	Engine = decorators.Engine
+
+	// LifecycleHook function run by Engine on startup or shutdown
+	LifecycleHook = decorators.LifecycleHook
+
+	// ScenarioFile declarative HTTP scenarios run by deco test
+	ScenarioFile = decorators.ScenarioFile
+
+	// ScenarioReport outcome of the scenarios
+	ScenarioReport = decorators.ScenarioReport
+
+	// LoadOptions load applied by RunLoadTest
+	LoadOptions = decorators.LoadOptions
+
+	// LoadReport latency and errors of a load test, per route
+	LoadReport = decorators.LoadReport
+
+	// SpecChangeReport changes between two contracts and whether they break clients
+	SpecChangeReport = decorators.SpecChangeReport
+
 	// RouteEntry representa uma rota registrada
 	RouteEntry = decorators.RouteEntry
 
@@ -71,12 +511,21 @@ type (
 	// MarkerConfig configuration of a marker personalizado
 	MarkerConfig = decorators.MarkerConfig
 
+	// MarkerArg argumento declarado de um marker, validado na geração
+	MarkerArg = decorators.MarkerArg
+
 	// MarkerInstance instância de um marker encontrado
 	MarkerInstance = decorators.MarkerInstance
 
 	// GenData dados para templates de geração
 	GenData = decorators.GenData
 
+	// ControllerInfo struct whose methods are handlers, declared with @Controller
+	ControllerInfo = decorators.ControllerInfo
+
+	// ControllerInstance controller created by the generated init
+	ControllerInstance = decorators.ControllerInstance
+
 	// MiddlewareInfo information about middlewares
 	MiddlewareInfo = decorators.MiddlewareInfo
 
@@ -94,4 +543,253 @@ type (
 
 	// Security types
 	SecurityConfig = decorators.SecurityConfig
+
+	// MockConfig canned response of @Mock
+	MockConfig = decorators.MockConfig
+
+	// DedupeConfig duplicate delivery detection of @Dedupe
+	DedupeConfig = decorators.DedupeConfig
+
+	// RequireHeaderConfig required header of @RequireHeader
+	RequireHeaderConfig = decorators.RequireHeaderConfig
+
+	// FileParamConfig file upload of @FileParam
+	FileParamConfig = decorators.FileParamConfig
+
+	// DeprecationInfo deprecation of a route declared with @Deprecated
+	DeprecationInfo = decorators.DeprecationInfo
+
+	// PathParamConstraint check of a path parameter, from {id:int} segments and @Param(location="path")
+	PathParamConstraint = decorators.PathParamConstraint
+
+	// Access log types
+	AccessLogConfig = decorators.AccessLogConfig
+	AccessLogEntry  = decorators.AccessLogEntry
+
+	// RouteIdentity route template, group, tags and operationId of a request
+	RouteIdentity = decorators.RouteIdentity
+
+	// Body capture types
+	BodyCapture       = decorators.BodyCapture
+	BodyCaptureConfig = decorators.BodyCaptureConfig
+	Redactor          = decorators.Redactor
+
+	// Profiling types
+	ProfilingConfig = decorators.ProfilingConfig
+	ProfileExporter = decorators.ProfileExporter
+
+	// Outbox types
+	Outbox        = decorators.Outbox
+	OutboxConfig  = decorators.OutboxConfig
+	OutboxEvent   = decorators.OutboxEvent
+	OutboxStore   = decorators.OutboxStore
+	Publisher     = decorators.Publisher
+	PublisherFunc = decorators.PublisherFunc
+	SQLExecutor   = decorators.SQLExecutor
+
+	// Consumer types
+	Message           = decorators.Message
+	MessageHandler    = decorators.MessageHandler
+	Subscriber        = decorators.Subscriber
+	SubscriptionEntry = decorators.SubscriptionEntry
+
+	// Transaction types
+	Tx                  = decorators.Tx
+	TxManager           = decorators.TxManager
+	TxManagerFunc       = decorators.TxManagerFunc
+	TransactionalConfig = decorators.TransactionalConfig
+
+	// Compression types
+	CompressorFactory = decorators.CompressorFactory
+
+	// Serialization types
+	JSONEngine          = decorators.JSONEngine
+	SerializationConfig = decorators.SerializationConfig
+
+	// Structured request log types
+	StructuredLogger     = decorators.StructuredLogger
+	StructuredLoggerFunc = decorators.StructuredLoggerFunc
+	LoggingConfig        = decorators.LoggingConfig
+
+	// Response envelope types
+	EnvelopeConfig = decorators.EnvelopeConfig
+
+	// WebSocket backplane types
+	WebSocketBackplane        = decorators.WebSocketBackplane
+	WebSocketBackplaneConfig  = decorators.WebSocketBackplaneConfig
+	WebSocketBackplaneFactory = decorators.WebSocketBackplaneFactory
+	WebSocketInstanceStats    = decorators.WebSocketInstanceStats
+	RedisWebSocketBackplane   = decorators.RedisWebSocketBackplane
+
+	// Saga types
+	Saga           = decorators.Saga
+	SagaAction     = decorators.SagaAction
+	SagaState      = decorators.SagaState
+	SagaStepConfig = decorators.SagaStepConfig
+
+	// Administration types
+	AdminConfig        = decorators.AdminConfig
+	CacheKeyLister     = decorators.CacheKeyLister
+	RateLimitCounter   = decorators.RateLimitCounter
+	RateLimitInspector = decorators.RateLimitInspector
+
+	// Consolidated statistics types (/decorators/stats)
+	StatsReport        = decorators.StatsReport
+	RouteStats         = decorators.RouteStats
+	CacheStoreStats    = decorators.CacheStoreStats
+	RateLimitStats     = decorators.RateLimitStats
+	ProxyStats         = decorators.ProxyStats
+	ProxyInstanceStats = decorators.ProxyInstanceStats
+
+	// Event types
+	CloudEvent    = decorators.CloudEvent
+	EventSink     = decorators.EventSink
+	EventSinkFunc = decorators.EventSinkFunc
+	EventsConfig  = decorators.EventsConfig
+
+	// Docs page localization types
+	DocsConfig          = decorators.DocsConfig
+	DocsBrandingConfig  = decorators.DocsBrandingConfig
+	DocsEndpointsConfig = decorators.DocsEndpointsConfig
+	DocsTranslations    = decorators.DocsTranslations
+	RouteTranslation    = decorators.RouteTranslation
+
+	// ConformanceReport response not matching its @Response schema
+	ConformanceReport = decorators.ConformanceReport
+
+	// Traffic learning types
+	LearnedRoute  = decorators.LearnedRoute
+	LearnedSchema = decorators.LearnedSchema
+	ObservedShape = decorators.ObservedShape
+
+	// Route graph types
+	RouteGraph     = decorators.RouteGraph
+	RouteGraphNode = decorators.RouteGraphNode
+	RouteGraphEdge = decorators.RouteGraphEdge
+
+	// Configuration file validation types
+	ConfigIssue           = decorators.ConfigIssue
+	ConfigValidationError = decorators.ConfigValidationError
+	ConfigOverride        = decorators.ConfigOverride
+
+	// Secrets types
+	SecretProvider      = decorators.SecretProvider
+	EnvSecretProvider   = decorators.EnvSecretProvider
+	EncryptedCacheStore = decorators.EncryptedCacheStore
+
+	// Folded middleware arguments (generated code)
+	CacheOptions       = decorators.CacheOptions
+	RateLimitOptions   = decorators.RateLimitOptions
+	ResponseSizeConfig = decorators.ResponseSizeConfig
+
+	// Cache backend types
+	CacheConfig         = decorators.CacheConfig
+	CacheStore          = decorators.CacheStore
+	CacheEntry          = decorators.CacheEntry
+	CacheStats          = decorators.CacheStats
+	CacheBackendFactory = decorators.CacheBackendFactory
+	CacheKeyConfig      = decorators.CacheKeyConfig
+	LimitsConfig        = decorators.LimitsConfig
+	CompressionConfig   = decorators.CompressionConfig
+	VersioningConfig    = decorators.VersioningConfig
+
+	// Workspace of a monorepo (deco.workspace.yaml)
+	Workspace = decorators.Workspace
+
+	// Mock server options (deco mock)
+	MockServerOptions = decorators.MockServerOptions
+
+	// Sensitive field types
+	SensitiveConfig = decorators.SensitiveConfig
+	SensitiveAccess = decorators.SensitiveAccess
+
+	// Response field access control types (@FieldACL)
+	FieldACLConfig = decorators.FieldACLConfig
+	FieldACLRule   = decorators.FieldACLRule
+
+	// Auth types
+	AuthConfig  = decorators.AuthConfig
+	JWTConfig   = decorators.JWTConfig
+	JWTVerifier = decorators.JWTVerifier
+	JWTClaims   = decorators.JWTClaims
+
+	// RevocationList tells whether a verified token was revoked
+	RevocationList = decorators.RevocationList
+
+	// Revocation and issuance types
+	TokenRevocationStore = decorators.TokenRevocationStore
+	MemoryRevocationList = decorators.MemoryRevocationList
+	RedisRevocationList  = decorators.RedisRevocationList
+	RevocationConfig     = decorators.RevocationConfig
+	TokenIssuer          = decorators.TokenIssuer
+	TokenIssuerConfig    = decorators.TokenIssuerConfig
+	TokenPair            = decorators.TokenPair
+
+	// Privacy types
+	PrivacyConfig = decorators.PrivacyConfig
+	PIIPolicy     = decorators.PIIPolicy
+	DataDomain    = decorators.DataDomain
+	PrivacyJob    = decorators.PrivacyJob
+	PrivacyAudit  = decorators.PrivacyAudit
+
+	// Bench types
+	BenchConfig   = decorators.BenchConfig
+	BenchScenario = decorators.BenchScenario
+	BenchOptions  = decorators.BenchOptions
+	BenchResult   = decorators.BenchResult
+
+	// Changelog types
+	ChangelogConfig   = decorators.ChangelogConfig
+	APIChangelogEntry = decorators.APIChangelogEntry
+
+	// gRPC types
+	GRPCConfig  = decorators.GRPCConfig
+	GRPCBinding = decorators.GRPCBinding
+
+	// Warm-up types
+	WarmupConfig = decorators.WarmupConfig
+	InitStatus   = decorators.InitStatus
+
+	// Dependency types
+	ProviderBinding = decorators.ProviderBinding
+
+	// Typed handlers
+	HTTPError = decorators.HTTPError
+
+	// EditorMarker marker offered by deco lsp
+	EditorMarker = decorators.EditorMarker
+
+	// HandlerAnnotation decorators proposed by deco annotate
+	HandlerAnnotation = decorators.HandlerAnnotation
+
+	// Server-Sent Events types
+	SSEConfig = decorators.SSEConfig
+	SSEEvent  = decorators.SSEEvent
+	SSEClient = decorators.SSEClient
+	SSEBroker = decorators.SSEBroker
+
+	// CircuitBreakerOptions configuration of @CircuitBreaker
+	CircuitBreakerOptions = decorators.CircuitBreakerOptions
 )
+
+// Funções genéricas não podem ser re-exportadas como variáveis
+
+// Provide makes the dependency built by factory available to the request as name (@Provide)
+func Provide[T any](name string, factory func(c *gin.Context) (T, error), cache bool) gin.HandlerFunc {
+	return decorators.Provide(name, factory, cache)
+}
+
+// Provided returns the @Provide dependency name of the request, building it on first use
+func Provided[T any](c *gin.Context, name string) (T, error) {
+	return decorators.Provided[T](c, name)
+}
+
+// Typed adapts a typed handler, func(c *gin.Context, req Req) (Res, error), to gin
+func Typed[Req, Res any](handler func(c *gin.Context, req Req) (Res, error)) gin.HandlerFunc {
+	return decorators.Typed(handler)
+}
+
+// StreamJSON writes the items as a JSON array one at a time, for large lists
+func StreamJSON[T any](c *gin.Context, status int, items iter.Seq2[T, error]) error {
+	return decorators.StreamJSON(c, status, items)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	deco "github.com/RodolfoBonis/deco"
 	_ "github.com/RodolfoBonis/deco/examples/basic/.deco"
 )
@@ -19,9 +21,10 @@ func main() {
 	}
 
 	// Criar engine com segurança
-	r := deco.DefaultWithSecurity(devSecurity)
+	r := deco.NewEngineWithSecurity(devSecurity)
 
-	if err := r.Run(":8080"); err != nil {
+	// Encerra com SIGINT/SIGTERM aguardando as requests em andamento
+	if err := r.RunWithGracefulShutdown(":8080", 30*time.Second); err != nil {
 		panic(err)
 	}
 }
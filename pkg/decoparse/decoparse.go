@@ -0,0 +1,363 @@
+// Package decoparse exposes the deco parser to other tools (custom gateways, docs portals, linters)
+// without shelling out to the CLI. Load parses the annotated handlers of one or more directories into
+// a plain model of routes, markers and schemas that only changes in backward compatible ways:
+//
+//	result, err := decoparse.Load("./handlers", "./internal/api/...")
+//	if err != nil {
+//		return err
+//	}
+//	for _, route := range result.Routes {
+//		fmt.Println(route.Method, route.Path, route.Handler)
+//	}
+//
+// Markers of plugin packages blank-imported by the handlers are loaded as they are by deco generate.
+package decoparse
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// Result routes and schemas of the loaded directories
+type Result struct {
+	Routes  []Route  `json:"routes"`
+	Schemas []Schema `json:"schemas"`
+}
+
+// Route handler annotated with @Route
+type Route struct {
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`    // gin path, typed segments already converted ("/users/:id")
+	Handler     string       `json:"handler"` // function name
+	Package     string       `json:"package"`
+	File        string       `json:"file"` // path of the source file, as found from the pattern
+	Line        int          `json:"line"` // line of @Route
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Group       *Group       `json:"group,omitempty"`
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+	Versions    []string     `json:"versions,omitempty"`    // @Version, empty for unversioned routes
+	Markers     []Marker     `json:"markers"`               // every marker of the handler comment, in source order
+	Middlewares []Middleware `json:"middlewares,omitempty"` // middleware chain, in execution order
+	Parameters  []Parameter  `json:"parameters,omitempty"`
+	Responses   []Response   `json:"responses,omitempty"`
+}
+
+// Marker annotation of a handler comment, e.g. @Cache(ttl=5m)
+type Marker struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"` // as written, "ttl=5m"
+	Raw  string   `json:"raw"`
+	Line int      `json:"line"`
+}
+
+// Middleware entry of the chain of a route
+type Middleware struct {
+	Name        string                 `json:"name"`
+	Args        map[string]interface{} `json:"args,omitempty"`
+	Description string                 `json:"description,omitempty"`
+}
+
+// Parameter request parameter declared by @Param, @RequireHeader or a typed path segment
+type Parameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	In          string `json:"in"` // query, path, header or body
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+	Example     string `json:"example,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+}
+
+// Response response declared by @Response
+type Response struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"` // schema name
+	Example     string `json:"example,omitempty"`
+}
+
+// Group group of a route, from @Group
+type Group struct {
+	Name        string `json:"name"`
+	Prefix      string `json:"prefix,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Deprecation deprecation of a route, from @Deprecated
+type Deprecation struct {
+	Message string `json:"message,omitempty"`
+	Sunset  string `json:"sunset,omitempty"`
+	Link    string `json:"link,omitempty"`
+}
+
+// Schema struct annotated with @Schema
+type Schema struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Package     string     `json:"package"`
+	File        string     `json:"file"`       // path of the source file, as found from the pattern
+	Properties  []Property `json:"properties"` // sorted by name
+	Required    []string   `json:"required,omitempty"`
+}
+
+// Property field of a schema, named as in JSON
+type Property struct {
+	Name        string    `json:"name"`
+	Type        string    `json:"type,omitempty"` // OpenAPI type
+	Format      string    `json:"format,omitempty"`
+	GoType      string    `json:"goType,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Required    bool      `json:"required,omitempty"`
+	Enum        []string  `json:"enum,omitempty"`
+	Ref         string    `json:"ref,omitempty"` // referenced schema
+	Items       *Property `json:"items,omitempty"`
+	Deprecated  bool      `json:"deprecated,omitempty"`
+}
+
+// Diagnostic invalid annotation found while loading
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	Code    string `json:"code"` // e.g. INVALID_ARGUMENTS
+}
+
+// String formats the diagnostic as "file:line - message"
+func (d Diagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d - %s", d.File, d.Line, d.Message)
+	}
+	return fmt.Sprintf("%s - %s", d.File, d.Message)
+}
+
+// Error invalid annotations of the loaded directories; Load returns it with the routes that parsed
+type Error struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Diagnostics))
+	for _, diagnostic := range e.Diagnostics {
+		messages = append(messages, diagnostic.String())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Load parses the directories matched by patterns, "." when there is none. A pattern is a directory,
+// or "dir/..." for the directory and its subdirectories, skipping testdata, vendor and names starting
+// with "." or "_" as the go tool does. On invalid annotations Load returns the routes that parsed and
+// an *Error listing them.
+func Load(patterns ...string) (*Result, error) {
+	dirs, err := expandPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Routes: []Route{}, Schemas: []Schema{}}
+	var invalid Error
+	for _, dir := range dirs {
+		routes, schemas, err := decorators.ParseDirectoryWithSchemas(dir)
+		var validation *decorators.MultipleValidationError
+		switch {
+		case errors.As(err, &validation):
+			for _, e := range validation.Errors {
+				invalid.Diagnostics = append(invalid.Diagnostics, Diagnostic{File: filepath.Join(dir, e.File), Line: e.Line, Message: e.Message, Code: e.Code})
+			}
+		case err != nil:
+			return nil, fmt.Errorf("decoparse: %s: %w", dir, err)
+		}
+
+		for _, route := range routes {
+			result.Routes = append(result.Routes, newRoute(route))
+		}
+		for _, schema := range schemas {
+			result.Schemas = append(result.Schemas, newSchema(schema))
+		}
+	}
+
+	if len(invalid.Diagnostics) > 0 {
+		return result, &invalid
+	}
+	return result, nil
+}
+
+// Marker returns the first marker of the route named name, without the "@"
+func (r *Route) Marker(name string) (Marker, bool) {
+	for _, marker := range r.Markers {
+		if marker.Name == name {
+			return marker, true
+		}
+	}
+	return Marker{}, false
+}
+
+// Arg returns the value of the key=value argument of the marker, unquoted
+func (m Marker) Arg(key string) (string, bool) {
+	for _, arg := range m.Args {
+		if name, value, found := strings.Cut(arg, "="); found && strings.TrimSpace(name) == key {
+			return decorators.MarkerValue(value), true
+		}
+	}
+	return "", false
+}
+
+// expandPatterns directories matched by the patterns, in order and without duplicates
+func expandPatterns(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(filepath.ToSlash(pattern), "/...")
+		if pattern == "..." {
+			root, recursive = ".", true
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("decoparse: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("decoparse: %s is not a directory", root)
+		}
+		if !recursive {
+			add(root)
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			name := entry.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			if hasGoFiles(path) {
+				add(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("decoparse: %w", err)
+		}
+	}
+	return dirs, nil
+}
+
+// hasGoFiles reports whether dir contains a .go file
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+			return true
+		}
+	}
+	return false
+}
+
+// newRoute converts the parser metadata of a route
+func newRoute(meta *decorators.RouteMeta) Route {
+	route := Route{
+		Method:      meta.Method,
+		Path:        meta.Path,
+		Handler:     meta.FuncName,
+		Package:     meta.PackageName,
+		File:        meta.FilePath,
+		Line:        meta.Line,
+		Summary:     meta.Summary,
+		Description: meta.Description,
+		Tags:        meta.Tags,
+		Versions:    meta.Versions,
+		Markers:     make([]Marker, 0, len(meta.Markers)),
+	}
+	if meta.Group != nil {
+		route.Group = &Group{Name: meta.Group.Name, Prefix: meta.Group.Prefix, Description: meta.Group.Description}
+	}
+	if meta.Deprecation != nil {
+		route.Deprecation = &Deprecation{Message: meta.Deprecation.Message, Sunset: meta.Deprecation.Sunset, Link: meta.Deprecation.Link}
+	}
+	for _, marker := range meta.Markers {
+		route.Markers = append(route.Markers, Marker{Name: marker.Name, Args: marker.Args, Raw: marker.Raw, Line: marker.Line})
+	}
+	for _, middleware := range meta.MiddlewareInfo {
+		route.Middlewares = append(route.Middlewares, Middleware{Name: middleware.Name, Args: middleware.Args, Description: middleware.Description})
+	}
+	for _, param := range meta.Parameters {
+		route.Parameters = append(route.Parameters, Parameter{
+			Name:        param.Name,
+			Type:        param.Type,
+			In:          param.Location,
+			Required:    param.Required,
+			Description: param.Description,
+			Example:     param.Example,
+			Pattern:     param.Pattern,
+		})
+	}
+	for _, response := range meta.Responses {
+		route.Responses = append(route.Responses, Response{Code: response.Code, Description: response.Description, Type: response.Type, Example: response.Example})
+	}
+	return route
+}
+
+// newSchema converts a parsed schema
+func newSchema(info *decorators.SchemaInfo) Schema {
+	schema := Schema{
+		Name:        info.Name,
+		Description: info.Description,
+		Version:     info.Version,
+		Package:     info.PackageName,
+		File:        info.FileName,
+		Properties:  make([]Property, 0, len(info.Properties)),
+		Required:    info.Required,
+	}
+	for _, property := range info.Properties {
+		schema.Properties = append(schema.Properties, newProperty(property))
+	}
+	sort.Slice(schema.Properties, func(i, j int) bool { return schema.Properties[i].Name < schema.Properties[j].Name })
+	return schema
+}
+
+// newProperty converts a schema property
+func newProperty(info *decorators.PropertyInfo) Property {
+	property := Property{
+		Name:        info.Name,
+		Type:        info.Type,
+		Format:      info.Format,
+		GoType:      info.GoType,
+		Description: info.Description,
+		Required:    info.Required,
+		Enum:        info.Enum,
+		Ref:         info.Ref,
+		Deprecated:  info.Deprecated,
+	}
+	if info.Items != nil {
+		items := newProperty(info.Items)
+		property.Items = &items
+	}
+	return property
+}
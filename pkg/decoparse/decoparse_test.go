@@ -0,0 +1,158 @@
+package decoparse
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usersSource = `package users
+
+import "github.com/gin-gonic/gin"
+
+// User account
+// @Schema()
+type User struct {
+	ID    int    ` + "`json:\"id\" validate:\"required\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+
+// GetUser returns a user
+// @Route("GET", "/users/{id:int}")
+// @Summary(Get user)
+// @Tag("users")
+// @Param(name="id", location="path", description="User ID")
+// @Cache(ttl=5m)
+// @Auth(role=admin)
+// @Response(code=200, description="User", type="User")
+// @Deprecated("use /v2/users/{id}", sunset=2027-01-31)
+func GetUser(c *gin.Context) {}
+`
+
+const ordersSource = `package orders
+
+import "github.com/gin-gonic/gin"
+
+// ListOrders lists orders
+// @Route("GET", "/orders")
+func ListOrders(c *gin.Context) {}
+`
+
+// writeTree writes files, keyed by path relative to the returned root
+func writeTree(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	root := writeTree(t, map[string]string{"users/users.go": usersSource})
+
+	result, err := Load(filepath.Join(root, "users"))
+	require.NoError(t, err)
+	require.Len(t, result.Routes, 1)
+
+	route := result.Routes[0]
+	assert.Equal(t, "GET", route.Method)
+	assert.Equal(t, "/users/:id", route.Path)
+	assert.Equal(t, "GetUser", route.Handler)
+	assert.Equal(t, "users", route.Package)
+	assert.Equal(t, filepath.Join(root, "users", "users.go"), route.File)
+	assert.Equal(t, 13, route.Line)
+	assert.Equal(t, "Get user", route.Summary)
+	assert.Equal(t, []string{"users"}, route.Tags)
+	assert.Equal(t, &Deprecation{Message: "use /v2/users/{id}", Sunset: "2027-01-31", Link: "/v2/users/{id}"}, route.Deprecation)
+	assert.Equal(t, []Parameter{{Name: "id", Type: "int64", In: "path", Required: true, Description: "User ID"}}, route.Parameters)
+	assert.Equal(t, []Response{{Code: "200", Description: "User", Type: "User"}}, route.Responses)
+
+	cache, ok := route.Marker("Cache")
+	require.True(t, ok)
+	ttl, ok := cache.Arg("ttl")
+	assert.True(t, ok)
+	assert.Equal(t, "5m", ttl)
+	assert.Equal(t, 17, cache.Line)
+	_, ok = route.Marker("RateLimit")
+	assert.False(t, ok)
+
+	var middlewares []string
+	for _, middleware := range route.Middlewares {
+		middlewares = append(middlewares, middleware.Name)
+	}
+	assert.Equal(t, []string{"PathParams", "Cache", "Auth", "Deprecated"}, middlewares, "middlewares as declared, before generation ordering")
+
+	require.Len(t, result.Schemas, 1)
+	schema := result.Schemas[0]
+	assert.Equal(t, "User", schema.Name)
+	assert.Equal(t, route.File, schema.File)
+	require.Len(t, schema.Properties, 2)
+	assert.Equal(t, "email", schema.Properties[0].Name)
+	assert.Equal(t, "id", schema.Properties[1].Name)
+	assert.Equal(t, "integer", schema.Properties[1].Type)
+	assert.True(t, schema.Properties[1].Required)
+}
+
+func TestLoad_Patterns(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"api/users/users.go":        usersSource,
+		"api/orders/orders.go":      ordersSource,
+		"api/testdata/fixture.go":   ordersSource,
+		"api/.cache/cached.go":      ordersSource,
+		"api/orders/README.md":      "not go",
+		"api/docs/empty/readme.txt": "no go files",
+	})
+
+	result, err := Load(filepath.Join(root, "api") + "/...")
+	require.NoError(t, err)
+	var handlers []string
+	for _, route := range result.Routes {
+		handlers = append(handlers, route.Handler)
+	}
+	assert.Equal(t, []string{"ListOrders", "GetUser"}, handlers, "subdirectories in lexical order, testdata and hidden skipped")
+
+	// Repeated directories are parsed once
+	result, err = Load(filepath.Join(root, "api", "orders"), filepath.Join(root, "api", "orders")+"/...")
+	require.NoError(t, err)
+	assert.Len(t, result.Routes, 1)
+
+	_, err = Load(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+	_, err = Load(filepath.Join(root, "api", "orders", "orders.go"))
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+func TestLoad_InvalidAnnotations(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"orders/orders.go": ordersSource,
+		"broken/broken.go": `package broken
+
+import "github.com/gin-gonic/gin"
+
+// Broken has an invalid cache duration
+// @Route("GET", "/broken")
+// @Cache(ttl=5mins)
+func Broken(c *gin.Context) {}
+`,
+	})
+
+	result, err := Load(root + "/...")
+	var invalid *Error
+	require.True(t, errors.As(err, &invalid))
+	require.Len(t, invalid.Diagnostics, 1)
+	diagnostic := invalid.Diagnostics[0]
+	assert.Equal(t, filepath.Join(root, "broken", "broken.go"), diagnostic.File)
+	assert.Equal(t, 7, diagnostic.Line)
+	assert.Equal(t, "INVALID_ARGUMENTS", diagnostic.Code)
+	assert.Contains(t, err.Error(), "broken.go:7 - ")
+
+	require.NotNil(t, result, "valid directories are still returned")
+	require.Len(t, result.Routes, 1)
+	assert.Equal(t, "ListOrders", result.Routes[0].Handler)
+}
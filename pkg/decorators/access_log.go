@@ -0,0 +1,396 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Access log formats
+const (
+	AccessLogFormatCombined = "combined" // Apache combined log format
+	AccessLogFormatJSON     = "json"     // one JSON object per line
+	AccessLogFormatTemplate = "template" // custom Go template over AccessLogEntry
+)
+
+// Access log outputs
+const (
+	AccessLogOutputStdout = "stdout"
+	AccessLogOutputStderr = "stderr"
+	AccessLogOutputFile   = "file"
+	AccessLogOutputSyslog = "syslog"
+)
+
+// noAccessLogKey context key set by @NoAccessLog
+const noAccessLogKey = "deco.noAccessLog"
+
+// AccessLogEntry data of one access log line, also the dot of custom templates
+type AccessLogEntry struct {
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr"`
+	User       string    `json:"user,omitempty"`
+	Method     string    `json:"method"`
+	URI        string    `json:"uri"`
+	Route      string    `json:"route,omitempty"`
+	Operation  string    `json:"operation_id,omitempty"`
+	Proto      string    `json:"proto"`
+	Status     int       `json:"status"`
+	Size       int       `json:"size"`
+	Referer    string    `json:"referer,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	DurationMS float64   `json:"duration_ms"`
+	RequestID  string    `json:"request_id,omitempty"`
+}
+
+// AccessLogger writes one line per request in the configured format
+type AccessLogger struct {
+	config   AccessLogConfig
+	tmpl     *template.Template
+	output   io.Writer
+	closer   io.Closer
+	outputMu sync.Mutex
+}
+
+// validate checks the access_log section of the configuration
+func (a AccessLogConfig) validate() error {
+	switch a.Format {
+	case "", AccessLogFormatCombined, AccessLogFormatJSON:
+	case AccessLogFormatTemplate:
+		if a.Template == "" {
+			return fmt.Errorf("access_log.template is required for format 'template'")
+		}
+		if _, err := template.New("access_log").Parse(a.Template); err != nil {
+			return fmt.Errorf("invalid access_log.template: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid access_log.format '%s' (valid: combined, json, template)", a.Format)
+	}
+
+	switch a.Output {
+	case "", AccessLogOutputStdout, AccessLogOutputStderr, AccessLogOutputSyslog:
+	case AccessLogOutputFile:
+		if a.File == "" {
+			return fmt.Errorf("access_log.file is required for output 'file'")
+		}
+	default:
+		return fmt.Errorf("invalid access_log.output '%s' (valid: stdout, stderr, file, syslog)", a.Output)
+	}
+
+	if a.MaxSizeMB < 0 || a.MaxBackups < 0 || a.MaxAgeDays < 0 {
+		return fmt.Errorf("access_log rotation options must not be negative")
+	}
+
+	return nil
+}
+
+// NewAccessLogger creates an access logger, opening its output
+func NewAccessLogger(config AccessLogConfig) (*AccessLogger, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	logger := &AccessLogger{config: config}
+	if config.Format == AccessLogFormatTemplate {
+		logger.tmpl = template.Must(template.New("access_log").Parse(config.Template))
+	}
+
+	switch config.Output {
+	case AccessLogOutputStderr:
+		logger.output = os.Stderr
+	case AccessLogOutputFile:
+		writer, err := newRotatingFileWriter(config.File, config.MaxSizeMB, config.MaxBackups, config.MaxAgeDays)
+		if err != nil {
+			return nil, err
+		}
+		logger.output, logger.closer = writer, writer
+	case AccessLogOutputSyslog:
+		writer, err := newSyslogWriter(config.SyslogNetwork, config.SyslogAddress, config.SyslogTag)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to syslog: %v", err)
+		}
+		logger.output, logger.closer = writer, writer
+	default:
+		logger.output = os.Stdout
+	}
+
+	return logger, nil
+}
+
+// NewAccessLoggerWithWriter creates an access logger writing to w
+func NewAccessLoggerWithWriter(config AccessLogConfig, w io.Writer) (*AccessLogger, error) {
+	config.Output = AccessLogOutputStdout
+	logger, err := NewAccessLogger(config)
+	if err != nil {
+		return nil, err
+	}
+	logger.output = w
+	return logger, nil
+}
+
+// Middleware logs every request that was not opted out with @NoAccessLog
+func (l *AccessLogger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		if c.GetBool(noAccessLogKey) {
+			return
+		}
+
+		entry := AccessLogEntry{
+			Time:       start,
+			RemoteAddr: c.ClientIP(),
+			Method:     c.Request.Method,
+			URI:        c.Request.RequestURI,
+			Route:      c.FullPath(),
+			Proto:      c.Request.Proto,
+			Status:     c.Writer.Status(),
+			Size:       c.Writer.Size(),
+			Referer:    c.Request.Referer(),
+			UserAgent:  c.Request.UserAgent(),
+			DurationMS: durationMS(time.Since(start)),
+			RequestID:  c.GetHeader("X-Request-ID"),
+		}
+		if entry.URI == "" {
+			entry.URI = c.Request.URL.RequestURI()
+		}
+		if identity, ok := GetRouteIdentity(c); ok {
+			entry.Operation = identity.OperationID
+		}
+		if entry.Size < 0 {
+			entry.Size = 0
+		}
+		if user, _, ok := c.Request.BasicAuth(); ok {
+			entry.User = user
+		}
+		if policy := GetPIIPolicy(); policy != nil {
+			entry.URI = policy.ScrubURL(entry.URI)
+			entry.Referer = policy.ScrubURL(entry.Referer)
+			entry.User = policy.ScrubField("user", entry.User)
+		}
+
+		l.Log(entry)
+	}
+}
+
+// Log writes a single entry
+func (l *AccessLogger) Log(entry AccessLogEntry) {
+	line, err := l.format(entry)
+	if err != nil {
+		LogVerbose("⚠️  Error formatting access log entry: %v", err)
+		return
+	}
+
+	l.outputMu.Lock()
+	defer l.outputMu.Unlock()
+	if _, err := l.output.Write(line); err != nil {
+		LogVerbose("⚠️  Error writing access log: %v", err)
+	}
+}
+
+// format renders an entry as a newline-terminated line
+func (l *AccessLogger) format(entry AccessLogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch l.config.Format {
+	case AccessLogFormatJSON:
+		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case AccessLogFormatTemplate:
+		if err := l.tmpl.Execute(&buf, entry); err != nil {
+			return nil, err
+		}
+	default:
+		buf.WriteString(combinedLogLine(entry))
+	}
+
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// combinedLogLine renders the Apache combined log format
+func combinedLogLine(entry AccessLogEntry) string {
+	size := "-"
+	if entry.Size > 0 {
+		size = fmt.Sprintf("%d", entry.Size)
+	}
+
+	return fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s "%s" "%s"`,
+		dashIfEmpty(entry.RemoteAddr),
+		dashIfEmpty(entry.User),
+		entry.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		entry.Method, entry.URI, entry.Proto,
+		entry.Status,
+		size,
+		dashIfEmpty(entry.Referer),
+		dashIfEmpty(entry.UserAgent),
+	)
+}
+
+// dashIfEmpty returns "-" for empty combined log fields
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Close releases the output (files and syslog connections)
+func (l *AccessLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// AccessLogMiddleware creates access log middleware; configuration errors disable logging
+func AccessLogMiddleware(config AccessLogConfig) gin.HandlerFunc {
+	logger, err := NewAccessLogger(config)
+	if err != nil {
+		LogSilent("⚠️  Access log disabled: %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return logger.Middleware()
+}
+
+// createNoAccessLogMiddleware opts a route out of the access log
+func createNoAccessLogMiddleware(args []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(noAccessLogKey, true)
+		c.Next()
+	}
+}
+
+// rotatingFileWriter appends to a file, rotating it by size and pruning old backups
+type rotatingFileWriter struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+	file       *os.File
+	size       int64
+}
+
+// newRotatingFileWriter opens path for appending
+func newRotatingFileWriter(path string, maxSizeMB, maxBackups, maxAgeDays int) (*rotatingFileWriter, error) {
+	writer := &rotatingFileWriter{
+		path:       path,
+		maxSize:    int64(maxSizeMB) << 20,
+		maxBackups: maxBackups,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+	}
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// open opens the current log file
+func (w *rotatingFileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return fmt.Errorf("error creating access log directory: %v", err)
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening access log file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening access log file: %v", err)
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when it would exceed the size limit
+func (w *rotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, fmt.Errorf("access log file is closed")
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file with a timestamp suffix and starts a new one
+func (w *rotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	backup := w.path + "." + time.Now().Format("20060102-150405.000000")
+	if err := os.Rename(w.path, backup); err != nil {
+		return fmt.Errorf("error rotating access log: %v", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.pruneBackups()
+	return nil
+}
+
+// pruneBackups removes rotated files beyond max_backups or older than max_age_days
+func (w *rotatingFileWriter) pruneBackups() {
+	if w.maxBackups == 0 && w.maxAge == 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(w.path + ".*")
+	if err != nil {
+		return
+	}
+	// Timestamp suffixes sort chronologically; newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	for i, backup := range backups {
+		expired := false
+		if w.maxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && time.Since(info.ModTime()) > w.maxAge {
+				expired = true
+			}
+		}
+		if expired || (w.maxBackups > 0 && i >= w.maxBackups) {
+			if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+				LogVerbose("⚠️  Error removing rotated access log %s: %v", backup, err)
+			}
+		}
+	}
+}
+
+// Close closes the current file
+func (w *rotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
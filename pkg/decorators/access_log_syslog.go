@@ -0,0 +1,13 @@
+//go:build !windows && !plan9
+
+package decorators
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// newSyslogWriter connects to syslog (local daemon when network and address are empty)
+func newSyslogWriter(network, address, tag string) (io.WriteCloser, error) {
+	return syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+}
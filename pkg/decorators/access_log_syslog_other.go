@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package decorators
+
+import (
+	"fmt"
+	"io"
+)
+
+// newSyslogWriter is unavailable on platforms without log/syslog
+func newSyslogWriter(network, address, tag string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("syslog output is not supported on this platform")
+}
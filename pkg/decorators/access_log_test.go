@@ -0,0 +1,139 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAccessLogRouter(t *testing.T, config AccessLogConfig, buf *bytes.Buffer) *gin.Engine {
+	logger, err := NewAccessLoggerWithWriter(config, buf)
+	if err != nil {
+		t.Fatalf("NewAccessLoggerWithWriter() error = %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(logger.Middleware())
+	router.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "hello") })
+	router.GET("/health", CreateNoAccessLogMiddleware(""), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestAccessLog_Combined(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(t, AccessLogConfig{Format: AccessLogFormatCombined}, &buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?full=true", http.NoBody)
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.Header.Set("Referer", "http://example.com")
+	req.SetBasicAuth("alice", "secret")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	assert.Contains(t, line, `192.0.2.1 - alice [`)
+	assert.Contains(t, line, `"GET /users/1?full=true HTTP/1.1" 200 5 "http://example.com" "curl/8.0"`)
+	assert.True(t, strings.HasSuffix(line, "\n"))
+}
+
+func TestAccessLog_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(t, AccessLogConfig{Format: AccessLogFormatJSON}, &buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", http.NoBody)
+	req.Header.Set("X-Request-ID", "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry AccessLogEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "/users/:id", entry.Route)
+	assert.Equal(t, "/users/7", entry.URI)
+	assert.Equal(t, http.StatusOK, entry.Status)
+	assert.Equal(t, "req-1", entry.RequestID)
+}
+
+func TestAccessLog_Template(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(t, AccessLogConfig{
+		Format:   AccessLogFormatTemplate,
+		Template: "{{.Method}} {{.Route}} {{.Status}}",
+	}, &buf)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", http.NoBody))
+	assert.Equal(t, "GET /users/:id 200\n", buf.String())
+}
+
+func TestAccessLog_NoAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(t, AccessLogConfig{}, &buf)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+	assert.Empty(t, buf.String())
+}
+
+func TestAccessLogConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AccessLogConfig
+		wantErr bool
+	}{
+		{"defaults", DefaultConfig().AccessLog, false},
+		{"unknown format", AccessLogConfig{Format: "common"}, true},
+		{"template without text", AccessLogConfig{Format: AccessLogFormatTemplate}, true},
+		{"broken template", AccessLogConfig{Format: AccessLogFormatTemplate, Template: "{{.Method"}, true},
+		{"file without path", AccessLogConfig{Output: AccessLogOutputFile}, true},
+		{"unknown output", AccessLogConfig{Output: "kafka"}, true},
+		{"negative rotation", AccessLogConfig{Output: AccessLogOutputFile, File: "a.log", MaxBackups: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRotatingFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	writer, err := newRotatingFileWriter(path, 0, 2, 0)
+	assert.NoError(t, err)
+	writer.maxSize = 10 // bytes, to force rotations
+	defer writer.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := writer.Write([]byte("12345678\n"))
+		assert.NoError(t, err)
+		time.Sleep(time.Millisecond) // distinct rotation timestamps
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678\n", string(data))
+
+	backups, _ := filepath.Glob(path + ".*")
+	assert.Len(t, backups, 2, "only max_backups rotated files are kept")
+}
+
+func TestNoAccessLogArgumentValidation(t *testing.T) {
+	_, err := parseArgumentsWithValidation("", "NoAccessLog")
+	assert.NoError(t, err)
+
+	_, err = parseArgumentsWithValidation(`"yes"`, "NoAccessLog")
+	assert.Error(t, err)
+
+	assert.Equal(t, `deco.CreateNoAccessLogMiddleware("")`, generateMiddlewareCall(MarkerInstance{Name: "NoAccessLog"}))
+}
@@ -0,0 +1,351 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// AdminPathPrefix prefix of the cache and rate-limit administration endpoints
+const AdminPathPrefix = "/decorators/admin"
+
+// Pagination limits of the admin listings
+const (
+	defaultAdminPageSize = 100
+	maxAdminPageSize     = 1000
+)
+
+// CacheKeyLister cache stores able to list their keys
+type CacheKeyLister interface {
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// RateLimitCounter current state of a rate-limit key
+type RateLimitCounter struct {
+	Key       string    `json:"key"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining,omitempty"`
+	Limit     int       `json:"limit,omitempty"` // not known by the Redis sliding window
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// RateLimitInspector rate limiters able to list their counters
+type RateLimitInspector interface {
+	Counters(ctx context.Context, prefix string) ([]RateLimitCounter, error)
+}
+
+// stores and limiters created by the cache and rate-limit middlewares
+var (
+	adminCacheStores  []CacheStore
+	adminRateLimiters []RateLimiter
+	adminMutex        sync.RWMutex
+)
+
+// registerAdminCacheStore makes a cache store visible to the admin endpoints
+func registerAdminCacheStore(store CacheStore) {
+	adminMutex.Lock()
+	defer adminMutex.Unlock()
+	adminCacheStores = append(adminCacheStores, store)
+}
+
+// registerAdminRateLimiter makes a rate limiter visible to the admin endpoints
+func registerAdminRateLimiter(limiter RateLimiter) {
+	adminMutex.Lock()
+	defer adminMutex.Unlock()
+	adminRateLimiters = append(adminRateLimiters, limiter)
+}
+
+// adminStores returns a snapshot of the registered stores and limiters
+func adminStores() ([]CacheStore, []RateLimiter) {
+	adminMutex.RLock()
+	defer adminMutex.RUnlock()
+	return append([]CacheStore(nil), adminCacheStores...), append([]RateLimiter(nil), adminRateLimiters...)
+}
+
+// releaseAdminStores returns the registered stores and limiters and forgets them, on shutdown
+func releaseAdminStores() ([]CacheStore, []RateLimiter) {
+	adminMutex.Lock()
+	defer adminMutex.Unlock()
+	stores, limiters := adminCacheStores, adminRateLimiters
+	adminCacheStores, adminRateLimiters = nil, nil
+	return stores, limiters
+}
+
+// Keys lists the live keys starting with prefix (in-memory implementation)
+func (m *MemoryCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(m.data))
+	for key, entry := range m.data {
+		if strings.HasPrefix(key, prefix) && now.Before(entry.ExpiresAt) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Keys lists the keys starting with prefix (Redis implementation)
+func (r *RedisCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, escapeRedisPattern(r.prefix+prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), r.prefix))
+	}
+	return keys, iter.Err()
+}
+
+// Counters lists the buckets whose key starts with prefix (in-memory implementation)
+func (m *MemoryRateLimiter) Counters(ctx context.Context, prefix string) ([]RateLimitCounter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := make([]RateLimitCounter, 0, len(m.buckets))
+	for key, bucket := range m.buckets {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		counters = append(counters, RateLimitCounter{
+			Key:       key,
+			Used:      bucket.limit - bucket.tokens,
+			Remaining: bucket.tokens,
+			Limit:     bucket.limit,
+			ResetAt:   bucket.lastRefill.Add(bucket.window),
+		})
+	}
+	return counters, nil
+}
+
+// Counters lists the counters whose key starts with prefix (Redis implementation); keys of other types
+// (e.g. cache entries under the same prefix) are skipped
+func (r *RedisRateLimiter) Counters(ctx context.Context, prefix string) ([]RateLimitCounter, error) {
+	var counters []RateLimitCounter
+	iter := r.client.Scan(ctx, 0, escapeRedisPattern(prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		kind, err := r.client.Type(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case "zset": // sliding window: one member per request, scored by the instant it leaves the window
+			live := &redis.ZRangeBy{Min: "(" + strconv.FormatInt(time.Now().UnixMilli(), 10), Max: "+inf", Count: 1}
+			used, err := r.client.ZCount(ctx, key, live.Min, live.Max).Result()
+			if err != nil {
+				return nil, err
+			}
+			oldest, err := r.client.ZRangeByScoreWithScores(ctx, key, live).Result()
+			if err != nil {
+				return nil, err
+			}
+			if used == 0 || len(oldest) == 0 {
+				continue
+			}
+			counters = append(counters, RateLimitCounter{Key: key, Used: int(used), ResetAt: time.UnixMilli(int64(oldest[0].Score))})
+
+		case "hash": // token bucket
+			values, err := r.client.HMGet(ctx, key, "tokens", "capacity", "full_at").Result()
+			if err != nil {
+				return nil, err
+			}
+			capacity, _ := strconv.Atoi(fmt.Sprint(values[1]))
+			if capacity == 0 {
+				continue
+			}
+			tokens, _ := strconv.ParseFloat(fmt.Sprint(values[0]), 64)
+			fullAt, _ := strconv.ParseInt(fmt.Sprint(values[2]), 10, 64)
+			counters = append(counters, RateLimitCounter{
+				Key:       key,
+				Used:      capacity - int(tokens),
+				Remaining: int(tokens),
+				Limit:     capacity,
+				ResetAt:   time.UnixMilli(fullAt),
+			})
+		}
+	}
+	return counters, iter.Err()
+}
+
+// escapeRedisPattern escapes the glob characters of a SCAN pattern
+func escapeRedisPattern(pattern string) string {
+	var escaped strings.Builder
+	for _, r := range pattern {
+		if strings.ContainsRune(`*?[]\`, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// adminKeysRequest body of the admin delete endpoints
+type adminKeysRequest struct {
+	Keys []string `json:"keys" binding:"required,min=1"`
+}
+
+// RegisterAdminRoutes registers the cache and rate-limit administration endpoints under
+// /decorators/admin. The endpoints can drop cached responses and reset limits, so callers
+// are expected to pass security middlewares (see AdminConfig).
+func RegisterAdminRoutes(r gin.IRouter, middlewares ...gin.HandlerFunc) {
+	group := r.Group(AdminPathPrefix, middlewares...)
+	group.GET("/cache/keys", listCacheKeysHandler)
+	group.DELETE("/cache/keys", deleteCacheKeysHandler)
+	group.GET("/ratelimit", listRateLimitCountersHandler)
+	group.DELETE("/ratelimit/keys", resetRateLimitKeysHandler)
+}
+
+// listCacheKeysHandler GET /decorators/admin/cache/keys?prefix=&offset=&limit=
+func listCacheKeysHandler(c *gin.Context) {
+	offset, limit, ok := adminPage(c)
+	if !ok {
+		return
+	}
+
+	stores, _ := adminStores()
+	seen := make(map[string]bool)
+	for _, store := range stores {
+		lister, ok := store.(CacheKeyLister)
+		if !ok {
+			continue
+		}
+		keys, err := lister.Keys(c.Request.Context(), c.Query("prefix"))
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "cache_unavailable", "message": err.Error()})
+			return
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	c.JSON(http.StatusOK, adminPageResponse(len(keys), offset, limit, func(start, end int) interface{} {
+		return keys[start:end]
+	}))
+}
+
+// deleteCacheKeysHandler DELETE /decorators/admin/cache/keys {"keys": [...]}
+func deleteCacheKeysHandler(c *gin.Context) {
+	var request adminKeysRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	stores, _ := adminStores()
+	for _, store := range stores {
+		for _, key := range request.Keys {
+			if err := store.Delete(c.Request.Context(), key); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "cache_unavailable", "message": err.Error()})
+				return
+			}
+		}
+	}
+	LogNormal("🧹 Admin: deleted %d cache keys", len(request.Keys))
+	c.JSON(http.StatusOK, gin.H{"deleted": request.Keys})
+}
+
+// listRateLimitCountersHandler GET /decorators/admin/ratelimit?prefix=&offset=&limit=
+func listRateLimitCountersHandler(c *gin.Context) {
+	offset, limit, ok := adminPage(c)
+	if !ok {
+		return
+	}
+
+	_, limiters := adminStores()
+	byKey := make(map[string]RateLimitCounter)
+	for _, limiter := range limiters {
+		inspector, ok := limiter.(RateLimitInspector)
+		if !ok {
+			continue
+		}
+		counters, err := inspector.Counters(c.Request.Context(), c.Query("prefix"))
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "rate_limiter_unavailable", "message": err.Error()})
+			return
+		}
+		// Limiters sharing a key (e.g. Redis) report it once, with the highest usage
+		for _, counter := range counters {
+			if existing, exists := byKey[counter.Key]; !exists || counter.Used > existing.Used {
+				byKey[counter.Key] = counter
+			}
+		}
+	}
+
+	counters := make([]RateLimitCounter, 0, len(byKey))
+	for _, counter := range byKey {
+		counters = append(counters, counter)
+	}
+	sort.Slice(counters, func(i, j int) bool { return counters[i].Key < counters[j].Key })
+	c.JSON(http.StatusOK, adminPageResponse(len(counters), offset, limit, func(start, end int) interface{} {
+		return counters[start:end]
+	}))
+}
+
+// resetRateLimitKeysHandler DELETE /decorators/admin/ratelimit/keys {"keys": [...]}
+func resetRateLimitKeysHandler(c *gin.Context) {
+	var request adminKeysRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	_, limiters := adminStores()
+	for _, limiter := range limiters {
+		for _, key := range request.Keys {
+			if err := limiter.Reset(c.Request.Context(), key); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "rate_limiter_unavailable", "message": err.Error()})
+				return
+			}
+		}
+	}
+	LogNormal("🧹 Admin: reset %d rate-limit keys", len(request.Keys))
+	c.JSON(http.StatusOK, gin.H{"reset": request.Keys})
+}
+
+// adminPage parses the offset and limit query parameters, answering 400 when invalid
+func adminPage(c *gin.Context) (offset, limit int, ok bool) {
+	offset, limit = 0, defaultAdminPageSize
+	var err error
+	if value := c.Query("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_offset", "message": "offset must be a non-negative integer"})
+			return 0, 0, false
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxAdminPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_limit",
+				"message": fmt.Sprintf("limit must be between 1 and %d", maxAdminPageSize),
+			})
+			return 0, 0, false
+		}
+	}
+	return offset, limit, true
+}
+
+// adminPageResponse builds a page of a sorted listing of total items, sliced by bounds
+func adminPageResponse(total, offset, limit int, page func(start, end int) interface{}) gin.H {
+	start := min(offset, total)
+	end := min(start+limit, total)
+	response := gin.H{"items": page(start, end), "total": total, "offset": offset, "limit": limit}
+	if end < total {
+		response["next_offset"] = end
+	}
+	return response
+}
@@ -0,0 +1,103 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminPageBody struct {
+	Items      json.RawMessage `json:"items"`
+	Total      int             `json:"total"`
+	NextOffset *int            `json:"next_offset"`
+}
+
+func adminRequest(t *testing.T, router *gin.Engine, method, path, body string) (int, adminPageBody) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var page adminPageBody
+	if w.Code == http.StatusOK && method == http.MethodGet {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	}
+	return w.Code, page
+}
+
+func TestAdminRoutes_Cache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	keyGen := func(c *gin.Context) string { return "admin-test:" + c.Param("id") }
+	router.GET("/items/:id", CacheMiddleware(&CacheConfig{Type: "memory", DefaultTTL: "1m", MaxSize: 100}, keyGen), func(c *gin.Context) {
+		c.String(http.StatusOK, c.Param("id"))
+	})
+	RegisterAdminRoutes(router)
+
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%d", i), nil))
+	}
+
+	code, page := adminRequest(t, router, http.MethodGet, "/decorators/admin/cache/keys?prefix=admin-test:&limit=2", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, page.Total)
+	assert.JSONEq(t, `["admin-test:0", "admin-test:1"]`, string(page.Items))
+	require.NotNil(t, page.NextOffset)
+	assert.Equal(t, 2, *page.NextOffset)
+
+	code, page = adminRequest(t, router, http.MethodGet, "/decorators/admin/cache/keys?prefix=admin-test:&offset=2", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `["admin-test:2"]`, string(page.Items))
+	assert.Nil(t, page.NextOffset)
+
+	code, _ = adminRequest(t, router, http.MethodDelete, "/decorators/admin/cache/keys", `{"keys": ["admin-test:1"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	_, page = adminRequest(t, router, http.MethodGet, "/decorators/admin/cache/keys?prefix=admin-test:", "")
+	assert.JSONEq(t, `["admin-test:0", "admin-test:2"]`, string(page.Items))
+
+	code, _ = adminRequest(t, router, http.MethodDelete, "/decorators/admin/cache/keys", `{"keys": []}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = adminRequest(t, router, http.MethodGet, "/decorators/admin/cache/keys?limit=5000", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAdminRoutes_RateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	keyGen := func(c *gin.Context) string { return "admin-test-rl:" + c.ClientIP() }
+	router.GET("/limited", CustomRateLimit(5, time.Minute, keyGen, "memory"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	RegisterAdminRoutes(router)
+
+	for i := 0; i < 2; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/limited", nil))
+	}
+
+	code, page := adminRequest(t, router, http.MethodGet, "/decorators/admin/ratelimit?prefix=admin-test-rl:", "")
+	require.Equal(t, http.StatusOK, code)
+	var counters []RateLimitCounter
+	require.NoError(t, json.Unmarshal(page.Items, &counters))
+	require.Len(t, counters, 1)
+	assert.Equal(t, 2, counters[0].Used)
+	assert.Equal(t, 3, counters[0].Remaining)
+	assert.Equal(t, 5, counters[0].Limit)
+
+	code, _ = adminRequest(t, router, http.MethodDelete, "/decorators/admin/ratelimit/keys", fmt.Sprintf(`{"keys": [%q]}`, counters[0].Key))
+	assert.Equal(t, http.StatusOK, code)
+	_, page = adminRequest(t, router, http.MethodGet, "/decorators/admin/ratelimit?prefix=admin-test-rl:", "")
+	assert.Equal(t, 0, page.Total)
+}
+
+func TestEscapeRedisPattern(t *testing.T) {
+	assert.Equal(t, `ratelimit:user:a\*b\?\[c\]`, escapeRedisPattern("ratelimit:user:a*b?[c]"))
+}
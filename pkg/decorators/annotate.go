@@ -0,0 +1,351 @@
+package decorators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Back-filling of documentation decorators (deco annotate): @Summary, the body @Param and the @Response
+// of each status the handler writes are proposed for the routes that do not declare them
+
+// statusWriteMethods gin.Context methods whose first argument is the response status
+var statusWriteMethods = map[string]bool{
+	"Status": true, "AbortWithStatus": true, "AbortWithError": true, "String": true, "Data": true,
+	"HTML": true, "Redirect": true, "ProtoBuf": true, "Render": true, "DataFromReader": true,
+}
+
+// annotationDiffContext lines of context around the insertions of AnnotationDiff
+const annotationDiffContext = 3
+
+// HandlerAnnotation decorators proposed for a handler
+type HandlerAnnotation struct {
+	File    string
+	Handler string
+	Line    int      // line the decorators are inserted after (the last decorator of the handler)
+	Lines   []string // comment lines to insert
+}
+
+// AnnotateSource proposes the decorators missing from the handlers of a Go source — @Summary from the
+// doc comment or the function name, @Param(location="body") from the bound struct and one @Response per
+// status written with c.JSON, c.Status, c.AbortWithStatus... — and returns the source with them inserted
+func AnnotateSource(fileName string, content []byte) ([]HandlerAnnotation, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, content, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var annotations []HandlerAnnotation
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		route, validationErr := parseFunctionWithValidation(fset, fileName, funcDecl, file.Name.Name)
+		if validationErr != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %s", validationErr.File, validationErr.Line, validationErr.Message)
+		}
+		if route == nil {
+			continue
+		}
+		if lines := missingAnnotations(route, funcDecl); len(lines) > 0 {
+			last := route.Line
+			for _, marker := range route.Markers {
+				last = max(last, marker.Line)
+			}
+			annotations = append(annotations, HandlerAnnotation{File: fileName, Handler: route.FuncName, Line: last, Lines: lines})
+		}
+	}
+	if len(annotations) == 0 {
+		return nil, content, nil
+	}
+	return annotations, insertAnnotations(content, annotations), nil
+}
+
+// LearnedAnnotations places the suggestions of a traffic learning report (LearnReport) in a Go source: the
+// decorators of each handler after its last decorator, and the @Schema types they reference at the end of
+// the file, except the types the file already declares
+func LearnedAnnotations(fileName string, content []byte, learned []LearnedRoute) ([]HandlerAnnotation, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, content, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byHandler := make(map[string][]LearnedRoute)
+	for _, route := range learned {
+		byHandler[route.Handler] = append(byHandler[route.Handler], route)
+	}
+	declared := make(map[string]bool)
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				declared[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+
+	var annotations []HandlerAnnotation
+	var schemas []string
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || len(byHandler[funcDecl.Name.Name]) == 0 {
+			continue
+		}
+		route, validationErr := parseFunctionWithValidation(fset, fileName, funcDecl, file.Name.Name)
+		if validationErr != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %s", validationErr.File, validationErr.Line, validationErr.Message)
+		}
+		if route == nil {
+			continue
+		}
+
+		last := route.Line
+		existing := make(map[string]bool)
+		for _, marker := range route.Markers {
+			last = max(last, marker.Line)
+			existing["// "+marker.Raw] = true
+		}
+		var lines []string
+		for _, suggestion := range byHandler[route.FuncName] {
+			for _, line := range suggestion.Annotations {
+				if !existing[line] {
+					existing[line] = true
+					lines = append(lines, line)
+				}
+			}
+			for _, schema := range suggestion.Schemas {
+				if !declared[schema.Name] {
+					declared[schema.Name] = true
+					schemas = append(schemas, "", strings.TrimSuffix(schema.Source, "\n"))
+				}
+			}
+		}
+		if len(lines) > 0 {
+			annotations = append(annotations, HandlerAnnotation{File: fileName, Handler: route.FuncName, Line: last, Lines: lines})
+		}
+	}
+	if len(annotations) == 0 {
+		return nil, content, nil
+	}
+	if len(schemas) > 0 {
+		end := strings.Count(strings.TrimSuffix(string(content), "\n"), "\n") + 1
+		annotations = append(annotations, HandlerAnnotation{File: fileName, Line: end, Lines: strings.Split(strings.Join(schemas, "\n"), "\n")})
+	}
+	return annotations, insertAnnotations(content, annotations), nil
+}
+
+// insertAnnotations inserts the lines of the annotations, sorted by line, after their line
+func insertAnnotations(content []byte, annotations []HandlerAnnotation) []byte {
+	source := strings.SplitAfter(string(content), "\n")
+	var b strings.Builder
+	next := 0
+	for i, line := range source {
+		b.WriteString(line)
+		if next < len(annotations) && annotations[next].Line == i+1 && !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+		for next < len(annotations) && annotations[next].Line == i+1 {
+			for _, annotation := range annotations[next].Lines {
+				b.WriteString(annotation + "\n")
+			}
+			next++
+		}
+	}
+	return []byte(b.String())
+}
+
+// missingAnnotations decorator lines the handler lacks, in the order @Summary, @Param, @Response
+func missingAnnotations(route *RouteMeta, funcDecl *ast.FuncDecl) []string {
+	hasSummary, hasBody := false, false
+	declared := make(map[string]bool)
+	for _, marker := range route.Markers {
+		switch marker.Name {
+		case "Summary":
+			hasSummary = true
+		case "Param":
+			hasBody = hasBody || parseParameterInfo(marker.Args).Location == "body"
+		case "RequestBody", "FileParam":
+			hasBody = true
+		case "Response":
+			code := parseResponseInfo(marker.Args).Code
+			if code == "" && len(marker.Args) > 0 && !strings.Contains(marker.Args[0], "=") {
+				code = MarkerValue(marker.Args[0])
+			}
+			declared[code] = true
+		}
+	}
+
+	var lines []string
+	if !hasSummary {
+		lines = append(lines, fmt.Sprintf("// @Summary(%q)", handlerSummary(funcDecl)))
+	}
+
+	request, responses := handlerStatuses(funcDecl)
+	if request != "" && !hasBody {
+		lines = append(lines, fmt.Sprintf(`// @Param(name="body", type=%q, location="body", required=true)`, request))
+	}
+	for _, response := range responses {
+		if declared[response.Code] {
+			continue
+		}
+		line := fmt.Sprintf("// @Response(code=%s, description=%q", response.Code, response.Description)
+		if response.Type != "" {
+			line += fmt.Sprintf(", type=%q", response.Type)
+		}
+		lines = append(lines, line+")")
+	}
+	return lines
+}
+
+// handlerStatuses request body type and responses shown by the handler code, sorted by status
+func handlerStatuses(funcDecl *ast.FuncDecl) (string, []ResponseInfo) {
+	route := &RouteMeta{}
+	inferHandlerTypes(route, funcDecl)
+
+	statuses := make(map[int]string)
+	for _, response := range route.InferredResponses {
+		code, _ := strconv.Atoi(response.Code)
+		statuses[code] = response.Type
+	}
+	ctxName, typed := handlerSignature(funcDecl)
+	if funcDecl.Body != nil && (ctxName != "" || typed) {
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			var code int
+			switch fun := call.Fun.(type) {
+			case *ast.SelectorExpr:
+				receiver, ok := fun.X.(*ast.Ident)
+				switch {
+				case ok && ctxName != "" && receiver.Name == ctxName && (statusWriteMethods[fun.Sel.Name] || jsonWriteMethods[fun.Sel.Name]):
+					code = statusCodeOf(call.Args[0])
+				case ok && typed && fun.Sel.Name == "NewHTTPError":
+					code = statusCodeOf(call.Args[0]) // deco.NewHTTPError(http.StatusNotFound, ...)
+				}
+			case *ast.Ident:
+				if typed && fun.Name == "NewHTTPError" {
+					code = statusCodeOf(call.Args[0])
+				}
+			}
+			if _, seen := statuses[code]; code != 0 && !seen {
+				statuses[code] = ""
+			}
+			return true
+		})
+	}
+
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	responses := make([]ResponseInfo, 0, len(codes))
+	for _, code := range codes {
+		responses = append(responses, ResponseInfo{Code: strconv.Itoa(code), Description: http.StatusText(code), Type: statuses[code]})
+	}
+	return route.InferredRequest, responses
+}
+
+// handlerSummary first sentence of the doc comment without the function name ("CreateUser creates a
+// user." gives "Creates a user"), or the function name in words ("GetUserByID" gives "Get user by ID")
+func handlerSummary(funcDecl *ast.FuncDecl) string {
+	name := funcDecl.Name.Name
+	for _, comment := range funcDecl.Doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if text == "" || strings.HasPrefix(text, "@") || strings.HasPrefix(text, "/*") {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, name+" "))
+		text, _, _ = strings.Cut(text, ". ")
+		text = strings.TrimSuffix(text, ".")
+		if text != "" && text != "godoc" {
+			first, size := utf8.DecodeRuneInString(text)
+			return string(unicode.ToUpper(first)) + text[size:]
+		}
+	}
+
+	words := splitIdentifier(name)
+	for i, word := range words {
+		if i > 0 && !isAcronym(word) {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitIdentifier splits a Go identifier into words: "GetUserByID" gives Get, User, By, ID
+func splitIdentifier(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd || runes[i] == '_' {
+			if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+				words = append(words, word)
+			}
+			start = i
+		}
+	}
+	if word := strings.Trim(string(runes[start:]), "_"); word != "" {
+		words = append(words, word)
+	}
+	return words
+}
+
+// isAcronym reports whether a word is all upper case, like ID or HTTP
+func isAcronym(word string) bool {
+	return len(word) > 1 && strings.ToUpper(word) == word
+}
+
+// AnnotationDiff unified diff of the annotations against the original content, for patch -p1 / git apply
+func AnnotationDiff(fileName string, content []byte, annotations []HandlerAnnotation) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", fileName, fileName)
+	offset := 0
+	for i := 0; i < len(annotations); {
+		// Insertions whose context overlaps share a hunk
+		j := i + 1
+		for j < len(annotations) && annotations[j].Line-annotations[j-1].Line <= 2*annotationDiffContext {
+			j++
+		}
+		start := max(annotations[i].Line-annotationDiffContext, 0)
+		end := min(annotations[j-1].Line+annotationDiffContext, len(lines))
+		added := 0
+		for _, annotation := range annotations[i:j] {
+			added += len(annotation.Lines)
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1+offset, end-start+added)
+		next := i
+		for n := start; n < end; n++ {
+			b.WriteString(" " + lines[n] + "\n")
+			for next < j && annotations[next].Line == n+1 {
+				for _, line := range annotations[next].Lines {
+					b.WriteString("+" + line + "\n")
+				}
+				next++
+			}
+		}
+		offset += added
+		i = j
+	}
+	return b.String()
+}
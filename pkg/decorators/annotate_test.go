@@ -0,0 +1,131 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotateSource = `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateUser creates a user. The e-mail must be unique.
+// @Route("POST", "/users")
+// @Auth(role="admin")
+func CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, UserResponse{})
+}
+
+// @Route("GET", "/users/:id")
+// @Summary("Get a user")
+// @Response(code=200, description="User", type="UserResponse")
+func GetUserByID(c *gin.Context) {
+	if c.Param("id") == "" {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.JSON(200, UserResponse{})
+}
+
+// @Route("GET", "/health")
+// @Summary("Health")
+// @Response(code=200, description="OK")
+func Health(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func helper() {}
+`
+
+func TestAnnotateSource(t *testing.T) {
+	annotations, updated, err := AnnotateSource("handlers/users.go", []byte(annotateSource))
+	require.NoError(t, err)
+	require.Len(t, annotations, 2, "fully documented handlers are left alone")
+
+	assert.Equal(t, HandlerAnnotation{
+		File:    "handlers/users.go",
+		Handler: "CreateUser",
+		Line:    11,
+		Lines: []string{
+			`// @Summary("Creates a user")`,
+			`// @Param(name="body", type="CreateUserRequest", location="body", required=true)`,
+			`// @Response(code=201, description="Created", type="UserResponse")`,
+			`// @Response(code=400, description="Bad Request")`,
+		},
+	}, annotations[0])
+	assert.Equal(t, "GetUserByID", annotations[1].Handler)
+	assert.Equal(t, []string{`// @Response(code=404, description="Not Found")`}, annotations[1].Lines)
+
+	// The updated source parses with the new decorators
+	again, _, err := AnnotateSource("handlers/users.go", updated)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "users.go", updated, parser.ParseComments)
+	require.NoError(t, err)
+	result := extractFileResult(fset, "users.go", file, "handlers")
+	require.Empty(t, result.Errors)
+	require.NoError(t, processMiddlewares(result.Routes[0]))
+	assert.Equal(t, "Creates a user", result.Routes[0].Summary)
+	assert.Len(t, result.Routes[0].Responses, 2)
+}
+
+func TestAnnotationDiff(t *testing.T) {
+	content := []byte(annotateSource)
+	annotations, _, err := AnnotateSource("handlers/users.go", content)
+	require.NoError(t, err)
+
+	diff := AnnotationDiff("handlers/users.go", content, annotations)
+	assert.Equal(t, `--- a/handlers/users.go
++++ b/handlers/users.go
+@@ -9,6 +9,10 @@
+ // CreateUser creates a user. The e-mail must be unique.
+ // @Route("POST", "/users")
+ // @Auth(role="admin")
++// @Summary("Creates a user")
++// @Param(name="body", type="CreateUserRequest", location="body", required=true)
++// @Response(code=201, description="Created", type="UserResponse")
++// @Response(code=400, description="Bad Request")
+ func CreateUser(c *gin.Context) {
+ 	var req CreateUserRequest
+ 	if err := c.ShouldBindJSON(&req); err != nil {
+@@ -21,6 +25,7 @@
+ // @Route("GET", "/users/:id")
+ // @Summary("Get a user")
+ // @Response(code=200, description="User", type="UserResponse")
++// @Response(code=404, description="Not Found")
+ func GetUserByID(c *gin.Context) {
+ 	if c.Param("id") == "" {
+ 		c.AbortWithStatus(http.StatusNotFound)
+`, diff)
+	assert.Empty(t, AnnotationDiff("handlers/users.go", content, nil))
+}
+
+func TestHandlerSummaryFromName(t *testing.T) {
+	assert.Equal(t, []string{"Get", "User", "By", "ID"}, splitIdentifier("GetUserByID"))
+	assert.Equal(t, []string{"Parse", "HTTP", "Request"}, splitIdentifier("ParseHTTPRequest"))
+
+	annotations, _, err := AnnotateSource("h.go", []byte(`package h
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/orders")
+func ListHTTPOrders(c *gin.Context) {}
+`))
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, []string{`// @Summary("List HTTP orders")`}, annotations[0].Lines)
+}
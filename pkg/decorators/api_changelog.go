@@ -0,0 +1,196 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangelogPath endpoint listing the API changes between deployments
+const ChangelogPath = "/decorators/changelog"
+
+// Changelog stores (changelog.store)
+const (
+	ChangelogStoreFile  = "file"
+	ChangelogStoreRedis = "redis"
+)
+
+// changelogRedisKey key of the changelog state in the Redis store
+const changelogRedisKey = "changelog"
+
+// APIChangelogEntry endpoints added, removed or modified by one deployment
+type APIChangelogEntry struct {
+	Version    string    `json:"version"`               // hash of the route table
+	APIVersion string    `json:"api_version,omitempty"` // openapi.version at deployment
+	DeployedAt time.Time `json:"deployed_at"`
+	Added      []string  `json:"added,omitempty"` // "GET /users/{id}"
+	Removed    []string  `json:"removed,omitempty"`
+	Modified   []string  `json:"modified,omitempty"`
+}
+
+// apiChangelogState persisted changelog: the route table summary of the latest deployment and
+// the entries, newest first
+type apiChangelogState struct {
+	Summary map[string]string   `json:"summary"` // operation hash by "METHOD path"
+	Entries []APIChangelogEntry `json:"entries"`
+}
+
+var (
+	apiChangelog      *apiChangelogState
+	apiChangelogMutex sync.RWMutex
+)
+
+// RecordAPIDeployment compares the route table with the one persisted by the previous deployment
+// and records an entry when it changed. The first deployment lists every endpoint as added.
+func RecordAPIDeployment(config *Config) (*APIChangelogEntry, error) {
+	settings := config.Changelog
+	state, err := loadChangelogState(config)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := GenerateOpenAPISpec(config)
+	summary := make(map[string]string)
+	for endpoint, operation := range specOperations(spec) {
+		data, _ := json.Marshal(operation)
+		summary[endpoint] = specHash(data)
+	}
+	data, _ := json.Marshal(summary) // map keys are encoded in order
+	version := specHash(data)
+
+	var entry *APIChangelogEntry
+	if len(state.Entries) == 0 || state.Entries[0].Version != version {
+		previous := make(map[string]interface{}, len(state.Summary))
+		for endpoint, hash := range state.Summary {
+			previous[endpoint] = hash
+		}
+		current := make(map[string]interface{}, len(summary))
+		for endpoint, hash := range summary {
+			current[endpoint] = hash
+		}
+
+		entry = &APIChangelogEntry{Version: version, APIVersion: config.OpenAPI.Version, DeployedAt: time.Now().UTC()}
+		entry.Added, entry.Removed, entry.Modified = diffJSONMaps(previous, current)
+		state.Summary = summary
+		state.Entries = append([]APIChangelogEntry{*entry}, state.Entries...)
+		if settings.MaxEntries > 0 && len(state.Entries) > settings.MaxEntries {
+			state.Entries = state.Entries[:settings.MaxEntries]
+		}
+		if err := saveChangelogState(config, state); err != nil {
+			return nil, err
+		}
+		LogVerbose("📜 API changelog: deployment %s (+%d -%d ~%d)", version, len(entry.Added), len(entry.Removed), len(entry.Modified))
+	}
+
+	apiChangelogMutex.Lock()
+	apiChangelog = state
+	apiChangelogMutex.Unlock()
+	return entry, nil
+}
+
+// loadChangelogState reads the persisted changelog; a missing one starts empty
+func loadChangelogState(config *Config) (*apiChangelogState, error) {
+	var data []byte
+	switch config.Changelog.Store {
+	case ChangelogStoreRedis:
+		store, err := NewRedisCache(config.Redis, "gin_decorators:")
+		if err != nil {
+			return nil, fmt.Errorf("changelog store: %v", err)
+		}
+		entry, err := store.Get(context.Background(), changelogRedisKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading changelog: %v", err)
+		}
+		if entry != nil {
+			data = entry.Data
+		}
+	default:
+		content, err := os.ReadFile(config.ResolvePath(config.Changelog.File))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading changelog: %v", err)
+		}
+		data = content
+	}
+
+	state := &apiChangelogState{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("error decoding changelog: %v", err)
+		}
+	}
+	return state, nil
+}
+
+// saveChangelogState persists the changelog for the next deployment
+func saveChangelogState(config *Config, state *apiChangelogState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding changelog: %v", err)
+	}
+
+	if config.Changelog.Store == ChangelogStoreRedis {
+		store, err := NewRedisCache(config.Redis, "gin_decorators:")
+		if err != nil {
+			return fmt.Errorf("changelog store: %v", err)
+		}
+		// The state never expires: the entry is kept until the next deployment replaces it
+		entry := &CacheEntry{Data: data, ExpiresAt: time.Now().AddDate(100, 0, 0)}
+		return store.Set(context.Background(), changelogRedisKey, entry, 0)
+	}
+
+	path := config.ResolvePath(config.Changelog.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error writing changelog: %v", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// validate checks the changelog configuration
+func (c ChangelogConfig) validate() error {
+	if c.Store != "" && c.Store != ChangelogStoreFile && c.Store != ChangelogStoreRedis {
+		return fmt.Errorf("invalid changelog.store '%s' (valid: file, redis)", c.Store)
+	}
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("invalid changelog.max_entries %d", c.MaxEntries)
+	}
+	return nil
+}
+
+// ChangelogHandler GET /decorators/changelog[?since=<version>] lists the deployments, newest first.
+// With since, only the deployments after that version are listed.
+func ChangelogHandler(c *gin.Context) {
+	apiChangelogMutex.RLock()
+	defer apiChangelogMutex.RUnlock()
+
+	if apiChangelog == nil || len(apiChangelog.Entries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "changelog_unavailable", "message": "no deployment was recorded (changelog.enabled)"})
+		return
+	}
+
+	entries := apiChangelog.Entries
+	if since := c.Query("since"); since != "" {
+		found := false
+		for i, entry := range entries {
+			if entry.Version == since {
+				entries, found = entries[:i], true
+				break
+			}
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown_version", "message": fmt.Sprintf("version '%s' is not in the changelog", since)})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": apiChangelog.Entries[0].Version,
+		"entries": entries,
+	})
+}
@@ -0,0 +1,77 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAPIDeployment(t *testing.T) {
+	saved := routes
+	defer func() {
+		routes = saved
+		apiChangelog = nil
+	}()
+	handler := func(c *gin.Context) {}
+
+	config := DefaultConfig()
+	config.Changelog.Enabled = true
+	config.Changelog.File = filepath.Join(t.TempDir(), "changelog.json")
+
+	routes = []RouteEntry{
+		{Method: "GET", Path: "/users", Handler: handler, Summary: "List users"},
+		{Method: "GET", Path: "/orders", Handler: handler},
+	}
+	first, err := RecordAPIDeployment(config)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, []string{"GET /orders", "GET /users"}, first.Added)
+
+	// A restart of the same deployment records nothing
+	again, err := RecordAPIDeployment(config)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+
+	routes = []RouteEntry{
+		{Method: "GET", Path: "/users", Handler: handler, Summary: "List active users"},
+		{Method: "POST", Path: "/users", Handler: handler},
+	}
+	second, err := RecordAPIDeployment(config)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, []string{"POST /users"}, second.Added)
+	assert.Equal(t, []string{"GET /orders"}, second.Removed)
+	assert.Equal(t, []string{"GET /users"}, second.Modified)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(ChangelogPath, ChangelogHandler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ChangelogPath+"?since="+first.Version, http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Version string              `json:"version"`
+		Entries []APIChangelogEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, second.Version, body.Version)
+	require.Len(t, body.Entries, 1)
+	assert.Equal(t, []string{"GET /orders"}, body.Entries[0].Removed)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ChangelogPath+"?since=nope", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestChangelogConfig_Validate(t *testing.T) {
+	assert.NoError(t, ChangelogConfig{Store: ChangelogStoreRedis}.validate())
+	assert.ErrorContains(t, ChangelogConfig{Store: "s3"}.validate(), "invalid changelog.store 's3'")
+	assert.ErrorContains(t, ChangelogConfig{MaxEntries: -1}.validate(), "invalid changelog.max_entries")
+}
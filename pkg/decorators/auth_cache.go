@@ -0,0 +1,173 @@
+package decorators
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Auth decision cache (auth.cache_ttl): the claims of verified tokens are kept by token hash for a short
+// time, so high-QPS routes skip the signature verification. The revocation list is checked on every
+// request, cached or not.
+
+// maxAuthCacheEntries tokens kept before the cache is swept
+const maxAuthCacheEntries = 10000
+
+// RevocationList tells whether a verified token was revoked. @Auth checks it on every request, so a cached
+// decision never outlives a revocation.
+type RevocationList interface {
+	IsRevoked(ctx context.Context, claims JWTClaims) (bool, error)
+}
+
+// authCacheEntry claims of a verified token
+type authCacheEntry struct {
+	claims   JWTClaims
+	verified time.Time
+	expires  time.Time // "exp" of the token, zero when it has none
+}
+
+// authDecisionCache verified claims by SHA-256 of the token
+type authDecisionCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]authCacheEntry
+	maxTTL  time.Duration // longest TTL asked for, entries older than it are swept
+	now     func() time.Time
+}
+
+var (
+	authCache      = newAuthDecisionCache()
+	authCacheTTL   time.Duration
+	revocationList RevocationList
+	authCacheMutex sync.RWMutex
+)
+
+func newAuthDecisionCache() *authDecisionCache {
+	return &authDecisionCache{entries: make(map[[sha256.Size]byte]authCacheEntry), now: time.Now}
+}
+
+// get returns the claims of a token verified less than ttl ago and not expired since
+func (a *authDecisionCache) get(token string, ttl time.Duration) (JWTClaims, bool) {
+	key := sha256.Sum256([]byte(token))
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.maxTTL = max(a.maxTTL, ttl)
+	entry, ok := a.entries[key]
+	if !ok {
+		return nil, false
+	}
+	now := a.now()
+	if now.Sub(entry.verified) >= ttl || (!entry.expires.IsZero() && !now.Before(entry.expires)) {
+		return nil, false
+	}
+	return entry.claims, true
+}
+
+// put keeps the claims of a verified token
+func (a *authDecisionCache) put(token string, claims JWTClaims) {
+	expires, _, _ := claims.time("exp")
+	key := sha256.Sum256([]byte(token))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if len(a.entries) >= maxAuthCacheEntries {
+		for k, entry := range a.entries {
+			if now.Sub(entry.verified) >= a.maxTTL || (!entry.expires.IsZero() && !now.Before(entry.expires)) {
+				delete(a.entries, k)
+			}
+		}
+		if len(a.entries) >= maxAuthCacheEntries {
+			clear(a.entries)
+		}
+	}
+	a.entries[key] = authCacheEntry{claims: claims, verified: now, expires: expires}
+}
+
+// forget drops a token, e.g. when it is revoked
+func (a *authDecisionCache) forget(token string) {
+	key := sha256.Sum256([]byte(token))
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.entries, key)
+}
+
+// ClearAuthCache drops every cached auth decision
+func ClearAuthCache() {
+	authCache.mu.Lock()
+	defer authCache.mu.Unlock()
+	clear(authCache.entries)
+}
+
+// UseRevocationList installs the revocation list checked by @Auth (nil removes it)
+func UseRevocationList(list RevocationList) {
+	authCacheMutex.Lock()
+	defer authCacheMutex.Unlock()
+	revocationList = list
+}
+
+// GetRevocationList returns the installed revocation list, nil when none
+func GetRevocationList() RevocationList {
+	authCacheMutex.RLock()
+	defer authCacheMutex.RUnlock()
+	return revocationList
+}
+
+// defaultAuthCacheTTL TTL of auth.cache_ttl, used by the routes without @Auth(cache=...)
+func defaultAuthCacheTTL() time.Duration {
+	authCacheMutex.RLock()
+	defer authCacheMutex.RUnlock()
+	return authCacheTTL
+}
+
+// verifyBearerToken verifies a token, reusing a decision cached less than ttl ago, and checks the
+// revocation list
+func verifyBearerToken(ctx context.Context, verifier *JWTVerifier, token string, ttl time.Duration) (JWTClaims, error) {
+	claims, cached := JWTClaims(nil), false
+	if ttl > 0 {
+		claims, cached = authCache.get(token, ttl)
+	}
+	if !cached {
+		verified, err := verifier.Verify(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		claims = verified
+		if claims.String("token_use") == refreshTokenUse {
+			return nil, fmt.Errorf("%w: refresh tokens are not access tokens", ErrInvalidToken)
+		}
+		if ttl > 0 {
+			authCache.put(token, claims)
+		}
+	}
+
+	if list := GetRevocationList(); list != nil {
+		revoked, err := list.IsRevoked(ctx, claims)
+		if err != nil {
+			return nil, fmt.Errorf("revocation list unavailable: %w", err)
+		}
+		if revoked {
+			authCache.forget(token)
+			return nil, fmt.Errorf("%w: token revoked", ErrInvalidToken)
+		}
+	}
+	return claims, nil
+}
+
+// validate checks the auth section
+func (a AuthConfig) validate() error {
+	if a.CacheTTL != "" {
+		if ttl, err := ParseDurationLiteral(a.CacheTTL); err != nil || ttl < 0 {
+			return fmt.Errorf("invalid auth.cache_ttl '%s'", a.CacheTTL)
+		}
+	}
+	if err := a.Revocation.validate(); err != nil {
+		return err
+	}
+	if err := a.Tokens.validate(); err != nil {
+		return err
+	}
+	return a.JWT.validate()
+}
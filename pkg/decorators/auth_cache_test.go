@@ -0,0 +1,121 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subjectRevocationList revokes the tokens of the listed subjects
+type subjectRevocationList struct {
+	subjects map[string]bool
+	err      error
+}
+
+func (l subjectRevocationList) IsRevoked(_ context.Context, claims JWTClaims) (bool, error) {
+	return l.subjects[claims.Subject()], l.err
+}
+
+func TestAuthDecisionCache(t *testing.T) {
+	cache := newAuthDecisionCache()
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", JWTClaims{"sub": "1"})
+	cache.put("b", JWTClaims{"sub": "2", "exp": json.Number(strconv.FormatInt(now.Unix()+5, 10))})
+
+	claims, ok := cache.get("a", time.Minute)
+	require.True(t, ok)
+	assert.Equal(t, "1", claims.Subject())
+	_, ok = cache.get("unknown", time.Minute)
+	assert.False(t, ok)
+
+	now = now.Add(10 * time.Second)
+	_, ok = cache.get("a", 5*time.Second)
+	assert.False(t, ok, "older than the route TTL")
+	_, ok = cache.get("a", time.Minute)
+	assert.True(t, ok)
+	_, ok = cache.get("b", time.Minute)
+	assert.False(t, ok, "the token expired")
+
+	cache.forget("a")
+	_, ok = cache.get("a", time.Minute)
+	assert.False(t, ok)
+}
+
+func TestVerifyBearerToken(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	ClearAuthCache()
+	defer ClearAuthCache()
+
+	verifier, err := NewJWTVerifier(JWTConfig{SecretKey: "jwt-secret"})
+	require.NoError(t, err)
+	token := signTestJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "42"}, hs256([]byte("s3cr3t")))
+
+	claims, err := verifyBearerToken(context.Background(), verifier, token, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "42", claims.Subject())
+
+	// A cached decision skips the signature verification
+	verifier.secret = []byte("rotated")
+	_, err = verifyBearerToken(context.Background(), verifier, token, time.Minute)
+	assert.NoError(t, err)
+	_, err = verifyBearerToken(context.Background(), verifier, token, 0)
+	assert.ErrorIs(t, err, ErrInvalidToken, "without cache the token is verified again")
+
+	// The revocation list is checked for cached decisions too
+	UseRevocationList(subjectRevocationList{subjects: map[string]bool{"42": true}})
+	defer UseRevocationList(nil)
+	_, err = verifyBearerToken(context.Background(), verifier, token, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, cached := authCache.get(token, time.Minute)
+	assert.False(t, cached, "revoked tokens leave the cache")
+
+	UseRevocationList(subjectRevocationList{err: errors.New("redis down")})
+	verifier.secret = []byte("s3cr3t")
+	_, err = verifyBearerToken(context.Background(), verifier, token, time.Minute)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthMiddlewareCacheTTL(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	require.NoError(t, ConfigureAuth(AuthConfig{JWT: JWTConfig{SecretKey: "jwt-secret"}, CacheTTL: "30s"}))
+	defer func() { _ = ConfigureAuth(AuthConfig{}) }()
+	assert.Equal(t, 30*time.Second, defaultAuthCacheTTL())
+	assert.Error(t, ConfigureAuth(AuthConfig{CacheTTL: "soon"}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/feed", createAuthMiddleware([]string{"cache=0"}), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/me", createAuthMiddleware(nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token := signTestJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "42"}, hs256([]byte("s3cr3t")))
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("/me"))
+	GetJWTVerifier().secret = []byte("rotated")
+	assert.Equal(t, http.StatusOK, request("/me"), "auth.cache_ttl reuses the decision")
+	assert.Equal(t, http.StatusUnauthorized, request("/feed"), "cache=0 verifies every request")
+
+	UseRevocationList(subjectRevocationList{err: errors.New("redis down")})
+	defer UseRevocationList(nil)
+	assert.Equal(t, http.StatusServiceUnavailable, request("/me"))
+}
@@ -0,0 +1,233 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BenchBaseline scenario without middlewares that overheads are measured against
+const BenchBaseline = "baseline"
+
+// benchMarkerAnnotation a marker of a bench scenario, written as in handler comments
+var benchMarkerAnnotation = regexp.MustCompile(`^@(\w+)\s*\((.*)\)$`)
+
+// BenchScenario middleware chain measured by deco bench, as marker annotations (e.g. "@Cache(ttl=1m)")
+type BenchScenario struct {
+	Name    string
+	Markers []string
+}
+
+// BenchOptions load applied to each scenario
+type BenchOptions struct {
+	Duration    time.Duration // measured time per scenario, after a short warm-up
+	Concurrency int           // concurrent clients, defaults to GOMAXPROCS
+}
+
+// BenchResult measurements of one scenario
+type BenchResult struct {
+	Scenario       string        `json:"scenario"`
+	Markers        []string      `json:"markers,omitempty"`
+	Requests       int64         `json:"requests"`
+	RequestsPerSec float64       `json:"requests_per_sec"`
+	Latency        time.Duration `json:"latency_ns"`  // mean time per request
+	Overhead       time.Duration `json:"overhead_ns"` // latency above the baseline
+	AllocsPerOp    float64       `json:"allocs_per_op"`
+	Budget         time.Duration `json:"budget_ns,omitempty"`
+	Exceeded       bool          `json:"exceeded,omitempty"`
+}
+
+// DefaultBenchScenarios one scenario per built-in marker that runs without external services,
+// plus their combination. Requests carry X-Bench-ID, so @RequireHeader passes.
+func DefaultBenchScenarios() []BenchScenario {
+	scenarios := []BenchScenario{
+		{Name: "RateLimit", Markers: []string{"@RateLimit(limit=1000000000, window=1m)"}},
+		{Name: "CORS", Markers: []string{"@CORS(origins=*)"}},
+		{Name: "Metrics", Markers: []string{"@Metrics()"}},
+		{Name: "RequireHeader", Markers: []string{"@RequireHeader(X-Bench-ID)"}},
+		{Name: "MaxResponseSize", Markers: []string{"@MaxResponseSize(1MB)"}},
+		{Name: "MaxBodySize", Markers: []string{"@MaxBodySize(1MB)"}},
+		{Name: "SlowThreshold", Markers: []string{"@SlowThreshold(threshold=1m)"}},
+		{Name: "Deprecated", Markers: []string{`@Deprecated("use /v2/bench", sunset=2099-12-31)`}},
+		// Last, so in the chain cache hits still pass through the other middlewares
+		{Name: "Cache", Markers: []string{"@Cache(ttl=1m)"}},
+	}
+
+	var chain []string
+	for _, scenario := range scenarios {
+		chain = append(chain, scenario.Markers...)
+	}
+	return append(scenarios, BenchScenario{Name: "chain", Markers: chain})
+}
+
+// BenchScenariosFromConfig the default scenarios plus the ones declared in bench.scenarios
+func BenchScenariosFromConfig(config BenchConfig) []BenchScenario {
+	scenarios := DefaultBenchScenarios()
+	names := make([]string, 0, len(config.Scenarios))
+	for name := range config.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		scenarios = append(scenarios, BenchScenario{Name: name, Markers: config.Scenarios[name]})
+	}
+	return scenarios
+}
+
+// RunBenchmarks measures a baseline and then each scenario, in process, so network noise does not
+// hide the middleware overhead
+func RunBenchmarks(scenarios []BenchScenario, options BenchOptions) ([]BenchResult, error) {
+	if options.Duration <= 0 {
+		options.Duration = 2 * time.Second
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	engines := make([]*gin.Engine, len(scenarios))
+	for i, scenario := range scenarios {
+		engine, err := benchEngine(scenario.Markers)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: %v", scenario.Name, err)
+		}
+		engines[i] = engine
+	}
+
+	baselineEngine, _ := benchEngine(nil)
+	baseline := runBenchScenario(baselineEngine, options)
+	baseline.Scenario = BenchBaseline
+	results := []BenchResult{baseline}
+
+	for i, scenario := range scenarios {
+		result := runBenchScenario(engines[i], options)
+		result.Scenario = scenario.Name
+		result.Markers = scenario.Markers
+		result.Overhead = max(result.Latency-baseline.Latency, 0)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// benchEngine builds the synthetic app: GET /bench/:id behind the scenario middlewares
+func benchEngine(annotations []string) (*gin.Engine, error) {
+	var middlewares []gin.HandlerFunc
+	for _, annotation := range annotations {
+		match := benchMarkerAnnotation.FindStringSubmatch(annotation)
+		if match == nil {
+			return nil, fmt.Errorf("invalid marker '%s' (expected @Name(args))", annotation)
+		}
+		config, ok := GetMarker(match[1])
+		if !ok || config.Factory == nil {
+			return nil, fmt.Errorf("marker @%s is not registered or does not create middleware", match[1])
+		}
+		args := parseArguments(match[2])
+		if err := validateArgumentValues(match[1], args); err != nil {
+			return nil, fmt.Errorf("@%s: %v", match[1], err)
+		}
+		middlewares = append(middlewares, config.Factory(args))
+	}
+
+	engine := gin.New()
+	handlers := append(middlewares, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "name": "bench"})
+	})
+	engine.GET("/bench/:id", handlers...)
+	return engine, nil
+}
+
+// runBenchScenario drives the engine from concurrent clients for the configured duration
+func runBenchScenario(engine *gin.Engine, options BenchOptions) BenchResult {
+	serve := func() {
+		req := httptest.NewRequest(http.MethodGet, "/bench/42", http.NoBody)
+		req.Header.Set("X-Bench-ID", "42")
+		req.Header.Set("Origin", "https://bench.local")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Warm-up fills caches and pools before measuring
+	for i := 0; i < 1000; i++ {
+		serve()
+	}
+	runtime.GC()
+
+	var requests int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for worker := 0; worker < options.Concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var served int64
+			for !stop.Load() {
+				serve()
+				served++
+			}
+			atomic.AddInt64(&requests, served)
+		}()
+	}
+	time.Sleep(options.Duration)
+	stop.Store(true)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	result := BenchResult{Requests: requests}
+	if requests > 0 {
+		result.RequestsPerSec = float64(requests) / elapsed.Seconds()
+		result.Latency = time.Duration(int64(elapsed) * int64(options.Concurrency) / requests)
+		result.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(requests)
+	}
+	return result
+}
+
+// validate checks the bench configuration
+func (b BenchConfig) validate() error {
+	if b.Duration != "" {
+		if duration, err := ParseDurationLiteral(b.Duration); err != nil || duration <= 0 {
+			return fmt.Errorf("invalid bench.duration '%s'", b.Duration)
+		}
+	}
+	if b.Concurrency < 0 {
+		return fmt.Errorf("invalid bench.concurrency %d", b.Concurrency)
+	}
+	for scenario, budget := range b.Budgets {
+		if _, err := ParseDurationLiteral(budget); err != nil {
+			return fmt.Errorf("invalid bench.budgets.%s: %v", scenario, err)
+		}
+	}
+	return nil
+}
+
+// CheckBenchBudgets marks the results whose overhead exceeds bench.budgets and describes the violations
+func CheckBenchBudgets(results []BenchResult, budgets map[string]string) ([]string, error) {
+	var violations []string
+	for i := range results {
+		limit, ok := budgets[results[i].Scenario]
+		if !ok {
+			continue
+		}
+		budget, err := ParseDurationLiteral(limit)
+		if err != nil {
+			return nil, fmt.Errorf("bench.budgets.%s: %v", results[i].Scenario, err)
+		}
+		results[i].Budget = budget
+		if results[i].Overhead > budget {
+			results[i].Exceeded = true
+			violations = append(violations, fmt.Sprintf("%s: overhead %s exceeds budget %s", results[i].Scenario, results[i].Overhead, budget))
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,57 @@
+package decorators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBenchmarks(t *testing.T) {
+	scenarios := []BenchScenario{
+		{Name: "cors", Markers: []string{"@CORS(origins=*)"}},
+		{Name: "pair", Markers: []string{"@RequireHeader(X-Bench-ID)", `@Cache(ttl="1m")`}},
+	}
+	results, err := RunBenchmarks(scenarios, BenchOptions{Duration: 20 * time.Millisecond, Concurrency: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, BenchBaseline, results[0].Scenario)
+	for _, result := range results {
+		assert.Positive(t, result.Requests, result.Scenario)
+		assert.Positive(t, result.Latency, result.Scenario)
+		assert.GreaterOrEqual(t, result.Overhead, time.Duration(0))
+	}
+	assert.Equal(t, "pair", results[2].Scenario)
+
+	_, err = RunBenchmarks([]BenchScenario{{Name: "bad", Markers: []string{"@RateLimit(limit=abc)"}}}, BenchOptions{})
+	assert.ErrorContains(t, err, "scenario bad: @RateLimit: limit: expected an integer")
+	_, err = RunBenchmarks([]BenchScenario{{Name: "doc", Markers: []string{"@Summary(x)"}}}, BenchOptions{})
+	assert.ErrorContains(t, err, "does not create middleware")
+}
+
+func TestCheckBenchBudgets(t *testing.T) {
+	results := []BenchResult{
+		{Scenario: BenchBaseline},
+		{Scenario: "Cache", Overhead: 30 * time.Microsecond},
+		{Scenario: "CORS", Overhead: 2 * time.Microsecond},
+	}
+	violations, err := CheckBenchBudgets(results, map[string]string{"Cache": "20us", "CORS": "5µs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cache: overhead 30µs exceeds budget 20µs"}, violations)
+	assert.True(t, results[1].Exceeded)
+	assert.False(t, results[2].Exceeded)
+	assert.Equal(t, 5*time.Microsecond, results[2].Budget)
+
+	_, err = CheckBenchBudgets(results, map[string]string{"Cache": "fast"})
+	assert.ErrorContains(t, err, "bench.budgets.Cache")
+	assert.ErrorContains(t, BenchConfig{Budgets: map[string]string{"Cache": "fast"}}.validate(), "invalid bench.budgets.Cache")
+}
+
+func TestBenchScenariosFromConfig(t *testing.T) {
+	scenarios := BenchScenariosFromConfig(BenchConfig{Scenarios: map[string][]string{"api": {"@CORS()"}}})
+	defaults := DefaultBenchScenarios()
+	require.Len(t, scenarios, len(defaults)+1)
+	assert.Equal(t, "chain", defaults[len(defaults)-1].Name)
+	assert.Equal(t, BenchScenario{Name: "api", Markers: []string{"@CORS()"}}, scenarios[len(scenarios)-1])
+}
@@ -0,0 +1,208 @@
+package decorators
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultBodyCaptureLimit bytes of each body kept by the body capture layer
+const DefaultBodyCaptureLimit = 64 << 10
+
+// bodyCaptureKey context key of the request's BodyCapture
+const bodyCaptureKey = "deco.bodyCapture"
+
+var (
+	bodyCaptureLimit    int64 = DefaultBodyCaptureLimit
+	bodyCaptureRedactor *Redactor
+	bodyCaptureMutex    sync.RWMutex
+)
+
+// BodyCapture request and response bodies of one request, tee'd while they are read and written.
+//
+// It is the single place where features that need bodies (logging, auditing, tracing
+// attributes, recording) get them, so the body is never re-read or buffered twice.
+// Only the first limit bytes are kept; bodies are never read ahead of the handler,
+// so streaming requests and responses are unaffected.
+type BodyCapture struct {
+	request  cappedBuffer
+	response cappedBuffer
+	redactor *Redactor
+}
+
+// cappedBuffer keeps the first limit bytes written to it
+type cappedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int64
+	total     int64
+	truncated bool
+}
+
+func (b *cappedBuffer) write(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.total += int64(len(p))
+	remaining := b.limit - int64(b.buf.Len())
+	if remaining <= 0 {
+		b.truncated = b.truncated || len(p) > 0
+		return
+	}
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+		b.truncated = true
+	}
+	b.buf.Write(p)
+}
+
+func (b *cappedBuffer) bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *cappedBuffer) state() (int64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total, b.truncated
+}
+
+// ConfigureBodyCapture applies the body_capture configuration to the capture layer
+func ConfigureBodyCapture(config BodyCaptureConfig) error {
+	limit := int64(DefaultBodyCaptureLimit)
+	if config.MaxBytes != "" {
+		size, err := ParseByteSize(config.MaxBytes)
+		if err != nil {
+			return fmt.Errorf("invalid body_capture.max_bytes: %v", err)
+		}
+		limit = size
+	}
+
+	redactor, err := NewRedactor(config.Redact, config.Replacement)
+	if err != nil {
+		return err
+	}
+
+	bodyCaptureMutex.Lock()
+	defer bodyCaptureMutex.Unlock()
+	bodyCaptureLimit = limit
+	bodyCaptureRedactor = redactor
+	return nil
+}
+
+// DefaultRedactor returns the redactor configured by body_capture.redact (nil when unset)
+func DefaultRedactor() *Redactor {
+	bodyCaptureMutex.RLock()
+	defer bodyCaptureMutex.RUnlock()
+	return bodyCaptureRedactor
+}
+
+// CaptureBodies installs the body capture layer for the request, or returns the one already installed
+func CaptureBodies(c *gin.Context) *BodyCapture {
+	if existing, ok := c.Get(bodyCaptureKey); ok {
+		if capture, ok := existing.(*BodyCapture); ok {
+			return capture
+		}
+	}
+
+	bodyCaptureMutex.RLock()
+	limit, redactor := bodyCaptureLimit, bodyCaptureRedactor
+	bodyCaptureMutex.RUnlock()
+
+	capture := &BodyCapture{
+		request:  cappedBuffer{limit: limit},
+		response: cappedBuffer{limit: limit},
+		redactor: redactor,
+	}
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		c.Request.Body = &teeReadCloser{ReadCloser: c.Request.Body, capture: &capture.request}
+	}
+	c.Writer = &teeResponseWriter{ResponseWriter: c.Writer, capture: &capture.response}
+	c.Set(bodyCaptureKey, capture)
+	return capture
+}
+
+// GetBodyCapture returns the request's capture, if a middleware installed one
+func GetBodyCapture(c *gin.Context) (*BodyCapture, bool) {
+	value, ok := c.Get(bodyCaptureKey)
+	if !ok {
+		return nil, false
+	}
+	capture, ok := value.(*BodyCapture)
+	return capture, ok
+}
+
+// BodyCaptureMiddleware installs the body capture layer for the rest of the chain
+func BodyCaptureMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		CaptureBodies(c)
+		c.Next()
+	}
+}
+
+// RequestBody bytes of the request body read so far (up to the limit)
+func (b *BodyCapture) RequestBody() []byte {
+	return b.request.bytes()
+}
+
+// ResponseBody bytes of the response body written so far (up to the limit)
+func (b *BodyCapture) ResponseBody() []byte {
+	return b.response.bytes()
+}
+
+// RedactedRequestBody request body with the configured redaction rules and privacy policy applied
+func (b *BodyCapture) RedactedRequestBody() []byte {
+	return GetPIIPolicy().ScrubJSON(b.redactor.RedactJSON(b.RequestBody()))
+}
+
+// RedactedResponseBody response body with the configured redaction rules and privacy policy applied
+func (b *BodyCapture) RedactedResponseBody() []byte {
+	return GetPIIPolicy().ScrubJSON(b.redactor.RedactJSON(b.ResponseBody()))
+}
+
+// RequestSize total request bytes read and whether the captured body was truncated
+func (b *BodyCapture) RequestSize() (int64, bool) {
+	return b.request.state()
+}
+
+// ResponseSize total response bytes written and whether the captured body was truncated
+func (b *BodyCapture) ResponseSize() (int64, bool) {
+	return b.response.state()
+}
+
+// teeReadCloser copies what the handler reads from the request body
+type teeReadCloser struct {
+	io.ReadCloser
+	capture *cappedBuffer
+}
+
+func (t *teeReadCloser) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if n > 0 {
+		t.capture.write(p[:n])
+	}
+	return n, err
+}
+
+// teeResponseWriter copies what the handler writes to the response; Flush and Hijack pass through
+type teeResponseWriter struct {
+	gin.ResponseWriter
+	capture *cappedBuffer
+}
+
+func (w *teeResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	if n > 0 {
+		w.capture.write(data[:n])
+	}
+	return n, err
+}
+
+func (w *teeResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,108 @@
+package decorators
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func withBodyCaptureConfig(t *testing.T, config BodyCaptureConfig) {
+	bodyCaptureMutex.RLock()
+	savedLimit, savedRedactor := bodyCaptureLimit, bodyCaptureRedactor
+	bodyCaptureMutex.RUnlock()
+	t.Cleanup(func() {
+		bodyCaptureMutex.Lock()
+		bodyCaptureLimit, bodyCaptureRedactor = savedLimit, savedRedactor
+		bodyCaptureMutex.Unlock()
+	})
+	assert.NoError(t, ConfigureBodyCapture(config))
+}
+
+func TestCaptureBodies(t *testing.T) {
+	withBodyCaptureConfig(t, BodyCaptureConfig{Redact: []string{"password"}})
+
+	var capture *BodyCapture
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/login", BodyCaptureMiddleware(), func(c *gin.Context) {
+		// A second consumer shares the same capture
+		capture = CaptureBodies(c)
+		data, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "application/json", data)
+	})
+
+	w := httptest.NewRecorder()
+	body := `{"user":"ana","password":"s3cret"}`
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body)))
+
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, body, string(capture.RequestBody()))
+	assert.Equal(t, body, string(capture.ResponseBody()))
+	assert.Equal(t, `{"password":"[REDACTED]","user":"ana"}`, string(capture.RedactedRequestBody()))
+	assert.Equal(t, `{"password":"[REDACTED]","user":"ana"}`, string(capture.RedactedResponseBody()))
+
+	total, truncated := capture.RequestSize()
+	assert.Equal(t, int64(len(body)), total)
+	assert.False(t, truncated)
+}
+
+func TestCaptureBodies_SizeCap(t *testing.T) {
+	withBodyCaptureConfig(t, BodyCaptureConfig{MaxBytes: "8B"})
+
+	var capture *BodyCapture
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/upload", BodyCaptureMiddleware(), func(c *gin.Context) {
+		capture, _ = GetBodyCapture(c)
+		data, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%d", len(data))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 100))))
+
+	// The handler still sees the full body; only the capture is capped
+	assert.Equal(t, "100", w.Body.String())
+	assert.Equal(t, "xxxxxxxx", string(capture.RequestBody()))
+	total, truncated := capture.RequestSize()
+	assert.Equal(t, int64(100), total)
+	assert.True(t, truncated)
+}
+
+func TestCaptureBodies_Streaming(t *testing.T) {
+	withBodyCaptureConfig(t, BodyCaptureConfig{})
+
+	var capture *BodyCapture
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", BodyCaptureMiddleware(), func(c *gin.Context) {
+		capture, _ = GetBodyCapture(c)
+		_, _ = c.Writer.WriteString("data: 1\n\n")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("data: 2\n\n")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", http.NoBody))
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", w.Body.String())
+	assert.Equal(t, w.Body.String(), string(capture.ResponseBody()))
+	assert.Empty(t, capture.RequestBody())
+}
+
+func TestConfigureBodyCapture_Invalid(t *testing.T) {
+	withBodyCaptureConfig(t, BodyCaptureConfig{})
+
+	assert.Error(t, ConfigureBodyCapture(BodyCaptureConfig{MaxBytes: "lots"}))
+	assert.Error(t, ConfigureBodyCapture(BodyCaptureConfig{Redact: []string{"a..b"}}))
+
+	config := DefaultConfig()
+	config.Capture.Redact = []string{"$"}
+	assert.Error(t, config.Validate())
+}
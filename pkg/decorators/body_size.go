@@ -0,0 +1,183 @@
+package decorators
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodySize request body limit of the routes without @MaxBodySize unless limits.max_body_size is set
+const DefaultMaxBodySize = "10MB"
+
+// bodyLimitKey context key of the limited body, so @MaxBodySize replaces the limit of limits.max_body_size
+const bodyLimitKey = "deco.body_limit"
+
+// parseMaxBodySizeArgs parses @MaxBodySize arguments: a positional size ("2MB") or size=
+func parseMaxBodySizeArgs(args []string) (int64, error) {
+	var limit int64
+	for _, arg := range args {
+		if arg = strings.TrimSpace(arg); arg == "" {
+			continue
+		}
+		size, err := ParseByteSize(strings.TrimPrefix(arg, "size="))
+		if err != nil {
+			return 0, fmt.Errorf("@MaxBodySize: %v", err)
+		}
+		limit = size
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("@MaxBodySize requires a positive size such as \"2MB\"")
+	}
+	return limit, nil
+}
+
+// limitedBody request body wrapped with http.MaxBytesReader; exceeding the limit answers 413 and
+// discards what the handler writes after, since it only sees a read error
+type limitedBody struct {
+	c        *gin.Context
+	original io.ReadCloser
+	reader   io.ReadCloser
+	limit    int64
+	writer   gin.ResponseWriter // c.Writer before the rejection
+	rejected bool
+}
+
+// setLimit replaces the limit; only called before the body is read
+func (b *limitedBody) setLimit(limit int64) {
+	b.limit = limit
+	b.reader = http.MaxBytesReader(b.c.Writer, b.original, limit)
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.rejected {
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+	if b.c.Request.ContentLength > b.limit {
+		b.reject()
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+	n, err := b.reader.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.reject()
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.original.Close()
+}
+
+// reject sends the 413 and silences the response of the handler
+func (b *limitedBody) reject() {
+	b.rejected = true
+	if b.c.Writer.Written() {
+		return
+	}
+	rejectBodyTooLarge(b.c, b.limit)
+	b.writer = b.c.Writer
+	b.c.Writer = &discardingWriter{ResponseWriter: b.c.Writer}
+}
+
+// rejectBodyTooLarge answers the structured 413
+func rejectBodyTooLarge(c *gin.Context, limit int64) {
+	LogVerbose("⚠️  Request body of %s %s rejected: exceeds %d bytes", c.Request.Method, getEndpointPattern(c), limit)
+	c.Header("Connection", "close")
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":     "request_too_large",
+		"message":   fmt.Sprintf("Request body exceeds the limit of %d bytes", limit),
+		"max_bytes": limit,
+	})
+}
+
+// discardingWriter drops the writes of a handler whose request was already answered with 413
+type discardingWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *discardingWriter) WriteHeader(int)                   {}
+func (w *discardingWriter) WriteHeaderNow()                   {}
+func (w *discardingWriter) Write(data []byte) (int, error)    { return len(data), nil }
+func (w *discardingWriter) WriteString(s string) (int, error) { return len(s), nil }
+
+// limitBody wraps the request body, or replaces the limit of the body already wrapped
+func limitBody(c *gin.Context, limit int64) {
+	if value, exists := c.Get(bodyLimitKey); exists {
+		value.(*limitedBody).setLimit(limit)
+		c.Next()
+		return
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		c.Next()
+		return
+	}
+
+	body := &limitedBody{c: c, original: c.Request.Body}
+	body.setLimit(limit)
+	c.Request.Body = body
+	c.Set(bodyLimitKey, body)
+	c.Next()
+	if body.writer != nil {
+		c.Writer = body.writer
+	}
+}
+
+// MaxBodySizeMiddleware caps the request body of a route (@MaxBodySize): a larger Content-Length is
+// answered with 413 before the handler runs, and reads past the limit end with 413 too
+func MaxBodySizeMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			rejectBodyTooLarge(c, limit)
+			return
+		}
+		limitBody(c, limit)
+	}
+}
+
+// DefaultMaxBodySizeMiddleware applies limits.max_body_size to every route; the Content-Length is checked
+// on the first read, so a later @MaxBodySize can still raise the limit of its route
+func DefaultMaxBodySizeMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		limitBody(c, limit)
+	}
+}
+
+// createMaxBodySizeMiddleware creates request body size cap middleware
+func createMaxBodySizeMiddleware(args []string) gin.HandlerFunc {
+	limit, err := parseMaxBodySizeArgs(args)
+	if err != nil {
+		// Rejected during generation; invalid hand-written calls keep the global limit
+		LogSilent("⚠️  %v", err)
+	}
+	return MaxBodySizeMiddleware(limit)
+}
+
+// maxBodySize limit of limits.max_body_size, 0 when disabled
+func (c LimitsConfig) maxBodySize() (int64, error) {
+	if c.MaxBodySize == "" {
+		return 0, nil
+	}
+	limit, err := ParseByteSize(c.MaxBodySize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid limits.max_body_size: %v", err)
+	}
+	return limit, nil
+}
+
+// validate checks limits
+func (c LimitsConfig) validate() error {
+	_, err := c.maxBodySize()
+	return err
+}
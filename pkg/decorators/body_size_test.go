@@ -0,0 +1,79 @@
+package decorators
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxBodySizeArgs(t *testing.T) {
+	limit, err := parseMaxBodySizeArgs([]string{`"2MB"`})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2<<20), limit)
+	limit, err = parseMaxBodySizeArgs([]string{"size=512"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(512), limit)
+
+	_, err = parseMaxBodySizeArgs(nil)
+	assert.Error(t, err)
+	_, err = parseMaxBodySizeArgs([]string{"2 lots"})
+	assert.Error(t, err)
+}
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(DefaultMaxBodySizeMiddleware(16))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	}
+	engine.POST("/small", echo)
+	engine.POST("/upload", createMaxBodySizeMiddleware([]string{"64"}), echo)
+
+	post := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1 // unknown length: only the reader can enforce the limit
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "10", post("/small", strings.Repeat("a", 10), false).Body.String())
+
+	w := post("/small", strings.Repeat("a", 20), false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "the global limit applies")
+	assert.JSONEq(t, `{"error":"request_too_large","message":"Request body exceeds the limit of 16 bytes","max_bytes":16}`, w.Body.String(),
+		"the 400 of the handler is discarded")
+
+	w = post("/small", strings.Repeat("a", 20), true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	assert.Equal(t, "40", post("/upload", strings.Repeat("a", 40), false).Body.String(), "@MaxBodySize raises the limit of its route")
+	assert.Equal(t, "40", post("/upload", strings.Repeat("a", 40), true).Body.String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("/upload", strings.Repeat("a", 100), false).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("/upload", strings.Repeat("a", 100), true).Code)
+}
+
+func TestLimitsConfig(t *testing.T) {
+	limit, err := DefaultConfig().Limits.maxBodySize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(10<<20), limit)
+
+	limit, err = LimitsConfig{MaxBodySize: "0"}.maxBodySize()
+	require.NoError(t, err)
+	assert.Zero(t, limit)
+	assert.Error(t, LimitsConfig{MaxBodySize: "big"}.validate())
+}
@@ -0,0 +1,6 @@
+//go:build !prod
+
+package decorators
+
+// prodBuild reports whether the binary was built with -tags prod
+const prodBuild = false
@@ -3,13 +3,14 @@ package decorators
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/json"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -61,13 +62,63 @@ type RedisCache struct {
 	stats  CacheStats
 }
 
+// CacheBackendFactory creates the store of a cache backend; config carries the route or global cache settings
+type CacheBackendFactory func(config *CacheConfig) (CacheStore, error)
+
+var (
+	cacheBackends = map[string]CacheBackendFactory{
+		"memory": func(config *CacheConfig) (CacheStore, error) {
+			return NewMemoryCache(config.MaxSize), nil
+		},
+		"redis": func(_ *CacheConfig) (CacheStore, error) {
+			return NewRedisCache(GetRedisConfig(), "gin_decorators:")
+		},
+	}
+	cacheBackendsMux sync.RWMutex
+)
+
+// RegisterCacheBackend registers a cache store (Memcached, BoltDB, ...) usable as cache.type or
+// per route with @Cache(backend="name"). Register it before the routes are set up.
+func RegisterCacheBackend(name string, factory CacheBackendFactory) {
+	cacheBackendsMux.Lock()
+	defer cacheBackendsMux.Unlock()
+	cacheBackends[name] = factory
+}
+
+// getCacheBackendFactory returns the factory registered under name
+func getCacheBackendFactory(name string) (CacheBackendFactory, bool) {
+	cacheBackendsMux.RLock()
+	defer cacheBackendsMux.RUnlock()
+	factory, exists := cacheBackends[name]
+	return factory, exists
+}
+
+// newCacheStore creates the store of config.Type; an unknown or failing backend falls back to memory
+func newCacheStore(config *CacheConfig) CacheStore {
+	backend := config.Type
+	if backend == "" {
+		backend = "memory"
+	}
+	factory, exists := getCacheBackendFactory(backend)
+	if !exists {
+		LogSilent("⚠️  Unknown cache backend '%s', using memory", backend)
+		return NewMemoryCache(config.MaxSize)
+	}
+	store, err := factory(config)
+	if err != nil || store == nil {
+		LogVerbose("⚠️  Cache backend %s unavailable (%v), using memory", backend, err)
+		return NewMemoryCache(config.MaxSize)
+	}
+	return store
+}
+
 // CacheKeyFunc function to generate cache key
 type CacheKeyFunc func(c *gin.Context) string
 
 // Default cache key generation functions
 var (
 	URLCacheKey = func(c *gin.Context) string {
-		return fmt.Sprintf("cache:url:%s:%s", c.Request.Method, c.Request.URL.Path)
+		return joinKey("cache:url:", c.Request.Method, ":", c.Request.URL.Path)
 	}
 
 	UserURLCacheKey = func(c *gin.Context) string {
@@ -75,7 +126,7 @@ var (
 		if userID == "" {
 			userID = "anonymous"
 		}
-		return fmt.Sprintf("cache:user:%s:url:%s:%s", userID, c.Request.Method, c.Request.URL.Path)
+		return joinKey("cache:user:", userID, ":url:", c.Request.Method, ":", c.Request.URL.Path)
 	}
 
 	EndpointCacheKey = func(c *gin.Context) string {
@@ -83,10 +134,31 @@ var (
 		if path == "" {
 			path = c.Request.URL.Path
 		}
-		return fmt.Sprintf("cache:endpoint:%s:%s", c.Request.Method, path)
+		return joinKey("cache:endpoint:", c.Request.Method, ":", path)
 	}
 )
 
+// keyBuffers buffers reused by the cache and rate limit key builders
+var keyBuffers = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 128)
+		return &buf
+	},
+}
+
+// joinKey concatenates the parts of a key in a pooled buffer; only the key itself is allocated
+func joinKey(parts ...string) string {
+	buf := keyBuffers.Get().(*[]byte)
+	key := (*buf)[:0]
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	result := string(key)
+	*buf = key
+	keyBuffers.Put(buf)
+	return result
+}
+
 // NewMemoryCache creates a new in-memory cache
 func NewMemoryCache(maxSize int) *MemoryCache {
 	return &MemoryCache{
@@ -234,6 +306,23 @@ func (m *MemoryCache) updateHitRate() {
 	}
 }
 
+// redisSettings Redis used by the middlewares (cache, rate limiting, @Dedupe), set by ConfigureRedis
+var redisSettings atomic.Pointer[RedisConfig]
+
+// ConfigureRedis sets the Redis the middlewares connect to; before it they use the default
+// localhost:6379. Middlewares created earlier keep their connection.
+func ConfigureRedis(config RedisConfig) {
+	redisSettings.Store(&config)
+}
+
+// GetRedisConfig Redis the middlewares connect to: the one of ConfigureRedis, or the default one
+func GetRedisConfig() RedisConfig {
+	if config := redisSettings.Load(); config != nil {
+		return *config
+	}
+	return DefaultConfig().Redis
+}
+
 // NewRedisCache creates a new Redis cache
 func NewRedisCache(config RedisConfig, prefix string) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
@@ -273,7 +362,7 @@ func (r *RedisCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
 	}
 
 	var entry CacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
+	if err := jsonUnmarshal(data, &entry); err != nil {
 		return nil, fmt.Errorf("error deserializing cache: %v", err)
 	}
 
@@ -295,7 +384,7 @@ func (r *RedisCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
 func (r *RedisCache) Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error {
 	fullKey := r.prefix + key
 
-	data, err := json.Marshal(entry)
+	data, err := jsonMarshal(entry)
 	if err != nil {
 		return fmt.Errorf("error serializing cache: %v", err)
 	}
@@ -362,6 +451,11 @@ func (r *RedisCache) Stats() CacheStats {
 	return stats
 }
 
+// Close closes the Redis connection
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
+
 // updateHitRate updates hit rate (Redis)
 func (r *RedisCache) updateHitRate() {
 	total := r.stats.Hits + r.stats.Misses
@@ -372,27 +466,24 @@ func (r *RedisCache) updateHitRate() {
 
 // CacheMiddleware creates cache middleware
 func CacheMiddleware(config *CacheConfig, keyGen CacheKeyFunc) gin.HandlerFunc {
-	var store CacheStore
-	var err error
-
 	// Choose implementation based on configuration
-	if config.Type == "redis" {
-		redisConfig := DefaultConfig().Redis
-		store, err = NewRedisCache(redisConfig, "gin_decorators:")
-		if err != nil {
-			// Fallback to memory if Redis fails
-			store = NewMemoryCache(config.MaxSize)
-		}
-	} else {
-		store = NewMemoryCache(config.MaxSize)
+	store := newCacheStore(config)
+	if config.Encrypt {
+		store = NewEncryptedCacheStore(store, config.EncryptionKey)
 	}
+	registerAdminCacheStore(store)
 
 	// Parse default TTL
-	defaultTTL, err := time.ParseDuration(config.DefaultTTL)
+	defaultTTL, err := ParseDurationLiteral(config.DefaultTTL)
 	if err != nil {
 		defaultTTL = 5 * time.Minute
 	}
 
+	// Key settings of the route, applied over cache.keys
+	routeKeys := newCacheKeyPolicy(config.Keys)
+	tracker := newCacheKeyTracker()
+	routeETag := config.ETag
+
 	return func(c *gin.Context) {
 		// Only cache GET methods by default
 		if c.Request.Method != "GET" {
@@ -400,19 +491,53 @@ func CacheMiddleware(config *CacheConfig, keyGen CacheKeyFunc) gin.HandlerFunc {
 			return
 		}
 
-		// Generate cache key
-		key := keyGen(c)
+		// Generate the canonical cache key: only the allowlisted headers vary it
+		rules := currentCacheKeyPolicy().rules(routeKeys, config.Keys)
+		key := canonicalCacheKey(keyGen(c), rules.vary, c.Request.Header)
+		// Versions selected by header or query share the path, so the version is part of the key
+		if version := APIVersion(c); version != "" {
+			key = joinKey(key, ":version=", version)
+		}
+		if len(key) > rules.maxLength {
+			rejectCacheKey(c, CacheKeyTooLong)
+			return
+		}
+		ctx := c.Request.Context()
+		key, err := rules.hasher.storeKey(ctx, key)
+		if err != nil {
+			LogVerbose("⚠️  Cache key not hashed, cache bypassed: %v", err)
+			c.Header("X-Cache", "BYPASS")
+			c.Next()
+			return
+		}
+		if !tracker.admit(cacheRoute(c), key, rules.maxKeys, defaultTTL) {
+			rejectCacheKey(c, CacheKeyCardinality)
+			return
+		}
+		if len(rules.vary) > 0 {
+			c.Header("Vary", strings.Join(rules.vary, ", "))
+		}
 
 		// Try to retrieve from cache
-		ctx := c.Request.Context()
+		etagMode := resolveCacheETagMode(routeETag)
 		entry, err := store.Get(ctx, key)
 		if err == nil && entry != nil {
-			// Cache hit - return cached response
+			// Cache hit - return cached response, or 304 when the client holds it
 			for headerKey, headerValue := range entry.Headers {
 				c.Header(headerKey, headerValue)
 			}
 			c.Header("X-Cache", "HIT")
 			c.Header("X-Cache-Key", generateCacheKeyHash(key))
+			if etagMode != CacheETagOff {
+				header := c.Writer.Header()
+				if header.Get("Etag") == "" { // entries cached before the validators
+					header.Set("Etag", computeETag(entry.Data, etagMode))
+				}
+				if notModified(c.Request, header.Get("Etag"), header.Get("Last-Modified")) {
+					respondNotModified(c)
+					return
+				}
+			}
 
 			c.Data(entry.Status, c.GetHeader("Content-Type"), entry.Data)
 			c.Abort()
@@ -423,21 +548,41 @@ func CacheMiddleware(config *CacheConfig, keyGen CacheKeyFunc) gin.HandlerFunc {
 		c.Header("X-Cache", "MISS")
 		c.Header("X-Cache-Key", generateCacheKeyHash(key))
 
-		// Capture response
-		writer := &responseWriter{
-			ResponseWriter: c.Writer,
-			body:           make([]byte, 0),
-			headers:        make(map[string]string),
-		}
+		// Capture response; with validators it is buffered so the ETag can precede the body
+		writer := cacheWriters.Get().(*responseWriter)
+		writer.ResponseWriter = c.Writer
+		writer.buffer = etagMode != CacheETagOff
 		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			writer.ResponseWriter = nil
+			writer.body = writer.body[:0]
+			writer.status = 0
+			writer.buffer = false
+			cacheWriters.Put(writer)
+		}()
 
 		c.Next()
 
+		status := writer.status
+		if writer.buffer {
+			status = writer.ResponseWriter.Status()
+			if status >= 200 && status < 300 {
+				setValidators(writer.Header(), writer.body, etagMode)
+			}
+		}
+
 		// Store in cache if response is successful
-		if writer.status >= 200 && writer.status < 300 {
+		if status >= 200 && status < 300 {
+			headers := make(map[string]string, len(writer.Header()))
+			for headerKey, values := range writer.Header() {
+				if len(values) > 0 {
+					headers[headerKey] = values[0]
+				}
+			}
 			entry := &CacheEntry{
-				Data:    writer.body,
-				Headers: writer.headers,
+				Data:    append([]byte(nil), writer.body...), // the buffer goes back to the pool
+				Headers: headers,
 				Status:  writer.status,
 			}
 
@@ -446,36 +591,73 @@ func CacheMiddleware(config *CacheConfig, keyGen CacheKeyFunc) gin.HandlerFunc {
 				log.Printf("Failed to store cache entry: %v", err)
 			}
 		}
+		if writer.buffer {
+			writer.flush(c.Request, status)
+		}
 	}
 }
 
 // responseWriter wrapper to capture response
 type responseWriter struct {
 	gin.ResponseWriter
-	body    []byte
-	headers map[string]string
-	status  int
+	body   []byte
+	status int
+	buffer bool // hold the response until flush instead of writing through
+}
+
+// cacheWriters response capture writers reused across cache misses
+var cacheWriters = sync.Pool{
+	New: func() interface{} {
+		return &responseWriter{body: make([]byte, 0, 1024)}
+	},
 }
 
 func (w *responseWriter) Write(data []byte) (int, error) {
 	w.body = append(w.body, data...)
+	if w.buffer {
+		return len(data), nil
+	}
 	return w.ResponseWriter.Write(data)
 }
 
+func (w *responseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeader records the status; gin only sends it with the first write or WriteHeaderNow
 func (w *responseWriter) WriteHeader(statusCode int) {
 	w.status = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
-func (w *responseWriter) Header() http.Header {
-	// Capture important headers
-	header := w.ResponseWriter.Header()
-	for key, values := range header {
-		if len(values) > 0 {
-			w.headers[key] = values[0]
+func (w *responseWriter) WriteHeaderNow() {
+	if !w.buffer {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *responseWriter) Flush() {
+	if !w.buffer {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// flush sends the buffered response, or a 304 without body when the client already holds it
+func (w *responseWriter) flush(request *http.Request, status int) {
+	header := w.Header()
+	if status >= 200 && status < 300 && notModified(request, header.Get("Etag"), header.Get("Last-Modified")) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	if len(w.body) > 0 {
+		if _, err := w.ResponseWriter.Write(w.body); err != nil {
+			LogVerbose("⚠️  Cached response not written: %v", err)
 		}
 	}
-	return header
 }
 
 // CacheByURL cache middleware by URL
@@ -504,11 +686,21 @@ func CustomCache(ttl time.Duration, keyGen CacheKeyFunc, cacheType string) gin.H
 	return CacheMiddleware(config, keyGen)
 }
 
-// ParseCacheArgs parses @Cache decorator arguments
-func ParseCacheArgs(args []string) (time.Duration, string, CacheKeyFunc) {
-	duration := 5 * time.Minute // default
-	cacheType := "memory"       // default
-	keyGen := URLCacheKey       // default
+// CacheOptions @Cache arguments, folded into the generated code as a literal
+type CacheOptions struct {
+	TTL           time.Duration
+	Backend       string // memory, redis or a RegisterCacheBackend name
+	Key           string // url, user or endpoint
+	Encrypt       bool
+	EncryptionKey string
+	Vary          []string // request headers in the key, over cache.keys.vary_headers
+	MaxKeys       int      // live keys of the route, over cache.keys.max_keys_per_route
+	ETag          string   // strong, weak or off, over cache.etag
+}
+
+// parseCacheOptions parses @Cache decorator arguments; invalid values keep the defaults
+func parseCacheOptions(args []string) CacheOptions {
+	options := CacheOptions{TTL: 5 * time.Minute, Backend: "memory", Key: "url"}
 
 	for _, arg := range args {
 		if strings.Contains(arg, "=") {
@@ -518,31 +710,71 @@ func ParseCacheArgs(args []string) (time.Duration, string, CacheKeyFunc) {
 
 			switch key {
 			case "duration", "ttl":
-				if parsed, err := time.ParseDuration(value); err == nil {
-					duration = parsed
+				if parsed, err := ParseDurationLiteral(value); err == nil {
+					options.TTL = parsed
 				}
-			case "type":
-				cacheType = value
+			case "type", "backend":
+				options.Backend = value
 			case "key", "by":
 				switch value {
-				case "url":
-					keyGen = URLCacheKey
-				case "user":
-					keyGen = UserURLCacheKey
-				case "endpoint":
-					keyGen = EndpointCacheKey
+				case "url", "user", "endpoint":
+					options.Key = value
+				}
+			case "vary":
+				options.Vary = MarkerList(parts[1])
+			case "max_keys":
+				if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+					options.MaxKeys = parsed
+				}
+			case "etag":
+				if value != "" && validateCacheETagMode(value) == nil {
+					options.ETag = value
 				}
 			}
 		}
 	}
+	options.Encrypt, options.EncryptionKey, _ = parseCacheEncryptionArgs(args)
+
+	return options
+}
+
+// keyFunc key function of the Key option
+func (o CacheOptions) keyFunc() CacheKeyFunc {
+	switch o.Key {
+	case "user":
+		return UserURLCacheKey
+	case "endpoint":
+		return EndpointCacheKey
+	default:
+		return URLCacheKey
+	}
+}
+
+// ParseCacheArgs parses @Cache decorator arguments
+func ParseCacheArgs(args []string) (time.Duration, string, CacheKeyFunc) {
+	options := parseCacheOptions(args)
+	return options.TTL, options.Backend, options.keyFunc()
+}
+
+// CacheWith creates the @Cache middleware from arguments already parsed, as emitted by the generator
+func CacheWith(options CacheOptions) gin.HandlerFunc {
+	config := &CacheConfig{
+		Type:          options.Backend,
+		DefaultTTL:    options.TTL.String(),
+		MaxSize:       1000,
+		Encrypt:       options.Encrypt,
+		EncryptionKey: options.EncryptionKey,
+		Keys:          CacheKeyConfig{VaryHeaders: options.Vary, MaxKeysPerRoute: options.MaxKeys},
+		ETag:          options.ETag,
+	}
 
-	return duration, cacheType, keyGen
+	return CacheMiddleware(config, options.keyFunc())
 }
 
-// generateCacheKeyHash generates MD5 hash of the key for headers
+// generateCacheKeyHash generates a short SHA-256 hash of the key for headers
 func generateCacheKeyHash(key string) string {
 	hash := sha256.Sum256([]byte(key))
-	return fmt.Sprintf("%x", hash)[:8] // First 8 characters
+	return hex.EncodeToString(hash[:4]) // First 8 characters
 }
 
 // CacheStatsHandler handler for cache statistics
@@ -0,0 +1,105 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCacheEncryptionKey secret holding the AES key of encrypted cache entries (16, 24 or 32 bytes)
+const DefaultCacheEncryptionKey = "cache-encryption-key"
+
+// EncryptedCacheStore encrypts cache entries at rest with AES-GCM before handing them to the wrapped store.
+// The whole entry (body, headers, status) is sealed and bound to its cache key, so entries cannot be
+// swapped between keys; entries that fail to decrypt are treated as misses.
+type EncryptedCacheStore struct {
+	store  CacheStore
+	cipher *secretCipher
+}
+
+// NewEncryptedCacheStore wraps a store; the key is read from the secret provider on first use
+func NewEncryptedCacheStore(store CacheStore, keyName string) *EncryptedCacheStore {
+	if keyName == "" {
+		keyName = DefaultCacheEncryptionKey
+	}
+	return &EncryptedCacheStore{store: store, cipher: &secretCipher{keyName: keyName}}
+}
+
+// Get reads and decrypts an entry
+func (s *EncryptedCacheStore) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	sealed, err := s.store.Get(ctx, key)
+	if err != nil || sealed == nil {
+		return nil, err
+	}
+	plaintext, err := s.cipher.open(ctx, sealed.Data, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting cache entry: %v", err)
+	}
+	var entry CacheEntry
+	if err := jsonUnmarshal(plaintext, &entry); err != nil {
+		return nil, fmt.Errorf("error deserializing cache: %v", err)
+	}
+	return &entry, nil
+}
+
+// Set encrypts and stores an entry
+func (s *EncryptedCacheStore) Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error {
+	plaintext, err := jsonMarshal(entry)
+	if err != nil {
+		return fmt.Errorf("error serializing cache: %v", err)
+	}
+	data, err := s.cipher.seal(ctx, plaintext, []byte(key))
+	if err != nil {
+		return fmt.Errorf("cache encryption key: %v", err)
+	}
+	return s.store.Set(ctx, key, &CacheEntry{Data: data, ExpiresAt: time.Now().Add(ttl)}, ttl)
+}
+
+// Delete removes an entry
+func (s *EncryptedCacheStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+// Clear removes all entries
+func (s *EncryptedCacheStore) Clear(ctx context.Context) error {
+	return s.store.Clear(ctx)
+}
+
+// Stats returns the statistics of the wrapped store
+func (s *EncryptedCacheStore) Stats() CacheStats {
+	return s.store.Stats()
+}
+
+// Close closes the wrapped store when it holds a connection
+func (s *EncryptedCacheStore) Close() error {
+	if closer, ok := s.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// parseCacheEncryptionArgs reads encrypt=true and encryption_key=<secret name> from @Cache arguments
+func parseCacheEncryptionArgs(args []string) (bool, string, error) {
+	encrypt, keyName := false, ""
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "encrypt":
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return false, "", fmt.Errorf("@Cache: invalid encrypt '%s' (expected true or false)", value)
+			}
+			encrypt = parsed
+		case "encryption_key":
+			keyName = value
+		}
+	}
+	return encrypt, keyName, nil
+}
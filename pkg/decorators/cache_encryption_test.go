@@ -0,0 +1,84 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedCacheStore(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"pii-key": []byte("0123456789abcdef0123456789abcdef")})
+	defer UseSecretProvider(nil)
+
+	ctx := context.Background()
+	inner := NewMemoryCache(10)
+	store := NewEncryptedCacheStore(inner, "pii-key")
+
+	entry := &CacheEntry{Data: []byte(`{"ssn":"123-45-6789"}`), Headers: map[string]string{"Content-Type": "application/json"}, Status: 200}
+	require.NoError(t, store.Set(ctx, "/users/1", entry, time.Minute))
+
+	// At rest, neither the body nor the headers are readable
+	sealed, err := inner.Get(ctx, "/users/1")
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(sealed.Data, []byte("123-45-6789")))
+	assert.Empty(t, sealed.Headers)
+
+	got, err := store.Get(ctx, "/users/1")
+	require.NoError(t, err)
+	assert.Equal(t, entry.Data, got.Data)
+	assert.Equal(t, "application/json", got.Headers["Content-Type"])
+	assert.Equal(t, 200, got.Status)
+
+	// Entries are bound to their key
+	require.NoError(t, inner.Set(ctx, "/users/2", sealed, time.Minute))
+	_, err = store.Get(ctx, "/users/2")
+	assert.Error(t, err)
+
+	missing, err := store.Get(ctx, "/users/3")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestEncryptedCacheStore_MissingKey(t *testing.T) {
+	store := NewEncryptedCacheStore(NewMemoryCache(10), "")
+	err := store.Set(context.Background(), "k", &CacheEntry{Data: []byte("x")}, time.Minute)
+	assert.ErrorContains(t, err, "secret 'cache-encryption-key' not found")
+
+	UseSecretProvider(staticSecretProvider{"cache-encryption-key": []byte("short")})
+	defer UseSecretProvider(nil)
+	err = store.Set(context.Background(), "k", &CacheEntry{Data: []byte("x")}, time.Minute)
+	assert.ErrorContains(t, err, "invalid key size")
+}
+
+func TestCacheMarker_Encrypt(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"cache-encryption-key": []byte("0123456789abcdef")})
+	defer UseSecretProvider(nil)
+
+	calls := 0
+	router := gin.New()
+	router.GET("/profile", createCacheMiddleware([]string{"duration=1m", "encrypt=true"}), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"email": "ana@example.com"})
+	})
+
+	for _, expected := range []string{"MISS", "HIT"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/profile", http.NoBody))
+		assert.Equal(t, expected, w.Header().Get("X-Cache"))
+		assert.Contains(t, w.Body.String(), "ana@example.com")
+	}
+	assert.Equal(t, 1, calls)
+
+	encrypt, keyName, err := parseCacheEncryptionArgs([]string{"encrypt=true", "encryption_key=pii-key"})
+	require.NoError(t, err)
+	assert.True(t, encrypt)
+	assert.Equal(t, "pii-key", keyName)
+	assert.Error(t, validateArgumentValues("Cache", []string{"encrypt=maybe"}))
+}
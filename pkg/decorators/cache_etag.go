@@ -0,0 +1,110 @@
+package decorators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag modes of cache.etag and @Cache(etag=...)
+const (
+	CacheETagStrong = "strong" // byte-for-byte validator, "<hash>"
+	CacheETagWeak   = "weak"   // semantic validator, W/"<hash>", for bodies re-encoded downstream (compression)
+	CacheETagOff    = "off"    // no validators, every request gets the full response
+)
+
+// cacheETagMode mode of cache.etag, set by ConfigureCacheETag; empty is strong
+var cacheETagMode atomic.Value
+
+// ConfigureCacheETag sets the ETag mode of the @Cache routes without etag=...: cached responses carry an
+// ETag and a Last-Modified, and If-None-Match / If-Modified-Since are answered with 304
+func ConfigureCacheETag(mode string) error {
+	if err := validateCacheETagMode(mode); err != nil {
+		return err
+	}
+	cacheETagMode.Store(mode)
+	return nil
+}
+
+// resolveCacheETagMode mode of a route: its own, else cache.etag, else strong
+func resolveCacheETagMode(route string) string {
+	if route != "" {
+		return route
+	}
+	if mode, _ := cacheETagMode.Load().(string); mode != "" {
+		return mode
+	}
+	return CacheETagStrong
+}
+
+// validateCacheETagMode checks a mode; empty inherits
+func validateCacheETagMode(mode string) error {
+	switch mode {
+	case "", CacheETagStrong, CacheETagWeak, CacheETagOff:
+		return nil
+	}
+	return fmt.Errorf("invalid cache.etag '%s' (valid: strong, weak, off)", mode)
+}
+
+// computeETag validator of a response body
+func computeETag(body []byte, mode string) string {
+	sum := sha256.Sum256(body)
+	tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	if mode == CacheETagWeak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// setValidators adds the ETag and Last-Modified of a response about to be cached, unless the handler
+// set them
+func setValidators(header http.Header, body []byte, mode string) {
+	if header.Get("Etag") == "" {
+		header.Set("Etag", computeETag(body, mode))
+	}
+	if header.Get("Last-Modified") == "" {
+		header.Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified evaluates the conditional headers of a GET against the validators (RFC 9110 §13.2.2):
+// If-None-Match is compared weakly and, when present, If-Modified-Since is ignored
+func notModified(request *http.Request, etag, lastModified string) bool {
+	if match := request.Header.Get("If-None-Match"); match != "" {
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := request.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.After(since)
+}
+
+// respondNotModified answers 304 with the headers of the cached response but its body metadata
+func respondNotModified(c *gin.Context) {
+	header := c.Writer.Header()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	c.AbortWithStatus(http.StatusNotModified)
+}
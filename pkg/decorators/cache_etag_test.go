@@ -0,0 +1,104 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotModified(t *testing.T) {
+	request := func(header, value string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(header, value)
+		return req
+	}
+	modified := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+
+	assert.True(t, notModified(request("If-None-Match", `"abc"`), `"abc"`, ""))
+	assert.True(t, notModified(request("If-None-Match", `"x", W/"abc"`), `"abc"`, ""), "weak comparison")
+	assert.True(t, notModified(request("If-None-Match", "*"), `W/"abc"`, ""))
+	assert.False(t, notModified(request("If-None-Match", `"other"`), `"abc"`, modified))
+
+	assert.True(t, notModified(request("If-Modified-Since", modified), `"abc"`, modified))
+	later := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	assert.True(t, notModified(request("If-Modified-Since", later), `"abc"`, modified))
+	earlier := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	assert.False(t, notModified(request("If-Modified-Since", earlier), `"abc"`, modified))
+	assert.False(t, notModified(request("If-Modified-Since", "yesterday"), `"abc"`, modified))
+
+	both := request("If-None-Match", `"other"`)
+	both.Header.Set("If-Modified-Since", later)
+	assert.False(t, notModified(both, `"abc"`, modified), "If-None-Match wins over If-Modified-Since")
+}
+
+func TestCacheMiddlewareConditionalRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	calls := 0
+	handler := func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	}
+	engine.GET("/strong/:id", createCacheMiddleware([]string{"ttl=1m"}), handler)
+	engine.GET("/weak/:id", createCacheMiddleware([]string{"ttl=1m", "etag=weak"}), handler)
+	engine.GET("/off/:id", createCacheMiddleware([]string{"ttl=1m", "etag=off"}), handler)
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/strong/1")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.JSONEq(t, `{"id":"1"}`, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	lastModified := first.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	hit := get("/strong/1", "If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, hit.Code)
+	assert.Empty(t, hit.Body.String())
+	assert.Equal(t, etag, hit.Header().Get("ETag"))
+	assert.Equal(t, "HIT", hit.Header().Get("X-Cache"))
+	assert.Equal(t, http.StatusNotModified, get("/strong/1", "If-Modified-Since", lastModified).Code)
+	assert.Equal(t, http.StatusOK, get("/strong/1", "If-None-Match", `"stale"`).Code)
+	assert.Equal(t, 1, calls, "304s do not run the handler")
+
+	miss := get("/strong/2", "If-None-Match", computeETag([]byte(`{"id":"2"}`), CacheETagStrong))
+	assert.Equal(t, http.StatusNotModified, miss.Code, "a miss with the same content still answers 304")
+	assert.Empty(t, miss.Header().Get("Content-Type"))
+
+	weak := get("/weak/1")
+	assert.True(t, strings.HasPrefix(weak.Header().Get("ETag"), `W/"`))
+	assert.Equal(t, http.StatusNotModified, get("/weak/1", "If-None-Match", weak.Header().Get("ETag")).Code)
+
+	off := get("/off/1")
+	assert.Empty(t, off.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, get("/off/1", "If-None-Match", "*").Code, "etag=off opts the route out")
+}
+
+func TestConfigureCacheETag(t *testing.T) {
+	defer func() { _ = ConfigureCacheETag("") }()
+	assert.Equal(t, CacheETagStrong, resolveCacheETagMode(""))
+
+	require.NoError(t, ConfigureCacheETag(CacheETagOff))
+	assert.Equal(t, CacheETagOff, resolveCacheETagMode(""))
+	assert.Equal(t, CacheETagWeak, resolveCacheETagMode(CacheETagWeak), "the route wins")
+
+	assert.Error(t, ConfigureCacheETag("sometimes"))
+	config := DefaultConfig()
+	config.Cache.ETag = "sometimes"
+	assert.Error(t, config.Validate())
+}
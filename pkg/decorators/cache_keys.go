@@ -0,0 +1,275 @@
+package decorators
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultCacheKeyMaxLength canonical keys longer than this are not cached unless cache.keys.max_length is set
+const DefaultCacheKeyMaxLength = 1024
+
+// Reasons of deco_cache_keys_rejected_total
+const (
+	CacheKeyTooLong     = "too_long"
+	CacheKeyCardinality = "cardinality"
+)
+
+// cacheKeyConfig cache.keys applied to every @Cache route, set by ConfigureCacheKeys
+var cacheKeyConfig atomic.Pointer[cacheKeyPolicy]
+
+// cacheKeyPolicy resolved cache.keys: the canonical vary header names and the HMAC key, read once
+type cacheKeyPolicy struct {
+	vary      []string
+	maxLength int
+	maxKeys   int
+	secret    string
+
+	mu  sync.Mutex
+	key []byte
+}
+
+// newCacheKeyPolicy canonicalizes the header names of the configuration
+func newCacheKeyPolicy(config CacheKeyConfig) *cacheKeyPolicy {
+	policy := &cacheKeyPolicy{
+		vary:      canonicalVaryHeaders(config.VaryHeaders),
+		maxLength: config.MaxLength,
+		maxKeys:   config.MaxKeysPerRoute,
+		secret:    config.HMACSecret,
+	}
+	if policy.maxLength <= 0 {
+		policy.maxLength = DefaultCacheKeyMaxLength
+	}
+	return policy
+}
+
+// cacheKeyRules settings of one request: those set on the route over cache.keys
+type cacheKeyRules struct {
+	vary      []string
+	maxLength int
+	maxKeys   int
+	hasher    *cacheKeyPolicy
+}
+
+// rules applies the settings of the route (CacheConfig.Keys, @Cache arguments) over the policy
+func (p *cacheKeyPolicy) rules(route *cacheKeyPolicy, config CacheKeyConfig) cacheKeyRules {
+	rules := cacheKeyRules{vary: p.vary, maxLength: p.maxLength, maxKeys: p.maxKeys, hasher: p}
+	if len(config.VaryHeaders) > 0 {
+		rules.vary = route.vary
+	}
+	if config.MaxLength > 0 {
+		rules.maxLength = route.maxLength
+	}
+	if config.MaxKeysPerRoute > 0 {
+		rules.maxKeys = route.maxKeys
+	}
+	if config.HMACSecret != "" {
+		rules.hasher = route
+	}
+	return rules
+}
+
+// ConfigureCacheKeys sets the key hardening of the @Cache routes: vary headers, maximum length, HMAC
+// hashing and per-route cardinality; routes override the vary headers and cardinality with
+// @Cache(vary=[...], max_keys=N)
+func ConfigureCacheKeys(config CacheKeyConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+	cacheKeyConfig.Store(newCacheKeyPolicy(config))
+	return nil
+}
+
+// currentCacheKeyPolicy policy of cache.keys, the defaults before ConfigureCacheKeys
+func currentCacheKeyPolicy() *cacheKeyPolicy {
+	if policy := cacheKeyConfig.Load(); policy != nil {
+		return policy
+	}
+	policy := newCacheKeyPolicy(CacheKeyConfig{})
+	cacheKeyConfig.CompareAndSwap(nil, policy)
+	return cacheKeyConfig.Load()
+}
+
+// canonicalVaryHeaders canonical, sorted and deduplicated header names
+func canonicalVaryHeaders(headers []string) []string {
+	seen := make(map[string]bool, len(headers))
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		name := http.CanonicalHeaderKey(strings.TrimSpace(header))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// canonicalHeaderValue header value normalized so equivalent spellings share an entry: lower case,
+// list items trimmed and whitespace collapsed
+func canonicalHeaderValue(values []string) string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.Join(strings.Fields(item), " "); item != "" {
+				items = append(items, strings.ToLower(item))
+			}
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// canonicalCacheKey appends the allowlisted vary headers to the base key; headers not in vary never
+// reach the key, whatever the client sends
+func canonicalCacheKey(base string, vary []string, header http.Header) string {
+	if len(vary) == 0 {
+		return base
+	}
+	parts := make([]string, 0, 2+4*len(vary))
+	parts = append(parts, base, ":vary")
+	for _, name := range vary {
+		parts = append(parts, ":", strings.ToLower(name), "=", canonicalHeaderValue(header.Values(name)))
+	}
+	return joinKey(parts...)
+}
+
+// hmacKey reads the HMAC key from the secret provider, until it is available
+func (p *cacheKeyPolicy) hmacKey(ctx context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key != nil {
+		return p.key, nil
+	}
+	key, err := GetSecret(ctx, p.secret)
+	if err != nil {
+		return nil, err
+	}
+	p.key = key
+	return key, nil
+}
+
+// storeKey key sent to the store: HMAC-SHA256 of the canonical key when cache.keys.hmac_secret is set,
+// so keys have a fixed length and cannot be forged to collide with another route's entries
+func (p *cacheKeyPolicy) storeKey(ctx context.Context, canonical string) (string, error) {
+	if p.secret == "" {
+		return canonical, nil
+	}
+	key, err := p.hmacKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(canonical))
+	return "cache:hmac:" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// cacheKeyTracker distinct live keys per route, to cap their cardinality
+type cacheKeyTracker struct {
+	mu     sync.Mutex
+	routes map[string]map[string]time.Time // route -> key -> expiry
+	now    func() time.Time
+}
+
+// newCacheKeyTracker creates an empty tracker
+func newCacheKeyTracker() *cacheKeyTracker {
+	return &cacheKeyTracker{routes: make(map[string]map[string]time.Time), now: time.Now}
+}
+
+// admit reports whether key may be cached for route: known keys always are, new keys while the route
+// has less than max live keys
+func (t *cacheKeyTracker) admit(route, key string, max int, ttl time.Duration) bool {
+	if max <= 0 {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	keys := t.routes[route]
+	if keys == nil {
+		keys = make(map[string]time.Time)
+		t.routes[route] = keys
+	}
+	if expiry, known := keys[key]; known && now.Before(expiry) {
+		return true
+	}
+	if len(keys) >= max {
+		for known, expiry := range keys {
+			if !now.Before(expiry) {
+				delete(keys, known)
+			}
+		}
+		if len(keys) >= max {
+			return false
+		}
+	}
+	keys[key] = now.Add(ttl)
+	return true
+}
+
+var (
+	cacheKeyMetricsOnce sync.Once
+	cacheKeysRejected   *prometheus.CounterVec
+)
+
+// cacheKeysRejectedCounter returns the deco_cache_keys_rejected_total counter
+func cacheKeysRejectedCounter() *prometheus.CounterVec {
+	cacheKeyMetricsOnce.Do(func() {
+		rejected := prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "deco_cache_keys_rejected_total",
+				Help: "Total number of responses not cached because their key was too long or over the route cardinality",
+			},
+			[]string{"route", "reason"},
+		)
+		if err := prometheus.Register(rejected); err != nil {
+			if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				rejected = are.ExistingCollector.(*prometheus.CounterVec)
+			}
+		}
+		cacheKeysRejected = rejected
+	})
+	return cacheKeysRejected
+}
+
+// cacheRoute route of the request, "unmatched" outside the routes so clients cannot add metric labels
+func cacheRoute(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// rejectCacheKey counts the rejected key and serves the request without the cache
+func rejectCacheKey(c *gin.Context, reason string) {
+	cacheKeysRejectedCounter().WithLabelValues(cacheRoute(c), reason).Inc()
+	c.Header("X-Cache", "BYPASS")
+	c.Next()
+}
+
+// validate checks cache.keys
+func (c CacheKeyConfig) validate() error {
+	if c.MaxLength < 0 {
+		return fmt.Errorf("invalid cache.keys.max_length %d (must not be negative)", c.MaxLength)
+	}
+	if c.MaxKeysPerRoute < 0 {
+		return fmt.Errorf("invalid cache.keys.max_keys_per_route %d (must not be negative)", c.MaxKeysPerRoute)
+	}
+	for _, header := range c.VaryHeaders {
+		if strings.TrimSpace(header) == "" || strings.ContainsAny(header, " :\t") {
+			return fmt.Errorf("invalid cache.keys.vary_headers entry '%s'", header)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalCacheKey(t *testing.T) {
+	vary := canonicalVaryHeaders([]string{"x-tenant", "Accept-Language", "X-Tenant"})
+	assert.Equal(t, []string{"Accept-Language", "X-Tenant"}, vary)
+
+	header := http.Header{}
+	header.Set("Accept-Language", " PT-BR,  en ")
+	header.Set("X-Tenant", "Acme")
+	header.Set("X-Forwarded-Host", "evil.example.com")
+	key := canonicalCacheKey("cache:url:GET:/items", vary, header)
+	assert.Equal(t, "cache:url:GET:/items:vary:accept-language=pt-br,en:x-tenant=acme", key)
+
+	header.Set("X-Forwarded-Host", "other.example.com")
+	assert.Equal(t, key, canonicalCacheKey("cache:url:GET:/items", vary, header), "headers outside vary never reach the key")
+	assert.Equal(t, "base", canonicalCacheKey("base", nil, header))
+}
+
+func TestCacheKeyTracker(t *testing.T) {
+	tracker := newCacheKeyTracker()
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	assert.True(t, tracker.admit("/items", "a", 2, time.Minute))
+	assert.True(t, tracker.admit("/items", "b", 2, time.Minute))
+	assert.False(t, tracker.admit("/items", "c", 2, time.Minute))
+	assert.True(t, tracker.admit("/items", "a", 2, time.Minute), "known keys stay cacheable")
+	assert.True(t, tracker.admit("/users", "c", 2, time.Minute), "limits are per route")
+
+	now = now.Add(2 * time.Minute)
+	assert.True(t, tracker.admit("/items", "c", 2, time.Minute), "expired keys free their slot")
+	assert.True(t, tracker.admit("/items", "d", 0, time.Minute), "0 is unlimited")
+}
+
+func TestCacheMiddlewareKeyHardening(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"cache-hmac": []byte("k3y")})
+	defer UseSecretProvider(nil)
+	require.NoError(t, ConfigureCacheKeys(CacheKeyConfig{VaryHeaders: []string{"X-Tenant"}, HMACSecret: "cache-hmac", MaxLength: 64}))
+	defer func() { _ = ConfigureCacheKeys(CacheKeyConfig{}) }()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	calls := 0
+	engine.GET("/reports/:id", createCacheMiddleware([]string{"ttl=1m", "max_keys=2"}), func(c *gin.Context) {
+		calls++
+		c.String(http.StatusOK, c.GetHeader("X-Tenant"))
+	})
+	get := func(path, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("X-Tenant", tenant)
+		req.Header.Set("X-Random", path+tenant)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "MISS", get("/reports/1", "acme").Header().Get("X-Cache"))
+	w := get("/reports/1", "ACME ")
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"), "tenant values are canonicalized")
+	assert.Equal(t, "X-Tenant", w.Header().Get("Vary"))
+	assert.Equal(t, "MISS", get("/reports/1", "globex").Header().Get("X-Cache"))
+
+	rejected := cacheKeysRejectedCounter().WithLabelValues("/reports/:id", CacheKeyCardinality)
+	before := testutil.ToFloat64(rejected)
+	assert.Equal(t, "BYPASS", get("/reports/2", "acme").Header().Get("X-Cache"), "the route holds 2 keys")
+	assert.Equal(t, before+1, testutil.ToFloat64(rejected))
+
+	assert.Equal(t, "BYPASS", get("/reports/"+strings.Repeat("x", 64), "acme").Header().Get("X-Cache"), "key over max_length")
+	assert.Equal(t, 4, calls)
+
+	assert.Error(t, ConfigureCacheKeys(CacheKeyConfig{VaryHeaders: []string{"X Tenant"}}))
+	assert.Error(t, ConfigureCacheKeys(CacheKeyConfig{MaxKeysPerRoute: -1}))
+}
+
+func TestCacheKeyHMAC(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"cache-hmac": []byte("k3y")})
+	defer UseSecretProvider(nil)
+	ctx := context.Background()
+
+	policy := newCacheKeyPolicy(CacheKeyConfig{HMACSecret: "cache-hmac"})
+	first, err := policy.storeKey(ctx, "cache:url:GET:/items")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "cache:hmac:"))
+	assert.Len(t, first, len("cache:hmac:")+64)
+	second, _ := policy.storeKey(ctx, "cache:url:GET:/items")
+	assert.Equal(t, first, second)
+	other, _ := policy.storeKey(ctx, "cache:url:GET:/users")
+	assert.NotEqual(t, first, other)
+
+	_, err = newCacheKeyPolicy(CacheKeyConfig{HMACSecret: "missing"}).storeKey(ctx, "key")
+	assert.Error(t, err)
+	plain, _ := newCacheKeyPolicy(CacheKeyConfig{}).storeKey(ctx, "key")
+	assert.Equal(t, "key", plain)
+}
@@ -0,0 +1,150 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheTestEngine GET /items/:id behind @Cache
+func cacheTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/items/:id", createCacheMiddleware([]string{"ttl=1m"}), func(c *gin.Context) {
+		c.Header("X-Item", c.Param("id"))
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	return engine
+}
+
+func TestCacheMiddlewareHitAndMiss(t *testing.T) {
+	engine := cacheTestEngine()
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, "1", w.Header().Get("X-Item"))
+	assert.JSONEq(t, `{"id":"1"}`, w.Body.String())
+	assert.Len(t, w.Header().Get("X-Cache-Key"), 8)
+
+	// Another key is a miss served by the handler
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/2", http.NoBody))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"id":"2"}`, w.Body.String())
+}
+
+// discardWriter response writer reused across benchmark iterations, so only the
+// allocations of the middleware are reported
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header            { return w.header }
+func (w *discardWriter) Write(data []byte) (int, error) { return len(data), nil }
+func (w *discardWriter) WriteHeader(int)                {}
+
+// serve serves req with a cleared header map
+func (w *discardWriter) serve(engine *gin.Engine, req *http.Request) {
+	clear(w.header)
+	engine.ServeHTTP(w, req)
+}
+
+// countingCacheStore memory store counting the entries written through it
+type countingCacheStore struct {
+	*MemoryCache
+	sets int
+}
+
+func (s *countingCacheStore) Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error {
+	s.sets++
+	return s.MemoryCache.Set(ctx, key, entry, ttl)
+}
+
+func TestRegisterCacheBackend(t *testing.T) {
+	store := &countingCacheStore{MemoryCache: NewMemoryCache(10)}
+	var received *CacheConfig
+	RegisterCacheBackend("counting", func(config *CacheConfig) (CacheStore, error) {
+		received = config
+		return store, nil
+	})
+	defer func() {
+		cacheBackendsMux.Lock()
+		delete(cacheBackends, "counting")
+		cacheBackendsMux.Unlock()
+	}()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/items", createCacheMiddleware([]string{"ttl=1m", `backend="counting"`}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+
+	require.NotNil(t, received)
+	assert.Equal(t, "counting", received.Type)
+	assert.Equal(t, 1, store.sets)
+
+	// Unknown backends fall back to memory
+	_, isMemory := newCacheStore(&CacheConfig{Type: "missing", MaxSize: 10}).(*MemoryCache)
+	assert.True(t, isMemory)
+	_, isMemory = newCacheStore(&CacheConfig{MaxSize: 10}).(*MemoryCache)
+	assert.True(t, isMemory)
+}
+
+func BenchmarkCacheMiddlewareHit(b *testing.B) {
+	engine := cacheTestEngine()
+	req := httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+	writer := &discardWriter{header: http.Header{}}
+	writer.serve(engine, req)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		writer.serve(engine, req)
+	}
+}
+
+func BenchmarkCacheKey(b *testing.B) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = URLCacheKey(c)
+	}
+}
+
+// Allocation ceilings of the hot paths, measured with a reused writer; raise them only with a reason
+func TestCacheHotPathAllocations(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { _ = URLCacheKey(c) }), 1.0)
+
+	engine := cacheTestEngine()
+	req := httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+	writer := &discardWriter{header: http.Header{}}
+	writer.serve(engine, req)
+	// 12: the ETag and Last-Modified validators of the hit add a header value each
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { writer.serve(engine, req) }), 12.0)
+}
+
+func TestConfigureRedis(t *testing.T) {
+	defer ConfigureRedis(DefaultConfig().Redis)
+	assert.Equal(t, "localhost:6379", GetRedisConfig().Address)
+
+	ConfigureRedis(RedisConfig{Address: "redis.internal:6380", DB: 2})
+	assert.Equal(t, RedisConfig{Address: "redis.internal:6380", DB: 2}, GetRedisConfig())
+}
@@ -0,0 +1,79 @@
+package decorators
+
+import (
+	"strings"
+)
+
+// CatchAllExtension extension of the path parameter of a catch-all route (gin "/files/*path"), which
+// matches the rest of the path, slashes included
+const CatchAllExtension = "x-catch-all"
+
+// catchAllName name of the catch-all parameter of a gin path, "" when the path has none
+func catchAllName(path string) string {
+	index := strings.LastIndex(path, "/*")
+	if index < 0 {
+		return ""
+	}
+	return path[index+2:]
+}
+
+// openAPIPathTemplate path of a route in the spec: the catch-all segment becomes a "{name}" template,
+// as OpenAPI has no wildcards
+func openAPIPathTemplate(path string) string {
+	name := catchAllName(path)
+	if name == "" {
+		return path
+	}
+	return strings.TrimSuffix(path, "*"+name) + "{" + name + "}"
+}
+
+// addCatchAllParameter documents the catch-all parameter of the route, declared by @Param or not
+func addCatchAllParameter(operation *OpenAPIOperation, route *RouteEntry) {
+	name := catchAllName(route.Path)
+	if name == "" {
+		return
+	}
+	for i := range operation.Parameters {
+		if param := &operation.Parameters[i]; param.In == "path" && param.Name == name {
+			param.Required = true
+			param.CatchAll = true
+			return
+		}
+	}
+	operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+		Name:        name,
+		In:          "path",
+		Description: "Rest of the path, may contain '/'",
+		Required:    true,
+		Schema:      &OpenAPISchema{Type: "string"},
+		CatchAll:    true,
+	})
+}
+
+// catchAllParameter name of the catch-all parameter of an operation of the spec, "" when it has none
+func (o *OpenAPIOperation) catchAllParameter() string {
+	for _, param := range o.Parameters {
+		if param.In == "path" && param.CatchAll {
+			return param.Name
+		}
+	}
+	return ""
+}
+
+// ginTemplateSegments segments of a spec path with the catch-all parameter written back as "*name"
+func ginTemplateSegments(template string, operation *OpenAPIOperation) []string {
+	segments := splitSpecPath(template)
+	if name := operation.catchAllParameter(); name != "" && len(segments) > 0 && segments[len(segments)-1] == "{"+name+"}" {
+		segments[len(segments)-1] = "*" + name
+	}
+	return segments
+}
+
+// displayPath path shown on the docs page: "/files/*path" reads "/files/{path...}"
+func displayPath(path string) string {
+	name := catchAllName(path)
+	if name == "" {
+		return path
+	}
+	return strings.TrimSuffix(path, "*"+name) + "{" + name + "...}"
+}
@@ -0,0 +1,49 @@
+package decorators
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIPathTemplate(t *testing.T) {
+	assert.Equal(t, "/static/{filepath}", openAPIPathTemplate("/static/*filepath"))
+	assert.Equal(t, "/{path}", openAPIPathTemplate("/*path"))
+	assert.Equal(t, "/users/:id", openAPIPathTemplate("/users/:id"))
+	assert.Equal(t, "/static/{filepath...}", displayPath("/static/*filepath"))
+}
+
+func TestCatchAllSpec(t *testing.T) {
+	spec := buildOpenAPISpec(DefaultConfig(), []RouteEntry{
+		{Method: "GET", Path: "/static/*filepath", FuncName: "ServeStatic"},
+		{Method: "GET", Path: "/files/*path", FuncName: "ServeFile", Parameters: []ParameterInfo{
+			{Name: "path", Type: "string", Location: "path", Description: "File path"},
+		}},
+	}, nil)
+
+	require.Contains(t, spec.Paths, "/static/{filepath}")
+	operation := spec.Paths["/static/{filepath}"]["get"]
+	require.Len(t, operation.Parameters, 1)
+	assert.Equal(t, "filepath", operation.Parameters[0].Name)
+	assert.True(t, operation.Parameters[0].Required)
+	assert.True(t, operation.Parameters[0].CatchAll)
+
+	declared := spec.Paths["/files/{path}"]["get"].Parameters
+	require.Len(t, declared, 1, "a declared @Param is not repeated")
+	assert.Equal(t, "File path", declared[0].Description)
+	assert.True(t, declared[0].CatchAll)
+
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"x-catch-all":true`)
+
+	// The template matches the rest of the path again once read back
+	parsed, err := ParseOpenAPISpec(data)
+	require.NoError(t, err)
+	match, ok := MatchSpecOperation(parsed, "GET", "/static/css/app.css")
+	require.True(t, ok)
+	assert.Equal(t, "/static/{filepath}", match.Template)
+	assert.Equal(t, map[string]string{"filepath": "css/app.css"}, match.PathParams)
+}
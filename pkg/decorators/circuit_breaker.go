@@ -1,10 +1,21 @@
 package decorators
 
 import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// CircuitBreakersPath lists the circuit breakers of @CircuitBreaker and @Proxy with their state
+const CircuitBreakersPath = "/decorators/circuit-breakers"
+
 // CircuitBreakerState represents the state of a circuit breaker
 type CircuitBreakerState int
 
@@ -18,12 +29,17 @@ const (
 type CircuitBreakerImpl struct {
 	state           CircuitBreakerState
 	failureCount    int
+	windowStart     time.Time // first failure counted in the window
 	lastFailureTime time.Time
 	lastSuccessTime time.Time
+	probing         bool  // the request let through while half-open is in flight
+	rejected        int64 // requests refused while open
 
 	// Configuration
+	name             string // reported by the circuit breaker events
 	failureThreshold int
 	recoveryTimeout  time.Duration
+	window           time.Duration // failures older than the window are forgotten; 0 counts consecutive failures
 
 	mu sync.RWMutex
 }
@@ -46,7 +62,7 @@ func (cb *CircuitBreakerImpl) IsOpen() bool {
 	case StateOpen:
 		// Check if recovery timeout has passed
 		if time.Since(cb.lastFailureTime) >= cb.recoveryTimeout {
-			cb.state = StateHalfOpen
+			cb.setState(StateHalfOpen)
 		}
 		return cb.state == StateOpen
 
@@ -67,15 +83,18 @@ func (cb *CircuitBreakerImpl) RecordSuccess() {
 	defer cb.mu.Unlock()
 
 	cb.lastSuccessTime = time.Now()
+	cb.probing = false
 
 	switch cb.state {
 	case StateHalfOpen:
 		// Success in half-open state, close the circuit
-		cb.state = StateClosed
+		cb.setState(StateClosed)
 		cb.failureCount = 0
 	case StateClosed:
-		// Already closed, just update success time and reset failure count
-		cb.failureCount = 0
+		// Already closed: consecutive failures start over, failures within a window are kept
+		if cb.window == 0 {
+			cb.failureCount = 0
+		}
 	}
 }
 
@@ -84,26 +103,91 @@ func (cb *CircuitBreakerImpl) RecordFailure() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.lastFailureTime = time.Now()
+	now := time.Now()
+	if cb.window > 0 && cb.failureCount > 0 && now.Sub(cb.windowStart) > cb.window {
+		cb.failureCount = 0
+	}
+	if cb.failureCount == 0 {
+		cb.windowStart = now
+	}
+	cb.lastFailureTime = now
 	cb.failureCount++
+	cb.probing = false
 
 	switch cb.state {
 	case StateClosed:
 		// Check if threshold reached
 		if cb.failureCount >= cb.failureThreshold {
-			cb.state = StateOpen
+			cb.setState(StateOpen)
+			cb.emitOpened()
 		}
 	case StateHalfOpen:
 		// Failure in half-open state, open the circuit
-		cb.state = StateOpen
+		cb.setState(StateOpen)
+		cb.emitOpened()
 	}
 }
 
+// allow reports whether a request may go through: always while closed, only the first one while
+// half-open (the probe), none while open
+func (cb *CircuitBreakerImpl) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen && time.Since(cb.lastFailureTime) >= cb.recoveryTimeout {
+		cb.setState(StateHalfOpen)
+		cb.probing = false
+	}
+	switch cb.state {
+	case StateOpen:
+	case StateHalfOpen:
+		if !cb.probing {
+			cb.probing = true
+			return true
+		}
+	default:
+		return true
+	}
+
+	cb.rejected++
+	if cb.name != "" {
+		circuitBreakerRejectedCounter().WithLabelValues(cb.name).Inc()
+	}
+	return false
+}
+
+// retryAfter time left before the open circuit lets a probe through
+func (cb *CircuitBreakerImpl) retryAfter() time.Duration {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return max(cb.recoveryTimeout-time.Since(cb.lastFailureTime), 0)
+}
+
+// setState changes the state and publishes it as deco_circuit_breaker_state (called with the lock held)
+func (cb *CircuitBreakerImpl) setState(state CircuitBreakerState) {
+	cb.state = state
+	if cb.name != "" {
+		circuitBreakerStateGauge().WithLabelValues(cb.name).Set(float64(state))
+	}
+}
+
+// emitOpened publishes the circuit breaker opened event (called with the lock held)
+func (cb *CircuitBreakerImpl) emitOpened() {
+	EmitEvent(EventCircuitBreakerOpened, cb.name, map[string]interface{}{
+		"failure_count":    cb.failureCount,
+		"recovery_timeout": cb.recoveryTimeout.String(),
+	})
+}
+
 // GetState returns the current state as a string
 func (cb *CircuitBreakerImpl) GetState() string {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
+	return cb.stateName()
+}
 
+// stateName name of the current state (called with the lock held)
+func (cb *CircuitBreakerImpl) stateName() string {
 	switch cb.state {
 	case StateClosed:
 		return "closed"
@@ -121,14 +205,19 @@ func (cb *CircuitBreakerImpl) GetStats() map[string]interface{} {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
 
-	return map[string]interface{}{
-		"state":             cb.GetState(),
+	stats := map[string]interface{}{
+		"state":             cb.stateName(),
 		"failure_count":     cb.failureCount,
 		"last_failure":      cb.lastFailureTime,
 		"last_success":      cb.lastSuccessTime,
 		"failure_threshold": cb.failureThreshold,
 		"recovery_timeout":  cb.recoveryTimeout.String(),
+		"rejected":          cb.rejected,
 	}
+	if cb.window > 0 {
+		stats["window"] = cb.window.String()
+	}
+	return stats
 }
 
 // createCircuitBreaker creates a circuit breaker from configuration
@@ -138,10 +227,205 @@ func createCircuitBreaker(config *ProxyConfig) CircuitBreaker {
 		failureThreshold = DefaultFailureThreshold
 	}
 
-	recoveryTimeout, _ := time.ParseDuration(config.CircuitBreaker)
+	recoveryTimeout, _ := ParseDurationLiteral(config.CircuitBreaker)
 	if recoveryTimeout == 0 {
 		recoveryTimeout = 30 * time.Second
 	}
 
-	return NewCircuitBreaker(failureThreshold, recoveryTimeout)
+	cb := NewCircuitBreaker(failureThreshold, recoveryTimeout)
+	// Names are shown by CircuitBreakersPath and the metrics without the quotes of the marker
+	cb.name = MarkerValue(config.Service)
+	if cb.name == "" {
+		cb.name = MarkerValue(config.Target)
+	}
+	if cb.name != "" {
+		circuitBreakersMutex.Lock()
+		circuitBreakers[cb.name] = cb
+		circuitBreakersMutex.Unlock()
+	}
+	return cb
+}
+
+var (
+	circuitBreakersMutex sync.RWMutex
+	circuitBreakers      = make(map[string]*CircuitBreakerImpl) // by name, for CircuitBreakersPath
+
+	circuitBreakerMetricsOnce sync.Once
+	circuitBreakerState       *prometheus.GaugeVec
+	circuitBreakerRejected    *prometheus.CounterVec
+)
+
+// registerCircuitBreakerMetrics registers deco_circuit_breaker_state and deco_circuit_breaker_rejected_total
+func registerCircuitBreakerMetrics() {
+	circuitBreakerMetricsOnce.Do(func() {
+		state := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "deco_circuit_breaker_state",
+				Help: "State of the circuit breakers: 0 closed, 1 open, 2 half-open",
+			},
+			[]string{"name"},
+		)
+		if err := prometheus.Register(state); err != nil {
+			if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				state = are.ExistingCollector.(*prometheus.GaugeVec)
+			}
+		}
+		rejected := prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "deco_circuit_breaker_rejected_total",
+				Help: "Total number of requests refused by open circuit breakers",
+			},
+			[]string{"name"},
+		)
+		if err := prometheus.Register(rejected); err != nil {
+			if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				rejected = are.ExistingCollector.(*prometheus.CounterVec)
+			}
+		}
+		circuitBreakerState, circuitBreakerRejected = state, rejected
+	})
+}
+
+// circuitBreakerStateGauge returns the deco_circuit_breaker_state gauge
+func circuitBreakerStateGauge() *prometheus.GaugeVec {
+	registerCircuitBreakerMetrics()
+	return circuitBreakerState
+}
+
+// circuitBreakerRejectedCounter returns the deco_circuit_breaker_rejected_total counter
+func circuitBreakerRejectedCounter() *prometheus.CounterVec {
+	registerCircuitBreakerMetrics()
+	return circuitBreakerRejected
+}
+
+// CircuitBreakerOptions configuration of @CircuitBreaker
+type CircuitBreakerOptions struct {
+	Name      string        // shared by the routes using it; defaults to "METHOD /path" of the route
+	Threshold int           // failures that open the circuit
+	Window    time.Duration // period the failures are counted in; 0 counts consecutive failures
+	Cooldown  time.Duration // time the circuit stays open before a probe request is let through
+}
+
+// parseCircuitBreakerArgs parses @CircuitBreaker(threshold=5, window="30s", cooldown="1m", name="payments")
+func parseCircuitBreakerArgs(args []string) (CircuitBreakerOptions, error) {
+	options := CircuitBreakerOptions{Threshold: DefaultFailureThreshold, Cooldown: 30 * time.Second}
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			if strings.TrimSpace(arg) == "" {
+				continue
+			}
+			return options, fmt.Errorf("@CircuitBreaker: unexpected argument '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			options.Name = value
+		case "threshold":
+			threshold, err := strconv.Atoi(value)
+			if err != nil || threshold < 1 {
+				return options, fmt.Errorf("@CircuitBreaker: invalid threshold '%s'", value)
+			}
+			options.Threshold = threshold
+		case "window", "cooldown":
+			duration, err := ParseDurationLiteral(value)
+			if err != nil || duration < 0 {
+				return options, fmt.Errorf("@CircuitBreaker: invalid %s '%s'", key, value)
+			}
+			if key == "window" {
+				options.Window = duration
+			} else {
+				options.Cooldown = duration
+			}
+		default:
+			return options, fmt.Errorf("@CircuitBreaker: unknown argument '%s' (valid: threshold, window, cooldown, name)", key)
+		}
+	}
+	return options, nil
+}
+
+// sharedCircuitBreaker returns the circuit breaker named name, creating it with the options when missing
+func sharedCircuitBreaker(name string, options CircuitBreakerOptions) *CircuitBreakerImpl {
+	circuitBreakersMutex.Lock()
+	defer circuitBreakersMutex.Unlock()
+	if cb, ok := circuitBreakers[name]; ok {
+		return cb
+	}
+	cb := NewCircuitBreaker(options.Threshold, options.Cooldown)
+	cb.name = name
+	cb.window = options.Window
+	cb.setState(StateClosed)
+	circuitBreakers[name] = cb
+	return cb
+}
+
+// CircuitBreakerMiddleware answers 503 without calling the handler while the circuit of the route is open.
+// Responses with a 5xx status (a failing @Proxy upstream answers 502/504) and panics count as failures.
+func CircuitBreakerMiddleware(options CircuitBreakerOptions) gin.HandlerFunc {
+	var mu sync.Mutex
+	var cb *CircuitBreakerImpl
+	breaker := func(c *gin.Context) *CircuitBreakerImpl {
+		mu.Lock()
+		defer mu.Unlock()
+		if cb == nil {
+			name := options.Name
+			if name == "" {
+				name = c.Request.Method + " " + getEndpointPattern(c)
+			}
+			cb = sharedCircuitBreaker(name, options)
+		}
+		return cb
+	}
+
+	return func(c *gin.Context) {
+		cb := breaker(c)
+		if !cb.allow() {
+			c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(cb.retryAfter().Seconds())), 1)))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "circuit_open",
+				"message": fmt.Sprintf("Circuit breaker %s is open", cb.name),
+			})
+			return
+		}
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				cb.RecordFailure()
+				panic(recovered)
+			}
+		}()
+		c.Next()
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			cb.RecordFailure()
+		} else {
+			cb.RecordSuccess()
+		}
+	}
+}
+
+// createCircuitBreakerMiddleware creates the @CircuitBreaker middleware
+func createCircuitBreakerMiddleware(args []string) gin.HandlerFunc {
+	options, err := parseCircuitBreakerArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return CircuitBreakerMiddleware(options)
+}
+
+// CircuitBreakerStats returns the statistics of the @CircuitBreaker and @Proxy circuit breakers, by name
+func CircuitBreakerStats() map[string]map[string]interface{} {
+	circuitBreakersMutex.RLock()
+	defer circuitBreakersMutex.RUnlock()
+	stats := make(map[string]map[string]interface{}, len(circuitBreakers))
+	for name, cb := range circuitBreakers {
+		stats[name] = cb.GetStats()
+	}
+	return stats
+}
+
+// CircuitBreakersHandler GET /decorators/circuit-breakers
+func CircuitBreakersHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"circuit_breakers": CircuitBreakerStats()})
 }
@@ -2,10 +2,14 @@
 package decorators
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCircuitBreaker(t *testing.T) {
@@ -159,6 +163,11 @@ func TestCreateCircuitBreakerFromConfig(t *testing.T) {
 	cb = createCircuitBreaker(config)
 	assert.NotNil(t, cb)
 	assert.False(t, cb.IsOpen())
+
+	// Named after the unquoted target of the @Proxy marker
+	cb = createCircuitBreaker(&ProxyConfig{Target: `"http://analytics-service:8085"`})
+	assert.Equal(t, "http://analytics-service:8085", cb.(*CircuitBreakerImpl).name)
+	assert.Contains(t, CircuitBreakerStats(), "http://analytics-service:8085")
 }
 
 func TestCircuitBreaker_ConcurrentAccess(t *testing.T) {
@@ -196,3 +205,78 @@ func TestCircuitBreaker_ConcurrentAccess(t *testing.T) {
 	stats := cb.GetStats()
 	assert.NotNil(t, stats)
 }
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Minute)
+	cb.window = 20 * time.Millisecond
+
+	cb.RecordFailure()
+	cb.RecordSuccess()
+	assert.Equal(t, 1, cb.failureCount, "failures within the window survive successes")
+
+	time.Sleep(30 * time.Millisecond)
+	cb.RecordFailure()
+	assert.Equal(t, 1, cb.failureCount, "failures older than the window are forgotten")
+	cb.RecordFailure()
+	assert.Equal(t, "open", cb.GetState())
+}
+
+func TestParseCircuitBreakerArgs(t *testing.T) {
+	options, err := parseCircuitBreakerArgs([]string{"threshold=3", `window="30s"`, `cooldown="1m"`, `name="payments"`})
+	require.NoError(t, err)
+	assert.Equal(t, CircuitBreakerOptions{Name: "payments", Threshold: 3, Window: 30 * time.Second, Cooldown: time.Minute}, options)
+
+	options, err = parseCircuitBreakerArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultFailureThreshold, options.Threshold)
+
+	_, err = parseCircuitBreakerArgs([]string{"threshold=0"})
+	assert.Error(t, err)
+	_, err = parseCircuitBreakerArgs([]string{"retries=3"})
+	assert.ErrorContains(t, err, "unknown argument 'retries'")
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	failing := true
+	router := gin.New()
+	router.GET("/upstream/:id", createCircuitBreakerMiddleware([]string{"threshold=2", `cooldown="50ms"`}), func(c *gin.Context) {
+		calls++
+		if failing {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream down"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/upstream/1", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadGateway, request().Code)
+	assert.Equal(t, http.StatusBadGateway, request().Code)
+	w := request()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "the open circuit short-circuits the handler")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, 2, calls)
+
+	stats := CircuitBreakerStats()["GET /upstream/:id"]
+	require.NotNil(t, stats)
+	assert.Equal(t, "open", stats["state"])
+	assert.Equal(t, int64(1), stats["rejected"])
+
+	// After the cooldown a probe goes through and closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	failing = false
+	assert.Equal(t, http.StatusOK, request().Code)
+	assert.Equal(t, "closed", CircuitBreakerStats()["GET /upstream/:id"]["state"])
+
+	w = httptest.NewRecorder()
+	handlerRouter := gin.New()
+	handlerRouter.GET(CircuitBreakersPath, CircuitBreakersHandler)
+	handlerRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, CircuitBreakersPath, nil))
+	assert.Contains(t, w.Body.String(), `"GET /upstream/:id"`)
+}
@@ -91,18 +91,21 @@ func (sm *SDKManager) GenerateSDKs(spec *OpenAPISpec) error {
 	}
 
 	// Generate for each language
+	generated := make([]string, 0, len(sm.config.Languages))
 	for _, language := range sm.config.Languages {
 		if generator, exists := sm.generators[language]; exists {
 			fmt.Printf("Gerando SDK para %s...\n", language)
 			if err := generator.Generate(spec, &sm.config); err != nil {
 				return fmt.Errorf("error ao gerar SDK para %s: %v", language, err)
 			}
+			generated = append(generated, language)
 		} else {
 			fmt.Printf("Generator not found para linguagem: %s\n", language)
 		}
 	}
 
-	return nil
+	// Record what changed since the previous generation
+	return sm.updateSDKChangelogs(spec, generated)
 }
 
 // Go SDK Generator
@@ -138,6 +141,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+{{- if .WebSockets}}
+	"sync"
+{{- end}}
 	"time"
 )
 
@@ -147,6 +153,11 @@ type Client struct {
 	HTTPClient *http.Client
 	APIKey     string
 	UserAgent  string
+{{- if .WebSockets}}
+
+	// WebSocketDialer opens the connections of the Connect* helpers
+	WebSocketDialer WebSocketDialFunc
+{{- end}}
 }
 
 // NewClient creates a new API client
@@ -170,14 +181,20 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.HTTPClient.Timeout = timeout
 }
 
+// Packages used only by the methods with parameters or request bodies
+var (
+	_ = url.PathEscape
+	_ = bytes.NewBuffer
+)
+` + goEnvironmentsTemplate + goModelsTemplate + `
 {{range .Endpoints}}
 // {{.FunctionName}} {{.Description}}
-func (c *Client) {{.FunctionName}}(ctx context.Context{{.ParametersSignature}}) ({{.ReturnType}}, error) {
+func (c *Client) {{.FunctionName}}(ctx context.Context{{.ParametersSignature}}{{.BodyParameter}}) ({{.ReturnType}}, error) {
 	{{.URLConstruction}}
 	
 	{{.RequestBody}}
 	
-	req, err := http.NewRequestWithContext(ctx, "{{.Method}}", url, {{.RequestBodyVar}})
+	req, err := http.NewRequestWithContext(ctx, "{{.Method}}", requestURL, {{.RequestBodyVar}})
 	if err != nil {
 		return {{.ZeroValue}}, fmt.Errorf("error creating request: %w", err)
 	}
@@ -207,6 +224,9 @@ type Error struct {
 func (e Error) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
 }
+{{- if .WebSockets}}
+` + goWebSocketSupport + goWebSocketEndpoints + `
+{{- end}}
 `
 
 	data := g.prepareTemplateData(spec, config)
@@ -215,9 +235,14 @@ func (e Error) Error() string {
 
 func (g *GoSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *ClientSDKConfig) map[string]interface{} {
 	endpoints := make([]map[string]interface{}, 0)
+	types := newSDKTypes(spec, "go", "Client")
 
 	for path, pathItem := range spec.Paths {
 		for method, operation := range pathItem {
+			// WebSocket routes get dedicated helpers instead of an HTTP method
+			if isWebSocketOperation(method, operation) {
+				continue
+			}
 			endpoint := map[string]interface{}{
 				"FunctionName":        g.generateFunctionName(method, path),
 				"Description":         operation.Summary,
@@ -225,23 +250,27 @@ func (g *GoSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *ClientSD
 				"Path":                path,
 				"ParametersSignature": g.generateParametersSignature(operation.Parameters),
 				"URLConstruction":     g.generateURLConstruction(path, operation.Parameters),
+				"BodyParameter":       g.generateBodyParameter(types, operation.RequestBody),
 				"RequestBody":         g.generateRequestBody(operation.RequestBody),
 				"RequestBodyVar":      g.getRequestBodyVar(operation.RequestBody),
 				"Headers":             g.generateHeaders(),
-				"ReturnType":          g.generateReturnType(operation.Responses),
-				"ZeroValue":           g.generateZeroValue(operation.Responses),
-				"ResponseHandling":    g.generateResponseHandling(operation.Responses),
+				"ReturnType":          g.generateReturnType(types, operation),
+				"ZeroValue":           g.generateZeroValue(types, operation),
+				"ResponseHandling":    g.generateResponseHandling(types, operation),
 			}
 			endpoints = append(endpoints, endpoint)
 		}
 	}
 
 	return map[string]interface{}{
-		"PackageName": config.PackageName,
-		"ClassName":   generateClassName(config.PackageName),
-		"ServiceName": spec.Info.Title,
-		"GeneratedAt": time.Now().Format("2006-01-02 15:04:05"),
-		"Endpoints":   endpoints,
+		"PackageName":  config.PackageName,
+		"ClassName":    generateClassName(config.PackageName),
+		"ServiceName":  spec.Info.Title,
+		"GeneratedAt":  time.Now().Format("2006-01-02 15:04:05"),
+		"Endpoints":    endpoints,
+		"Models":       types.models(types.goType, goFieldName),
+		"WebSockets":   g.prepareWebSocketData(spec),
+		"Environments": collectSDKEnvironments(spec),
 	}
 }
 
@@ -309,12 +338,12 @@ func (g *GoSDKGenerator) generateParametersSignature(params []OpenAPIParameter)
 
 func (g *GoSDKGenerator) generateURLConstruction(path string, params []OpenAPIParameter) string {
 	// Replace path parameters and build query
-	code := fmt.Sprintf("url := c.BaseURL + %q", path)
+	code := fmt.Sprintf("requestURL := c.BaseURL + %q", path)
 
 	// Replace path parameters
 	for _, param := range params {
 		if param.In == "path" {
-			code = strings.ReplaceAll(code, "{"+param.Name+"}", fmt.Sprintf("\" + %s + \"", param.Name))
+			code = strings.ReplaceAll(code, "{"+param.Name+"}", fmt.Sprintf("\" + url.PathEscape(fmt.Sprint(%s)) + \"", param.Name))
 		}
 	}
 
@@ -329,17 +358,25 @@ func (g *GoSDKGenerator) generateURLConstruction(path string, params []OpenAPIPa
 	if len(queryParams) > 0 {
 		code += "\n\tvalues := url.Values{}\n"
 		for _, param := range queryParams {
-			code += fmt.Sprintf("\tvalues.Set(%q, %s)\n", param, param)
+			code += fmt.Sprintf("\tvalues.Set(%q, fmt.Sprint(%s))\n", param, param)
 		}
-		code += "\turl += \"?\" + values.Encode()"
+		code += "\trequestURL += \"?\" + values.Encode()"
 	}
 
 	return code
 }
 
+// generateBodyParameter typed request body argument of a method
+func (g *GoSDKGenerator) generateBodyParameter(types *sdkTypes, body *OpenAPIRequestBody) string {
+	if body == nil {
+		return ""
+	}
+	return ", requestBody " + types.goType(sdkRequestSchema(body), true)
+}
+
 func (g *GoSDKGenerator) generateRequestBody(body *OpenAPIRequestBody) string {
 	if body == nil {
-		return "var body io.Reader"
+		return ""
 	}
 	return `jsonBody, _ := json.Marshal(requestBody)
 	body := bytes.NewBuffer(jsonBody)`
@@ -360,26 +397,38 @@ func (g *GoSDKGenerator) generateHeaders() string {
 	}`
 }
 
-func (g *GoSDKGenerator) generateReturnType(_ map[string]OpenAPIResponse) string {
-	return "interface{}" // Simplificado
+// generateReturnType type of the 2xx response, or of its data when enveloped; structs are returned by pointer
+func (g *GoSDKGenerator) generateReturnType(types *sdkTypes, operation *OpenAPIOperation) string {
+	return types.goResultType(sdkResultSchema(operation))
 }
 
-func (g *GoSDKGenerator) generateZeroValue(_ map[string]OpenAPIResponse) string {
-	return "nil"
+func (g *GoSDKGenerator) generateZeroValue(types *sdkTypes, operation *OpenAPIOperation) string {
+	return goZeroValue(g.generateReturnType(types, operation))
 }
 
-func (g *GoSDKGenerator) generateResponseHandling(_ map[string]OpenAPIResponse) string {
-	return `body, err := io.ReadAll(resp.Body)
+func (g *GoSDKGenerator) generateResponseHandling(types *sdkTypes, operation *OpenAPIOperation) string {
+	returnType := g.generateReturnType(types, operation)
+	zero := goZeroValue(returnType)
+	result := "result"
+	if strings.HasPrefix(returnType, "*") {
+		result = "&result"
+	}
+	// Enveloped responses are decoded through their data member
+	target := "&result"
+	if isEnvelopedOperation(operation) {
+		target = "&struct {\n\t\tData *" + strings.TrimPrefix(returnType, "*") + " `json:\"data\"`\n\t}{Data: &result}"
+	}
+	return fmt.Sprintf(`data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return %[2]s, fmt.Errorf("error reading response: %%w", err)
 	}
 
-	var result interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
+	var result %[1]s
+	if err := json.Unmarshal(data, %[4]s); err != nil {
+		return %[2]s, fmt.Errorf("error parsing response: %%w", err)
 	}
 
-	return result, nil`
+	return %[3]s, nil`, strings.TrimPrefix(returnType, "*"), zero, result, target)
 }
 
 func (g *GoSDKGenerator) convertTypeToGo(openAPIType string) string {
@@ -441,11 +490,15 @@ func (p *PythonSDKGenerator) Generate(spec *OpenAPISpec, config *ClientSDKConfig
 Generated automatically by gin-decorators on {{.GeneratedAt}}
 """
 
+from __future__ import annotations
+
 import requests
 import json
-from typing import Dict, Any, Optional
+from dataclasses import dataclass
+from typing import Dict, Any, List, Optional
 from urllib.parse import urljoin, urlencode
 
+` + pythonEnvironmentsTemplate + pythonModelsTemplate + `
 
 class {{.ClassName}}:
     """Client for {{.ServiceName}} API"""
@@ -466,9 +519,9 @@ class {{.ClassName}}:
         """Set API key for authentication"""
         self.api_key = api_key
         self.session.headers['Authorization'] = f'Bearer {api_key}'
-    
+    ` + pythonUseEnvironmentTemplate + `
 {{range .Endpoints}}
-    def {{.FunctionName}}(self{{.ParametersSignature}}) -> Dict[str, Any]:
+    def {{.FunctionName}}(self{{.ParametersSignature}}{{.BodyParameter}}) -> {{.ReturnType}}:
         """{{.Description}}"""
         {{.URLConstruction}}
         
@@ -477,7 +530,7 @@ class {{.ClassName}}:
         response = self.session.{{.Method}}(url{{.RequestBodyParam}})
         response.raise_for_status()
         
-        return response.json()
+        return {{.ResponseConversion}}
 {{end}}
 
 
@@ -498,15 +551,24 @@ func (p *PythonSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *Clie
 	// Generate proper class name (e.g., "testapi" -> "TestAPIClient")
 	className := generateClassName(config.PackageName)
 	endpoints := make([]map[string]interface{}, 0)
+	types := newSDKTypes(spec, "python", className)
 
 	for path, pathItem := range spec.Paths {
 		for method, operation := range pathItem {
+			success := sdkResultSchema(operation)
+			response := "response.json()"
+			if isEnvelopedOperation(operation) {
+				response = "response.json()['data']"
+			}
 			endpoint := map[string]interface{}{
 				"FunctionName":        p.generateFunctionName(method, path),
 				"Description":         operation.Summary,
 				"Method":              strings.ToLower(method),
 				"Path":                path,
 				"ParametersSignature": p.generateParametersSignature(operation.Parameters),
+				"BodyParameter":       p.generateBodyParameter(types, operation.RequestBody),
+				"ReturnType":          types.pythonType(success, true),
+				"ResponseConversion":  types.pythonFromJSON(success, response),
 				"URLConstruction":     p.generateURLConstruction(path, operation.Parameters),
 				"RequestBody":         p.generateRequestBody(operation.RequestBody),
 				"RequestBodyParam":    p.getRequestBodyParam(operation.RequestBody),
@@ -516,11 +578,13 @@ func (p *PythonSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *Clie
 	}
 
 	return map[string]interface{}{
-		"PackageName": config.PackageName,
-		"ClassName":   className,
-		"ServiceName": spec.Info.Title,
-		"GeneratedAt": time.Now().Format("2006-01-02 15:04:05"),
-		"Endpoints":   endpoints,
+		"PackageName":  config.PackageName,
+		"ClassName":    className,
+		"ServiceName":  spec.Info.Title,
+		"GeneratedAt":  time.Now().Format("2006-01-02 15:04:05"),
+		"Endpoints":    endpoints,
+		"Models":       types.pythonModels(),
+		"Environments": collectSDKEnvironments(spec),
 	}
 }
 
@@ -598,18 +662,26 @@ func (p *PythonSDKGenerator) generateURLConstruction(path string, params []OpenA
 	return code
 }
 
+// generateBodyParameter typed request body argument of a method
+func (p *PythonSDKGenerator) generateBodyParameter(types *sdkTypes, body *OpenAPIRequestBody) string {
+	if body == nil {
+		return ""
+	}
+	return ", request_body: " + types.pythonType(sdkRequestSchema(body), true)
+}
+
 func (p *PythonSDKGenerator) generateRequestBody(body *OpenAPIRequestBody) string {
 	if body == nil {
 		return ""
 	}
-	return "json_data = json.dumps(request_body)"
+	return "payload = _to_json(request_body)"
 }
 
 func (p *PythonSDKGenerator) getRequestBodyParam(body *OpenAPIRequestBody) string {
 	if body == nil {
 		return ""
 	}
-	return ", json=request_body"
+	return ", json=payload"
 }
 
 func (p *PythonSDKGenerator) convertTypeToPython(openAPIType string) string {
@@ -670,7 +742,7 @@ func (j *JavaScriptSDKGenerator) Generate(spec *OpenAPISpec, config *ClientSDKCo
  * {{.ServiceName}} API Client
  * Generated automatically by gin-decorators on {{.GeneratedAt}}
  */
-
+` + jsEnvironmentsTemplate + `
 class {{.ClassName}} {
     constructor(baseURL, apiKey = null) {
         this.baseURL = baseURL.replace(/\/$/, '');
@@ -689,7 +761,7 @@ class {{.ClassName}} {
         this.apiKey = apiKey;
         this.defaultHeaders['Authorization'] = ` + "`Bearer ${apiKey}`" + `;
     }
-
+` + jsUseEnvironmentTemplate + `
 {{range .Endpoints}}
     async {{.FunctionName}}({{.ParametersSignature}}) {
         {{.URLConstruction}}
@@ -705,12 +777,15 @@ class {{.ClassName}} {
             throw new Error(` + "`API Error: ${response.status} ${response.statusText}`" + `);
         }
         
-        return await response.json();
+        return {{.Result}};
     }
 {{end}}
 }
 
 module.exports = {{.ClassName}};
+{{- if .Environments}}
+module.exports.ENVIRONMENTS = ENVIRONMENTS;
+{{- end}}
 `
 
 	data := j.prepareTemplateData(spec, config)
@@ -730,20 +805,30 @@ func (j *JavaScriptSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *
 				"ParametersSignature": j.generateParametersSignature(operation.Parameters),
 				"URLConstruction":     j.generateURLConstruction(path, operation.Parameters),
 				"RequestBody":         j.generateRequestBody(operation.RequestBody),
+				"Result":              jsResult(operation),
 			}
 			endpoints = append(endpoints, endpoint)
 		}
 	}
 
 	return map[string]interface{}{
-		"PackageName": config.PackageName,
-		"ClassName":   className,
-		"ServiceName": spec.Info.Title,
-		"GeneratedAt": time.Now().Format("2006-01-02 15:04:05"),
-		"Endpoints":   endpoints,
+		"PackageName":  config.PackageName,
+		"ClassName":    className,
+		"ServiceName":  spec.Info.Title,
+		"GeneratedAt":  time.Now().Format("2006-01-02 15:04:05"),
+		"Endpoints":    endpoints,
+		"Environments": collectSDKEnvironments(spec),
 	}
 }
 
+// jsResult expression of the value returned by a JavaScript or TypeScript method
+func jsResult(operation *OpenAPIOperation) string {
+	if isEnvelopedOperation(operation) {
+		return "(await response.json()).data"
+	}
+	return "await response.json()"
+}
+
 func (j *JavaScriptSDKGenerator) generateFunctionName(method, path string) string {
 	// Convert to camelCase
 	parts := strings.Split(strings.Trim(path, "/"), "/")
@@ -844,7 +929,8 @@ func (t *TypeScriptSDKGenerator) Generate(spec *OpenAPISpec, config *ClientSDKCo
  * {{.ServiceName}} API Client
  * Generated automatically by gin-decorators on {{.GeneratedAt}}
  */
-
+` + jsEnvironmentsTemplate + `
+` + tsModelsTemplate + `
 export class {{.ClassName}} {
     private baseURL: string;
     private apiKey: string | null;
@@ -867,9 +953,9 @@ export class {{.ClassName}} {
         this.apiKey = apiKey;
         this.defaultHeaders['Authorization'] = ` + "`Bearer ${apiKey}`" + `;
     }
-
+` + jsUseEnvironmentTemplate + `
 {{range .Endpoints}}
-    async {{.FunctionName}}({{.ParametersSignature}}): Promise<any> {
+    async {{.FunctionName}}({{.ParametersSignature}}): Promise<{{.ReturnType}}> {
         {{.URLConstruction}}
         
         const options: RequestInit = {
@@ -883,10 +969,9 @@ export class {{.ClassName}} {
             throw new Error(` + "`API Error: ${response.status} ${response.statusText}`" + `);
         }
         
-        return await response.json();
+        return {{.Result}} as {{.ReturnType}};
     }
-{{end}}
-}
+{{end}}` + tsWebSocketMethods + `}
 
 export class APIError extends Error {
     public statusCode?: number;
@@ -897,6 +982,9 @@ export class APIError extends Error {
         this.name = 'APIError';
     }
 }
+{{- if .WebSockets}}
+` + tsWebSocketSupport + `
+{{- end}}
 `
 
 	data := t.prepareTemplateData(spec, config)
@@ -907,13 +995,19 @@ func (t *TypeScriptSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *
 	// Generate proper class name
 	className := generateClassName(config.PackageName)
 	endpoints := make([]map[string]interface{}, 0)
+	types := newSDKTypes(spec, "typescript", className)
 
 	for path, pathItem := range spec.Paths {
 		for method, operation := range pathItem {
+			if isWebSocketOperation(method, operation) {
+				continue
+			}
 			endpoint := map[string]interface{}{
 				"FunctionName":        t.generateFunctionName(method, path),
 				"Method":              strings.ToUpper(method),
-				"ParametersSignature": t.generateParametersSignature(operation.Parameters),
+				"ParametersSignature": t.generateMethodSignature(types, operation.Parameters, operation.RequestBody),
+				"ReturnType":          types.tsType(sdkResultSchema(operation), true),
+				"Result":              jsResult(operation),
 				"URLConstruction":     t.generateURLConstruction(path, operation.Parameters),
 				"RequestBody":         t.generateRequestBody(operation.RequestBody),
 			}
@@ -922,11 +1016,15 @@ func (t *TypeScriptSDKGenerator) prepareTemplateData(spec *OpenAPISpec, config *
 	}
 
 	return map[string]interface{}{
-		"PackageName": config.PackageName,
-		"ClassName":   className,
-		"ServiceName": spec.Info.Title,
-		"GeneratedAt": time.Now().Format("2006-01-02 15:04:05"),
-		"Endpoints":   endpoints,
+		"PackageName":  config.PackageName,
+		"ClassName":    className,
+		"ServiceName":  spec.Info.Title,
+		"GeneratedAt":  time.Now().Format("2006-01-02 15:04:05"),
+		"Endpoints":    endpoints,
+		"Models":       types.models(types.tsType, tsPropertyName),
+		"WebSockets":   t.prepareWebSocketData(spec),
+		"Environments": collectSDKEnvironments(spec),
+		"TypeScript":   true,
 	}
 }
 
@@ -956,6 +1054,18 @@ func (t *TypeScriptSDKGenerator) generateParametersSignature(params []OpenAPIPar
 	return strings.Join(parts, ", ")
 }
 
+// generateMethodSignature parameters of a method, followed by the typed request body
+func (t *TypeScriptSDKGenerator) generateMethodSignature(types *sdkTypes, params []OpenAPIParameter, body *OpenAPIRequestBody) string {
+	signature := t.generateParametersSignature(params)
+	if body == nil {
+		return signature
+	}
+	if signature != "" {
+		signature += ", "
+	}
+	return signature + "requestBody: " + types.tsType(sdkRequestSchema(body), true)
+}
+
 func (t *TypeScriptSDKGenerator) generateURLConstruction(path string, params []OpenAPIParameter) string {
 	// Similar to JavaScript
 	code := fmt.Sprintf("let url = `${this.baseURL}%s`;", path)
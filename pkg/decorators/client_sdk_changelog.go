@@ -0,0 +1,196 @@
+package decorators
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SDKSpecSnapshotFile spec of the last SDK generation, kept in the SDK output directory
+const SDKSpecSnapshotFile = ".deco-spec.json"
+
+// SDKChangelogFile changelog written in each language directory
+const SDKChangelogFile = "CHANGELOG.md"
+
+// SpecDiff endpoints and models added, removed or changed between two specs
+type SpecDiff struct {
+	AddedEndpoints   []string `json:"added_endpoints,omitempty"` // "GET /users/{id}"
+	RemovedEndpoints []string `json:"removed_endpoints,omitempty"`
+	ChangedEndpoints []string `json:"changed_endpoints,omitempty"`
+	AddedModels      []string `json:"added_models,omitempty"`
+	RemovedModels    []string `json:"removed_models,omitempty"`
+	ChangedModels    []string `json:"changed_models,omitempty"`
+}
+
+// Empty reports whether the specs are equivalent for clients
+func (d *SpecDiff) Empty() bool {
+	return len(d.AddedEndpoints)+len(d.RemovedEndpoints)+len(d.ChangedEndpoints)+
+		len(d.AddedModels)+len(d.RemovedModels)+len(d.ChangedModels) == 0
+}
+
+// DiffOpenAPISpecs compares the operations and component schemas of two specs
+func DiffOpenAPISpecs(previous, current *OpenAPISpec) *SpecDiff {
+	diff := &SpecDiff{}
+	diff.AddedEndpoints, diff.RemovedEndpoints, diff.ChangedEndpoints = diffJSONMaps(specOperations(previous), specOperations(current))
+	diff.AddedModels, diff.RemovedModels, diff.ChangedModels = diffJSONMaps(specModels(previous), specModels(current))
+	return diff
+}
+
+// specOperations indexes the operations of a spec by "METHOD path"
+func specOperations(spec *OpenAPISpec) map[string]interface{} {
+	operations := make(map[string]interface{})
+	if spec == nil {
+		return operations
+	}
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem {
+			operations[strings.ToUpper(method)+" "+path] = operation
+		}
+	}
+	return operations
+}
+
+// specModels indexes the component schemas of a spec by name
+func specModels(spec *OpenAPISpec) map[string]interface{} {
+	models := make(map[string]interface{})
+	if spec == nil || spec.Components == nil {
+		return models
+	}
+	for name, schema := range spec.Components.Schemas {
+		models[name] = schema
+	}
+	return models
+}
+
+// diffJSONMaps compares two indexes by the JSON encoding of their values; results are sorted
+func diffJSONMaps(previous, current map[string]interface{}) (added, removed, changed []string) {
+	for key, value := range current {
+		old, exists := previous[key]
+		if !exists {
+			added = append(added, key)
+			continue
+		}
+		oldJSON, _ := json.Marshal(old)
+		newJSON, _ := json.Marshal(value)
+		if !bytes.Equal(oldJSON, newJSON) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, exists := current[key]; !exists {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
+
+// specHash short content hash of an encoded spec
+func specHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// sdkChangelogEntry renders the changelog entry of a generation; a nil diff marks the first generation
+func sdkChangelogEntry(spec *OpenAPISpec, hash string, diff *SpecDiff, date time.Time) string {
+	var entry strings.Builder
+	version := spec.Info.Version
+	if version == "" {
+		version = "unversioned"
+	}
+	fmt.Fprintf(&entry, "## %s - %s (spec %s)\n\n", version, date.Format("2006-01-02"), hash)
+
+	if diff == nil {
+		fmt.Fprintf(&entry, "Initial SDK: %d endpoints, %d models.\n\n", len(specOperations(spec)), len(specModels(spec)))
+		return entry.String()
+	}
+
+	writeChangelogSection(&entry, "Added", diff.AddedEndpoints, diff.AddedModels)
+	writeChangelogSection(&entry, "Removed", diff.RemovedEndpoints, diff.RemovedModels)
+	writeChangelogSection(&entry, "Changed", diff.ChangedEndpoints, diff.ChangedModels)
+	return entry.String()
+}
+
+// writeChangelogSection writes one changelog section, skipped when empty
+func writeChangelogSection(entry *strings.Builder, title string, endpoints, models []string) {
+	if len(endpoints)+len(models) == 0 {
+		return
+	}
+	fmt.Fprintf(entry, "### %s\n\n", title)
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(entry, "- Endpoint `%s`\n", endpoint)
+	}
+	for _, model := range models {
+		fmt.Fprintf(entry, "- Model `%s`\n", model)
+	}
+	entry.WriteString("\n")
+}
+
+// prependChangelogEntry adds an entry at the top of a changelog, below its title
+func prependChangelogEntry(path, entry string) error {
+	const title = "# Changelog\n\n"
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	body := strings.TrimPrefix(string(existing), title)
+	return os.WriteFile(path, []byte(title+entry+body), 0o644)
+}
+
+// loadSpecSnapshot reads the spec saved by the previous SDK generation, if any
+func loadSpecSnapshot(outputDir string) (*OpenAPISpec, string) {
+	data, err := os.ReadFile(filepath.Join(outputDir, SDKSpecSnapshotFile))
+	if err != nil {
+		return nil, ""
+	}
+	var spec OpenAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, ""
+	}
+	return &spec, specHash(data)
+}
+
+// updateSDKChangelogs records what changed since the previous generation in each language
+// directory and saves the spec snapshot for the next one. Unchanged specs add no entry.
+func (sm *SDKManager) updateSDKChangelogs(spec *OpenAPISpec, languages []string) error {
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding spec snapshot: %v", err)
+	}
+	hash := specHash(data)
+
+	previous, previousHash := loadSpecSnapshot(sm.config.OutputDir)
+	if previous != nil && previousHash == hash {
+		return nil
+	}
+
+	var diff *SpecDiff
+	if previous != nil {
+		diff = DiffOpenAPISpecs(previous, spec)
+	}
+
+	if diff == nil || !diff.Empty() {
+		entry := sdkChangelogEntry(spec, hash, diff, time.Now())
+		for _, language := range languages {
+			dir := filepath.Join(sm.config.OutputDir, language)
+			if _, err := os.Stat(dir); err != nil {
+				continue
+			}
+			if err := prependChangelogEntry(filepath.Join(dir, SDKChangelogFile), entry); err != nil {
+				return fmt.Errorf("error writing changelog for %s: %v", language, err)
+			}
+		}
+	}
+
+	return os.WriteFile(filepath.Join(sm.config.OutputDir, SDKSpecSnapshotFile), data, 0o644)
+}
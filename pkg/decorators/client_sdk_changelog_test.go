@@ -0,0 +1,80 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func changelogSpec(paths map[string]OpenAPIPath, schemas map[string]*OpenAPISchema) *OpenAPISpec {
+	return &OpenAPISpec{
+		OpenAPI:    "3.0.3",
+		Info:       OpenAPIInfo{Title: "Shop", Version: "1.1.0"},
+		Paths:      paths,
+		Components: &OpenAPIComponents{Schemas: schemas},
+	}
+}
+
+func TestDiffOpenAPISpecs(t *testing.T) {
+	previous := changelogSpec(map[string]OpenAPIPath{
+		"/users":  {"get": {Summary: "List users"}},
+		"/orders": {"get": {Summary: "List orders"}},
+	}, map[string]*OpenAPISchema{
+		"User":  {Type: "object"},
+		"Order": {Type: "object"},
+	})
+	current := changelogSpec(map[string]OpenAPIPath{
+		"/users":      {"get": {Summary: "List all users"}, "post": {Summary: "Create user"}},
+		"/users/{id}": {"get": {Summary: "Get user"}},
+	}, map[string]*OpenAPISchema{
+		"User":    {Type: "object", Required: []string{"id"}},
+		"Address": {Type: "object"},
+	})
+
+	diff := DiffOpenAPISpecs(previous, current)
+	assert.Equal(t, []string{"GET /users/{id}", "POST /users"}, diff.AddedEndpoints)
+	assert.Equal(t, []string{"GET /orders"}, diff.RemovedEndpoints)
+	assert.Equal(t, []string{"GET /users"}, diff.ChangedEndpoints)
+	assert.Equal(t, []string{"Address"}, diff.AddedModels)
+	assert.Equal(t, []string{"Order"}, diff.RemovedModels)
+	assert.Equal(t, []string{"User"}, diff.ChangedModels)
+
+	assert.True(t, DiffOpenAPISpecs(current, current).Empty())
+
+	entry := sdkChangelogEntry(current, "abc123", diff, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	assert.True(t, strings.HasPrefix(entry, "## 1.1.0 - 2026-01-02 (spec abc123)\n"))
+	assert.Contains(t, entry, "### Removed\n\n- Endpoint `GET /orders`\n- Model `Order`\n")
+}
+
+func TestSDKManager_Changelog(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewSDKManager(&ClientSDKConfig{Enabled: true, OutputDir: dir, Languages: []string{"go", "python"}, PackageName: "shop"})
+
+	spec := changelogSpec(map[string]OpenAPIPath{"/users": {"get": {Summary: "List users"}}}, map[string]*OpenAPISchema{})
+	assert.NoError(t, manager.GenerateSDKs(spec))
+	assert.FileExists(t, filepath.Join(dir, SDKSpecSnapshotFile))
+
+	changelog, err := os.ReadFile(filepath.Join(dir, "go", SDKChangelogFile))
+	assert.NoError(t, err)
+	assert.Contains(t, string(changelog), "Initial SDK: 1 endpoints, 0 models.")
+
+	// Regenerating the same spec adds no entry
+	assert.NoError(t, manager.GenerateSDKs(spec))
+	unchanged, _ := os.ReadFile(filepath.Join(dir, "go", SDKChangelogFile))
+	assert.Equal(t, string(changelog), string(unchanged))
+
+	spec.Paths["/users"]["post"] = &OpenAPIOperation{Summary: "Create user"}
+	assert.NoError(t, manager.GenerateSDKs(spec))
+	for _, language := range []string{"go", "python"} {
+		updated, err := os.ReadFile(filepath.Join(dir, language, SDKChangelogFile))
+		assert.NoError(t, err)
+		content := string(updated)
+		assert.True(t, strings.HasPrefix(content, "# Changelog\n\n## 1.1.0"))
+		assert.Contains(t, content, "- Endpoint `POST /users`")
+		assert.Less(t, strings.Index(content, "POST /users"), strings.Index(content, "Initial SDK"), "newest entry first")
+	}
+}
@@ -0,0 +1,130 @@
+package decorators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sdkEnvironment named server of the spec, as emitted by the SDK generators
+type sdkEnvironment struct {
+	Name  string // "staging"
+	Ident string // exported identifier, e.g. "Staging"
+	URL   string
+}
+
+// collectSDKEnvironments returns the named environments of openapi.servers, in declaration order.
+// Servers without x-environment are named after their description ("Server HTTPS" -> "server-https").
+func collectSDKEnvironments(spec *OpenAPISpec) []sdkEnvironment {
+	environments := make([]sdkEnvironment, 0, len(spec.Servers))
+	seen := make(map[string]bool, len(spec.Servers))
+
+	for i, server := range spec.Servers {
+		name := server.Environment
+		if name == "" {
+			name = environmentSlug(server.Description)
+		}
+		if name == "" {
+			name = fmt.Sprintf("server%d", i+1)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		environments = append(environments, sdkEnvironment{
+			Name:  name,
+			Ident: sdkIdentifier(name),
+			URL:   strings.TrimSuffix(expandServerVariables(server), "/"),
+		})
+	}
+	return environments
+}
+
+// expandServerVariables replaces {variables} of a server URL with their defaults
+func expandServerVariables(server OpenAPIServer) string {
+	url := server.URL
+	for name, variable := range server.Variables {
+		url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+	}
+	return url
+}
+
+// environmentSlug lowercases a description into a dash-separated name
+func environmentSlug(description string) string {
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	return strings.Join(fields, "-")
+}
+
+// Go
+
+// goEnvironmentsTemplate environments and UseEnvironment of the Go SDK
+const goEnvironmentsTemplate = `{{if .Environments}}
+// Environment names declared in openapi.servers
+const (
+{{- range .Environments}}
+	Environment{{.Ident}} = {{printf "%q" .Name}}
+{{- end}}
+)
+
+// Environments base URL of each environment
+var Environments = map[string]string{
+{{- range .Environments}}
+	{{printf "%q" .Name}}: {{printf "%q" .URL}},
+{{- end}}
+}
+
+// UseEnvironment points the client at a named environment, e.g. client.UseEnvironment(EnvironmentStaging)
+func (c *Client) UseEnvironment(name string) error {
+	baseURL, ok := Environments[name]
+	if !ok {
+		return fmt.Errorf("unknown environment %q", name)
+	}
+	c.BaseURL = baseURL
+	return nil
+}
+{{end}}`
+
+// Python
+
+// pythonEnvironmentsTemplate module-level environments of the Python SDK
+const pythonEnvironmentsTemplate = `{{if .Environments}}
+ENVIRONMENTS: Dict[str, str] = {
+{{- range .Environments}}
+    {{printf "%q" .Name}}: {{printf "%q" .URL}},
+{{- end}}
+}
+{{end}}`
+
+// pythonUseEnvironmentTemplate use_environment method of the Python client
+const pythonUseEnvironmentTemplate = `{{if .Environments}}
+    def use_environment(self, name: str):
+        """Point the client at a named environment from openapi.servers"""
+        if name not in ENVIRONMENTS:
+            raise ValueError(f"unknown environment '{name}'")
+        self.base_url = ENVIRONMENTS[name]
+    {{end}}`
+
+// JavaScript and TypeScript
+
+// jsEnvironmentsTemplate environments constant of the JavaScript and TypeScript SDKs
+const jsEnvironmentsTemplate = `{{if .Environments}}
+{{if .TypeScript}}export {{end}}const ENVIRONMENTS = {
+{{- range .Environments}}
+    {{printf "%q" .Name}}: {{printf "%q" .URL}},
+{{- end}}
+}{{if .TypeScript}} as const;
+
+export type Environment = keyof typeof ENVIRONMENTS;{{else}};{{end}}
+{{end}}`
+
+// jsUseEnvironmentTemplate useEnvironment method of the JavaScript and TypeScript clients
+const jsUseEnvironmentTemplate = `{{if .Environments}}
+    useEnvironment(name{{if .TypeScript}}: Environment): void{{else}}){{end}} {
+        if (!(name in ENVIRONMENTS)) {
+            throw new Error(` + "`Unknown environment: ${name}`" + `);
+        }
+        this.baseURL = ENVIRONMENTS[name];
+    }
+{{end}}`
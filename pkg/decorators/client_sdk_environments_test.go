@@ -0,0 +1,88 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func environmentsSDKSpec() *OpenAPISpec {
+	config := DefaultConfig()
+	config.OpenAPI.Servers = []OpenAPIServerConfig{
+		{Name: "prod", URL: "https://api.example.com/"},
+		{Name: "staging", URL: "https://staging.example.com"},
+		{Name: "local", URL: "http://localhost:8080"},
+	}
+	return buildOpenAPISpec(config, []RouteEntry{{Method: "GET", Path: "/users", FuncName: "ListUsers"}}, nil)
+}
+
+func TestConfigureSpecServers_Environments(t *testing.T) {
+	spec := environmentsSDKSpec()
+	if assert.Len(t, spec.Servers, 3) {
+		assert.Equal(t, "staging", spec.Servers[1].Environment)
+	}
+
+	// Without named servers, host/schemes are used and named after their description
+	spec = buildOpenAPISpec(DefaultConfig(), nil, nil)
+	environments := collectSDKEnvironments(spec)
+	if assert.Len(t, environments, 2) {
+		assert.Equal(t, "server-http", environments[0].Name)
+		assert.Equal(t, "http://localhost:8080/api", environments[0].URL)
+	}
+
+	spec.Servers = []OpenAPIServer{{URL: "https://{region}.example.com", Variables: map[string]ServerVariable{"region": {Default: "eu"}}}}
+	environments = collectSDKEnvironments(spec)
+	assert.Equal(t, sdkEnvironment{Name: "server1", Ident: "Server1", URL: "https://eu.example.com"}, environments[0])
+}
+
+func TestOpenAPIConfigValidate_Servers(t *testing.T) {
+	config := DefaultConfig()
+	config.OpenAPI.Servers = []OpenAPIServerConfig{{Name: "prod", URL: "https://api.example.com"}}
+	assert.NoError(t, config.Validate())
+
+	config.OpenAPI.Servers = append(config.OpenAPI.Servers, OpenAPIServerConfig{Name: "prod", URL: "https://other.example.com"})
+	assert.Error(t, config.Validate())
+
+	config.OpenAPI.Servers = []OpenAPIServerConfig{{URL: "https://api.example.com"}}
+	assert.Error(t, config.Validate())
+}
+
+func TestSDKGenerators_UseEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	config := &ClientSDKConfig{OutputDir: dir, PackageName: "shopapi"}
+	spec := environmentsSDKSpec()
+
+	for _, generator := range []SDKGenerator{&GoSDKGenerator{}, &PythonSDKGenerator{}, &JavaScriptSDKGenerator{}, &TypeScriptSDKGenerator{}} {
+		assert.NoError(t, generator.Generate(spec, config), generator.GetLanguage())
+	}
+
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	goCode := read("go/client.go")
+	_, err := parser.ParseFile(token.NewFileSet(), "client.go", goCode, parser.AllErrors)
+	assert.NoError(t, err)
+	assert.Contains(t, goCode, `EnvironmentStaging = "staging"`)
+	assert.Contains(t, goCode, `"prod": "https://api.example.com",`)
+	assert.Contains(t, goCode, "func (c *Client) UseEnvironment(name string) error")
+
+	pythonCode := read("python/client.py")
+	assert.Contains(t, pythonCode, `"local": "http://localhost:8080",`)
+	assert.Contains(t, pythonCode, "def use_environment(self, name: str):")
+
+	jsCode := read("javascript/client.js")
+	assert.Contains(t, jsCode, "const ENVIRONMENTS = {")
+	assert.Contains(t, jsCode, "useEnvironment(name) {")
+	assert.Contains(t, jsCode, "module.exports.ENVIRONMENTS = ENVIRONMENTS;")
+
+	tsCode := read("typescript/client.ts")
+	assert.Contains(t, tsCode, "export type Environment = keyof typeof ENVIRONMENTS;")
+	assert.Contains(t, tsCode, "useEnvironment(name: Environment): void {")
+}
@@ -0,0 +1,524 @@
+package decorators
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// sdkModel named type of components.schemas, as emitted by the SDK generators
+type sdkModel struct {
+	Name        string // identifier in the SDK, e.g. "User"
+	Description string
+	Alias       string // type of a schema that is not an object with properties; empty for structs
+	Fields      []sdkModelField
+}
+
+// sdkModelField property of a model
+type sdkModelField struct {
+	JSONName    string
+	Name        string // identifier in the SDK
+	Type        string
+	Required    bool
+	Description string
+	FromJSON    string // Python only: conversion of data["json_name"] into the field type
+}
+
+// sdkTypes maps the schemas of a spec to the types of one SDK language
+type sdkTypes struct {
+	schemas map[string]*OpenAPISchema
+	names   map[string]string // schema name → SDK identifier
+}
+
+// sdkReservedNames identifiers the SDK templates already declare, per language
+var sdkReservedNames = map[string]map[string]bool{
+	"go": {
+		"Client": true, "NewClient": true, "Error": true, "Environment": true, "Environments": true,
+		"WebSocketConn": true, "WebSocketDialFunc": true, "WebSocketHandler": true, "WebSocketClient": true,
+		"WebSocketMessage": true, "NewWebSocketClient": true,
+	},
+	"python": {
+		"APIError": true, "ENVIRONMENTS": true, "Any": true, "Dict": true, "List": true, "Optional": true,
+	},
+	"typescript": {
+		"APIError": true, "ENVIRONMENTS": true, "Environment": true, "SocketMessage": true, "WebSocketClient": true,
+		"Error": true, "Date": true, "Record": true, "Promise": true, "Response": true, "RequestInit": true,
+		"URLSearchParams": true, "WebSocket": true,
+	},
+}
+
+// newSDKTypes names the component schemas for a language; names colliding with the client code get a
+// "Model" suffix
+func newSDKTypes(spec *OpenAPISpec, language, className string) *sdkTypes {
+	types := &sdkTypes{schemas: make(map[string]*OpenAPISchema), names: make(map[string]string)}
+	if spec.Components == nil {
+		return types
+	}
+
+	reserved := map[string]bool{className: true}
+	for name := range sdkReservedNames[language] {
+		reserved[name] = true
+	}
+	used := make(map[string]bool)
+	for _, name := range sortedSchemaNames(spec.Components.Schemas) {
+		identifier := sdkIdentifier(name)
+		if reserved[identifier] {
+			identifier += "Model"
+		}
+		for base, i := identifier, 2; used[identifier]; i++ {
+			identifier = base + strconv.Itoa(i)
+		}
+		used[identifier] = true
+		types.schemas[name] = spec.Components.Schemas[name]
+		types.names[name] = identifier
+	}
+	return types
+}
+
+// sortedSchemaNames names of the component schemas, sorted
+func sortedSchemaNames(schemas map[string]*OpenAPISchema) []string {
+	names := make([]string, 0, len(schemas))
+	for name, schema := range schemas {
+		if schema != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ref resolves a "#/components/schemas/..." reference to the SDK identifier and its schema
+func (s *sdkTypes) ref(ref string) (string, *OpenAPISchema, bool) {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	identifier, ok := s.names[name]
+	if !ok {
+		return "", nil, false
+	}
+	return identifier, s.schemas[name], true
+}
+
+// isStructSchema reports whether a schema becomes a struct/interface/dataclass rather than an alias
+func isStructSchema(schema *OpenAPISchema) bool {
+	return schema.Ref == "" && len(schema.Properties) > 0 && (schema.Type == "" || schema.Type == "object")
+}
+
+// unwrapSchema unwraps an allOf with one schema, the form used to describe a $ref
+func unwrapSchema(schema *OpenAPISchema) *OpenAPISchema {
+	for schema != nil && schema.Ref == "" && len(schema.AllOf) == 1 && schema.Type == "" {
+		schema = schema.AllOf[0]
+	}
+	return schema
+}
+
+// additionalSchema schema of the values of a map, nil when any value is allowed
+func additionalSchema(schema *OpenAPISchema) *OpenAPISchema {
+	if additional, ok := schema.AdditionalProperties.(*OpenAPISchema); ok {
+		return additional
+	}
+	return nil
+}
+
+// models named types of the spec, in name order; typeOf renders a property type, fieldName its identifier
+func (s *sdkTypes) models(typeOf func(schema *OpenAPISchema, required bool) string, fieldName func(string) string) []sdkModel {
+	names := make([]string, 0, len(s.names))
+	for name := range s.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	models := make([]sdkModel, 0, len(names))
+	for _, name := range names {
+		schema := unwrapSchema(s.schemas[name])
+		model := sdkModel{Name: s.names[name], Description: oneLine(schema.Description)}
+		if !isStructSchema(schema) {
+			model.Alias = typeOf(schema, true)
+			models = append(models, model)
+			continue
+		}
+
+		required := make(map[string]bool, len(schema.Required))
+		for _, property := range schema.Required {
+			required[property] = true
+		}
+		properties := make([]string, 0, len(schema.Properties))
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			propertySchema := unwrapSchema(schema.Properties[property])
+			model.Fields = append(model.Fields, sdkModelField{
+				JSONName:    property,
+				Name:        fieldName(property),
+				Type:        typeOf(propertySchema, required[property] && !propertySchema.Nullable),
+				Required:    required[property],
+				Description: oneLine(propertySchema.Description),
+			})
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
+// oneLine joins a multi-line description for a single-line comment
+func oneLine(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// sdkSuccessSchema JSON schema of the first 2xx response, nil when it has none
+func sdkSuccessSchema(responses map[string]OpenAPIResponse) *OpenAPISchema {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if media, ok := responses[code].Content["application/json"]; ok && media.Schema != nil {
+			return unwrapSchema(media.Schema)
+		}
+	}
+	return nil
+}
+
+// sdkRequestSchema JSON schema of a request body, nil when it has none
+func sdkRequestSchema(body *OpenAPIRequestBody) *OpenAPISchema {
+	if body == nil {
+		return nil
+	}
+	if media, ok := body.Content["application/json"]; ok && media.Schema != nil {
+		return unwrapSchema(media.Schema)
+	}
+	return nil
+}
+
+// Go
+
+// goModelsTemplate model types of the Go SDK
+const goModelsTemplate = `{{range .Models}}
+// {{.Name}} {{if .Description}}{{.Description}}{{else}}model of the API{{end}}
+{{- if .Alias}}
+type {{.Name}} {{.Alias}}
+{{- else}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- if .Description}}
+	// {{.Description}}
+{{- end}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSONName}}{{if not .Required}},omitempty{{end}}"` + "`" + `
+{{- end}}
+}
+{{- end}}
+{{end}}`
+
+// goType Go type of a schema; optional structs are pointers so they can be omitted
+func (s *sdkTypes) goType(schema *OpenAPISchema, required bool) string {
+	schema = unwrapSchema(schema)
+	if schema == nil {
+		return "interface{}"
+	}
+	if schema.Ref != "" {
+		name, target, ok := s.ref(schema.Ref)
+		if !ok {
+			return "interface{}"
+		}
+		if !required && isStructSchema(unwrapSchema(target)) {
+			return "*" + name
+		}
+		return name
+	}
+
+	var goType string
+	switch schema.Type {
+	case "string":
+		goType = "string"
+		if schema.Format == "date-time" {
+			goType = "time.Time"
+		}
+	case "integer":
+		goType = "int"
+		if schema.Format == "int64" || schema.Format == "int32" {
+			goType = schema.Format
+		}
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		return "[]" + s.goType(schema.Items, true)
+	case "object":
+		if additional := additionalSchema(schema); additional != nil {
+			return "map[string]" + s.goType(additional, true)
+		}
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+	if !required && schema.Nullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+// goResultType return type of a method: structs are returned by pointer
+func (s *sdkTypes) goResultType(schema *OpenAPISchema) string {
+	if schema == nil {
+		return "interface{}"
+	}
+	return s.goType(schema, false)
+}
+
+// goZeroValue zero value returned with an error
+func goZeroValue(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["), goType == "interface{}":
+		return "nil"
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case goType == "int", goType == "int32", goType == "int64", goType == "float64":
+		return "0"
+	}
+	return goType + "{}"
+}
+
+// TypeScript
+
+// tsModelsTemplate model types of the TypeScript SDK
+const tsModelsTemplate = `{{range .Models}}
+/** {{if .Description}}{{.Description}}{{else}}{{.Name}} model of the API{{end}} */
+{{- if .Alias}}
+export type {{.Name}} = {{.Alias}};
+{{- else}}
+export interface {{.Name}} {
+{{- range .Fields}}
+{{- if .Description}}
+    /** {{.Description}} */
+{{- end}}
+    {{.Name}}{{if not .Required}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{- end}}
+{{end}}`
+
+// tsType TypeScript type of a schema
+func (s *sdkTypes) tsType(schema *OpenAPISchema, _ bool) string {
+	schema = unwrapSchema(schema)
+	if schema == nil {
+		return "any"
+	}
+	if schema.Ref != "" {
+		if name, _, ok := s.ref(schema.Ref); ok {
+			return name
+		}
+		return "any"
+	}
+
+	var tsType string
+	switch schema.Type {
+	case "string":
+		tsType = "string"
+		if len(schema.Enum) > 0 {
+			values := make([]string, 0, len(schema.Enum))
+			for _, value := range schema.Enum {
+				values = append(values, strconv.Quote(fmt.Sprint(value)))
+			}
+			tsType = strings.Join(values, " | ")
+		}
+	case "integer", "number":
+		tsType = "number"
+	case "boolean":
+		tsType = "boolean"
+	case "array":
+		item := s.tsType(schema.Items, true)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		tsType = item + "[]"
+	case "object":
+		tsType = "Record<string, any>"
+		if additional := additionalSchema(schema); additional != nil {
+			tsType = "Record<string, " + s.tsType(additional, true) + ">"
+		}
+	default:
+		return "any"
+	}
+	if schema.Nullable {
+		tsType += " | null"
+	}
+	return tsType
+}
+
+// tsPropertyName quotes the JSON names that are not identifiers
+func tsPropertyName(name string) string {
+	for i, r := range name {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return strconv.Quote(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// Python
+
+// pythonModelsTemplate dataclasses of the Python SDK, with the conversions from and to JSON
+const pythonModelsTemplate = `{{if .Models}}
+
+def _to_json(value: Any) -> Any:
+    if hasattr(value, 'to_dict'):
+        return value.to_dict()
+    if isinstance(value, list):
+        return [_to_json(item) for item in value]
+    if isinstance(value, dict):
+        return {key: _to_json(item) for key, item in value.items()}
+    return value
+{{- range .Models}}{{if not .Alias}}
+
+
+@dataclass
+class {{.Name}}:
+    """{{if .Description}}{{.Description}}{{else}}{{.Name}} model of the API{{end}}"""
+{{- range .Fields}}{{if .Required}}
+    {{.Name}}: {{.Type}}
+{{- end}}{{end}}
+{{- range .Fields}}{{if not .Required}}
+    {{.Name}}: Optional[{{.Type}}] = None
+{{- end}}{{end}}
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> '{{.Name}}':
+        return cls(
+{{- range .Fields}}
+            {{.Name}}={{.FromJSON}},
+{{- end}}
+        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        data = {
+{{- range .Fields}}
+            '{{.JSONName}}': _to_json(self.{{.Name}}),
+{{- end}}
+        }
+        return {key: value for key, value in data.items() if value is not None}
+{{- end}}{{end}}
+{{- range .Models}}{{if .Alias}}
+
+
+{{.Name}} = {{.Alias}}
+{{- end}}{{end}}
+{{end}}`
+
+// pythonType Python annotation of a schema
+func (s *sdkTypes) pythonType(schema *OpenAPISchema, _ bool) string {
+	schema = unwrapSchema(schema)
+	if schema == nil {
+		return "Any"
+	}
+	if schema.Ref != "" {
+		if name, _, ok := s.ref(schema.Ref); ok {
+			return name
+		}
+		return "Any"
+	}
+	switch schema.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List[" + s.pythonType(schema.Items, true) + "]"
+	case "object":
+		if additional := additionalSchema(schema); additional != nil {
+			return "Dict[str, " + s.pythonType(additional, true) + "]"
+		}
+		return "Dict[str, Any]"
+	}
+	return "Any"
+}
+
+// pythonFromJSON expression converting the JSON value expr to the type of the schema
+func (s *sdkTypes) pythonFromJSON(schema *OpenAPISchema, expr string) string {
+	schema = unwrapSchema(schema)
+	if schema == nil {
+		return expr
+	}
+	if schema.Ref != "" {
+		if name, target, ok := s.ref(schema.Ref); ok && isStructSchema(unwrapSchema(target)) {
+			return name + ".from_dict(" + expr + ")"
+		}
+		return expr
+	}
+	if schema.Type == "array" {
+		if item := s.pythonFromJSON(schema.Items, "item"); item != "item" {
+			return "[" + item + " for item in " + expr + "]"
+		}
+	}
+	return expr
+}
+
+// pythonModels models of the Python SDK, with the JSON conversion of each field
+func (s *sdkTypes) pythonModels() []sdkModel {
+	models := s.models(s.pythonType, pythonIdentifier)
+	for i := range models {
+		schema := unwrapSchema(s.schemas[s.schemaName(models[i].Name)])
+		for j := range models[i].Fields {
+			field := &models[i].Fields[j]
+			value := fmt.Sprintf("data.get('%s')", field.JSONName)
+			if converted := s.pythonFromJSON(schema.Properties[field.JSONName], fmt.Sprintf("data['%s']", field.JSONName)); converted != fmt.Sprintf("data['%s']", field.JSONName) {
+				value = fmt.Sprintf("%s if %s is not None else None", converted, value)
+			}
+			field.FromJSON = value
+		}
+	}
+	return models
+}
+
+// schemaName component name of an SDK identifier
+func (s *sdkTypes) schemaName(identifier string) string {
+	for name, id := range s.names {
+		if id == identifier {
+			return name
+		}
+	}
+	return ""
+}
+
+// pythonKeywords names that cannot be used as attributes
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true, "except": true,
+	"finally": true, "for": true, "from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true, "return": true, "try": true,
+	"while": true, "with": true, "yield": true,
+}
+
+// pythonIdentifier turns a JSON name into an attribute name: "user-id" -> "user_id", "class" -> "class_"
+func pythonIdentifier(name string) string {
+	var identifier strings.Builder
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r)):
+			identifier.WriteRune(r)
+		case unicode.IsDigit(r):
+			identifier.WriteString("_")
+			identifier.WriteRune(r)
+		default:
+			identifier.WriteString("_")
+		}
+	}
+	if identifier.Len() == 0 || pythonKeywords[identifier.String()] {
+		identifier.WriteString("_")
+	}
+	return identifier.String()
+}
@@ -0,0 +1,121 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func modelsSDKSpec() *OpenAPISpec {
+	ref := func(name string) *OpenAPISchema { return &OpenAPISchema{Ref: "#/components/schemas/" + name} }
+	jsonBody := func(schema *OpenAPISchema) map[string]MediaType {
+		return map[string]MediaType{"application/json": {Schema: schema}}
+	}
+	return &OpenAPISpec{
+		Info: OpenAPIInfo{Title: "Shop API", Version: "1.0.0"},
+		Paths: map[string]OpenAPIPath{
+			"/users": {
+				"get": {Summary: "List users", Responses: map[string]OpenAPIResponse{
+					"200": {Content: jsonBody(&OpenAPISchema{Type: "array", Items: ref("User")})},
+				}},
+				"post": {Summary: "Create user", RequestBody: &OpenAPIRequestBody{Content: jsonBody(ref("CreateUserRequest"))},
+					Responses: map[string]OpenAPIResponse{
+						"400": {Content: jsonBody(ref("Error"))},
+						"201": {Content: jsonBody(ref("User"))},
+					}},
+			},
+			"/users/{id}": {
+				"get": {Summary: "Get user", Parameters: []OpenAPIParameter{
+					{Name: "id", In: "path", Required: true, Schema: &OpenAPISchema{Type: "integer"}},
+					{Name: "expand", In: "query", Schema: &OpenAPISchema{Type: "boolean"}},
+				}, Responses: map[string]OpenAPIResponse{"200": {Content: jsonBody(ref("User"))}}},
+			},
+		},
+		Components: &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+			"User": {Type: "object", Required: []string{"id", "name"}, Properties: map[string]*OpenAPISchema{
+				"id":         {Type: "integer", Format: "int64"},
+				"name":       {Type: "string", Description: "Full name"},
+				"address":    ref("Address"),
+				"tags":       {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+				"created_at": {Type: "string", Format: "date-time"},
+				"class":      {Type: "string", Enum: []interface{}{"gold", "silver"}},
+			}},
+			"Address": {Type: "object", Properties: map[string]*OpenAPISchema{"city": {Type: "string"}}},
+			"CreateUserRequest": {Type: "object", Required: []string{"name"}, Properties: map[string]*OpenAPISchema{
+				"name": {Type: "string"},
+			}},
+			"Error": {Type: "object", Properties: map[string]*OpenAPISchema{"message": {Type: "string"}}},
+			"Tags":  {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+		}},
+	}
+}
+
+func TestSDKGenerators_TypedModels(t *testing.T) {
+	dir := t.TempDir()
+	config := &ClientSDKConfig{OutputDir: dir, PackageName: "shopapi"}
+	spec := modelsSDKSpec()
+	for _, generator := range []SDKGenerator{&GoSDKGenerator{}, &PythonSDKGenerator{}, &TypeScriptSDKGenerator{}} {
+		require.NoError(t, generator.Generate(spec, config), generator.GetLanguage())
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	goCode := read("go/client.go")
+	_, err := parser.ParseFile(token.NewFileSet(), "client.go", goCode, parser.AllErrors)
+	assert.NoError(t, err)
+	assert.Contains(t, goCode, "type User struct {")
+	assert.Contains(t, goCode, "\tID int64 `json:\"id\"`")
+	assert.Contains(t, goCode, "\tAddress *Address `json:\"address,omitempty\"`")
+	assert.Contains(t, goCode, "\tCreatedAt time.Time `json:\"created_at,omitempty\"`")
+	assert.Contains(t, goCode, "type ErrorModel struct {", "names declared by the client are suffixed")
+	assert.Contains(t, goCode, "type Tags []string")
+	assert.Contains(t, goCode, "func (c *Client) CreateUsers(ctx context.Context, requestBody CreateUserRequest) (*User, error) {")
+	assert.Contains(t, goCode, "func (c *Client) GetUsers(ctx context.Context) ([]User, error) {")
+	assert.Contains(t, goCode, "return &result, nil")
+	assert.Contains(t, goCode, `requestURL := c.BaseURL + "/users/" + url.PathEscape(fmt.Sprint(id)) + ""`)
+
+	pythonCode := read("python/client.py")
+	assert.Contains(t, pythonCode, "@dataclass\nclass User:")
+	assert.Contains(t, pythonCode, "    id: int\n    name: str\n    address: Optional[Address] = None", "required fields come first")
+	assert.Contains(t, pythonCode, "class_: Optional[str] = None")
+	assert.Contains(t, pythonCode, "address=Address.from_dict(data['address']) if data.get('address') is not None else None,")
+	assert.Contains(t, pythonCode, "'class': _to_json(self.class_),")
+	assert.Contains(t, pythonCode, "Tags = List[str]")
+	assert.Contains(t, pythonCode, "def create_users(self, request_body: CreateUserRequest) -> User:")
+	assert.Contains(t, pythonCode, "return [User.from_dict(item) for item in response.json()]")
+
+	tsCode := read("typescript/client.ts")
+	assert.Contains(t, tsCode, "export interface User {")
+	assert.Contains(t, tsCode, "    address?: Address;")
+	assert.Contains(t, tsCode, `    class?: "gold" | "silver";`)
+	assert.Contains(t, tsCode, "    /** Full name */\n    name: string;")
+	assert.Contains(t, tsCode, "export interface ErrorModel {", "the global Error is not shadowed")
+	assert.Contains(t, tsCode, "export type Tags = string[];")
+	assert.Contains(t, tsCode, "async postUsers(requestBody: CreateUserRequest): Promise<User> {")
+	assert.Contains(t, tsCode, "return await response.json() as User[];")
+}
+
+func TestSDKTypes(t *testing.T) {
+	types := newSDKTypes(modelsSDKSpec(), "go", "Client")
+
+	assert.Equal(t, "map[string]float64", types.goType(&OpenAPISchema{Type: "object", AdditionalProperties: &OpenAPISchema{Type: "number"}}, true))
+	assert.Equal(t, "*string", types.goType(&OpenAPISchema{Type: "string", Nullable: true}, false))
+	assert.Equal(t, "interface{}", types.goType(&OpenAPISchema{Ref: "#/components/schemas/Unknown"}, true))
+	assert.Equal(t, "User", types.goType(&OpenAPISchema{AllOf: []*OpenAPISchema{{Ref: "#/components/schemas/User"}}}, true))
+
+	assert.Equal(t, "nil", goZeroValue("*User"))
+	assert.Equal(t, `""`, goZeroValue("string"))
+	assert.Equal(t, "time.Time{}", goZeroValue("time.Time"))
+
+	assert.Equal(t, `"content-type"`, tsPropertyName("content-type"))
+	assert.Equal(t, "_2fa", pythonIdentifier("2fa"))
+	assert.Equal(t, "user_id", pythonIdentifier("user-id"))
+}
@@ -0,0 +1,425 @@
+package decorators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WebSocketExtension operation extension listing the message types of a @WebSocket route
+const WebSocketExtension = "x-websocket"
+
+// sdkWebSocketEndpoint WebSocket route as seen by the SDK generators
+type sdkWebSocketEndpoint struct {
+	Path         string
+	Name         string // exported identifier derived from the path, e.g. "WsChat"
+	Parameters   []OpenAPIParameter
+	MessageTypes []sdkWebSocketMessage
+}
+
+// sdkWebSocketMessage message type handled by a WebSocket route
+type sdkWebSocketMessage struct {
+	Type string // wire value of the "type" field
+	Name string // exported identifier, e.g. "ChatMessage" for "chat_message"
+}
+
+// websocketExtension builds the x-websocket extension of a route, or nil when it is not a WebSocket route
+func websocketExtension(route *RouteEntry) map[string]interface{} {
+	isWebSocket := route.Method == "WS"
+	for _, mw := range route.MiddlewareInfo {
+		if mw.Name == "WebSocket" {
+			isWebSocket = true
+		}
+	}
+	if !isWebSocket {
+		return nil
+	}
+
+	messageTypes := append([]string{}, route.WebSocketHandlers...)
+	return map[string]interface{}{"messageTypes": messageTypes}
+}
+
+// isWebSocketOperation reports whether an operation describes a WebSocket route
+func isWebSocketOperation(method string, operation *OpenAPIOperation) bool {
+	if strings.EqualFold(method, "ws") {
+		return true
+	}
+	_, ok := operation.Extensions[WebSocketExtension]
+	return ok
+}
+
+// collectWebSocketEndpoints returns the WebSocket routes of a spec, sorted by path
+func collectWebSocketEndpoints(spec *OpenAPISpec) []sdkWebSocketEndpoint {
+	byPath := make(map[string]*sdkWebSocketEndpoint)
+
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem {
+			if operation == nil || !isWebSocketOperation(method, operation) {
+				continue
+			}
+
+			endpoint, exists := byPath[path]
+			if !exists {
+				endpoint = &sdkWebSocketEndpoint{Path: path, Name: sdkIdentifier(path)}
+				byPath[path] = endpoint
+			}
+			if len(endpoint.Parameters) == 0 {
+				endpoint.Parameters = operation.Parameters
+			}
+			for _, messageType := range websocketMessageTypes(operation.Extensions[WebSocketExtension]) {
+				endpoint.addMessageType(messageType)
+			}
+		}
+	}
+
+	endpoints := make([]sdkWebSocketEndpoint, 0, len(byPath))
+	for _, endpoint := range byPath {
+		endpoints = append(endpoints, *endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Path < endpoints[j].Path })
+	return endpoints
+}
+
+// addMessageType adds a message type once
+func (e *sdkWebSocketEndpoint) addMessageType(messageType string) {
+	for _, existing := range e.MessageTypes {
+		if existing.Type == messageType {
+			return
+		}
+	}
+	e.MessageTypes = append(e.MessageTypes, sdkWebSocketMessage{Type: messageType, Name: sdkIdentifier(messageType)})
+}
+
+// websocketMessageTypes reads the message types of an x-websocket extension, either built in memory or decoded from JSON
+func websocketMessageTypes(extension interface{}) []string {
+	values, ok := extension.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	switch typed := values["messageTypes"].(type) {
+	case []string:
+		return typed
+	case []interface{}:
+		messageTypes := make([]string, 0, len(typed))
+		for _, value := range typed {
+			if messageType, ok := value.(string); ok && messageType != "" {
+				messageTypes = append(messageTypes, messageType)
+			}
+		}
+		return messageTypes
+	}
+	return nil
+}
+
+// sdkIdentifier converts a path or message type into an exported identifier: "/ws/chat-room" -> "WsChatRoom"
+func sdkIdentifier(value string) string {
+	var name strings.Builder
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+
+	identifier := name.String()
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) {
+		identifier = "Socket" + identifier
+	}
+	return identifier
+}
+
+// lowerFirst lowercases the first letter of an identifier
+func lowerFirst(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToLower(value[:1]) + value[1:]
+}
+
+// Go helpers
+
+// goWebSocketSupport common WebSocket client code of the Go SDK
+const goWebSocketSupport = `
+// WebSocketConn is the connection used by the WebSocket helpers.
+// *websocket.Conn from github.com/gorilla/websocket satisfies it.
+type WebSocketConn interface {
+	ReadJSON(v interface{}) error
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
+// WebSocketDialFunc opens a WebSocket connection, e.g. wrapping websocket.DefaultDialer.DialContext
+type WebSocketDialFunc func(ctx context.Context, url string, header http.Header) (WebSocketConn, error)
+
+// WebSocketMessage is the envelope exchanged with the server
+type WebSocketMessage struct {
+	Type     string                 ` + "`json:\"type\"`" + `
+	Data     json.RawMessage        ` + "`json:\"data,omitempty\"`" + `
+	Target   string                 ` + "`json:\"target,omitempty\"`" + `
+	Group    string                 ` + "`json:\"group,omitempty\"`" + `
+	Metadata map[string]interface{} ` + "`json:\"metadata,omitempty\"`" + `
+}
+
+// WebSocketHandler handles the data of one message type
+type WebSocketHandler func(data json.RawMessage, message *WebSocketMessage)
+
+// WebSocketClient sends and dispatches messages of a WebSocket connection
+type WebSocketClient struct {
+	conn     WebSocketConn
+	writeMu  sync.Mutex
+	mu       sync.RWMutex
+	handlers map[string][]WebSocketHandler
+}
+
+// NewWebSocketClient wraps an open connection
+func NewWebSocketClient(conn WebSocketConn) *WebSocketClient {
+	return &WebSocketClient{conn: conn, handlers: make(map[string][]WebSocketHandler)}
+}
+
+// Send sends a message of the given type
+func (w *WebSocketClient) Send(messageType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding %s message: %w", messageType, err)
+	}
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	return w.conn.WriteJSON(WebSocketMessage{Type: messageType, Data: payload})
+}
+
+// On registers a handler for a message type; "*" receives every message
+func (w *WebSocketClient) On(messageType string, handler WebSocketHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[messageType] = append(w.handlers[messageType], handler)
+}
+
+// Listen reads messages and dispatches them to the handlers until the connection fails or ctx is done
+func (w *WebSocketClient) Listen(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		w.conn.Close()
+	}()
+
+	for {
+		var message WebSocketMessage
+		if err := w.conn.ReadJSON(&message); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		w.mu.RLock()
+		handlers := append(append([]WebSocketHandler{}, w.handlers[message.Type]...), w.handlers["*"]...)
+		w.mu.RUnlock()
+		for _, handler := range handlers {
+			handler(message.Data, &message)
+		}
+	}
+}
+
+// Close closes the connection
+func (w *WebSocketClient) Close() error {
+	return w.conn.Close()
+}
+
+// websocketURL converts an HTTP URL into its WebSocket equivalent
+func websocketURL(httpURL string) string {
+	if strings.HasPrefix(httpURL, "http") {
+		return "ws" + strings.TrimPrefix(httpURL, "http")
+	}
+	return httpURL
+}
+`
+
+// goWebSocketEndpoints template of the per-route Go helpers
+const goWebSocketEndpoints = `{{range .WebSockets}}
+// {{.Name}}URL returns the WebSocket URL of {{.Path}}
+func (c *Client) {{.Name}}URL({{.URLSignature}}) string {
+	{{.URLConstruction}}
+	return websocketURL(requestURL)
+}
+
+// {{.Name}}Socket typed connection to {{.Path}}
+type {{.Name}}Socket struct {
+	*WebSocketClient
+}
+
+// Connect{{.Name}} opens a connection to {{.Path}} using c.WebSocketDialer
+func (c *Client) Connect{{.Name}}(ctx context.Context{{.ParametersSignature}}) (*{{.Name}}Socket, error) {
+	if c.WebSocketDialer == nil {
+		return nil, fmt.Errorf("WebSocketDialer is not set")
+	}
+	header := http.Header{}
+	header.Set("User-Agent", c.UserAgent)
+	if c.APIKey != "" {
+		header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	conn, err := c.WebSocketDialer(ctx, c.{{.Name}}URL({{.Arguments}}), header)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to {{.Path}}: %w", err)
+	}
+	return &{{.Name}}Socket{NewWebSocketClient(conn)}, nil
+}
+{{$socket := .Name}}{{range .MessageTypes}}
+// Send{{.Name}} sends a "{{.Type}}" message
+func (s *{{$socket}}Socket) Send{{.Name}}(data interface{}) error {
+	return s.Send({{printf "%q" .Type}}, data)
+}
+
+// On{{.Name}} handles "{{.Type}}" messages
+func (s *{{$socket}}Socket) On{{.Name}}(handler WebSocketHandler) {
+	s.On({{printf "%q" .Type}}, handler)
+}
+{{end}}{{end}}`
+
+// prepareWebSocketData builds the template data of the Go WebSocket helpers
+func (g *GoSDKGenerator) prepareWebSocketData(spec *OpenAPISpec) []map[string]interface{} {
+	endpoints := collectWebSocketEndpoints(spec)
+	data := make([]map[string]interface{}, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		arguments := make([]string, 0, len(endpoint.Parameters))
+		for _, param := range endpoint.Parameters {
+			if param.In == "path" || param.In == "query" {
+				arguments = append(arguments, param.Name)
+			}
+		}
+		signature := g.generateParametersSignature(urlParameters(endpoint.Parameters))
+
+		data = append(data, map[string]interface{}{
+			"Name":                endpoint.Name,
+			"Path":                endpoint.Path,
+			"ParametersSignature": signature,
+			"URLSignature":        strings.TrimPrefix(signature, ", "),
+			"URLConstruction":     g.generateURLConstruction(endpoint.Path, endpoint.Parameters),
+			"Arguments":           strings.Join(arguments, ", "),
+			"MessageTypes":        endpoint.MessageTypes,
+		})
+	}
+	return data
+}
+
+// urlParameters keeps the parameters that are part of the URL
+func urlParameters(params []OpenAPIParameter) []OpenAPIParameter {
+	filtered := make([]OpenAPIParameter, 0, len(params))
+	for _, param := range params {
+		if param.In == "path" || param.In == "query" {
+			filtered = append(filtered, param)
+		}
+	}
+	return filtered
+}
+
+// TypeScript helpers
+
+// tsWebSocketSupport common WebSocket client code of the TypeScript SDK
+const tsWebSocketSupport = `
+export interface SocketMessage<T extends string = string> {
+    type: T;
+    data?: any;
+    target?: string;
+    group?: string;
+    metadata?: Record<string, any>;
+}
+
+export type SocketHandler<T extends string = string> = (data: any, message: SocketMessage<T>) => void;
+
+export class DecoSocket<T extends string = string> {
+    readonly socket: WebSocket;
+    private handlers = new Map<string, SocketHandler<T>[]>();
+
+    constructor(url: string, protocols?: string | string[]) {
+        this.socket = new WebSocket(url, protocols);
+        this.socket.onmessage = (event: MessageEvent) => {
+            const message = JSON.parse(event.data) as SocketMessage<T>;
+            const handlers = [...(this.handlers.get(message.type) ?? []), ...(this.handlers.get('*') ?? [])];
+            handlers.forEach((handler) => handler(message.data, message));
+        };
+    }
+
+    send(type: T, data?: any): void {
+        this.socket.send(JSON.stringify({ type, data }));
+    }
+
+    on(type: T | '*', handler: SocketHandler<T>): () => void {
+        const handlers = this.handlers.get(type) ?? [];
+        handlers.push(handler);
+        this.handlers.set(type, handlers);
+        return () => this.handlers.set(type, (this.handlers.get(type) ?? []).filter((h) => h !== handler));
+    }
+
+    close(code?: number, reason?: string): void {
+        this.socket.close(code, reason);
+    }
+}
+{{range .WebSockets}}{{$socket := .Name}}
+export type {{.Name}}MessageType = {{.MessageUnion}};
+
+export class {{.Name}}Socket extends DecoSocket<{{.Name}}MessageType> {
+{{- range .MessageTypes}}
+    send{{.Name}}(data?: any): void {
+        this.send('{{.Type}}', data);
+    }
+
+    on{{.Name}}(handler: SocketHandler<{{$socket}}MessageType>): () => void {
+        return this.on('{{.Type}}', handler);
+    }
+{{- end}}
+}
+{{end}}`
+
+// tsWebSocketMethods template of the per-route methods of the TypeScript client class
+const tsWebSocketMethods = `{{range .WebSockets}}
+    {{.URLMethod}}({{.ParametersSignature}}): string {
+        {{.URLConstruction}}
+        return url.replace(/^http/, 'ws');
+    }
+
+    connect{{.Name}}({{.ParametersSignature}}{{if .ParametersSignature}}, {{end}}protocols?: string | string[]): {{.Name}}Socket {
+        return new {{.Name}}Socket(this.{{.URLMethod}}({{.Arguments}}), protocols);
+    }
+{{end}}`
+
+// prepareWebSocketData builds the template data of the TypeScript WebSocket helpers
+func (t *TypeScriptSDKGenerator) prepareWebSocketData(spec *OpenAPISpec) []map[string]interface{} {
+	endpoints := collectWebSocketEndpoints(spec)
+	data := make([]map[string]interface{}, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		params := urlParameters(endpoint.Parameters)
+		arguments := make([]string, 0, len(params))
+		for _, param := range params {
+			arguments = append(arguments, param.Name)
+		}
+
+		union := "string"
+		if len(endpoint.MessageTypes) > 0 {
+			quoted := make([]string, 0, len(endpoint.MessageTypes))
+			for _, message := range endpoint.MessageTypes {
+				quoted = append(quoted, fmt.Sprintf("'%s'", message.Type))
+			}
+			union = strings.Join(quoted, " | ")
+		}
+
+		data = append(data, map[string]interface{}{
+			"Name":                endpoint.Name,
+			"URLMethod":           lowerFirst(endpoint.Name) + "URL",
+			"ParametersSignature": t.generateParametersSignature(params),
+			"URLConstruction":     t.generateURLConstruction(endpoint.Path, endpoint.Parameters),
+			"Arguments":           strings.Join(arguments, ", "),
+			"MessageTypes":        endpoint.MessageTypes,
+			"MessageUnion":        union,
+		})
+	}
+	return data
+}
@@ -0,0 +1,85 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func websocketSDKSpec() *OpenAPISpec {
+	routes := []RouteEntry{
+		{Method: "GET", Path: "/users", FuncName: "ListUsers"},
+		{
+			Method:            "GET",
+			Path:              "/ws/chat",
+			FuncName:          "Chat",
+			MiddlewareInfo:    []MiddlewareInfo{{Name: "WebSocket", Args: map[string]interface{}{}}},
+			WebSocketHandlers: []string{"chat_message", "join"},
+		},
+		{Method: "WS", Path: "/ws/Notify", FuncName: "Notify", WebSocketHandlers: []string{"notify"}},
+	}
+	return buildOpenAPISpec(DefaultConfig(), routes, nil)
+}
+
+func TestOpenAPIWebSocketExtension(t *testing.T) {
+	spec := websocketSDKSpec()
+
+	operation := spec.Paths["/ws/chat"]["get"]
+	if assert.NotNil(t, operation) {
+		assert.Equal(t, []string{"chat_message", "join"}, websocketMessageTypes(operation.Extensions[WebSocketExtension]))
+	}
+	assert.NotContains(t, spec.Paths["/users"]["get"].Extensions, WebSocketExtension)
+
+	endpoints := collectWebSocketEndpoints(spec)
+	if assert.Len(t, endpoints, 2) {
+		assert.Equal(t, "WsNotify", endpoints[0].Name)
+		assert.Equal(t, "WsChat", endpoints[1].Name)
+		assert.Equal(t, "ChatMessage", endpoints[1].MessageTypes[0].Name)
+	}
+
+	// Specs decoded from JSON carry []interface{}
+	assert.Equal(t, []string{"a"}, websocketMessageTypes(map[string]interface{}{"messageTypes": []interface{}{"a", ""}}))
+}
+
+func TestGoSDKWebSocketHelpers(t *testing.T) {
+	dir := t.TempDir()
+	config := &ClientSDKConfig{OutputDir: dir, PackageName: "chatapi"}
+	assert.NoError(t, (&GoSDKGenerator{}).Generate(websocketSDKSpec(), config))
+
+	path := filepath.Join(dir, "go", "client.go")
+	source, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), path, source, parser.AllErrors)
+	assert.NoError(t, err, "generated Go client must parse")
+
+	code := string(source)
+	assert.Contains(t, code, `"sync"`)
+	assert.Contains(t, code, "WebSocketDialer WebSocketDialFunc")
+	assert.Contains(t, code, "func (c *Client) WsChatURL() string")
+	assert.Contains(t, code, "func (c *Client) ConnectWsChat(ctx context.Context) (*WsChatSocket, error)")
+	assert.Contains(t, code, "func (s *WsChatSocket) SendChatMessage(data interface{}) error")
+	assert.Contains(t, code, `s.On("join", handler)`)
+	assert.NotContains(t, code, "func (c *Client) GetWsChat(", "WebSocket routes get no HTTP method")
+}
+
+func TestTypeScriptSDKWebSocketHelpers(t *testing.T) {
+	dir := t.TempDir()
+	config := &ClientSDKConfig{OutputDir: dir, PackageName: "chatapi"}
+	assert.NoError(t, (&TypeScriptSDKGenerator{}).Generate(websocketSDKSpec(), config))
+
+	source, err := os.ReadFile(filepath.Join(dir, "typescript", "client.ts"))
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "export class DecoSocket<T extends string = string>")
+	assert.Contains(t, code, "export type WsChatMessageType = 'chat_message' | 'join';")
+	assert.Contains(t, code, "sendChatMessage(data?: any): void")
+	assert.Contains(t, code, "onJoin(handler: SocketHandler<WsChatMessageType>): () => void")
+	assert.Contains(t, code, "connectWsChat(protocols?: string | string[]): WsChatSocket")
+	assert.Contains(t, code, "return url.replace(/^http/, 'ws');")
+	assert.NotContains(t, code, "async getWsChat(")
+}
@@ -0,0 +1,450 @@
+package decorators
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Content codings of Accept-Encoding
+const (
+	CompressionBrotli  = "br"
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+)
+
+// Defaults of compression and @Compress
+const (
+	DefaultCompressionLevel   = 5
+	DefaultCompressionMinSize = "1KB"
+)
+
+var (
+	// DefaultCompressionTypes content types compressed when no types are configured
+	DefaultCompressionTypes = []string{
+		"text/*",
+		"application/json",
+		"application/*+json",
+		"application/javascript",
+		"application/xml",
+		"application/*+xml",
+		"image/svg+xml",
+	}
+
+	// DefaultCompressionEncodings encodings offered when none are configured, preferred first
+	DefaultCompressionEncodings = []string{CompressionBrotli, CompressionGzip, CompressionDeflate}
+)
+
+// CompressorFactory creates the writer of a content coding, compressing into w at level
+type CompressorFactory func(w io.Writer, level int) (io.WriteCloser, error)
+
+var (
+	compressors = map[string]CompressorFactory{
+		CompressionBrotli:  newBrotliCompressor,
+		CompressionGzip:    newGzipCompressor,
+		CompressionDeflate: newDeflateCompressor,
+	}
+	compressorsMux sync.RWMutex
+
+	compressionSettings atomic.Pointer[CompressionConfig]
+)
+
+// RegisterCompressor registers the writer of a content coding, e.g. zstd, or replaces a built-in one
+// (br, gzip and deflate)
+func RegisterCompressor(encoding string, factory CompressorFactory) {
+	compressorsMux.Lock()
+	defer compressorsMux.Unlock()
+	compressors[encoding] = factory
+}
+
+// getCompressor returns the factory registered for encoding
+func getCompressor(encoding string) (CompressorFactory, bool) {
+	compressorsMux.RLock()
+	defer compressorsMux.RUnlock()
+	factory, exists := compressors[encoding]
+	return factory, exists
+}
+
+// newBrotliCompressor brotli writer, levels 1 to 11
+func newBrotliCompressor(w io.Writer, level int) (io.WriteCloser, error) {
+	return brotli.NewWriterLevel(w, level), nil
+}
+
+// newGzipCompressor gzip writer; levels above 9 are capped
+func newGzipCompressor(w io.Writer, level int) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, min(level, gzip.BestCompression))
+}
+
+// newDeflateCompressor deflate writer; levels above 9 are capped
+func newDeflateCompressor(w io.Writer, level int) (io.WriteCloser, error) {
+	return flate.NewWriter(w, min(level, flate.BestCompression))
+}
+
+// ConfigureCompression sets the defaults of @Compress from the compression section
+func ConfigureCompression(config CompressionConfig) error {
+	if err := config.validate("compression"); err != nil {
+		return err
+	}
+	compressionSettings.Store(&config)
+	return nil
+}
+
+// compressionConfig compression of ConfigureCompression, or the default one
+func compressionConfig() CompressionConfig {
+	if config := compressionSettings.Load(); config != nil {
+		return *config
+	}
+	return CompressionConfig{}
+}
+
+// validate checks the settings, section naming them in the errors ("compression" or "@Compress")
+func (c CompressionConfig) validate(section string) error {
+	if c.Level < 0 || c.Level > 11 {
+		return fmt.Errorf("invalid %s level %d (valid: 1-11)", section, c.Level)
+	}
+	if c.MinSize != "" {
+		if _, err := ParseByteSize(c.MinSize); err != nil {
+			return fmt.Errorf("invalid %s min_size: %v", section, err)
+		}
+	}
+	for _, pattern := range c.Types {
+		if _, err := path.Match(pattern, "text/plain"); err != nil || !strings.Contains(pattern, "/") {
+			return fmt.Errorf("invalid %s type '%s' (expected type/subtype)", section, pattern)
+		}
+	}
+	return nil
+}
+
+// resolve fills the unset settings of a route from the global ones, then from the defaults
+func (c CompressionConfig) resolve(global CompressionConfig) compressionSettingsResolved {
+	resolved := compressionSettingsResolved{
+		types:     firstNonEmpty(c.Types, global.Types, DefaultCompressionTypes),
+		encodings: firstNonEmpty(c.Encodings, global.Encodings, DefaultCompressionEncodings),
+		level:     DefaultCompressionLevel,
+	}
+	if c.Level > 0 {
+		resolved.level = c.Level
+	} else if global.Level > 0 {
+		resolved.level = global.Level
+	}
+
+	minSize := DefaultCompressionMinSize
+	if c.MinSize != "" {
+		minSize = c.MinSize
+	} else if global.MinSize != "" {
+		minSize = global.MinSize
+	}
+	resolved.minSize, _ = ParseByteSize(minSize) // validated when configured
+	return resolved
+}
+
+// compressionSettingsResolved settings of a request
+type compressionSettingsResolved struct {
+	types     []string
+	encodings []string
+	level     int
+	minSize   int64
+}
+
+// firstNonEmpty first list with items
+func firstNonEmpty(lists ...[]string) []string {
+	for _, list := range lists {
+		if len(list) > 0 {
+			return list
+		}
+	}
+	return nil
+}
+
+// parseCompressArgs parses @Compress(types="application/json", level=5, min_size=2KB, encodings="gzip")
+func parseCompressArgs(args []string) (CompressionConfig, error) {
+	var config CompressionConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@Compress: unexpected argument '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "types":
+			config.Types = splitMarkerList(value)
+		case "level":
+			level, err := strconv.Atoi(value)
+			if err != nil || level < 1 {
+				return config, fmt.Errorf("@Compress: invalid level '%s' (valid: 1-11)", value)
+			}
+			config.Level = level
+		case "min_size":
+			config.MinSize = value
+		case "encodings":
+			config.Encodings = splitMarkerList(value)
+		default:
+			return config, fmt.Errorf("@Compress: unknown argument '%s' (valid: types, level, min_size, encodings)", key)
+		}
+	}
+	return config, config.validate("@Compress")
+}
+
+// negotiateEncoding picks the encoding of Accept-Encoding with the highest quality among the registered
+// ones of offered, the first offered on ties; "" when none is acceptable
+func negotiateEncoding(acceptEncoding string, offered []string) string {
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if name == "*" {
+			wildcard = quality
+		} else {
+			qualities[name] = quality
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range offered {
+		if _, registered := getCompressor(encoding); !registered {
+			continue
+		}
+		quality, listed := qualities[encoding]
+		if !listed {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressibleType reports whether the media type of contentType matches one of types
+func compressibleType(contentType string, types []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range types {
+		if matched, _ := path.Match(strings.ToLower(pattern), mediaType); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds value to the Vary header unless it is already listed
+func addVary(header http.Header, value string) {
+	for _, existing := range header.Values("Vary") {
+		for _, name := range strings.Split(existing, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
+
+// compressWriter compresses the response once it is known to be worth it: the decision is taken on the
+// first Flush, once min_size bytes were written or when the handler returns. Until then writes are held.
+type compressWriter struct {
+	gin.ResponseWriter
+	settings   compressionSettingsResolved
+	encoding   string
+	factory    CompressorFactory
+	buffer     []byte
+	decided    bool
+	compressor io.WriteCloser // nil when the response is sent as is
+}
+
+// decide compresses or not the response of size bytes (-1 when streaming) and sends the headers
+func (w *compressWriter) decide(size int64) {
+	w.decided = true
+	if !w.shouldCompress(size) {
+		return
+	}
+
+	compressor, err := w.factory(w.ResponseWriter, w.settings.level)
+	if err != nil {
+		LogVerbose("⚠️  @Compress: %s writer: %v", w.encoding, err)
+		return
+	}
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	// The compressed body is a different representation: a strong ETag would claim byte equality
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+	w.compressor = compressor
+}
+
+// shouldCompress applies the exclusion rules to the response
+func (w *compressWriter) shouldCompress(size int64) bool {
+	switch status := w.Status(); {
+	case status < http.StatusOK, status == http.StatusNoContent, status == http.StatusPartialContent, status == http.StatusNotModified:
+		return false
+	}
+	header := w.Header()
+	if encoding := header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		return false // already compressed, e.g. by an inner @Compress or a proxied upstream
+	}
+	if strings.Contains(header.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+	if size >= 0 && size < w.settings.minSize {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" && len(w.buffer) > 0 {
+		contentType = http.DetectContentType(w.buffer)
+	}
+	return compressibleType(contentType, w.settings.types)
+}
+
+// release writes the held bytes through the chosen writer
+func (w *compressWriter) release() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	buffer := w.buffer
+	w.buffer = nil
+	_, err := w.writeDecided(buffer)
+	return err
+}
+
+// writeDecided writes once the decision is taken
+func (w *compressWriter) writeDecided(data []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.writeDecided(data)
+	}
+
+	// A declared Content-Length decides at once
+	if length, err := strconv.ParseInt(w.Header().Get("Content-Length"), 10, 64); err == nil {
+		w.buffer = append(w.buffer, data...)
+		w.decide(length)
+		return len(data), w.release()
+	}
+
+	w.buffer = append(w.buffer, data...)
+	if int64(len(w.buffer)) >= w.settings.minSize {
+		w.decide(int64(len(w.buffer)))
+		return len(data), w.release()
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers: a response whose headers go out before any body (SSE, bodiless
+// aborts) is sent as is
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided && len(w.buffer) == 0 {
+		w.decided = true
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written reports held bytes as written, as the handler already produced them
+func (w *compressWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush streams: the held bytes and the compressor buffer are sent at every flush
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(-1)
+	}
+	if err := w.release(); err != nil {
+		LogVerbose("⚠️  @Compress: %v", err)
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			LogVerbose("⚠️  @Compress: %v", err)
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close sends what the handler left: the held bytes, or the end of the compressed stream
+func (w *compressWriter) close() {
+	if !w.decided {
+		w.decide(int64(len(w.buffer)))
+	}
+	if err := w.release(); err != nil {
+		LogVerbose("⚠️  @Compress: %v", err)
+	}
+	if w.compressor != nil {
+		if err := w.compressor.Close(); err != nil {
+			LogVerbose("⚠️  @Compress: %v", err)
+		}
+	}
+}
+
+// CompressMiddleware compresses the responses of a route (@Compress) with the encoding negotiated from
+// Accept-Encoding. Unset settings come from the compression section, then from the defaults. Responses
+// already encoded, smaller than min_size, of other content types, marked no-transform or bodiless are
+// sent as is; a handler that flushes gets each chunk compressed and flushed.
+func CompressMiddleware(config CompressionConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings := config.resolve(compressionConfig())
+		addVary(c.Writer.Header(), "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), settings.encodings)
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		factory, _ := getCompressor(encoding)
+		writer := &compressWriter{ResponseWriter: c.Writer, settings: settings, encoding: encoding, factory: factory}
+		c.Writer = writer
+		// On a panic the held bytes are dropped, so the recovery can still answer
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+		writer.close()
+	}
+}
+
+// createCompressMiddleware creates response compression middleware
+func createCompressMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseCompressArgs(args)
+	if err != nil {
+		// Rejected during generation; invalid hand-written calls use the global settings
+		LogSilent("⚠️  %v", err)
+		config = CompressionConfig{}
+	}
+	return CompressMiddleware(config)
+}
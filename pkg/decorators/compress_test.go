@@ -0,0 +1,235 @@
+package decorators
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compressTestRouter serves /test with handler behind @Compress(args)
+func compressTestRouter(args []string, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Any("/test", createCompressMiddleware(args), handler)
+	return router
+}
+
+// jsonOfSize responds a JSON string of size bytes
+func jsonOfSize(size int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`"`+strings.Repeat("a", size-2)+`"`))
+	}
+}
+
+func compressRequest(router *gin.Engine, method, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/test", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, body []byte) string {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	offered := DefaultCompressionEncodings
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"gzip, deflate, br", "br"}, // ties go to the server preference
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"GZIP", "gzip"},
+		{"gzip;q=0", ""},
+		{"*", "br"},
+		{"*;q=0.1, deflate;q=0.5", "deflate"},
+		{"gzip;q=0, *", "br"},
+		{"identity", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, negotiateEncoding(tt.accept, offered), tt.accept)
+	}
+	assert.Equal(t, "deflate", negotiateEncoding("gzip, deflate", []string{"deflate", "gzip"}))
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	router := compressTestRouter(nil, jsonOfSize(4096))
+
+	w := compressRequest(router, http.MethodGet, "gzip")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Less(t, w.Body.Len(), 4096)
+	assert.Len(t, gunzip(t, w.Body.Bytes()), 4096)
+
+	// Without an acceptable encoding the response is sent as is, still varying
+	w = compressRequest(router, http.MethodGet, "")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, 4096, w.Body.Len())
+
+	w = compressRequest(router, http.MethodHead, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+}
+
+func TestCompressMiddleware_Exclusions(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  gin.HandlerFunc
+		encoding string // Content-Encoding set by the handler
+	}{
+		{"below min_size", jsonOfSize(100), ""},
+		{"content type not listed", func(c *gin.Context) {
+			c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{1}, 4096))
+		}, ""},
+		{"already encoded", func(c *gin.Context) {
+			c.Header("Content-Encoding", "deflate")
+			c.Data(http.StatusOK, "application/json", bytes.Repeat([]byte{1}, 4096))
+		}, "deflate"},
+		{"no-transform", func(c *gin.Context) {
+			c.Header("Cache-Control", "no-transform")
+			jsonOfSize(4096)(c)
+		}, ""},
+		{"partial content", func(c *gin.Context) {
+			c.Data(http.StatusPartialContent, "application/json", bytes.Repeat([]byte{'a'}, 4096))
+		}, ""},
+		{"no content", func(c *gin.Context) { c.Status(http.StatusNoContent) }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := compressRequest(compressTestRouter(nil, tt.handler), http.MethodGet, "gzip")
+			assert.Equal(t, tt.encoding, w.Header().Get("Content-Encoding"))
+			if w.Code != http.StatusNoContent {
+				assert.GreaterOrEqual(t, w.Body.Len(), 100, "body sent as is")
+			}
+		})
+	}
+}
+
+func TestCompressMiddleware_Arguments(t *testing.T) {
+	// types= narrows the compressed content types, min_size= the threshold
+	router := compressTestRouter([]string{`types="text/*"`, "min_size=10"}, jsonOfSize(4096))
+	assert.Empty(t, compressRequest(router, http.MethodGet, "gzip").Header().Get("Content-Encoding"))
+
+	router = compressTestRouter([]string{`types="application/json"`, "min_size=10B"}, jsonOfSize(100))
+	assert.Equal(t, "gzip", compressRequest(router, http.MethodGet, "gzip").Header().Get("Content-Encoding"))
+
+	// encodings= restricts the offered encodings
+	router = compressTestRouter([]string{`encodings="deflate"`}, jsonOfSize(4096))
+	assert.Equal(t, "deflate", compressRequest(router, http.MethodGet, "gzip, deflate").Header().Get("Content-Encoding"))
+	assert.Empty(t, compressRequest(router, http.MethodGet, "gzip").Header().Get("Content-Encoding"))
+}
+
+func TestCompressMiddleware_ContentLengthAndETag(t *testing.T) {
+	router := compressTestRouter(nil, func(c *gin.Context) {
+		c.Header("Content-Length", "4096")
+		c.Header("ETag", `"v1"`)
+		c.Data(http.StatusOK, "application/json", bytes.Repeat([]byte{'a'}, 4096))
+	})
+	w := compressRequest(router, http.MethodGet, "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Equal(t, `W/"v1"`, w.Header().Get("ETag"))
+	assert.Len(t, gunzip(t, w.Body.Bytes()), 4096)
+}
+
+func TestCompressMiddleware_Streaming(t *testing.T) {
+	router := compressTestRouter(nil, func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		for _, event := range []string{"data: one\n\n", "data: two\n\n"} {
+			_, _ = c.Writer.WriteString(event)
+			c.Writer.Flush()
+		}
+	})
+	w := compressRequest(router, http.MethodGet, "gzip")
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"), "small flushed chunks are still compressed")
+	assert.Equal(t, "data: one\n\ndata: two\n\n", gunzip(t, w.Body.Bytes()))
+
+	// Headers sent before the body (e.g. c.Stream) leave the response uncompressed
+	router = compressTestRouter(nil, func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.WriteHeaderNow()
+		_, _ = c.Writer.WriteString("data: one\n\n")
+	})
+	w = compressRequest(router, http.MethodGet, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: one\n\n", w.Body.String())
+}
+
+func TestCompressMiddleware_Brotli(t *testing.T) {
+	router := compressTestRouter(nil, jsonOfSize(4096))
+	w := compressRequest(router, http.MethodGet, "gzip, br")
+	assert.Equal(t, CompressionBrotli, w.Header().Get("Content-Encoding"), "preferred by default")
+
+	data, err := io.ReadAll(brotli.NewReader(w.Body))
+	require.NoError(t, err)
+	assert.Len(t, data, 4096)
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	RegisterCompressor("zstd", newGzipCompressor) // stands in for a zstd writer
+	defer func() {
+		compressorsMux.Lock()
+		delete(compressors, "zstd")
+		compressorsMux.Unlock()
+	}()
+
+	router := compressTestRouter([]string{`encodings="zstd,gzip"`}, jsonOfSize(4096))
+	w := compressRequest(router, http.MethodGet, "gzip, zstd")
+	assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"), "preferred when registered")
+}
+
+func TestConfigureCompression(t *testing.T) {
+	defer compressionSettings.Store(nil)
+
+	assert.ErrorContains(t, ConfigureCompression(CompressionConfig{Level: 12}), "invalid compression level")
+	assert.ErrorContains(t, ConfigureCompression(CompressionConfig{MinSize: "big"}), "min_size")
+	assert.ErrorContains(t, ConfigureCompression(CompressionConfig{Types: []string{"json"}}), "expected type/subtype")
+
+	// Routes inherit the global settings at request time
+	router := compressTestRouter(nil, jsonOfSize(100))
+	assert.Empty(t, compressRequest(router, http.MethodGet, "gzip").Header().Get("Content-Encoding"))
+	require.NoError(t, ConfigureCompression(CompressionConfig{MinSize: "50B"}))
+	assert.Equal(t, "gzip", compressRequest(router, http.MethodGet, "gzip").Header().Get("Content-Encoding"))
+}
+
+func TestParseCompressArgs(t *testing.T) {
+	config, err := parseCompressArgs([]string{`types="application/json,text/*"`, "level=9", "min_size=2KB", `encodings="gzip"`})
+	require.NoError(t, err)
+	assert.Equal(t, CompressionConfig{Types: []string{"application/json", "text/*"}, Level: 9, MinSize: "2KB", Encodings: []string{"gzip"}}, config)
+
+	for _, args := range [][]string{{"level=0"}, {"level=12"}, {"min_size=lots"}, {"types=json"}, {"speed=fast"}, {"gzip"}} {
+		_, err := parseCompressArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_Compression(t *testing.T) {
+	path := t.TempDir() + "/.deco.yaml"
+	writeTestFile(t, path, "version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\ncompression:\n  enabled: true\n  level: 9\n  min_size: 2KB\n")
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionConfig{Enabled: true, Level: 9, MinSize: "2KB"}, config.Compress)
+}
@@ -2,11 +2,13 @@ package decorators
 
 import (
 	"fmt"
+	"go/token"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -28,6 +30,39 @@ type Config struct {
 	Telemetry  TelemetryConfig     `yaml:"telemetry,omitempty"`
 	ClientSDK  ClientSDKConfig     `yaml:"client_sdk,omitempty"`
 	Proxy      ProxyConfigSettings `yaml:"proxy,omitempty"`
+	SpecLint   SpecLintConfig      `yaml:"spec_lint,omitempty"`
+	Profiling  ProfilingConfig     `yaml:"profiling,omitempty"`
+	AccessLog  AccessLogConfig     `yaml:"access_log,omitempty"`
+	Capture    BodyCaptureConfig   `yaml:"body_capture,omitempty"`
+	Privacy    PrivacyConfig       `yaml:"privacy,omitempty"`
+	Outbox     OutboxConfig        `yaml:"outbox,omitempty"`
+	Admin      AdminConfig         `yaml:"admin,omitempty"`
+	Events     EventsConfig        `yaml:"events,omitempty"`
+	Docs       DocsConfig          `yaml:"docs,omitempty"`
+	Changelog  ChangelogConfig     `yaml:"changelog,omitempty"`
+	Bench      BenchConfig         `yaml:"bench,omitempty"`
+	GRPC       GRPCConfig          `yaml:"grpc,omitempty"`
+	Warmup     WarmupConfig        `yaml:"warmup,omitempty"`
+	Auth       AuthConfig          `yaml:"auth,omitempty"`
+	Limits     LimitsConfig        `yaml:"limits,omitempty"`
+	Compress   CompressionConfig   `yaml:"compression,omitempty"`
+	Versioning VersioningConfig    `yaml:"versioning,omitempty"`
+	Serialize  SerializationConfig `yaml:"serialization,omitempty"`
+	Logging    LoggingConfig       `yaml:"logging,omitempty"`
+	Envelope   EnvelopeConfig      `yaml:"envelope,omitempty"`
+
+	baseDir   string               // directory of the loaded config file
+	file      string               // loaded config file, empty for defaults
+	workspace string               // workspace file whose defaults were merged, if any
+	sections  map[string]yaml.Node // raw top-level sections, for BindConfig
+}
+
+// ResolvePath resolves a path from the configuration against the config file directory
+func (c *Config) ResolvePath(path string) string {
+	if c == nil || c.baseDir == "" || path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.baseDir, path)
 }
 
 // HandlersConfig configuration for handlers discovery
@@ -38,13 +73,90 @@ type HandlersConfig struct {
 
 // GenerationConfig configuration for code generation
 type GenerationConfig struct {
-	Template string `yaml:"template,omitempty"`
+	Output       string   `yaml:"output,omitempty"`  // generated file, DefaultOutputPath when empty
+	Package      string   `yaml:"package,omitempty"` // package of the generated file, DefaultPackageName when empty
+	Template     string   `yaml:"template,omitempty"`
+	CacheDir     string   `yaml:"cache_dir,omitempty"`     // directory of cache.json, defaults to the directory of the generated file
+	DisableCache bool     `yaml:"disable_cache,omitempty"` // disable the per-file parse cache
+	Plugins      []string `yaml:"plugins,omitempty"`       // marker plugin packages, e.g. "github.com/acme/deco-stripe"
+	Split        bool     `yaml:"split,omitempty"`         // write the registrations of each handler package to its own file
+
+	// MiddlewareOrder relative order of markers in each chain, e.g. [Auth, RateLimit, Cache]; markers that are
+	// not listed keep their position. Defaults to DefaultMiddlewareOrder.
+	MiddlewareOrder []string `yaml:"middleware_order,omitempty"`
+}
+
+// DefaultOutputPath is where the generated init file is written
+const DefaultOutputPath = "./.deco/init_decorators.go"
+
+// DefaultPackageName is the package of the generated init file
+const DefaultPackageName = "deco"
+
+// OutputPath returns the path of the generated init file
+func (g GenerationConfig) OutputPath() string {
+	if g.Output != "" {
+		return g.Output
+	}
+	return DefaultOutputPath
+}
+
+// PackageName returns the package of the generated init file
+func (g GenerationConfig) PackageName() string {
+	if g.Package != "" {
+		return g.Package
+	}
+	return DefaultPackageName
+}
+
+// validate checks the output and package of the generated code
+func (g GenerationConfig) validate() error {
+	if g.Output != "" {
+		if err := ValidateOutputPath(g.Output); err != nil {
+			return fmt.Errorf("invalid generation.output: %v", err)
+		}
+	}
+	if g.Package != "" {
+		if err := ValidatePackageName(g.Package); err != nil {
+			return fmt.Errorf("invalid generation.package: %v", err)
+		}
+	}
+	return nil
+}
+
+// ValidateOutputPath checks that path can hold the generated code: a non-test .go file outside vendor
+func ValidateOutputPath(path string) error {
+	clean := filepath.ToSlash(filepath.Clean(path))
+	switch {
+	case !strings.HasSuffix(clean, ".go") || strings.HasSuffix(clean, "/.go") || clean == ".go":
+		return fmt.Errorf("'%s' is not a .go file", path)
+	case strings.HasSuffix(clean, "_test.go"):
+		return fmt.Errorf("'%s' is a test file, which is not compiled into the program", path)
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(clean)), "/") {
+		if dir == "vendor" || dir == "testdata" {
+			return fmt.Errorf("'%s' is inside %s, which the go tool does not build", path, dir)
+		}
+	}
+	return nil
+}
+
+// ValidatePackageName checks that name can be the package of the generated code
+func ValidatePackageName(name string) error {
+	switch {
+	case !token.IsIdentifier(name) || name == "_":
+		return fmt.Errorf("'%s' is not a valid Go package name", name)
+	case name == "main":
+		return fmt.Errorf("'main' cannot be imported by the application")
+	}
+	return nil
 }
 
 // DevConfig configuration for development mode
 type DevConfig struct {
-	AutoDiscover bool `yaml:"auto_discover"`
-	Watch        bool `yaml:"watch"`
+	AutoDiscover   bool `yaml:"auto_discover"`
+	Watch          bool `yaml:"watch"`
+	CheckResponses bool `yaml:"check_responses,omitempty"` // validate JSON responses against their @Response schema (ignored with -tags prod)
+	Learn          bool `yaml:"learn,omitempty"`           // record traffic shapes and suggest @Param/@Response/@Schema (ignored with -tags prod)
 }
 
 // ProdConfig configuration for production mode
@@ -64,10 +176,66 @@ type RedisConfig struct {
 
 // CacheConfig cache system configuration
 type CacheConfig struct {
-	Type        string `yaml:"type"` // "memory", "redis"
+	Type        string `yaml:"type"` // "memory", "redis" or a backend registered with RegisterCacheBackend
 	DefaultTTL  string `yaml:"default_ttl"`
 	MaxSize     int    `yaml:"max_size,omitempty"`
 	Compression bool   `yaml:"compression"`
+
+	Encrypt       bool   `yaml:"encrypt,omitempty"`        // encrypt entries at rest (AES-GCM), also per route with @Cache(encrypt=true)
+	EncryptionKey string `yaml:"encryption_key,omitempty"` // secret name of the key, defaults to "cache-encryption-key"
+
+	Keys CacheKeyConfig `yaml:"keys,omitempty"` // cache key hardening
+	ETag string         `yaml:"etag,omitempty"` // validators of cached responses: "strong" (default), "weak" or "off"
+}
+
+// CacheKeyConfig hardening of the cache keys against poisoning and bloat through request variation
+type CacheKeyConfig struct {
+	VaryHeaders     []string `yaml:"vary_headers,omitempty"`       // request headers allowed in the key (e.g. Accept-Language); others are ignored
+	MaxLength       int      `yaml:"max_length,omitempty"`         // longer canonical keys are not cached, defaults to 1024
+	HMACSecret      string   `yaml:"hmac_secret,omitempty"`        // secret name of the key hashing the keys with HMAC-SHA256
+	MaxKeysPerRoute int      `yaml:"max_keys_per_route,omitempty"` // live keys per route and instance; requests with new keys beyond it bypass the cache
+}
+
+// LimitsConfig request limits applied to every route
+type LimitsConfig struct {
+	MaxBodySize string `yaml:"max_body_size,omitempty"` // request body cap (e.g. "10MB", "0" disables); routes override it with @MaxBodySize
+}
+
+// CompressionConfig response compression: every route with enabled, otherwise the defaults of @Compress
+type CompressionConfig struct {
+	Enabled   bool     `yaml:"enabled,omitempty"`   // compress the responses of every route
+	Types     []string `yaml:"types,omitempty"`     // compressed content types, wildcards allowed ("text/*", "application/*+json")
+	Level     int      `yaml:"level,omitempty"`     // 1 (fastest) to 9 (smallest), up to 11 for brotli; 5 by default
+	MinSize   string   `yaml:"min_size,omitempty"`  // smaller responses are sent as is, "1KB" by default
+	Encodings []string `yaml:"encodings,omitempty"` // preferred encodings first, [br, gzip, deflate] by default; only registered ones are offered
+}
+
+// VersioningConfig how clients select the version of @Version routes
+type VersioningConfig struct {
+	Strategy   string `yaml:"strategy,omitempty"`    // "path" (default), "header" or "query"
+	PathPrefix string `yaml:"path_prefix,omitempty"` // path strategy, "/{version}" by default, e.g. "/api/{version}"
+	Header     string `yaml:"header,omitempty"`      // header strategy, "API-Version" by default
+	Query      string `yaml:"query,omitempty"`       // query strategy, "version" by default
+	Default    string `yaml:"default,omitempty"`     // version of requests naming none (header, query); the latest of the route by default
+}
+
+// SerializationConfig JSON engine of request bodies, cache entries and the response helpers
+type SerializationConfig struct {
+	Engine string `yaml:"engine,omitempty"` // "stdlib" (default), or an engine registered with RegisterJSONEngine such as "sonic" or "jsoniter"
+}
+
+// LoggingConfig structured request logs: every route with enabled, otherwise the defaults of @Log
+type LoggingConfig struct {
+	Enabled bool     `yaml:"enabled,omitempty"` // log the requests of every route
+	Level   string   `yaml:"level,omitempty"`   // "debug", "info" (default), "warn" or "error"; 4xx are logged at warn and 5xx at error at least
+	Bodies  bool     `yaml:"bodies,omitempty"`  // include the request and response bodies, up to body_capture.max_bytes
+	Redact  []string `yaml:"redact,omitempty"`  // body fields masked in the logs, as body_capture.redact; @Log adds its own
+}
+
+// EnvelopeConfig response envelope: every route with enabled, otherwise the defaults of @Envelope
+type EnvelopeConfig struct {
+	Enabled    bool `yaml:"enabled,omitempty"`     // wrap the JSON responses of every route as {"data": ..., "meta": ...}
+	SkipErrors bool `yaml:"skip_errors,omitempty"` // send 4xx and 5xx responses as is instead of {"error": ...}
 }
 
 // RateLimitConfig rate limiting configuration
@@ -75,8 +243,9 @@ type RateLimitConfig struct {
 	Enabled    bool   `yaml:"enabled"`
 	Type       string `yaml:"type"` // "memory", "redis"
 	DefaultRPS int    `yaml:"default_rps"`
-	BurstSize  int    `yaml:"burst_size"`
-	KeyFunc    string `yaml:"key_func"` // "ip", "user", "custom"
+	BurstSize  int    `yaml:"burst_size"` // token bucket capacity
+	KeyFunc    string `yaml:"key_func"`   // "ip", "user", "custom"
+	Algorithm  string `yaml:"algorithm"`  // redis: "sliding_window", "token_bucket"
 }
 
 // MetricsConfig Prometheus configuration
@@ -86,6 +255,10 @@ type MetricsConfig struct {
 	Namespace string    `yaml:"namespace"`
 	Subsystem string    `yaml:"subsystem"`
 	Buckets   []float64 `yaml:"buckets,omitempty"`
+	// SizeBuckets histogram buckets for request/response sizes in bytes
+	SizeBuckets []float64 `yaml:"size_buckets,omitempty"`
+	// SlowThreshold default slow request threshold for routes without @SlowThreshold (e.g. "1s")
+	SlowThreshold string `yaml:"slow_threshold,omitempty"`
 }
 
 // OpenAPIConfig OpenAPI documentation configuration
@@ -99,6 +272,225 @@ type OpenAPIConfig struct {
 	Contact     map[string]interface{} `yaml:"contact,omitempty"`
 	License     map[string]interface{} `yaml:"license,omitempty"`
 	Security    []map[string][]string  `yaml:"security,omitempty"`
+	OperationID OperationIDConfig      `yaml:"operation_id,omitempty"`
+	Servers     []OpenAPIServerConfig  `yaml:"servers,omitempty"`      // named environments; replace host/schemes when set
+	SpecVersion string                 `yaml:"spec_version,omitempty"` // "3.1" also serves /decorators/openapi31.json
+}
+
+// OpenAPIServerConfig named server (environment) published in openapi.servers
+type OpenAPIServerConfig struct {
+	Name        string `yaml:"name"` // environment name used by the SDKs, e.g. "staging"
+	URL         string `yaml:"url"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// validate checks that servers have unique names and URLs
+func (o OpenAPIConfig) validate() error {
+	seen := make(map[string]bool, len(o.Servers))
+	for i, server := range o.Servers {
+		if server.Name == "" {
+			return fmt.Errorf("openapi.servers[%d]: name is required", i)
+		}
+		if server.URL == "" {
+			return fmt.Errorf("openapi.servers[%d] (%s): url is required", i, server.Name)
+		}
+		if seen[server.Name] {
+			return fmt.Errorf("openapi.servers: duplicate name '%s'", server.Name)
+		}
+		seen[server.Name] = true
+	}
+	if o.SpecVersion != "" && o.SpecVersion != OpenAPISpecVersion30 && o.SpecVersion != OpenAPISpecVersion31 {
+		return fmt.Errorf("invalid openapi.spec_version '%s' (valid: 3.0, 3.1)", o.SpecVersion)
+	}
+	return nil
+}
+
+// OperationIDConfig configures how operationIds are generated
+type OperationIDConfig struct {
+	Strategy string `yaml:"strategy,omitempty"` // "funcName", "methodPath", "template"
+	Template string `yaml:"template,omitempty"` // used by the template strategy
+}
+
+// SpecLintConfig configuration for linting the generated spec
+type SpecLintConfig struct {
+	Enabled   *bool  `yaml:"enabled,omitempty"`    // defaults to true
+	RulesFile string `yaml:"rules_file,omitempty"` // defaults to spec_lint.rules, relative to the config file
+	FailOn    string `yaml:"fail_on"`              // "error", "warn", "never"
+}
+
+// IsEnabled reports whether spec linting runs, defaulting to true when unset
+func (s SpecLintConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// ProfilingConfig continuous profiling configuration
+type ProfilingConfig struct {
+	Enabled        bool              `yaml:"enabled"`                   // exposes /decorators/debug/pprof/* behind the security middleware
+	Exporter       string            `yaml:"exporter,omitempty"`        // "pyroscope", "parca" or a registered exporter name
+	ServerAddress  string            `yaml:"server_address,omitempty"`  // exporter server, e.g. http://pyroscope:4040
+	AuthToken      string            `yaml:"auth_token,omitempty"`      // bearer token sent to the exporter server
+	AppName        string            `yaml:"app_name,omitempty"`        // application name reported to the exporter
+	UploadInterval string            `yaml:"upload_interval,omitempty"` // profile collection window, e.g. "15s"
+	Tags           map[string]string `yaml:"tags,omitempty"`            // static labels attached to every profile
+}
+
+// AdminConfig cache and rate-limit administration endpoints configuration
+type AdminConfig struct {
+	Enabled  bool   `yaml:"enabled"`             // exposes /decorators/admin/* behind the security middleware
+	AuthRole string `yaml:"auth_role,omitempty"` // additionally requires @Auth with this role
+}
+
+// EventsConfig CloudEvents publishing configuration
+type EventsConfig struct {
+	Enabled   bool              `yaml:"enabled"`
+	Sink      string            `yaml:"sink,omitempty"`       // HTTP endpoint receiving the events in structured mode
+	Source    string            `yaml:"source,omitempty"`     // CloudEvents source attribute
+	Timeout   string            `yaml:"timeout,omitempty"`    // per-delivery timeout, e.g. "5s"
+	QueueSize int               `yaml:"queue_size,omitempty"` // events waiting for delivery before new ones are dropped
+	Headers   map[string]string `yaml:"headers,omitempty"`    // extra headers, e.g. Authorization
+}
+
+// DocsConfig documentation page configuration
+type DocsConfig struct {
+	Locale       string              `yaml:"locale,omitempty"`       // language used when the request does not choose one, e.g. "pt-BR"
+	Translations string              `yaml:"translations,omitempty"` // YAML file with UI strings and route texts per locale
+	Branding     DocsBrandingConfig  `yaml:"branding,omitempty"`
+	BasePath     string              `yaml:"base_path,omitempty"` // prefix of the documentation endpoints, default "/decorators"
+	Endpoints    DocsEndpointsConfig `yaml:"endpoints,omitempty"`
+}
+
+// DocsEndpointsConfig documentation endpoints served under docs.base_path; unset ones are served
+type DocsEndpointsConfig struct {
+	Page      *bool `yaml:"page,omitempty"`       // docs
+	JSON      *bool `yaml:"json,omitempty"`       // docs.json
+	OpenAPI   *bool `yaml:"openapi,omitempty"`    // openapi.json, openapi.yaml and openapi31.json
+	SwaggerUI *bool `yaml:"swagger_ui,omitempty"` // swagger-ui and the swagger redirect
+}
+
+// DocsBrandingConfig look of the docs page and the Swagger UI
+type DocsBrandingConfig struct {
+	Title        string `yaml:"title,omitempty"`         // replaces "gin-decorators" in the header and page titles
+	LogoURL      string `yaml:"logo_url,omitempty"`      // image shown in the header
+	FaviconURL   string `yaml:"favicon_url,omitempty"`   // page icon
+	PrimaryColor string `yaml:"primary_color,omitempty"` // CSS color of the header and highlights, e.g. "#0052cc"
+	Theme        string `yaml:"theme,omitempty"`         // "dark", "light" or "auto" (follows the browser)
+	CustomCSS    string `yaml:"custom_css,omitempty"`    // CSS file appended to both pages, relative to the config file
+}
+
+// AccessLogConfig access log configuration
+type AccessLogConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Format        string `yaml:"format"`                   // "combined", "json" or "template"
+	Template      string `yaml:"template,omitempty"`       // Go template over AccessLogEntry, used by format "template"
+	Output        string `yaml:"output"`                   // "stdout", "stderr", "file" or "syslog"
+	File          string `yaml:"file,omitempty"`           // log file, relative to the working directory
+	MaxSizeMB     int    `yaml:"max_size_mb,omitempty"`    // rotate the file once it reaches this size (0 disables)
+	MaxBackups    int    `yaml:"max_backups,omitempty"`    // rotated files to keep (0 keeps all)
+	MaxAgeDays    int    `yaml:"max_age_days,omitempty"`   // delete rotated files older than this (0 keeps all)
+	SyslogNetwork string `yaml:"syslog_network,omitempty"` // "udp", "tcp" or empty for the local daemon
+	SyslogAddress string `yaml:"syslog_address,omitempty"` // e.g. "logs.internal:514"
+	SyslogTag     string `yaml:"syslog_tag,omitempty"`
+}
+
+// BodyCaptureConfig configuration of the shared body capture layer and its redaction rules
+type BodyCaptureConfig struct {
+	MaxBytes    string   `yaml:"max_bytes,omitempty"`   // bytes kept per body, e.g. "64KB"
+	Redact      []string `yaml:"redact,omitempty"`      // JSON path rules, e.g. "password", "$.card.number", "items[*].token"
+	Replacement string   `yaml:"replacement,omitempty"` // defaults to "[REDACTED]"
+}
+
+// ChangelogConfig route table snapshots recorded per deployment for /decorators/changelog
+type ChangelogConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Store      string `yaml:"store,omitempty"`       // "file" (default) or "redis"
+	File       string `yaml:"file,omitempty"`        // snapshot file of the file store, defaults to ".deco/changelog.json"
+	MaxEntries int    `yaml:"max_entries,omitempty"` // deployments kept, defaults to 50
+}
+
+// BenchConfig load and performance budgets of deco bench
+type BenchConfig struct {
+	Duration    string              `yaml:"duration,omitempty"`    // measured time per scenario, defaults to "2s"
+	Concurrency int                 `yaml:"concurrency,omitempty"` // concurrent clients, defaults to GOMAXPROCS
+	Budgets     map[string]string   `yaml:"budgets,omitempty"`     // max overhead per request by scenario, e.g. Cache: "20us"
+	Scenarios   map[string][]string `yaml:"scenarios,omitempty"`   // extra scenarios, e.g. api: ["@Auth(role=user)", "@Cache(ttl=1m)"]
+}
+
+// GRPCConfig gRPC server of the @GRPC routes
+type GRPCConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Address    string `yaml:"address,omitempty"`    // listen address, defaults to ":9090"
+	Package    string `yaml:"package,omitempty"`    // proto package, defaults to "api"
+	Proto      string `yaml:"proto,omitempty"`      // generated .proto file, defaults to <package>.proto next to the output
+	Reflection bool   `yaml:"reflection,omitempty"` // register server reflection (grpcurl, Postman)
+}
+
+// AuthConfig configuration of @Auth
+type AuthConfig struct {
+	JWT        JWTConfig         `yaml:"jwt,omitempty"`
+	CacheTTL   string            `yaml:"cache_ttl,omitempty"` // reuse the claims of a verified token for this long, e.g. "30s" (routes override it with @Auth(cache=...))
+	Revocation RevocationConfig  `yaml:"revocation,omitempty"`
+	Tokens     TokenIssuerConfig `yaml:"tokens,omitempty"`
+}
+
+// RevocationConfig revocation list checked by @Auth for logout and compromised tokens
+type RevocationConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Store      string `yaml:"store,omitempty"`       // "memory" (single instance) or "redis" (uses the redis section), defaults to "memory"
+	Prefix     string `yaml:"prefix,omitempty"`      // Redis key prefix, defaults to "deco:revoked:"
+	SubjectTTL string `yaml:"subject_ttl,omitempty"` // how long a subject revocation is kept, at least the longest token lifetime; defaults to "720h"
+	Endpoint   bool   `yaml:"endpoint,omitempty"`    // exposes POST /decorators/auth/revoke behind the security middleware
+	AuthRole   string `yaml:"auth_role,omitempty"`   // additionally requires @Auth with this role on the endpoint
+}
+
+// TokenIssuerConfig access and refresh tokens issued by IssueTokens, for apps using deco as their auth
+// layer. Tokens are signed with auth.jwt.secret_key (HS256) or with private_key.
+type TokenIssuerConfig struct {
+	AccessTTL  string `yaml:"access_ttl,omitempty"`  // defaults to "15m"
+	RefreshTTL string `yaml:"refresh_ttl,omitempty"` // defaults to "720h"
+	PrivateKey string `yaml:"private_key,omitempty"` // PEM file of an RSA (RS256), ECDSA (ES256/384/512) or Ed25519 (EdDSA) private key
+}
+
+// JWTConfig verification of the bearer tokens of @Auth. Without a key source (secret_key, public_keys
+// or jwks_url) tokens are not verified, only required.
+type JWTConfig struct {
+	Algorithms  []string `yaml:"algorithms,omitempty"`   // accepted "alg" values, defaults to every supported one
+	SecretKey   string   `yaml:"secret_key,omitempty"`   // secret name of the HMAC key (HS256/384/512), read with GetSecret
+	PublicKeys  []string `yaml:"public_keys,omitempty"`  // PEM files of RSA, ECDSA or Ed25519 public keys (or certificates)
+	JWKSURL     string   `yaml:"jwks_url,omitempty"`     // e.g. https://issuer/.well-known/jwks.json
+	JWKSRefresh string   `yaml:"jwks_refresh,omitempty"` // how long fetched keys are kept, defaults to "10m"
+	Issuer      string   `yaml:"issuer,omitempty"`       // required "iss"
+	Audience    []string `yaml:"audience,omitempty"`     // "aud" must contain one of them
+	Leeway      string   `yaml:"leeway,omitempty"`       // clock skew tolerated on exp/nbf, defaults to "30s"
+	RolesClaim  string   `yaml:"roles_claim,omitempty"`  // claim with the roles, dotted for nested ones (realm_access.roles), defaults to "roles"
+	UserClaim   string   `yaml:"user_claim,omitempty"`   // claim set as "user_id", defaults to "sub"
+}
+
+// WarmupConfig eager initialization of dependencies when the engine starts
+type WarmupConfig struct {
+	Timeout     string `yaml:"timeout,omitempty"`     // per initializer, defaults to "10s"
+	Parallelism int    `yaml:"parallelism,omitempty"` // initializers run at once, defaults to 4
+	Background  bool   `yaml:"background,omitempty"`  // start serving at once; readiness fails until the warm-up ends
+}
+
+// PrivacyConfig central PII policy applied to access logs, trace attributes, audit events and recorded bodies
+type PrivacyConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Fields      []string `yaml:"fields,omitempty"`      // field name patterns replaced as a whole, e.g. "password", "*_token"
+	Detectors   []string `yaml:"detectors,omitempty"`   // "email", "card", "cpf" (all by default)
+	Patterns    []string `yaml:"patterns,omitempty"`    // extra regular expressions, e.g. '\+55 ?\d{2} ?\d{4,5}-?\d{4}'
+	Replacement string   `yaml:"replacement,omitempty"` // defaults to "[PII]"
+	Endpoints   bool     `yaml:"endpoints,omitempty"`   // exposes /decorators/privacy/* (export and erasure) behind the security middleware
+	AuthRole    string   `yaml:"auth_role,omitempty"`   // additionally requires @Auth with this role on the endpoints
+}
+
+// OutboxConfig configuration of the transactional outbox
+type OutboxConfig struct {
+	Table        string `yaml:"table,omitempty"`         // defaults to "deco_outbox"
+	Dialect      string `yaml:"dialect,omitempty"`       // "postgres" (default), "mysql" or "sqlite"
+	BatchSize    int    `yaml:"batch_size,omitempty"`    // events published per poll
+	PollInterval string `yaml:"poll_interval,omitempty"` // e.g. "1s"
+	MaxAttempts  int    `yaml:"max_attempts,omitempty"`  // attempts before an event is marked dead
+	RetryBackoff string `yaml:"retry_backoff,omitempty"` // first retry delay, doubled per attempt
 }
 
 // ValidationConfig validation configuration
@@ -119,6 +511,14 @@ type WebSocketConfig struct {
 	Compression  bool   `yaml:"compression"`
 	PingInterval string `yaml:"ping_interval"`
 	PongTimeout  string `yaml:"pong_timeout"`
+
+	Backplane WebSocketBackplaneConfig `yaml:"backplane,omitempty"` // propagates broadcasts across instances
+}
+
+// WebSocketBackplaneConfig backplane of the WebSocket hub, for services running several instances
+type WebSocketBackplaneConfig struct {
+	Type    string `yaml:"type,omitempty"`    // "redis" or a name registered with RegisterWebSocketBackplane; empty keeps broadcasts local
+	Channel string `yaml:"channel,omitempty"` // pub/sub channel, "deco:websocket" by default
 }
 
 // TelemetryConfig OpenTelemetry configuration
@@ -130,6 +530,9 @@ type TelemetryConfig struct {
 	Endpoint       string  `yaml:"endpoint"`
 	Insecure       bool    `yaml:"insecure"`
 	SampleRate     float64 `yaml:"sample_rate"`
+	RecordBodies   bool    `yaml:"record_bodies,omitempty"` // add captured (redacted) bodies as span attributes
+
+	BaggageAllowlist []string `yaml:"baggage_allowlist,omitempty"` // baggage keys forwarded to upstreams (empty forwards all)
 }
 
 // ClientSDKConfig SDK generation configuration
@@ -258,6 +661,7 @@ func DefaultConfig() *Config {
 			DefaultTTL:  "1h",
 			MaxSize:     1000,
 			Compression: false,
+			ETag:        CacheETagStrong,
 		},
 		RateLimit: RateLimitConfig{
 			Enabled:    false,
@@ -265,6 +669,7 @@ func DefaultConfig() *Config {
 			DefaultRPS: 100,
 			BurstSize:  200,
 			KeyFunc:    "ip",
+			Algorithm:  RateLimitSlidingWindow,
 		},
 		Metrics: MetricsConfig{
 			Enabled:   false,
@@ -346,18 +751,101 @@ func DefaultConfig() *Config {
 				IdleConnTimeout: "90s",
 			},
 		},
+		SpecLint: SpecLintConfig{
+			FailOn: "error",
+		},
+		Profiling: ProfilingConfig{
+			Enabled:        false,
+			AppName:        "gin-decorators",
+			UploadInterval: "15s",
+		},
+		Events: EventsConfig{
+			Source:    "gin-decorators",
+			Timeout:   "5s",
+			QueueSize: 1000,
+		},
+		Docs: DocsConfig{
+			Locale:   DefaultDocsLocale,
+			BasePath: DefaultDocsBasePath,
+		},
+		Capture: BodyCaptureConfig{
+			MaxBytes:    "64KB",
+			Replacement: DefaultRedactionReplacement,
+		},
+		Privacy: PrivacyConfig{
+			Replacement: DefaultPIIReplacement,
+		},
+		Changelog: ChangelogConfig{
+			Store:      ChangelogStoreFile,
+			File:       ".deco/changelog.json",
+			MaxEntries: 50,
+		},
+		GRPC: GRPCConfig{
+			Address: ":9090",
+			Package: "api",
+		},
+		Warmup: WarmupConfig{
+			Timeout:     "10s",
+			Parallelism: 4,
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				JWKSRefresh: "10m",
+				Leeway:      "30s",
+				RolesClaim:  "roles",
+				UserClaim:   "sub",
+			},
+			Revocation: RevocationConfig{
+				Store:      RevocationStoreMemory,
+				Prefix:     "deco:revoked:",
+				SubjectTTL: "720h",
+			},
+			Tokens: TokenIssuerConfig{
+				AccessTTL:  "15m",
+				RefreshTTL: "720h",
+			},
+		},
+		Limits: LimitsConfig{
+			MaxBodySize: DefaultMaxBodySize,
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:   false,
+			Format:    AccessLogFormatCombined,
+			Output:    AccessLogOutputStdout,
+			SyslogTag: "gin-decorators",
+		},
+		Outbox: OutboxConfig{
+			Table:        "deco_outbox",
+			Dialect:      "postgres",
+			BatchSize:    100,
+			PollInterval: "1s",
+			MaxAttempts:  10,
+			RetryBackoff: "1s",
+		},
 	}
 }
 
-// LoadConfig loads configuration from file
+// LoadConfig loads configuration from file, applying the DECO_* environment overrides
 func LoadConfig(configPath string) (*Config, error) {
+	return LoadConfigWithOverrides(configPath, EnvConfigOverrides())
+}
+
+// LoadConfigWithOverrides loads configuration from file, then sets the overrides on top of it
+func LoadConfigWithOverrides(configPath string, overrides []ConfigOverride) (*Config, error) {
 	if configPath == "" {
 		configPath = findConfigFile()
 	}
 
 	if configPath == "" {
 		// Use default configuration if file not found
-		return DefaultConfig(), nil
+		if len(overrides) == 0 {
+			return DefaultConfig(), nil
+		}
+		data, err := yaml.Marshal(DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("error serializing configuration: %v", err)
+		}
+		return loadConfigData(data, "", overrides)
 	}
 
 	data, err := os.ReadFile(configPath)
@@ -365,13 +853,55 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("error reading file de configuration %s: %v", configPath, err)
 	}
 
+	// Shared defaults of the enclosing deco.workspace.yaml go under the file
+	data, workspaceFile, err := applyWorkspaceDefaults(data, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := loadConfigData(data, configPath, overrides)
+	if err != nil {
+		return nil, err
+	}
+	config.workspace = workspaceFile
+
+	// Relative paths in the configuration are resolved against the project root
+	if absConfigPath, err := filepath.Abs(configPath); err == nil {
+		config.baseDir = filepath.Dir(absConfigPath)
+	}
+
+	return config, nil
+}
+
+// loadConfigData validates, merges and decodes the content of a configuration file
+func loadConfigData(data []byte, configPath string, overrides []ConfigOverride) (*Config, error) {
+	// Unknown keys, wrong types and invalid durations are reported with their line
+	if issues := validateConfigSchema(data, configPath); len(issues) > 0 {
+		return nil, &ConfigValidationError{Issues: issues}
+	}
+	if len(overrides) > 0 {
+		var issues []ConfigIssue
+		if data, issues = applyConfigOverrides(data, overrides); len(issues) > 0 {
+			return nil, &ConfigValidationError{Issues: issues}
+		}
+	}
+
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("error parsing da configuration: %v", err)
 	}
 
+	// Application sections are kept raw and decoded on demand by BindConfig
+	if err := yaml.Unmarshal(data, &config.sections); err != nil {
+		return nil, fmt.Errorf("error parsing da configuration: %v", err)
+	}
+	config.file = configPath
+
 	// Apply defaults for unspecified fields
 	applyDefaults(&config)
+	if err := config.Validate(); err != nil {
+		return nil, &ConfigValidationError{Issues: []ConfigIssue{{File: configPath, Message: err.Error()}}}
+	}
 
 	return &config, nil
 }
@@ -382,6 +912,8 @@ func SaveConfig(config *Config, configPath string) error {
 	if err != nil {
 		return fmt.Errorf("error serializing configuration: %v", err)
 	}
+	// Editors running yaml-language-server validate and complete the file against the published schema
+	data = append([]byte("# yaml-language-server: $schema="+ConfigSchemaURL+"\n"), data...)
 
 	if err := os.WriteFile(configPath, data, 0o600); err != nil {
 		return fmt.Errorf("error saving configuration: %v", err)
@@ -444,10 +976,22 @@ func applyDefaults(config *Config) {
 	if config.RateLimit.Type == "" {
 		config.RateLimit = defaults.RateLimit
 	}
+	if config.RateLimit.Algorithm == "" {
+		config.RateLimit.Algorithm = defaults.RateLimit.Algorithm
+	}
 
-	// Apply defaults for Metrics
+	// Apply defaults for Metrics field by field, so options set alone (e.g. slow_threshold) are kept
 	if config.Metrics.Endpoint == "" {
-		config.Metrics = defaults.Metrics
+		config.Metrics.Endpoint = defaults.Metrics.Endpoint
+	}
+	if config.Metrics.Namespace == "" {
+		config.Metrics.Namespace = defaults.Metrics.Namespace
+	}
+	if config.Metrics.Subsystem == "" {
+		config.Metrics.Subsystem = defaults.Metrics.Subsystem
+	}
+	if len(config.Metrics.Buckets) == 0 {
+		config.Metrics.Buckets = defaults.Metrics.Buckets
 	}
 
 	// Apply defaults for OpenAPI
@@ -462,7 +1006,9 @@ func applyDefaults(config *Config) {
 
 	// Apply defaults for WebSocket
 	if config.WebSocket.ReadBuffer == 0 {
+		backplane := config.WebSocket.Backplane
 		config.WebSocket = defaults.WebSocket
+		config.WebSocket.Backplane = backplane
 	}
 
 	// Apply defaults for Telemetry
@@ -479,6 +1025,126 @@ func applyDefaults(config *Config) {
 	if !config.Proxy.Enabled {
 		config.Proxy = defaults.Proxy
 	}
+
+	// Apply defaults for SpecLint
+	if config.SpecLint.FailOn == "" {
+		config.SpecLint.FailOn = defaults.SpecLint.FailOn
+	}
+
+	// Apply defaults for Profiling
+	if config.Profiling.AppName == "" {
+		config.Profiling.AppName = defaults.Profiling.AppName
+	}
+	if config.Profiling.UploadInterval == "" {
+		config.Profiling.UploadInterval = defaults.Profiling.UploadInterval
+	}
+
+	// Apply defaults for Events
+	if config.Events.Source == "" {
+		config.Events.Source = defaults.Events.Source
+	}
+	if config.Events.Timeout == "" {
+		config.Events.Timeout = defaults.Events.Timeout
+	}
+	if config.Events.QueueSize == 0 {
+		config.Events.QueueSize = defaults.Events.QueueSize
+	}
+
+	// Apply defaults for docs
+	if config.Docs.Locale == "" {
+		config.Docs.Locale = defaults.Docs.Locale
+	}
+	if config.Docs.BasePath == "" {
+		config.Docs.BasePath = defaults.Docs.BasePath
+	}
+
+	// Apply defaults for body capture
+	if config.Capture.MaxBytes == "" {
+		config.Capture.MaxBytes = defaults.Capture.MaxBytes
+	}
+	if config.Capture.Replacement == "" {
+		config.Capture.Replacement = defaults.Capture.Replacement
+	}
+
+	// Apply defaults for privacy
+	if config.Privacy.Replacement == "" {
+		config.Privacy.Replacement = defaults.Privacy.Replacement
+	}
+
+	// Apply defaults for changelog
+	if config.Changelog.Store == "" {
+		config.Changelog.Store = defaults.Changelog.Store
+	}
+	if config.Changelog.File == "" {
+		config.Changelog.File = defaults.Changelog.File
+	}
+	if config.Changelog.MaxEntries == 0 {
+		config.Changelog.MaxEntries = defaults.Changelog.MaxEntries
+	}
+
+	// Apply defaults for warm-up
+	if config.Warmup.Timeout == "" {
+		config.Warmup.Timeout = defaults.Warmup.Timeout
+	}
+	if config.Warmup.Parallelism == 0 {
+		config.Warmup.Parallelism = defaults.Warmup.Parallelism
+	}
+
+	// Apply defaults for JWT verification
+	if config.Auth.JWT.JWKSRefresh == "" {
+		config.Auth.JWT.JWKSRefresh = defaults.Auth.JWT.JWKSRefresh
+	}
+	if config.Auth.JWT.Leeway == "" {
+		config.Auth.JWT.Leeway = defaults.Auth.JWT.Leeway
+	}
+	if config.Auth.JWT.RolesClaim == "" {
+		config.Auth.JWT.RolesClaim = defaults.Auth.JWT.RolesClaim
+	}
+	if config.Auth.JWT.UserClaim == "" {
+		config.Auth.JWT.UserClaim = defaults.Auth.JWT.UserClaim
+	}
+	if config.Auth.Revocation.Store == "" {
+		config.Auth.Revocation.Store = defaults.Auth.Revocation.Store
+	}
+	if config.Auth.Revocation.Prefix == "" {
+		config.Auth.Revocation.Prefix = defaults.Auth.Revocation.Prefix
+	}
+	if config.Auth.Revocation.SubjectTTL == "" {
+		config.Auth.Revocation.SubjectTTL = defaults.Auth.Revocation.SubjectTTL
+	}
+	if config.Auth.Tokens.AccessTTL == "" {
+		config.Auth.Tokens.AccessTTL = defaults.Auth.Tokens.AccessTTL
+	}
+	if config.Auth.Tokens.RefreshTTL == "" {
+		config.Auth.Tokens.RefreshTTL = defaults.Auth.Tokens.RefreshTTL
+	}
+
+	// Apply defaults for the request limits
+	if config.Limits.MaxBodySize == "" {
+		config.Limits.MaxBodySize = defaults.Limits.MaxBodySize
+	}
+
+	// Apply defaults for gRPC
+	if config.GRPC.Address == "" {
+		config.GRPC.Address = defaults.GRPC.Address
+	}
+	if config.GRPC.Package == "" {
+		config.GRPC.Package = defaults.GRPC.Package
+	}
+
+	// Apply defaults for AccessLog
+	if config.AccessLog.Format == "" {
+		config.AccessLog.Format = defaults.AccessLog.Format
+	}
+	if config.AccessLog.Output == "" {
+		config.AccessLog.Output = defaults.AccessLog.Output
+	}
+	if config.AccessLog.SyslogTag == "" {
+		config.AccessLog.SyslogTag = defaults.AccessLog.SyslogTag
+	}
+
+	// Apply defaults for the outbox
+	config.Outbox = config.Outbox.withDefaults()
 }
 
 // DiscoverHandlers discovers handler files based on configuration
@@ -646,5 +1312,120 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if _, err := NewOperationIDGenerator(c.OpenAPI.OperationID); err != nil {
+		return err
+	}
+
+	if err := c.OpenAPI.validate(); err != nil {
+		return err
+	}
+
+	switch c.SpecLint.FailOn {
+	case "", "error", "warn", "never":
+	default:
+		return fmt.Errorf("invalid spec_lint.fail_on '%s' (valid: error, warn, never)", c.SpecLint.FailOn)
+	}
+
+	if c.Metrics.SlowThreshold != "" {
+		if _, err := time.ParseDuration(c.Metrics.SlowThreshold); err != nil {
+			return fmt.Errorf("invalid metrics.slow_threshold '%s': %v", c.Metrics.SlowThreshold, err)
+		}
+	}
+
+	if err := c.Profiling.validate(); err != nil {
+		return err
+	}
+
+	if err := c.WebSocket.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Compress.validate("compression"); err != nil {
+		return err
+	}
+
+	if err := c.Versioning.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Logging.validate("logging"); err != nil {
+		return err
+	}
+
+	if err := c.AccessLog.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Outbox.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Events.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Docs.Branding.validate(); err != nil {
+		return err
+	}
+	if err := c.Docs.validatePaths(); err != nil {
+		return err
+	}
+
+	if c.Capture.MaxBytes != "" {
+		if _, err := ParseByteSize(c.Capture.MaxBytes); err != nil {
+			return fmt.Errorf("invalid body_capture.max_bytes: %v", err)
+		}
+	}
+	if _, err := NewRedactor(c.Capture.Redact, c.Capture.Replacement); err != nil {
+		return fmt.Errorf("invalid body_capture.redact: %v", err)
+	}
+
+	if err := c.Privacy.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Changelog.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Bench.validate(); err != nil {
+		return err
+	}
+
+	if err := c.GRPC.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Warmup.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Auth.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Cache.Keys.validate(); err != nil {
+		return err
+	}
+
+	if err := validateCacheETagMode(c.Cache.ETag); err != nil {
+		return err
+	}
+
+	if err := validateRateLimitAlgorithm(c.RateLimit.Algorithm); err != nil {
+		return err
+	}
+
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+
+	if err := validateMiddlewareOrder(c.Generate.MiddlewareOrder); err != nil {
+		return err
+	}
+	if err := c.Generate.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
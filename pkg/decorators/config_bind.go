@@ -0,0 +1,103 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// envReferencePattern matches ${VAR} and ${VAR:-default} in configuration values
+var envReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// BindConfig decodes an application section of the project configuration (.deco.yaml) into target.
+// String values may reference environment variables as ${VAR} or ${VAR:-default}; the result is
+// checked with the struct's `validate` tags and, when implemented, its Validate() error method.
+func BindConfig(section string, target interface{}) error {
+	config, err := LoadConfig("")
+	if err != nil {
+		return err
+	}
+	return config.Bind(section, target)
+}
+
+// Bind decodes a top-level section of the configuration into target, see BindConfig
+func (c *Config) Bind(section string, target interface{}) error {
+	node, exists := c.sections[section]
+	if !exists {
+		if c.file == "" {
+			return fmt.Errorf("config section '%s' not found: no configuration file loaded", section)
+		}
+		return fmt.Errorf("config section '%s' not found in %s", section, c.file)
+	}
+
+	node = *copyYAMLNode(&node)
+	if err := interpolateEnv(&node); err != nil {
+		return fmt.Errorf("config section '%s': %v", section, err)
+	}
+	if err := node.Decode(target); err != nil {
+		return fmt.Errorf("config section '%s': %v", section, err)
+	}
+
+	if err := validate.Struct(target); err != nil {
+		// Non-struct targets (maps, slices) have nothing to validate
+		if _, invalid := err.(*validator.InvalidValidationError); !invalid {
+			return fmt.Errorf("config section '%s': %v", section, err)
+		}
+	}
+	if validatable, ok := target.(interface{ Validate() error }); ok {
+		if err := validatable.Validate(); err != nil {
+			return fmt.Errorf("config section '%s': %v", section, err)
+		}
+	}
+	return nil
+}
+
+// copyYAMLNode deep-copies a node so interpolation does not leak into the loaded configuration
+func copyYAMLNode(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = copyYAMLNode(child)
+	}
+	return &copied
+}
+
+// interpolateEnv expands environment references in the scalar values of a node tree.
+// Unset variables without a default are an error rather than an empty string.
+func interpolateEnv(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var missing string
+		original := node.Value
+		node.Value = envReferencePattern.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			match := envReferencePattern.FindStringSubmatch(reference)
+			if value, set := os.LookupEnv(match[1]); set {
+				return value
+			}
+			if match[2] != "" {
+				return match[3]
+			}
+			if missing == "" {
+				missing = match[1]
+			}
+			return reference
+		})
+		if missing != "" {
+			return fmt.Errorf("line %d: environment variable %s is not set", node.Line, missing)
+		}
+		// Interpolated values are re-typed from their content (e.g. "${PORT}" -> int)
+		if node.Value != original && node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) == 0 {
+			node.Tag = ""
+		}
+		return nil
+	}
+
+	for _, child := range node.Content {
+		if err := interpolateEnv(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
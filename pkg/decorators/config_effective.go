@@ -0,0 +1,328 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envOverridePrefix prefix of the environment variables overriding configuration keys,
+// e.g. DECO_REDIS_ENABLED=false or DECO_CACHE_DEFAULT_TTL=5m
+const envOverridePrefix = "DECO_"
+
+// secretConfigKeyPattern keys whose values are masked when the configuration is printed
+var secretConfigKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|api_?key|authorization|cookie|credential)`)
+
+// maskedConfigValue replacement printed for secret values
+const maskedConfigValue = "********"
+
+// ConfigOverride configuration value set outside the configuration file
+type ConfigOverride struct {
+	Path   string // YAML path, e.g. "redis.enabled"
+	Value  string // YAML value, e.g. "false" or "[go, python]"
+	Source string // e.g. "env DECO_REDIS_ENABLED" or "flag --set"
+}
+
+// EnvConfigOverrides collects the DECO_* environment variables naming a framework configuration key.
+// Underscores separate both sections and words (DECO_RATE_LIMIT_DEFAULT_RPS -> rate_limit.default_rps);
+// variables that do not resolve to a key, such as DECO_CONFIG, are ignored.
+func EnvConfigOverrides() []ConfigOverride {
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	var overrides []ConfigOverride
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, envOverridePrefix) {
+			continue
+		}
+		if path := resolveEnvConfigPath(strings.ToLower(strings.TrimPrefix(name, envOverridePrefix))); path != "" {
+			overrides = append(overrides, ConfigOverride{Path: path, Value: value, Source: "env " + name})
+		}
+	}
+	return overrides
+}
+
+// resolveEnvConfigPath maps an underscore-separated name to a configuration key, preferring the longest
+// matching key at each level; empty when the name is not a framework key
+func resolveEnvConfigPath(name string) string {
+	schema, path := getConfigSchema(), ""
+	for {
+		best := ""
+		for property := range schema.Properties {
+			if (name == property || strings.HasPrefix(name, property+"_")) && len(property) > len(best) {
+				best = property
+			}
+		}
+		if best == "" {
+			return ""
+		}
+
+		path = joinConfigPath(path, best)
+		schema = schema.Properties[best]
+		if name == best {
+			// Whole sections are not overridable: DECO_DEV=1 must not replace dev:
+			if schema.Properties != nil {
+				return ""
+			}
+			return path
+		}
+		if schema.Properties == nil {
+			return ""
+		}
+		name = name[len(best)+1:]
+	}
+}
+
+// configSchemaAt schema of a YAML path, nil when the key is unknown
+func configSchemaAt(path string) *configSchema {
+	schema := getConfigSchema()
+	for _, segment := range strings.Split(path, ".") {
+		if property, exists := schema.Properties[segment]; exists {
+			schema = property
+			continue
+		}
+		switch additional := schema.AdditionalProperties.(type) {
+		case *configSchema:
+			schema = additional
+		case bool:
+			if !additional {
+				return nil
+			}
+			return &configSchema{} // application section: any value
+		default:
+			return nil
+		}
+	}
+	return schema
+}
+
+// applyConfigOverrides sets the overrides on the YAML document, checking each value against the schema
+func applyConfigOverrides(data []byte, overrides []ConfigOverride) ([]byte, []ConfigIssue) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, []ConfigIssue{{Message: err.Error()}}
+	}
+	if len(document.Content) == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	var issues []ConfigIssue
+	for _, override := range overrides {
+		schema := configSchemaAt(override.Path)
+		if override.Path == "" || schema == nil {
+			issues = append(issues, ConfigIssue{File: override.Source, Path: override.Path, Message: "unknown key"})
+			continue
+		}
+
+		value := parseOverrideValue(override.Value)
+		before := len(issues)
+		validateConfigNode(schema, value, override.Path, override.Source, &issues)
+		if len(issues) > before {
+			continue
+		}
+		if err := setConfigNode(document.Content[0], strings.Split(override.Path, "."), value); err != nil {
+			issues = append(issues, ConfigIssue{File: override.Source, Path: override.Path, Message: err.Error()})
+		}
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	merged, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, []ConfigIssue{{Message: err.Error()}}
+	}
+	return merged, nil
+}
+
+// parseOverrideValue reads an override as YAML ("false", "5m", "[go, python]"), falling back to a plain string
+func parseOverrideValue(value string) *yaml.Node {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil || len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	}
+	node := document.Content[0]
+	clearNodePositions(node)
+	return node
+}
+
+// clearNodePositions drops line/column so issues point at the override source instead
+func clearNodePositions(node *yaml.Node) {
+	node.Line, node.Column = 0, 0
+	for _, child := range node.Content {
+		clearNodePositions(child)
+	}
+}
+
+// setConfigNode replaces (or creates) the value at a path of a mapping node
+func setConfigNode(mapping *yaml.Node, path []string, value *yaml.Node) error {
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot set a key inside a non-object value")
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			mapping.Content[i+1] = value
+			return nil
+		}
+		child := mapping.Content[i+1]
+		if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+			*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		return setConfigNode(child, path[1:], value)
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		mapping.Content = append(mapping.Content, key, value)
+		return nil
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, key, child)
+	return setConfigNode(child, path[1:], value)
+}
+
+// RenderEffectiveConfig prints the loaded configuration — defaults, file, application sections and
+// overrides merged — as "yaml" or "json", with secret values masked. In YAML, overridden keys are
+// annotated with their source.
+func RenderEffectiveConfig(c *Config, overrides []ConfigOverride, format string) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(c); err != nil {
+		return nil, fmt.Errorf("error serializing configuration: %v", err)
+	}
+
+	// Application sections are not part of Config and are appended as loaded
+	names := make([]string, 0, len(c.sections))
+	for name := range c.sections {
+		if _, framework := getConfigSchema().Properties[name]; !framework {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		section := c.sections[name]
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, copyYAMLNode(&section))
+	}
+
+	maskConfigSecrets(&root, false)
+
+	if format == "" || format == "yaml" {
+		for _, override := range overrides {
+			if key := findConfigKey(&root, strings.Split(override.Path, ".")); key != nil {
+				key.LineComment = "from " + override.Source
+			}
+		}
+	}
+
+	header := "effective configuration: defaults"
+	if c.workspace != "" {
+		header += " + " + c.workspace
+	}
+	if c.file != "" {
+		header += " + " + c.file
+	}
+	if len(overrides) > 0 {
+		header += fmt.Sprintf(" + %d override(s)", len(overrides))
+	}
+	return renderConfigNode(&root, header, format)
+}
+
+// RenderConfigFile prints a configuration file as written, without defaults or overrides,
+// as "yaml" or "json", with secret values masked
+func RenderConfigFile(path, format string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file de configuration %s: %v", path, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("error parsing da configuration: %v", err)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(document.Content) > 0 {
+		root = document.Content[0]
+	}
+
+	maskConfigSecrets(root, false)
+	return renderConfigNode(root, path, format)
+}
+
+// renderConfigNode serializes a configuration tree; YAML output starts with a header comment
+func renderConfigNode(root *yaml.Node, header, format string) ([]byte, error) {
+	switch format {
+	case "", "yaml":
+		data, err := yaml.Marshal(root)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing configuration: %v", err)
+		}
+		return append([]byte("# "+header+"\n"), data...), nil
+	case "json":
+		var value interface{}
+		if err := root.Decode(&value); err != nil {
+			return nil, fmt.Errorf("error serializing configuration: %v", err)
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error serializing configuration: %v", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unknown format '%s' (valid: yaml, json)", format)
+	}
+}
+
+// maskConfigSecrets replaces secret values: keys matching secretConfigKeyPattern (every value below them)
+// and the password of URLs with credentials
+func maskConfigSecrets(node *yaml.Node, secret bool) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			maskConfigSecrets(node.Content[i+1], secret || secretConfigKeyPattern.MatchString(node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			maskConfigSecrets(child, secret)
+		}
+	case yaml.ScalarNode:
+		if node.Value == "" || node.Tag == "!!null" {
+			return
+		}
+		if secret {
+			node.Tag, node.Style, node.Value = "!!str", 0, maskedConfigValue
+			return
+		}
+		if parsed, err := url.Parse(node.Value); err == nil && parsed.User != nil {
+			if _, hasPassword := parsed.User.Password(); hasPassword {
+				// The username is escaped, so the first '@' closes the userinfo
+				parsed.User = url.User(parsed.User.Username())
+				node.Value = strings.Replace(parsed.String(), "@", ":"+maskedConfigValue+"@", 1)
+			}
+		}
+	}
+}
+
+// findConfigKey key node of a path in a mapping, nil when absent
+func findConfigKey(mapping *yaml.Node, path []string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return mapping.Content[i]
+		}
+		return findConfigKey(mapping.Content[i+1], path[1:])
+	}
+	return nil
+}
@@ -0,0 +1,417 @@
+package decorators
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSchemaURL where the JSON Schema of the configuration file is published (docs/deco.schema.json)
+const ConfigSchemaURL = "https://raw.githubusercontent.com/RodolfoBonis/deco/main/docs/deco.schema.json"
+
+// configSchema JSON Schema (subset) of the configuration file, derived from the Config struct
+type configSchema struct {
+	Schema               string                   `json:"$schema,omitempty"`
+	ID                   string                   `json:"$id,omitempty"`
+	Title                string                   `json:"title,omitempty"`
+	Type                 string                   `json:"type,omitempty"`
+	Format               string                   `json:"format,omitempty"`
+	Pattern              string                   `json:"pattern,omitempty"`
+	Enum                 []string                 `json:"enum,omitempty"`
+	Default              interface{}              `json:"default,omitempty"`
+	Properties           map[string]*configSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}              `json:"additionalProperties,omitempty"` // bool or *configSchema
+	Items                *configSchema            `json:"items,omitempty"`
+}
+
+// configFormatPatterns patterns published for the formats: JSON Schema editors know neither "5m" durations
+// ("duration" is ISO 8601 there) nor byte sizes
+var configFormatPatterns = map[string]string{
+	"duration":  `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`,
+	"byte-size": `^ *([0-9]+(\.[0-9]*)?|\.[0-9]+) *([KkMmGgTt]([Ii]?[Bb])?|[Bb])? *$`,
+}
+
+// configFieldFormats string fields with a format, by YAML path
+var configFieldFormats = map[string]string{
+	"cache.default_ttl":                              "duration",
+	"metrics.slow_threshold":                         "duration",
+	"websocket.ping_interval":                        "duration",
+	"websocket.pong_timeout":                         "duration",
+	"proxy.load_balancing.health_check_interval":     "duration",
+	"proxy.load_balancing.health_check_timeout":      "duration",
+	"proxy.circuit_breaker.default_recovery_timeout": "duration",
+	"proxy.retry.default_delay":                      "duration",
+	"proxy.http_client.timeout":                      "duration",
+	"proxy.http_client.idle_conn_timeout":            "duration",
+	"profiling.upload_interval":                      "duration",
+	"events.timeout":                                 "duration",
+	"outbox.poll_interval":                           "duration",
+	"outbox.retry_backoff":                           "duration",
+	"body_capture.max_bytes":                         "byte-size",
+	"limits.max_body_size":                           "byte-size",
+	"compression.min_size":                           "byte-size",
+	"bench.duration":                                 "duration",
+	"bench.budgets.*":                                "duration",
+	"warmup.timeout":                                 "duration",
+	"auth.jwt.jwks_refresh":                          "duration",
+	"auth.jwt.leeway":                                "duration",
+	"auth.cache_ttl":                                 "duration",
+	"auth.revocation.subject_ttl":                    "duration",
+	"auth.tokens.access_ttl":                         "duration",
+	"auth.tokens.refresh_ttl":                        "duration",
+}
+
+// configFieldEnums string fields with a closed set of values, by YAML path
+var configFieldEnums = map[string][]string{
+	"rate_limit.type":               {"memory", "redis"},
+	"rate_limit.algorithm":          {RateLimitSlidingWindow, RateLimitTokenBucket},
+	"cache.etag":                    {CacheETagStrong, CacheETagWeak, CacheETagOff},
+	"access_log.format":             {"combined", "json", "template"},
+	"access_log.output":             {"stdout", "stderr", "file", "syslog"},
+	"outbox.dialect":                {"postgres", "mysql", "sqlite"},
+	"spec_lint.fail_on":             {"error", "warn", "never"},
+	"openapi.operation_id.strategy": {"funcName", "methodPath", "template"},
+	"openapi.spec_version":          {"3.0", "3.1"},
+	"client_sdk.languages[]":        {"go", "python", "javascript", "typescript"},
+	"docs.branding.theme":           {"dark", "light", "auto"},
+	"privacy.detectors[]":           {"email", "card", "cpf"},
+	"changelog.store":               {"file", "redis"},
+	"auth.jwt.algorithms[]":         jwtAlgorithmNames(),
+	"auth.revocation.store":         {RevocationStoreMemory, RevocationStoreRedis},
+	"versioning.strategy":           {VersioningPath, VersioningHeader, VersioningQuery},
+	"logging.level":                 {"debug", "info", "warn", "error"},
+}
+
+// ConfigIssue problem found in the configuration file, with the position of the offending YAML node
+type ConfigIssue struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"` // e.g. "events.timeout"
+	Message string `json:"message"`
+}
+
+// String formats the issue as file:line:column: path: message
+func (i ConfigIssue) String() string {
+	var b strings.Builder
+	if i.File != "" {
+		b.WriteString(i.File)
+		if i.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", i.Line, i.Column)
+		}
+		b.WriteString(": ")
+	}
+	if i.Path != "" {
+		b.WriteString(i.Path + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// ConfigValidationError configuration file rejected at load time
+type ConfigValidationError struct {
+	Issues []ConfigIssue
+}
+
+func (e *ConfigValidationError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = "  " + issue.String()
+	}
+	return fmt.Sprintf("invalid configuration (%d issue(s)):\n%s", len(e.Issues), strings.Join(lines, "\n"))
+}
+
+// configSchemaOnce the schema is built from the Config struct on first use
+var (
+	configSchemaOnce sync.Once
+	configSchemaRoot *configSchema
+)
+
+// getConfigSchema returns the schema of the configuration file
+func getConfigSchema() *configSchema {
+	configSchemaOnce.Do(func() {
+		configSchemaRoot = buildConfigSchema(reflect.TypeOf(Config{}), "")
+		// Other top-level sections belong to the application (BindConfig)
+		configSchemaRoot.AdditionalProperties = true
+	})
+	return configSchemaRoot
+}
+
+// buildConfigSchema describes a Go type; path is the YAML path used for formats and enums
+func buildConfigSchema(t reflect.Type, path string) *configSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &configSchema{Type: "object", Properties: make(map[string]*configSchema), AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			schema.Properties[name] = buildConfigSchema(field.Type, joinConfigPath(path, name))
+		}
+		return schema
+	case reflect.Map:
+		return &configSchema{Type: "object", AdditionalProperties: buildConfigSchema(t.Elem(), path+".*")}
+	case reflect.Slice, reflect.Array:
+		return &configSchema{Type: "array", Items: buildConfigSchema(t.Elem(), path+"[]")}
+	case reflect.String:
+		return &configSchema{Type: "string", Format: configFieldFormats[path], Enum: configFieldEnums[path]}
+	case reflect.Bool:
+		return &configSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &configSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &configSchema{Type: "number"}
+	default:
+		return &configSchema{} // interface{}: any value
+	}
+}
+
+// ConfigJSONSchema returns the JSON Schema (draft-07) of the configuration file, with the defaults of
+// DefaultConfig, so editors (yaml-language-server, VS Code) validate and complete .deco.yaml
+func ConfigJSONSchema() ([]byte, error) {
+	root := publishConfigSchema(getConfigSchema(), reflect.ValueOf(DefaultConfig()).Elem())
+	root.Schema = "http://json-schema.org/draft-07/schema#"
+	root.ID = ConfigSchemaURL
+	root.Title = "deco configuration (.deco.yaml)"
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// publishConfigSchema copies a schema for publication: formats become patterns and the non-zero values
+// of defaults (invalid for items and map values) become "default"
+func publishConfigSchema(schema *configSchema, defaults reflect.Value) *configSchema {
+	published := *schema
+	published.Format = ""
+	published.Pattern = configFormatPatterns[schema.Format]
+	for defaults.IsValid() && defaults.Kind() == reflect.Ptr {
+		defaults = defaults.Elem()
+	}
+
+	if schema.Properties != nil {
+		fields := make(map[string]reflect.Value)
+		if defaults.IsValid() && defaults.Kind() == reflect.Struct {
+			for i := 0; i < defaults.NumField(); i++ {
+				field := defaults.Type().Field(i)
+				if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); field.IsExported() && name != "" && name != "-" {
+					fields[name] = defaults.Field(i)
+				}
+			}
+		}
+		published.Properties = make(map[string]*configSchema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			published.Properties[name] = publishConfigSchema(property, fields[name])
+		}
+	} else if defaults.IsValid() && !defaults.IsZero() && defaults.Kind() != reflect.Struct {
+		if (defaults.Kind() != reflect.Slice && defaults.Kind() != reflect.Map) || defaults.Len() > 0 {
+			published.Default = defaults.Interface()
+		}
+	}
+	if items := schema.Items; items != nil {
+		published.Items = publishConfigSchema(items, reflect.Value{})
+	}
+	if additional, ok := schema.AdditionalProperties.(*configSchema); ok {
+		published.AdditionalProperties = publishConfigSchema(additional, reflect.Value{})
+	}
+	return &published
+}
+
+// joinConfigPath joins YAML path segments
+func joinConfigPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// ValidateConfigFile runs the load-time checks on a configuration file and returns its issues:
+// unknown keys, wrong types, invalid durations and enums (with the line of the offending node),
+// then the semantic checks of Config.Validate
+func ValidateConfigFile(path string) ([]ConfigIssue, error) {
+	_, err := LoadConfig(path)
+	var invalid *ConfigValidationError
+	if errors.As(err, &invalid) {
+		return invalid.Issues, nil
+	}
+	return nil, err
+}
+
+// validateConfigSchema validates the YAML document against the configuration schema
+func validateConfigSchema(data []byte, file string) []ConfigIssue {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return []ConfigIssue{{File: file, Message: err.Error()}}
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	var issues []ConfigIssue
+	validateConfigNode(getConfigSchema(), document.Content[0], "", file, &issues)
+	return issues
+}
+
+// validateConfigNode validates a node and its children, appending the issues found
+func validateConfigNode(schema *configSchema, node *yaml.Node, path, file string, issues *[]ConfigIssue) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	report := func(format string, args ...interface{}) {
+		*issues = append(*issues, ConfigIssue{File: file, Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			report("expected object, found %s", describeConfigNode(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			childPath := joinConfigPath(path, key.Value)
+			if property, exists := schema.Properties[key.Value]; exists {
+				validateConfigNode(property, value, childPath, file, issues)
+				continue
+			}
+			switch additional := schema.AdditionalProperties.(type) {
+			case *configSchema:
+				validateConfigNode(additional, value, childPath, file, issues)
+			case bool:
+				if !additional {
+					message := fmt.Sprintf("unknown key '%s'", key.Value)
+					if suggestion := suggestConfigKey(key.Value, schema.Properties); suggestion != "" {
+						message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+					}
+					*issues = append(*issues, ConfigIssue{File: file, Line: key.Line, Column: key.Column, Path: childPath, Message: message})
+				}
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			report("expected array, found %s", describeConfigNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			validateConfigNode(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), file, issues)
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			report("expected string, found %s", describeConfigNode(node))
+			return
+		}
+		if node.Value == "" {
+			return
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, node.Value) {
+			report("invalid value '%s' (valid: %s)", node.Value, strings.Join(schema.Enum, ", "))
+		}
+		switch schema.Format {
+		case "duration":
+			if _, err := time.ParseDuration(node.Value); err != nil {
+				report("invalid duration '%s' (e.g. \"500ms\", \"30s\", \"5m\")", node.Value)
+			}
+		case "byte-size":
+			if _, err := ParseByteSize(node.Value); err != nil {
+				report("invalid size '%s' (e.g. \"64KB\", \"10MB\")", node.Value)
+			}
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			report("expected integer, found %s", describeConfigNode(node))
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			report("expected number, found %s", describeConfigNode(node))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			report("expected boolean, found %s", describeConfigNode(node))
+		}
+	}
+}
+
+// describeConfigNode names the YAML type of a node for error messages
+func describeConfigNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return fmt.Sprintf("integer %s", node.Value)
+	case "!!float":
+		return fmt.Sprintf("number %s", node.Value)
+	case "!!bool":
+		return fmt.Sprintf("boolean %s", node.Value)
+	default:
+		return fmt.Sprintf("string %q", node.Value)
+	}
+}
+
+// suggestConfigKey closest known key to a misspelled one, empty when none is close
+func suggestConfigKey(key string, properties map[string]*configSchema) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if distance := levenshtein(key, name); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// levenshtein edit distance between two strings
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,130 @@
+package decorators
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigSchema(t *testing.T) {
+	data := []byte(`version: "1.0"
+handlers:
+  include: ["handlers/*.go"]
+events:
+  timout: 5s
+  queue_size: many
+cache:
+  type: memcached
+  default_ttl: 1 hour
+body_capture:
+  max_bytes: lots
+metrics:
+  enabled: "yes"
+  buckets: [0.1, 1]
+client_sdk:
+  languages: [go, rust]
+redis: enabled
+myapp:
+  anything: [1, 2]
+`)
+
+	var messages []string
+	for _, issue := range validateConfigSchema(data, ".deco.yaml") {
+		messages = append(messages, issue.String())
+	}
+	assert.Equal(t, []string{
+		".deco.yaml:5:3: events.timout: unknown key 'timout' (did you mean 'timeout'?)",
+		`.deco.yaml:6:15: events.queue_size: expected integer, found string "many"`,
+		`.deco.yaml:9:16: cache.default_ttl: invalid duration '1 hour' (e.g. "500ms", "30s", "5m")`,
+		`.deco.yaml:11:14: body_capture.max_bytes: invalid size 'lots' (e.g. "64KB", "10MB")`,
+		`.deco.yaml:13:12: metrics.enabled: expected boolean, found string "yes"`,
+		".deco.yaml:16:19: client_sdk.languages[1]: invalid value 'rust' (valid: go, python, javascript, typescript)",
+		`.deco.yaml:17:8: redis: expected object, found string "enabled"`,
+	}, messages)
+
+	assert.Nil(t, validateConfigSchema([]byte(""), ".deco.yaml"))
+	assert.Len(t, validateConfigSchema([]byte("events: [\n"), ".deco.yaml"), 1)
+}
+
+func TestValidateConfigSchema_SavedDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".deco.yaml")
+	require.NoError(t, SaveConfig(DefaultConfig(), path))
+
+	issues, err := ValidateConfigFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLoadConfig_RejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".deco.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\noutbox:\n  poll_interval: soon\n"), 0o600))
+
+	_, err := LoadConfig(path)
+	var invalid *ConfigValidationError
+	require.True(t, errors.As(err, &invalid))
+	require.Len(t, invalid.Issues, 1)
+	assert.Equal(t, 5, invalid.Issues[0].Line)
+	assert.Equal(t, "outbox.poll_interval", invalid.Issues[0].Path)
+	assert.Contains(t, err.Error(), "invalid duration 'soon'")
+
+	// Semantic checks of Config.Validate run after the schema
+	require.NoError(t, os.WriteFile(path, []byte("version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\nopenapi:\n  version: \"3.0.0\"\n  servers:\n    - url: https://api.example.com\n"), 0o600))
+	issues, err := ValidateConfigFile(path)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, "name is required")
+}
+
+func TestSuggestConfigKey(t *testing.T) {
+	properties := map[string]*configSchema{"timeout": {}, "queue_size": {}, "sink": {}}
+	assert.Equal(t, "timeout", suggestConfigKey("timeuot", properties))
+	assert.Equal(t, "sink", suggestConfigKey("snk", properties))
+	assert.Empty(t, suggestConfigKey("retries", properties))
+}
+
+func TestConfigJSONSchema(t *testing.T) {
+	data, err := ConfigJSONSchema()
+	require.NoError(t, err)
+
+	var schema configSchema
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, ConfigSchemaURL, schema.ID)
+	assert.Equal(t, true, schema.AdditionalProperties, "application sections are allowed")
+
+	warmup := schema.Properties["warmup"]
+	require.NotNil(t, warmup)
+	assert.Equal(t, false, warmup.AdditionalProperties)
+	timeout := warmup.Properties["timeout"]
+	assert.Equal(t, "10s", timeout.Default)
+	assert.Empty(t, timeout.Format, "editors read format \"duration\" as ISO 8601")
+
+	duration := regexp.MustCompile(timeout.Pattern)
+	for _, valid := range []string{"500ms", "30s", "1h30m", "1.5h", "0"} {
+		assert.True(t, duration.MatchString(valid), valid)
+	}
+	for _, invalid := range []string{"1 hour", "5", "abc"} {
+		assert.False(t, duration.MatchString(invalid), invalid)
+	}
+
+	size := regexp.MustCompile(schema.Properties["body_capture"].Properties["max_bytes"].Pattern)
+	for _, valid := range []string{"512", "10KB", "5mb", "1GiB", "1.5k"} {
+		assert.True(t, size.MatchString(valid), valid)
+	}
+	assert.False(t, size.MatchString("lots"))
+
+	assert.Equal(t, []string{"memory", "redis"}, schema.Properties["rate_limit"].Properties["type"].Enum)
+}
+
+func TestConfigJSONSchema_Published(t *testing.T) {
+	data, err := ConfigJSONSchema()
+	require.NoError(t, err)
+	published, err := os.ReadFile("../../docs/deco.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(published), "regenerate with: deco config schema -o docs/deco.schema.json")
+}
@@ -121,9 +121,10 @@ func TestSaveConfig(t *testing.T) {
 	err := SaveConfig(config, configPath)
 	assert.NoError(t, err)
 
-	// Check if file exists
-	_, err = os.Stat(configPath)
+	// Check if file exists and points editors at the schema
+	data, err := os.ReadFile(configPath)
 	assert.NoError(t, err)
+	assert.Contains(t, string(data), "# yaml-language-server: $schema="+ConfigSchemaURL+"\n")
 
 	// Load config back
 	loadedConfig, err := LoadConfig(configPath)
@@ -324,3 +325,29 @@ handlers:
 	configPath = findConfigFile()
 	assert.Equal(t, "/custom/path/config.yaml", configPath)
 }
+
+func TestGenerationConfig_OutputAndPackage(t *testing.T) {
+	assert.Equal(t, DefaultOutputPath, GenerationConfig{}.OutputPath())
+	assert.Equal(t, DefaultPackageName, GenerationConfig{}.PackageName())
+
+	generation := GenerationConfig{Output: "internal/gen/deco/routes.go", Package: "gen"}
+	assert.Equal(t, "internal/gen/deco/routes.go", generation.OutputPath())
+	assert.Equal(t, "gen", generation.PackageName())
+
+	config := &Config{Version: "1.0", Handlers: HandlersConfig{Include: []string{"*.go"}}, Generate: generation}
+	assert.NoError(t, config.Validate())
+
+	for output, message := range map[string]string{
+		"internal/gen/deco":             "not a .go file",
+		"internal/gen/routes_test.go":   "test file",
+		"vendor/acme/gen/routes.go":     "inside vendor",
+		"api/testdata/gen/init_deco.go": "inside testdata",
+	} {
+		config.Generate = GenerationConfig{Output: output}
+		assert.ErrorContains(t, config.Validate(), message, output)
+	}
+	for _, pkg := range []string{"main", "my-gen", "func", "_", "1gen"} {
+		config.Generate = GenerationConfig{Package: pkg}
+		assert.ErrorContains(t, config.Validate(), "invalid generation.package", pkg)
+	}
+}
@@ -0,0 +1,235 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConformanceDebugPath endpoint listing the recent response conformance mismatches
+const ConformanceDebugPath = "/decorators/debug/conformance"
+
+// maxConformanceReports mismatching responses kept for the debug endpoint
+const maxConformanceReports = 100
+
+// ConformanceReport a response that does not match its declared @Response schema
+type ConformanceReport struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	Schema     string    `json:"schema,omitempty"`
+	Mismatches []string  `json:"mismatches"`
+	Time       time.Time `json:"time"`
+}
+
+// recent mismatches, newest last
+var (
+	conformanceReports      []ConformanceReport
+	conformanceReportsMutex sync.RWMutex
+)
+
+// ResponseConformanceMiddleware validates JSON responses of the registered routes against the schema
+// of their @Response for the status code, logging extra fields, wrong types, missing required fields
+// and undeclared status codes. It is meant for development and does nothing in -tags prod builds.
+func ResponseConformanceMiddleware() gin.HandlerFunc {
+	if prodBuild {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	declared := make(map[string][]ResponseInfo)
+	for _, route := range GetRoutes() {
+		declared[route.Method+" "+route.Path] = route.Responses
+	}
+
+	return func(c *gin.Context) {
+		responses, exists := declared[c.Request.Method+" "+c.FullPath()]
+		if !exists || len(responses) == 0 {
+			c.Next()
+			return
+		}
+
+		capture := CaptureBodies(c)
+		c.Next()
+
+		if _, truncated := capture.ResponseSize(); truncated {
+			return
+		}
+		report := checkResponseConformance(responses, c.Writer.Status(), c.Writer.Header().Get("Content-Type"), capture.ResponseBody())
+		if report == nil {
+			return
+		}
+
+		report.Method, report.Path, report.Time = c.Request.Method, c.FullPath(), time.Now()
+		LogNormal("⚠️  Response of %s %s (%d) does not match %s: %s",
+			report.Method, report.Path, report.Status, report.Schema, strings.Join(report.Mismatches, "; "))
+		recordConformanceReport(*report)
+	}
+}
+
+// checkResponseConformance compares a response with the declared responses, nil when it conforms
+func checkResponseConformance(responses []ResponseInfo, status int, contentType string, body []byte) *ConformanceReport {
+	var declared *ResponseInfo
+	for i := range responses {
+		if responses[i].Code == strconv.Itoa(status) {
+			declared = &responses[i]
+			break
+		}
+	}
+	if declared == nil {
+		return &ConformanceReport{Status: status, Mismatches: []string{fmt.Sprintf("status %d is not declared with @Response", status)}}
+	}
+
+	if declared.Type == "" || !strings.Contains(contentType, "json") || len(body) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return &ConformanceReport{Status: status, Schema: declared.Type, Mismatches: []string{"invalid JSON: " + err.Error()}}
+	}
+
+	var mismatches []string
+	checkConformanceType(declared.Type, value, "$", &mismatches)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	return &ConformanceReport{Status: status, Schema: declared.Type, Mismatches: mismatches}
+}
+
+// checkConformanceType checks a value against a Go type name of a response ("User", "[]User")
+func checkConformanceType(typeName string, value interface{}, path string, mismatches *[]string) {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if itemType, isSlice := strings.CutPrefix(typeName, "[]"); isSlice {
+		items, ok := value.([]interface{})
+		if !ok {
+			*mismatches = append(*mismatches, fmt.Sprintf("%s: expected array, found %s", path, jsonTypeName(value)))
+			return
+		}
+		for i, item := range items {
+			checkConformanceType(itemType, item, fmt.Sprintf("%s[%d]", path, i), mismatches)
+		}
+		return
+	}
+
+	// Types without a registered schema (maps, time.Time, external types) are not checked
+	if _, name, qualified := strings.Cut(typeName, "."); qualified {
+		typeName = name
+	}
+	if schema := GetSchema(typeName); schema != nil {
+		checkConformanceSchema(schema, value, path, mismatches)
+	}
+}
+
+// checkConformanceSchema checks an object against a registered schema
+func checkConformanceSchema(schema *SchemaInfo, value interface{}, path string, mismatches *[]string) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		*mismatches = append(*mismatches, fmt.Sprintf("%s: expected object %s, found %s", path, schema.Name, jsonTypeName(value)))
+		return
+	}
+
+	fields := make([]string, 0, len(object))
+	for field := range object {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		property, declared := schema.Properties[field]
+		if !declared {
+			*mismatches = append(*mismatches, fmt.Sprintf("%s.%s: field not declared in %s", path, field, schema.Name))
+			continue
+		}
+		checkConformanceProperty(property, object[field], path+"."+field, mismatches)
+	}
+	for _, field := range schema.Required {
+		if _, present := object[field]; !present {
+			*mismatches = append(*mismatches, fmt.Sprintf("%s.%s: required field missing", path, field))
+		}
+	}
+}
+
+// checkConformanceProperty checks a field value against its property; null is always accepted
+func checkConformanceProperty(property *PropertyInfo, value interface{}, path string, mismatches *[]string) {
+	if value == nil {
+		return
+	}
+
+	switch property.Type {
+	case "object":
+		// Nested structs are checked against their schema; maps and other types are not
+		if property.GoType != "" {
+			checkConformanceType(property.GoType, value, path, mismatches)
+		}
+		return
+	case "array":
+		if property.GoType != "" {
+			checkConformanceType(property.GoType, value, path, mismatches)
+			return
+		}
+	case "integer":
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			return
+		}
+	case "number":
+		if _, ok := value.(float64); ok {
+			return
+		}
+	case "string", "boolean":
+	default:
+		return
+	}
+
+	if found := jsonTypeName(value); found != property.Type {
+		*mismatches = append(*mismatches, fmt.Sprintf("%s: expected %s, found %s", path, property.Type, found))
+	}
+}
+
+// jsonTypeName JSON type of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// recordConformanceReport keeps a mismatch for the debug endpoint
+func recordConformanceReport(report ConformanceReport) {
+	conformanceReportsMutex.Lock()
+	defer conformanceReportsMutex.Unlock()
+
+	conformanceReports = append(conformanceReports, report)
+	if len(conformanceReports) > maxConformanceReports {
+		conformanceReports = conformanceReports[len(conformanceReports)-maxConformanceReports:]
+	}
+}
+
+// GetConformanceReports returns the recent response conformance mismatches, newest last
+func GetConformanceReports() []ConformanceReport {
+	conformanceReportsMutex.RLock()
+	defer conformanceReportsMutex.RUnlock()
+	return append([]ConformanceReport(nil), conformanceReports...)
+}
+
+// ConformanceReportsHandler lists the recent response conformance mismatches
+func ConformanceReportsHandler(c *gin.Context) {
+	reports := GetConformanceReports()
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "count": len(reports)})
+}
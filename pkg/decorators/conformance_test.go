@@ -0,0 +1,107 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerConformanceSchemas(t *testing.T) {
+	ClearSchemas()
+	t.Cleanup(ClearSchemas)
+
+	RegisterSchema(&SchemaInfo{
+		Name: "AddressResponse",
+		Type: "object",
+		Properties: map[string]*PropertyInfo{
+			"city": {Name: "city", Type: "string"},
+		},
+	})
+	RegisterSchema(&SchemaInfo{
+		Name: "OrderResponse",
+		Type: "object",
+		Properties: map[string]*PropertyInfo{
+			"id":         {Name: "id", Type: "integer", GoType: "int"},
+			"total":      {Name: "total", Type: "number", GoType: "float64"},
+			"paid":       {Name: "paid", Type: "boolean", GoType: "bool"},
+			"tags":       {Name: "tags", Type: "array", GoType: "[]string"},
+			"address":    {Name: "address", Type: "object", GoType: "*AddressResponse"},
+			"created_at": {Name: "created_at", Type: "object", GoType: "time.Time"},
+		},
+		Required: []string{"id"},
+	})
+}
+
+func TestCheckResponseConformance(t *testing.T) {
+	registerConformanceSchemas(t)
+	responses := []ResponseInfo{{Code: "200", Type: "OrderResponse"}, {Code: "404", Description: "Not found"}}
+
+	conforming := `{"id": 1, "total": 9.5, "paid": true, "tags": ["a"], "address": {"city": "Porto"}, "created_at": "2024-01-01T00:00:00Z"}`
+	assert.Nil(t, checkResponseConformance(responses, 200, "application/json", []byte(conforming)))
+	assert.Nil(t, checkResponseConformance(responses, 200, "application/json", []byte(`{"id": 1, "address": null}`)))
+	assert.Nil(t, checkResponseConformance(responses, 404, "application/json", []byte(`{"error": "not_found"}`)))
+	assert.Nil(t, checkResponseConformance(responses, 200, "text/plain", []byte("ok")))
+
+	report := checkResponseConformance(responses, 200, "application/json; charset=utf-8",
+		[]byte(`{"id": 1.5, "paid": "yes", "tags": "a", "address": {"city": 7}, "discount": 3}`))
+	require.NotNil(t, report)
+	assert.Equal(t, "OrderResponse", report.Schema)
+	assert.Equal(t, []string{
+		"$.address.city: expected string, found number",
+		"$.discount: field not declared in OrderResponse",
+		"$.id: expected integer, found number",
+		"$.paid: expected boolean, found string",
+		"$.tags: expected array, found string",
+	}, report.Mismatches)
+
+	report = checkResponseConformance(responses, 200, "application/json", []byte(`{"total": 1}`))
+	require.NotNil(t, report)
+	assert.Equal(t, []string{"$.id: required field missing"}, report.Mismatches)
+
+	report = checkResponseConformance(responses, 500, "application/json", []byte(`{}`))
+	require.NotNil(t, report)
+	assert.Equal(t, []string{"status 500 is not declared with @Response"}, report.Mismatches)
+
+	report = checkResponseConformance([]ResponseInfo{{Code: "200", Type: "[]OrderResponse"}}, 200, "application/json", []byte(`[{"id": 1}, {"id": "2"}]`))
+	require.NotNil(t, report)
+	assert.Equal(t, []string{"$[1].id: expected integer, found string"}, report.Mismatches)
+}
+
+func TestResponseConformanceMiddleware(t *testing.T) {
+	if prodBuild {
+		t.Skip("conformance checking is disabled in prod builds")
+	}
+	registerConformanceSchemas(t)
+
+	registryMutex.Lock()
+	saved := routes
+	routes = []RouteEntry{{Method: "GET", Path: "/orders/:id", Responses: []ResponseInfo{{Code: "200", Type: "OrderResponse"}}}}
+	registryMutex.Unlock()
+	t.Cleanup(func() {
+		registryMutex.Lock()
+		routes = saved
+		registryMutex.Unlock()
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ResponseConformanceMiddleware())
+	router.GET("/orders/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	before := len(GetConformanceReports())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/7", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	reports := GetConformanceReports()
+	require.Len(t, reports, before+1)
+	report := reports[len(reports)-1]
+	assert.Equal(t, "/orders/:id", report.Path)
+	assert.Equal(t, []string{"$.id: expected integer, found string"}, report.Mismatches)
+}
@@ -0,0 +1,198 @@
+package decorators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// ControllerInfo struct whose methods are the handlers of its routes (@Controller on the type)
+type ControllerInfo struct {
+	Type        string `json:"type"`                  // UserController
+	Prefix      string `json:"prefix,omitempty"`      // path prefix of the routes of its methods, e.g. /users
+	Constructor string `json:"constructor,omitempty"` // function returning the controller, e.g. NewUserController; &UserController{} when empty
+}
+
+// parseControllerArgs parses @Controller("/users", constructor="NewUserController")
+func parseControllerArgs(args []string) (ControllerInfo, error) {
+	var controller ControllerInfo
+	prefixSet := false
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			if prefixSet {
+				return controller, fmt.Errorf("@Controller: unexpected argument '%s'", arg)
+			}
+			controller.Prefix, prefixSet = MarkerValue(arg), true
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "prefix":
+			controller.Prefix, prefixSet = value, true
+		case "constructor":
+			if !token.IsIdentifier(value) {
+				return controller, fmt.Errorf("@Controller: constructor '%s' is not a function name", value)
+			}
+			controller.Constructor = value
+		default:
+			return controller, fmt.Errorf("@Controller: unknown argument '%s' (valid: prefix, constructor)", key)
+		}
+	}
+
+	controller.Prefix = strings.TrimSuffix(controller.Prefix, "/")
+	if controller.Prefix != "" && !strings.HasPrefix(controller.Prefix, "/") {
+		return controller, fmt.Errorf("@Controller: prefix '%s' must start with '/'", controller.Prefix)
+	}
+	if _, _, err := parseTypedPath(controller.Prefix); err != nil {
+		return controller, fmt.Errorf("@Controller: invalid prefix: %v", err)
+	}
+	return controller, nil
+}
+
+// parseControllerFromType reads the @Controller of a type declaration, nil when it has none
+func parseControllerFromType(fset *token.FileSet, fileName string, genDecl *ast.GenDecl) (*ControllerInfo, *ValidationError) {
+	if genDecl.Tok != token.TYPE || genDecl.Doc == nil || len(genDecl.Specs) != 1 {
+		return nil, nil
+	}
+	typeSpec, ok := genDecl.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return nil, nil
+	}
+
+	config, _ := GetMarker("Controller")
+	match := config.Pattern.FindStringSubmatch(genDecl.Doc.Text())
+	if match == nil {
+		return nil, nil
+	}
+	controller, err := parseControllerArgs(parseArgumentsFromString(match[1]))
+	if err == nil && typeSpec.TypeParams != nil {
+		err = fmt.Errorf("@Controller type %s cannot be generic", typeSpec.Name.Name)
+	}
+	if err != nil {
+		return nil, &ValidationError{
+			File:    filepath.Base(fileName),
+			Line:    fset.Position(genDecl.Pos()).Line,
+			Message: err.Error(),
+			Code:    "INVALID_CONTROLLER",
+		}
+	}
+	controller.Type = typeSpec.Name.Name
+	return &controller, nil
+}
+
+// receiverTypeName type of a method receiver, "UserController" for (u *UserController); empty for
+// functions and generic receivers
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	recv := funcDecl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// handlerFuncName name of the handler of a route: "GetUser" for functions, "UserController.Get" for methods
+func handlerFuncName(funcDecl *ast.FuncDecl) string {
+	if typeName := receiverTypeName(funcDecl); typeName != "" {
+		return typeName + "." + funcDecl.Name.Name
+	}
+	return funcDecl.Name.Name
+}
+
+// handlerController controller of a method handler, nil for functions; applyControllers completes it
+// with the @Controller of the type
+func handlerController(funcDecl *ast.FuncDecl) *ControllerInfo {
+	if typeName := receiverTypeName(funcDecl); typeName != "" {
+		return &ControllerInfo{Type: typeName}
+	}
+	return nil
+}
+
+// applyControllers completes the routes of controller methods with the @Controller of their type:
+// the prefix is prepended to their paths and the constructor recorded for the generated code. declared
+// holds the controllers by package and type, "handlers.UserController".
+func applyControllers(routes []*RouteMeta, declared map[string]*ControllerInfo) {
+	for _, route := range routes {
+		if route.Controller == nil {
+			continue
+		}
+		controller, ok := declared[route.PackageName+"."+route.Controller.Type]
+		if !ok {
+			continue // plain struct: instantiated with &Type{}, paths as declared
+		}
+		route.Controller = &ControllerInfo{Type: controller.Type, Prefix: controller.Prefix, Constructor: controller.Constructor}
+		if controller.Prefix == "" || route.Path == "" {
+			continue
+		}
+
+		// Validated by parseControllerArgs
+		prefix, prefixParams, _ := parseTypedPath(controller.Prefix)
+		if route.Path == "/" {
+			route.Path = prefix
+		} else {
+			route.Path = prefix + route.Path
+		}
+		route.PathParams = append(prefixParams, route.PathParams...)
+	}
+}
+
+// controllerMethod name of the controller method handling the route, "Get" for "UserController.Get"
+func (r *RouteMeta) controllerMethod() string {
+	return strings.TrimPrefix(r.FuncName, r.Controller.Type+".")
+}
+
+// controllerVar variable of the generated code holding the controller of a route
+func controllerVar(route *RouteMeta) string {
+	return lowerFirst(route.PackageName) + route.Controller.Type
+}
+
+// ControllerInstance controller created once by the generated init, shared by the routes of its methods
+type ControllerInstance struct {
+	Var   string // handlersUserController
+	Value string // handlers.NewUserController() or &handlers.UserController{}
+}
+
+// Controllers controllers of the routes, in the order of their first route
+func (d *GenData) Controllers() []ControllerInstance {
+	var instances []ControllerInstance
+	seen := make(map[string]bool)
+	for _, route := range d.Routes {
+		if route.Controller == nil || seen[controllerVar(route)] {
+			continue
+		}
+		seen[controllerVar(route)] = true
+
+		qualifier := ""
+		if d.ExternalHandlers() {
+			qualifier = route.PackageName + "."
+		}
+		value := "&" + qualifier + route.Controller.Type + "{}"
+		if route.Controller.Constructor != "" {
+			value = qualifier + route.Controller.Constructor + "()"
+		}
+		instances = append(instances, ControllerInstance{Var: controllerVar(route), Value: value})
+	}
+	return instances
+}
+
+// Handler expression of the generated code referencing the handler of a route: the function, qualified
+// by its package when external, or the method of the controller instance
+func (d *GenData) Handler(route *RouteMeta) string {
+	switch {
+	case route.Controller != nil:
+		return controllerVar(route) + "." + route.controllerMethod()
+	case d.ExternalHandlers():
+		return route.PackageName + "." + route.FuncName
+	}
+	return route.FuncName
+}
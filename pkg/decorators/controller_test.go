@@ -0,0 +1,126 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeControllerSources writes a @Controller struct with its methods in another file, and a plain struct
+// with a decorated method
+func writeControllerSources(t *testing.T) string {
+	dir := t.TempDir()
+	controller := `package handlers
+
+// UserService looks users up
+type UserService struct{}
+
+// @Controller("/tenants/{tenant:int}/users/", constructor="NewUserController")
+type UserController struct {
+	service *UserService
+}
+
+// NewUserController creates the controller with its dependencies
+func NewUserController() *UserController {
+	return &UserController{service: &UserService{}}
+}
+
+// StatusController has no @Controller: its methods keep their paths
+type StatusController struct{}
+`
+	methods := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/")
+func (u *UserController) List(c *gin.Context) {}
+
+// @Route("GET", "/{id}")
+// @Cache(ttl=1m)
+func (u UserController) Get(c *gin.Context) {}
+
+// @Route("GET", "/status")
+func (s *StatusController) Status(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "controller.go"), []byte(controller), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(methods), 0o600))
+	return dir
+}
+
+func TestParseControllerArgs(t *testing.T) {
+	controller, err := parseControllerArgs([]string{`"/users/"`, `constructor="NewUserController"`})
+	require.NoError(t, err)
+	assert.Equal(t, ControllerInfo{Prefix: "/users", Constructor: "NewUserController"}, controller)
+
+	controller, err = parseControllerArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, ControllerInfo{}, controller)
+
+	for _, args := range [][]string{{"users"}, {`"/a"`, `"/b"`}, {`constructor="New()"`}, {"scope=request"}, {`"/{id:}"`}} {
+		_, err := parseControllerArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestControllerRoutes(t *testing.T) {
+	routes, err := ParseDirectory(writeControllerSources(t))
+	require.NoError(t, err)
+	require.Len(t, routes, 3)
+
+	byName := make(map[string]*RouteMeta)
+	for _, route := range routes {
+		byName[route.FuncName] = route
+	}
+	list, get, status := byName["UserController.List"], byName["UserController.Get"], byName["StatusController.Status"]
+	require.NotNil(t, list)
+	require.NotNil(t, get)
+	require.NotNil(t, status)
+
+	assert.Equal(t, "/tenants/:tenant/users", list.Path)
+	assert.Equal(t, "/tenants/:tenant/users/:id", get.Path)
+	assert.Equal(t, []PathParamConstraint{{Name: "tenant", Type: "int", NotFound: true}}, list.PathParams)
+	assert.Equal(t, &ControllerInfo{Type: "UserController", Prefix: "/tenants/{tenant:int}/users", Constructor: "NewUserController"}, get.Controller)
+	assert.Len(t, get.MiddlewareCalls, 2, "typed prefix parameters are checked like the path's own")
+
+	assert.Equal(t, "/status", status.Path)
+	assert.Equal(t, &ControllerInfo{Type: "StatusController"}, status.Controller)
+
+	// An invalid @Controller is reported at its type
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.go"), []byte("package handlers\n\n// @Controller(\"users\")\ntype BadController struct{}\n"), 0o600))
+	_, err = ParseDirectory(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must start with '/'")
+}
+
+func TestGenerateInitFile_Controller(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	dir := writeControllerSources(t)
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig()))
+
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	code := string(generated)
+	assert.Contains(t, code, "handlersUserController := NewUserController()")
+	assert.Contains(t, code, "handlersStatusController := &StatusController{}")
+	assert.Contains(t, code, "Handler:     handlersUserController.Get,")
+	assert.Contains(t, code, "Handler:     handlersStatusController.Status,")
+	assert.Contains(t, code, `FuncName:    "UserController.List",`)
+	assert.Contains(t, code, `OperationID: "UserControllerList",`)
+	_, err = parser.ParseFile(token.NewFileSet(), outputPath, generated, 0)
+	assert.NoError(t, err)
+
+	// Handlers of another package are qualified by it
+	data := &GenData{PackageName: DefaultPackageName, Routes: []*RouteMeta{{FuncName: "UserController.Get", PackageName: "handlers", Controller: &ControllerInfo{Type: "UserController"}}}}
+	assert.Equal(t, []ControllerInstance{{Var: "handlersUserController", Value: "&handlers.UserController{}"}}, data.Controllers())
+	assert.Equal(t, "handlersUserController.Get", data.Handler(data.Routes[0]))
+}
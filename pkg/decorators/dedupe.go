@@ -0,0 +1,189 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DedupeHeader response header set on replayed deliveries
+const DedupeHeader = "X-Deco-Dedupe"
+
+// defaultDedupeTTL how long a delivery is remembered when @Dedupe has no ttl
+const defaultDedupeTTL = 24 * time.Hour
+
+// dedupeMemorySize entries kept by the in-memory dedupe store
+const dedupeMemorySize = 10000
+
+// DedupeConfig configuration of @Dedupe
+type DedupeConfig struct {
+	Source string        // "header", "query" or "param"
+	Name   string        // header, query parameter or path parameter holding the delivery id
+	TTL    time.Duration // how long outcomes are replayed
+	Store  string        // "memory" (default) or "redis"
+}
+
+// parseDedupeArgs parses @Dedupe(key="header:X-Event-ID", ttl="48h", store="redis")
+func parseDedupeArgs(args []string) (DedupeConfig, error) {
+	config := DedupeConfig{TTL: defaultDedupeTTL, Store: "memory"}
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			return config, fmt.Errorf("@Dedupe: expected key=value, found '%s'", arg)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "key":
+			source, name, found := strings.Cut(value, ":")
+			if !found || name == "" {
+				return config, fmt.Errorf("@Dedupe: invalid key '%s', expected header:Name, query:name or param:name", value)
+			}
+			switch source {
+			case "header", "query", "param":
+			default:
+				return config, fmt.Errorf("@Dedupe: invalid key source '%s' (valid: header, query, param)", source)
+			}
+			config.Source, config.Name = source, name
+		case "ttl":
+			ttl, err := ParseDurationLiteral(value)
+			if err != nil || ttl <= 0 {
+				return config, fmt.Errorf("@Dedupe: invalid ttl '%s'", value)
+			}
+			config.TTL = ttl
+		case "store":
+			if value != "memory" && value != "redis" {
+				return config, fmt.Errorf("@Dedupe: invalid store '%s' (valid: memory, redis)", value)
+			}
+			config.Store = value
+		default:
+			return config, fmt.Errorf("@Dedupe: unknown argument '%s' (valid: key, ttl, store)", key)
+		}
+	}
+
+	if config.Source == "" {
+		return config, fmt.Errorf("@Dedupe requires a key, e.g. key=\"header:X-Event-ID\"")
+	}
+	return config, nil
+}
+
+// deliveryID extracts the delivery id of a request
+func (d DedupeConfig) deliveryID(c *gin.Context) string {
+	switch d.Source {
+	case "header":
+		return c.GetHeader(d.Name)
+	case "query":
+		return c.Query(d.Name)
+	case "param":
+		return c.Param(d.Name)
+	}
+	return ""
+}
+
+// DedupeMiddleware processes each delivery once: duplicates within the TTL get the original
+// status and body replayed without running the handler. Requests without a delivery id pass
+// through; failed deliveries (5xx) are forgotten so the sender's retry is processed again.
+func DedupeMiddleware(config DedupeConfig, store CacheStore) gin.HandlerFunc {
+	if config.TTL <= 0 {
+		config.TTL = defaultDedupeTTL
+	}
+	var inFlight sync.Map
+
+	return func(c *gin.Context) {
+		id := config.deliveryID(c)
+		if id == "" {
+			c.Next()
+			return
+		}
+
+		key := "dedupe:" + generateCacheKeyHash(c.Request.Method+" "+getEndpointPattern(c)+" "+id)
+		ctx := c.Request.Context()
+
+		// Concurrent duplicates in this instance are rejected until the first one finishes
+		if _, busy := inFlight.LoadOrStore(key, struct{}{}); busy {
+			rejectInFlightDelivery(c)
+			return
+		}
+		defer inFlight.Delete(key)
+
+		if entry, err := store.Get(ctx, key); err == nil && entry != nil {
+			if entry.Status == 0 {
+				// Still being processed by another instance
+				rejectInFlightDelivery(c)
+				return
+			}
+			c.Header(DedupeHeader, "replay")
+			for name, value := range entry.Headers {
+				c.Header(name, value)
+			}
+			c.Data(entry.Status, entry.Headers["Content-Type"], entry.Data)
+			c.Abort()
+			return
+		}
+
+		// Claim the delivery for other instances sharing the store
+		pending := &CacheEntry{ExpiresAt: time.Now().Add(config.TTL)}
+		if err := store.Set(ctx, key, pending, config.TTL); err != nil {
+			LogSilent("⚠️  @Dedupe: could not record delivery: %v", err)
+		}
+
+		capture := CaptureBodies(c)
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			_ = store.Delete(ctx, key)
+			return
+		}
+
+		outcome := &CacheEntry{
+			Status:    status,
+			Headers:   map[string]string{"Content-Type": c.Writer.Header().Get("Content-Type")},
+			ExpiresAt: time.Now().Add(config.TTL),
+		}
+		// Bodies over the capture limit are replayed empty rather than truncated
+		if _, truncated := capture.ResponseSize(); !truncated {
+			outcome.Data = capture.ResponseBody()
+		}
+		if err := store.Set(ctx, key, outcome, config.TTL); err != nil {
+			LogSilent("⚠️  @Dedupe: could not record outcome: %v", err)
+		}
+	}
+}
+
+// rejectInFlightDelivery answers a duplicate of a delivery that is still being processed
+func rejectInFlightDelivery(c *gin.Context) {
+	c.Header(DedupeHeader, "in-flight")
+	c.Header("Retry-After", "1")
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+		"error":   "duplicate_delivery",
+		"message": "This delivery is already being processed",
+	})
+}
+
+// newDedupeStore creates the store of a @Dedupe route, falling back to memory when Redis is unavailable
+func newDedupeStore(config DedupeConfig) CacheStore {
+	if config.Store == "redis" {
+		store, err := NewRedisCache(GetRedisConfig(), "gin_decorators:")
+		if err == nil {
+			return store
+		}
+		LogSilent("⚠️  @Dedupe: Redis unavailable (%v), using memory store", err)
+	}
+	return NewMemoryCache(dedupeMemorySize)
+}
+
+// createDedupeMiddleware creates @Dedupe middleware
+func createDedupeMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseDedupeArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return DedupeMiddleware(config, newDedupeStore(config))
+}
@@ -0,0 +1,126 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDedupeArgs(t *testing.T) {
+	config, err := parseDedupeArgs([]string{`key="header:X-Event-ID"`, `ttl="48h"`})
+	assert.NoError(t, err)
+	assert.Equal(t, "header", config.Source)
+	assert.Equal(t, "X-Event-ID", config.Name)
+	assert.Equal(t, 48*time.Hour, config.TTL)
+	assert.Equal(t, "memory", config.Store)
+
+	config, err = parseDedupeArgs([]string{"key=param:id"})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultDedupeTTL, config.TTL)
+
+	for _, args := range [][]string{
+		nil,
+		{"key=X-Event-ID"},
+		{"key=cookie:id"},
+		{"key=header:X-Event-ID", "ttl=soon"},
+		{"key=header:X-Event-ID", "store=disk"},
+		{"key=header:X-Event-ID", "window=1h"},
+	} {
+		_, err := parseDedupeArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func newDedupeRouter(status *int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	config := DedupeConfig{Source: "header", Name: "X-Event-ID", TTL: time.Hour}
+	router.POST("/webhooks", DedupeMiddleware(config, NewMemoryCache(100)), func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"processed": *calls})
+	})
+	return router
+}
+
+func deliver(router *gin.Engine, eventID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", nil)
+	if eventID != "" {
+		req.Header.Set("X-Event-ID", eventID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDedupeMiddleware_ReplaysDuplicates(t *testing.T) {
+	status, calls := http.StatusAccepted, 0
+	router := newDedupeRouter(&status, &calls)
+
+	first := deliver(router, "evt-1")
+	assert.Equal(t, http.StatusAccepted, first.Code)
+	assert.Empty(t, first.Header().Get(DedupeHeader))
+
+	replay := deliver(router, "evt-1")
+	assert.Equal(t, http.StatusAccepted, replay.Code)
+	assert.Equal(t, "replay", replay.Header().Get(DedupeHeader))
+	assert.JSONEq(t, first.Body.String(), replay.Body.String())
+	assert.Contains(t, replay.Header().Get("Content-Type"), "application/json")
+	assert.Equal(t, 1, calls)
+
+	deliver(router, "evt-2")
+	assert.Equal(t, 2, calls)
+
+	// Requests without a delivery id are never deduplicated
+	deliver(router, "")
+	deliver(router, "")
+	assert.Equal(t, 4, calls)
+}
+
+func TestDedupeMiddleware_ReprocessesFailures(t *testing.T) {
+	status, calls := http.StatusServiceUnavailable, 0
+	router := newDedupeRouter(&status, &calls)
+
+	assert.Equal(t, http.StatusServiceUnavailable, deliver(router, "evt-1").Code)
+
+	status = http.StatusOK
+	retry := deliver(router, "evt-1")
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.Empty(t, retry.Header().Get(DedupeHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestDedupeMiddleware_RejectsInFlightDuplicates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := NewMemoryCache(100)
+	config := DedupeConfig{Source: "query", Name: "delivery", TTL: time.Hour}
+
+	router := gin.New()
+	router.POST("/webhooks", DedupeMiddleware(config, store), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// A pending claim left by another instance sharing the store
+	key := "dedupe:" + generateCacheKeyHash("POST /webhooks d-1")
+	req := httptest.NewRequest(http.MethodPost, "/webhooks?delivery=d-1", nil)
+	assert.NoError(t, store.Set(req.Context(), key, &CacheEntry{ExpiresAt: time.Now().Add(time.Hour)}, time.Hour))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "in-flight", w.Header().Get(DedupeHeader))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestDedupeMarker(t *testing.T) {
+	_, exists := GetMarkers()["Dedupe"]
+	assert.True(t, exists)
+
+	call := generateMiddlewareCall(MarkerInstance{Name: "Dedupe", Args: []string{"key=header:X-Event-ID", "ttl=48h"}})
+	assert.Equal(t, `deco.CreateDedupeMiddleware("key=header:X-Event-ID,ttl=48h")`, call)
+
+	assert.Error(t, validateArgumentValues("Dedupe", []string{"ttl=48h"}))
+}
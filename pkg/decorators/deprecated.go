@@ -0,0 +1,119 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationInfo deprecation of a route declared with @Deprecated
+type DeprecationInfo struct {
+	Message string `json:"message,omitempty"` // "use /v2/users"
+	Sunset  string `json:"sunset,omitempty"`  // date the route goes away, YYYY-MM-DD or RFC 3339
+	Link    string `json:"link,omitempty"`    // replacement route, sent as Link rel="successor-version"
+	Headers bool   `json:"headers"`           // emits Deprecation/Sunset/Link at runtime
+}
+
+// parseDeprecatedArgs parses @Deprecated("use /v2/users", sunset=2027-01-31, link=/v2/users, headers=false).
+// Without link=, the first path or URL of the message is the replacement.
+func parseDeprecatedArgs(args []string) (*DeprecationInfo, error) {
+	info := &DeprecationInfo{Headers: true}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		key, value, found := strings.Cut(arg, "=")
+		if !found || strings.ContainsAny(strings.TrimSpace(key), " \"'") {
+			if info.Message != "" {
+				return nil, fmt.Errorf("@Deprecated: unexpected argument '%s'", arg)
+			}
+			info.Message = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "sunset":
+			if _, err := parseSunsetDate(value); err != nil {
+				return nil, fmt.Errorf("@Deprecated: invalid sunset '%s', use YYYY-MM-DD or RFC 3339", value)
+			}
+			info.Sunset = value
+		case "link":
+			info.Link = value
+		case "headers":
+			info.Headers = value != "false"
+		default:
+			return nil, fmt.Errorf("@Deprecated: unknown argument '%s' (valid: sunset, link, headers)", key)
+		}
+	}
+
+	if info.Link == "" {
+		info.Link = replacementLink(info.Message)
+	}
+	return info, nil
+}
+
+// parseSunsetDate parses the sunset date of @Deprecated
+func parseSunsetDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// replacementLink first path or URL of a deprecation message, "" when it names none
+func replacementLink(message string) string {
+	for _, word := range strings.Fields(message) {
+		word = strings.TrimRight(word, ".,;:)")
+		if strings.HasPrefix(word, "/") || strings.HasPrefix(word, "http://") || strings.HasPrefix(word, "https://") {
+			return word
+		}
+	}
+	return ""
+}
+
+// notice human-readable deprecation, appended to the operation description
+func (d *DeprecationInfo) notice() string {
+	notice := "Deprecated"
+	if d.Message != "" {
+		notice += ": " + strings.TrimSuffix(d.Message, ".")
+	}
+	if d.Sunset != "" {
+		notice += fmt.Sprintf(" (removed on %s)", d.Sunset)
+	}
+	return notice + "."
+}
+
+// DeprecatedMiddleware marks the responses of a deprecated route: Deprecation: true, Sunset (RFC 8594)
+// and a Link to the replacement route
+func DeprecatedMiddleware(info DeprecationInfo) gin.HandlerFunc {
+	var sunset string
+	if date, err := parseSunsetDate(info.Sunset); err == nil {
+		sunset = date.UTC().Format(http.TimeFormat)
+	}
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", "true")
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		if info.Link != "" {
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, info.Link))
+		}
+		c.Next()
+	}
+}
+
+// createDeprecatedMiddleware creates @Deprecated middleware
+func createDeprecatedMiddleware(args []string) gin.HandlerFunc {
+	info, err := parseDeprecatedArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return DeprecatedMiddleware(*info)
+}
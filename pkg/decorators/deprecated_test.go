@@ -0,0 +1,115 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeprecatedArgs(t *testing.T) {
+	info, err := parseDeprecatedArgs([]string{`"use /v2/users."`, "sunset=2027-01-31"})
+	require.NoError(t, err)
+	assert.Equal(t, &DeprecationInfo{Message: "use /v2/users.", Sunset: "2027-01-31", Link: "/v2/users", Headers: true}, info)
+	assert.Equal(t, "Deprecated: use /v2/users (removed on 2027-01-31).", info.notice())
+
+	info, err = parseDeprecatedArgs([]string{`"see the migration guide"`, "link=https://docs.acme.com/v2", "headers=false"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://docs.acme.com/v2", info.Link)
+	assert.False(t, info.Headers)
+
+	info, err = parseDeprecatedArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Deprecated.", info.notice())
+
+	for _, args := range [][]string{{"sunset=next-year"}, {`"a"`, `"b"`}, {"since=2026-01-01"}} {
+		_, err := parseDeprecatedArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestDeprecatedMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/users", createDeprecatedMiddleware([]string{`"use /v2/users"`, "sunset=2027-01-31"}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sun, 31 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/users>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestDeprecatedMarker(t *testing.T) {
+	args, err := parseArgumentsWithValidation(`"use /v2/users", sunset=2027-01-31`, "Deprecated")
+	require.NoError(t, err)
+	_, err = parseArgumentsWithValidation(`"use /v2/users", sunset=soon`, "Deprecated")
+	assert.Error(t, err)
+
+	route := &RouteMeta{Method: "GET", Path: "/v1/users", Description: "Lists users.", Markers: []MarkerInstance{{Name: "Deprecated", Args: args}}}
+	require.NoError(t, processMiddlewares(route))
+	require.NotNil(t, route.Deprecation)
+	assert.Equal(t, []string{`deco.CreateDeprecatedMiddleware("use /v2/users,sunset=2027-01-31")`}, route.MiddlewareCalls)
+
+	entry := routeEntryFromMeta(route)
+	operation := convertRouteToOperation(&entry, &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{}})
+	assert.True(t, operation.Deprecated)
+	assert.Equal(t, "Lists users.\n\nDeprecated: use /v2/users (removed on 2027-01-31).", operation.Description)
+
+	// headers=false only documents the deprecation
+	route = &RouteMeta{Method: "GET", Path: "/v1/orders", Markers: []MarkerInstance{{Name: "Deprecated", Args: []string{"headers=false"}}}}
+	require.NoError(t, processMiddlewares(route))
+	assert.NotNil(t, route.Deprecation)
+	assert.Empty(t, route.MiddlewareCalls)
+}
+
+func TestDocsPage_DeprecatedBadge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registryMutex.Lock()
+	saved := routes
+	routes = []RouteEntry{{Method: "GET", Path: "/v1/users", FuncName: "ListUsersV1", Deprecation: &DeprecationInfo{Message: "use /v2/users"}}}
+	registryMutex.Unlock()
+	t.Cleanup(func() {
+		registryMutex.Lock()
+		routes = saved
+		registryMutex.Unlock()
+	})
+
+	router := gin.New()
+	router.GET("/docs", DocsPageHandler(DefaultConfig()))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Contains(t, w.Body.String(), `<span class="deprecated" title="use /v2/users">Deprecated</span>`)
+}
+
+func TestGenerateInitFile_Deprecated(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/v1/users")
+// @Deprecated("use /v2/users", sunset=2027-01-31)
+func ListUsersV1(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig()))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `Deprecation: &decorators.DeprecationInfo{Message: "use /v2/users", Sunset: "2027-01-31", Link: "/v2/users", Headers: true},`)
+	assert.Contains(t, string(generated), `deco.CreateDeprecatedMiddleware("use /v2/users,sunset=2027-01-31")`)
+}
@@ -0,0 +1,105 @@
+package decorators
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultDocsTitle product name shown by the docs pages without docs.branding.title
+const defaultDocsTitle = "gin-decorators"
+
+// cssColorRegex colors accepted by docs.branding.primary_color: hex, rgb()/hsl() or a named color
+var cssColorRegex = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|(rgb|hsl)a?\([0-9.,%\s]+\)|[a-zA-Z]+)$`)
+
+// validate checks the theme and the primary color, which are written into the page CSS
+func (b DocsBrandingConfig) validate() error {
+	switch b.Theme {
+	case "", "dark", "light", "auto":
+	default:
+		return fmt.Errorf("docs.branding.theme must be 'dark', 'light' or 'auto', found '%s'", b.Theme)
+	}
+	if b.PrimaryColor != "" && !cssColorRegex.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("docs.branding.primary_color: invalid CSS color '%s'", b.PrimaryColor)
+	}
+	return nil
+}
+
+// docsBranding branding resolved for the templates; the custom CSS file is read once
+type docsBranding struct {
+	Title        string
+	LogoURL      string
+	FaviconURL   string
+	PrimaryColor template.CSS
+	Theme        string
+	CustomCSS    template.CSS
+}
+
+// newDocsBranding resolves docs.branding, logging and skipping invalid values
+func newDocsBranding(config *Config) docsBranding {
+	branding := docsBranding{Title: defaultDocsTitle}
+	if config == nil {
+		return branding
+	}
+
+	settings := config.Docs.Branding
+	if err := settings.validate(); err != nil {
+		LogSilent("⚠️  %v", err)
+		settings.Theme, settings.PrimaryColor = "", ""
+	}
+
+	if settings.Title != "" {
+		branding.Title = settings.Title
+	}
+	branding.LogoURL = settings.LogoURL
+	branding.FaviconURL = settings.FaviconURL
+	branding.PrimaryColor = template.CSS(settings.PrimaryColor) // nolint:gosec // Safe: validated CSS color
+	branding.Theme = settings.Theme
+
+	if settings.CustomCSS != "" {
+		css, err := os.ReadFile(config.ResolvePath(settings.CustomCSS))
+		if err != nil {
+			LogSilent("⚠️  Error reading docs.branding.custom_css: %v", err)
+		} else {
+			branding.CustomCSS = template.CSS(css) // nolint:gosec // Safe: stylesheet provided by the application
+		}
+	}
+	return branding
+}
+
+// swaggerUIHead favicon and styles added to the Swagger UI page. Swagger UI has no dark theme,
+// so "dark" inverts the page colors, keeping images as they are.
+func (b docsBranding) swaggerUIHead() string {
+	var head strings.Builder
+	if b.FaviconURL != "" {
+		fmt.Fprintf(&head, "    <link rel=\"icon\" href=\"%s\" />\n", html.EscapeString(b.FaviconURL))
+	}
+
+	var css strings.Builder
+	if b.PrimaryColor != "" {
+		fmt.Fprintf(&css, "        .swagger-ui .topbar { background-color: %s; }\n", b.PrimaryColor)
+		fmt.Fprintf(&css, "        .swagger-ui .btn.execute, .swagger-ui .btn.authorize { border-color: %[1]s; }\n", b.PrimaryColor)
+	}
+	if b.LogoURL != "" {
+		fmt.Fprintf(&css, "        .swagger-ui .topbar-wrapper img { content: url(%q); }\n", b.LogoURL)
+	}
+	const darkCSS = "html { filter: invert(88%) hue-rotate(180deg); background: #fff; } " +
+		"img, .swagger-ui .topbar { filter: invert(100%) hue-rotate(180deg); }"
+	switch b.Theme {
+	case "dark":
+		css.WriteString("        " + darkCSS + "\n")
+	case "auto":
+		css.WriteString("        @media (prefers-color-scheme: dark) { " + darkCSS + " }\n")
+	}
+	if b.CustomCSS != "" {
+		css.WriteString(string(b.CustomCSS) + "\n")
+	}
+
+	if css.Len() > 0 {
+		head.WriteString("    <style>\n" + css.String() + "    </style>\n")
+	}
+	return head.String()
+}
@@ -0,0 +1,92 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsBrandingConfigValidate(t *testing.T) {
+	assert.NoError(t, DocsBrandingConfig{}.validate())
+	for _, color := range []string{"#0052cc", "#fff", "rgb(0, 82, 204)", "hsla(210, 100%, 40%, 0.9)", "navy"} {
+		assert.NoError(t, DocsBrandingConfig{Theme: "auto", PrimaryColor: color}.validate(), color)
+	}
+
+	assert.Error(t, DocsBrandingConfig{Theme: "solarized"}.validate())
+	for _, color := range []string{"#00f; } body { display: none", "url(x)", "red</style>"} {
+		assert.Error(t, DocsBrandingConfig{PrimaryColor: color}.validate(), color)
+	}
+}
+
+func brandingConfig(t *testing.T) *Config {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs.css"), []byte(".header { letter-spacing: 1px; }"), 0o600))
+
+	config := DefaultConfig()
+	config.baseDir = dir
+	config.Docs.Branding = DocsBrandingConfig{
+		Title:        "Acme API",
+		LogoURL:      "https://cdn.acme.test/logo.svg",
+		FaviconURL:   "https://cdn.acme.test/favicon.ico",
+		PrimaryColor: "#0052cc",
+		Theme:        "light",
+		CustomCSS:    "docs.css",
+	}
+	return config
+}
+
+func TestDocsPageHandler_Branding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/decorators/docs", DocsPageHandler(brandingConfig(t)))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/decorators/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `data-theme="light"`)
+	assert.Contains(t, body, "<title>Acme API - Route Documentation</title>")
+	assert.Contains(t, body, `<link rel="icon" href="https://cdn.acme.test/favicon.ico">`)
+	assert.Contains(t, body, `<img class="brand-logo" src="https://cdn.acme.test/logo.svg" alt="">Acme API</h1>`)
+	assert.Contains(t, body, "--mascot-blue: #0052cc;")
+	assert.Contains(t, body, ".header { letter-spacing: 1px; }")
+	assert.NotContains(t, body, "🎨 gin-decorators")
+
+	// Without branding the page keeps the dark theme and the framework name
+	router = gin.New()
+	router.GET("/decorators/docs", DocsHandler)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/decorators/docs", nil))
+	assert.Contains(t, w.Body.String(), `data-theme="dark"`)
+	assert.Contains(t, w.Body.String(), "🎨 gin-decorators")
+}
+
+func TestSwaggerUIHandler_Branding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	config := brandingConfig(t)
+	config.Docs.Branding.Theme = "dark"
+	router.GET("/swagger-ui", SwaggerUIHandler(config))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger-ui", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "<title>Acme API - API Documentation</title>")
+	assert.Contains(t, body, `<link rel="icon" href="https://cdn.acme.test/favicon.ico" />`)
+	assert.Contains(t, body, ".swagger-ui .topbar { background-color: #0052cc; }")
+	assert.Contains(t, body, `.swagger-ui .topbar-wrapper img { content: url("https://cdn.acme.test/logo.svg"); }`)
+	assert.Contains(t, body, "filter: invert(88%)")
+	assert.Contains(t, body, ".header { letter-spacing: 1px; }")
+	assert.Contains(t, body, "url: '/decorators/openapi.json'")
+
+	assert.Empty(t, newDocsBranding(nil).swaggerUIHead())
+}
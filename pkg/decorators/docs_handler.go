@@ -16,9 +16,38 @@ type DocsData struct {
 	TotalMiddlewares int
 }
 
-// DocsHandler serves the HTML documentation page
+// DocsHandler serves the HTML documentation page in the language of the request
 func DocsHandler(c *gin.Context) {
-	routes := GetRoutes()
+	renderDocsPage(c, newDocsLocalizer(c, DefaultDocsLocale, nil), newDocsBranding(nil), DefaultDocsBasePath+"/docs.json")
+}
+
+// DocsPageHandler serves the HTML documentation page with the docs configuration: the default
+// locale, the translations file and the branding, read once when the handler is created
+func DocsPageHandler(config *Config) gin.HandlerFunc {
+	var translations map[string]DocsTranslations
+	if config.Docs.Translations != "" {
+		loaded, err := LoadDocsTranslations(config.ResolvePath(config.Docs.Translations))
+		if err != nil {
+			LogSilent("⚠️  %v", err)
+		}
+		translations = loaded
+	}
+
+	branding := newDocsBranding(config)
+	jsonURL := ""
+	if docsEndpointEnabled(config.Docs.Endpoints.JSON) {
+		jsonURL = config.Docs.Path("docs.json")
+	}
+
+	return func(c *gin.Context) {
+		renderDocsPage(c, newDocsLocalizer(c, config.Docs.Locale, translations), branding, jsonURL)
+	}
+}
+
+// renderDocsPage renders the documentation page translated by the localizer, linking to the JSON
+// documentation at jsonURL when it is served
+func renderDocsPage(c *gin.Context, localizer *docsLocalizer, branding docsBranding, jsonURL string) {
+	routes := localizer.localizeRoutes(GetRoutes())
 	groups := GetGroups()
 
 	// Calculate statistics
@@ -82,6 +111,10 @@ func DocsHandler(c *gin.Context) {
 		UniqueMiddlewares int
 		TotalWebSockets   int
 		TotalProxies      int
+		Lang              string
+		Languages         []docsLanguage
+		Branding          docsBranding
+		JSONURL           string
 	}{
 		Routes:            routes,
 		RoutesByTag:       routesByTag,
@@ -95,18 +128,24 @@ func DocsHandler(c *gin.Context) {
 		UniqueMiddlewares: len(uniqueMiddlewares),
 		TotalWebSockets:   totalWebSockets,
 		TotalProxies:      totalProxies,
+		Lang:              localizer.locale,
+		Languages:         localizer.languages(),
+		Branding:          branding,
+		JSONURL:           jsonURL,
 	}
 
 	htmlTemplate := `
 <!DOCTYPE html>
-<html lang="pt-BR">
+<html lang="{{.Lang}}" data-theme="{{or .Branding.Theme "dark"}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>gin-decorators - Route Documentation</title>
+    <title>{{.Branding.Title}} - {{t "title"}}</title>
+    {{if .Branding.FaviconURL}}<link rel="icon" href="{{.Branding.FaviconURL}}">{{end}}
     <style>
         :root {
             --mascot-blue: #40B0C0;
+            --mascot-blue-dark: #2a8a9a;
             --mascot-cream: #F5E5C0;
             --mascot-green: #66CC33;
             --mascot-brown: #A0522D;
@@ -119,6 +158,28 @@ func DocsHandler(c *gin.Context) {
             --text-muted: #808080;
         }
 
+        :root[data-theme="light"] {
+            --dark-bg: #f4f6f8;
+            --dark-surface: #ffffff;
+            --dark-surface-hover: #eef1f4;
+            --dark-border: #d9dee3;
+            --text-primary: #1a1a1a;
+            --text-secondary: #4a4a4a;
+            --text-muted: #777777;
+        }
+
+        @media (prefers-color-scheme: light) {
+            :root[data-theme="auto"] {
+                --dark-bg: #f4f6f8;
+                --dark-surface: #ffffff;
+                --dark-surface-hover: #eef1f4;
+                --dark-border: #d9dee3;
+                --text-primary: #1a1a1a;
+                --text-secondary: #4a4a4a;
+                --text-muted: #777777;
+            }
+        }
+
         * {
             box-sizing: border-box;
         }
@@ -149,7 +210,7 @@ func DocsHandler(c *gin.Context) {
         }
 
         .header {
-            background: linear-gradient(135deg, var(--mascot-blue) 0%, #2a8a9a 100%);
+            background: linear-gradient(135deg, var(--mascot-blue) 0%, var(--mascot-blue-dark) 100%);
             color: white;
             padding: 40px 30px;
             text-align: center;
@@ -182,6 +243,12 @@ func DocsHandler(c *gin.Context) {
             z-index: 1;
         }
 
+        .header h1 .brand-logo {
+            height: 1em;
+            vertical-align: middle;
+            margin-right: 12px;
+        }
+
         .header p {
             margin: 10px 0 0 0;
             opacity: 0.95;
@@ -401,7 +468,7 @@ func DocsHandler(c *gin.Context) {
         .method-PUT { background: linear-gradient(135deg, #FF9800, #F57C00); color: white; }
         .method-DELETE { background: linear-gradient(135deg, #F44336, #D32F2F); color: white; }
         .method-PATCH { background: linear-gradient(135deg, #9C27B0, #7B1FA2); color: white; }
-        .method-WS { background: linear-gradient(135deg, var(--mascot-blue), #2a8a9a); color: white; }
+        .method-WS { background: linear-gradient(135deg, var(--mascot-blue), var(--mascot-blue-dark)); color: white; }
 
         .path {
             font-family: 'Monaco', 'Menlo', 'Consolas', monospace;
@@ -424,13 +491,23 @@ func DocsHandler(c *gin.Context) {
             border-radius: 6px;
         }
 
+        .deprecated {
+            background: #F44336;
+            color: white;
+            padding: 4px 10px;
+            border-radius: 6px;
+            font-size: 0.75rem;
+            font-weight: 700;
+            text-transform: uppercase;
+        }
+
         .route-tags {
             margin-bottom: 15px;
         }
 
         .tag {
             display: inline-block;
-            background: linear-gradient(135deg, var(--mascot-blue), #2a8a9a);
+            background: linear-gradient(135deg, var(--mascot-blue), var(--mascot-blue-dark));
             color: white;
             padding: 4px 12px;
             border-radius: 20px;
@@ -553,6 +630,41 @@ func DocsHandler(c *gin.Context) {
             border-left: 4px solid var(--mascot-cream);
         }
 
+        .description.markdown {
+            font-style: normal;
+        }
+
+        .description.markdown p,
+        .description.markdown ul,
+        .description.markdown ol {
+            margin: 0 0 8px;
+        }
+
+        .description.markdown h4,
+        .description.markdown h5,
+        .description.markdown h6 {
+            color: var(--text-primary);
+            margin: 12px 0 6px;
+        }
+
+        .description.markdown code {
+            font-family: 'Monaco', 'Menlo', 'Consolas', monospace;
+            font-size: 0.85rem;
+        }
+
+        .description.markdown pre {
+            background: var(--dark-bg);
+            padding: 12px;
+            border-radius: 6px;
+            overflow-x: auto;
+            margin: 8px 0;
+        }
+
+        .tok-keyword { color: #c678dd; }
+        .tok-string { color: #98c379; }
+        .tok-number { color: #d19a66; }
+        .tok-comment { color: var(--text-muted); font-style: italic; }
+
         .empty-state {
             text-align: center;
             padding: 80px;
@@ -564,11 +676,28 @@ func DocsHandler(c *gin.Context) {
             margin-bottom: 10px;
         }
 
+        .language-switcher {
+            position: absolute;
+            top: 15px;
+            right: 20px;
+            z-index: 1;
+            font-size: 0.85em;
+        }
+
+        .language-switcher select {
+            margin-left: 6px;
+            padding: 4px 8px;
+            border-radius: 6px;
+            border: 1px solid rgba(255, 255, 255, 0.4);
+            background: rgba(0, 0, 0, 0.2);
+            color: white;
+        }
+
         .json-link {
             position: fixed;
             bottom: 80px;
             right: 80px;
-            background: linear-gradient(135deg, var(--mascot-blue), #2a8a9a);
+            background: linear-gradient(135deg, var(--mascot-blue), var(--mascot-blue-dark));
             color: white;
             padding: 15px 25px;
             border-radius: 30px;
@@ -646,48 +775,69 @@ func DocsHandler(c *gin.Context) {
             background: var(--mascot-green);
         }
     </style>
+    {{if .Branding.PrimaryColor}}
+    <style>
+        :root {
+            --mascot-blue: {{.Branding.PrimaryColor}};
+            --mascot-blue-dark: color-mix(in srgb, {{.Branding.PrimaryColor}} 75%, black);
+        }
+    </style>
+    {{end}}
+    {{if .Branding.CustomCSS}}
+    <style>
+{{.Branding.CustomCSS}}
+    </style>
+    {{end}}
 </head>
 <body>
     <div class="container">
         <div class="header">
-            <h1>🎨 gin-decorators</h1>
-            <p>Automatic documentation of routes</p>
+            <h1>{{if .Branding.LogoURL}}<img class="brand-logo" src="{{.Branding.LogoURL}}" alt="">{{else}}🎨 {{end}}{{.Branding.Title}}</h1>
+            <p>{{t "subtitle"}}</p>
+            <div class="language-switcher">
+                <label for="docs-language">{{t "language"}}</label>
+                <select id="docs-language" onchange="switchLanguage(this.value)">
+                    {{range .Languages}}
+                    <option value="{{.Code}}"{{if eq .Code $.Lang}} selected{{end}}>{{.Name}}</option>
+                    {{end}}
+                </select>
+            </div>
         </div>
         
         <div class="stats">
             <div class="stat">
                 <div class="stat-number">{{.TotalRoutes}}</div>
-                <div class="stat-label">Routes registradas</div>
+                <div class="stat-label">{{t "stat_routes"}}</div>
             </div>
             <div class="stat">
                 <div class="stat-number">{{.UniqueMethods}}</div>
-                <div class="stat-label">Unique methods</div>
+                <div class="stat-label">{{t "stat_methods"}}</div>
             </div>
             <div class="stat">
                 <div class="stat-number">{{.TotalMiddlewares}}</div>
-                <div class="stat-label">Applied middlewares</div>
+                <div class="stat-label">{{t "stat_middlewares"}}</div>
             </div>
             <div class="stat">
                 <div class="stat-number">{{.UniqueMiddlewares}}</div>
-                <div class="stat-label">Middleware types</div>
+                <div class="stat-label">{{t "stat_middleware_types"}}</div>
             </div>
             <div class="stat">
                 <div class="stat-number">{{.TotalWebSockets}}</div>
-                <div class="stat-label">WebSocket handlers</div>
+                <div class="stat-label">{{t "stat_websockets"}}</div>
             </div>
             <div class="stat">
                 <div class="stat-number">{{.TotalProxies}}</div>
-                <div class="stat-label">Proxies processed</div>
+                <div class="stat-label">{{t "stat_proxies"}}</div>
             </div>
         </div>
         
         <div class="view-controls">
-            <button class="view-toggle active" onclick="switchView('tags')">🏷️ Por Tags</button>
-            <button class="view-toggle" onclick="switchView('groups')">📁 Por Grupos</button>
-            <button class="view-toggle" onclick="switchView('all')">📄 Todas as Rotas</button>
+            <button class="view-toggle active" onclick="switchView('tags')">🏷️ {{t "view_tags"}}</button>
+            <button class="view-toggle" onclick="switchView('groups')">📁 {{t "view_groups"}}</button>
+            <button class="view-toggle" onclick="switchView('all')">📄 {{t "view_all"}}</button>
             <div style="margin-left: auto;">
-                <button class="view-toggle" onclick="expandAll()" style="background: var(--mascot-green);">🔽 Expandir Tudo</button>
-                <button class="view-toggle" onclick="collapseAll()" style="background: var(--mascot-brown);">🔼 Colapsar Tudo</button>
+                <button class="view-toggle" onclick="expandAll()" style="background: var(--mascot-green);">🔽 {{t "expand_all"}}</button>
+                <button class="view-toggle" onclick="collapseAll()" style="background: var(--mascot-brown);">🔼 {{t "collapse_all"}}</button>
             </div>
         </div>
         
@@ -697,7 +847,7 @@ func DocsHandler(c *gin.Context) {
                 {{range $tag, $routes := .RoutesByTag}}
                 <div class="collapse-section">
                     <div class="collapse-header" onclick="toggleCollapse('tag-{{$tag}}')">
-                        <h3>🏷️ {{$tag}} ({{len $routes}} rotas)</h3>
+                        <h3>🏷️ {{$tag}} ({{len $routes}} {{t "routes"}})</h3>
                         <span class="collapse-icon" id="icon-tag-{{$tag}}">▼</span>
                     </div>
                     <div class="collapse-content" id="content-tag-{{$tag}}">
@@ -706,8 +856,9 @@ func DocsHandler(c *gin.Context) {
                             <div class="route">
                                 <div class="route-header">
                                     <span class="method method-{{.Method}}">{{.Method}}</span>
-                                    <span class="path">{{.Path}}</span>
+                                    <span class="path">{{path .Path}}</span>
                                     <span class="handler">{{.FuncName}}</span>
+                                    {{if .Deprecation}}<span class="deprecated" title="{{.Deprecation.Message}}">{{t "deprecated"}}</span>{{end}}
                                 </div>
                                 
                                 {{if .Tags}}
@@ -719,7 +870,7 @@ func DocsHandler(c *gin.Context) {
                                 {{end}}
                                 
                                 {{if .Description}}
-                                <div class="description">{{.Description}}</div>
+                                <div class="description markdown">{{markdown .Description}}</div>
                                 {{end}}
                                 
                                 {{if .MiddlewareInfo}}
@@ -759,7 +910,7 @@ func DocsHandler(c *gin.Context) {
             {{if .UntaggedRoutes}}
             <div class="collapse-section">
                 <div class="collapse-header" onclick="toggleCollapse('untagged')">
-                    <h3>🏷️ Sem Tags ({{len .UntaggedRoutes}} rotas)</h3>
+                    <h3>🏷️ {{t "untagged"}} ({{len .UntaggedRoutes}} {{t "routes"}})</h3>
                     <span class="collapse-icon" id="icon-untagged">▼</span>
                 </div>
                 <div class="collapse-content" id="content-untagged">
@@ -768,12 +919,13 @@ func DocsHandler(c *gin.Context) {
                         <div class="route">
                             <div class="route-header">
                                 <span class="method method-{{.Method}}">{{.Method}}</span>
-                                <span class="path">{{.Path}}</span>
+                                <span class="path">{{path .Path}}</span>
                                 <span class="handler">{{.FuncName}}</span>
+                                {{if .Deprecation}}<span class="deprecated" title="{{.Deprecation.Message}}">{{t "deprecated"}}</span>{{end}}
                             </div>
                             
                             {{if .Description}}
-                            <div class="description">{{.Description}}</div>
+                            <div class="description markdown">{{markdown .Description}}</div>
                             {{end}}
                             
                             {{if .MiddlewareInfo}}
@@ -816,7 +968,7 @@ func DocsHandler(c *gin.Context) {
                 {{range $group, $routes := .RoutesByGroup}}
                 <div class="collapse-section">
                     <div class="collapse-header" onclick="toggleCollapse('group-{{$group}}')">
-                        <h3>📁 {{$group}} ({{len $routes}} rotas)</h3>
+                        <h3>📁 {{$group}} ({{len $routes}} {{t "routes"}})</h3>
                         <span class="collapse-icon" id="icon-group-{{$group}}">▼</span>
                     </div>
                     <div class="collapse-content" id="content-group-{{$group}}">
@@ -825,8 +977,9 @@ func DocsHandler(c *gin.Context) {
                             <div class="route">
                                 <div class="route-header">
                                     <span class="method method-{{.Method}}">{{.Method}}</span>
-                                    <span class="path">{{.Path}}</span>
+                                    <span class="path">{{path .Path}}</span>
                                     <span class="handler">{{.FuncName}}</span>
+                                    {{if .Deprecation}}<span class="deprecated" title="{{.Deprecation.Message}}">{{t "deprecated"}}</span>{{end}}
                                 </div>
                                 
                                 {{if .Tags}}
@@ -838,7 +991,7 @@ func DocsHandler(c *gin.Context) {
                                 {{end}}
                                 
                                 {{if .Description}}
-                                <div class="description">{{.Description}}</div>
+                                <div class="description markdown">{{markdown .Description}}</div>
                                 {{end}}
                                 
                                 {{if .MiddlewareInfo}}
@@ -878,7 +1031,7 @@ func DocsHandler(c *gin.Context) {
             {{if .UngroupedRoutes}}
             <div class="collapse-section">
                 <div class="collapse-header" onclick="toggleCollapse('ungrouped')">
-                    <h3>📁 Sem Grupo ({{len .UngroupedRoutes}} rotas)</h3>
+                    <h3>📁 {{t "ungrouped"}} ({{len .UngroupedRoutes}} {{t "routes"}})</h3>
                     <span class="collapse-icon" id="icon-ungrouped">▼</span>
                 </div>
                 <div class="collapse-content" id="content-ungrouped">
@@ -887,8 +1040,9 @@ func DocsHandler(c *gin.Context) {
                         <div class="route">
                             <div class="route-header">
                                 <span class="method method-{{.Method}}">{{.Method}}</span>
-                                <span class="path">{{.Path}}</span>
+                                <span class="path">{{path .Path}}</span>
                                 <span class="handler">{{.FuncName}}</span>
+                                {{if .Deprecation}}<span class="deprecated" title="{{.Deprecation.Message}}">{{t "deprecated"}}</span>{{end}}
                             </div>
                             
                             {{if .Tags}}
@@ -900,7 +1054,7 @@ func DocsHandler(c *gin.Context) {
                             {{end}}
                             
                             {{if .Description}}
-                            <div class="description">{{.Description}}</div>
+                            <div class="description markdown">{{markdown .Description}}</div>
                             {{end}}
                             
                             {{if .MiddlewareInfo}}
@@ -945,8 +1099,9 @@ func DocsHandler(c *gin.Context) {
                     <div class="route">
                         <div class="route-header">
                             <span class="method method-{{.Method}}">{{.Method}}</span>
-                            <span class="path">{{.Path}}</span>
+                            <span class="path">{{path .Path}}</span>
                             <span class="handler">{{.FuncName}}</span>
+                            {{if .Deprecation}}<span class="deprecated" title="{{.Deprecation.Message}}">{{t "deprecated"}}</span>{{end}}
                         </div>
                         
                         {{if .Tags}}
@@ -958,7 +1113,7 @@ func DocsHandler(c *gin.Context) {
                         {{end}}
                         
                         {{if .Description}}
-                        <div class="description">{{.Description}}</div>
+                        <div class="description markdown">{{markdown .Description}}</div>
                         {{end}}
                         
                         {{if .MiddlewareInfo}}
@@ -991,17 +1146,24 @@ func DocsHandler(c *gin.Context) {
                     {{end}}
                 {{else}}
                     <div class="empty-state">
-                        <h3>No registered routes</h3>
-                        <p>Add @Route annotations to your handlers to see them here.</p>
+                        <h3>{{t "empty_title"}}</h3>
+                        <p>{{t "empty_hint"}}</p>
                     </div>
                 {{end}}
             </div>
         </div>
     </div>
     
-    <a href="/decorators/docs.json" class="json-link">📄 JSON</a>
+    {{if .JSONURL}}<a href="{{.JSONURL}}" class="json-link">📄 JSON</a>{{end}}
     
     <script>
+        // Reload the page in another language
+        function switchLanguage(lang) {
+            const params = new URLSearchParams(window.location.search);
+            params.set('lang', lang);
+            window.location.search = params.toString();
+        }
+
         // Toggle collapse functionality
         function toggleCollapse(id) {
             const content = document.getElementById('content-' + id);
@@ -1079,7 +1241,10 @@ func DocsHandler(c *gin.Context) {
 `
 
 	tmpl, err := template.New("docs").Funcs(template.FuncMap{
-		"lower": strings.ToLower,
+		"lower":    strings.ToLower,
+		"t":        localizer.T,
+		"markdown": renderMarkdown,
+		"path":     displayPath,
 	}).Parse(htmlTemplate)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Error processing template"})
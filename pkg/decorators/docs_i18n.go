@@ -0,0 +1,290 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDocsLocale language of the docs page when neither the request nor the config choose one
+const DefaultDocsLocale = "en"
+
+// RouteTranslation summary and description of a route in another language
+type RouteTranslation struct {
+	Summary     string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// DocsTranslations texts of one locale loaded from the docs.translations file:
+//
+//	pt-BR:
+//	  ui:
+//	    subtitle: "Documentação da API de pedidos"
+//	  routes:
+//	    "GET /orders/:id":
+//	      summary: "Buscar pedido"
+type DocsTranslations struct {
+	UI     map[string]string           `yaml:"ui,omitempty"`     // overrides the built-in UI strings
+	Routes map[string]RouteTranslation `yaml:"routes,omitempty"` // keyed by "METHOD path"
+}
+
+// docsLocales built-in UI strings of the docs page; missing keys fall back to DefaultDocsLocale
+var docsLocales = map[string]map[string]string{
+	"en": {
+		"language_name":         "English",
+		"language":              "Language",
+		"title":                 "Route Documentation",
+		"subtitle":              "Automatic documentation of routes",
+		"stat_routes":           "Registered routes",
+		"stat_methods":          "Unique methods",
+		"stat_middlewares":      "Applied middlewares",
+		"stat_middleware_types": "Middleware types",
+		"stat_websockets":       "WebSocket handlers",
+		"stat_proxies":          "Proxies processed",
+		"view_tags":             "By Tags",
+		"view_groups":           "By Groups",
+		"view_all":              "All Routes",
+		"expand_all":            "Expand All",
+		"collapse_all":          "Collapse All",
+		"routes":                "routes",
+		"untagged":              "Untagged",
+		"ungrouped":             "Ungrouped",
+		"empty_title":           "No registered routes",
+		"empty_hint":            "Add @Route annotations to your handlers to see them here.",
+		"deprecated":            "Deprecated",
+
+		"middleware.Auth":            "Authentication and authorization middleware",
+		"middleware.Cache":           "Response cache middleware",
+		"middleware.RateLimit":       "Rate limiting middleware",
+		"middleware.Metrics":         "Metrics collection middleware",
+		"middleware.CORS":            "Cross-Origin Resource Sharing middleware",
+		"middleware.WebSocket":       "WebSocket connection upgrade middleware",
+		"middleware.WebSocketStats":  "WebSocket statistics middleware",
+		"middleware.Proxy":           "Reverse proxy middleware with service discovery and load balancing",
+		"middleware.MaxResponseSize": "Response size limit middleware",
+		"middleware.MaxBodySize":     "Request body size limit middleware",
+		"middleware.SlowThreshold":   "Slow request detection middleware",
+		"middleware.NoAccessLog":     "Removes the route from the access log",
+		"middleware.Mock":            "Mocked response (the handler is not executed)",
+		"middleware.Dedupe":          "Deduplicates repeated deliveries (webhooks)",
+		"middleware.SagaStep":        "Runs the route as a saga step",
+		"middleware.RequireHeader":   "Requires a request header, optionally with a format",
+		"middleware.FileParam":       "Validates a multipart/form-data file upload (size and type)",
+		"middleware.FieldACL":        "Strips response fields restricted by role (acl tag)",
+		"middleware.SSE":             "Streams the events of a channel as Server-Sent Events",
+		"middleware.CircuitBreaker":  "Route circuit breaker: answers 503 while the circuit is open",
+		"middleware.Deprecated":      "Deprecated route: sends Deprecation, Sunset and a Link to the replacement",
+		"middleware.PathParams":      "Checks the type and format of the path parameters",
+		"middleware.Transactional":   "Runs the route in a transaction: commit on 2xx, rollback on errors and panics",
+		"middleware.Compress":        "Compresses the response (gzip, brotli) as negotiated by Accept-Encoding",
+		"middleware.Log":             "Writes a structured log of the request (method, path, status, latency and redacted bodies)",
+		"middleware.Envelope":        "Wraps JSON responses as {\"data\", \"meta\", \"error\"}",
+	},
+	"pt-BR": {
+		"language_name":         "Português (Brasil)",
+		"language":              "Idioma",
+		"title":                 "Documentação de Rotas",
+		"subtitle":              "Documentação automática de rotas",
+		"stat_routes":           "Rotas registradas",
+		"stat_methods":          "Métodos únicos",
+		"stat_middlewares":      "Middlewares aplicados",
+		"stat_middleware_types": "Tipos de middleware",
+		"stat_websockets":       "Handlers WebSocket",
+		"stat_proxies":          "Proxies processados",
+		"view_tags":             "Por Tags",
+		"view_groups":           "Por Grupos",
+		"view_all":              "Todas as Rotas",
+		"expand_all":            "Expandir Tudo",
+		"collapse_all":          "Colapsar Tudo",
+		"routes":                "rotas",
+		"untagged":              "Sem Tags",
+		"ungrouped":             "Sem Grupo",
+		"empty_title":           "Nenhuma rota registrada",
+		"empty_hint":            "Adicione anotações @Route aos seus handlers para vê-las aqui.",
+		"deprecated":            "Depreciada",
+	},
+}
+
+// translationMarkerRegex extracts the field and locale of @Summary.pt-BR(...) / @Description.pt-BR(...)
+var translationMarkerRegex = regexp.MustCompile(`^@(Summary|Description)\.([A-Za-z]{2,3}(?:-[A-Za-z0-9]+)*)`)
+
+// LoadDocsTranslations reads the docs.translations file, keyed by locale
+func LoadDocsTranslations(path string) (map[string]DocsTranslations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading docs translations %s: %v", path, err)
+	}
+
+	var translations map[string]DocsTranslations
+	if err := yaml.Unmarshal(data, &translations); err != nil {
+		return nil, fmt.Errorf("error parsing docs translations %s: %v", path, err)
+	}
+	return translations, nil
+}
+
+// docsLocalizer texts of the docs page for the negotiated locale
+type docsLocalizer struct {
+	locale       string
+	translations map[string]DocsTranslations
+}
+
+// newDocsLocalizer negotiates the locale of a request: ?lang=, then Accept-Language, then the fallback
+func newDocsLocalizer(c *gin.Context, fallback string, translations map[string]DocsTranslations) *docsLocalizer {
+	l := &docsLocalizer{translations: translations}
+	available := l.locales()
+
+	if locale := matchDocsLocale(c.Query("lang"), available); locale != "" {
+		l.locale = locale
+		return l
+	}
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if locale := matchDocsLocale(tag, available); locale != "" {
+			l.locale = locale
+			return l
+		}
+	}
+	if l.locale = matchDocsLocale(fallback, available); l.locale == "" {
+		l.locale = DefaultDocsLocale
+	}
+	return l
+}
+
+// matchDocsLocale finds a locale by exact tag, case-insensitively, or by its primary language ("pt" -> "pt-BR")
+func matchDocsLocale(tag string, available []string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	for _, locale := range available {
+		if strings.EqualFold(locale, tag) {
+			return locale
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range available {
+		if primary, _, _ := strings.Cut(locale, "-"); strings.EqualFold(primary, language) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// locales lists the built-in locales and those of the translations file, sorted
+func (l *docsLocalizer) locales() []string {
+	seen := make(map[string]bool)
+	for locale := range docsLocales {
+		seen[locale] = true
+	}
+	for locale := range l.translations {
+		seen[locale] = true
+	}
+
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// lookup returns a UI string of a locale from the translations file or the built-in bundle
+func (l *docsLocalizer) lookup(locale, key string) (string, bool) {
+	if text, exists := l.translations[locale].UI[key]; exists {
+		return text, true
+	}
+	text, exists := docsLocales[locale][key]
+	return text, exists
+}
+
+// T translates a UI string to the negotiated locale, falling back to DefaultDocsLocale
+func (l *docsLocalizer) T(key string) string {
+	if text, exists := l.lookup(l.locale, key); exists {
+		return text
+	}
+	if text, exists := l.lookup(DefaultDocsLocale, key); exists {
+		return text
+	}
+	return key
+}
+
+// languages options of the language switcher, each named in its own language
+func (l *docsLocalizer) languages() []docsLanguage {
+	var languages []docsLanguage
+	for _, locale := range l.locales() {
+		name, exists := l.lookup(locale, "language_name")
+		if !exists {
+			name = locale
+		}
+		languages = append(languages, docsLanguage{Code: locale, Name: name})
+	}
+	return languages
+}
+
+// docsLanguage option of the language switcher
+type docsLanguage struct {
+	Code string
+	Name string
+}
+
+// localizeRoutes translates summaries, descriptions and middleware descriptions of the routes.
+// The translations file wins over @Summary.<locale>/@Description.<locale> markers.
+func (l *docsLocalizer) localizeRoutes(routes []RouteEntry) []RouteEntry {
+	localized := make([]RouteEntry, len(routes))
+	for i, route := range routes {
+		translation := route.Translations[l.locale]
+		if fromFile, exists := l.translations[l.locale].Routes[route.Method+" "+route.Path]; exists {
+			if fromFile.Summary != "" {
+				translation.Summary = fromFile.Summary
+			}
+			if fromFile.Description != "" {
+				translation.Description = fromFile.Description
+			}
+		}
+		if translation.Summary != "" {
+			route.Summary = translation.Summary
+		}
+		if translation.Description != "" {
+			route.Description = translation.Description
+		}
+
+		if len(route.MiddlewareInfo) > 0 {
+			middlewares := make([]MiddlewareInfo, len(route.MiddlewareInfo))
+			for j, mw := range route.MiddlewareInfo {
+				// Only the built-in descriptions are translated, generated in Portuguese
+				if text, exists := l.lookup(l.locale, "middleware."+mw.Name); exists && mw.Description == getMiddlewareDescription(mw.Name) {
+					mw.Description = text
+				}
+				middlewares[j] = mw
+			}
+			route.MiddlewareInfo = middlewares
+		}
+		localized[i] = route
+	}
+	return localized
+}
+
+// processTranslationMarker stores @Summary.<locale>("...") and @Description.<locale>("...")
+func processTranslationMarker(marker MarkerInstance, route *RouteMeta) {
+	match := translationMarkerRegex.FindStringSubmatch(marker.Raw)
+	if match == nil || len(marker.Args) == 0 {
+		return
+	}
+
+	if route.Translations == nil {
+		route.Translations = make(map[string]RouteTranslation)
+	}
+	translation := route.Translations[match[2]]
+	text := strings.Trim(marker.Args[0], `"`)
+	if match[1] == "Summary" {
+		translation.Summary = text
+	} else {
+		translation.Description = text
+	}
+	route.Translations[match[2]] = translation
+}
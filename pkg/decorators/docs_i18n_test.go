@@ -0,0 +1,142 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocsLocalizer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	translations := map[string]DocsTranslations{"es": {UI: map[string]string{"language_name": "Español"}}}
+
+	negotiate := func(target, acceptLanguage, fallback string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+		return newDocsLocalizer(c, fallback, translations).locale
+	}
+
+	assert.Equal(t, "pt-BR", negotiate("/docs?lang=pt-br", "en", "en"))
+	assert.Equal(t, "pt-BR", negotiate("/docs", "fr-FR, pt-PT;q=0.8, en;q=0.5", "en"))
+	assert.Equal(t, "es", negotiate("/docs", "es-MX", "en"))
+	assert.Equal(t, "pt-BR", negotiate("/docs?lang=de", "", "pt-BR"))
+	assert.Equal(t, DefaultDocsLocale, negotiate("/docs", "", "ja"))
+}
+
+func TestDocsLocalizerTranslate(t *testing.T) {
+	l := &docsLocalizer{locale: "pt-BR", translations: map[string]DocsTranslations{
+		"pt-BR": {UI: map[string]string{"subtitle": "API de pedidos"}},
+	}}
+	assert.Equal(t, "API de pedidos", l.T("subtitle"))
+	assert.Equal(t, "Rotas registradas", l.T("stat_routes"))
+	assert.Equal(t, "Response cache middleware", (&docsLocalizer{locale: "en"}).T("middleware.Cache"))
+	assert.Equal(t, "unknown_key", l.T("unknown_key"))
+
+	assert.Equal(t, []docsLanguage{{Code: "en", Name: "English"}, {Code: "pt-BR", Name: "Português (Brasil)"}}, l.languages())
+}
+
+func TestDocsLocalizerLocalizeRoutes(t *testing.T) {
+	routes := []RouteEntry{{
+		Method:      "GET",
+		Path:        "/orders",
+		Summary:     "List orders",
+		Description: "Lists the orders of the customer",
+		Translations: map[string]RouteTranslation{
+			"pt-BR": {Summary: "Listar pedidos", Description: "Lista os pedidos do cliente"},
+		},
+		MiddlewareInfo: []MiddlewareInfo{
+			{Name: "Cache", Description: getMiddlewareDescription("Cache")},
+			{Name: "Audit", Description: "Custom audit"},
+		},
+	}}
+
+	l := &docsLocalizer{locale: "pt-BR", translations: map[string]DocsTranslations{
+		"pt-BR": {Routes: map[string]RouteTranslation{"GET /orders": {Summary: "Pedidos"}}},
+	}}
+	localized := l.localizeRoutes(routes)
+	assert.Equal(t, "Pedidos", localized[0].Summary)
+	assert.Equal(t, "Lista os pedidos do cliente", localized[0].Description)
+	assert.Equal(t, getMiddlewareDescription("Cache"), localized[0].MiddlewareInfo[0].Description)
+
+	localized = (&docsLocalizer{locale: "en"}).localizeRoutes(routes)
+	assert.Equal(t, "List orders", localized[0].Summary)
+	assert.Equal(t, "Response cache middleware", localized[0].MiddlewareInfo[0].Description)
+	assert.Equal(t, "Custom audit", localized[0].MiddlewareInfo[1].Description)
+	assert.Equal(t, getMiddlewareDescription("Cache"), routes[0].MiddlewareInfo[0].Description, "registered routes are not modified")
+}
+
+func TestParseDirectory_TranslationMarkers(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/orders")
+// @Summary("List orders")
+// @Summary.pt-BR("Listar pedidos")
+// @Description.pt-BR("Lista os pedidos do cliente")
+// @Description.es("Lista los pedidos")
+func ListOrders(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Equal(t, "List orders", routes[0].Summary)
+	assert.Equal(t, map[string]RouteTranslation{
+		"pt-BR": {Summary: "Listar pedidos", Description: "Lista os pedidos do cliente"},
+		"es":    {Description: "Lista los pedidos"},
+	}, routes[0].Translations)
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", nil))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `"pt-BR": {Summary: "Listar pedidos", Description: "Lista os pedidos do cliente"},`)
+}
+
+func TestDocsPageHandler(t *testing.T) {
+	dir := t.TempDir()
+	translationsFile := filepath.Join(dir, "docs.i18n.yaml")
+	require.NoError(t, os.WriteFile(translationsFile, []byte(`
+pt-BR:
+  ui:
+    subtitle: "API de pedidos"
+es:
+  ui:
+    language_name: "Español"
+    title: "Documentación de rutas"
+`), 0o600))
+
+	config := DefaultConfig()
+	config.Docs = DocsConfig{Locale: "pt-BR", Translations: translationsFile}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/decorators/docs", DocsPageHandler(config))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/decorators/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<html lang="pt-BR"`)
+	assert.Contains(t, w.Body.String(), "API de pedidos")
+	assert.Contains(t, w.Body.String(), "Rotas registradas")
+	assert.Contains(t, w.Body.String(), `<option value="es">Español</option>`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/decorators/docs?lang=es", nil))
+	assert.Contains(t, w.Body.String(), "Documentación de rutas")
+	assert.Contains(t, w.Body.String(), "Registered routes", "missing strings fall back to English")
+}
@@ -0,0 +1,261 @@
+package decorators
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// @Doc(file="docs/users_get.md") loads the extended documentation of a route from a markdown
+// file next to the handler. The markdown becomes the OpenAPI description as is and is rendered
+// to HTML (with code highlighting) in the docs page.
+
+// loadDocMarkers appends the markdown files referenced by @Doc to the route description.
+// Files are read after the parse cache, so editing the markdown alone is picked up.
+func loadDocMarkers(route *RouteMeta) error {
+	for _, marker := range route.Markers {
+		if marker.Name != "Doc" {
+			continue
+		}
+		docFile, err := parseDocArgs(marker.Args)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(docFile) {
+			docFile = filepath.Join(filepath.Dir(route.FilePath), docFile)
+		}
+
+		content, err := os.ReadFile(docFile)
+		if err != nil {
+			return fmt.Errorf("%s:%d: @Doc: cannot read %s: %v", route.FileName, marker.Line, docFile, err)
+		}
+		text := strings.TrimSpace(strings.ReplaceAll(string(content), "\r\n", "\n"))
+		if route.Description != "" && text != "" {
+			text = route.Description + "\n\n" + text
+		}
+		if text != "" {
+			route.Description = text
+		}
+	}
+	return nil
+}
+
+// parseDocArgs parses @Doc arguments: file="path.md" or a positional path
+func parseDocArgs(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("@Doc requires one markdown file, e.g. @Doc(file=\"docs/users_get.md\")")
+	}
+	value := strings.TrimSpace(args[0])
+	if key, rest, found := strings.Cut(value, "="); found {
+		if strings.TrimSpace(key) != "file" {
+			return "", fmt.Errorf("@Doc: unknown argument '%s' (valid: file)", strings.TrimSpace(key))
+		}
+		value = rest
+	}
+	file := MarkerValue(value)
+	if file == "" {
+		return "", fmt.Errorf("@Doc requires one markdown file, e.g. @Doc(file=\"docs/users_get.md\")")
+	}
+	return file, nil
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownOrdered = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalic  = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*`)
+)
+
+// renderMarkdown renders the markdown subset used in endpoint docs (headings, paragraphs,
+// lists, fenced code, inline code, emphasis and links) to escaped HTML
+func renderMarkdown(text string) template.HTML {
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInlineMarkdown(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flushParagraph()
+			closeList()
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if lang != "" {
+				class = ` class="language-` + html.EscapeString(lang) + `"`
+			}
+			out.WriteString("<pre><code" + class + ">" + highlightCode(strings.Join(code, "\n"), lang) + "</code></pre>\n")
+			continue
+		}
+
+		if trimmed == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+		if match := markdownHeading.FindStringSubmatch(trimmed); match != nil {
+			flushParagraph()
+			closeList()
+			// Route docs sit inside a card, so # is rendered as a fourth-level heading
+			level := min(len(match[1])+3, 6)
+			out.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, renderInlineMarkdown(match[2]), level))
+			continue
+		}
+		if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInlineMarkdown(match[1]) + "</li>\n")
+			continue
+		}
+		if match := markdownOrdered.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInlineMarkdown(match[1]) + "</li>\n")
+			continue
+		}
+
+		closeList()
+		paragraph = append(paragraph, trimmed)
+	}
+	flushParagraph()
+	closeList()
+
+	return template.HTML(strings.TrimSuffix(out.String(), "\n")) // every text fragment is escaped above
+}
+
+// renderInlineMarkdown renders inline code, emphasis and links of one block
+func renderInlineMarkdown(text string) string {
+	var out strings.Builder
+	// Backticks alternate plain text and code spans
+	for i, segment := range strings.Split(text, "`") {
+		if i%2 == 1 {
+			out.WriteString("<code>" + html.EscapeString(segment) + "</code>")
+			continue
+		}
+		escaped := html.EscapeString(segment)
+		escaped = markdownLink.ReplaceAllStringFunc(escaped, func(link string) string {
+			match := markdownLink.FindStringSubmatch(link)
+			if !safeMarkdownURL(html.UnescapeString(match[2])) {
+				return match[1]
+			}
+			return `<a href="` + match[2] + `">` + match[1] + `</a>`
+		})
+		escaped = markdownBold.ReplaceAllString(escaped, "<strong>$1</strong>")
+		escaped = markdownItalic.ReplaceAllString(escaped, "$1<em>$2</em>")
+		out.WriteString(escaped)
+	}
+	return out.String()
+}
+
+// safeMarkdownURL accepts relative links and http, https and mailto URLs
+func safeMarkdownURL(url string) bool {
+	scheme, _, found := strings.Cut(url, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// codeKeywords keywords highlighted in code blocks (Go, JavaScript, Python and shell share most of them)
+var codeKeywords = map[string]bool{
+	"func": true, "return": true, "if": true, "else": true, "for": true, "range": true, "var": true,
+	"const": true, "type": true, "struct": true, "package": true, "import": true, "interface": true,
+	"map": true, "chan": true, "go": true, "defer": true, "switch": true, "case": true, "default": true,
+	"break": true, "continue": true, "select": true, "nil": true, "true": true, "false": true,
+	"null": true, "function": true, "let": true, "new": true, "async": true, "await": true, "class": true,
+	"def": true, "from": true, "in": true, "None": true, "True": true, "False": true, "export": true,
+}
+
+// highlightCode escapes a code block, wrapping comments, strings, numbers and keywords in
+// tok-* spans styled by the docs page
+func highlightCode(code, lang string) string {
+	hashComments := map[string]bool{"sh": true, "bash": true, "shell": true, "yaml": true, "yml": true, "python": true, "py": true, "toml": true}[strings.ToLower(lang)]
+	runes := []rune(code)
+	var out strings.Builder
+	span := func(class string, text []rune) {
+		out.WriteString(`<span class="tok-` + class + `">` + html.EscapeString(string(text)) + "</span>")
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case (r == '/' && i+1 < len(runes) && runes[i+1] == '/') || (r == '#' && hashComments):
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			span("comment", runes[i:end])
+			i = end
+		case r == '"' || r == '\'' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r && (r == '`' || runes[end] != '\n') {
+				if runes[end] == '\\' && r != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			span("string", runes[i:end])
+			i = end
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			span("number", runes[i:end])
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if word := runes[i:end]; codeKeywords[string(word)] {
+				span("keyword", word)
+			} else {
+				out.WriteString(html.EscapeString(string(word)))
+			}
+			i = end
+		default:
+			out.WriteString(html.EscapeString(string(r)))
+			i++
+		}
+	}
+	return out.String()
+}
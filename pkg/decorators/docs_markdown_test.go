@@ -0,0 +1,63 @@
+package decorators
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirectory_DocMarker(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "docs", "users_get.md"), "## Pagination\n\nUse `page` and `size`.\n")
+	writeTestFile(t, filepath.Join(dir, "handlers.go"), `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Description("Lists users")
+// @Doc(file="docs/users_get.md")
+func ListUsers(c *gin.Context) {}
+
+// @Route("GET", "/users/:id")
+// @Doc(docs/missing.md)
+func GetUser(c *gin.Context) {}
+`)
+
+	_, err := ParseDirectory(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "handlers.go:11: @Doc: cannot read")
+
+	writeTestFile(t, filepath.Join(dir, "docs", "missing.md"), "Returns one user.")
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	assert.Equal(t, "Lists users\n\n## Pagination\n\nUse `page` and `size`.", routes[0].Description)
+	assert.Equal(t, "Returns one user.", routes[1].Description)
+	assert.Equal(t, "Lists users", firstLine(routes[0].Description))
+
+	assert.ErrorContains(t, validateArgumentValues("Doc", []string{"path=x.md"}), "unknown argument 'path'")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	html := string(renderMarkdown("# Users\n\nReturns **active** users, see [guide](https://example.com/a?b=1&c=2).\n\n" +
+		"- one\n- `two`\n\n1. first\n\n```go\n// list\nusers := Find(\"<all>\", 10)\n```"))
+
+	assert.Equal(t, "<h4>Users</h4>\n"+
+		"<p>Returns <strong>active</strong> users, see <a href=\"https://example.com/a?b=1&amp;c=2\">guide</a>.</p>\n"+
+		"<ul>\n<li>one</li>\n<li><code>two</code></li>\n</ul>\n"+
+		"<ol>\n<li>first</li>\n</ol>\n"+
+		"<pre><code class=\"language-go\"><span class=\"tok-comment\">// list</span>\n"+
+		"users := Find(<span class=\"tok-string\">&#34;&lt;all&gt;&#34;</span>, <span class=\"tok-number\">10</span>)</code></pre>", html)
+}
+
+func TestRenderMarkdown_Escapes(t *testing.T) {
+	html := string(renderMarkdown("<script>alert(1)</script> [x](javascript:alert(1)) *note*"))
+	assert.NotContains(t, html, "<script>")
+	assert.NotContains(t, html, "href")
+	assert.Contains(t, html, "<em>note</em>")
+
+	assert.Equal(t, `<span class="tok-keyword">func</span> f() {}`, highlightCode("func f() {}", "go"))
+	assert.Equal(t, `<span class="tok-comment"># run</span>`, highlightCode("# run", "bash"))
+}
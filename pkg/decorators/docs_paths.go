@@ -0,0 +1,64 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDocsBasePath prefix of the documentation endpoints without docs.base_path
+const DefaultDocsBasePath = "/decorators"
+
+// Path endpoint of the documentation under docs.base_path, e.g. Path("swagger-ui")
+func (d DocsConfig) Path(name string) string {
+	base := DefaultDocsBasePath
+	if d.BasePath != "" {
+		base = strings.TrimSuffix(d.BasePath, "/")
+	}
+	return base + "/" + name
+}
+
+// docsEndpointEnabled reports whether a documentation endpoint is served, defaulting to true when unset
+func docsEndpointEnabled(flag *bool) bool {
+	return flag == nil || *flag
+}
+
+// validatePaths checks the base path and that the Swagger UI keeps the spec it loads
+func (d DocsConfig) validatePaths() error {
+	if d.BasePath != "" && !strings.HasPrefix(d.BasePath, "/") {
+		return fmt.Errorf("docs.base_path must start with '/', found '%s'", d.BasePath)
+	}
+	if docsEndpointEnabled(d.Endpoints.SwaggerUI) && !docsEndpointEnabled(d.Endpoints.OpenAPI) {
+		return fmt.Errorf("docs.endpoints.swagger_ui needs docs.endpoints.openapi, the Swagger UI loads openapi.json")
+	}
+	return nil
+}
+
+// registerDocsRoutes mounts the enabled documentation endpoints under docs.base_path
+func registerDocsRoutes(r *gin.Engine, config *Config, securityMiddleware gin.HandlerFunc) {
+	docs, endpoints := config.Docs, config.Docs.Endpoints
+
+	if docsEndpointEnabled(endpoints.Page) {
+		r.GET(docs.Path("docs"), securityMiddleware, DocsPageHandler(config))
+	}
+	if docsEndpointEnabled(endpoints.JSON) {
+		r.GET(docs.Path("docs.json"), securityMiddleware, DocsJSONHandler)
+	}
+	if docsEndpointEnabled(endpoints.OpenAPI) {
+		r.GET(docs.Path("openapi.json"), securityMiddleware, OpenAPIJSONHandler(config))
+		r.GET(docs.Path("openapi.yaml"), securityMiddleware, OpenAPIYAMLHandler(config))
+		r.GET(docs.Path("openapi/:version"), securityMiddleware, OpenAPIVersionHandler(config))
+		if config.OpenAPI.SpecVersion == OpenAPISpecVersion31 {
+			r.GET(docs.Path("openapi31.json"), securityMiddleware, OpenAPI31Handler(config))
+		}
+	}
+	if docsEndpointEnabled(endpoints.SwaggerUI) {
+		swaggerUI := docs.Path("swagger-ui")
+		r.GET(swaggerUI, securityMiddleware, SwaggerUIHandler(config))
+		r.GET(docs.Path("swagger"), securityMiddleware, func(c *gin.Context) {
+			c.Redirect(http.StatusMovedPermanently, swaggerUI)
+		})
+	}
+}
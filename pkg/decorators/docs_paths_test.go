@@ -0,0 +1,49 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsConfig_Path(t *testing.T) {
+	assert.Equal(t, "/decorators/docs", DocsConfig{}.Path("docs"))
+	assert.Equal(t, "/internal/api-docs/swagger-ui", DocsConfig{BasePath: "/internal/api-docs/"}.Path("swagger-ui"))
+	assert.Equal(t, "/openapi.json", DocsConfig{BasePath: "/"}.Path("openapi.json"))
+}
+
+func TestDocsConfig_ValidatePaths(t *testing.T) {
+	disabled := false
+	assert.NoError(t, DocsConfig{}.validatePaths())
+	assert.ErrorContains(t, DocsConfig{BasePath: "api-docs"}.validatePaths(), "must start with '/'")
+	assert.ErrorContains(t, DocsConfig{Endpoints: DocsEndpointsConfig{OpenAPI: &disabled}}.validatePaths(), "swagger_ui needs")
+	assert.NoError(t, DocsConfig{Endpoints: DocsEndpointsConfig{OpenAPI: &disabled, SwaggerUI: &disabled}}.validatePaths())
+}
+
+func TestRegisterDocsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	disabled := false
+	config := DefaultConfig()
+	config.Docs.BasePath = "/internal/api-docs"
+	config.Docs.Endpoints.JSON = &disabled
+
+	router := gin.New()
+	registerDocsRoutes(router, config, func(c *gin.Context) { c.Next() })
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	page := serve("/internal/api-docs/docs")
+	assert.Equal(t, http.StatusOK, page.Code)
+	assert.NotContains(t, page.Body.String(), "docs.json", "no link to a disabled endpoint")
+	assert.Equal(t, http.StatusNotFound, serve("/internal/api-docs/docs.json").Code)
+	assert.Equal(t, http.StatusNotFound, serve("/decorators/docs").Code)
+	assert.Equal(t, http.StatusOK, serve("/internal/api-docs/openapi.json").Code)
+	assert.Contains(t, serve("/internal/api-docs/swagger-ui").Body.String(), "url: '/internal/api-docs/openapi.json'")
+	assert.Equal(t, "/internal/api-docs/swagger-ui", serve("/internal/api-docs/swagger").Header().Get("Location"))
+}
@@ -0,0 +1,265 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Project diagnostics of deco doctor: each check returns findings with the position to fix and how
+
+// Doctor finding severities
+const (
+	DoctorError   = "error"
+	DoctorWarning = "warning"
+)
+
+// DoctorFinding problem found in the project, with the fix to apply
+type DoctorFinding struct {
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// String formats the finding as file:line: message
+func (f DoctorFinding) String() string {
+	switch {
+	case f.File != "" && f.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+	case f.File != "":
+		return f.File + ": " + f.Message
+	}
+	return f.Message
+}
+
+// ConfigIssueFindings turns the configuration issues into findings
+func ConfigIssueFindings(issues []ConfigIssue) []DoctorFinding {
+	findings := make([]DoctorFinding, 0, len(issues))
+	for _, issue := range issues {
+		message := issue.Message
+		if issue.Path != "" {
+			message = issue.Path + ": " + message
+		}
+		findings = append(findings, DoctorFinding{
+			Severity: DoctorError,
+			File:     issue.File,
+			Line:     issue.Line,
+			Message:  message,
+			Fix:      "fix the key; 'deco config schema' lists the accepted keys and values",
+		})
+	}
+	return findings
+}
+
+// DecoratorErrorFindings turns the decorator errors of the parser into findings
+func DecoratorErrorFindings(errs []ValidationError) []DoctorFinding {
+	findings := make([]DoctorFinding, 0, len(errs))
+	for _, err := range errs {
+		findings = append(findings, DoctorFinding{
+			Severity: DoctorError,
+			File:     err.File,
+			Line:     err.Line,
+			Message:  err.Message,
+			Fix:      "fix the decorator; 'deco lsp' shows these errors in the editor",
+		})
+	}
+	return findings
+}
+
+// routeShape path with the parameter names erased: gin rejects two routes that only differ by them
+func routeShape(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segments[i] = ":"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// DuplicateRouteFindings reports the routes declared twice for the same method, path and @Version, including
+// paths that only differ by parameter names (gin panics on them at startup)
+func DuplicateRouteFindings(routes []*RouteMeta) []DoctorFinding {
+	first := make(map[string]*RouteMeta)
+	var findings []DoctorFinding
+	for _, route := range routes {
+		if route.Method == "" || route.Path == "" {
+			continue
+		}
+		var original *RouteMeta
+		for _, version := range routeVersions(route) {
+			key := strings.ToUpper(route.Method) + " " + routeShape(route.Path) + " " + version
+			if first[key] == nil {
+				first[key] = route
+			} else if original == nil {
+				original = first[key]
+			}
+		}
+		if original == nil {
+			continue
+		}
+
+		message := fmt.Sprintf("duplicate route %s %s, already declared by %s at %s:%d",
+			route.Method, route.Path, original.FuncName, original.FilePath, original.Line)
+		if original.Path != route.Path {
+			message = fmt.Sprintf("route %s %s conflicts with %s (%s at %s:%d): only the parameter names differ",
+				route.Method, route.Path, original.Path, original.FuncName, original.FilePath, original.Line)
+		}
+		findings = append(findings, DoctorFinding{
+			Severity: DoctorError,
+			File:     route.FilePath,
+			Line:     route.Line,
+			Message:  message,
+			Fix:      fmt.Sprintf("change the method, path or @Version of %s, or remove one of the handlers", route.FuncName),
+		})
+	}
+	return findings
+}
+
+// doctorBuiltinTypes types described without a @Schema
+var doctorBuiltinTypes = map[string]bool{
+	"string": true, "bool": true, "boolean": true, "byte": true, "rune": true, "integer": true, "number": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "object": true, "array": true, "file": true,
+	"interface{}": true, "any": true, "time.Time": true, "time.Duration": true, "uuid.UUID": true,
+	"json.RawMessage": true, "gin.H": true,
+}
+
+// schemaTypeName element type of a declared type: "[]models.User" -> "User"; empty for builtins
+func schemaTypeName(typeName string) string {
+	for {
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(typeName), "[]"), "*")
+		if strings.HasPrefix(trimmed, "map[") {
+			if end := strings.Index(trimmed, "]"); end > 0 {
+				trimmed = trimmed[end+1:]
+			}
+		}
+		if trimmed == typeName {
+			break
+		}
+		typeName = trimmed
+	}
+	if typeName == "" || doctorBuiltinTypes[typeName] {
+		return ""
+	}
+	if dot := strings.LastIndex(typeName, "."); dot >= 0 {
+		return typeName[dot+1:]
+	}
+	return typeName
+}
+
+// UnknownSchemaFindings reports the @Param bodies and @Response types that name no registered @Schema;
+// the OpenAPI spec and the SDKs describe them as untyped objects
+func UnknownSchemaFindings(routes []*RouteMeta) []DoctorFinding {
+	schemas := GetSchemas()
+	var findings []DoctorFinding
+	for _, route := range routes {
+		types := make([]string, 0, len(route.Parameters)+len(route.Responses))
+		for _, param := range route.Parameters {
+			if param.Location == "body" {
+				types = append(types, param.Type)
+			}
+		}
+		for _, response := range route.Responses {
+			types = append(types, response.Type)
+		}
+
+		reported := make(map[string]bool)
+		for _, declared := range types {
+			name := schemaTypeName(declared)
+			if name == "" || schemas[name] != nil || reported[name] {
+				continue
+			}
+			reported[name] = true
+
+			message := fmt.Sprintf("%s references unknown schema '%s'", route.FuncName, declared)
+			if suggestion := closestSchemaName(name, schemas); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			}
+			findings = append(findings, DoctorFinding{
+				Severity: DoctorWarning,
+				File:     route.FilePath,
+				Line:     markerLine(route, declared),
+				Message:  message,
+				Fix:      fmt.Sprintf("add // @Schema() above the %s struct, in a file parsed with the handlers", name),
+			})
+		}
+	}
+	return findings
+}
+
+// closestSchemaName registered schema closest to a misspelled name, empty when none is close
+func closestSchemaName(name string, schemas map[string]*SchemaInfo) string {
+	names := make([]string, 0, len(schemas))
+	for candidate := range schemas {
+		names = append(names, candidate)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, candidate := range names {
+		if distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// markerLine line of the decorator mentioning text, the @Route line when none does
+func markerLine(route *RouteMeta, text string) int {
+	for _, marker := range route.Markers {
+		if marker.Line > 0 && strings.Contains(marker.Raw, text) {
+			return marker.Line
+		}
+	}
+	return route.Line
+}
+
+// PingRedis checks that the Redis of the configuration answers
+func PingRedis(ctx context.Context, config RedisConfig) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Address,
+		Password: config.Password,
+		DB:       config.DB,
+		PoolSize: 1,
+	})
+	defer client.Close()
+	return client.Ping(ctx).Err()
+}
+
+// DialTelemetryEndpoint checks that the OTLP collector of telemetry.endpoint accepts connections
+func DialTelemetryEndpoint(ctx context.Context, endpoint string) error {
+	address := endpoint
+	if parsed, err := url.Parse(endpoint); err == nil && parsed.Host != "" {
+		address = parsed.Host
+		if parsed.Port() == "" {
+			port := "80"
+			if parsed.Scheme == "https" {
+				port = "443"
+			}
+			address = net.JoinHostPort(parsed.Hostname(), port)
+		}
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "4317") // OTLP/gRPC
+	}
+
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
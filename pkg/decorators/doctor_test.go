@@ -0,0 +1,70 @@
+package decorators
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateRouteFindings(t *testing.T) {
+	routes := []*RouteMeta{
+		{Method: "GET", Path: "/users/:id", FuncName: "GetUser", FilePath: "handlers/users.go", Line: 10},
+		{Method: "GET", Path: "/users/:id", FuncName: "FindUser", FilePath: "handlers/find.go", Line: 4},
+		{Method: "GET", Path: "/users/{userID}", FuncName: "ShowUser", FilePath: "handlers/show.go", Line: 8},
+		{Method: "DELETE", Path: "/users/:id", FuncName: "DeleteUser", FilePath: "handlers/users.go", Line: 20},
+		{Method: "GET", Path: "/users/:id/posts", FuncName: "ListPosts", FilePath: "handlers/posts.go", Line: 3},
+	}
+
+	findings := DuplicateRouteFindings(routes)
+	require.Len(t, findings, 2)
+	assert.Equal(t, "handlers/find.go:4: duplicate route GET /users/:id, already declared by GetUser at handlers/users.go:10", findings[0].String())
+	assert.Equal(t, DoctorError, findings[0].Severity)
+	assert.Contains(t, findings[1].Message, "only the parameter names differ")
+	assert.Equal(t, "handlers/show.go", findings[1].File)
+}
+
+func TestUnknownSchemaFindings(t *testing.T) {
+	ClearSchemas()
+	defer ClearSchemas()
+	RegisterSchema(&SchemaInfo{Name: "User", Type: "object"})
+
+	route := &RouteMeta{
+		Method: "POST", Path: "/users", FuncName: "CreateUser", FilePath: "handlers/users.go", Line: 12,
+		Parameters: []ParameterInfo{
+			{Name: "body", Type: "models.Usr", Location: "body"},
+			{Name: "id", Type: "Identifier", Location: "query"},
+		},
+		Responses: []ResponseInfo{
+			{Code: "200", Type: "[]*models.User"},
+			{Code: "201", Type: "map[string]int"},
+			{Code: "400", Type: "ErrorResponse"},
+		},
+		Markers: []MarkerInstance{
+			{Name: "Route", Raw: `@Route("POST", "/users")`, Line: 12},
+			{Name: "Param", Raw: `@Param(name="body", type="models.Usr", location="body")`, Line: 9},
+		},
+	}
+
+	findings := UnknownSchemaFindings([]*RouteMeta{route})
+	require.Len(t, findings, 2)
+	assert.Equal(t, "handlers/users.go:9: CreateUser references unknown schema 'models.Usr' (did you mean 'User'?)", findings[0].String())
+	assert.Equal(t, DoctorWarning, findings[0].Severity)
+	assert.Equal(t, 12, findings[1].Line, "falls back to the @Route line")
+	assert.Contains(t, findings[1].Fix, "ErrorResponse")
+}
+
+func TestDialTelemetryEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	address := listener.Addr().String()
+
+	assert.NoError(t, DialTelemetryEndpoint(context.Background(), address))
+	assert.NoError(t, DialTelemetryEndpoint(context.Background(), "http://"+address+"/v1/traces"))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, DialTelemetryEndpoint(context.Background(), address))
+}
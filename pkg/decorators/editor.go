@@ -0,0 +1,169 @@
+package decorators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Editor support (deco lsp): diagnostics, completion and hover for the decorators of handler comments
+
+// UnknownMarkerCode code of the ValidationError reported for a misspelled marker
+const UnknownMarkerCode = "UNKNOWN_MARKER"
+
+// markerUsePattern "@Name(" at the start of a comment line
+var markerUsePattern = regexp.MustCompile(`^//\s*@([A-Za-z]\w*)\s*\(`)
+
+// documentationMarkers descriptions of the markers that only document the route
+var documentationMarkers = map[string]string{
+	"Route":                  `Registers the handler: @Route("GET", "/users/:id"), @Route("GET,HEAD", "/health") for several methods`,
+	"Group":                  `Groups routes in the docs: @Group(name="users", prefix="/users", description="...")`,
+	"Param":                  `Documents a parameter: @Param(name="id", type="string", location="path", required=true)`,
+	"RequestBody":            `Documents the request body: @RequestBody(CreateUserRequest, contentType="multipart/form-data")`,
+	"Description":            "Description of the operation",
+	"Doc":                    `Reads the description from a markdown file: @Doc(file="docs/users.md")`,
+	"Summary":                "Summary of the operation",
+	"SummaryTranslation":     `Localized summary: @SummaryTranslation(lang="pt-BR", text="...")`,
+	"DescriptionTranslation": `Localized description: @DescriptionTranslation(lang="pt-BR", text="...")`,
+	"Schema":                 "Publishes the struct as an OpenAPI schema",
+	"Controller":             `Serves the decorated methods of the struct under a prefix: @Controller("/users", constructor="NewUserController")`,
+	"SchemaVersion":          "Version of the schema for migrations",
+	"Tag":                    "Tag of the operation",
+	"Response":               `Documents a response: @Response(code=200, description="OK", type="UserResponse")`,
+	"MiddlewareOrder":        `Orders the middlewares of the route: @MiddlewareOrder(Cache, Auth)`,
+	"Version":                `Serves the route under API versions, as versioning configures: @Version("v1", "v2")`,
+}
+
+// EditorMarker marker as offered to editors
+type EditorMarker struct {
+	Name        string
+	Description string
+	Args        []MarkerArg
+}
+
+// EditorMarkers returns the registered markers, @Route included, sorted by name
+func EditorMarkers() []EditorMarker {
+	registered := GetMarkers()
+	list := make([]EditorMarker, 0, len(registered)+1)
+	list = append(list, EditorMarker{Name: "Route", Description: documentationMarkers["Route"]})
+	for name, config := range registered {
+		list = append(list, EditorMarker{Name: name, Description: markerDescription(config), Args: config.Args})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LookupEditorMarker returns the marker named name
+func LookupEditorMarker(name string) (EditorMarker, bool) {
+	for _, marker := range EditorMarkers() {
+		if marker.Name == name {
+			return marker, true
+		}
+	}
+	return EditorMarker{}, false
+}
+
+// markerDescription English description of a marker
+func markerDescription(config MarkerConfig) string {
+	if config.Description != "" {
+		return config.Description
+	}
+	if description, ok := documentationMarkers[config.Name]; ok {
+		return description
+	}
+	if description, ok := docsLocales["en"]["middleware."+config.Name]; ok {
+		return description
+	}
+	return getMiddlewareDescription(config.Name)
+}
+
+// Markdown documentation of the marker shown on hover
+func (m EditorMarker) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**@%s**\n\n%s", m.Name, m.Description)
+	if len(m.Args) > 0 {
+		b.WriteString("\n\nArguments:\n")
+		for _, arg := range m.Args {
+			fmt.Fprintf(&b, "\n- `%s`", arg.Name)
+			if arg.Type != "" {
+				fmt.Fprintf(&b, " (%s)", arg.Type)
+			}
+			if len(arg.Enum) > 0 {
+				fmt.Fprintf(&b, ": %s", strings.Join(arg.Enum, ", "))
+			}
+			if arg.Required {
+				b.WriteString(" — required")
+			}
+		}
+	}
+	return b.String()
+}
+
+// ValidateSource checks the decorators of a Go source as deco generate does, without registering
+// routes or schemas. Go syntax errors are left to the Go tooling: the decorators of the functions that
+// still parse are checked. Markers close to a known name are reported with UnknownMarkerCode.
+func ValidateSource(fileName string, content []byte) []ValidationError {
+	fset := token.NewFileSet()
+	file, _ := parser.ParseFile(fset, fileName, content, parser.ParseComments)
+	if file == nil {
+		return nil
+	}
+
+	result := extractFileResult(fset, fileName, file, file.Name.Name)
+	errors := append([]ValidationError{}, result.Errors...)
+	for _, route := range result.Routes {
+		if err := processMiddlewares(route); err != nil {
+			errors = append(errors, ValidationError{
+				File:    filepath.Base(fileName),
+				Line:    route.Line,
+				Message: err.Error(),
+				Code:    "INVALID_MARKER",
+			})
+		}
+	}
+	return append(errors, unknownMarkers(fset, fileName, file)...)
+}
+
+// unknownMarkers reports "@Name(" lines of function comments whose name is a typo of a known marker
+func unknownMarkers(fset *token.FileSet, fileName string, file *ast.File) []ValidationError {
+	known := make(map[string]bool)
+	for _, marker := range EditorMarkers() {
+		known[marker.Name] = true
+	}
+
+	var errors []ValidationError
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Doc == nil {
+			continue
+		}
+		for _, comment := range funcDecl.Doc.List {
+			match := markerUsePattern.FindStringSubmatch(comment.Text)
+			if match == nil || known[match[1]] {
+				continue
+			}
+			best, bestDistance := "", 3
+			for name := range known {
+				if distance := levenshtein(strings.ToLower(match[1]), strings.ToLower(name)); distance < bestDistance ||
+					(distance == bestDistance && name < best) {
+					best, bestDistance = name, distance
+				}
+			}
+			if best == "" {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				File:    filepath.Base(fileName),
+				Line:    fset.Position(comment.Pos()).Line,
+				Message: fmt.Sprintf("unknown marker @%s (did you mean @%s?)", match[1], best),
+				Code:    UnknownMarkerCode,
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,67 @@
+package decorators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSource(t *testing.T) {
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/ping")
+// @Cache(ttl="abc")
+func Ping(c *gin.Context) {}
+
+// @Route("GET", "/orders")
+// @Cahce(ttl="5m")
+// @Mention(user="ana")
+func Orders(c *gin.Context) {}
+
+// Users lists users
+// @Route("GET", "/users")
+// @RateLimit(limit=10
+func Users(c *gin.Context) {}
+
+func Broken( {
+`
+	errors := ValidateSource("/src/handlers.go", []byte(source))
+	require.Len(t, errors, 3)
+
+	assert.Equal(t, ValidationError{
+		File:    "handlers.go",
+		Line:    6,
+		Code:    "INVALID_ARGUMENTS",
+		Message: `Error in @Cache decorator arguments: ttl: invalid duration '"abc"' (e.g. "500ms", "30s", "5m", "1h30m", "7d")`,
+	}, errors[0])
+	assert.Equal(t, "UNMATCHED_PARENTHESES", errors[1].Code)
+	assert.Equal(t, 16, errors[1].Line, "syntax errors point at the comment line")
+	assert.Equal(t, ValidationError{
+		File:    "handlers.go",
+		Line:    10,
+		Code:    UnknownMarkerCode,
+		Message: "unknown marker @Cahce (did you mean @Cache?)",
+	}, errors[2], "names far from every marker are not reported")
+}
+
+func TestEditorMarkers(t *testing.T) {
+	markers := EditorMarkers()
+	require.NotEmpty(t, markers)
+	assert.Equal(t, "Auth", markers[0].Name)
+
+	route, ok := LookupEditorMarker("Route")
+	require.True(t, ok)
+	assert.Contains(t, route.Description, `@Route("GET", "/users/:id")`)
+
+	rateLimit, ok := LookupEditorMarker("RateLimit")
+	require.True(t, ok)
+	assert.Equal(t, "Rate limiting middleware", rateLimit.Description)
+	assert.Contains(t, rateLimit.Markdown(), "- `type`: memory, redis")
+	assert.Contains(t, rateLimit.Markdown(), "- `window` (duration)")
+
+	_, ok = LookupEditorMarker("Nope")
+	assert.False(t, ok)
+}
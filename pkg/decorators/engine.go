@@ -0,0 +1,180 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LifecycleHook function run when the engine starts or stops; ctx carries the shutdown deadline
+type LifecycleHook func(ctx context.Context) error
+
+// Engine gin.Engine returned by NewEngine, with lifecycle hooks and graceful shutdown
+type Engine struct {
+	*gin.Engine
+
+	mu       sync.Mutex
+	startup  []LifecycleHook
+	shutdown []LifecycleHook
+	server   *http.Server
+	stopped  bool
+}
+
+// newEngine wraps the gin engine
+func newEngine(r *gin.Engine) *Engine {
+	return &Engine{Engine: r}
+}
+
+// OnStartup registers a hook run before the server accepts connections, in registration order
+func (e *Engine) OnStartup(hook LifecycleHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.startup = append(e.startup, hook)
+}
+
+// OnShutdown registers a hook run once in-flight requests are drained, in reverse registration order
+// (the last resource opened is the first closed), before the telemetry and cache connections close
+func (e *Engine) OnShutdown(hook LifecycleHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = append(e.shutdown, hook)
+}
+
+// Start runs the startup hooks; the first error stops them
+func (e *Engine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	hooks := append([]LifecycleHook(nil), e.startup...)
+	e.mu.Unlock()
+
+	for i, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("startup hook %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// RunWithGracefulShutdown runs the startup hooks and serves addr until SIGINT or SIGTERM, then shuts
+// down within timeout (see Shutdown)
+func (e *Engine) RunWithGracefulShutdown(addr string, timeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return e.RunContext(ctx, addr, timeout)
+}
+
+// RunContext runs the startup hooks and serves addr until ctx is done, then shuts down within timeout
+func (e *Engine) RunContext(ctx context.Context, addr string, timeout time.Duration) error {
+	if err := e.Start(ctx); err != nil {
+		return errors.Join(err, e.shutdownWithin(timeout))
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Join(err, e.shutdownWithin(timeout))
+	}
+	return e.serve(ctx, listener, timeout)
+}
+
+// serve serves the listener until ctx is done or the server fails
+func (e *Engine) serve(ctx context.Context, listener net.Listener, timeout time.Duration) error {
+	server := &http.Server{Handler: e.Engine, ReadHeaderTimeout: 30 * time.Second}
+	e.mu.Lock()
+	e.server = server
+	e.mu.Unlock()
+
+	LogNormal("Listening and serving HTTP on %s", listener.Addr())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+
+	var serveErr error
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			serveErr = err
+		}
+	case <-ctx.Done():
+		LogNormal("Shutting down: draining in-flight requests (timeout %s)", timeout)
+	}
+	return errors.Join(serveErr, e.shutdownWithin(timeout))
+}
+
+// shutdownWithin shuts down with a fresh timeout, the serving context being already done
+func (e *Engine) shutdownWithin(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return e.Shutdown(ctx)
+}
+
+// Shutdown stops the engine in order: in-flight HTTP requests finish, the
+// gRPC server and @Subscribe consumers stop, WebSocket clients get a close frame, the OnShutdown hooks
+// run, events and telemetry exporters flush, and the cache, rate limit and revocation connections
+// close. Later steps still run when one fails; the errors are joined. Only the first call does anything.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.stopped = true
+	server := e.server
+	hooks := append([]LifecycleHook(nil), e.shutdown...)
+	e.mu.Unlock()
+
+	EmitEvent(EventEngineStopping, "", nil)
+	var errs []error
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("draining HTTP requests: %w", err))
+		}
+	}
+
+	StopGRPCServer()
+	StopSubscriptions()
+	CloseWebSocketHub()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook %d: %w", i+1, err))
+		}
+	}
+
+	StopEvents()
+	StopProfiling()
+	if err := ShutdownTelemetry(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flushing telemetry: %w", err))
+	}
+	errs = append(errs, closeConnections()...)
+	return errors.Join(errs...)
+}
+
+// closeConnections closes the cache stores, rate limiters and revocation list holding connections
+func closeConnections() []error {
+	stores, limiters := releaseAdminStores()
+	closers := make([]interface{}, 0, len(stores)+len(limiters)+1)
+	for _, store := range stores {
+		closers = append(closers, store)
+	}
+	for _, limiter := range limiters {
+		closers = append(closers, limiter)
+	}
+	closers = append(closers, GetRevocationList())
+
+	var errs []error
+	for _, candidate := range closers {
+		if closer, ok := candidate.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("closing %T: %w", candidate, err))
+			}
+		}
+	}
+	return errs
+}
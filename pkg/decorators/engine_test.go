@@ -0,0 +1,123 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineGracefulShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := newEngine(gin.New())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	engine.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	var order []string
+	engine.OnStartup(func(context.Context) error { order = append(order, "start-db"); return nil })
+	engine.OnStartup(func(context.Context) error { order = append(order, "start-queue"); return nil })
+	engine.OnShutdown(func(context.Context) error { order = append(order, "stop-db"); return nil })
+	engine.OnShutdown(func(context.Context) error { order = append(order, "stop-queue"); return errors.New("queue busy") })
+	require.NoError(t, engine.Start(context.Background()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- engine.serve(ctx, listener, 5*time.Second) }()
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-stopped:
+		t.Fatal("shutdown finished before the in-flight request")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	assert.Equal(t, "done", <-response, "in-flight requests are drained")
+	err = <-stopped
+	assert.ErrorContains(t, err, "shutdown hook 2: queue busy")
+	assert.Equal(t, []string{"start-db", "start-queue", "stop-queue", "stop-db"}, order, "shutdown hooks run in reverse")
+
+	assert.NoError(t, engine.Shutdown(context.Background()), "only the first shutdown runs")
+	assert.Len(t, order, 4)
+}
+
+func TestEngineStartupFailure(t *testing.T) {
+	engine := newEngine(gin.New())
+	engine.OnStartup(func(context.Context) error { return errors.New("no database") })
+	closed := false
+	engine.OnShutdown(func(context.Context) error { closed = true; return nil })
+
+	err := engine.RunContext(context.Background(), "127.0.0.1:0", time.Second)
+	assert.ErrorContains(t, err, "startup hook 1: no database")
+	assert.True(t, closed, "resources opened before the failure are released")
+}
+
+func TestCloseWebSocketHub(t *testing.T) {
+	hub := InitWebSocket(WebSocketConfig{Enabled: true, PingInterval: "54s", PongTimeout: "60s"})
+	conn := &WebSocketConnection{
+		ID:       "closing",
+		Hub:      hub,
+		Send:     make(chan []byte, 256),
+		Groups:   make(map[string]bool),
+		Metadata: make(map[string]interface{}),
+	}
+	hub.register <- conn
+	require.NoError(t, hub.JoinGroup(conn.ID, "room"))
+
+	CloseWebSocketHub()
+	CloseWebSocketHub()
+
+	<-conn.Send // welcome message
+	_, open := <-conn.Send
+	assert.False(t, open, "the writer sends the close frame")
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	assert.Empty(t, hub.connections)
+	assert.Empty(t, hub.groups)
+}
+
+func TestClosedRedisConnections(t *testing.T) {
+	cache := &RedisCache{client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})}
+	limiter := &RedisRateLimiter{client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})}
+	registerAdminCacheStore(cache)
+	registerAdminRateLimiter(limiter)
+
+	assert.Empty(t, closeConnections())
+	assert.ErrorIs(t, cache.Close(), redis.ErrClosed, "closeConnections closed the cache")
+	assert.ErrorIs(t, limiter.Close(), redis.ErrClosed)
+	stores, limiters := adminStores()
+	assert.Empty(t, stores)
+	assert.Empty(t, limiters)
+
+	store := NewEncryptedCacheStore(NewMemoryCache(10), DefaultCacheEncryptionKey)
+	assert.NoError(t, store.Close(), "stores without a connection close as a no-op")
+	assert.NoError(t, ShutdownTelemetry(context.Background()))
+}
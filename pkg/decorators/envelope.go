@@ -0,0 +1,323 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeExtension OpenAPI extension marking the operations whose responses are enveloped, used by the
+// SDK generators to unwrap data
+const EnvelopeExtension = "x-envelope"
+
+// Context keys of the envelope of a request
+const (
+	envelopeKey     = "deco.envelope"
+	envelopeMetaKey = "deco.envelopeMeta"
+)
+
+var envelopeSettings atomic.Pointer[EnvelopeConfig]
+
+// ConfigureEnvelope sets the defaults of @Envelope from the envelope section
+func ConfigureEnvelope(config EnvelopeConfig) {
+	envelopeSettings.Store(&config)
+}
+
+// envelopeConfig envelope of ConfigureEnvelope, or the default one
+func envelopeConfig() EnvelopeConfig {
+	if config := envelopeSettings.Load(); config != nil {
+		return *config
+	}
+	return EnvelopeConfig{}
+}
+
+// parseEnvelopeArgs parses @Envelope() and @Envelope(skip_errors=true)
+func parseEnvelopeArgs(args []string) (EnvelopeConfig, error) {
+	var config EnvelopeConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@Envelope: unexpected argument '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "skip_errors":
+			skip, err := strconv.ParseBool(value)
+			if err != nil {
+				return config, fmt.Errorf("@Envelope: invalid skip_errors '%s' (expected true or false)", value)
+			}
+			config.SkipErrors = skip
+		default:
+			return config, fmt.Errorf("@Envelope: unknown argument '%s' (valid: skip_errors)", key)
+		}
+	}
+	return config, nil
+}
+
+// SetEnvelopeMeta adds a key to the meta object of the enveloped response, e.g. pagination:
+//
+//	deco.SetEnvelopeMeta(c, "page", gin.H{"number": page, "size": size, "total": total})
+func SetEnvelopeMeta(c *gin.Context, key string, value interface{}) {
+	meta, _ := c.Get(envelopeMetaKey)
+	values, ok := meta.(map[string]interface{})
+	if !ok {
+		values = make(map[string]interface{})
+		c.Set(envelopeMetaKey, values)
+	}
+	values[key] = value
+}
+
+// envelopeWriter holds the JSON response until the handler returns, to wrap it whole. A Flush streams
+// the response as is, as do responses whose headers go out before any body.
+type envelopeWriter struct {
+	gin.ResponseWriter
+	buffer      []byte
+	passthrough bool
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	w.buffer = append(w.buffer, data...)
+	return len(data), nil
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers: bodiless responses and aborts are sent as is
+func (w *envelopeWriter) WriteHeaderNow() {
+	if len(w.buffer) == 0 {
+		w.passthrough = true
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written reports held bytes as written, as the handler already produced them
+func (w *envelopeWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush streams: the held bytes are sent unwrapped and the rest of the response goes straight through
+func (w *envelopeWriter) Flush() {
+	w.passthrough = true
+	if err := w.release(w.buffer); err != nil {
+		LogVerbose("⚠️  @Envelope: %v", err)
+	}
+	w.buffer = nil
+	w.ResponseWriter.Flush()
+}
+
+// release writes body to the client
+func (w *envelopeWriter) release(body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
+
+// close wraps the held response, or sends it as is when it is not JSON or not enveloped
+func (w *envelopeWriter) close(c *gin.Context, config EnvelopeConfig) {
+	if w.passthrough {
+		return
+	}
+	body := w.buffer
+	w.buffer = nil
+
+	if key := envelopeMember(w.Status(), config); key != "" && isJSONContentType(w.Header().Get("Content-Type")) && json.Valid(body) {
+		meta, _ := c.Get(envelopeMetaKey)
+		enveloped, err := envelopeBody(key, body, meta)
+		if err != nil {
+			LogVerbose("⚠️  @Envelope: %v", err)
+		} else {
+			body = enveloped
+			w.Header().Del("Content-Length")
+		}
+	}
+	if err := w.release(body); err != nil {
+		LogVerbose("⚠️  @Envelope: %v", err)
+	}
+}
+
+// envelopeMember member holding a response of status: data for 2xx, error for 4xx and 5xx
+func envelopeMember(status int, config EnvelopeConfig) string {
+	switch {
+	case status >= 200 && status < 300 && status != http.StatusNoContent:
+		return "data"
+	case status >= 400 && !config.SkipErrors:
+		return "error"
+	}
+	return ""
+}
+
+// isJSONContentType reports whether contentType is application/json or a +json type
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// envelopeBody wraps the JSON body under key, with meta when the handler set it
+func envelopeBody(key string, body []byte, meta interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"`)
+	buf.WriteString(key)
+	buf.WriteString(`":`)
+	buf.Write(bytes.TrimSpace(body))
+	if values, ok := meta.(map[string]interface{}); ok && len(values) > 0 {
+		encoded, err := jsonMarshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding meta: %v", err)
+		}
+		buf.WriteString(`,"meta":`)
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// EnvelopeMiddleware wraps the JSON responses of a route (@Envelope) as {"data": ..., "meta": ...}, and
+// error responses as {"error": ..., "meta": ...} unless skip_errors. meta is set with SetEnvelopeMeta.
+// Bodiless, non-JSON and streamed (flushed) responses are sent as is. When envelope.enabled also wraps
+// every route, the response is wrapped once, with the settings of the route.
+func EnvelopeMiddleware(config EnvelopeConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings := config
+		settings.SkipErrors = config.SkipErrors || envelopeConfig().SkipErrors
+
+		if outer, ok := c.Get(envelopeKey); ok {
+			if outerSettings, ok := outer.(*EnvelopeConfig); ok {
+				*outerSettings = settings
+				c.Next()
+				return
+			}
+		}
+
+		c.Set(envelopeKey, &settings)
+		writer := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// On a panic the held bytes are dropped, so the recovery can still answer
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+		writer.close(c, settings)
+	}
+}
+
+// createEnvelopeMiddleware creates response envelope middleware
+func createEnvelopeMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseEnvelopeArgs(args)
+	if err != nil {
+		// Rejected during generation; invalid hand-written calls use the global settings
+		LogSilent("⚠️  %v", err)
+		config = EnvelopeConfig{}
+	}
+	return EnvelopeMiddleware(config)
+}
+
+// routeEnvelope envelope of a documented route: its @Envelope, or the envelope section when enabled
+func routeEnvelope(route *RouteEntry, config *Config) (EnvelopeConfig, bool) {
+	global := envelopeConfig()
+	if config != nil {
+		global = config.Envelope
+	}
+	for _, mw := range route.MiddlewareInfo {
+		if mw.Name != "Envelope" {
+			continue
+		}
+		routeConfig := EnvelopeConfig{SkipErrors: global.SkipErrors}
+		if skip, err := strconv.ParseBool(fmt.Sprint(mw.Args["skip_errors"])); err == nil && skip {
+			routeConfig.SkipErrors = true
+		}
+		return routeConfig, true
+	}
+	return global, global.Enabled
+}
+
+// envelopeOperation documents the enveloped responses of an operation: the JSON schemas and examples of
+// its responses move under data or error, next to an optional meta object
+func envelopeOperation(operation *OpenAPIOperation, config EnvelopeConfig) {
+	for code, response := range operation.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue
+		}
+		key := envelopeMember(status, config)
+		if key == "" {
+			continue
+		}
+		for contentType, media := range response.Content {
+			if !isJSONContentType(contentType) {
+				continue
+			}
+			wrapped := media.Schema
+			if wrapped == nil {
+				wrapped = &OpenAPISchema{}
+			}
+			media.Schema = &OpenAPISchema{
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					key:    wrapped,
+					"meta": {Type: "object", AdditionalProperties: true},
+				},
+				Required: []string{key},
+			}
+			if media.Example != nil {
+				media.Example = envelopeExample(key, media.Example)
+			}
+			for name, example := range media.Examples {
+				example.Value = envelopeExample(key, example.Value)
+				media.Examples[name] = example
+			}
+			response.Content[contentType] = media
+		}
+		operation.Responses[code] = response
+	}
+	operation.Extensions[EnvelopeExtension] = true
+}
+
+// envelopeExample wraps an example under key; examples written as JSON text are decoded first
+func envelopeExample(key string, example interface{}) interface{} {
+	if text, ok := example.(string); ok {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+			example = decoded
+		}
+	}
+	return map[string]interface{}{key: example}
+}
+
+// isEnvelopedOperation reports whether the SDKs unwrap the data of the responses of an operation
+func isEnvelopedOperation(operation *OpenAPIOperation) bool {
+	enveloped, _ := operation.Extensions[EnvelopeExtension].(bool)
+	return enveloped
+}
+
+// sdkResultSchema JSON schema of what an SDK method returns: the success response, or its data when
+// enveloped; with @FieldACL the unrestricted schema, whose restricted fields may be absent
+func sdkResultSchema(operation *OpenAPIOperation) *OpenAPISchema {
+	if schema, ok := operation.Extensions[FieldACLExtension].(*OpenAPISchema); ok {
+		return schema
+	}
+	schema := sdkSuccessSchema(operation.Responses)
+	if schema == nil || !isEnvelopedOperation(operation) {
+		return schema
+	}
+	return unwrapSchema(schema.Properties["data"])
+}
@@ -0,0 +1,173 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envelopeTestRouter router with JSON, error, text, bodiless and streamed routes behind middlewares
+func envelopeTestRouter(middlewares ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares...)
+	router.GET("/users", func(c *gin.Context) {
+		SetEnvelopeMeta(c, "total", 2)
+		c.JSON(http.StatusOK, []gin.H{{"id": 1}, {"id": 2}})
+	})
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"code": "not_found"})
+	})
+	router.GET("/health", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	router.DELETE("/users/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/export", func(c *gin.Context) {
+		_ = StreamJSON(c, http.StatusOK, seqOf([]int{1, 2, 3}, nil))
+	})
+	return router
+}
+
+func envelopeRequest(router http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, http.NoBody))
+	return w
+}
+
+func TestEnvelopeMiddleware(t *testing.T) {
+	router := envelopeTestRouter(EnvelopeMiddleware(EnvelopeConfig{}))
+
+	w := envelopeRequest(router, http.MethodGet, "/users")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": [{"id": 1}, {"id": 2}], "meta": {"total": 2}}`, w.Body.String())
+
+	w = envelopeRequest(router, http.MethodGet, "/users/7")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error": {"code": "not_found"}}`, w.Body.String())
+
+	assert.Equal(t, "ok", envelopeRequest(router, http.MethodGet, "/health").Body.String(), "non-JSON responses are sent as is")
+	assert.Equal(t, http.StatusNoContent, envelopeRequest(router, http.MethodDelete, "/users/7").Code)
+	assert.Equal(t, "[1,2,3]", envelopeRequest(router, http.MethodGet, "/export").Body.String(), "streamed responses are sent as is")
+}
+
+func TestEnvelopeMiddleware_SkipErrors(t *testing.T) {
+	w := envelopeRequest(envelopeTestRouter(EnvelopeMiddleware(EnvelopeConfig{SkipErrors: true})), http.MethodGet, "/users/7")
+	assert.JSONEq(t, `{"code": "not_found"}`, w.Body.String())
+
+	defer envelopeSettings.Store(nil)
+	ConfigureEnvelope(EnvelopeConfig{SkipErrors: true})
+	w = envelopeRequest(envelopeTestRouter(EnvelopeMiddleware(EnvelopeConfig{})), http.MethodGet, "/users/7")
+	assert.JSONEq(t, `{"code": "not_found"}`, w.Body.String(), "routes inherit envelope.skip_errors")
+}
+
+func TestEnvelopeMiddleware_GlobalAndRoute(t *testing.T) {
+	// The global layer and @Envelope wrap once, with the settings of the route
+	router := envelopeTestRouter(EnvelopeMiddleware(EnvelopeConfig{}), EnvelopeMiddleware(EnvelopeConfig{SkipErrors: true}))
+
+	assert.JSONEq(t, `{"data": [{"id": 1}, {"id": 2}], "meta": {"total": 2}}`, envelopeRequest(router, http.MethodGet, "/users").Body.String())
+	assert.JSONEq(t, `{"code": "not_found"}`, envelopeRequest(router, http.MethodGet, "/users/7").Body.String())
+}
+
+func TestParseEnvelopeArgs(t *testing.T) {
+	config, err := parseEnvelopeArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, EnvelopeConfig{}, config)
+
+	config, err = parseEnvelopeArgs([]string{"skip_errors=true"})
+	require.NoError(t, err)
+	assert.True(t, config.SkipErrors)
+
+	for _, args := range [][]string{{"skip_errors=maybe"}, {"key=data"}, {"data"}} {
+		_, err := parseEnvelopeArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+// envelopeTestMetas a route with @Envelope and one without
+func envelopeTestMetas() []*RouteMeta {
+	return []*RouteMeta{
+		{
+			Method: "GET", Path: "/orders/{id}", FuncName: "GetOrder",
+			MiddlewareInfo: []MiddlewareInfo{{Name: "Envelope", Args: map[string]interface{}{}}},
+			Responses: []ResponseInfo{
+				{Code: "200", Description: "Order", Type: "object", Example: `{"id": 1}`},
+				{Code: "404", Description: "Not found", Type: "object"},
+			},
+		},
+		{
+			Method: "GET", Path: "/health", FuncName: "Health",
+			Responses: []ResponseInfo{{Code: "200", Description: "OK", Type: "object"}},
+		},
+	}
+}
+
+func TestEnvelopeOperation(t *testing.T) {
+	spec := GenerateOpenAPISpecFromMeta(DefaultConfig(), envelopeTestMetas())
+
+	operation := spec.Paths["/orders/{id}"]["get"]
+	require.NotNil(t, operation)
+	assert.True(t, isEnvelopedOperation(operation))
+	media := operation.Responses["200"].Content["application/json"]
+	assert.Equal(t, []string{"data"}, media.Schema.Required)
+	assert.Equal(t, "object", media.Schema.Properties["data"].Type)
+	assert.Contains(t, media.Schema.Properties, "meta")
+	assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{"id": float64(1)}}, media.Example)
+	assert.Contains(t, operation.Responses["404"].Content["application/json"].Schema.Properties, "error")
+
+	health := spec.Paths["/health"]["get"]
+	assert.False(t, isEnvelopedOperation(health))
+	assert.NotContains(t, health.Responses["200"].Content["application/json"].Schema.Properties, "data")
+
+	// envelope.enabled documents every route, skip_errors leaves the errors as they are
+	config := DefaultConfig()
+	config.Envelope = EnvelopeConfig{Enabled: true, SkipErrors: true}
+	spec = GenerateOpenAPISpecFromMeta(config, envelopeTestMetas())
+	assert.True(t, isEnvelopedOperation(spec.Paths["/health"]["get"]))
+	assert.NotContains(t, spec.Paths["/orders/{id}"]["get"].Responses["404"].Content["application/json"].Schema.Properties, "error")
+}
+
+func TestSDKGenerators_Envelope(t *testing.T) {
+	spec := modelsSDKSpec()
+	for _, operation := range []*OpenAPIOperation{spec.Paths["/users"]["get"], spec.Paths["/users/{id}"]["get"]} {
+		operation.Extensions = make(map[string]interface{})
+		envelopeOperation(operation, EnvelopeConfig{})
+	}
+	assert.Equal(t, "#/components/schemas/User", sdkResultSchema(spec.Paths["/users/{id}"]["get"]).Ref)
+
+	dir := t.TempDir()
+	config := &ClientSDKConfig{OutputDir: dir, PackageName: "shopapi"}
+	for _, generator := range []SDKGenerator{&GoSDKGenerator{}, &PythonSDKGenerator{}, &JavaScriptSDKGenerator{}, &TypeScriptSDKGenerator{}} {
+		require.NoError(t, generator.Generate(spec, config), generator.GetLanguage())
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	goCode := read("go/client.go")
+	_, err := parser.ParseFile(token.NewFileSet(), "client.go", goCode, parser.AllErrors)
+	require.NoError(t, err)
+	assert.Contains(t, goCode, "Data *User `json:\"data\"`")
+	assert.Contains(t, goCode, "func (c *Client) CreateUsers(ctx context.Context, requestBody CreateUserRequest) (*User, error) {")
+
+	assert.Contains(t, read("python/client.py"), "return User.from_dict(response.json()['data'])")
+	assert.Contains(t, read("javascript/client.js"), "return (await response.json()).data;")
+	tsCode := read("typescript/client.ts")
+	assert.Contains(t, tsCode, "return (await response.json()).data as User[];")
+	assert.Contains(t, tsCode, "return await response.json() as User;", "operations without an envelope are returned as is")
+}
+
+func TestLoadConfig_Envelope(t *testing.T) {
+	path := t.TempDir() + "/.deco.yaml"
+	writeTestFile(t, path, "version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\nenvelope:\n  enabled: true\n  skip_errors: true\n")
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, EnvelopeConfig{Enabled: true, SkipErrors: true}, config.Envelope)
+}
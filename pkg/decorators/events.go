@@ -0,0 +1,240 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CloudEvents types emitted by the framework
+const (
+	EventEngineStarted        = "io.deco.engine.started"
+	EventEngineStopping       = "io.deco.engine.stopping"
+	EventRouteDisabled        = "io.deco.route.disabled"
+	EventConfigReloaded       = "io.deco.config.reloaded"
+	EventCircuitBreakerOpened = "io.deco.circuitbreaker.opened"
+)
+
+// CloudEventsContentType content type of the CloudEvents structured mode
+const CloudEventsContentType = "application/cloudevents+json"
+
+// CloudEvent event in the CloudEvents 1.0 JSON format
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+}
+
+// EventSink destination of the framework events
+type EventSink interface {
+	Send(ctx context.Context, event *CloudEvent) error
+}
+
+// EventSinkFunc adapts a function to EventSink
+type EventSinkFunc func(ctx context.Context, event *CloudEvent) error
+
+// Send calls f(ctx, event)
+func (f EventSinkFunc) Send(ctx context.Context, event *CloudEvent) error {
+	return f(ctx, event)
+}
+
+// HTTPEventSink posts events in structured mode to an HTTP endpoint
+// (Knative broker, Argo Events, EventBridge API destination...)
+type HTTPEventSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPEventSink creates a sink posting to url with the given extra headers
+func NewHTTPEventSink(url string, headers map[string]string, timeout time.Duration) *HTTPEventSink {
+	return &HTTPEventSink{url: url, headers: headers, client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts an event; any non-2xx response is an error
+func (s *HTTPEventSink) Send(ctx context.Context, event *CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", CloudEventsContentType)
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink answered %s", resp.Status)
+	}
+	return nil
+}
+
+// eventDispatcher delivers events to a sink in the background; events are dropped
+// rather than blocking the caller when the queue is full
+type eventDispatcher struct {
+	sink   EventSink
+	source string
+	queue  chan *CloudEvent
+	done   chan struct{}
+}
+
+// active dispatcher, nil while events are disabled
+var (
+	eventDispatcherInstance *eventDispatcher
+	eventsMutex             sync.RWMutex
+)
+
+// validate checks the events configuration
+func (e EventsConfig) validate() error {
+	if e.Timeout != "" {
+		if timeout, err := time.ParseDuration(e.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid events.timeout '%s'", e.Timeout)
+		}
+	}
+	if e.QueueSize < 0 {
+		return fmt.Errorf("invalid events.queue_size %d", e.QueueSize)
+	}
+	if !e.Enabled {
+		return nil
+	}
+	if sink, err := url.Parse(e.Sink); err != nil || (sink.Scheme != "http" && sink.Scheme != "https") || sink.Host == "" {
+		return fmt.Errorf("events.sink must be an http(s) URL, found '%s'", e.Sink)
+	}
+	return nil
+}
+
+// ConfigureEvents starts publishing framework events to the configured HTTP sink;
+// a disabled configuration stops any active sink
+func ConfigureEvents(config EventsConfig) error {
+	if !config.Enabled {
+		StopEvents()
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	timeout := 5 * time.Second
+	if config.Timeout != "" {
+		timeout, _ = time.ParseDuration(config.Timeout)
+	}
+	UseEventSink(NewHTTPEventSink(config.Sink, config.Headers, timeout), config.Source, config.QueueSize)
+	return nil
+}
+
+// UseEventSink publishes framework events to sink, replacing the active one. source is the
+// CloudEvents source attribute; queueSize bounds the events waiting for delivery (0 keeps 1000).
+func UseEventSink(sink EventSink, source string, queueSize int) {
+	if source == "" {
+		source = "gin-decorators"
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	dispatcher := &eventDispatcher{
+		sink:   sink,
+		source: source,
+		queue:  make(chan *CloudEvent, queueSize),
+		done:   make(chan struct{}),
+	}
+	go dispatcher.run()
+
+	eventsMutex.Lock()
+	previous := eventDispatcherInstance
+	eventDispatcherInstance = dispatcher
+	eventsMutex.Unlock()
+
+	previous.stop()
+}
+
+// StopEvents delivers the queued events and stops publishing
+func StopEvents() {
+	eventsMutex.Lock()
+	dispatcher := eventDispatcherInstance
+	eventDispatcherInstance = nil
+	eventsMutex.Unlock()
+
+	dispatcher.stop()
+}
+
+// EmitEvent publishes an event of eventType about subject (a route, a service...) to the active
+// sink. Besides the framework events, applications may emit EventRouteDisabled and
+// EventConfigReloaded, or their own types. Without a sink, EmitEvent does nothing.
+func EmitEvent(eventType, subject string, data interface{}) {
+	eventsMutex.RLock()
+	defer eventsMutex.RUnlock()
+
+	dispatcher := eventDispatcherInstance
+	if dispatcher == nil {
+		return
+	}
+
+	event := &CloudEvent{
+		SpecVersion: "1.0",
+		ID:          generateEventID(),
+		Source:      dispatcher.source,
+		Type:        eventType,
+		Subject:     subject,
+		Time:        time.Now().UTC(),
+		Data:        data,
+	}
+	if data != nil {
+		event.DataContentType = "application/json"
+	}
+
+	select {
+	case dispatcher.queue <- event:
+	default:
+		LogSilent("⚠️  Event queue full, dropping %s event", eventType)
+	}
+}
+
+// run delivers queued events until the queue is closed
+func (d *eventDispatcher) run() {
+	defer close(d.done)
+	for event := range d.queue {
+		if err := d.sink.Send(context.Background(), event); err != nil {
+			LogSilent("⚠️  Error sending %s event: %v", event.Type, err)
+		}
+	}
+}
+
+// stop closes the queue and waits for the pending deliveries
+func (d *eventDispatcher) stop() {
+	if d == nil {
+		return
+	}
+	close(d.queue)
+	<-d.done
+}
+
+// generateEventID generates a random event id
+func generateEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
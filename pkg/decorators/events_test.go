@@ -0,0 +1,110 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectEvents installs a sink recording every delivered event
+func collectEvents(t *testing.T) func() []*CloudEvent {
+	var (
+		mu     sync.Mutex
+		events []*CloudEvent
+	)
+	UseEventSink(EventSinkFunc(func(_ context.Context, event *CloudEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}), "orders-api", 0)
+	t.Cleanup(StopEvents)
+
+	return func() []*CloudEvent {
+		StopEvents()
+		mu.Lock()
+		defer mu.Unlock()
+		return events
+	}
+}
+
+func TestEmitEvent(t *testing.T) {
+	// Without a sink events are discarded
+	EmitEvent(EventConfigReloaded, "", nil)
+
+	delivered := collectEvents(t)
+	EmitEvent(EventRouteDisabled, "GET /orders", map[string]interface{}{"reason": "maintenance"})
+
+	events := delivered()
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "orders-api", event.Source)
+	assert.Equal(t, EventRouteDisabled, event.Type)
+	assert.Equal(t, "GET /orders", event.Subject)
+	assert.Equal(t, "application/json", event.DataContentType)
+	assert.Len(t, event.ID, 32)
+}
+
+func TestCircuitBreaker_EmitsOpened(t *testing.T) {
+	delivered := collectEvents(t)
+
+	cb := createCircuitBreaker(&ProxyConfig{Service: "payments", FailureThreshold: 2, CircuitBreaker: "1s"}).(*CircuitBreakerImpl)
+	cb.RecordFailure()
+	cb.RecordFailure()
+	cb.RecordFailure() // already open
+
+	events := delivered()
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCircuitBreakerOpened, events[0].Type)
+	assert.Equal(t, "payments", events[0].Subject)
+}
+
+func TestHTTPEventSink(t *testing.T) {
+	received := make(chan CloudEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, CloudEventsContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var event CloudEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	require.NoError(t, ConfigureEvents(EventsConfig{
+		Enabled: true,
+		Sink:    server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}))
+	EmitEvent(EventEngineStarted, "", map[string]interface{}{"routes": 3})
+	StopEvents()
+
+	select {
+	case event := <-received:
+		assert.Equal(t, EventEngineStarted, event.Type)
+		assert.Equal(t, "gin-decorators", event.Source)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	sink := NewHTTPEventSink(missing.URL, nil, time.Second)
+	assert.Error(t, sink.Send(context.Background(), &CloudEvent{Type: EventEngineStarted}))
+}
+
+func TestEventsConfigValidate(t *testing.T) {
+	assert.NoError(t, EventsConfig{}.validate())
+	assert.NoError(t, EventsConfig{Enabled: true, Sink: "https://broker.internal/events"}.validate())
+	assert.Error(t, EventsConfig{Enabled: true}.validate())
+	assert.Error(t, EventsConfig{Enabled: true, Sink: "broker:8080"}.validate())
+	assert.Error(t, EventsConfig{Timeout: "soon"}.validate())
+}
@@ -0,0 +1,406 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldACLExtension operation extension holding the unrestricted success schema of a @FieldACL route,
+// returned by the SDK methods while the responses document one variant per role
+const FieldACLExtension = "x-field-acl"
+
+// FieldACLRule response field readable only with one of Roles, as a JSON path rule (see Redactor)
+type FieldACLRule struct {
+	Path  string
+	Roles []string // roles or scopes of the principal, any of them grants access
+}
+
+// FieldACLConfig fields restricted by @FieldACL
+type FieldACLConfig struct {
+	Rules []FieldACLRule
+}
+
+// parseFieldACLArgs parses the rules generated from the acl tags: $.salary=admin|hr, $.*.email=admin
+func parseFieldACLArgs(args []string) (FieldACLConfig, error) {
+	var config FieldACLConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		path, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@FieldACL: invalid rule '%s' (expected path=role|role)", arg)
+		}
+		path = strings.TrimSpace(path)
+		if _, err := parseRedactionRule(path); err != nil {
+			return config, fmt.Errorf("@FieldACL: %v", err)
+		}
+
+		var roles []string
+		for _, role := range strings.Split(MarkerValue(value), "|") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+		if len(roles) == 0 {
+			return config, fmt.Errorf("@FieldACL: rule '%s' has no roles", arg)
+		}
+		config.Rules = append(config.Rules, FieldACLRule{Path: path, Roles: roles})
+	}
+	return config, nil
+}
+
+// validateFieldACLMarker rejects arguments on @FieldACL(): its rules come from the acl tags of the response types
+func validateFieldACLMarker(args []string) error {
+	for _, arg := range args {
+		if strings.TrimSpace(arg) != "" {
+			return fmt.Errorf("@FieldACL takes no arguments (restrict fields with the acl tag, e.g. acl:\"admin\")")
+		}
+	}
+	return nil
+}
+
+// processFieldACLMarker adds the @FieldACL middleware once the route's @Response types are known: fields
+// tagged acl:"..." in their schemas become the rules baked into the call
+func processFieldACLMarker(route *RouteMeta, responses []ResponseInfo, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo) {
+	var args []string
+	for _, response := range responses {
+		for _, rule := range fieldACLSchemaRules(response.Type, "$", make(map[string]bool)) {
+			arg := rule.Path + "=" + strings.Join(rule.Roles, "|")
+			if !contains(args, arg) {
+				args = append(args, arg)
+			}
+		}
+	}
+	if len(args) == 0 {
+		LogSilent("⚠️  %s: @FieldACL found no fields (tag them with acl:\"role\")", route.FuncName)
+	}
+
+	*middlewareCalls = append(*middlewareCalls, fmt.Sprintf(`deco.CreateFieldACLMiddleware(%q)`, strings.Join(args, ",")))
+	*middlewareInfo = append(*middlewareInfo, MiddlewareInfo{
+		Name:        "FieldACL",
+		Args:        parseArgsToMap(args),
+		Description: getMiddlewareDescription("FieldACL"),
+	})
+}
+
+// fieldACLSchemaRules anchored rules of the restricted fields of a Go type ("User", "[]User"), following nested schemas
+func fieldACLSchemaRules(typeName, path string, visited map[string]bool) []FieldACLRule {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if itemType, isSlice := strings.CutPrefix(typeName, "[]"); isSlice {
+		return fieldACLSchemaRules(itemType, path+".*", visited)
+	}
+	if _, name, qualified := strings.Cut(typeName, "."); qualified {
+		typeName = name
+	}
+
+	schema := GetSchema(typeName)
+	if schema == nil || visited[typeName] {
+		return nil
+	}
+	visited[typeName] = true
+	defer delete(visited, typeName)
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []FieldACLRule
+	for _, name := range names {
+		property := schema.Properties[name]
+		fieldPath := path + "." + name
+		if len(property.ACL) > 0 {
+			rules = append(rules, FieldACLRule{Path: fieldPath, Roles: property.ACL})
+			continue
+		}
+		if property.GoType != "" {
+			rules = append(rules, fieldACLSchemaRules(property.GoType, fieldPath, visited)...)
+		}
+	}
+	return rules
+}
+
+// fieldACLRule compiled FieldACLRule
+type fieldACLRule struct {
+	path  []string
+	roles []string
+}
+
+// FieldACLMiddleware strips the restricted fields from JSON responses unless the principal holds one of
+// their roles or scopes: the roles set by @Auth (user_roles, user_role) and the scope/scp claims of the
+// token. Anonymous requests get none of the restricted fields.
+func FieldACLMiddleware(config FieldACLConfig) gin.HandlerFunc {
+	rules := make([]fieldACLRule, 0, len(config.Rules))
+	for _, rule := range config.Rules {
+		if segments, err := parseRedactionRule(rule.Path); err == nil {
+			rules = append(rules, fieldACLRule{path: segments, roles: rule.Roles})
+		}
+	}
+
+	return func(c *gin.Context) {
+		if len(rules) == 0 {
+			c.Next()
+			return
+		}
+
+		// The response is buffered (without a size limit) so fields can be removed before it is sent
+		original := c.Writer
+		writer := &sizeLimitBufferWriter{ResponseWriter: original, limit: math.MaxInt64, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.streaming {
+			LogNormal("⚠️  Streamed response of %s %s was sent without @FieldACL processing", c.Request.Method, getEndpointPattern(c))
+			return
+		}
+		if !writer.wroteHeader && writer.buffer.Len() == 0 {
+			return
+		}
+
+		body := writer.buffer.Bytes()
+		grants := principalGrants(c)
+		var hidden [][]string
+		for _, rule := range rules {
+			if !containsAny(grants, rule.roles) {
+				hidden = append(hidden, rule.path)
+			}
+		}
+		if len(hidden) > 0 && strings.Contains(original.Header().Get("Content-Type"), "json") {
+			if document, ok := decodeSensitiveJSON(body); ok {
+				if stripped, err := json.Marshal(stripFields(document, nil, hidden)); err == nil {
+					body = stripped
+					original.Header().Del("Content-Length")
+				}
+			}
+		}
+
+		original.WriteHeader(writer.status)
+		original.WriteHeaderNow()
+		if len(body) > 0 {
+			_, _ = original.Write(body)
+		}
+	}
+}
+
+// principalGrants roles and scopes of the principal whose token @Auth verified. Roles set without verified
+// claims (@Auth without auth.jwt accepts any bearer token) grant nothing, so restricted fields stay hidden.
+func principalGrants(c *gin.Context) []string {
+	claims, ok := JWTClaimsFrom(c)
+	if !ok {
+		return nil
+	}
+
+	var grants []string
+	if roles, ok := c.Get("user_roles"); ok {
+		if list, ok := roles.([]string); ok {
+			grants = append(grants, list...)
+		}
+	}
+	if role := c.GetString("user_role"); role != "" {
+		grants = append(grants, role)
+	}
+	grants = append(grants, claims.Strings("scope")...)
+	grants = append(grants, claims.Strings("scp")...)
+	return grants
+}
+
+// stripFields removes the object members selected by rules from a decoded JSON value
+func stripFields(value interface{}, path []string, rules [][]string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			childPath := append(path[:len(path):len(path)], key)
+			if matchesAnyRule(rules, childPath) {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = stripFields(child, childPath, rules)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = stripFields(child, append(path[:len(path):len(path)], strconv.Itoa(i)), rules)
+		}
+	}
+	return value
+}
+
+// matchesAnyRule reports whether path is selected by one of rules
+func matchesAnyRule(rules [][]string, path []string) bool {
+	for _, rule := range rules {
+		if matchRedactionPath(rule, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// createFieldACLMiddleware creates field access control middleware
+func createFieldACLMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseFieldACLArgs(args)
+	if err != nil {
+		// Generated rules are always valid; a broken hand-written call fails closed instead of leaking fields
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "field_acl_unavailable",
+				"message": "Response field access rules could not be applied",
+			})
+		}
+	}
+	return FieldACLMiddleware(config)
+}
+
+// hasFieldACL reports whether a documented route has @FieldACL
+func hasFieldACL(route *RouteEntry) bool {
+	for _, mw := range route.MiddlewareInfo {
+		if mw.Name == "FieldACL" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldACLOperation documents the responses of a @FieldACL route as one variant per role: each variant
+// is a component schema without the properties the role may not read ("UserAsAdmin"), next to the
+// variant of principals without any of the roles ("UserRestricted")
+func fieldACLOperation(operation *OpenAPIOperation, components *OpenAPIComponents) {
+	if success := sdkSuccessSchema(operation.Responses); success != nil {
+		operation.Extensions[FieldACLExtension] = success
+	}
+
+	for code, response := range operation.Responses {
+		for contentType, media := range response.Content {
+			if !isJSONContentType(contentType) || media.Schema == nil {
+				continue
+			}
+			roles := make(map[string]bool)
+			collectACLRoles(media.Schema, components, roles, make(map[string]bool))
+			if len(roles) == 0 {
+				continue
+			}
+
+			names := make([]string, 0, len(roles)+1)
+			for role := range roles {
+				names = append(names, role)
+			}
+			sort.Strings(names)
+			names = append(names, "") // principals without any of the roles
+
+			var variants []*OpenAPISchema
+			for _, role := range names {
+				variant := aclSchemaVariant(media.Schema, role, components, make(map[string]bool))
+				if !containsSchema(variants, variant) {
+					variants = append(variants, variant)
+				}
+			}
+			media.Schema = &OpenAPISchema{OneOf: variants}
+			response.Content[contentType] = media
+		}
+		operation.Responses[code] = response
+	}
+}
+
+// collectACLRoles adds the roles of the restricted properties reachable from schema
+func collectACLRoles(schema *OpenAPISchema, components *OpenAPIComponents, roles, visiting map[string]bool) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if visiting[name] {
+			return
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		collectACLRoles(components.Schemas[name], components, roles, visiting)
+		return
+	}
+	collectACLRoles(schema.Items, components, roles, visiting)
+	for _, property := range schema.Properties {
+		for _, role := range property.ACL {
+			roles[role] = true
+		}
+		collectACLRoles(property, components, roles, visiting)
+	}
+}
+
+// aclSchemaVariant schema as seen by a principal holding role (none when empty), or schema itself when
+// nothing is hidden. Components with hidden properties are copied to a variant component.
+func aclSchemaVariant(schema *OpenAPISchema, role string, components *OpenAPIComponents, visiting map[string]bool) *OpenAPISchema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		component := components.Schemas[name]
+		if component == nil || visiting[name] {
+			return schema
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		variant := aclSchemaVariant(component, role, components, visiting)
+		if variant == component {
+			return schema
+		}
+		variantName := name + "Restricted"
+		variant.Description = fmt.Sprintf("%s without the fields restricted by role", name)
+		if role != "" {
+			variantName = name + "As" + sdkIdentifier(role)
+			variant.Description = fmt.Sprintf("%s as seen with the %s role", name, role)
+		}
+		if _, exists := components.Schemas[variantName]; !exists {
+			components.Schemas[variantName] = variant
+		}
+		return &OpenAPISchema{Ref: "#/components/schemas/" + variantName}
+	}
+
+	variant := *schema
+	changed := false
+	if schema.Items != nil {
+		variant.Items = aclSchemaVariant(schema.Items, role, components, visiting)
+		changed = variant.Items != schema.Items
+	}
+	if len(schema.Properties) > 0 {
+		variant.Properties = make(map[string]*OpenAPISchema, len(schema.Properties))
+		variant.Required = nil
+		for name, property := range schema.Properties {
+			if len(property.ACL) > 0 && (role == "" || !contains(property.ACL, role)) {
+				changed = true
+				continue
+			}
+			variant.Properties[name] = aclSchemaVariant(property, role, components, visiting)
+			changed = changed || variant.Properties[name] != property
+		}
+		for _, name := range schema.Required {
+			if _, kept := variant.Properties[name]; kept {
+				variant.Required = append(variant.Required, name)
+			}
+		}
+	}
+	if !changed {
+		return schema
+	}
+	return &variant
+}
+
+// containsSchema reports whether schemas holds schema, or a reference to the same component
+func containsSchema(schemas []*OpenAPISchema, schema *OpenAPISchema) bool {
+	for _, existing := range schemas {
+		if existing == schema || (existing.Ref != "" && existing.Ref == schema.Ref) {
+			return true
+		}
+	}
+	return false
+}
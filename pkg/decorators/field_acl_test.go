@@ -0,0 +1,144 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseFieldACLRoutes parses an Employee schema with acl tags and a @FieldACL handler listing employees
+func parseFieldACLRoutes(t *testing.T) []*RouteMeta {
+	ClearSchemas()
+	t.Cleanup(ClearSchemas)
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Schema()
+type Employee struct {
+	ID      int      ` + "`json:\"id\"`" + `
+	Name    string   ` + "`json:\"name\"`" + `
+	Salary  float64  ` + "`json:\"salary\" acl:\"admin,hr\"`" + `
+	SSN     string   ` + "`json:\"ssn\" acl:\"admin\"`" + `
+	Manager *Manager ` + "`json:\"manager\"`" + `
+}
+
+// @Schema()
+type Manager struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Phone string ` + "`json:\"phone\" acl:\"admin,hr\"`" + `
+}
+
+// @Route("GET", "/employees")
+// @FieldACL()
+// @Response(code=200, description="Employees", type="[]Employee")
+func ListEmployees(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "employees.go"), []byte(source), 0o600))
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	return routes
+}
+
+func TestFieldACLMarker(t *testing.T) {
+	routes := parseFieldACLRoutes(t)
+
+	assert.Equal(t, []string{"admin", "hr"}, GetSchema("Employee").Properties["salary"].ACL)
+	assert.Equal(t, []string{"admin", "hr"}, convertSchemaInfoToOpenAPISchema(GetSchema("Employee")).Properties["salary"].ACL)
+
+	require.Len(t, routes[0].MiddlewareCalls, 1)
+	assert.Equal(t, `deco.CreateFieldACLMiddleware("$.*.manager.phone=admin|hr,$.*.salary=admin|hr,$.*.ssn=admin")`, routes[0].MiddlewareCalls[0])
+	assert.Equal(t, "FieldACL", routes[0].MiddlewareInfo[0].Name)
+
+	assert.Error(t, validateArgumentValues("FieldACL", []string{"salary=admin"}))
+}
+
+func TestParseFieldACLArgs(t *testing.T) {
+	config, err := parseFieldACLArgs([]string{"$.salary=admin|hr", "$.*.ssn=admin"})
+	require.NoError(t, err)
+	assert.Equal(t, FieldACLConfig{Rules: []FieldACLRule{{Path: "$.salary", Roles: []string{"admin", "hr"}}, {Path: "$.*.ssn", Roles: []string{"admin"}}}}, config)
+
+	for _, args := range [][]string{{"salary"}, {"salary="}, {"$..a=admin"}} {
+		_, err := parseFieldACLArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestFieldACLMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// Stands for @Auth with auth.jwt: verified claims and the roles of the token
+		claims := JWTClaims{}
+		if role := c.GetHeader("X-Role"); role != "" {
+			c.Set("user_roles", []string{role})
+			claims["roles"] = []interface{}{role}
+		}
+		if scope := c.GetHeader("X-Scope"); scope != "" {
+			claims["scope"] = scope
+		}
+		if c.GetHeader("X-Unverified-Role") != "" {
+			// @Auth without auth.jwt sets the decorator's role for any bearer token
+			c.Set("user_role", c.GetHeader("X-Unverified-Role"))
+			return
+		}
+		c.Set("claims", claims)
+	})
+	router.GET("/employees/:id", FieldACLMiddleware(FieldACLConfig{Rules: []FieldACLRule{
+		{Path: "$.salary", Roles: []string{"admin", "hr"}},
+		{Path: "$.ssn", Roles: []string{"admin", "employees:read-pii"}},
+	}}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": 7, "name": "Ana", "salary": 5200, "ssn": "123-45-6789"})
+	})
+
+	send := func(header, value string) string {
+		req := httptest.NewRequest(http.MethodGet, "/employees/7", http.NoBody)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	assert.JSONEq(t, `{"id": 7, "name": "Ana"}`, send("", ""), "anonymous requests get no restricted field")
+	assert.JSONEq(t, `{"id": 7, "name": "Ana", "salary": 5200}`, send("X-Role", "hr"))
+	assert.JSONEq(t, `{"id": 7, "name": "Ana", "salary": 5200, "ssn": "123-45-6789"}`, send("X-Role", "admin"))
+	assert.JSONEq(t, `{"id": 7, "name": "Ana", "ssn": "123-45-6789"}`, send("X-Scope", "profile employees:read-pii"), "token scopes grant access too")
+	assert.JSONEq(t, `{"id": 7, "name": "Ana"}`, send("X-Unverified-Role", "admin"), "roles without verified claims grant nothing")
+}
+
+func TestFieldACLOperation(t *testing.T) {
+	routes := parseFieldACLRoutes(t)
+	spec := GenerateOpenAPISpecFromMeta(DefaultConfig(), routes)
+
+	operation := spec.Paths["/employees"]["get"]
+	require.NotNil(t, operation)
+	variants := operation.Responses["200"].Content["application/json"].Schema.OneOf
+	require.Len(t, variants, 3)
+	assert.Equal(t, "#/components/schemas/Employee", variants[0].Items.Ref, "admin reads every field")
+	assert.Equal(t, "#/components/schemas/EmployeeAsHr", variants[1].Items.Ref)
+	assert.Equal(t, "#/components/schemas/EmployeeRestricted", variants[2].Items.Ref)
+
+	schemas := spec.Components.Schemas
+	assert.Contains(t, schemas["EmployeeAsHr"].Properties, "salary")
+	assert.NotContains(t, schemas["EmployeeAsHr"].Properties, "ssn")
+	assert.NotContains(t, schemas["EmployeeRestricted"].Properties, "salary")
+	assert.Contains(t, schemas["EmployeeRestricted"].Properties, "manager")
+	assert.Contains(t, schemas["Employee"].Properties, "ssn", "the full schema is left as is")
+
+	// SDK methods return the full type
+	result := sdkResultSchema(operation)
+	require.NotNil(t, result)
+	assert.Equal(t, "#/components/schemas/Employee", result.Items.Ref)
+}
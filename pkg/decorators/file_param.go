@@ -0,0 +1,305 @@
+package decorators
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseRequestBodyArgs parses @RequestBody(CreateUserRequest, contentType="multipart/form-data", description="...")
+// into the body parameter of the route
+func parseRequestBodyArgs(args []string) (ParameterInfo, error) {
+	param := ParameterInfo{Name: "body", Location: "body", Required: true}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			if param.Type != "" {
+				return param, fmt.Errorf("@RequestBody: unexpected argument '%s'", arg)
+			}
+			param.Type = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "type":
+			param.Type = value
+		case "contentType":
+			if _, _, err := mime.ParseMediaType(value); err != nil {
+				return param, fmt.Errorf("@RequestBody: invalid contentType '%s'", value)
+			}
+			param.ContentType = value
+		case "description":
+			param.Description = value
+		case "example":
+			param.Example = value
+		default:
+			return param, fmt.Errorf("@RequestBody: unknown argument '%s' (valid: type, contentType, description, example)", key)
+		}
+	}
+
+	if param.Type == "" && param.ContentType == "" {
+		return param, fmt.Errorf("@RequestBody requires a type or a contentType, e.g. @RequestBody(CreateUserRequest)")
+	}
+	return param, nil
+}
+
+// FileParamConfig configuration of @FileParam
+type FileParamConfig struct {
+	Name        string // form field of the file
+	Required    bool
+	Multiple    bool     // accepts several files in the field
+	MaxSize     string   // largest accepted file, e.g. "5MB"; empty for no limit
+	Types       []string // accepted media types, e.g. image/png or image/*; empty accepts any
+	Description string
+}
+
+// parseFileParamArgs parses @FileParam(name="avatar", required=true, max_size="5MB", types="image/png,image/jpeg")
+func parseFileParamArgs(args []string) (FileParamConfig, error) {
+	var config FileParamConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			if config.Name != "" {
+				return config, fmt.Errorf("@FileParam: unexpected argument '%s'", arg)
+			}
+			config.Name = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			config.Name = value
+		case "required", "multiple":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return config, fmt.Errorf("@FileParam: invalid %s '%s' (expected true or false)", strings.TrimSpace(key), value)
+			}
+			if strings.TrimSpace(key) == "required" {
+				config.Required = enabled
+			} else {
+				config.Multiple = enabled
+			}
+		case "max_size":
+			if _, err := ParseByteSize(value); err != nil {
+				return config, fmt.Errorf("@FileParam: invalid max_size '%s': %v", value, err)
+			}
+			config.MaxSize = value
+		case "types":
+			config.Types = splitMarkerList(value)
+			for _, mediaType := range config.Types {
+				if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+					return config, fmt.Errorf("@FileParam: invalid type '%s'", mediaType)
+				}
+			}
+		case "description":
+			config.Description = value
+		default:
+			return config, fmt.Errorf("@FileParam: unknown argument '%s' (valid: name, required, multiple, max_size, types, description)", key)
+		}
+	}
+
+	if config.Name == "" {
+		return config, fmt.Errorf("@FileParam requires a field name, e.g. @FileParam(name=\"avatar\")")
+	}
+	return config, nil
+}
+
+// parameterInfo documents the file as a field of the multipart request body
+func (f FileParamConfig) parameterInfo() ParameterInfo {
+	return ParameterInfo{
+		Name:        f.Name,
+		Type:        "file",
+		Location:    "file",
+		Required:    f.Required,
+		Description: f.Description,
+		Multiple:    f.Multiple,
+		MaxSize:     f.MaxSize,
+		Accept:      strings.Join(f.Types, ","),
+	}
+}
+
+// FileParamMiddleware validates a file field of a multipart request (@FileParam): a missing required
+// file or several files in a single-file field are rejected with 400, a file above max_size with 413
+// and a file of another media type with 415, all with the standard validation response. The media type
+// is sniffed from the content; the declared one is only trusted for unrecognized or plain text content.
+// The parsed form stays available to the handler through c.FormFile and c.MultipartForm.
+func FileParamMiddleware(config FileParamConfig) gin.HandlerFunc {
+	maxSize, _ := ParseByteSize(config.MaxSize) // validated by parseFileParamArgs
+	reject := func(c *gin.Context, status int, field ValidationField) {
+		field.Field = config.Name
+		c.AbortWithStatusJSON(status, ValidationResponse{
+			Error:   "validation_failed",
+			Message: "Invalid file upload",
+			Fields:  []ValidationField{field},
+		})
+	}
+
+	return func(c *gin.Context) {
+		form, err := c.MultipartForm()
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			reject(c, http.StatusBadRequest, ValidationField{Tag: "multipart", Message: fmt.Sprintf("Invalid multipart body: %v", err)})
+			return
+		}
+
+		var files []*multipart.FileHeader
+		if form != nil {
+			files = form.File[config.Name]
+		}
+		switch {
+		case len(files) == 0 && config.Required:
+			reject(c, http.StatusBadRequest, ValidationField{Tag: "required", Message: fmt.Sprintf("File %s is required", config.Name)})
+			return
+		case len(files) > 1 && !config.Multiple:
+			reject(c, http.StatusBadRequest, ValidationField{Tag: "multiple", Message: fmt.Sprintf("File %s accepts a single file", config.Name)})
+			return
+		}
+
+		for _, file := range files {
+			if maxSize > 0 && file.Size > maxSize {
+				reject(c, http.StatusRequestEntityTooLarge, ValidationField{
+					Value:   file.Filename,
+					Tag:     "max_size",
+					Param:   config.MaxSize,
+					Message: fmt.Sprintf("File %s must not exceed %s", config.Name, config.MaxSize),
+				})
+				return
+			}
+			if len(config.Types) == 0 {
+				continue
+			}
+			contentType, err := uploadedContentType(file)
+			if err != nil {
+				reject(c, http.StatusBadRequest, ValidationField{Value: file.Filename, Tag: "multipart", Message: fmt.Sprintf("Unreadable file %s: %v", config.Name, err)})
+				return
+			}
+			if !compressibleType(contentType, config.Types) {
+				reject(c, http.StatusUnsupportedMediaType, ValidationField{
+					Value:   contentType,
+					Tag:     "types",
+					Param:   strings.Join(config.Types, ","),
+					Message: fmt.Sprintf("File %s must be one of %s", config.Name, strings.Join(config.Types, ", ")),
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// uploadedContentType media type of an uploaded file, sniffed from its first bytes. The declared type
+// is used when the content is not recognized, or is plain text declared as another text type.
+func uploadedContentType(file *multipart.FileHeader) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+
+	declared, _, err := mime.ParseMediaType(file.Header.Get("Content-Type"))
+	switch {
+	case err != nil:
+		return sniffed, nil
+	case sniffed == "application/octet-stream",
+		sniffed == "text/plain" && (strings.HasPrefix(declared, "text/") || isJSONContentType(declared)):
+		return declared, nil
+	}
+	return sniffed, nil
+}
+
+// createFileParamMiddleware creates @FileParam middleware
+func createFileParamMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseFileParamArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return FileParamMiddleware(config)
+}
+
+// requestBodyContentType media type of a documented body parameter, JSON unless @RequestBody sets one
+func requestBodyContentType(param ParameterInfo) string {
+	if param.ContentType != "" {
+		return param.ContentType
+	}
+	return "application/json"
+}
+
+// multipartRequestBody documents the @FileParam fields of a route as a multipart/form-data body: each
+// file is a binary property, next to the fields of the body type when there is one
+func multipartRequestBody(body *OpenAPIRequestBody, files []ParameterInfo) *OpenAPIRequestBody {
+	if body == nil {
+		body = &OpenAPIRequestBody{Content: make(map[string]MediaType)}
+	}
+
+	// The fields of the body type are sent as form fields next to the files
+	media, ok := body.Content["multipart/form-data"]
+	if !ok {
+		media = body.Content["application/json"]
+		delete(body.Content, "application/json")
+	}
+
+	fileSchema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	for _, file := range files {
+		property := &OpenAPISchema{Type: "string", Format: "binary"}
+		if file.Multiple {
+			property = &OpenAPISchema{Type: "array", Items: property}
+		}
+		property.Description = fileDescription(file)
+		fileSchema.Properties[file.Name] = property
+		if file.Required {
+			fileSchema.Required = append(fileSchema.Required, file.Name)
+			body.Required = true
+		}
+		if file.Accept != "" {
+			if media.Encoding == nil {
+				media.Encoding = make(map[string]Encoding)
+			}
+			media.Encoding[file.Name] = Encoding{ContentType: strings.Join(splitMarkerList(file.Accept), ", ")}
+		}
+	}
+
+	if media.Schema == nil {
+		media.Schema = fileSchema
+	} else {
+		media.Schema = &OpenAPISchema{AllOf: []*OpenAPISchema{media.Schema, fileSchema}}
+	}
+	media.Example = nil
+	body.Content["multipart/form-data"] = media
+	return body
+}
+
+// fileDescription description of a file field with its limits, e.g. "Profile picture (max 5MB; image/png, image/jpeg)"
+func fileDescription(file ParameterInfo) string {
+	var limits []string
+	if file.MaxSize != "" {
+		limits = append(limits, "max "+file.MaxSize)
+	}
+	if file.Accept != "" {
+		limits = append(limits, strings.Join(splitMarkerList(file.Accept), ", "))
+	}
+	if len(limits) == 0 {
+		return file.Description
+	}
+	if file.Description == "" {
+		return strings.Join(limits, "; ")
+	}
+	return fmt.Sprintf("%s (%s)", file.Description, strings.Join(limits, "; "))
+}
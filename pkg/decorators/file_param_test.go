@@ -0,0 +1,189 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader first bytes of a PNG image
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// uploadFile file part of a test multipart body
+type uploadFile struct {
+	field, name, contentType, content string
+}
+
+// multipartRequest POST /upload with the files as a multipart/form-data body
+func multipartRequest(t *testing.T, files ...uploadFile) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="`+file.field+`"; filename="`+file.name+`"`)
+		header.Set("Content-Type", file.contentType)
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.WriteField("title", "Profile"))
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestParseFileParamArgs(t *testing.T) {
+	config, err := parseFileParamArgs([]string{`name="avatar"`, "required=true", `max_size="5MB"`, `types="image/png,image/*"`, `description="Profile picture"`})
+	require.NoError(t, err)
+	assert.Equal(t, FileParamConfig{Name: "avatar", Required: true, MaxSize: "5MB", Types: []string{"image/png", "image/*"}, Description: "Profile picture"}, config)
+	assert.Equal(t, ParameterInfo{Name: "avatar", Type: "file", Location: "file", Required: true, Description: "Profile picture", MaxSize: "5MB", Accept: "image/png,image/*"}, config.parameterInfo())
+
+	config, err = parseFileParamArgs([]string{`"attachments"`, "multiple=true"})
+	require.NoError(t, err)
+	assert.Equal(t, FileParamConfig{Name: "attachments", Multiple: true}, config)
+
+	for _, args := range [][]string{nil, {"required=true"}, {"a", "b"}, {"a", "max_size=huge"}, {"a", "multiple=maybe"}, {"a", `types="image/"`}, {"a", "accept=image/png"}} {
+		_, err := parseFileParamArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestParseRequestBodyArgs(t *testing.T) {
+	param, err := parseRequestBodyArgs([]string{"CreateDocumentRequest", `contentType="multipart/form-data"`, `description="New document"`})
+	require.NoError(t, err)
+	assert.Equal(t, ParameterInfo{Name: "body", Type: "CreateDocumentRequest", Location: "body", Required: true, Description: "New document", ContentType: "multipart/form-data"}, param)
+
+	param, err = parseRequestBodyArgs([]string{`type="User"`})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", requestBodyContentType(param))
+
+	for _, args := range [][]string{nil, {"A", "B"}, {"A", `contentType="multipart/"`}, {"A", "required=false"}} {
+		_, err := parseRequestBodyArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestFileParamMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/upload", FileParamMiddleware(FileParamConfig{Name: "avatar", Required: true, MaxSize: "16B", Types: []string{"image/png", "text/*"}}), func(c *gin.Context) {
+		file, err := c.FormFile("avatar")
+		require.NoError(t, err, "the parsed form reaches the handler")
+		c.String(http.StatusOK, file.Filename+" "+c.PostForm("title"))
+	})
+
+	send := func(files ...uploadFile) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, multipartRequest(t, files...))
+		return w
+	}
+
+	w := send(uploadFile{"avatar", "me.png", "application/octet-stream", pngHeader})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "me.png Profile", w.Body.String())
+	assert.Equal(t, http.StatusOK, send(uploadFile{"avatar", "notes.csv", "text/csv", "a,b\n1,2\n"}).Code, "plain text keeps its declared text type")
+
+	for name, test := range map[string]struct {
+		files  []uploadFile
+		status int
+		tag    string
+	}{
+		"missing":   {nil, http.StatusBadRequest, "required"},
+		"several":   {[]uploadFile{{"avatar", "a.png", "image/png", pngHeader}, {"avatar", "b.png", "image/png", pngHeader}}, http.StatusBadRequest, "multiple"},
+		"too large": {[]uploadFile{{"avatar", "big.png", "image/png", pngHeader + strings.Repeat("x", 16)}}, http.StatusRequestEntityTooLarge, "max_size"},
+		"type":      {[]uploadFile{{"avatar", "me.gif", "image/png", "GIF89a"}}, http.StatusUnsupportedMediaType, "types"},
+	} {
+		w := send(test.files...)
+		require.Equal(t, test.status, w.Code, name)
+		var response ValidationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "validation_failed", response.Error, name)
+		require.Len(t, response.Fields, 1, name)
+		assert.Equal(t, "avatar", response.Fields[0].Field, name)
+		assert.Equal(t, test.tag, response.Fields[0].Tag, name)
+	}
+
+	// Optional files may be absent, even from requests that are not multipart
+	optional := gin.New()
+	optional.POST("/upload", FileParamMiddleware(FileParamConfig{Name: "avatar"}), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	w = httptest.NewRecorder()
+	optional.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestFileParamMarker(t *testing.T) {
+	fileArgs, err := parseArgumentsWithValidation(`name="avatar", required=true, max_size="5MB", types="image/png,image/jpeg"`, "FileParam")
+	require.NoError(t, err)
+	bodyArgs, err := parseArgumentsWithValidation(`UploadRequest, contentType="multipart/form-data"`, "RequestBody")
+	require.NoError(t, err)
+	_, err = parseArgumentsWithValidation(`name="avatar", max_size="5 parsecs"`, "FileParam")
+	assert.Error(t, err)
+
+	call := generateMiddlewareCall(MarkerInstance{Name: "FileParam", Args: fileArgs})
+	assert.Equal(t, `deco.CreateFileParamMiddleware("name=\"avatar\",required=true,max_size=\"5MB\",types=\"image/png,image/jpeg\"")`, call)
+
+	route := &RouteMeta{Method: "POST", Path: "/upload", Markers: []MarkerInstance{{Name: "RequestBody", Args: bodyArgs}, {Name: "FileParam", Args: fileArgs}}}
+	require.NoError(t, processMiddlewares(route))
+	assert.Len(t, route.MiddlewareCalls, 1, "@RequestBody only documents the body")
+	require.Len(t, route.Parameters, 2)
+	assert.Equal(t, "body", route.Parameters[0].Location)
+	assert.Equal(t, "file", route.Parameters[1].Location)
+}
+
+func TestMultipartRequestBody(t *testing.T) {
+	metas := []*RouteMeta{
+		{
+			Method: "PUT", Path: "/users/{id}/avatar", FuncName: "UploadAvatar",
+			Parameters: []ParameterInfo{
+				{Name: "id", Type: "string", Location: "path", Required: true},
+				{Name: "avatar", Type: "file", Location: "file", Required: true, Description: "Profile picture", MaxSize: "5MB", Accept: "image/png,image/jpeg"},
+				{Name: "extras", Type: "file", Location: "file", Multiple: true},
+			},
+		},
+		{
+			Method: "POST", Path: "/documents", FuncName: "CreateDocument",
+			Parameters: []ParameterInfo{
+				{Name: "body", Type: "object", Location: "body", ContentType: "multipart/form-data"},
+				{Name: "file", Type: "file", Location: "file"},
+			},
+		},
+	}
+	spec := GenerateOpenAPISpecFromMeta(DefaultConfig(), metas)
+
+	operation := spec.Paths["/users/{id}/avatar"]["put"]
+	require.NotNil(t, operation)
+	require.Len(t, operation.Parameters, 1, "files are not parameters")
+	require.NotNil(t, operation.RequestBody)
+	assert.True(t, operation.RequestBody.Required)
+	media, ok := operation.RequestBody.Content["multipart/form-data"]
+	require.True(t, ok)
+	avatar := media.Schema.Properties["avatar"]
+	assert.Equal(t, "string", avatar.Type)
+	assert.Equal(t, "binary", avatar.Format)
+	assert.Equal(t, "Profile picture (max 5MB; image/png, image/jpeg)", avatar.Description)
+	assert.Equal(t, "array", media.Schema.Properties["extras"].Type)
+	assert.Equal(t, "binary", media.Schema.Properties["extras"].Items.Format)
+	assert.Equal(t, []string{"avatar"}, media.Schema.Required)
+	assert.Equal(t, "image/png, image/jpeg", media.Encoding["avatar"].ContentType)
+
+	// The fields of the body type sit next to the files
+	body := spec.Paths["/documents"]["post"].RequestBody
+	require.Len(t, body.Content, 1)
+	schema := body.Content["multipart/form-data"].Schema
+	require.Len(t, schema.AllOf, 2)
+	assert.Equal(t, "object", schema.AllOf[0].Type)
+	assert.Contains(t, schema.AllOf[1].Properties, "file")
+}
@@ -19,6 +19,19 @@ func escapeGoString(s string) string {
 	return strconv.Quote(s)
 }
 
+// firstLine returns the first line of a multi-line text, e.g. for a generated comment
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// generatorFuncs functions available to the generation templates
+var generatorFuncs = template.FuncMap{
+	"escapeString":  escapeGoString,
+	"firstLine":     firstLine,
+	"routeVersions": routeVersions,
+}
+
 // GenerateInitFile generates the init_decorators.go file for production
 func GenerateInitFile(rootDir, outputPath, pkgName string) error {
 	return GenerateInitFileWithConfig(rootDir, outputPath, pkgName, nil)
@@ -26,15 +39,48 @@ func GenerateInitFile(rootDir, outputPath, pkgName string) error {
 
 // GenerateInitFileWithConfig generates file with specific configuration
 func GenerateInitFileWithConfig(rootDir, outputPath, pkgName string, config *Config) error {
+	// Use default configuration if not provided
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	// Marker plugins listed in the configuration (blank imports of the handlers are found while parsing)
+	if err := LoadMarkerPlugins(rootDir, config.Generate.Plugins); err != nil {
+		return err
+	}
+
 	// Parse and prepare data
-	routes, genData, err := parseAndPrepareData(rootDir, pkgName)
+	cache := parseCacheForConfig(config, outputPath)
+	routes, genData, err := parseAndPrepareData(rootDir, pkgName, cache)
 	if err != nil {
 		return err
 	}
 
-	// Use default configuration if not provided
-	if config == nil {
-		config = DefaultConfig()
+	// Apply the global slow request threshold
+	applySlowThresholdDefault(routes, config.Metrics.SlowThreshold)
+	applyMiddlewareOrder(routes, config.Generate.MiddlewareOrder)
+	addMissingImports(genData, middlewareCallImports(routes))
+
+	// Fail on ambiguous operationIds before writing anything
+	if err := DetectOperationIDCollisions(routes, config.OpenAPI.OperationID); err != nil {
+		return err
+	}
+	if err := assignOperationIDs(routes, config.OpenAPI.OperationID); err != nil {
+		return err
+	}
+
+	// Lint the resulting spec
+	if err := runSpecLint(routes, config); err != nil {
+		return err
+	}
+
+	if hits, misses := cache.Stats(); hits+misses > 0 {
+		LogVerbose("🗃️  Parse cache: %d files reused, %d reparsed", hits, misses)
+	}
+
+	// Write the .proto of the @GRPC routes; its descriptor is embedded in the generated code
+	if err := writeGRPCProto(routes, genData, config, outputPath); err != nil {
+		return err
 	}
 
 	// Generate the file
@@ -42,11 +88,26 @@ func GenerateInitFileWithConfig(rootDir, outputPath, pkgName string, config *Con
 		return err
 	}
 
+	// Map generated blocks back to their handlers
+	if err := WriteSourceMap(outputPath, routes); err != nil {
+		LogVerbose("⚠️  Could not write source map: %v", err)
+	}
+
+	// Report schema versions and upcoming field removals
+	if err := WriteMigrationReport(outputPath); err != nil {
+		LogVerbose("⚠️  %v", err)
+	}
+
 	// Validate if enabled
 	if config.Prod.Validate {
 		if err := ValidateGeneration(outputPath); err != nil {
 			return fmt.Errorf("validation failed: %v", err)
 		}
+		for _, shard := range GeneratedFiles(outputPath)[1:] {
+			if err := validateGoSyntax(shard); err != nil {
+				return fmt.Errorf("validation failed: %s: %v", shard, err)
+			}
+		}
 		LogVerbose("File validado com success")
 	}
 
@@ -57,10 +118,10 @@ func GenerateInitFileWithConfig(rootDir, outputPath, pkgName string, config *Con
 }
 
 // parseAndPrepareData parses the directory and prepares generation data
-func parseAndPrepareData(rootDir, pkgName string) ([]*RouteMeta, *GenData, error) {
-	routes, err := ParseDirectory(rootDir)
+func parseAndPrepareData(rootDir, pkgName string, cache *ParseCache) ([]*RouteMeta, *GenData, error) {
+	routes, err := ParseDirectoryWithCache(rootDir, cache)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error in parsing do directory %s: %v", rootDir, err)
+		return nil, nil, fmt.Errorf("error in parsing do directory %s: %w", rootDir, err)
 	}
 
 	if err := executeParserHooks(routes); err != nil {
@@ -70,9 +131,9 @@ func parseAndPrepareData(rootDir, pkgName string) ([]*RouteMeta, *GenData, error
 	genData := &GenData{
 		PackageName: pkgName,
 		Routes:      routes,
-		Imports: []string{
+		Imports: append([]string{
 			`decorators "github.com/RodolfoBonis/deco/pkg/decorators"`,
-		},
+		}, markerPluginImports(routes)...),
 		Metadata: map[string]interface{}{
 			"generated_at": time.Now().Format(time.RFC3339),
 		},
@@ -87,11 +148,13 @@ func parseAndPrepareData(rootDir, pkgName string) ([]*RouteMeta, *GenData, error
 
 // generateFile generates the output file
 func generateFile(outputPath string, genData *GenData, config *Config) error {
+	if config.Generate.Split {
+		return generateSplitFiles(outputPath, genData)
+	}
+
 	tmplContent := getTemplateContent(config)
 
-	tmpl, err := template.New("init_decorators").Funcs(template.FuncMap{
-		"escapeString": escapeGoString,
-	}).Parse(tmplContent)
+	tmpl, err := template.New("init_decorators").Funcs(generatorFuncs).Parse(tmplContent)
 	if err != nil {
 		return fmt.Errorf("error processing template: %v", err)
 	}
@@ -114,7 +177,8 @@ func generateFile(outputPath string, genData *GenData, config *Config) error {
 		return fmt.Errorf("error executing template: %v", err)
 	}
 
-	return nil
+	// Shards of a previous split generation would register the routes twice
+	return removeStaleShards(outputPath, nil)
 }
 
 // getTemplateContent returns the appropriate template content
@@ -134,7 +198,10 @@ func createOutputDirectory(outputPath string) error {
 	return nil
 }
 
-// createGitignoreIfNeeded creates .gitignore for .deco folders
+// gitignoreRequiredEntries generated artifacts that must never be committed
+var gitignoreRequiredEntries = []string{"sourcemap.json", "cache.json"}
+
+// createGitignoreIfNeeded creates .gitignore for .deco folders, adding missing entries to an existing one
 func createGitignoreIfNeeded(outputPath string) error {
 	outputDir := filepath.Dir(outputPath)
 	if !strings.Contains(outputDir, ".deco") {
@@ -142,12 +209,14 @@ func createGitignoreIfNeeded(outputPath string) error {
 	}
 
 	gitignorePath := filepath.Join(outputDir, ".gitignore")
-	if _, err := os.Stat(gitignorePath); err == nil {
-		return nil // Already exists
+	if existing, err := os.ReadFile(gitignorePath); err == nil {
+		return appendMissingGitignoreEntries(gitignorePath, string(existing))
 	}
 
 	gitignoreContent := `# Files generateds automatically pelo gin-decorators
 *.go
+sourcemap.json
+cache.json
 !.gitignore
 
 # Files de cache e temporários
@@ -162,6 +231,37 @@ func createGitignoreIfNeeded(outputPath string) error {
 	return nil
 }
 
+// appendMissingGitignoreEntries adds required entries that an existing .gitignore lacks
+func appendMissingGitignoreEntries(gitignorePath, existing string) error {
+	present := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, entry := range gitignoreRequiredEntries {
+		if !present[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	content := existing
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += strings.Join(missing, "\n") + "\n"
+
+	if err := os.WriteFile(gitignorePath, []byte(content), 0o600); err != nil {
+		fmt.Printf("⚠️  Warning: could not update .gitignore em %s: %v\n", filepath.Dir(gitignorePath), err)
+	} else {
+		fmt.Printf("📝 Added %s to %s\n", strings.Join(missing, ", "), gitignorePath)
+	}
+	return nil
+}
+
 // logGenerationStats logs generation statistics
 func logGenerationStats(routes []*RouteMeta, genData *GenData, outputPath string, config *Config) {
 	stats := calculateStats(routes)
@@ -215,7 +315,11 @@ func calculateStats(routes []*RouteMeta) generationStats {
 
 // getInitTemplate returns the default template for code generation
 func getInitTemplate() string {
-	return `// Code generated by gin-decorators; DO NOT EDIT.
+	return initHeaderTemplate + "func init() {" + initRoutesTemplate + initTrailerTemplate
+}
+
+// initHeaderTemplate package clause and imports of the generated files
+const initHeaderTemplate = `// Code generated by gin-decorators; DO NOT EDIT.
 // This file is automatically generated and works in both dev and prod modes.
 package {{ .PackageName }}
 
@@ -226,19 +330,31 @@ import (
 {{- end }}
 )
 
-func init() {
+`
+
+// initRoutesTemplate registrations of the routes, WebSocket handlers and subscriptions of .Routes
+const initRoutesTemplate = `
+{{- range .Controllers }}
+	{{ .Var }} := {{ .Value }}
+{{- end }}
 {{- range .Routes }}
+{{- $route := . }}
 {{- if and .Method .Path }}
-	// {{ .Method }} {{ .Path }} -> {{ .FuncName }}
+{{- range $version := routeVersions $route }}
+{{- with $route }}
+	// {{ .Method }} {{ .Path }} -> {{ .FuncName }}{{ if $version }} ({{ $version }}){{ end }}
 	{{- if .Description }}
-	// {{ .Description }}
+	// {{ firstLine .Description }}
 	{{- end }}
 	decorators.RegisterRouteWithMeta(&decorators.RouteEntry{
 		Method:      "{{ .Method }}",
 		Path:        "{{ .Path }}",
-		Handler:     {{ if eq $.PackageName "deco" }}{{ .PackageName }}.{{ .FuncName }}{{ else }}{{ .FuncName }}{{ end }},
-		{{- if .MiddlewareCalls }}
+		Handler:     {{ if .TypedHandler }}decorators.Typed({{ end }}{{ $.Handler . }}{{ if .TypedHandler }}){{ end }},
+		{{- if or .Providers .MiddlewareCalls }}
 		Middlewares: []gin.HandlerFunc{
+			{{- range .Providers }}
+			decorators.Provide({{ escapeString .Name }}, {{ if $.ExternalHandlers }}{{ $route.PackageName }}.{{ end }}{{ .Factory }}, {{ .Cache }}),
+			{{- end }}
 			{{- range .MiddlewareCalls }}
 			{{ . }},
 			{{- end }}
@@ -246,12 +362,22 @@ func init() {
 		{{- end }}
 		FuncName:    "{{ .FuncName }}",
 		PackageName: "{{ .PackageName }}",
+		{{- if .OperationID }}
+		OperationID: {{ escapeString .OperationID }},
+		{{- end }}
 		{{- if .Description }}
 		Description: {{ escapeString .Description }},
 		{{- end }}
 		{{- if .Summary }}
 		Summary:     {{ escapeString .Summary }},
 		{{- end }}
+		{{- if .Translations }}
+		Translations: map[string]decorators.RouteTranslation{
+			{{- range $locale, $text := .Translations }}
+			{{ escapeString $locale }}: {Summary: {{ escapeString $text.Summary }}, Description: {{ escapeString $text.Description }}},
+			{{- end }}
+		},
+		{{- end }}
 		{{- if .Tags }}
 		Tags:        []string{
 			{{- range .Tags }}
@@ -284,6 +410,21 @@ func init() {
 				Required:    {{ .Required }},
 				Description: {{ escapeString .Description }},
 				Example:     {{ escapeString .Example }},
+				{{- if .Pattern }}
+				Pattern:     {{ escapeString .Pattern }},
+				{{- end }}
+				{{- if .ContentType }}
+				ContentType: {{ escapeString .ContentType }},
+				{{- end }}
+				{{- if .Multiple }}
+				Multiple:    true,
+				{{- end }}
+				{{- if .MaxSize }}
+				MaxSize:     {{ escapeString .MaxSize }},
+				{{- end }}
+				{{- if .Accept }}
+				Accept:      {{ escapeString .Accept }},
+				{{- end }}
 			},
 			{{- end }}
 		},
@@ -307,19 +448,30 @@ func init() {
 			{{- end }}
 		},
 		{{- end }}
+		{{- if .GRPC }}
+		GRPC:        &decorators.GRPCBinding{Service: {{ escapeString .GRPC.Service }}, Method: {{ escapeString .GRPC.Method }}},
+		{{- end }}
+		{{- if .Deprecation }}
+		Deprecation: &decorators.DeprecationInfo{Message: {{ escapeString .Deprecation.Message }}, Sunset: {{ escapeString .Deprecation.Sunset }}, Link: {{ escapeString .Deprecation.Link }}, Headers: {{ .Deprecation.Headers }}},
+		{{- end }}
+		{{- if $version }}
+		Version:     {{ escapeString $version }},
+		{{- end }}
 	})
+{{- end }}
+{{- end }}
 {{- else if .WebSocketHandlers }}
 	// WebSocket-only handlers for {{ .FuncName }}
-	{{- $funcName := .FuncName }}
+	{{- $handler := $.Handler . }}
 	{{- range .WebSocketHandlers }}
-	decorators.RegisterWebSocketHandler("{{ . }}", {{ if eq $.PackageName "deco" }}{{ $funcName }}{{ else }}{{ $funcName }}{{ end }})
+	decorators.RegisterWebSocketHandler("{{ . }}", {{ $handler }})
 	{{- end }}
 	
 	// Register WebSocket handlers as routes for documentation
 	decorators.RegisterRouteWithMeta(&decorators.RouteEntry{
 		Method:      "WS",
 		Path:        "/ws/{{ .FuncName }}",
-		Handler:     decorators.WebSocketHandlerWrapper({{ if eq $.PackageName "deco" }}{{ .FuncName }}{{ else }}{{ .FuncName }}{{ end }}),
+		Handler:     decorators.WebSocketHandlerWrapper({{ $.Handler . }}),
 		FuncName:    "{{ .FuncName }}",
 		PackageName: "{{ .PackageName }}",
 		{{- if .Description }}
@@ -328,6 +480,13 @@ func init() {
 		{{- if .Summary }}
 		Summary:     {{ escapeString .Summary }},
 		{{- end }}
+		{{- if .Translations }}
+		Translations: map[string]decorators.RouteTranslation{
+			{{- range $locale, $text := .Translations }}
+			{{ escapeString $locale }}: {Summary: {{ escapeString $text.Summary }}, Description: {{ escapeString $text.Description }}},
+			{{- end }}
+		},
+		{{- end }}
 		{{- if .Tags }}
 		Tags:        []string{
 			{{- range .Tags }}
@@ -363,7 +522,30 @@ func init() {
 			{{- end }}
 		},
 	})
+{{- else if .Subscription }}
+	// @Subscribe {{ .Subscription.Topic }} ({{ .Subscription.Group }}) -> {{ .FuncName }}
+	decorators.RegisterSubscription(&decorators.SubscriptionEntry{
+		Topic:       {{ escapeString .Subscription.Topic }},
+		Group:       {{ escapeString .Subscription.Group }},
+		Concurrency: {{ .Subscription.Concurrency }},
+		Retries:     {{ .Subscription.Retries }},
+		Backoff:     {{ .Subscription.Backoff.Nanoseconds }}, // {{ .Subscription.Backoff }}
+		{{- if .Subscription.DLQ }}
+		DLQ:         {{ escapeString .Subscription.DLQ }},
+		{{- end }}
+		Handler:     {{ $.Handler . }},
+		FuncName:    "{{ .FuncName }}",
+		PackageName: "{{ .PackageName }}",
+	})
+{{- end }}
 {{- end }}
+`
+
+// initTrailerTemplate end of init() — gRPC descriptor and default WebSocket handlers — and GeneratedMetadata
+const initTrailerTemplate = `{{- with index .Metadata "grpc_descriptor" }}
+
+	// gRPC services of the @GRPC routes (descriptor of the generated .proto)
+	decorators.RegisterGRPCDescriptor({{ escapeString . }})
 {{- end }}
 
 	// Initialize WebSocket default handlers
@@ -377,16 +559,41 @@ var GeneratedMetadata = map[string]interface{}{
 	"package_name": "{{ .PackageName }}",
 }
 `
-}
 
 // GenerateFromTemplate generates code using custom template
 func GenerateFromTemplate(rootDir, templatePath, outputPath, pkgName string) error {
+	return GenerateFromTemplateWithConfig(rootDir, templatePath, outputPath, pkgName, nil)
+}
+
+// GenerateFromTemplateWithConfig generates code using custom template and specific configuration
+func GenerateFromTemplateWithConfig(rootDir, templatePath, outputPath, pkgName string, config *Config) error {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
 	// Parse source directory
-	routes, err := ParseDirectory(rootDir)
+	routes, err := ParseDirectoryWithCache(rootDir, parseCacheForConfig(config, outputPath))
 	if err != nil {
 		return fmt.Errorf("error in parsing: %v", err)
 	}
 
+	// Apply the global slow request threshold
+	applySlowThresholdDefault(routes, config.Metrics.SlowThreshold)
+	applyMiddlewareOrder(routes, config.Generate.MiddlewareOrder)
+
+	// Fail on ambiguous operationIds before writing anything
+	if err := DetectOperationIDCollisions(routes, config.OpenAPI.OperationID); err != nil {
+		return err
+	}
+	if err := assignOperationIDs(routes, config.OpenAPI.OperationID); err != nil {
+		return err
+	}
+
+	// Lint the resulting spec
+	if err := runSpecLint(routes, config); err != nil {
+		return err
+	}
+
 	// Run hooks
 	if err := executeParserHooks(routes); err != nil {
 		return err
@@ -422,7 +629,20 @@ func GenerateFromTemplate(rootDir, templatePath, outputPath, pkgName string) err
 	defer outputFile.Close()
 
 	// Run template
-	return tmpl.Execute(outputFile, genData)
+	if err := tmpl.Execute(outputFile, genData); err != nil {
+		return err
+	}
+
+	if err := WriteSourceMap(outputPath, routes); err != nil {
+		LogVerbose("⚠️  Could not write source map: %v", err)
+	}
+
+	// Report schema versions and upcoming field removals
+	if err := WriteMigrationReport(outputPath); err != nil {
+		LogVerbose("⚠️  %v", err)
+	}
+
+	return nil
 }
 
 // ValidateGeneration validates if the generated file is correct
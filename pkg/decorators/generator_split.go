@@ -0,0 +1,194 @@
+package decorators
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Split generation (generation.split): the registrations of each handler package go to their own file
+// next to the generated one, which keeps the gRPC descriptor, the default WebSocket handlers and
+// GeneratedMetadata. Every file declares the same package, so the code importing it does not change.
+
+// shardSuffix ends the name of the per-package files, so no shard reads as a _test, GOOS or GOARCH file
+const shardSuffix = "_routes.go"
+
+// generatedHeader first line of every generated file, checked before removing a stale shard
+const generatedHeader = "// Code generated by gin-decorators; DO NOT EDIT."
+
+var (
+	shardTemplate      = template.Must(template.New("shard").Funcs(generatorFuncs).Parse(initHeaderTemplate + "func init() {" + initRoutesTemplate + "}\n"))
+	aggregatorTemplate = template.Must(template.New("aggregator").Funcs(generatorFuncs).Parse(initHeaderTemplate + "func init() {\n" + initTrailerTemplate))
+
+	majorVersion   = regexp.MustCompile(`^v[0-9]+$`)
+	shardNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// ShardPath returns the file with the registrations of a handler package when generation.split is enabled,
+// e.g. .deco/init_decorators_users_routes.go
+func ShardPath(outputPath, pkg string) string {
+	name := strings.Trim(shardNameChars.ReplaceAllString(strings.ToLower(pkg), "_"), "_")
+	return filepath.Clean(strings.TrimSuffix(outputPath, ".go") + "_" + name + shardSuffix)
+}
+
+// GeneratedFiles returns the generated file followed by its per-package shards, if any
+func GeneratedFiles(outputPath string) []string {
+	files := []string{outputPath}
+	matches, _ := filepath.Glob(strings.TrimSuffix(outputPath, ".go") + "_*" + shardSuffix)
+	for _, match := range matches {
+		if isGeneratedFile(match) {
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// isGeneratedFile reports whether the file starts with the header of the generated code
+func isGeneratedFile(file string) bool {
+	content, err := os.ReadFile(file)
+	return err == nil && bytes.HasPrefix(content, []byte(generatedHeader))
+}
+
+// generateSplitFiles writes one file per handler package and the aggregator at outputPath
+func generateSplitFiles(outputPath string, genData *GenData) error {
+	if err := createOutputDirectory(outputPath); err != nil {
+		return err
+	}
+	if err := createGitignoreIfNeeded(outputPath); err != nil {
+		return err
+	}
+
+	// Package names differing only in case share a file
+	groups := make(map[string][]*RouteMeta)
+	for _, route := range genData.Routes {
+		pkg := route.PackageName
+		if pkg == "" {
+			pkg = genData.PackageName
+		}
+		shard := ShardPath(outputPath, pkg)
+		groups[shard] = append(groups[shard], route)
+	}
+	shards := make([]string, 0, len(groups))
+	for shard := range groups {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	written := make(map[string]bool)
+	for _, shard := range shards {
+		data := *genData
+		data.Routes = groups[shard]
+		if err := renderGoFile(shard, shardTemplate, &data); err != nil {
+			return err
+		}
+		written[shard] = true
+	}
+
+	if err := renderGoFile(outputPath, aggregatorTemplate, genData); err != nil {
+		return err
+	}
+	LogVerbose("🧩 Registrations split in %d files by package", len(shards))
+	return removeStaleShards(outputPath, written)
+}
+
+// renderGoFile executes a generation template, drops the imports the file does not use and writes it formatted
+func renderGoFile(file string, tmpl *template.Template, data *GenData) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("error executing template: %v", err)
+	}
+	source, err := pruneUnusedImports(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting %s: %v", file, err)
+	}
+	if err := os.WriteFile(file, source, 0o644); err != nil {
+		return fmt.Errorf("error creating file %s: %v", file, err)
+	}
+	return nil
+}
+
+// pruneUnusedImports removes the imports no selector of the file refers to — each shard only uses some of
+// them — and formats the source. Blank imports and imports whose name cannot be told from the path stay.
+func pruneUnusedImports(source []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+		specs := gen.Specs[:0]
+		for _, spec := range gen.Specs {
+			name := importName(spec.(*ast.ImportSpec))
+			if name == "" || name == "_" || name == "." || used[name] {
+				specs = append(specs, spec)
+			}
+		}
+		if gen.Specs = specs; len(specs) > 0 {
+			decls = append(decls, gen)
+		}
+	}
+	file.Decls = decls
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importName name an import is referred by, empty when the package name may differ from the path
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	importPath, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return ""
+	}
+	base := path.Base(importPath)
+	if !token.IsIdentifier(base) || majorVersion.MatchString(base) {
+		return ""
+	}
+	return base
+}
+
+// removeStaleShards deletes the generated shards of outputPath that are not in keep
+func removeStaleShards(outputPath string, keep map[string]bool) error {
+	for _, shard := range GeneratedFiles(outputPath)[1:] {
+		if keep[shard] {
+			continue
+		}
+		if err := os.Remove(shard); err != nil {
+			return fmt.Errorf("error removing stale generated file %s: %v", shard, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package decorators
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateInitFile_Split(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "users.go"), `package users
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Cache(ttl=1m)
+func ListUsers(c *gin.Context) {}
+
+// @Route("POST", "/users")
+func CreateUser(c *gin.Context) {}
+`)
+	writeTestFile(t, filepath.Join(dir, "orders.go"), `package orders
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/orders")
+func ListOrders(c *gin.Context) {}
+`)
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	config := DefaultConfig()
+	config.Generate.Split = true
+	config.Prod.Validate = true
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", config))
+
+	usersShard := filepath.Join(dir, ".deco", "init_decorators_users_routes.go")
+	ordersShard := filepath.Join(dir, ".deco", "init_decorators_orders_routes.go")
+	assert.Equal(t, []string{outputPath, ordersShard, usersShard}, GeneratedFiles(outputPath))
+
+	for _, file := range GeneratedFiles(outputPath) {
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		require.NoError(t, err)
+		assert.Equal(t, "handlers", parsed.Name.Name, "every file keeps the package name")
+	}
+
+	aggregator, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(aggregator), "RegisterRouteWithMeta")
+	assert.Contains(t, string(aggregator), `"routes_count": 3`)
+	assert.NotContains(t, string(aggregator), "gin-gonic", "unused imports are dropped")
+
+	users, err := os.ReadFile(usersShard)
+	require.NoError(t, err)
+	assert.Contains(t, string(users), `Path:        "/users"`)
+	assert.NotContains(t, string(users), "/orders")
+	orders, err := os.ReadFile(ordersShard)
+	require.NoError(t, err)
+	assert.NotContains(t, string(orders), "gin-gonic", "the shard has no middlewares")
+
+	// The source map points at the shard holding each block
+	sm, err := LoadSourceMap(SourceMapPath(outputPath))
+	require.NoError(t, err)
+	require.Len(t, sm.Entries, 3)
+	for _, entry := range sm.Entries {
+		assert.NotEmpty(t, entry.Generated)
+		located, _ := sm.LookupIn(entry.Generated, entry.StartLine)
+		assert.Equal(t, entry.FuncName, located.FuncName)
+	}
+
+	// Going back to a single file removes the shards
+	config.Generate.Split = false
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", config))
+	assert.Equal(t, []string{outputPath}, GeneratedFiles(outputPath))
+	assert.NoFileExists(t, usersShard)
+}
+
+func TestGenerateInitFile_CustomPackage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "users.go"), `package users
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+func ListUsers(c *gin.Context) {}
+`)
+
+	outputPath := filepath.Join(dir, "internal", "gen", "deco", "routes.go")
+	config := DefaultConfig()
+	config.Prod.Validate = true
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "gen", config))
+
+	parsed, err := parser.ParseFile(token.NewFileSet(), outputPath, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "gen", parsed.Name.Name)
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "users.ListUsers", "handlers of another package are qualified")
+	_, err = os.Stat(SourceMapPath(outputPath))
+	assert.NoError(t, err, "the source map sits next to the generated file")
+}
+
+func TestShardPath(t *testing.T) {
+	assert.Equal(t, ".deco/init_decorators_users_routes.go", ShardPath(".deco/init_decorators.go", "users"))
+	assert.Equal(t, ".deco/init_decorators_linux_routes.go", ShardPath(".deco/init_decorators.go", "linux"))
+	assert.Equal(t, ".deco/init_decorators_user_v2_routes.go", ShardPath(".deco/init_decorators.go", "User_V2"))
+}
+
+func TestPruneUnusedImports(t *testing.T) {
+	source, err := pruneUnusedImports([]byte(`package p
+
+import (
+	"time"
+	"github.com/gin-gonic/gin"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/acme/api/v2"
+	_ "github.com/acme/plugin"
+)
+
+var _ = redis.Nil
+var timeout = time.Second
+`))
+	require.NoError(t, err)
+	assert.NotContains(t, string(source), "gin-gonic")
+	assert.Contains(t, string(source), `"github.com/acme/api/v2"`, "the package name of a versioned path is unknown")
+	assert.Contains(t, string(source), `_ "github.com/acme/plugin"`)
+	assert.Contains(t, string(source), `"time"`)
+}
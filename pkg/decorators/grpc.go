@@ -0,0 +1,420 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// @GRPC(service="UserService", method="GetUser") exposes a route as a unary gRPC method as well.
+// The generator writes the .proto of the annotated routes and embeds its descriptor; at runtime
+// each call is translated into the HTTP request of the route and served by the Gin engine, so
+// auth, rate limiting, metrics and every other middleware apply to both transports.
+
+// GRPCBinding gRPC method a route is exposed as
+type GRPCBinding struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+// Valid proto service, method and package names
+var (
+	grpcIdentifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	grpcPackage    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*$`)
+)
+
+// parseGRPCArgs parses @GRPC arguments; method defaults to the handler name
+func parseGRPCArgs(args []string, funcName string) (*GRPCBinding, error) {
+	binding := &GRPCBinding{}
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			return nil, fmt.Errorf("@GRPC: expected key=value, found '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "service":
+			binding.Service = value
+		case "method":
+			binding.Method = value
+		default:
+			return nil, fmt.Errorf("@GRPC: unknown argument '%s' (valid: service, method)", strings.TrimSpace(key))
+		}
+	}
+
+	if binding.Service == "" {
+		return nil, fmt.Errorf("@GRPC requires a service, e.g. service=\"UserService\"")
+	}
+	if !grpcIdentifier.MatchString(binding.Service) {
+		return nil, fmt.Errorf("@GRPC: invalid service name '%s'", binding.Service)
+	}
+	if binding.Method != "" && !grpcIdentifier.MatchString(binding.Method) {
+		return nil, fmt.Errorf("@GRPC: invalid method name '%s'", binding.Method)
+	}
+	if binding.Method == "" {
+		binding.Method = funcName
+	}
+	return binding, nil
+}
+
+// validateGRPCMarker rejects @GRPC without arguments, which skips argument validation during parsing
+func validateGRPCMarker(route *RouteMeta) error {
+	for _, marker := range route.Markers {
+		if marker.Name == "GRPC" {
+			if _, err := parseGRPCArgs(marker.Args, route.FuncName); err != nil {
+				return fmt.Errorf("%s:%d: %v", route.FileName, marker.Line, err)
+			}
+		}
+	}
+	return nil
+}
+
+// grpc runtime state
+var (
+	grpcDescriptor *descriptorpb.FileDescriptorProto
+	grpcServer     *grpc.Server
+	grpcMutex      sync.Mutex
+)
+
+// RegisterGRPCDescriptor registers the serialized descriptor embedded by the generated code
+func RegisterGRPCDescriptor(raw string) {
+	descriptor := &descriptorpb.FileDescriptorProto{}
+	if err := proto.Unmarshal([]byte(raw), descriptor); err != nil {
+		LogSilent("⚠️  Invalid gRPC descriptor: %v", err)
+		return
+	}
+
+	grpcMutex.Lock()
+	grpcDescriptor = descriptor
+	grpcMutex.Unlock()
+	LogVerbose("gRPC descriptor registrado: %d services", len(descriptor.GetService()))
+}
+
+// NewGRPCServer creates a gRPC server with the services of the registered descriptor. Each method
+// is served by handler (the Gin engine) through the HTTP route bound by @GRPC.
+func NewGRPCServer(handler http.Handler, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	grpcMutex.Lock()
+	descriptor := grpcDescriptor
+	grpcMutex.Unlock()
+	if descriptor == nil {
+		return nil, fmt.Errorf("no gRPC descriptor registered (no @GRPC routes were generated)")
+	}
+
+	file, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gRPC descriptor: %v", err)
+	}
+	// Registered globally so server reflection can describe the services; a second server reuses it
+	if _, err := protoregistry.GlobalFiles.FindFileByPath(file.Path()); err != nil {
+		if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+			return nil, fmt.Errorf("error registering gRPC descriptor: %v", err)
+		}
+	}
+
+	bindings := make(map[string]RouteEntry)
+	for _, route := range GetRoutes() {
+		if route.GRPC != nil {
+			bindings[route.GRPC.Service+"/"+route.GRPC.Method] = route
+		}
+	}
+
+	server := grpc.NewServer(opts...)
+	services := file.Services()
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		desc := grpc.ServiceDesc{
+			ServiceName: string(service.FullName()),
+			HandlerType: (*interface{})(nil),
+			Metadata:    file.Path(),
+		}
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			route, ok := bindings[string(service.Name())+"/"+string(method.Name())]
+			if !ok {
+				return nil, fmt.Errorf("gRPC method %s has no registered route", method.FullName())
+			}
+			desc.Methods = append(desc.Methods, grpc.MethodDesc{
+				MethodName: string(method.Name()),
+				Handler:    grpcMethodHandler(handler, route, method),
+			})
+		}
+		server.RegisterService(&desc, struct{}{})
+	}
+	return server, nil
+}
+
+// StartGRPCServer serves the @GRPC methods on config.Address next to the HTTP server
+func StartGRPCServer(handler http.Handler, config GRPCConfig) error {
+	server, err := NewGRPCServer(handler)
+	if err != nil {
+		return err
+	}
+	if config.Reflection {
+		reflection.Register(server)
+	}
+
+	listener, err := net.Listen("tcp", config.Address)
+	if err != nil {
+		return fmt.Errorf("gRPC listen %s: %v", config.Address, err)
+	}
+
+	grpcMutex.Lock()
+	previous := grpcServer
+	grpcServer = server
+	grpcMutex.Unlock()
+	if previous != nil {
+		previous.Stop()
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			LogSilent("⚠️  gRPC server stopped: %v", err)
+		}
+	}()
+	LogNormal("gRPC server listening on %s", config.Address)
+	return nil
+}
+
+// StopGRPCServer stops the server started by StartGRPCServer, waiting for in-flight calls
+func StopGRPCServer() {
+	grpcMutex.Lock()
+	server := grpcServer
+	grpcServer = nil
+	grpcMutex.Unlock()
+	if server != nil {
+		server.GracefulStop()
+	}
+}
+
+// grpcMethodHandler unary handler that serves a gRPC method through the HTTP route
+func grpcMethodHandler(handler http.Handler, route RouteEntry, method protoreflect.MethodDescriptor) grpc.MethodHandler {
+	fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+	return func(_ interface{}, ctx context.Context, decode func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := dynamicpb.NewMessage(method.Input())
+		if err := decode(in); err != nil {
+			return nil, err
+		}
+		call := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return serveGRPCCall(ctx, handler, route, req.(*dynamicpb.Message), method.Output())
+		}
+		if interceptor == nil {
+			return call(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: fullMethod}, call)
+	}
+}
+
+// serveGRPCCall builds the HTTP request of the route from the gRPC message and metadata, serves it
+// and decodes the JSON response into the output message
+func serveGRPCCall(ctx context.Context, handler http.Handler, route RouteEntry, in *dynamicpb.Message, output protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	fields := in.Descriptor().Fields()
+	// Unset path parameters take the zero value of their type, as proto3 does
+	fieldValue := func(name string, path bool) (string, bool) {
+		field := fields.ByName(protoreflect.Name(grpcFieldName(name)))
+		if field == nil || field.IsList() || (!path && !in.Has(field)) {
+			return "", false
+		}
+		return fmt.Sprint(in.Get(field).Interface()), true
+	}
+
+	segments := strings.Split(route.Path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			value, _ := fieldValue(segment[1:], true)
+			segments[i] = url.PathEscape(value)
+		}
+	}
+	target := strings.Join(segments, "/")
+
+	query := url.Values{}
+	for _, param := range route.Parameters {
+		if param.Location == "query" {
+			if value, ok := fieldValue(param.Name, false); ok {
+				query.Set(param.Name, value)
+			}
+		}
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body []byte
+	if field := fields.ByName(grpcBodyField); field != nil && field.Message() != nil && in.Has(field) {
+		data, err := json.Marshal(grpcMessageToJSON(in.Get(field).Message()))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid body: %v", err)
+		}
+		body = data
+	}
+
+	req, err := http.NewRequestWithContext(ctx, route.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			if strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") || key == "content-type" {
+				continue
+			}
+			req.Header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	header := metadata.MD{}
+	for key, values := range recorder.Header() {
+		if key != "Content-Type" && key != "Content-Length" {
+			header.Set(strings.ToLower(key), values...)
+		}
+	}
+	if len(header) > 0 {
+		_ = grpc.SetHeader(ctx, header)
+	}
+
+	if recorder.Code >= 300 {
+		return nil, status.Error(grpcCodeFromHTTP(recorder.Code), grpcErrorMessage(recorder.Code, recorder.Body.Bytes()))
+	}
+
+	out := dynamicpb.NewMessage(output)
+	if err := decodeGRPCResponse(recorder.Body.Bytes(), out); err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot decode response of %s %s: %v", route.Method, route.Path, err)
+	}
+	return out, nil
+}
+
+// decodeGRPCResponse fills the output message from the JSON body. Arrays fill the items field of
+// the generated wrapper and other payloads without a schema go raw into its json field.
+func decodeGRPCResponse(data []byte, out *dynamicpb.Message) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	fields := out.Descriptor().Fields()
+	if field := fields.ByName(grpcRawField); field != nil && fields.Len() == 1 {
+		out.Set(field, protoreflect.ValueOfString(string(data)))
+		return nil
+	}
+	if field := fields.ByName(grpcItemsField); field != nil && fields.Len() == 1 && data[0] == '[' {
+		data = append(append([]byte(`{"items":`), data...), '}')
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, out)
+}
+
+// grpcMessageToJSON converts a message to the JSON value the HTTP handler expects. Unlike
+// protojson, 64-bit integers stay numbers so they bind to Go integer fields.
+func grpcMessageToJSON(message protoreflect.Message) map[string]interface{} {
+	out := make(map[string]interface{})
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.IsList() {
+			list := value.List()
+			items := make([]interface{}, list.Len())
+			for i := range items {
+				items[i] = grpcValueToJSON(field, list.Get(i))
+			}
+			out[field.JSONName()] = items
+		} else {
+			out[field.JSONName()] = grpcValueToJSON(field, value)
+		}
+		return true
+	})
+	return out
+}
+
+// grpcValueToJSON converts a singular field value
+func grpcValueToJSON(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return grpcMessageToJSON(value.Message())
+	case protoreflect.EnumKind:
+		return int32(value.Enum())
+	default:
+		return value.Interface()
+	}
+}
+
+// grpcCodeFromHTTP maps the HTTP status of the route to a gRPC status code
+func grpcCodeFromHTTP(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if code >= 500 {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// grpcErrorMessage the "message" (or "error") of a JSON error body, or the HTTP status text
+func grpcErrorMessage(code int, body []byte) string {
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		for _, key := range []string{"message", "error"} {
+			if message, ok := payload[key].(string); ok && message != "" {
+				return message
+			}
+		}
+	}
+	return http.StatusText(code)
+}
+
+// validate checks the gRPC configuration
+func (g GRPCConfig) validate() error {
+	if g.Enabled && g.Address == "" {
+		return fmt.Errorf("grpc.address is required when grpc is enabled")
+	}
+	if g.Package != "" && !grpcPackage.MatchString(g.Package) {
+		return fmt.Errorf("invalid grpc.package '%s'", g.Package)
+	}
+	return nil
+}
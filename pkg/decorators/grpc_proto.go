@@ -0,0 +1,430 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Fields of the generated request and response messages
+const (
+	grpcBodyField  = "body"  // request body of the route, typed by its schema
+	grpcItemsField = "items" // elements of an array response
+	grpcRawField   = "json"  // raw JSON of a response without a registered schema
+)
+
+// grpcProtoBuilder builds the descriptor of the @GRPC routes and the messages of the schemas they use
+type grpcProtoBuilder struct {
+	file     *descriptorpb.FileDescriptorProto
+	schemas  map[string]*SchemaInfo
+	messages map[string]bool
+}
+
+// BuildGRPCDescriptor builds the proto3 file of the @GRPC routes: one service per @GRPC service,
+// request messages with the path and query parameters and the body, and one message per
+// registered schema used by the routes. Schema fields are numbered in name order.
+func BuildGRPCDescriptor(routes []*RouteMeta, protoPackage string) (*descriptorpb.FileDescriptorProto, error) {
+	if protoPackage == "" {
+		protoPackage = DefaultConfig().GRPC.Package
+	}
+	builder := &grpcProtoBuilder{
+		file: &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(strings.ReplaceAll(protoPackage, ".", "_") + ".proto"),
+			Package: proto.String(protoPackage),
+			Syntax:  proto.String("proto3"),
+		},
+		schemas:  GetSchemas(),
+		messages: make(map[string]bool),
+	}
+
+	services := make(map[string]*descriptorpb.ServiceDescriptorProto)
+	bound := make(map[string]string)
+	for _, route := range routes {
+		if route.GRPC == nil {
+			continue
+		}
+		key := route.GRPC.Service + "/" + route.GRPC.Method
+		if previous, ok := bound[key]; ok {
+			return nil, fmt.Errorf("@GRPC method %s.%s is bound to both %s and %s", route.GRPC.Service, route.GRPC.Method, previous, route.FuncName)
+		}
+		bound[key] = route.FuncName
+
+		service, ok := services[route.GRPC.Service]
+		if !ok {
+			service = &descriptorpb.ServiceDescriptorProto{Name: proto.String(route.GRPC.Service)}
+			services[route.GRPC.Service] = service
+			builder.file.Service = append(builder.file.Service, service)
+		}
+
+		input, err := builder.requestMessage(route)
+		if err != nil {
+			return nil, err
+		}
+		output, err := builder.responseMessage(route)
+		if err != nil {
+			return nil, err
+		}
+		service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(route.GRPC.Method),
+			InputType:  proto.String(input),
+			OutputType: proto.String(output),
+		})
+	}
+
+	if _, err := protodesc.NewFile(builder.file, nil); err != nil {
+		return nil, fmt.Errorf("invalid gRPC descriptor: %v", err)
+	}
+	return builder.file, nil
+}
+
+// typeName fully-qualified name of a message of the file
+func (b *grpcProtoBuilder) typeName(message string) string {
+	return "." + b.file.GetPackage() + "." + message
+}
+
+// newMessage adds an empty message, prefixing the service name when the name is taken
+func (b *grpcProtoBuilder) newMessage(route *RouteMeta, suffix string) (*descriptorpb.DescriptorProto, error) {
+	name := route.GRPC.Method + suffix
+	if b.messages[name] {
+		name = route.GRPC.Service + route.GRPC.Method + suffix
+	}
+	if b.messages[name] {
+		return nil, fmt.Errorf("@GRPC %s.%s: message %s is already defined", route.GRPC.Service, route.GRPC.Method, name)
+	}
+	b.messages[name] = true
+	message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	b.file.MessageType = append(b.file.MessageType, message)
+	return message, nil
+}
+
+// requestMessage <Method>Request with the path and query parameters and the body of the route
+func (b *grpcProtoBuilder) requestMessage(route *RouteMeta) (string, error) {
+	// Schemas are added first so a schema named like the request message is reported
+	var bodyType string
+	for _, param := range route.Parameters {
+		if param.Location == "body" {
+			if name, ok := b.schemaMessage(param.Type); ok {
+				bodyType = name
+			}
+		}
+	}
+
+	message, err := b.newMessage(route, "Request")
+	if err != nil {
+		return "", err
+	}
+
+	declared := make(map[string]ParameterInfo)
+	for _, param := range route.Parameters {
+		declared[param.Location+":"+param.Name] = param
+	}
+	addParam := func(name, paramType string) {
+		fieldType, _, _, ok := b.goTypeField(paramGoType(paramType))
+		if !ok || fieldType == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			fieldType = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		}
+		addGRPCField(message, name, fieldType, "", false)
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			addParam(segment[1:], declared["path:"+segment[1:]].Type)
+		}
+	}
+	for _, param := range route.Parameters {
+		if param.Location == "query" {
+			addParam(param.Name, param.Type)
+		}
+	}
+	if bodyType != "" {
+		addGRPCField(message, grpcBodyField, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, bodyType, false)
+	}
+	return b.typeName(message.GetName()), nil
+}
+
+// responseMessage the schema message of the first 2xx response, or a <Method>Response wrapper
+// with the items of an array response or the raw JSON of other payloads
+func (b *grpcProtoBuilder) responseMessage(route *RouteMeta) (string, error) {
+	responseType := ""
+	for _, response := range route.Responses {
+		if strings.HasPrefix(response.Code, "2") && response.Type != "" {
+			responseType = response.Type
+			break
+		}
+	}
+
+	if name, ok := b.schemaMessage(responseType); ok && !strings.HasPrefix(responseType, "[]") {
+		return name, nil
+	}
+
+	fieldType, typeName, repeated, ok := b.goTypeField(responseType)
+	message, err := b.newMessage(route, "Response")
+	if err != nil {
+		return "", err
+	}
+	if ok && repeated {
+		addGRPCField(message, grpcItemsField, fieldType, typeName, true)
+	} else {
+		addGRPCField(message, grpcRawField, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false)
+	}
+	return b.typeName(message.GetName()), nil
+}
+
+// schemaMessage adds the message of a registered schema, and of the schemas it references
+func (b *grpcProtoBuilder) schemaMessage(goType string) (string, bool) {
+	name := strings.TrimLeft(goType, "*[]")
+	name = name[strings.LastIndex(name, ".")+1:]
+	schema, ok := b.schemas[name]
+	if !ok || name == "" {
+		return "", false
+	}
+	if b.messages[name] {
+		return b.typeName(name), true
+	}
+	b.messages[name] = true
+
+	message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	b.file.MessageType = append(b.file.MessageType, message)
+
+	names := make([]string, 0, len(schema.Properties))
+	for property := range schema.Properties {
+		names = append(names, property)
+	}
+	sort.Strings(names)
+	for _, property := range names {
+		fieldType, typeName, repeated, ok := b.propertyField(schema.Properties[property])
+		if !ok {
+			LogVerbose("⚠️  gRPC: field %s.%s has no proto type and was skipped", name, property)
+			continue
+		}
+		addGRPCField(message, property, fieldType, typeName, repeated)
+	}
+	return b.typeName(name), true
+}
+
+// propertyField proto type of a schema property, from its Go type or, for schemas registered
+// by hand, from its OpenAPI type
+func (b *grpcProtoBuilder) propertyField(property *PropertyInfo) (descriptorpb.FieldDescriptorProto_Type, string, bool, bool) {
+	if property.GoType != "" {
+		return b.goTypeField(property.GoType)
+	}
+	if property.Ref != "" {
+		return b.goTypeField(strings.TrimPrefix(property.Ref, "#/components/schemas/"))
+	}
+	if property.Type == "array" && property.Items != nil {
+		fieldType, typeName, repeated, ok := b.propertyField(property.Items)
+		if property.Items.GoType == "" && property.Items.Ref == "" && property.Items.Name != "" {
+			fieldType, typeName, repeated, ok = b.goTypeField(property.Items.Name)
+		}
+		return fieldType, typeName, true, ok && !repeated
+	}
+	if property.Type == "integer" && property.Format == "int32" {
+		return descriptorpb.FieldDescriptorProto_TYPE_INT32, "", false, true
+	}
+	return b.goTypeField(paramGoType(property.Type))
+}
+
+// goTypeField proto type of a Go type: scalars, []T as repeated and registered schemas as messages.
+// Maps, interfaces and nested slices have no proto3 field equivalent here.
+func (b *grpcProtoBuilder) goTypeField(goType string) (descriptorpb.FieldDescriptorProto_Type, string, bool, bool) {
+	goType = strings.TrimPrefix(strings.TrimSpace(goType), "*")
+	if strings.HasPrefix(goType, "[]") && goType != "[]byte" {
+		fieldType, typeName, repeated, ok := b.goTypeField(goType[2:])
+		return fieldType, typeName, true, ok && !repeated
+	}
+
+	switch goType {
+	case "":
+		return 0, "", false, false
+	case "string", "time.Time", "uuid.UUID":
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false, true
+	case "int", "int64", "time.Duration":
+		return descriptorpb.FieldDescriptorProto_TYPE_INT64, "", false, true
+	case "int8", "int16", "int32":
+		return descriptorpb.FieldDescriptorProto_TYPE_INT32, "", false, true
+	case "uint", "uint64":
+		return descriptorpb.FieldDescriptorProto_TYPE_UINT64, "", false, true
+	case "uint8", "uint16", "uint32":
+		return descriptorpb.FieldDescriptorProto_TYPE_UINT32, "", false, true
+	case "float32":
+		return descriptorpb.FieldDescriptorProto_TYPE_FLOAT, "", false, true
+	case "float64":
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "", false, true
+	case "bool":
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL, "", false, true
+	case "[]byte":
+		return descriptorpb.FieldDescriptorProto_TYPE_BYTES, "", false, true
+	}
+
+	if typeName, ok := b.schemaMessage(goType); ok {
+		return descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, typeName, false, true
+	}
+	return 0, "", false, false
+}
+
+// paramGoType Go type of a parameter or OpenAPI type name
+func paramGoType(paramType string) string {
+	switch paramType {
+	case "integer":
+		return "int64"
+	case "number", "float":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "", "object", "array":
+		return "string"
+	}
+	return paramType
+}
+
+// addGRPCField appends a field numbered after the existing ones; json_name keeps the JSON name
+// of the route so protojson and the HTTP handler agree. Names already taken are skipped.
+func addGRPCField(message *descriptorpb.DescriptorProto, jsonName string, fieldType descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) {
+	name := grpcFieldName(jsonName)
+	for _, field := range message.Field {
+		if field.GetName() == name {
+			return
+		}
+	}
+
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(int32(len(message.Field) + 1)),
+		Label:    label.Enum(),
+		Type:     fieldType.Enum(),
+		JsonName: proto.String(jsonName),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+	message.Field = append(message.Field, field)
+}
+
+// grpcFieldName snake_case proto field name of a JSON or parameter name
+func grpcFieldName(name string) string {
+	var out strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				out.WriteRune('_')
+			}
+			out.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			out.WriteRune(r)
+		default:
+			out.WriteRune('_')
+		}
+	}
+	field := out.String()
+	if field == "" || unicode.IsDigit(rune(field[0])) {
+		field = "_" + field
+	}
+	return field
+}
+
+// protoJSONName default JSON name protoc derives from a field name
+func protoJSONName(name string) string {
+	var out strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// RenderProto renders the descriptor as a .proto file
+func RenderProto(file *descriptorpb.FileDescriptorProto) string {
+	var out strings.Builder
+	out.WriteString("// Code generated by deco. DO NOT EDIT.\n")
+	out.WriteString("// gRPC services of the @GRPC routes; each method is served by its HTTP route.\n\n")
+	out.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&out, "package %s;\n", file.GetPackage())
+
+	localName := func(typeName string) string {
+		return strings.TrimPrefix(typeName, "."+file.GetPackage()+".")
+	}
+
+	for _, service := range file.GetService() {
+		fmt.Fprintf(&out, "\nservice %s {\n", service.GetName())
+		for _, method := range service.GetMethod() {
+			fmt.Fprintf(&out, "  rpc %s(%s) returns (%s);\n", method.GetName(), localName(method.GetInputType()), localName(method.GetOutputType()))
+		}
+		out.WriteString("}\n")
+	}
+
+	for _, message := range file.GetMessageType() {
+		fmt.Fprintf(&out, "\nmessage %s {\n", message.GetName())
+		for _, field := range message.GetField() {
+			fieldType := strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+			if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+				fieldType = localName(field.GetTypeName())
+			}
+			if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+				fieldType = "repeated " + fieldType
+			}
+			option := ""
+			if field.GetJsonName() != protoJSONName(field.GetName()) {
+				option = fmt.Sprintf(" [json_name = %q]", field.GetJsonName())
+			}
+			fmt.Fprintf(&out, "  %s %s = %d%s;\n", fieldType, field.GetName(), field.GetNumber(), option)
+		}
+		out.WriteString("}\n")
+	}
+	return out.String()
+}
+
+// writeGRPCProto writes the .proto of the @GRPC routes and embeds its descriptor in the generated
+// code (genData.Metadata["grpc_descriptor"]). The file defaults to <package>.proto next to the output.
+func writeGRPCProto(routes []*RouteMeta, genData *GenData, config *Config, outputPath string) error {
+	hasGRPC := false
+	for _, route := range routes {
+		hasGRPC = hasGRPC || route.GRPC != nil
+	}
+	if !hasGRPC {
+		return nil
+	}
+
+	file, err := BuildGRPCDescriptor(routes, config.GRPC.Package)
+	if err != nil {
+		return err
+	}
+	raw, err := proto.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error encoding gRPC descriptor: %v", err)
+	}
+	genData.Metadata["grpc_descriptor"] = string(raw)
+
+	protoPath := filepath.Join(filepath.Dir(outputPath), file.GetName())
+	if config.GRPC.Proto != "" {
+		protoPath = config.ResolvePath(config.GRPC.Proto)
+	}
+	if err := os.MkdirAll(filepath.Dir(protoPath), 0o755); err != nil {
+		return fmt.Errorf("error writing %s: %v", protoPath, err)
+	}
+	if err := os.WriteFile(protoPath, []byte(RenderProto(file)), 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %v", protoPath, err)
+	}
+	LogVerbose("📡 gRPC: %d services written to %s", len(file.GetService()), protoPath)
+	return nil
+}
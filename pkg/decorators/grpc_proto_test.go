@@ -0,0 +1,151 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// registerGRPCTestSchemas registers the schemas of the gRPC tests and restores the registry after
+func registerGRPCTestSchemas(t *testing.T) {
+	schemasMutex.Lock()
+	saved := schemas
+	schemas = make(map[string]*SchemaInfo)
+	schemasMutex.Unlock()
+	t.Cleanup(func() {
+		schemasMutex.Lock()
+		schemas = saved
+		schemasMutex.Unlock()
+	})
+
+	RegisterSchema(&SchemaInfo{Name: "GRPCUser", Type: "object", Properties: map[string]*PropertyInfo{
+		"id":        {Name: "id", Type: "integer", GoType: "int"},
+		"name":      {Name: "name", Type: "string", GoType: "string"},
+		"createdAt": {Name: "createdAt", Type: "string", GoType: "time.Time"},
+		"address":   {Name: "address", Type: "object", GoType: "*GRPCAddress"},
+		"tags":      {Name: "tags", Type: "array", GoType: "[]string"},
+		"extra":     {Name: "extra", Type: "object", GoType: "map[string]interface{}"},
+	}})
+	RegisterSchema(&SchemaInfo{Name: "GRPCAddress", Type: "object", Properties: map[string]*PropertyInfo{
+		"city": {Name: "city", Type: "string", GoType: "string"},
+	}})
+	RegisterSchema(&SchemaInfo{Name: "GRPCCreateUser", Type: "object", Properties: map[string]*PropertyInfo{
+		"name": {Name: "name", Type: "string", GoType: "string"},
+	}})
+}
+
+// grpcTestRoutes routes bound to UserService
+func grpcTestRoutes() []*RouteMeta {
+	return []*RouteMeta{
+		{
+			Method: "GET", Path: "/users/:id", FuncName: "GetUser",
+			Parameters: []ParameterInfo{{Name: "id", Type: "int", Location: "path"}, {Name: "fields", Type: "string", Location: "query"}},
+			Responses:  []ResponseInfo{{Code: "200", Type: "GRPCUser"}},
+			GRPC:       &GRPCBinding{Service: "UserService", Method: "GetUser"},
+		},
+		{
+			Method: "GET", Path: "/users", FuncName: "ListUsers",
+			Responses: []ResponseInfo{{Code: "200", Type: "[]GRPCUser"}},
+			GRPC:      &GRPCBinding{Service: "UserService", Method: "ListUsers"},
+		},
+		{
+			Method: "POST", Path: "/users", FuncName: "CreateUser",
+			Parameters: []ParameterInfo{{Name: "body", Type: "GRPCCreateUser", Location: "body"}},
+			Responses:  []ResponseInfo{{Code: "201", Type: "GRPCUser"}},
+			GRPC:       &GRPCBinding{Service: "UserService", Method: "CreateUser"},
+		},
+		{Method: "GET", Path: "/health", FuncName: "Health"},
+	}
+}
+
+func TestBuildGRPCDescriptor(t *testing.T) {
+	registerGRPCTestSchemas(t)
+
+	file, err := BuildGRPCDescriptor(grpcTestRoutes(), "acme.users")
+	require.NoError(t, err)
+	assert.Equal(t, "acme.users", file.GetPackage())
+	require.Len(t, file.GetService(), 1)
+	methods := file.GetService()[0].GetMethod()
+	require.Len(t, methods, 3)
+	assert.Equal(t, ".acme.users.GetUserRequest", methods[0].GetInputType())
+	assert.Equal(t, ".acme.users.GRPCUser", methods[0].GetOutputType())
+	assert.Equal(t, ".acme.users.ListUsersResponse", methods[1].GetOutputType())
+
+	messages := make(map[string]*descriptorpb.DescriptorProto)
+	for _, message := range file.GetMessageType() {
+		messages[message.GetName()] = message
+	}
+	fieldTypes := func(name string) map[string]string {
+		types := make(map[string]string)
+		for _, field := range messages[name].GetField() {
+			types[field.GetName()] = field.GetType().String()
+		}
+		return types
+	}
+
+	assert.Equal(t, map[string]string{"id": "TYPE_INT64", "fields": "TYPE_STRING"}, fieldTypes("GetUserRequest"))
+	assert.Equal(t, map[string]string{"body": "TYPE_MESSAGE"}, fieldTypes("CreateUserRequest"))
+	// Maps have no field equivalent and are skipped
+	assert.Equal(t, map[string]string{
+		"address": "TYPE_MESSAGE", "created_at": "TYPE_STRING", "id": "TYPE_INT64", "name": "TYPE_STRING", "tags": "TYPE_STRING",
+	}, fieldTypes("GRPCUser"))
+	assert.Contains(t, messages, "GRPCAddress")
+	assert.Equal(t, descriptorpb.FieldDescriptorProto_LABEL_REPEATED, messages["ListUsersResponse"].GetField()[0].GetLabel())
+}
+
+func TestBuildGRPCDescriptorDuplicateMethod(t *testing.T) {
+	registerGRPCTestSchemas(t)
+
+	routes := grpcTestRoutes()
+	routes[1].GRPC.Method = "GetUser"
+	_, err := BuildGRPCDescriptor(routes, "api")
+	assert.ErrorContains(t, err, "UserService.GetUser is bound to both GetUser and ListUsers")
+}
+
+func TestRenderProto(t *testing.T) {
+	registerGRPCTestSchemas(t)
+
+	file, err := BuildGRPCDescriptor(grpcTestRoutes(), "api")
+	require.NoError(t, err)
+	text := RenderProto(file)
+
+	assert.Contains(t, text, "syntax = \"proto3\";")
+	assert.Contains(t, text, "package api;")
+	assert.Contains(t, text, "rpc GetUser(GetUserRequest) returns (GRPCUser);")
+	assert.Contains(t, text, "rpc ListUsers(ListUsersRequest) returns (ListUsersResponse);")
+	assert.Contains(t, text, "  GRPCAddress address = 1;")
+	assert.Contains(t, text, "  string created_at = 2;")
+	assert.Contains(t, text, "  repeated string tags = 5;")
+	assert.Contains(t, text, "  repeated GRPCUser items = 1;")
+}
+
+func TestGRPCFieldName(t *testing.T) {
+	assert.Equal(t, "user_id", grpcFieldName("userID"))
+	assert.Equal(t, "user_id", grpcFieldName("user_id"))
+	assert.Equal(t, "http_server", grpcFieldName("HTTPServer"))
+	assert.Equal(t, "x_request_id", grpcFieldName("X-Request-ID"))
+	assert.Equal(t, "_2fa", grpcFieldName("2fa"))
+}
+
+func TestWriteGRPCProto(t *testing.T) {
+	registerGRPCTestSchemas(t)
+
+	dir := t.TempDir()
+	genData := &GenData{Metadata: map[string]interface{}{}}
+	config := DefaultConfig()
+	require.NoError(t, writeGRPCProto(grpcTestRoutes(), genData, config, filepath.Join(dir, "init_decorators.go")))
+
+	content, err := os.ReadFile(filepath.Join(dir, "api.proto"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "service UserService")
+	assert.NotEmpty(t, genData.Metadata["grpc_descriptor"])
+
+	// Without @GRPC routes nothing is written
+	genData = &GenData{Metadata: map[string]interface{}{}}
+	require.NoError(t, writeGRPCProto(grpcTestRoutes()[3:], genData, config, filepath.Join(dir, "other", "init_decorators.go")))
+	assert.NotContains(t, genData.Metadata, "grpc_descriptor")
+}
@@ -0,0 +1,172 @@
+package decorators
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestParseGRPCArgs(t *testing.T) {
+	binding, err := parseGRPCArgs([]string{`service="UserService"`}, "GetUser")
+	require.NoError(t, err)
+	assert.Equal(t, &GRPCBinding{Service: "UserService", Method: "GetUser"}, binding)
+
+	binding, err = parseGRPCArgs([]string{"service=UserService", "method=Fetch"}, "GetUser")
+	require.NoError(t, err)
+	assert.Equal(t, "Fetch", binding.Method)
+
+	_, err = parseGRPCArgs(nil, "GetUser")
+	assert.ErrorContains(t, err, "requires a service")
+	_, err = parseGRPCArgs([]string{"service=user-service"}, "GetUser")
+	assert.ErrorContains(t, err, "invalid service name")
+	_, err = parseGRPCArgs([]string{"service=Users", "stream=true"}, "GetUser")
+	assert.ErrorContains(t, err, "unknown argument 'stream'")
+}
+
+func TestGRPCCodeFromHTTP(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, grpcCodeFromHTTP(http.StatusBadRequest))
+	assert.Equal(t, codes.Unauthenticated, grpcCodeFromHTTP(http.StatusUnauthorized))
+	assert.Equal(t, codes.PermissionDenied, grpcCodeFromHTTP(http.StatusForbidden))
+	assert.Equal(t, codes.NotFound, grpcCodeFromHTTP(http.StatusNotFound))
+	assert.Equal(t, codes.ResourceExhausted, grpcCodeFromHTTP(http.StatusTooManyRequests))
+	assert.Equal(t, codes.Unavailable, grpcCodeFromHTTP(http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Internal, grpcCodeFromHTTP(http.StatusBadGateway))
+}
+
+func TestGRPCServerServesRoutes(t *testing.T) {
+	registerGRPCTestSchemas(t)
+	saved := routes
+	defer func() {
+		routes = saved
+		grpcMutex.Lock()
+		grpcDescriptor = nil
+		grpcMutex.Unlock()
+	}()
+
+	metas := grpcTestRoutes()
+	file, err := BuildGRPCDescriptor(metas, "grpctest")
+	require.NoError(t, err)
+	raw, err := proto.Marshal(file)
+	require.NoError(t, err)
+	RegisterGRPCDescriptor(string(raw))
+
+	// The middleware of the route applies to gRPC calls as well
+	requireToken := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer secret" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "missing token"})
+			return
+		}
+		c.Header("X-Served-By", "gin")
+		c.Next()
+	}
+	routes = []RouteEntry{
+		{Method: "GET", Path: "/users/:id", GRPC: metas[0].GRPC, Parameters: metas[0].Parameters, Handler: func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"id": 42, "name": c.Param("id") + ":" + c.Query("fields"), "createdAt": "2024-01-01T00:00:00Z", "unknown": true})
+		}},
+		{Method: "GET", Path: "/users", GRPC: metas[1].GRPC, Handler: func(c *gin.Context) {
+			c.JSON(http.StatusOK, []gin.H{{"id": 1}, {"id": 2}})
+		}},
+		{Method: "POST", Path: "/users", GRPC: metas[2].GRPC, Handler: func(c *gin.Context) {
+			var body struct {
+				Name string `json:"name"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil || body.Name == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+				return
+			}
+			c.JSON(http.StatusCreated, gin.H{"id": 7, "name": body.Name})
+		}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	for _, route := range routes {
+		engine.Handle(route.Method, route.Path, requireToken, route.Handler)
+	}
+
+	server, err := NewGRPCServer(engine)
+	require.NoError(t, err)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	fd, err := protodesc.NewFile(file, nil)
+	require.NoError(t, err)
+	service := fd.Services().ByName("UserService")
+	invoke := func(ctx context.Context, method string, fill func(protoreflect.Message)) (*dynamicpb.Message, metadata.MD, error) {
+		descriptor := service.Methods().ByName(protoreflect.Name(method))
+		in := dynamicpb.NewMessage(descriptor.Input())
+		if fill != nil {
+			fill(in)
+		}
+		out := dynamicpb.NewMessage(descriptor.Output())
+		var header metadata.MD
+		err := conn.Invoke(ctx, "/grpctest.UserService/"+method, in, out, grpc.Header(&header))
+		return out, header, err
+	}
+	authorized := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+
+	// Path and query parameters become the HTTP request of the route
+	out, header, err := invoke(authorized, "GetUser", func(in protoreflect.Message) {
+		in.Set(in.Descriptor().Fields().ByName("id"), protoreflect.ValueOfInt64(5))
+		in.Set(in.Descriptor().Fields().ByName("fields"), protoreflect.ValueOfString("name"))
+	})
+	require.NoError(t, err)
+	fields := out.Descriptor().Fields()
+	assert.Equal(t, int64(42), out.Get(fields.ByName("id")).Int())
+	assert.Equal(t, "5:name", out.Get(fields.ByName("name")).String())
+	assert.Equal(t, "2024-01-01T00:00:00Z", out.Get(fields.ByName("created_at")).String())
+	assert.Equal(t, []string{"gin"}, header.Get("x-served-by"))
+
+	// Middleware rejections map to gRPC status codes
+	_, _, err = invoke(context.Background(), "GetUser", nil)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "missing token", status.Convert(err).Message())
+
+	// Array responses fill the items of the wrapper
+	out, _, err = invoke(authorized, "ListUsers", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, out.Get(out.Descriptor().Fields().ByName("items")).List().Len())
+
+	// The body field is sent as the JSON body
+	out, _, err = invoke(authorized, "CreateUser", func(in protoreflect.Message) {
+		body := in.NewField(in.Descriptor().Fields().ByName("body")).Message()
+		body.Set(body.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString("Ana"))
+		in.Set(in.Descriptor().Fields().ByName("body"), protoreflect.ValueOfMessage(body))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", out.Get(out.Descriptor().Fields().ByName("name")).String())
+
+	_, _, err = invoke(authorized, "CreateUser", nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNewGRPCServerWithoutDescriptor(t *testing.T) {
+	grpcMutex.Lock()
+	grpcDescriptor = nil
+	grpcMutex.Unlock()
+
+	_, err := NewGRPCServer(gin.New())
+	assert.ErrorContains(t, err, "no gRPC descriptor registered")
+}
@@ -0,0 +1,510 @@
+package decorators
+
+import (
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/tools/go/packages"
+)
+
+// Request and response types are inferred from the handler, so @Param(location=body) and @Response
+// only document what the code does not show:
+//
+//	func CreateUser(c *gin.Context, req CreateUserRequest) (UserResponse, error)  // typed handler
+//	func CreateUser(c *gin.Context) { ...; c.ShouldBindJSON(&req); ...; c.JSON(http.StatusCreated, UserResponse{...}) }
+//
+// Explicit decorators take precedence over the inferred types.
+
+// ginPackagePath import path of gin, whose *gin.Context the type-checked inference recognizes by type
+const ginPackagePath = "github.com/gin-gonic/gin"
+
+// bodyBindMethods gin.Context methods that bind the request body into their first argument
+var bodyBindMethods = map[string]bool{
+	"ShouldBindJSON": true, "BindJSON": true, "ShouldBind": true, "Bind": true,
+	"ShouldBindWith": true, "BindWith": true, "ShouldBindBodyWith": true,
+	"ShouldBindXML": true, "BindXML": true, "ShouldBindYAML": true, "BindYAML": true,
+}
+
+// jsonWriteMethods gin.Context methods writing their second argument as the response body
+var jsonWriteMethods = map[string]bool{
+	"JSON": true, "IndentedJSON": true, "PureJSON": true, "SecureJSON": true, "AsciiJSON": true,
+	"AbortWithStatusJSON": true, "XML": true, "YAML": true,
+}
+
+// statusConstants http.StatusXxx constant names by status code
+var statusConstants = func() map[string]int {
+	constants := make(map[string]int)
+	for code := 100; code < 600; code++ {
+		text := http.StatusText(code)
+		if text == "" {
+			continue
+		}
+		name := "Status"
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			name += strings.ToUpper(word[:1]) + word[1:]
+		}
+		constants[name] = code
+	}
+	return constants
+}()
+
+// inferHandlerTypes records the request and response types shown by the handler signature or body,
+// from the syntax: inferPackageTypes replaces them with the type-checked ones when the package loads
+func inferHandlerTypes(route *RouteMeta, funcDecl *ast.FuncDecl) {
+	ctxName, typed := handlerSignature(funcDecl)
+	if ctxName == "" && !typed {
+		return
+	}
+
+	if typed {
+		route.TypedHandler = true
+		route.InferredRequest = handlerTypeName(funcDecl.Type.Params.List[1].Type)
+		if response := handlerTypeName(funcDecl.Type.Results.List[0].Type); response != "" {
+			route.InferredResponses = []ResponseInfo{{Code: "200", Description: http.StatusText(http.StatusOK), Type: response}}
+		}
+		return
+	}
+	if funcDecl.Body == nil {
+		return
+	}
+
+	vars := declaredVariableTypes(funcDecl.Body)
+	inferBodyTypes(route, funcDecl.Body, handlerTypeResolver{
+		isContext: func(expr ast.Expr) bool {
+			receiver, ok := expr.(*ast.Ident)
+			return ok && receiver.Name == ctxName
+		},
+		typeName: func(expr ast.Expr) string { return expressionTypeName(expr, vars) },
+		status:   statusCodeOf,
+	})
+}
+
+// handlerTypeResolver resolves the expressions of a handler body, from the syntax or the type information
+type handlerTypeResolver struct {
+	isContext func(expr ast.Expr) bool   // the expression is the *gin.Context
+	typeName  func(expr ast.Expr) string // schema name of the type of the expression, "" when unknown
+	status    func(expr ast.Expr) int    // status code of the expression, 0 when unknown
+}
+
+// inferBodyTypes records the request bound and the responses written through the *gin.Context in body
+func inferBodyTypes(route *RouteMeta, body *ast.BlockStmt, resolver handlerTypeResolver) {
+	seen := make(map[string]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !resolver.isContext(selector.X) {
+			return true
+		}
+
+		switch {
+		case bodyBindMethods[selector.Sel.Name] && len(call.Args) > 0 && route.InferredRequest == "":
+			route.InferredRequest = resolver.typeName(call.Args[0])
+		case jsonWriteMethods[selector.Sel.Name] && len(call.Args) == 2:
+			code := resolver.status(call.Args[0])
+			typeName := resolver.typeName(call.Args[1])
+			if code == 0 || typeName == "" || seen[strconv.Itoa(code)] {
+				return true
+			}
+			seen[strconv.Itoa(code)] = true
+			route.InferredResponses = append(route.InferredResponses, ResponseInfo{
+				Code:        strconv.Itoa(code),
+				Description: http.StatusText(code),
+				Type:        typeName,
+			})
+		}
+		return true
+	})
+}
+
+// inferPackageTypes infers the request and response types of the routes again from the type-checked
+// package of rootDir, which sees what the syntax does not: variables holding function results, types of
+// other packages (qualified, models.User, so they do not collide with the handler's own) and the
+// *gin.Context by its type. Routes keep the syntax-based types when the package does not load or
+// type-check, e.g. sources outside a module.
+func inferPackageTypes(rootDir string, routes []*RouteMeta) {
+	if len(routes) == 0 {
+		return
+	}
+	dir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return
+	}
+
+	// Dependencies are type-checked from source, not from export data the installed go may write in a
+	// newer format, and without their function bodies, which the handlers' types do not need
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir: dir,
+		ParseFile: func(fset *token.FileSet, fileName string, src []byte) (*ast.File, error) {
+			file, err := parser.ParseFile(fset, fileName, src, parser.ParseComments|parser.SkipObjectResolution)
+			if err != nil || filepath.Dir(fileName) == dir {
+				return file, err
+			}
+			for _, decl := range file.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+					funcDecl.Body = nil
+				}
+			}
+			return file, nil
+		},
+	}, ".")
+	if err != nil || len(pkgs) != 1 || len(pkgs[0].Errors) > 0 {
+		LogVerbose("Handler types inferred from the syntax: %s does not type-check", rootDir)
+		return
+	}
+	pkg := pkgs[0]
+
+	// Handlers by file and name, "users.go:UserController.Get"
+	handlers := make(map[string]*ast.FuncDecl)
+	for _, file := range pkg.Syntax {
+		fileName := filepath.Base(pkg.Fset.File(file.Pos()).Name())
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				handlers[fileName+":"+handlerFuncName(funcDecl)] = funcDecl
+			}
+		}
+	}
+
+	for _, route := range routes {
+		funcDecl, ok := handlers[route.FileName+":"+route.FuncName]
+		if !ok || route.PackageName != pkg.Name {
+			continue // e.g. handlers of _test.go files
+		}
+		route.TypedHandler, route.InferredRequest, route.InferredResponses = false, "", nil
+		inferCheckedHandlerTypes(route, funcDecl, pkg.Types, pkg.TypesInfo)
+	}
+}
+
+// inferCheckedHandlerTypes records the request and response types of a handler from the type information
+func inferCheckedHandlerTypes(route *RouteMeta, funcDecl *ast.FuncDecl, pkg *types.Package, info *types.Info) {
+	fn, ok := info.Defs[funcDecl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	signature := fn.Type().(*types.Signature)
+	params, results := signature.Params(), signature.Results()
+	if params.Len() == 0 || !isGinContextType(params.At(0).Type()) {
+		return
+	}
+
+	if params.Len() == 2 && results.Len() == 2 && !signature.Variadic() && isErrorTypeOf(results.At(1).Type()) {
+		route.TypedHandler = true
+		route.InferredRequest = checkedTypeName(params.At(1).Type(), pkg)
+		if response := checkedTypeName(results.At(0).Type(), pkg); response != "" {
+			route.InferredResponses = []ResponseInfo{{Code: "200", Description: http.StatusText(http.StatusOK), Type: response}}
+		}
+		return
+	}
+	if funcDecl.Body == nil {
+		return
+	}
+
+	inferBodyTypes(route, funcDecl.Body, handlerTypeResolver{
+		isContext: func(expr ast.Expr) bool { return isGinContextType(info.TypeOf(expr)) },
+		typeName:  func(expr ast.Expr) string { return checkedTypeName(info.TypeOf(expr), pkg) },
+		status: func(expr ast.Expr) int {
+			if value := info.Types[expr].Value; value != nil {
+				if code, exact := constant.Int64Val(constant.ToInt(value)); exact {
+					return int(code)
+				}
+			}
+			return 0
+		},
+	})
+}
+
+// isGinContextType reports whether typ is *gin.Context of github.com/gin-gonic/gin
+func isGinContextType(typ types.Type) bool {
+	pointer, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := types.Unalias(pointer.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == ginPackagePath && named.Obj().Name() == "Context"
+}
+
+// isErrorTypeOf reports whether typ is the error type
+func isErrorTypeOf(typ types.Type) bool {
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
+// checkedTypeName schema name of a checked type: T for types of pkg, models.T for those of other packages, *T
+// gives T and []T gives []T. Basic types keep their name; maps, arrays, interfaces, anonymous structs
+// and gin types (gin.H) have no schema.
+func checkedTypeName(typ types.Type, pkg *types.Package) string {
+	switch typ := types.Unalias(typ).(type) {
+	case *types.Pointer:
+		return checkedTypeName(typ.Elem(), pkg)
+	case *types.Slice:
+		if item := checkedTypeName(typ.Elem(), pkg); item != "" {
+			return "[]" + item
+		}
+	case *types.Basic:
+		if typ.Kind() != types.Invalid && typ.Kind() != types.UntypedNil {
+			return types.Default(typ).String()
+		}
+	case *types.Named:
+		obj := typ.Obj()
+		if _, isInterface := typ.Underlying().(*types.Interface); isInterface || obj.Pkg() == nil || obj.Pkg().Path() == ginPackagePath {
+			return ""
+		}
+		if obj.Pkg() == pkg {
+			return obj.Name()
+		}
+		return obj.Pkg().Name() + "." + obj.Name()
+	}
+	return ""
+}
+
+// handlerSignature returns the name of the *gin.Context parameter and whether the handler is typed:
+// func(c *gin.Context, req Req) (Res, error)
+func handlerSignature(funcDecl *ast.FuncDecl) (ctxName string, typed bool) {
+	params := funcDecl.Type.Params.List
+	if len(params) == 0 || len(params[0].Names) > 1 || !isGinContext(params[0].Type) {
+		return "", false
+	}
+	if len(params[0].Names) == 1 {
+		ctxName = params[0].Names[0].Name
+	}
+
+	results := funcDecl.Type.Results
+	typed = len(params) == 2 && len(params[1].Names) <= 1 &&
+		results != nil && len(results.List) == 2 && len(results.List[0].Names) <= 1 &&
+		isErrorType(results.List[1].Type)
+	if ctxName == "" && !typed {
+		return "", false
+	}
+	return ctxName, typed
+}
+
+// isGinContext reports whether expr is *gin.Context
+func isGinContext(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "Context"
+}
+
+// isErrorType reports whether expr is the error type
+func isErrorType(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// declaredVariableTypes types of the variables declared in body with an explicit type or a composite literal
+func declaredVariableTypes(body *ast.BlockStmt) map[string]string {
+	vars := make(map[string]string)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if stmt.Type != nil {
+					vars[name.Name] = handlerTypeName(stmt.Type)
+				} else if i < len(stmt.Values) {
+					vars[name.Name] = expressionTypeName(stmt.Values[i], vars)
+				}
+			}
+		case *ast.AssignStmt:
+			if stmt.Tok != token.DEFINE || len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					if typeName := expressionTypeName(stmt.Rhs[i], vars); typeName != "" {
+						vars[ident.Name] = typeName
+					}
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// expressionTypeName type of a composite literal, &literal, new(T) or declared variable; "" when unknown
+func expressionTypeName(expr ast.Expr, vars map[string]string) string {
+	switch value := expr.(type) {
+	case *ast.CompositeLit:
+		return handlerTypeName(value.Type)
+	case *ast.UnaryExpr:
+		if value.Op == token.AND {
+			return expressionTypeName(value.X, vars)
+		}
+	case *ast.Ident:
+		return vars[value.Name]
+	case *ast.CallExpr:
+		if ident, ok := value.Fun.(*ast.Ident); ok && ident.Name == "new" && len(value.Args) == 1 {
+			return handlerTypeName(value.Args[0])
+		}
+	case *ast.ParenExpr:
+		return expressionTypeName(value.X, vars)
+	}
+	return ""
+}
+
+// handlerTypeName schema name of a type expression: T and *T give T, pkg.T stays qualified, []T gives []T.
+// Maps, gin.H and interfaces have no schema.
+func handlerTypeName(expr ast.Expr) string {
+	switch typ := expr.(type) {
+	case *ast.Ident:
+		if typ.Name == "any" || typ.Name == "error" {
+			return ""
+		}
+		return typ.Name
+	case *ast.SelectorExpr:
+		pkg, ok := typ.X.(*ast.Ident)
+		if !ok || pkg.Name == "gin" {
+			return ""
+		}
+		return pkg.Name + "." + typ.Sel.Name
+	case *ast.StarExpr:
+		return handlerTypeName(typ.X)
+	case *ast.ArrayType:
+		if typ.Len != nil {
+			return ""
+		}
+		if item := handlerTypeName(typ.Elt); item != "" {
+			return "[]" + item
+		}
+	}
+	return ""
+}
+
+// statusCodeOf status of an integer literal or http.StatusXxx; 0 when unknown
+func statusCodeOf(expr ast.Expr) int {
+	switch status := expr.(type) {
+	case *ast.BasicLit:
+		if status.Kind == token.INT {
+			code, _ := strconv.Atoi(status.Value)
+			return code
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := status.X.(*ast.Ident); ok && pkg.Name == "http" {
+			return statusConstants[status.Sel.Name]
+		}
+	}
+	return 0
+}
+
+// applyInferredTypes adds the inferred request body and responses the decorators do not declare
+func applyInferredTypes(route *RouteMeta, parameters []ParameterInfo, responses []ResponseInfo) ([]ParameterInfo, []ResponseInfo) {
+	if route.InferredRequest != "" {
+		hasBody := false
+		for _, param := range parameters {
+			hasBody = hasBody || param.Location == "body"
+		}
+		if !hasBody {
+			parameters = append(parameters, ParameterInfo{
+				Name:        "body",
+				Type:        route.InferredRequest,
+				Location:    "body",
+				Required:    true,
+				Description: "Request body",
+			})
+		}
+	}
+
+	declared := make(map[string]bool, len(responses))
+	for _, response := range responses {
+		declared[response.Code] = true
+	}
+	for _, response := range route.InferredResponses {
+		if !declared[response.Code] {
+			responses = append(responses, response)
+		}
+	}
+	return parameters, responses
+}
+
+// HTTPError error returned by a typed handler to answer with a status other than 500
+type HTTPError struct {
+	Status  int    `json:"-"`
+	Message string `json:"message"`
+}
+
+// NewHTTPError creates an HTTPError
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error implements error
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// StatusCode status of the response
+func (e *HTTPError) StatusCode() int {
+	return e.Status
+}
+
+// Typed adapts a typed handler, func(c *gin.Context, req Req) (Res, error), to gin: the request is
+// bound (JSON body, form or query, by method and content type) and validated, and the result is written
+// as JSON with status 200. Binding errors answer 400; errors with a StatusCode() int method answer that
+// status and other errors 500. A handler that writes the response itself is left alone.
+func Typed[Req, Res any](handler func(c *gin.Context, req Req) (Res, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req Req
+		err := c.ShouldBind(&req)
+		if errors.Is(err, io.EOF) {
+			// Empty body: nothing to decode, the fields are still validated
+			err = binding.Validator.ValidateStruct(&req)
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, bindingErrorResponse(err))
+			return
+		}
+
+		res, err := handler(c, req)
+		if c.Writer.Written() {
+			return
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			var coded interface{ StatusCode() int }
+			if errors.As(err, &coded) {
+				status = coded.StatusCode()
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": http.StatusText(status), "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// bindingErrorResponse describes a binding error in the format of the validation middlewares
+func bindingErrorResponse(err error) ValidationResponse {
+	response := ValidationResponse{Error: "validation_failed", Message: "Invalid request"}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		response.Fields = []ValidationField{{Field: "body", Message: err.Error()}}
+		return response
+	}
+	for _, fieldErr := range fieldErrors {
+		response.Fields = append(response.Fields, ValidationField{
+			Field:   fieldErr.Field(),
+			Tag:     fieldErr.Tag(),
+			Message: getValidationMessage(fieldErr, &ValidationConfig{}),
+			Param:   fieldErr.Param(),
+		})
+	}
+	return response
+}
@@ -0,0 +1,252 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirectory_InferredTypes(t *testing.T) {
+	savedParserHooks := parserHooks
+	parserHooks = nil
+	defer func() { parserHooks = savedParserHooks }()
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"example.com/app/models"
+)
+
+// @Route("POST", "/users")
+func CreateUser(c *gin.Context) {
+	var req models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	user := &UserResponse{Name: req.Name}
+	c.JSON(http.StatusCreated, user)
+}
+
+// @Route("GET", "/users")
+// @Response(code=200, description="The users", type="UserPage")
+func ListUsers(c *gin.Context) {
+	c.JSON(200, []UserResponse{})
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+}
+
+// @Route("PUT", "/users/:id")
+// @Param(name="user", type="UpdateUserRequest", location="body", required=true, description="Changes")
+func UpdateUser(ctx *gin.Context, req PatchUserRequest) (*UserResponse, error) {
+	return nil, nil
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	byName := make(map[string]*RouteMeta)
+	for _, route := range routes {
+		byName[route.FuncName] = route
+	}
+
+	create := byName["CreateUser"]
+	require.Len(t, create.Parameters, 1)
+	assert.Equal(t, ParameterInfo{Name: "body", Type: "models.CreateUserRequest", Location: "body", Required: true, Description: "Request body"}, create.Parameters[0])
+	assert.Equal(t, []ResponseInfo{
+		{Code: "400", Description: "Bad Request", Type: "ErrorResponse"},
+		{Code: "201", Description: "Created", Type: "UserResponse"},
+	}, create.Responses)
+	assert.False(t, create.TypedHandler)
+
+	// Explicit decorators win; untyped bodies (gin.H) are not documented
+	list := byName["ListUsers"]
+	assert.Equal(t, []ResponseInfo{{Code: "200", Description: "The users", Type: "UserPage"}}, list.Responses)
+
+	update := byName["UpdateUser"]
+	assert.True(t, update.TypedHandler)
+	require.Len(t, update.Parameters, 1)
+	assert.Equal(t, "UpdateUserRequest", update.Parameters[0].Type)
+	assert.Equal(t, []ResponseInfo{{Code: "200", Description: "OK", Type: "UserResponse"}}, update.Responses)
+}
+
+func TestParseDirectory_CheckedTypes(t *testing.T) {
+	savedParserHooks := parserHooks
+	parserHooks = nil
+	defer func() { parserHooks = savedParserHooks }()
+
+	// Inside this module, so the package type-checks against the real gin
+	dir, err := os.MkdirTemp(".", "_checked")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "models"), 0o755))
+	models := `package models
+
+// User of another package with the name of one of the handlers
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+	source := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/RodolfoBonis/deco/pkg/decorators/` + filepath.Base(dir) + `/models"
+)
+
+const statusAccepted = http.StatusAccepted
+
+type User struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type UserService struct{}
+
+func (s UserService) Build() CreateUserRequest { return CreateUserRequest{} }
+
+func (s UserService) Find() (*models.User, error) { return nil, nil }
+
+type CreateUserRequest struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// @Route("POST", "/users")
+func CreateUser(ctx *gin.Context) {
+	req := UserService{}.Build()
+	c := ctx
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return
+	}
+	user, _ := UserService{}.Find()
+	c.JSON(statusAccepted, user)
+	c.JSON(http.StatusOK, User{})
+}
+
+// Context is not gin's: nothing is inferred
+type Context struct{}
+
+func (c *Context) JSON(code int, obj any) {}
+
+// @Route("GET", "/other")
+func Other(c *Context) {
+	c.JSON(http.StatusOK, User{})
+}
+
+// @Route("PUT", "/users/:id")
+func UpdateUser(c *gin.Context, req models.User) ([]User, error) {
+	return nil, nil
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models", "user.go"), []byte(models), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	byName := make(map[string]*RouteMeta)
+	for _, route := range routes {
+		byName[route.FuncName] = route
+	}
+
+	create := byName["CreateUser"]
+	assert.Equal(t, "CreateUserRequest", create.InferredRequest, "variables holding function results are resolved")
+	assert.Equal(t, []ResponseInfo{
+		{Code: "202", Description: "Accepted", Type: "models.User"},
+		{Code: "200", Description: "OK", Type: "User"},
+	}, create.InferredResponses)
+
+	other := byName["Other"]
+	assert.Empty(t, other.InferredResponses, "only *gin.Context of github.com/gin-gonic/gin is a context")
+	assert.False(t, other.TypedHandler)
+
+	update := byName["UpdateUser"]
+	assert.True(t, update.TypedHandler)
+	assert.Equal(t, "models.User", update.InferredRequest)
+	assert.Equal(t, []ResponseInfo{{Code: "200", Description: "OK", Type: "[]User"}}, update.InferredResponses)
+}
+
+func TestStatusConstants(t *testing.T) {
+	assert.Equal(t, 200, statusConstants["StatusOK"])
+	assert.Equal(t, 422, statusConstants["StatusUnprocessableEntity"])
+	assert.Equal(t, 505, statusConstants["StatusHTTPVersionNotSupported"])
+}
+
+type typedCreateRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type typedCreateResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestTyped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", Typed(func(c *gin.Context, req typedCreateRequest) (typedCreateResponse, error) {
+		if req.Name == "taken" {
+			return typedCreateResponse{}, NewHTTPError(http.StatusConflict, "name already taken")
+		}
+		return typedCreateResponse{ID: "42", Name: req.Name}, nil
+	}))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(`{"name":"ana"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"42","name":"ana"}`, w.Body.String())
+
+	w = serve(`{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"tag":"required"`)
+
+	w = serve(``)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "an empty body is still validated")
+
+	w = serve(`{"name":"taken"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "name already taken")
+}
+
+func TestGenerateInitFile_TypedHandler(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("POST", "/users")
+func CreateUser(c *gin.Context, req CreateUserRequest) (UserResponse, error) {
+	return UserResponse{}, nil
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig()))
+
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `Handler:     decorators.Typed(CreateUser),`)
+}
@@ -0,0 +1,724 @@
+package decorators
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	_ "crypto/sha256" // SHA-256 for HS256/RS256/PS256/ES256
+	_ "crypto/sha512" // SHA-384/512 for the other algorithms
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWT verification of the bearer tokens of @Auth (auth.jwt)
+
+// jwksRefetchInterval minimum interval between fetches of the JWKS triggered by an unknown "kid"
+const jwksRefetchInterval = 30 * time.Second
+
+// ErrInvalidToken wraps every verification failure of JWTVerifier.Verify
+var ErrInvalidToken = errors.New("invalid token")
+
+// jwtAlgorithm signature family and hash of a JWS "alg"
+type jwtAlgorithm struct {
+	family string // HS, RS, PS, ES or EdDSA
+	hash   crypto.Hash
+	curve  elliptic.Curve // ES only
+}
+
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	"HS256": {family: "HS", hash: crypto.SHA256},
+	"HS384": {family: "HS", hash: crypto.SHA384},
+	"HS512": {family: "HS", hash: crypto.SHA512},
+	"RS256": {family: "RS", hash: crypto.SHA256},
+	"RS384": {family: "RS", hash: crypto.SHA384},
+	"RS512": {family: "RS", hash: crypto.SHA512},
+	"PS256": {family: "PS", hash: crypto.SHA256},
+	"PS384": {family: "PS", hash: crypto.SHA384},
+	"PS512": {family: "PS", hash: crypto.SHA512},
+	"ES256": {family: "ES", hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384": {family: "ES", hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512": {family: "ES", hash: crypto.SHA512, curve: elliptic.P521()},
+	"EdDSA": {family: "EdDSA"},
+}
+
+// jwtAlgorithmNames supported "alg" values, sorted
+func jwtAlgorithmNames() []string {
+	names := make([]string, 0, len(jwtAlgorithms))
+	for name := range jwtAlgorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// JWTClaims payload of a verified token
+type JWTClaims map[string]interface{}
+
+// Get returns a claim; dotted paths reach nested objects (realm_access.roles)
+func (c JWTClaims) Get(path string) (interface{}, bool) {
+	if value, ok := c[path]; ok {
+		return value, true
+	}
+	var current interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// String returns a string (or numeric) claim, empty when missing
+func (c JWTClaims) String(path string) string {
+	value, _ := c.Get(path)
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+// Strings returns a list claim; a string is split on spaces and commas (OAuth "scope")
+func (c JWTClaims) Strings(path string) []string {
+	value, _ := c.Get(path)
+	switch v := value.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// Subject returns the "sub" claim
+func (c JWTClaims) Subject() string {
+	return c.String("sub")
+}
+
+// time returns a NumericDate claim
+func (c JWTClaims) time(name string) (time.Time, bool, error) {
+	value, ok := c[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("%w: claim %s is not a number", ErrInvalidToken, name)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: claim %s is not a number", ErrInvalidToken, name)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// jwtKey public key of an issuer
+type jwtKey struct {
+	id  string
+	alg string // restricts the key to an algorithm when set
+	key crypto.PublicKey
+}
+
+// accepts reports whether the key can verify a signature of the algorithm
+func (k jwtKey) accepts(alg string, algorithm jwtAlgorithm) bool {
+	if k.alg != "" && k.alg != alg {
+		return false
+	}
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		return algorithm.family == "RS" || algorithm.family == "PS"
+	case *ecdsa.PublicKey:
+		return algorithm.family == "ES" && key.Curve == algorithm.curve
+	case ed25519.PublicKey:
+		return algorithm.family == "EdDSA"
+	}
+	return false
+}
+
+// verify checks the signature of the signing input
+func (k jwtKey) verify(algorithm jwtAlgorithm, input, signature []byte) bool {
+	if algorithm.family == "EdDSA" {
+		return ed25519.Verify(k.key.(ed25519.PublicKey), input, signature)
+	}
+
+	hasher := algorithm.hash.New()
+	hasher.Write(input)
+	digest := hasher.Sum(nil)
+
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		if algorithm.family == "PS" {
+			return rsa.VerifyPSS(key, algorithm.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(key, algorithm.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// JWTVerifier verifies signed JWTs (JWS compact serialization) against an HMAC secret, PEM public keys
+// and/or a JWKS endpoint, then checks exp, nbf, iss and aud
+type JWTVerifier struct {
+	config     JWTConfig
+	algorithms map[string]bool
+	leeway     time.Duration
+	keys       []jwtKey
+	jwks       *jwksCache
+
+	secretMutex sync.Mutex
+	secret      []byte
+
+	now func() time.Time
+}
+
+// NewJWTVerifier builds a verifier; public key files are read now, the HMAC secret on first use
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	verifier := &JWTVerifier{config: config, algorithms: make(map[string]bool), now: time.Now}
+	algorithms := config.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = jwtAlgorithmNames()
+	}
+	for _, alg := range algorithms {
+		verifier.algorithms[alg] = true
+	}
+	if config.Leeway != "" {
+		verifier.leeway, _ = ParseDurationLiteral(config.Leeway)
+	}
+
+	for _, file := range config.PublicKeys {
+		keys, err := loadPEMKeys(file)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = append(verifier.keys, keys...)
+	}
+
+	if config.JWKSURL != "" {
+		refresh := 10 * time.Minute
+		if config.JWKSRefresh != "" {
+			refresh, _ = ParseDurationLiteral(config.JWKSRefresh)
+		}
+		verifier.jwks = &jwksCache{url: config.JWKSURL, refresh: refresh, client: &http.Client{Timeout: 10 * time.Second}}
+	}
+	return verifier, nil
+}
+
+// validate checks the JWT configuration
+func (j JWTConfig) validate() error {
+	for _, alg := range j.Algorithms {
+		if _, ok := jwtAlgorithms[alg]; !ok {
+			return fmt.Errorf("invalid auth.jwt.algorithms '%s' (supported: %s)", alg, strings.Join(jwtAlgorithmNames(), ", "))
+		}
+	}
+	if j.JWKSRefresh != "" {
+		if refresh, err := ParseDurationLiteral(j.JWKSRefresh); err != nil || refresh <= 0 {
+			return fmt.Errorf("invalid auth.jwt.jwks_refresh '%s'", j.JWKSRefresh)
+		}
+	}
+	if j.Leeway != "" {
+		if leeway, err := ParseDurationLiteral(j.Leeway); err != nil || leeway < 0 {
+			return fmt.Errorf("invalid auth.jwt.leeway '%s'", j.Leeway)
+		}
+	}
+	if j.JWKSURL != "" && !strings.HasPrefix(j.JWKSURL, "https://") && !strings.HasPrefix(j.JWKSURL, "http://") {
+		return fmt.Errorf("invalid auth.jwt.jwks_url '%s'", j.JWKSURL)
+	}
+	return nil
+}
+
+// enabled reports whether a key source is configured
+func (j JWTConfig) enabled() bool {
+	return j.SecretKey != "" || len(j.PublicKeys) > 0 || j.JWKSURL != ""
+}
+
+// Verify checks the signature and the registered claims of a token and returns its claims
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	algorithm, supported := jwtAlgorithms[header.Alg]
+	if !supported || !v.algorithms[header.Alg] {
+		return nil, fmt.Errorf("%w: algorithm '%s' not accepted", ErrInvalidToken, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	input := []byte(parts[0] + "." + parts[1])
+	if err := v.verifySignature(ctx, header.Alg, header.Kid, algorithm, input, signature); err != nil {
+		return nil, err
+	}
+
+	var claims JWTClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil || claims == nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature checks the signature with the HMAC secret or the keys matching kid
+func (v *JWTVerifier) verifySignature(ctx context.Context, alg, kid string, algorithm jwtAlgorithm, input, signature []byte) error {
+	if algorithm.family == "HS" {
+		secret, err := v.hmacSecret(ctx)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(algorithm.hash.New, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	}
+
+	keys := v.keys
+	if v.jwks != nil {
+		fetched, err := v.jwks.lookup(ctx, kid)
+		if err != nil && len(keys) == 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		keys = append(append([]jwtKey{}, keys...), fetched...)
+	}
+	for _, key := range keys {
+		if kid != "" && key.id != "" && key.id != kid {
+			continue
+		}
+		if key.accepts(alg, algorithm) && key.verify(algorithm, input, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+}
+
+// hmacSecret reads the HMAC key from the secret provider on first use
+func (v *JWTVerifier) hmacSecret(ctx context.Context) ([]byte, error) {
+	if v.config.SecretKey == "" {
+		return nil, fmt.Errorf("%w: no HMAC secret configured", ErrInvalidToken)
+	}
+	v.secretMutex.Lock()
+	defer v.secretMutex.Unlock()
+	if v.secret == nil {
+		secret, err := GetSecret(ctx, v.config.SecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		v.secret = secret
+	}
+	return v.secret, nil
+}
+
+// checkClaims checks exp, nbf, iss and aud
+func (v *JWTVerifier) checkClaims(claims JWTClaims) error {
+	now := v.now()
+	expires, ok, err := claims.time("exp")
+	if err != nil {
+		return err
+	}
+	if ok && now.After(expires.Add(v.leeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	notBefore, ok, err := claims.time("nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(v.leeway).Before(notBefore) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	if v.config.Issuer != "" && claims.String("iss") != v.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer '%s'", ErrInvalidToken, claims.String("iss"))
+	}
+	if len(v.config.Audience) > 0 {
+		audiences := claims.Strings("aud")
+		if s, ok := claims["aud"].(string); ok {
+			audiences = []string{s} // a single audience is not a list
+		}
+		if !containsAny(audiences, v.config.Audience) {
+			return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+		}
+	}
+	return nil
+}
+
+// containsAny reports whether list contains one of the values
+func containsAny(list, values []string) bool {
+	for _, item := range list {
+		for _, value := range values {
+			if item == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeJWTSegment decodes a base64url JSON segment; numbers are kept as json.Number
+func decodeJWTSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// loadPEMKeys reads the public keys and certificates of a PEM file
+func loadPEMKeys(file string) ([]jwtKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("auth.jwt.public_keys: %v", err)
+	}
+
+	var keys []jwtKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		var key crypto.PublicKey
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var certificate *x509.Certificate
+			if certificate, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = certificate.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("auth.jwt.public_keys: %s: %v", file, err)
+		}
+		keys = append(keys, jwtKey{key: key})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("auth.jwt.public_keys: %s: no public key found", file)
+	}
+	return keys, nil
+}
+
+// jwksCache keys of a JWKS endpoint, fetched on first use and kept for the refresh interval;
+// an unknown "kid" (key rotation) triggers a new fetch at most every jwksRefetchInterval
+type jwksCache struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu          sync.Mutex
+	keys        []jwtKey
+	fetched     time.Time
+	lastAttempt time.Time
+}
+
+// lookup returns the current keys, fetching them when stale or when kid is unknown
+func (j *jwksCache) lookup(ctx context.Context, kid string) ([]jwtKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	stale := j.fetched.IsZero() || now.Sub(j.fetched) > j.refresh
+	if !stale && kid != "" && !j.hasKey(kid) && now.Sub(j.lastAttempt) > jwksRefetchInterval {
+		stale = true
+	}
+	if stale && now.Sub(j.lastAttempt) > time.Second {
+		j.lastAttempt = now
+		keys, err := fetchJWKS(ctx, j.client, j.url)
+		if err != nil {
+			if j.keys == nil {
+				return nil, err
+			}
+			LogVerbose("⚠️  JWKS refresh failed, keeping the previous keys: %v", err)
+		} else {
+			j.keys, j.fetched = keys, now
+		}
+	}
+	if j.keys == nil {
+		return nil, errors.New("jwks: keys not available yet")
+	}
+	return j.keys, nil
+}
+
+// hasKey reports whether a key has the id
+func (j *jwksCache) hasKey(kid string) bool {
+	for _, key := range j.keys {
+		if key.id == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonWebKey JWK of a JWKS document
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchJWKS downloads a JWKS document; keys of unsupported types or for encryption are skipped
+func fetchJWKS(ctx context.Context, client *http.Client, url string) ([]jwtKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: %s answered %d", url, resp.StatusCode)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("jwks: %v", err)
+	}
+
+	keys := make([]jwtKey, 0, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			LogVerbose("⚠️  JWKS key '%s' skipped: %v", jwk.Kid, err)
+			continue
+		}
+		keys = append(keys, jwtKey{id: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA, EC or OKP (Ed25519) key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var check ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, check = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, check = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, check = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("invalid EC key")
+		}
+		// crypto/ecdh rejects points that are not on the curve
+		if _, err := check.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, errors.New("invalid EC key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve '%s'", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type '%s'", k.Kty)
+}
+
+var (
+	jwtVerifierMutex sync.RWMutex
+	jwtVerifier      *JWTVerifier
+)
+
+// ConfigureAuth installs the JWT verifier used by @Auth; without a key source (auth.jwt) tokens are
+// only required to be present
+func ConfigureAuth(config AuthConfig) error {
+	var verifier *JWTVerifier
+	if config.JWT.enabled() {
+		built, err := NewJWTVerifier(config.JWT)
+		if err != nil {
+			return err
+		}
+		verifier = built
+	}
+
+	var issuer *TokenIssuer
+	if config.Tokens.signs(config.JWT) {
+		built, err := NewTokenIssuer(config)
+		if err != nil {
+			return err
+		}
+		issuer = built
+		if issuer.key != nil {
+			// Access tokens signed with the private key are accepted by @Auth without listing its public key
+			if verifier == nil {
+				if verifier, err = NewJWTVerifier(config.JWT); err != nil {
+					return err
+				}
+			}
+			verifier.keys = append(verifier.keys, issuer.publicKey())
+		}
+	}
+
+	var cacheTTL time.Duration
+	if config.CacheTTL != "" {
+		ttl, err := ParseDurationLiteral(config.CacheTTL)
+		if err != nil || ttl < 0 {
+			return fmt.Errorf("invalid auth.cache_ttl '%s'", config.CacheTTL)
+		}
+		cacheTTL = ttl
+	}
+
+	jwtVerifierMutex.Lock()
+	jwtVerifier = verifier
+	jwtVerifierMutex.Unlock()
+
+	tokenIssuerMutex.Lock()
+	tokenIssuer = issuer
+	tokenIssuerMutex.Unlock()
+
+	// Decisions taken with the previous keys are dropped
+	authCacheMutex.Lock()
+	authCacheTTL = cacheTTL
+	authCacheMutex.Unlock()
+	ClearAuthCache()
+	return nil
+}
+
+// GetJWTVerifier returns the installed verifier (nil when auth.jwt has no key source)
+func GetJWTVerifier() *JWTVerifier {
+	jwtVerifierMutex.RLock()
+	defer jwtVerifierMutex.RUnlock()
+	return jwtVerifier
+}
+
+// JWTClaimsFrom returns the claims of the token verified by @Auth
+func JWTClaimsFrom(c *gin.Context) (JWTClaims, bool) {
+	value, ok := c.Get("claims")
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(JWTClaims)
+	return claims, ok
+}
+
+// warmJWKS fetches the JWKS of the installed verifier during warm-up
+func warmJWKS(ctx context.Context) error {
+	verifier := GetJWTVerifier()
+	if verifier == nil || verifier.jwks == nil {
+		return nil
+	}
+	_, err := verifier.jwks.lookup(ctx, "")
+	return err
+}
+
+// resolvedAuth auth section with public key files resolved against the config file directory
+func (c *Config) resolvedAuth() AuthConfig {
+	auth := c.Auth
+	auth.JWT.PublicKeys = make([]string, len(c.Auth.JWT.PublicKeys))
+	for i, file := range c.Auth.JWT.PublicKeys {
+		auth.JWT.PublicKeys[i] = c.ResolvePath(file)
+	}
+	if c.Auth.Tokens.PrivateKey != "" {
+		auth.Tokens.PrivateKey = c.ResolvePath(c.Auth.Tokens.PrivateKey)
+	}
+	return auth
+}
@@ -0,0 +1,239 @@
+package decorators
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signTestJWT signs claims with the header fields; sign receives the signing input
+func signTestJWT(t *testing.T, header, claims map[string]interface{}, sign func(input []byte) []byte) string {
+	t.Helper()
+	encode := func(value interface{}) string {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(header) + "." + encode(claims)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(input []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(input []byte) []byte {
+		digest := sha256.Sum256(input)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		return signature
+	}
+}
+
+func TestJWTVerifierHMAC(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+
+	verifier, err := NewJWTVerifier(JWTConfig{SecretKey: "jwt-secret", Issuer: "https://issuer", Audience: []string{"api"}, Leeway: "30s"})
+	require.NoError(t, err)
+
+	now := time.Now().Unix()
+	token := signTestJWT(t, map[string]interface{}{"alg": "HS256"},
+		map[string]interface{}{"sub": "42", "iss": "https://issuer", "aud": "api", "exp": now + 60, "roles": []string{"admin"}},
+		hs256([]byte("s3cr3t")))
+	claims, err := verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "42", claims.Subject())
+	assert.Equal(t, []string{"admin"}, claims.Strings("roles"))
+
+	cases := map[string]struct {
+		header map[string]interface{}
+		claims map[string]interface{}
+		secret string
+	}{
+		"wrong secret":  {map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"iss": "https://issuer", "aud": "api"}, "other"},
+		"expired":       {map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"iss": "https://issuer", "aud": "api", "exp": now - 120}, "s3cr3t"},
+		"not yet valid": {map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"iss": "https://issuer", "aud": "api", "nbf": now + 120}, "s3cr3t"},
+		"issuer":        {map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"iss": "https://other", "aud": "api"}, "s3cr3t"},
+		"audience":      {map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"iss": "https://issuer", "aud": []string{"web"}}, "s3cr3t"},
+		"alg none":      {map[string]interface{}{"alg": "none"}, map[string]interface{}{"iss": "https://issuer", "aud": "api"}, "s3cr3t"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), signTestJWT(t, tc.header, tc.claims, hs256([]byte(tc.secret))))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	// The expiry is tolerated within the leeway
+	_, err = verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "HS256"},
+		map[string]interface{}{"iss": "https://issuer", "aud": "api", "exp": now - 10}, hs256([]byte("s3cr3t"))))
+	assert.NoError(t, err)
+}
+
+func TestJWTVerifierJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	var mu sync.Mutex
+	current := &key.PublicKey
+	kid := "k1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+			{
+				"kty": "RSA", "kid": kid, "alg": "RS256",
+				"n": base64.RawURLEncoding.EncodeToString(current.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(current.E)).Bytes()),
+			},
+		}})
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSURL: server.URL, JWKSRefresh: "1h", Algorithms: []string{"RS256"}})
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "RS256", "kid": "k1"},
+		map[string]interface{}{"sub": "ana"}, rs256(t, key)))
+	require.NoError(t, err)
+	assert.Equal(t, "ana", claims.Subject())
+
+	_, err = verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "RS256", "kid": "k1"},
+		map[string]interface{}{"sub": "ana"}, rs256(t, key)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "keys are cached")
+
+	// HS256 is not accepted, even signed with the public modulus
+	_, err = verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "HS256", "kid": "k1"},
+		map[string]interface{}{"sub": "ana"}, hs256(key.N.Bytes())))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// An unknown kid refetches the keys once the refetch interval elapsed
+	mu.Lock()
+	current, kid = &rotated.PublicKey, "k2"
+	mu.Unlock()
+	verifier.jwks.lastAttempt = time.Now().Add(-time.Minute)
+	_, err = verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "RS256", "kid": "k2"},
+		map[string]interface{}{"sub": "ana"}, rs256(t, rotated)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestJWTVerifierPEMKeys(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "issuer.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	verifier, err := NewJWTVerifier(JWTConfig{PublicKeys: []string{file}})
+	require.NoError(t, err)
+
+	es256 := func(input []byte) []byte {
+		digest := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature
+	}
+	claims, err := verifier.Verify(context.Background(), signTestJWT(t, map[string]interface{}{"alg": "ES256"},
+		map[string]interface{}{"realm_access": map[string]interface{}{"roles": []string{"ops"}}}, es256))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ops"}, claims.Strings("realm_access.roles"))
+
+	_, err = NewJWTVerifier(JWTConfig{PublicKeys: []string{filepath.Join(t.TempDir(), "missing.pem")}})
+	assert.Error(t, err)
+	_, err = NewJWTVerifier(JWTConfig{SecretKey: "k", Algorithms: []string{"none"}})
+	assert.ErrorContains(t, err, "invalid auth.jwt.algorithms 'none'")
+}
+
+func TestAuthMiddlewareWithJWT(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	require.NoError(t, ConfigureAuth(AuthConfig{JWT: JWTConfig{SecretKey: "jwt-secret", RolesClaim: "roles", UserClaim: "sub"}}))
+	defer func() { _ = ConfigureAuth(AuthConfig{}) }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", createAuthMiddleware([]string{`role="admin"`}), func(c *gin.Context) {
+		claims, _ := JWTClaimsFrom(c)
+		c.JSON(http.StatusOK, gin.H{"user": c.GetString("user_id"), "sub": claims.Subject()})
+	})
+	router.GET("/feed", createAuthMiddleware([]string{"required=false"}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"authenticated": c.GetBool("authenticated")})
+	})
+
+	request := func(path string, roles ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if roles != nil {
+			token := signTestJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "42", "roles": roles}, hs256([]byte("s3cr3t")))
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/admin", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user":"42","sub":"42"}`, w.Body.String())
+
+	w = request("/admin", "viewer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer not.a.jwt")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+
+	w = request("/feed")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"authenticated":false}`, w.Body.String())
+}
+
+func TestConfigureAuthDisabled(t *testing.T) {
+	require.NoError(t, ConfigureAuth(AuthConfig{JWT: DefaultConfig().Auth.JWT}))
+	assert.Nil(t, GetJWTVerifier())
+
+	err := ConfigureAuth(AuthConfig{JWT: JWTConfig{JWKSURL: "ftp://keys"}})
+	assert.True(t, err != nil && !errors.Is(err, ErrInvalidToken))
+}
@@ -0,0 +1,394 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Traffic learning (dev.learn): the shapes of the query parameters, request bodies and responses seen by
+// each route are recorded, and the @Param, @Response and @Schema declarations they lack are suggested
+
+// LearnDebugPath endpoint with the decorators suggested from the recorded traffic
+const LearnDebugPath = "/decorators/debug/learn"
+
+// maxObservedProperties properties recorded per object, so map-like objects do not grow without bound
+const maxObservedProperties = 100
+
+// fieldInitialisms words written in upper case in suggested field names
+var fieldInitialisms = map[string]bool{"id": true, "url": true, "uri": true, "api": true, "http": true, "json": true, "uuid": true, "ip": true}
+
+// ObservedShape JSON shape merged from the values seen at one place of the payloads
+type ObservedShape struct {
+	Type       string                    `json:"type"` // object, array, string, integer, number, boolean, null or mixed
+	Nullable   bool                      `json:"nullable,omitempty"`
+	Properties map[string]*ObservedShape `json:"properties,omitempty"`
+	Items      *ObservedShape            `json:"items,omitempty"`
+	Seen       int                       `json:"seen"` // values merged; a property seen less often than its object is optional
+}
+
+// routeObservation traffic recorded for one route
+type routeObservation struct {
+	samples   int
+	query     map[string]*ObservedShape
+	body      *ObservedShape
+	responses map[int]*ObservedShape // nil shape for responses without a JSON body
+}
+
+// LearnedRoute decorators suggested for a route from its recorded traffic
+type LearnedRoute struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Handler     string          `json:"handler,omitempty"`
+	Samples     int             `json:"samples"`
+	Annotations []string        `json:"annotations"`       // decorator comment lines missing from the handler
+	Schemas     []LearnedSchema `json:"schemas,omitempty"` // @Schema types referenced by the annotations
+}
+
+// LearnedSchema Go type suggested for an observed payload
+type LearnedSchema struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+var (
+	observations      = make(map[string]*routeObservation)
+	observationsMutex sync.Mutex
+)
+
+// TrafficLearningMiddleware records the shape of the traffic of every route for LearnReport. Only the
+// structure of the payloads is kept, never their values. It is meant for development and staging and does
+// nothing in -tags prod builds.
+func TrafficLearningMiddleware() gin.HandlerFunc {
+	if prodBuild {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		capture := CaptureBodies(c)
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" || strings.HasPrefix(route, "/decorators/") {
+			return
+		}
+		recordTraffic(c.Request.Method+" "+route, c, capture)
+	}
+}
+
+// recordTraffic merges the request and response of c into the observation of the route
+func recordTraffic(key string, c *gin.Context, capture *BodyCapture) {
+	var body, response interface{}
+	hasBody := decodeObservedJSON(c.ContentType(), capture.RequestBody(), capture.RequestSize, &body)
+	hasResponse := decodeObservedJSON(c.Writer.Header().Get("Content-Type"), capture.ResponseBody(), capture.ResponseSize, &response)
+
+	observationsMutex.Lock()
+	defer observationsMutex.Unlock()
+
+	observation := observations[key]
+	if observation == nil {
+		observation = &routeObservation{query: make(map[string]*ObservedShape), responses: make(map[int]*ObservedShape)}
+		observations[key] = observation
+	}
+	observation.samples++
+
+	for name, values := range c.Request.URL.Query() {
+		for _, value := range values {
+			observation.query[name] = observeQueryValue(observation.query[name], value)
+		}
+	}
+	if hasBody {
+		observation.body = observeJSON(observation.body, body)
+	}
+
+	status := c.Writer.Status()
+	shape, seen := observation.responses[status]
+	if hasResponse {
+		shape = observeJSON(shape, response)
+	}
+	if hasResponse || !seen {
+		observation.responses[status] = shape
+	}
+}
+
+// decodeObservedJSON decodes a complete JSON body, reporting whether there was one
+func decodeObservedJSON(contentType string, data []byte, size func() (int64, bool), value *interface{}) bool {
+	if _, truncated := size(); truncated || len(data) == 0 || !strings.Contains(contentType, "json") {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// observeJSON merges a decoded JSON value into a shape
+func observeJSON(shape *ObservedShape, value interface{}) *ObservedShape {
+	if shape == nil {
+		shape = &ObservedShape{}
+	}
+	shape.Seen++
+
+	if value == nil {
+		if shape.Type == "" {
+			shape.Type = "null"
+		}
+		shape.Nullable = true
+		return shape
+	}
+
+	shape.Type = mergeObservedType(shape.Type, observedTypeName(value))
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if shape.Properties == nil {
+			shape.Properties = make(map[string]*ObservedShape)
+		}
+		for name, property := range value {
+			if _, known := shape.Properties[name]; known || len(shape.Properties) < maxObservedProperties {
+				shape.Properties[name] = observeJSON(shape.Properties[name], property)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			shape.Items = observeJSON(shape.Items, item)
+		}
+	}
+	return shape
+}
+
+// observeQueryValue merges a query parameter value into its shape
+func observeQueryValue(shape *ObservedShape, value string) *ObservedShape {
+	if shape == nil {
+		shape = &ObservedShape{}
+	}
+	shape.Seen++
+
+	kind := "string"
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		kind = "integer"
+	} else if _, err := strconv.ParseFloat(value, 64); err == nil {
+		kind = "number"
+	} else if value == "true" || value == "false" {
+		kind = "boolean"
+	}
+	if shape.Type = mergeObservedType(shape.Type, kind); shape.Type == "mixed" {
+		shape.Type = "string"
+	}
+	return shape
+}
+
+// observedTypeName JSON type of a decoded value, telling integers from other numbers
+func observedTypeName(value interface{}) string {
+	if number, ok := value.(float64); ok && number == math.Trunc(number) && math.Abs(number) < 1<<53 {
+		return "integer"
+	}
+	return jsonTypeName(value)
+}
+
+// mergeObservedType type of a place that held values of both types
+func mergeObservedType(current, observed string) string {
+	switch {
+	case current == "" || current == "null" || current == observed:
+		return observed
+	case (current == "integer" && observed == "number") || (current == "number" && observed == "integer"):
+		return "number"
+	default:
+		return "mixed"
+	}
+}
+
+// LearnReport suggestions for the routes with recorded traffic whose declarations are incomplete
+func LearnReport() []LearnedRoute {
+	registered := make(map[string]RouteEntry)
+	for _, route := range GetRoutes() {
+		registered[route.Method+" "+route.Path] = route
+	}
+
+	observationsMutex.Lock()
+	defer observationsMutex.Unlock()
+
+	var report []LearnedRoute
+	for key, observation := range observations {
+		method, path, _ := strings.Cut(key, " ")
+		learned := suggestDeclarations(registered[key], observation)
+		if len(learned.Annotations) == 0 {
+			continue
+		}
+		learned.Method, learned.Path = method, path
+		report = append(report, learned)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Path != report[j].Path {
+			return report[i].Path < report[j].Path
+		}
+		return report[i].Method < report[j].Method
+	})
+	return report
+}
+
+// suggestDeclarations decorators and schemas the route lacks for its observed traffic
+func suggestDeclarations(route RouteEntry, observation *routeObservation) LearnedRoute {
+	learned := LearnedRoute{Handler: route.FuncName, Samples: observation.samples}
+	declared := make(map[string]bool)
+	for _, param := range route.Parameters {
+		declared[param.Location+":"+param.Name] = true
+	}
+	for _, response := range route.Responses {
+		declared["response:"+response.Code] = true
+	}
+	prefix := strings.ReplaceAll(route.FuncName, ".", "") // UserController.Get -> UserControllerGet
+	if prefix == "" {
+		prefix = capitalize(strings.ToLower(route.Method)) + pathCamel(route.Path)
+	}
+
+	names := make([]string, 0, len(observation.query))
+	for name := range observation.query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if declared["query:"+name] {
+			continue
+		}
+		shape := observation.query[name]
+		learned.Annotations = append(learned.Annotations, fmt.Sprintf(`// @Param(name=%q, type=%q, location="query", required=%t)`,
+			name, shape.Type, shape.Seen >= observation.samples))
+	}
+
+	schemas := newSchemaBuilder()
+	if observation.body != nil && !declared["body:body"] {
+		learned.Annotations = append(learned.Annotations, fmt.Sprintf(`// @Param(name="body", type=%q, location="body", required=true)`,
+			schemas.goType(prefix+"Request", observation.body)))
+	}
+
+	statuses := make([]int, 0, len(observation.responses))
+	for status := range observation.responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		code := strconv.Itoa(status)
+		if declared["response:"+code] {
+			continue
+		}
+		line := fmt.Sprintf("// @Response(code=%d, description=%q", status, http.StatusText(status))
+		// Only success bodies get a type; error bodies are usually shared by the whole API
+		if shape := observation.responses[status]; shape != nil && status < 300 {
+			line += fmt.Sprintf(", type=%q", schemas.goType(prefix+"Response", shape))
+		}
+		learned.Annotations = append(learned.Annotations, line+")")
+	}
+
+	learned.Schemas = schemas.schemas
+	return learned
+}
+
+// schemaBuilder writes the Go structs of observed object shapes
+type schemaBuilder struct {
+	schemas []LearnedSchema
+	names   map[string]bool
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{names: make(map[string]bool)}
+}
+
+// goType Go type of a shape, declaring a struct named name for objects
+func (b *schemaBuilder) goType(name string, shape *ObservedShape) string {
+	switch shape.Type {
+	case "object":
+		return b.declare(name, shape)
+	case "array":
+		if shape.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + b.goType(name, shape.Items)
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	default:
+		return "interface{}"
+	}
+}
+
+// declare writes the struct of an object shape annotated with @Schema and returns its name
+func (b *schemaBuilder) declare(name string, shape *ObservedShape) string {
+	for base, n := name, 2; b.names[name]; n++ {
+		name = base + strconv.Itoa(n)
+	}
+	b.names[name] = true
+	index := len(b.schemas)
+	b.schemas = append(b.schemas, LearnedSchema{Name: name})
+
+	keys := make([]string, 0, len(shape.Properties))
+	for key := range shape.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var source strings.Builder
+	fmt.Fprintf(&source, "// %s was learned from the observed traffic\n// @Schema()\ntype %s struct {\n", name, name)
+	fields := make(map[string]bool)
+	for _, key := range keys {
+		property := shape.Properties[key]
+		field := goFieldName(key)
+		for base, n := field, 2; fields[field]; n++ {
+			field = base + strconv.Itoa(n)
+		}
+		fields[field] = true
+
+		fieldType := b.goType(name+field, property)
+		if property.Nullable && property.Type != "null" && !strings.HasPrefix(fieldType, "[]") && fieldType != "interface{}" {
+			fieldType = "*" + fieldType
+		}
+		tag := key
+		if property.Seen < shape.Seen {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&source, "\t%s %s `json:%q`\n", field, fieldType, tag)
+	}
+	source.WriteString("}\n")
+
+	formatted, err := format.Source([]byte(source.String()))
+	if err != nil {
+		formatted = []byte(source.String())
+	}
+	b.schemas[index].Source = string(formatted)
+	return name
+}
+
+// goFieldName exported Go field name of a JSON key: "user_id" gives UserID
+func goFieldName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name strings.Builder
+	for _, word := range words {
+		if fieldInitialisms[strings.ToLower(word)] {
+			name.WriteString(strings.ToUpper(word))
+		} else {
+			name.WriteString(capitalize(word))
+		}
+	}
+	if name.Len() == 0 || !unicode.IsLetter([]rune(name.String())[0]) {
+		return "Field" + name.String()
+	}
+	return name.String()
+}
+
+// LearnReportHandler serves the suggestions of LearnReport
+func LearnReportHandler(c *gin.Context) {
+	report := LearnReport()
+	c.JSON(http.StatusOK, gin.H{"routes": report, "count": len(report)})
+}
@@ -0,0 +1,159 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveJSON(t *testing.T) {
+	var shape *ObservedShape
+	for _, body := range []string{
+		`{"name": "Ana", "age": 30, "address": {"city": "Porto"}, "tags": ["a"]}`,
+		`{"name": "Rui", "age": 31.5, "address": null, "nickname": "r"}`,
+	} {
+		var value interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &value))
+		shape = observeJSON(shape, value)
+	}
+
+	assert.Equal(t, "object", shape.Type)
+	assert.Equal(t, 2, shape.Seen)
+	assert.Equal(t, "number", shape.Properties["age"].Type, "integers and decimals merge to number")
+	assert.True(t, shape.Properties["address"].Nullable)
+	assert.Equal(t, "string", shape.Properties["tags"].Items.Type)
+	assert.Equal(t, 1, shape.Properties["nickname"].Seen)
+
+	shape = observeJSON(nil, "text")
+	shape = observeJSON(shape, true)
+	assert.Equal(t, "mixed", shape.Type)
+
+	query := observeQueryValue(nil, "10")
+	assert.Equal(t, "integer", query.Type)
+	assert.Equal(t, "string", observeQueryValue(query, "ten").Type)
+}
+
+func TestTrafficLearningMiddleware(t *testing.T) {
+	if prodBuild {
+		t.Skip("traffic learning is disabled in prod builds")
+	}
+
+	registryMutex.Lock()
+	saved := routes
+	routes = []RouteEntry{
+		{Method: "POST", Path: "/users", FuncName: "CreateUser"},
+		{Method: "GET", Path: "/users", FuncName: "ListUsers", Parameters: []ParameterInfo{{Name: "page", Location: "query"}},
+			Responses: []ResponseInfo{{Code: "200", Type: "[]User"}}},
+	}
+	registryMutex.Unlock()
+	observationsMutex.Lock()
+	observations = make(map[string]*routeObservation)
+	observationsMutex.Unlock()
+	t.Cleanup(func() {
+		registryMutex.Lock()
+		routes = saved
+		registryMutex.Unlock()
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TrafficLearningMiddleware())
+	router.POST("/users", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil || body["name"] == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1, "name": body["name"], "user_id": "u1"})
+	})
+	router.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, []gin.H{{"id": 1}})
+	})
+
+	send := func(method, target, body string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send(http.MethodPost, "/users?dry_run=true", `{"name": "Ana", "email": "ana@example.com"}`)
+	send(http.MethodPost, "/users", `{"name": "Rui"}`)
+	send(http.MethodPost, "/users", `{"name": ""}`)
+	send(http.MethodGet, "/users?page=2", "")
+
+	report := LearnReport()
+	require.Len(t, report, 1, "GET /users declares everything it was called with")
+	learned := report[0]
+	assert.Equal(t, "CreateUser", learned.Handler)
+	assert.Equal(t, 3, learned.Samples)
+	assert.Equal(t, []string{
+		`// @Param(name="dry_run", type="boolean", location="query", required=false)`,
+		`// @Param(name="body", type="CreateUserRequest", location="body", required=true)`,
+		`// @Response(code=201, description="Created", type="CreateUserResponse")`,
+		`// @Response(code=400, description="Bad Request")`,
+	}, learned.Annotations)
+
+	require.Len(t, learned.Schemas, 2)
+	assert.Equal(t, "CreateUserRequest", learned.Schemas[0].Name)
+	assert.Equal(t, "// CreateUserRequest was learned from the observed traffic\n// @Schema()\ntype CreateUserRequest struct {\n"+
+		"\tEmail string `json:\"email,omitempty\"`\n"+
+		"\tName  string `json:\"name\"`\n}\n", learned.Schemas[0].Source)
+	assert.Contains(t, learned.Schemas[1].Source, "UserID string `json:\"user_id\"`")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	LearnReportHandler(c)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+}
+
+func TestLearnedAnnotations(t *testing.T) {
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("POST", "/users")
+// @Response(code=400, description="Bad Request")
+func CreateUser(c *gin.Context) {}
+`
+	learned := []LearnedRoute{{
+		Method: "POST", Path: "/users", Handler: "CreateUser",
+		Annotations: []string{
+			`// @Param(name="body", type="CreateUserRequest", location="body", required=true)`,
+			`// @Response(code=400, description="Bad Request")`,
+		},
+		Schemas: []LearnedSchema{{Name: "CreateUserRequest", Source: "// @Schema()\ntype CreateUserRequest struct {\n\tName string `json:\"name\"`\n}\n"}},
+	}}
+
+	annotations, updated, err := LearnedAnnotations("users.go", []byte(source), learned)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	assert.Equal(t, `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("POST", "/users")
+// @Response(code=400, description="Bad Request")
+// @Param(name="body", type="CreateUserRequest", location="body", required=true)
+func CreateUser(c *gin.Context) {}
+
+// @Schema()
+type CreateUserRequest struct {
+	Name string `+"`json:\"name\"`"+`
+}
+`, string(updated))
+
+	diff := AnnotationDiff("users.go", []byte(source), annotations)
+	assert.Contains(t, diff, "@@ -4,4 +4,10 @@")
+
+	// Applied twice, nothing is left to add
+	again, _, err := LearnedAnnotations("users.go", updated, learned)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
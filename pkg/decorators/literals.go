@@ -0,0 +1,73 @@
+package decorators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDurationLiteral parses durations such as "500ms", "30s", "5m", "1h30m" or "7d" (days are 24h)
+func ParseDurationLiteral(value string) (time.Duration, error) {
+	s := strings.TrimSpace(strings.Trim(strings.TrimSpace(value), `"'`))
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	// Days are not understood by time.ParseDuration: "1d12h" is 24h + 12h
+	var days time.Duration
+	if idx := strings.IndexByte(s, 'd'); idx > 0 {
+		count, err := strconv.ParseFloat(s[:idx], 64)
+		if err == nil && count >= 0 {
+			days = time.Duration(count * float64(24*time.Hour))
+			s = s[idx+1:]
+		}
+	}
+
+	var duration time.Duration
+	if s != "" {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s' (e.g. \"500ms\", \"30s\", \"5m\", \"1h30m\", \"7d\")", value)
+		}
+		duration = parsed
+	}
+	if days > 0 && duration < 0 {
+		return 0, fmt.Errorf("invalid duration '%s' (e.g. \"500ms\", \"30s\", \"5m\", \"1h30m\", \"7d\")", value)
+	}
+	return days + duration, nil
+}
+
+// ParseByteSize parses sizes such as "512", "10KB", "5MB", "1GiB" or "1.5k" into bytes.
+// Units are binary: KB and KiB are both 1024 bytes, MB and MiB 1024², and so on.
+func ParseByteSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(strings.Trim(value, `"'`)))
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.multiplier
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size '%s' (e.g. \"512\", \"10KB\", \"5MB\", \"1GiB\")", value)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
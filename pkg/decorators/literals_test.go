@@ -0,0 +1,86 @@
+package decorators
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDurationLiteral(t *testing.T) {
+	cases := map[string]time.Duration{
+		"500ms":   500 * time.Millisecond,
+		"5m":      5 * time.Minute,
+		"1h30m":   90 * time.Minute,
+		`"30s"`:   30 * time.Second,
+		"7d":      7 * 24 * time.Hour,
+		"1d12h":   36 * time.Hour,
+		"0.5d":    12 * time.Hour,
+		" 2m30s ": 150 * time.Second,
+	}
+	for input, expected := range cases {
+		duration, err := ParseDurationLiteral(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, duration, input)
+	}
+
+	for _, input := range []string{"", "soon", "5", "d", "1d-2h", "5 minutes"} {
+		_, err := ParseDurationLiteral(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseByteSize_BinaryUnits(t *testing.T) {
+	cases := map[string]int64{
+		"1GiB":  1 << 30,
+		"10MiB": 10 << 20,
+		"2kib":  2048,
+		"1TB":   1 << 40,
+		"10MB":  10 << 20,
+	}
+	for input, expected := range cases {
+		size, err := ParseByteSize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, size, input)
+	}
+	_, err := ParseByteSize("ten megs")
+	assert.ErrorContains(t, err, `e.g. "512"`)
+}
+
+func TestValidateMarkerLiterals(t *testing.T) {
+	assert.NoError(t, validateArgumentValues("Cache", []string{"ttl=1h30m", "type=memory"}))
+	assert.NoError(t, validateArgumentValues("Proxy", []string{`timeout="2s"`, "retries=3"}))
+	assert.NoError(t, validateArgumentValues("Subscribe", []string{"topic=orders", "group=mailer", "backoff=0s"}))
+
+	err := validateArgumentValues("Cache", []string{"ttl=5 minutes"})
+	assert.EqualError(t, err, `ttl: invalid duration '5 minutes' (e.g. "500ms", "30s", "5m", "1h30m", "7d")`)
+	assert.ErrorContains(t, validateArgumentValues("RateLimit", []string{"limit=10", "window=-1m"}), "window: duration must not be negative")
+	assert.ErrorContains(t, validateArgumentValues("MaxResponseSize", []string{"size=lots"}), "size: invalid size 'lots'")
+
+	duration, _, _ := ParseCacheArgs([]string{"ttl=1d"})
+	assert.Equal(t, 24*time.Hour, duration)
+}
+
+func TestParseDirectory_LiteralErrorPointsToAnnotation(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(`package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Summary("List users")
+// @Cache(ttl=5mins)
+func ListUsers(c *gin.Context) {}
+`), 0o600))
+
+	_, err := ParseDirectory(dir)
+	var multiple *MultipleValidationError
+	require.True(t, errors.As(err, &multiple), "%v", err)
+	require.Len(t, multiple.Errors, 1)
+	assert.Equal(t, 7, multiple.Errors[0].Line)
+	assert.Contains(t, multiple.Errors[0].Message, "@Cache decorator arguments: ttl: invalid duration '5mins'")
+}
@@ -0,0 +1,483 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// loadTick interval at which RunLoadTest dispatches the requests due
+const loadTick = 5 * time.Millisecond
+
+// LoadTarget operation of the spec exercised by RunLoadTest
+type LoadTarget struct {
+	Method     string
+	Template   string // path as declared in the spec, e.g. "/users/{id}"
+	Operation  *OpenAPIOperation
+	PathParams map[string]string // values fixed by a concrete route ("GET /users/42"); the others are generated
+}
+
+// Route "METHOD /template" of the target
+func (t LoadTarget) Route() string {
+	return t.Method + " " + t.Template
+}
+
+// LoadOptions load applied by RunLoadTest
+type LoadOptions struct {
+	BaseURL     string
+	RPS         int               // target rate once ramped up, spread evenly over the targets
+	Duration    time.Duration     // total time, ramp included
+	RampUp      time.Duration     // linear ramp from zero to RPS at the start
+	Concurrency int               // ceiling of in-flight requests, over it requests are dropped (default: RPS)
+	Timeout     time.Duration     // per request (default: 10s)
+	Headers     map[string]string // sent with every request, e.g. Authorization
+	MetricsPath string            // Prometheus endpoint scraped before and after for the server-side latency
+	Seed        int64             // seed of the generated payloads
+}
+
+// LoadLatency latency percentiles of a route
+type LoadLatency struct {
+	P50  time.Duration `json:"p50_ns"`
+	P90  time.Duration `json:"p90_ns"`
+	P95  time.Duration `json:"p95_ns"`
+	P99  time.Duration `json:"p99_ns"`
+	Max  time.Duration `json:"max_ns"`
+	Mean time.Duration `json:"mean_ns"`
+}
+
+// LoadServerStats latency of a route measured by the server (http_request_duration_seconds), estimated
+// from the histogram buckets like histogram_quantile
+type LoadServerStats struct {
+	Requests int64         `json:"requests"`
+	P50      time.Duration `json:"p50_ns"`
+	P95      time.Duration `json:"p95_ns"`
+	P99      time.Duration `json:"p99_ns"`
+}
+
+// LoadRouteReport result of one route
+type LoadRouteReport struct {
+	Route    string           `json:"route"`
+	Requests int              `json:"requests"`
+	Errors   int              `json:"errors"` // transport failures and 5xx
+	Statuses map[int]int      `json:"statuses"`
+	Latency  LoadLatency      `json:"latency"`
+	Server   *LoadServerStats `json:"server,omitempty"`
+
+	latencies []time.Duration
+}
+
+// LoadReport result of a load test
+type LoadReport struct {
+	Duration     time.Duration      `json:"duration_ns"`
+	Requests     int                `json:"requests"`
+	Errors       int                `json:"errors"`
+	Dropped      int                `json:"dropped"` // not sent: the concurrency ceiling was reached
+	RPS          float64            `json:"rps"`     // rate achieved
+	Routes       []*LoadRouteReport `json:"routes"`
+	MetricsError string             `json:"metrics_error,omitempty"` // why the server-side latency is missing
+}
+
+// LoadTargets selects the operations of routes such as "GET /users" or "GET /users/42"; without routes,
+// every GET of the spec, so a default run does not write
+func LoadTargets(spec *OpenAPISpec, routes []string) ([]LoadTarget, error) {
+	if len(routes) == 0 {
+		for template, path := range spec.Paths {
+			if operation := path["get"]; operation != nil {
+				routes = append(routes, "GET "+template)
+			}
+		}
+		if len(routes) == 0 {
+			return nil, fmt.Errorf("the spec has no GET operation; select the routes to load")
+		}
+		sort.Strings(routes)
+	}
+
+	targets := make([]LoadTarget, 0, len(routes))
+	for _, route := range routes {
+		method, path, found := strings.Cut(strings.TrimSpace(route), " ")
+		if !found {
+			return nil, fmt.Errorf("invalid route '%s', expected 'METHOD /path'", route)
+		}
+		match, found := MatchSpecOperation(spec, method, strings.TrimSpace(path))
+		if !found {
+			return nil, fmt.Errorf("%s is not in the API contract", route)
+		}
+
+		// Parameters written as in the template ("{id}") are generated, concrete ones are kept
+		fixed := make(map[string]string)
+		for name, value := range match.PathParams {
+			if value != "{"+name+"}" && value != ":"+name {
+				fixed[name] = value
+			}
+		}
+		targets = append(targets, LoadTarget{Method: match.Method, Template: match.Template, Operation: match.Operation, PathParams: fixed})
+	}
+	return targets, nil
+}
+
+// newRequest builds a request of the target: path, required query and header parameters and the JSON
+// body generated from their schemas
+func (t LoadTarget) newRequest(ctx context.Context, options LoadOptions, generator *SpecPayloadGenerator) (*http.Request, error) {
+	params := make(map[string]OpenAPIParameter, len(t.Operation.Parameters))
+	for _, param := range t.Operation.Parameters {
+		params[param.In+":"+param.Name] = param
+	}
+	parameterValue := func(param OpenAPIParameter) string {
+		if param.Example != nil {
+			return fmt.Sprint(param.Example)
+		}
+		return fmt.Sprint(generator.Value(param.Schema, param.Name))
+	}
+
+	segments := splitSpecPath(t.Template)
+	for i, segment := range segments {
+		name := strings.TrimPrefix(strings.Trim(segment, "{}"), ":")
+		if name == segment {
+			continue
+		}
+		value, fixed := t.PathParams[name]
+		if !fixed {
+			value = parameterValue(params["path:"+name])
+		}
+		// A catch-all value spans several segments, escaped one by one
+		parts := strings.Split(value, "/")
+		if !params["path:"+name].CatchAll {
+			parts = []string{value}
+		}
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+
+	query := url.Values{}
+	header := make(http.Header)
+	for _, param := range t.Operation.Parameters {
+		if !param.Required {
+			continue
+		}
+		switch param.In {
+		case "query":
+			query.Set(param.Name, parameterValue(param))
+		case "header":
+			header.Set(param.Name, parameterValue(param))
+		}
+	}
+	for name, value := range options.Headers {
+		header.Set(name, value)
+	}
+
+	var body io.Reader = http.NoBody
+	if t.Operation.RequestBody != nil {
+		if media, ok := t.Operation.RequestBody.Content["application/json"]; ok && media.Schema != nil {
+			payload, err := json.Marshal(generator.Value(media.Schema, ""))
+			if err != nil {
+				return nil, fmt.Errorf("error generating the body of %s: %v", t.Route(), err)
+			}
+			body = bytes.NewReader(payload)
+			header.Set("Content-Type", "application/json")
+		}
+	}
+
+	target := strings.TrimSuffix(options.BaseURL, "/") + "/" + strings.Join(segments, "/")
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, t.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	return req, nil
+}
+
+// RunLoadTest sends open-loop load to the targets: the rate follows the ramp whatever the latency, the
+// targets take turns and requests over the concurrency ceiling are dropped, not queued. With a
+// MetricsPath the server histograms are scraped around the run to set the server latency beside the
+// client one.
+func RunLoadTest(ctx context.Context, targets []LoadTarget, spec *OpenAPISpec, options LoadOptions) (*LoadReport, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no routes to load")
+	}
+	if options.RPS <= 0 || options.Duration <= 0 {
+		return nil, fmt.Errorf("rps and duration must be positive")
+	}
+	options.RampUp = min(max(options.RampUp, 0), options.Duration)
+	if options.Concurrency <= 0 {
+		options.Concurrency = options.RPS
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = options.Concurrency
+	client := &http.Client{Timeout: options.Timeout, Transport: transport}
+	defer transport.CloseIdleConnections()
+
+	report := &LoadReport{Routes: make([]*LoadRouteReport, len(targets))}
+	for i, target := range targets {
+		report.Routes[i] = &LoadRouteReport{Route: target.Route(), Statuses: make(map[int]int)}
+	}
+
+	var before map[string]*loadHistogram
+	if options.MetricsPath != "" {
+		var err error
+		if before, err = scrapeLoadHistograms(client, options.BaseURL, options.MetricsPath); err != nil {
+			report.MetricsError = err.Error()
+		}
+	}
+
+	generator := NewSpecPayloadGenerator(spec, options.Seed)
+	for _, target := range targets {
+		if _, err := target.newRequest(ctx, options, generator); err != nil {
+			return nil, err
+		}
+	}
+	slots := make(chan struct{}, options.Concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	record := func(route *LoadRouteReport, status int, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		route.Requests++
+		route.Statuses[status]++
+		if status == 0 || status >= http.StatusInternalServerError {
+			route.Errors++
+		}
+		route.latencies = append(route.latencies, latency)
+	}
+
+	ticker := time.NewTicker(loadTick)
+	defer ticker.Stop()
+	start := time.Now()
+	sent := 0
+dispatch:
+	for {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			if elapsed >= options.Duration {
+				break dispatch
+			}
+			for due := int(loadScheduled(elapsed, options.RPS, options.RampUp)); sent < due; sent++ {
+				route := report.Routes[sent%len(targets)]
+				req, err := targets[sent%len(targets)].newRequest(ctx, options, generator)
+				if err != nil {
+					record(route, 0, 0)
+					continue
+				}
+				select {
+				case slots <- struct{}{}:
+				default:
+					report.Dropped++
+					continue
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-slots }()
+					began := time.Now()
+					status := 0
+					if resp, err := client.Do(req); err == nil {
+						_, _ = io.Copy(io.Discard, resp.Body)
+						resp.Body.Close()
+						status = resp.StatusCode
+					}
+					record(route, status, time.Since(began))
+				}()
+			}
+		}
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+
+	for _, route := range report.Routes {
+		route.Latency = loadLatency(route.latencies)
+		report.Requests += route.Requests
+		report.Errors += route.Errors
+	}
+	report.RPS = float64(report.Requests) / report.Duration.Seconds()
+
+	if before != nil {
+		after, err := scrapeLoadHistograms(client, options.BaseURL, options.MetricsPath)
+		if err != nil {
+			report.MetricsError = err.Error()
+		} else {
+			for i, target := range targets {
+				report.Routes[i].Server = after.delta(before, target).stats()
+			}
+		}
+	}
+	return report, nil
+}
+
+// loadScheduled requests due after elapsed: the rate climbs linearly to rps during the ramp, then holds
+func loadScheduled(elapsed time.Duration, rps int, ramp time.Duration) float64 {
+	t, r, rate := elapsed.Seconds(), ramp.Seconds(), float64(rps)
+	if t <= r {
+		return rate * t * t / (2 * r)
+	}
+	return rate*r/2 + rate*(t-r)
+}
+
+// loadLatency percentiles (nearest rank) of the latencies
+func loadLatency(latencies []time.Duration) LoadLatency {
+	if len(latencies) == 0 {
+		return LoadLatency{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p float64) time.Duration {
+		return latencies[max(int(math.Ceil(p*float64(len(latencies))))-1, 0)]
+	}
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	return LoadLatency{
+		P50:  percentile(0.50),
+		P90:  percentile(0.90),
+		P95:  percentile(0.95),
+		P99:  percentile(0.99),
+		Max:  latencies[len(latencies)-1],
+		Mean: total / time.Duration(len(latencies)),
+	}
+}
+
+// loadHistogram request-duration histogram of a route, all statuses summed
+type loadHistogram struct {
+	count   float64
+	buckets map[float64]float64 // upper bound (s) -> cumulative count
+}
+
+// loadHistograms histograms by "METHOD /path", with the path in gin syntax as in the endpoint label
+type loadHistograms map[string]*loadHistogram
+
+// scrapeLoadHistograms reads the http_request_duration_seconds histograms of the server
+func scrapeLoadHistograms(client *http.Client, baseURL, metricsPath string) (loadHistograms, error) {
+	resp, err := client.Get(strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(metricsPath, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("could not scrape %s: %v", metricsPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not scrape %s: %s", metricsPath, resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics on %s: %v", metricsPath, err)
+	}
+
+	histograms := make(loadHistograms)
+	for name, family := range families {
+		if !strings.HasSuffix(name, "http_request_duration_seconds") || family.GetType() != dto.MetricType_HISTOGRAM {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var method, endpoint string
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "method":
+					method = label.GetValue()
+				case "endpoint":
+					endpoint = label.GetValue()
+				}
+			}
+			key := method + " " + endpoint
+			histogram := histograms[key]
+			if histogram == nil {
+				histogram = &loadHistogram{buckets: make(map[float64]float64)}
+				histograms[key] = histogram
+			}
+			histogram.count += float64(metric.GetHistogram().GetSampleCount())
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				histogram.buckets[bucket.GetUpperBound()] += float64(bucket.GetCumulativeCount())
+			}
+		}
+	}
+	if len(histograms) == 0 {
+		return nil, fmt.Errorf("no http_request_duration_seconds histogram on %s (metrics disabled?)", metricsPath)
+	}
+	return histograms, nil
+}
+
+// delta histogram of the target during the run: the scrape after minus the one before
+func (h loadHistograms) delta(before loadHistograms, target LoadTarget) *loadHistogram {
+	segments := ginTemplateSegments(target.Template, target.Operation)
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + strings.Trim(segment, "{}")
+		}
+	}
+	key := target.Method + " /" + strings.Join(segments, "/")
+
+	after := h[key]
+	if after == nil {
+		return nil
+	}
+	delta := &loadHistogram{count: after.count, buckets: make(map[float64]float64, len(after.buckets))}
+	for bound, count := range after.buckets {
+		delta.buckets[bound] = count
+	}
+	if previous := before[key]; previous != nil {
+		delta.count -= previous.count
+		for bound, count := range previous.buckets {
+			delta.buckets[bound] -= count
+		}
+	}
+	return delta
+}
+
+// stats server-side statistics of the histogram, nil without requests
+func (h *loadHistogram) stats() *LoadServerStats {
+	if h == nil || h.count <= 0 {
+		return nil
+	}
+	return &LoadServerStats{
+		Requests: int64(h.count),
+		P50:      h.quantile(0.50),
+		P95:      h.quantile(0.95),
+		P99:      h.quantile(0.99),
+	}
+}
+
+// quantile estimates a quantile by linear interpolation inside its bucket; in the +Inf bucket it is the
+// highest finite bound
+func (h *loadHistogram) quantile(q float64) time.Duration {
+	bounds := make([]float64, 0, len(h.buckets))
+	for bound := range h.buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+
+	rank := q * h.count
+	lower, below := 0.0, 0.0
+	for _, bound := range bounds {
+		count := h.buckets[bound]
+		if count >= rank {
+			if math.IsInf(bound, 1) || count == below {
+				return time.Duration(lower * float64(time.Second))
+			}
+			return time.Duration((lower + (bound-lower)*(rank-below)/(count-below)) * float64(time.Second))
+		}
+		lower, below = bound, count
+	}
+	return time.Duration(lower * float64(time.Second))
+}
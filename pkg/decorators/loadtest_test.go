@@ -0,0 +1,183 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTestSpec users API of the load tests
+func loadTestSpec() *OpenAPISpec {
+	return &OpenAPISpec{
+		Paths: map[string]OpenAPIPath{
+			"/users": {
+				"get": &OpenAPIOperation{Parameters: []OpenAPIParameter{
+					{Name: "page", In: "query", Required: true, Schema: &OpenAPISchema{Type: "integer", Minimum: 1, Maximum: 3}},
+				}},
+				"post": &OpenAPIOperation{RequestBody: &OpenAPIRequestBody{Required: true, Content: map[string]MediaType{
+					"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/User"}},
+				}}},
+			},
+			"/users/{id}": {
+				"get": &OpenAPIOperation{Parameters: []OpenAPIParameter{
+					{Name: "id", In: "path", Required: true, Schema: &OpenAPISchema{Type: "integer"}},
+				}},
+			},
+		},
+		Components: &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+			"User": {Type: "object", Properties: map[string]*OpenAPISchema{"email": {Type: "string", Format: "email"}}},
+		}},
+	}
+}
+
+func TestLoadTargets(t *testing.T) {
+	spec := loadTestSpec()
+	targets, err := LoadTargets(spec, nil)
+	require.NoError(t, err)
+	require.Len(t, targets, 2, "every GET by default")
+	assert.Equal(t, "GET /users", targets[0].Route())
+	assert.Equal(t, "GET /users/{id}", targets[1].Route())
+	assert.Empty(t, targets[1].PathParams)
+
+	targets, err = LoadTargets(spec, []string{"post /users", "GET /users/42"})
+	require.NoError(t, err)
+	assert.Equal(t, "POST /users", targets[0].Route())
+	assert.Equal(t, map[string]string{"id": "42"}, targets[1].PathParams)
+
+	_, err = LoadTargets(spec, []string{"DELETE /users/1"})
+	assert.ErrorContains(t, err, "not in the API contract")
+	_, err = LoadTargets(spec, []string{"/users"})
+	assert.Error(t, err)
+}
+
+func TestRunLoadTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	var invalid atomic.Int32
+	engine.GET("/users", func(c *gin.Context) {
+		if c.Query("page") == "" {
+			invalid.Add(1)
+		}
+		c.Status(http.StatusOK)
+	})
+	engine.GET("/users/:id", func(c *gin.Context) {
+		if c.Param("id") == "{id}" {
+			invalid.Add(1)
+		}
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/users", func(c *gin.Context) {
+		var user struct {
+			Email string `json:"email" binding:"required,email"`
+		}
+		if err := c.ShouldBindJSON(&user); err != nil {
+			invalid.Add(1)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	// Histogram of the server: every scrape reports 10 more requests of GET /users/:id, 0.1s to 0.25s
+	var scrapes atomic.Int32
+	engine.GET("/metrics", func(c *gin.Context) {
+		count := 10 * scrapes.Add(1)
+		c.String(http.StatusOK, "# TYPE app_api_http_request_duration_seconds histogram\n"+
+			"app_api_http_request_duration_seconds_bucket{endpoint=\"/users/:id\",method=\"GET\",status=\"200\",le=\"0.1\"} 0\n"+
+			"app_api_http_request_duration_seconds_bucket{endpoint=\"/users/:id\",method=\"GET\",status=\"200\",le=\"0.25\"} %d\n"+
+			"app_api_http_request_duration_seconds_bucket{endpoint=\"/users/:id\",method=\"GET\",status=\"200\",le=\"+Inf\"} %d\n"+
+			"app_api_http_request_duration_seconds_sum{endpoint=\"/users/:id\",method=\"GET\",status=\"200\"} 1\n"+
+			"app_api_http_request_duration_seconds_count{endpoint=\"/users/:id\",method=\"GET\",status=\"200\"} %d\n",
+			count, count, count)
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	spec := loadTestSpec()
+	targets, err := LoadTargets(spec, []string{"GET /users", "GET /users/{id}", "POST /users"})
+	require.NoError(t, err)
+	report, err := RunLoadTest(context.Background(), targets, spec, LoadOptions{
+		BaseURL:     server.URL,
+		RPS:         300,
+		Duration:    300 * time.Millisecond,
+		RampUp:      100 * time.Millisecond,
+		MetricsPath: "/metrics",
+	})
+	require.NoError(t, err)
+
+	assert.Zero(t, invalid.Load(), "the generated requests satisfy the contract")
+	assert.Empty(t, report.MetricsError)
+	assert.Zero(t, report.Errors)
+	assert.Greater(t, report.Requests, 20)
+	require.Len(t, report.Routes, 3)
+	for _, route := range report.Routes {
+		assert.Greater(t, route.Requests, 0, route.Route)
+		assert.Positive(t, route.Latency.P50, route.Route)
+		assert.LessOrEqual(t, route.Latency.P50, route.Latency.P99, route.Route)
+	}
+	assert.Equal(t, loadRoute(report, "POST /users").Requests, loadRoute(report, "POST /users").Statuses[http.StatusCreated])
+
+	serverSide := loadRoute(report, "GET /users/{id}").Server
+	require.NotNil(t, serverSide)
+	assert.Equal(t, int64(10), serverSide.Requests)
+	assert.Equal(t, 175*time.Millisecond, serverSide.P50)
+	assert.Nil(t, loadRoute(report, "GET /users").Server, "routes without samples have no server latency")
+}
+
+// loadRoute report of a route
+func loadRoute(report *LoadReport, name string) *LoadRouteReport {
+	for _, route := range report.Routes {
+		if route.Route == name {
+			return route
+		}
+	}
+	panic(fmt.Sprintf("no route %s", name))
+}
+
+func TestRunLoadTest_NoMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spec := loadTestSpec()
+	targets, err := LoadTargets(spec, []string{"GET /users/7"})
+	require.NoError(t, err)
+	report, err := RunLoadTest(context.Background(), targets, spec, LoadOptions{
+		BaseURL: server.URL, RPS: 100, Duration: 100 * time.Millisecond, MetricsPath: "/metrics",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, report.MetricsError, "404")
+	assert.Greater(t, report.Requests, 0)
+
+	_, err = RunLoadTest(context.Background(), targets, spec, LoadOptions{BaseURL: server.URL})
+	assert.Error(t, err)
+}
+
+func TestLoadScheduled(t *testing.T) {
+	assert.InDelta(t, 12.5, loadScheduled(500*time.Millisecond, 100, time.Second), 0.001, "half of the ramp")
+	assert.InDelta(t, 50, loadScheduled(time.Second, 100, time.Second), 0.001)
+	assert.InDelta(t, 150, loadScheduled(2*time.Second, 100, time.Second), 0.001)
+	assert.InDelta(t, 200, loadScheduled(2*time.Second, 100, 0), 0.001, "no ramp")
+}
+
+func TestLoadLatency(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+	latency := loadLatency(latencies)
+	assert.Equal(t, 50*time.Millisecond, latency.P50)
+	assert.Equal(t, 99*time.Millisecond, latency.P99)
+	assert.Equal(t, 100*time.Millisecond, latency.Max)
+	assert.Equal(t, LoadLatency{}, loadLatency(nil))
+}
@@ -0,0 +1,154 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Marker arguments are comma-separated; a value may be a quoted string (commas, parentheses and
+// Go escapes allowed), an array ("roles=[admin, ops]") or an inline JSON object
+// ("limits={"rps": 10, "burst": 20}"). Quotes and brackets only open at the start of a value,
+// so apostrophes in free text ("@Summary(User's profile)") are plain characters.
+
+// splitMarkerArguments splits marker arguments on top-level commas, keeping each argument as written
+func splitMarkerArguments(text string) ([]string, error) {
+	var args []string
+	var quote byte
+	var nesting []byte
+	start, valueStart := 0, true
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		case len(nesting) > 0 && (ch == '"' || ch == '\''):
+			// Strings inside arrays and objects always quote
+			quote = ch
+			continue
+		case valueStart && (ch == '"' || ch == '\''):
+			quote = ch
+		case (valueStart || len(nesting) > 0) && (ch == '[' || ch == '{'):
+			nesting = append(nesting, ch)
+		case len(nesting) > 0 && (ch == ']' || ch == '}'):
+			if open := nesting[len(nesting)-1]; (open == '[') != (ch == ']') {
+				return nil, fmt.Errorf("unbalanced '%c' at position %d", ch, i+1)
+			}
+			nesting = nesting[:len(nesting)-1]
+		case len(nesting) == 0 && ch == ',':
+			args = appendMarkerArgument(args, text[start:i])
+			start, valueStart = i+1, true
+			continue
+		}
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+		case ch == '=' && len(nesting) == 0:
+			valueStart = true
+		default:
+			valueStart = false
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if len(nesting) > 0 {
+		return nil, fmt.Errorf("unclosed '%c'", nesting[len(nesting)-1])
+	}
+	return appendMarkerArgument(args, text[start:]), nil
+}
+
+// appendMarkerArgument appends a trimmed argument, skipping empty ones. A fully quoted argument
+// loses its quotes unless they protect a comma, which must survive the generated "a,b" argument string.
+func appendMarkerArgument(args []string, arg string) []string {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return args
+	}
+	if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] && !strings.Contains(arg, ",") {
+		arg = MarkerValue(arg)
+	}
+	return append(args, arg)
+}
+
+// markerArgumentsEnd returns the index of the parenthesis closing the arguments that start at
+// start, skipping quoted strings and nested parentheses, or -1 when it is not found
+func markerArgumentsEnd(text string, start int) int {
+	var quote byte
+	depth, valueStart := 0, true
+	for i := start; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		case valueStart && (ch == '"' || ch == '\''):
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ch == '\n':
+			// Arguments never span lines outside quoted strings
+			return -1
+		}
+		valueStart = ch == '=' || ch == ',' || ch == ' ' || ch == '\t' || ch == '[' || ch == '{' || ch == ':' ||
+			(valueStart && ch == '(')
+	}
+	return -1
+}
+
+// MarkerValue returns a marker argument value without its quotes, resolving Go escapes in
+// double-quoted values ("^2\\." is the pattern ^2\.)
+func MarkerValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	return strings.Trim(value, `"'`)
+}
+
+// MarkerList returns the items of an array value ("[admin, ops]"); other values are a single item
+func MarkerList(value string) []string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		if value = MarkerValue(value); value == "" {
+			return nil
+		}
+		return []string{value}
+	}
+
+	items, err := splitMarkerArguments(value[1 : len(value)-1])
+	if err != nil {
+		return nil
+	}
+	for i, item := range items {
+		items[i] = MarkerValue(item)
+	}
+	return items
+}
+
+// MarkerJSON decodes an inline JSON value ("{"rps": 10}" or a JSON array) into target
+func MarkerJSON(value string, target interface{}) error {
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), target); err != nil {
+		return fmt.Errorf("invalid JSON value %s: %v", value, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package decorators
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMarkerArguments(t *testing.T) {
+	cases := map[string][]string{
+		`code=200, description="a, b"`: {"code=200", `description="a, b"`},
+		`"GET", "/users"`:              {"GET", "/users"},
+		`"a, b"`:                       {`"a, b"`},
+		`description="say \"hi\", bye", type=User`:     {`description="say \"hi\", bye"`, "type=User"},
+		`roles=[admin, ops], key=x`:                    {"roles=[admin, ops]", "key=x"},
+		`limits={"rps": 10, "paths": ["/a", "/b"]}, x`: {`limits={"rps": 10, "paths": ["/a", "/b"]}`, "x"},
+		`User's profile`:                               {"User's profile"},
+		`pattern='^v[0-9]+,?$'`:                        {`pattern='^v[0-9]+,?$'`},
+		``:                                             nil,
+	}
+	for input, expected := range cases {
+		args, err := splitMarkerArguments(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, args, input)
+	}
+
+	for _, input := range []string{`description="open`, `roles=[admin, ops`, `limits={"a": [1}`} {
+		_, err := splitMarkerArguments(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestMarkerValueHelpers(t *testing.T) {
+	assert.Equal(t, `say "hi", bye`, MarkerValue(`"say \"hi\", bye"`))
+	assert.Equal(t, "plain", MarkerValue(" 'plain' "))
+	assert.Equal(t, []string{"admin", "ops, eu"}, MarkerList(`[admin, "ops, eu"]`))
+	assert.Equal(t, []string{"admin"}, MarkerList("admin"))
+	assert.Empty(t, MarkerList(""))
+
+	var limits struct {
+		RPS   int      `json:"rps"`
+		Paths []string `json:"paths"`
+	}
+	require.NoError(t, MarkerJSON(`{"rps": 10, "paths": ["/a"]}`, &limits))
+	assert.Equal(t, 10, limits.RPS)
+	assert.Error(t, MarkerJSON(`{rps: 10}`, &limits))
+
+	// Generated argument strings keep quoted commas together at runtime
+	assert.Equal(t, []string{`description="a, b"`, "roles=[x,y]"}, parseArguments(`description="a, b",roles=[x,y]`))
+}
+
+func TestExtractMarkers_QuotedArguments(t *testing.T) {
+	src := `package handlers
+
+// @Route("GET", "/users/:id")
+// @Summary("Get a user (by id), with orders")
+// @Response(code=200, description="The user, with (nested) orders", type=User)
+// @Description(User's profile)
+func GetUser() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	funcDecl := file.Decls[0].(*ast.FuncDecl)
+
+	found, validationErr := extractMarkersWithValidation(fset, "handlers.go", funcDecl, funcDecl.Doc.Text())
+	require.Nil(t, validationErr)
+	route := &RouteMeta{Method: "GET", Path: "/users/:id", FuncName: "GetUser", Markers: found}
+	require.NoError(t, processMiddlewares(route))
+
+	assert.Equal(t, "Get a user (by id), with orders", route.Summary)
+	assert.Equal(t, "User's profile", route.Description)
+	require.Len(t, route.Responses, 1)
+	assert.Equal(t, "The user, with (nested) orders", route.Responses[0].Description)
+	assert.Equal(t, "User", route.Responses[0].Type)
+}
+
+func TestAuthMarker_RolesArray(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var roles interface{}
+	router.GET("/admin", CreateAuthMiddleware(`roles=[admin, ops]`), func(c *gin.Context) {
+		roles, _ = c.Get("user_roles")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", http.NoBody)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"admin", "ops"}, roles)
+}
@@ -0,0 +1,364 @@
+package decorators
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Marker plugin packages publish decorators outside the core: their init registers a
+// MarkerConfig with RegisterMarker, so blank-importing the package makes the factory available
+// at runtime. The deco binary cannot run that init, so it reads the registrations from the
+// package source instead and generates deco.CreateMarkerMiddleware calls plus the blank import.
+
+var (
+	// loadedMarkerPlugins packages already scanned, so dev-mode regenerations skip go list
+	loadedMarkerPlugins = make(map[string]bool)
+	markerPluginsMutex  sync.Mutex
+)
+
+// LoadMarkerPlugins registers the markers declared by plugin packages (import paths resolved
+// with go list from rootDir) so the parser accepts them
+func LoadMarkerPlugins(rootDir string, packages []string) error {
+	markerPluginsMutex.Lock()
+	defer markerPluginsMutex.Unlock()
+
+	var pending []string
+	for _, pkg := range packages {
+		if !loadedMarkerPlugins[pkg] {
+			pending = append(pending, pkg)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	dirs, err := resolvePackageDirs(rootDir, pending)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pending {
+		if dirs[pkg] == "" {
+			return fmt.Errorf("marker plugin %s: package not found (is it required in go.mod?)", pkg)
+		}
+		loadedMarkerPlugins[pkg] = true
+		configs, err := scanMarkerPlugin(dirs[pkg], pkg)
+		if err != nil {
+			return fmt.Errorf("marker plugin %s: %v", pkg, err)
+		}
+		for _, config := range configs {
+			if existing, ok := GetMarker(config.Name); ok && existing.Package != config.Package {
+				return fmt.Errorf("marker plugin %s: @%s is already registered", pkg, config.Name)
+			}
+			RegisterMarker(config)
+		}
+	}
+	return nil
+}
+
+// resolvePackageDirs maps import paths to their source directories
+func resolvePackageDirs(rootDir string, packages []string) (map[string]string, error) {
+	cmd := exec.Command("go", append([]string{"list", "-e", "-f", "{{.ImportPath}}={{.Dir}}"}, packages...)...)
+	cmd.Dir = rootDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error resolving marker plugins: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	dirs := make(map[string]string, len(packages))
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if importPath, dir, found := strings.Cut(line, "="); found {
+			dirs[importPath] = dir
+		}
+	}
+	return dirs, nil
+}
+
+// scanMarkerPlugin reads the MarkerConfig literals of a package: Name, Description and a
+// regexp.MustCompile Pattern must be constants; a missing pattern uses the @Name(args) form
+func scanMarkerPlugin(dir, importPath string) ([]MarkerConfig, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []MarkerConfig
+	fset := token.NewFileSet()
+	for _, fileName := range files {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, fileName, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var scanErr error
+		ast.Inspect(file, func(node ast.Node) bool {
+			literal, ok := node.(*ast.CompositeLit)
+			if !ok || !isMarkerConfigType(literal.Type) || scanErr != nil {
+				return scanErr == nil
+			}
+			config, err := markerConfigFromLiteral(literal)
+			if err != nil {
+				scanErr = fmt.Errorf("%s: %v", fset.Position(literal.Pos()), err)
+				return false
+			}
+			config.Package = importPath
+			configs = append(configs, config)
+			return false
+		})
+		if scanErr != nil {
+			return nil, scanErr
+		}
+	}
+	return configs, nil
+}
+
+// isMarkerConfigType reports whether a composite literal type is MarkerConfig (qualified or not)
+func isMarkerConfigType(expr ast.Expr) bool {
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		return typed.Name == "MarkerConfig"
+	case *ast.SelectorExpr:
+		return typed.Sel.Name == "MarkerConfig"
+	}
+	return false
+}
+
+// markerConfigFromLiteral extracts the constant fields of a MarkerConfig literal
+func markerConfigFromLiteral(literal *ast.CompositeLit) (MarkerConfig, error) {
+	var config MarkerConfig
+	for _, element := range literal.Elts {
+		field, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := field.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Name", "Description":
+			value, ok := stringLiteral(field.Value)
+			if !ok {
+				return config, fmt.Errorf("MarkerConfig.%s must be a string constant", key.Name)
+			}
+			if key.Name == "Name" {
+				config.Name = value
+			} else {
+				config.Description = value
+			}
+		case "Pattern":
+			call, ok := field.Value.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return config, fmt.Errorf("MarkerConfig.Pattern must be regexp.MustCompile(<constant>)")
+			}
+			expr, ok := stringLiteral(call.Args[0])
+			if !ok {
+				return config, fmt.Errorf("MarkerConfig.Pattern must be regexp.MustCompile(<constant>)")
+			}
+			pattern, err := regexp.Compile(expr)
+			if err != nil {
+				return config, fmt.Errorf("invalid MarkerConfig.Pattern: %v", err)
+			}
+			config.Pattern = pattern
+		case "Args":
+			args, err := markerArgsFromLiteral(field.Value)
+			if err != nil {
+				return config, err
+			}
+			config.Args = args
+		}
+	}
+
+	if config.Name == "" {
+		return config, fmt.Errorf("MarkerConfig.Name is required")
+	}
+	if config.Pattern == nil {
+		config.Pattern = regexp.MustCompile(`@` + regexp.QuoteMeta(config.Name) + `\s*\(([^)]*)\)`)
+	}
+	return config, nil
+}
+
+// markerArgsFromLiteral extracts a []MarkerArg literal whose fields are constants; types may be
+// written as strings or as the decorators.MarkerArg* constants
+func markerArgsFromLiteral(expr ast.Expr) ([]MarkerArg, error) {
+	list, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("MarkerConfig.Args must be a []MarkerArg literal")
+	}
+
+	constants := map[string]string{
+		"MarkerArgString": MarkerArgString, "MarkerArgInt": MarkerArgInt, "MarkerArgNumber": MarkerArgNumber,
+		"MarkerArgBool": MarkerArgBool, "MarkerArgDuration": MarkerArgDuration, "MarkerArgSize": MarkerArgSize,
+		"MarkerArgList": MarkerArgList, "MarkerArgJSON": MarkerArgJSON,
+	}
+
+	args := make([]MarkerArg, 0, len(list.Elts))
+	for _, element := range list.Elts {
+		literal, ok := element.(*ast.CompositeLit)
+		if !ok {
+			return nil, fmt.Errorf("MarkerConfig.Args must be a []MarkerArg literal")
+		}
+		var arg MarkerArg
+		for _, element := range literal.Elts {
+			field, ok := element.(*ast.KeyValueExpr)
+			if !ok {
+				return nil, fmt.Errorf("MarkerArg fields must be keyed")
+			}
+			key, _ := field.Key.(*ast.Ident)
+			if key == nil {
+				continue
+			}
+
+			switch key.Name {
+			case "Name":
+				if arg.Name, ok = stringLiteral(field.Value); !ok {
+					return nil, fmt.Errorf("MarkerArg.Name must be a string constant")
+				}
+			case "Type":
+				if arg.Type, ok = stringLiteral(field.Value); !ok {
+					if selector, isSelector := field.Value.(*ast.SelectorExpr); isSelector {
+						arg.Type, ok = constants[selector.Sel.Name]
+					} else if ident, isIdent := field.Value.(*ast.Ident); isIdent {
+						arg.Type, ok = constants[ident.Name]
+					}
+				}
+				if !ok {
+					return nil, fmt.Errorf("MarkerArg.Type must be a string or MarkerArg* constant")
+				}
+			case "Required":
+				ident, isIdent := field.Value.(*ast.Ident)
+				if !isIdent || (ident.Name != "true" && ident.Name != "false") {
+					return nil, fmt.Errorf("MarkerArg.Required must be true or false")
+				}
+				arg.Required = ident.Name == "true"
+			case "Enum":
+				values, isList := field.Value.(*ast.CompositeLit)
+				if !isList {
+					return nil, fmt.Errorf("MarkerArg.Enum must be a []string literal")
+				}
+				for _, item := range values.Elts {
+					value, isString := stringLiteral(item)
+					if !isString {
+						return nil, fmt.Errorf("MarkerArg.Enum must be a []string literal")
+					}
+					arg.Enum = append(arg.Enum, value)
+				}
+			}
+		}
+		if arg.Name == "" {
+			return nil, fmt.Errorf("MarkerArg.Name is required")
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// stringLiteral returns the value of a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+// blankImportedPackages lists the non-standard packages blank-imported by Go files
+func blankImportedPackages(files []string) []string {
+	seen := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, fileName := range files {
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(fset, fileName, content, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			// Standard library paths have no dot in their first element; deco itself declares no plugins
+			if spec.Name != nil && spec.Name.Name == "_" && strings.Contains(strings.Split(importPath, "/")[0], ".") &&
+				!strings.HasPrefix(importPath, decoModulePath) {
+				seen[importPath] = true
+			}
+		}
+	}
+
+	packages := make([]string, 0, len(seen))
+	for pkg := range seen {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// markerPluginImports blank imports of the plugin packages whose markers the routes use
+func markerPluginImports(routes []*RouteMeta) []string {
+	registered := GetMarkers()
+	seen := make(map[string]bool)
+	var imports []string
+	for _, route := range routes {
+		for _, marker := range route.Markers {
+			pkg := registered[marker.Name].Package
+			if pkg != "" && !seen[pkg] {
+				seen[pkg] = true
+				imports = append(imports, fmt.Sprintf("_ %q", pkg))
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// processPluginMarker generates the middleware of markers declared by a plugin package.
+// Markers registered in-process (a deco binary built with the plugin) opt in by setting Package.
+func processPluginMarker(marker MarkerInstance, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo) {
+	config, ok := GetMarker(marker.Name)
+	if !ok || config.Package == "" {
+		return
+	}
+
+	*middlewareCalls = append(*middlewareCalls, fmt.Sprintf(`deco.CreateMarkerMiddleware(%q, %q)`, marker.Name, strings.Join(marker.Args, ",")))
+	*middlewareInfo = append(*middlewareInfo, MiddlewareInfo{
+		Name:        marker.Name,
+		Args:        parseArgsToMap(marker.Args),
+		Description: config.Description,
+	})
+}
+
+// CreateMarkerMiddleware creates the middleware of a marker registered by a plugin package (wrapper for generation).
+// A marker whose package was not imported fails closed, rejecting requests instead of skipping the decorator.
+func CreateMarkerMiddleware(name, args string) gin.HandlerFunc {
+	config, ok := GetMarker(name)
+	if !ok || config.Factory == nil {
+		LogSilent("⚠️  Marker @%s is not registered at runtime (is its plugin package imported?)", name)
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "marker_unavailable",
+				"message": fmt.Sprintf("middleware @%s is not available", name),
+			})
+		}
+	}
+	return config.Factory(parseArguments(args))
+}
@@ -0,0 +1,115 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestMarkerPlugins_DiscoveredFromBlankImports(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(root, "plugins", "stripe", "stripe.go"), `package stripe
+
+import (
+	"regexp"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+func init() {
+	decorators.RegisterMarker(decorators.MarkerConfig{
+		Name:        "Stripe",
+		Pattern:     regexp.MustCompile(`+"`@Stripe\\s*\\(([^)]*)\\)`"+`),
+		Factory:     verifySignature,
+		Description: "Verifies Stripe webhook signatures",
+	})
+	decorators.RegisterMarker(decorators.MarkerConfig{Name: "Recaptcha", Factory: verifyRecaptcha})
+}
+`)
+	writeTestFile(t, filepath.Join(root, "handlers", "webhooks.go"), `package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	_ "example.com/app/plugins/stripe"
+)
+
+// @Route("POST", "/webhooks/stripe")
+// @Stripe(secret=whsec)
+// @Recaptcha()
+func StripeWebhook(c *gin.Context) {}
+`)
+	defer func() {
+		delete(markers, "Stripe")
+		delete(markers, "Recaptcha")
+		delete(loadedMarkerPlugins, "example.com/app/plugins/stripe")
+	}()
+
+	routes, err := ParseDirectory(filepath.Join(root, "handlers"))
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.ElementsMatch(t, []string{
+		`deco.CreateMarkerMiddleware("Stripe", "secret=whsec")`,
+		`deco.CreateMarkerMiddleware("Recaptcha", "")`,
+	}, routes[0].MiddlewareCalls)
+	descriptions := make(map[string]string)
+	for _, info := range routes[0].MiddlewareInfo {
+		descriptions[info.Name] = info.Description
+	}
+	assert.Equal(t, "Verifies Stripe webhook signatures", descriptions["Stripe"])
+	assert.Equal(t, []string{`_ "example.com/app/plugins/stripe"`}, markerPluginImports(routes))
+
+	err = LoadMarkerPlugins(root, []string{"example.com/app/plugins/missing"})
+	assert.ErrorContains(t, err, "package not found")
+}
+
+func TestMarkerConfigFromLiteral_Errors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "bad.go"), "package bad\n\nvar name = \"X\"\nvar _ = MarkerConfig{Name: name}\n")
+	_, err := scanMarkerPlugin(root, "example.com/bad")
+	assert.ErrorContains(t, err, "MarkerConfig.Name must be a string constant")
+
+	writeTestFile(t, filepath.Join(root, "bad.go"), "package bad\n\nvar _ = MarkerConfig{Name: \"Auth\"}\n")
+	configs, err := scanMarkerPlugin(root, "example.com/bad")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.True(t, configs[0].Pattern.MatchString(`@Auth(role=admin)`))
+}
+
+func TestCreateMarkerMiddleware(t *testing.T) {
+	RegisterMarker(MarkerConfig{Name: "TestPluginTenant", Package: "example.com/tenant", Factory: func(args []string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Header("X-Tenant", args[0])
+			c.Next()
+		}
+	}})
+	defer delete(markers, "TestPluginTenant")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ok", CreateMarkerMiddleware("TestPluginTenant", "acme"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/missing", CreateMarkerMiddleware("TestPluginMissing", ""), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "acme", w.Header().Get("X-Tenant"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", http.NoBody))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "marker_unavailable")
+}
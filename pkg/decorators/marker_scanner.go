@@ -0,0 +1,155 @@
+package decorators
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// markerScanner finds every registered marker of a comment in one pass: only the '@' positions followed
+// by the literal prefix of a marker pattern run that pattern, anchored there. Patterns without an
+// "@Name" literal prefix fall back to a search over the whole comment.
+type markerScanner struct {
+	version   int
+	size      int
+	byPrefix  map[string][]scannerMarker // by identifier of the literal prefix, "Cache" for `@Cache\s*\(`
+	maxPrefix int                        // longest identifier of byPrefix
+	fallback  []scannerMarker
+}
+
+// scannerMarker pattern of a marker, as compiled by the scanner
+type scannerMarker struct {
+	name    string
+	prefix  string         // literal prefix, "@Cache"
+	pattern *regexp.Regexp // anchored at the start of the text, except for fallback markers
+	simple  bool           // pattern is the usual `@Name\s*\(([^)]*)\)`, matched without the regexp
+}
+
+// markerMatch marker found in a comment, loc as returned by FindStringSubmatchIndex
+type markerMatch struct {
+	name string
+	loc  []int
+}
+
+var (
+	markersVersion int        // bumped by RegisterMarker
+	markersLock    sync.Mutex // guards markers, markersVersion and cachedScanner
+	cachedScanner  *markerScanner
+)
+
+// getMarkerScanner returns the scanner of the current registry, compiling it again when markers changed
+func getMarkerScanner() *markerScanner {
+	markersLock.Lock()
+	defer markersLock.Unlock()
+	if cachedScanner == nil || cachedScanner.version != markersVersion || cachedScanner.size != len(markers) {
+		cachedScanner = newMarkerScanner(markers, markersVersion)
+	}
+	return cachedScanner
+}
+
+// newMarkerScanner compiles the scanner of a registry
+func newMarkerScanner(registry map[string]MarkerConfig, version int) *markerScanner {
+	scanner := &markerScanner{version: version, size: len(registry), byPrefix: make(map[string][]scannerMarker)}
+	for name, config := range registry {
+		if config.Pattern == nil {
+			continue
+		}
+		prefix, _ := config.Pattern.LiteralPrefix()
+		identifier := markerIdentifier(strings.TrimPrefix(prefix, "@"))
+		anchored, err := regexp.Compile(`^(?:` + config.Pattern.String() + `)`)
+		if !strings.HasPrefix(prefix, "@") || identifier == "" || err != nil {
+			scanner.fallback = append(scanner.fallback, scannerMarker{name: name, pattern: config.Pattern})
+			continue
+		}
+		simple := config.Pattern.String() == prefix+`\s*\(([^)]*)\)` && prefix == "@"+identifier
+		scanner.byPrefix[identifier] = append(scanner.byPrefix[identifier], scannerMarker{name: name, prefix: prefix, pattern: anchored, simple: simple})
+		scanner.maxPrefix = max(scanner.maxPrefix, len(identifier))
+	}
+	return scanner
+}
+
+// markerIdentifier leading identifier characters of s
+func markerIdentifier(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c != '_' && (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// match matches the marker at offset, returning the locations in text
+func (m scannerMarker) match(text string, offset int) []int {
+	if m.simple {
+		open := offset + len(m.prefix)
+		for open < len(text) && strings.IndexByte(" \t\n\f\r", text[open]) >= 0 {
+			open++
+		}
+		if open == len(text) || text[open] != '(' {
+			return nil
+		}
+		end := strings.IndexByte(text[open:], ')')
+		if end < 0 {
+			return nil
+		}
+		end += open
+		return []int{offset, end + 1, open + 1, end}
+	}
+
+	loc := m.pattern.FindStringSubmatchIndex(text[offset:])
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += offset
+		}
+	}
+	return loc
+}
+
+// scan returns the markers of text in source order, markers at the same offset by name. As with one
+// FindAll per pattern, matches of different markers may overlap, matches of the same marker do not.
+func (s *markerScanner) scan(text string) []markerMatch {
+	var found []markerMatch
+	var ends map[string]int
+
+	for offset := strings.IndexByte(text, '@'); offset >= 0; {
+		identifier := markerIdentifier(text[offset+1:])
+		// A prefix may stop inside the identifier (`@Cach(e|ed)`), so every leading part is a candidate
+		for length := min(len(identifier), s.maxPrefix); length > 0; length-- {
+			for _, marker := range s.byPrefix[identifier[:length]] {
+				if offset < ends[marker.name] || !strings.HasPrefix(text[offset:], marker.prefix) {
+					continue
+				}
+				loc := marker.match(text, offset)
+				if loc == nil {
+					continue
+				}
+				if ends == nil {
+					ends = make(map[string]int)
+				}
+				ends[marker.name] = loc[1]
+				found = append(found, markerMatch{name: marker.name, loc: loc})
+			}
+		}
+
+		next := strings.IndexByte(text[offset+1:], '@')
+		if next < 0 {
+			break
+		}
+		offset += next + 1
+	}
+
+	for _, marker := range s.fallback {
+		for _, loc := range marker.pattern.FindAllStringSubmatchIndex(text, -1) {
+			found = append(found, markerMatch{name: marker.name, loc: loc})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].loc[0] != found[j].loc[0] {
+			return found[i].loc[0] < found[j].loc[0]
+		}
+		return found[i].name < found[j].name
+	})
+	return found
+}
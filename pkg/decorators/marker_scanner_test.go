@@ -0,0 +1,143 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scanMarkersPerPattern runs every pattern over the whole text, as extraction did before the scanner
+func scanMarkersPerPattern(registry map[string]MarkerConfig, text string) []markerMatch {
+	var found []markerMatch
+	for name, config := range registry {
+		for _, loc := range config.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			found = append(found, markerMatch{name: name, loc: loc})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].loc[0] != found[j].loc[0] {
+			return found[i].loc[0] < found[j].loc[0]
+		}
+		return found[i].name < found[j].name
+	})
+	return found
+}
+
+func TestMarkerScanner_MatchesPerPatternSearch(t *testing.T) {
+	registry := map[string]MarkerConfig{
+		"Cache":       {Pattern: regexp.MustCompile(`@Cache\s*\(([^)]*)\)`)},
+		"CacheByUser": {Pattern: regexp.MustCompile(`@CacheByUser\s*\(([^)]*)\)`)},
+		"Summary":     {Pattern: regexp.MustCompile(`@Summary\s*\(([^)]*)\)`)},
+		"Translated":  {Pattern: regexp.MustCompile(`@Summary\.([a-zA-Z-]+)\s*\(([^)]*)\)`)},
+		"Partial":     {Pattern: regexp.MustCompile(`@Cach(?:ed|ing)\s*\(([^)]*)\)`)},
+		"Bare":        {Pattern: regexp.MustCompile(`(?m)^\s*@Public\b`)},
+	}
+	texts := []string{
+		"@Cache(ttl=1m)\n@CacheByUser(ttl=5m)\n@Cached()\n@Caching(x)",
+		"@Summary(List users)\n@Summary.pt-BR(Lista usuários)\n@Summary (spaced)",
+		`@Summary(see @Cache(ttl=1m) first) @Cache(ttl=2m)`,
+		"email me at user@example.com @Cache(broken\n@Public\n  @Public",
+		"no markers at all",
+		"@@Cache() @",
+	}
+
+	scanner := newMarkerScanner(registry, 0)
+	require.Len(t, scanner.fallback, 1, "patterns without an @Name prefix are searched as before")
+	for _, text := range texts {
+		assert.Equal(t, scanMarkersPerPattern(registry, text), scanner.scan(text), text)
+	}
+}
+
+func TestGetMarkerScanner_Recompiles(t *testing.T) {
+	first := getMarkerScanner()
+	assert.Same(t, first, getMarkerScanner())
+
+	RegisterMarker(MarkerConfig{Name: "ScannerProbe", Pattern: regexp.MustCompile(`@ScannerProbe\s*\(([^)]*)\)`)})
+	defer delete(markers, "ScannerProbe")
+	found := getMarkerScanner().scan("@ScannerProbe(x)")
+	require.Len(t, found, 1)
+	assert.Equal(t, "ScannerProbe", found[0].name)
+}
+
+// benchmarkRepo writes a repository of files handlers, each with a few annotated routes
+func benchmarkRepo(b *testing.B, files int) string {
+	dir := b.TempDir()
+	for i := 0; i < files; i++ {
+		var source strings.Builder
+		fmt.Fprintf(&source, "package handlers\n\nimport \"github.com/gin-gonic/gin\"\n")
+		for j := 0; j < 3; j++ {
+			fmt.Fprintf(&source, `
+// GetItem%[1]d_%[2]d returns an item of the catalog.
+// It is cached for a minute and rate limited per user; contact api@example.com for quota raises.
+// @Route("GET", "/items%[1]d/%[2]d/:id")
+// @Summary(Get item %[1]d)
+// @Description("Returns the item with the given ID, or 404")
+// @Auth(role=user)
+// @Cache(ttl=1m)
+// @RateLimit(limit=100, window=1m)
+// @Param(name="id", type="int", location="path", required=true, description="Item ID")
+// @Response(code=200, description="Item")
+// @Response(code=404, description="Not found")
+// @Tag("items")
+func GetItem%[1]d_%[2]d(c *gin.Context) {}
+`, i, j)
+		}
+		require.NoError(b, os.WriteFile(filepath.Join(dir, fmt.Sprintf("items_%d.go", i)), []byte(source.String()), 0o600))
+	}
+	return dir
+}
+
+// benchmarkComments the doc comments of the routes of benchmarkRepo
+func benchmarkComments(b *testing.B, files int) []string {
+	routes, err := ParseDirectory(benchmarkRepo(b, files))
+	require.NoError(b, err)
+	comments := make([]string, 0, len(routes))
+	for _, route := range routes {
+		var comment strings.Builder
+		comment.WriteString(route.FuncName + " returns an item of the catalog.\nContact api@example.com for quota raises.\n")
+		for _, marker := range route.Markers {
+			comment.WriteString(marker.Raw + "\n")
+		}
+		comments = append(comments, comment.String())
+	}
+	return comments
+}
+
+func BenchmarkMarkerScan(b *testing.B) {
+	comments := benchmarkComments(b, 1000)
+	scanner := getMarkerScanner()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, comment := range comments {
+			scanner.scan(comment)
+		}
+	}
+}
+
+func BenchmarkMarkerScanPerPattern(b *testing.B) {
+	comments := benchmarkComments(b, 1000)
+	registry := GetMarkers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, comment := range comments {
+			scanMarkersPerPattern(registry, comment)
+		}
+	}
+}
+
+func BenchmarkParseDirectory(b *testing.B) {
+	dir := benchmarkRepo(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseDirectory(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
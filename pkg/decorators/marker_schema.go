@@ -0,0 +1,254 @@
+package decorators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Types of marker arguments checked at generation time
+const (
+	MarkerArgString   = "string"
+	MarkerArgInt      = "int"
+	MarkerArgNumber   = "number"
+	MarkerArgBool     = "bool"
+	MarkerArgDuration = "duration"
+	MarkerArgSize     = "size"
+	MarkerArgList     = "list"
+	MarkerArgJSON     = "json"
+)
+
+// MarkerArg declares a key=value argument of a marker. Positional arguments and keys a
+// marker does not declare are left to its own parser.
+type MarkerArg struct {
+	Name     string   // argument key (ex: "limit")
+	Type     string   // one of the MarkerArg* types; empty is a string
+	Required bool     // the key must be present
+	Enum     []string // accepted values, if restricted
+}
+
+// builtinMarkerArgs argument schemas of the built-in markers
+var builtinMarkerArgs = map[string][]MarkerArg{
+	"Auth": {
+		{Name: "role"},
+		{Name: "roles", Type: MarkerArgList},
+		{Name: "required", Type: MarkerArgBool},
+		{Name: "cache", Type: MarkerArgDuration}, // TTL of the auth decision cache for the route
+	},
+	"Cache": {
+		{Name: "duration", Type: MarkerArgDuration},
+		{Name: "ttl", Type: MarkerArgDuration},
+		{Name: "type"},    // backend: memory, redis or one registered with RegisterCacheBackend
+		{Name: "backend"}, // alias of type
+		{Name: "key", Enum: []string{"url", "user", "endpoint"}},
+		{Name: "by", Enum: []string{"url", "user", "endpoint"}},
+		{Name: "encrypt", Type: MarkerArgBool},
+		{Name: "vary", Type: MarkerArgList},    // request headers in the key
+		{Name: "max_keys", Type: MarkerArgInt}, // live keys of the route
+		{Name: "etag", Enum: []string{CacheETagStrong, CacheETagWeak, CacheETagOff}},
+	},
+	"CacheByURL":      {{Name: "ttl", Type: MarkerArgDuration}},
+	"CacheByUser":     {{Name: "ttl", Type: MarkerArgDuration}},
+	"CacheByEndpoint": {{Name: "ttl", Type: MarkerArgDuration}},
+	"CacheStats":      {{Name: "maxSize", Type: MarkerArgInt}},
+	"InvalidateCache": {{Name: "maxSize", Type: MarkerArgInt}},
+	"RateLimit": {
+		{Name: "limit", Type: MarkerArgInt},
+		{Name: "rps", Type: MarkerArgInt},
+		{Name: "window", Type: MarkerArgDuration},
+		{Name: "type", Enum: []string{"memory", "redis"}},
+		{Name: "key", Enum: []string{"ip", "user", "endpoint"}},
+		{Name: "by", Enum: []string{"ip", "user", "endpoint"}},
+		{Name: "algorithm", Enum: []string{RateLimitSlidingWindow, RateLimitTokenBucket}},
+		{Name: "burst", Type: MarkerArgInt},
+	},
+	"RateLimitByIP":       {{Name: "limit", Type: MarkerArgInt}},
+	"RateLimitByUser":     {{Name: "limit", Type: MarkerArgInt}},
+	"RateLimitByEndpoint": {{Name: "limit", Type: MarkerArgInt}},
+	"Proxy": {
+		{Name: "timeout", Type: MarkerArgDuration},
+		{Name: "retries", Type: MarkerArgInt},
+		{Name: "retry_delay", Type: MarkerArgDuration},
+		{Name: "health_interval", Type: MarkerArgDuration},
+		{Name: "circuit_breaker", Type: MarkerArgDuration},
+		{Name: "failure_threshold", Type: MarkerArgInt},
+		{Name: "discovery", Enum: []string{"static", "consul", "dns", "kubernetes"}},
+	},
+	"Doc": {{Name: "file"}},
+	"Dedupe": {
+		{Name: "key", Required: true},
+		{Name: "ttl", Type: MarkerArgDuration},
+		{Name: "store", Enum: []string{"memory", "redis"}},
+	},
+	"Subscribe": {
+		{Name: "topic", Required: true},
+		{Name: "group", Required: true},
+		{Name: "concurrency", Type: MarkerArgInt},
+		{Name: "retries", Type: MarkerArgInt},
+		{Name: "backoff", Type: MarkerArgDuration},
+	},
+	"GRPC": {
+		{Name: "service", Required: true},
+		{Name: "method"},
+	},
+	"Provide": {
+		{Name: "name"}, // usually positional
+		{Name: "factory", Required: true},
+		{Name: "cache", Type: MarkerArgBool},
+	},
+	"SlowThreshold": {{Name: "threshold", Type: MarkerArgDuration}},
+	"Deprecated": {
+		{Name: "sunset"},
+		{Name: "link"},
+		{Name: "headers", Type: MarkerArgBool},
+	},
+	"Controller": {
+		{Name: "prefix"}, // usually positional
+		{Name: "constructor"},
+	},
+	"RequestBody": {
+		{Name: "type"}, // usually positional
+		{Name: "contentType"},
+		{Name: "description"},
+		{Name: "example"},
+	},
+	"FileParam": {
+		{Name: "name"}, // usually positional
+		{Name: "required", Type: MarkerArgBool},
+		{Name: "multiple", Type: MarkerArgBool},
+		{Name: "max_size", Type: MarkerArgSize},
+		{Name: "types", Type: MarkerArgList},
+		{Name: "description"},
+	},
+	"Transactional": {
+		{Name: "manager"},
+		{Name: "isolation", Enum: []string{"default", "read_uncommitted", "read_committed", "repeatable_read", "snapshot", "serializable"}},
+		{Name: "readonly", Type: MarkerArgBool},
+	},
+	"Compress": {
+		{Name: "types", Type: MarkerArgList},
+		{Name: "level", Type: MarkerArgInt},
+		{Name: "min_size", Type: MarkerArgSize},
+		{Name: "encodings", Type: MarkerArgList},
+	},
+	"Log": {
+		{Name: "level", Enum: []string{"debug", "info", "warn", "error"}},
+		{Name: "redact", Type: MarkerArgList},
+		{Name: "bodies", Type: MarkerArgBool},
+	},
+	"Envelope": {
+		{Name: "skip_errors", Type: MarkerArgBool},
+	},
+	"CircuitBreaker": {
+		{Name: "threshold", Type: MarkerArgInt},
+		{Name: "window", Type: MarkerArgDuration},
+		{Name: "cooldown", Type: MarkerArgDuration},
+		{Name: "name"},
+	},
+	"SSE": {
+		{Name: "channel"},
+		{Name: "events", Type: MarkerArgList},
+		{Name: "type"},
+		{Name: "heartbeat", Type: MarkerArgDuration},
+	},
+	"MaxResponseSize": {
+		{Name: "size", Type: MarkerArgSize},
+		{Name: "action", Enum: []string{ResponseSizeActionReject, ResponseSizeActionLog}},
+	},
+	"MaxBodySize": {{Name: "size", Type: MarkerArgSize}},
+}
+
+// attachBuiltinMarkerArgs sets the argument schemas of the default markers
+func attachBuiltinMarkerArgs() {
+	markersLock.Lock()
+	defer markersLock.Unlock()
+	for name, args := range builtinMarkerArgs {
+		if config, ok := markers[name]; ok {
+			config.Args = args
+			markers[name] = config
+		}
+	}
+}
+
+// validateMarkerArgs checks marker arguments against the schema declared in its MarkerConfig
+func validateMarkerArgs(decoratorName string, args []string) error {
+	config, _ := GetMarker(decoratorName)
+	schema := config.Args
+	if len(schema) == 0 {
+		return nil
+	}
+
+	present := make(map[string]bool)
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		present[key] = true
+		for _, declared := range schema {
+			if declared.Name != key {
+				continue
+			}
+			if err := declared.check(value); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+		}
+	}
+
+	for _, declared := range schema {
+		if declared.Required && !present[declared.Name] {
+			return fmt.Errorf("missing required argument '%s'", declared.Name)
+		}
+	}
+	return nil
+}
+
+// check validates one argument value against its declared type and enum
+func (a MarkerArg) check(raw string) error {
+	value := MarkerValue(raw)
+	switch a.Type {
+	case MarkerArgInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("expected an integer, found '%s'", value)
+		}
+	case MarkerArgNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("expected a number, found '%s'", value)
+		}
+	case MarkerArgBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("expected true or false, found '%s'", value)
+		}
+	case MarkerArgDuration:
+		duration, err := ParseDurationLiteral(raw)
+		if err != nil {
+			return err
+		}
+		if duration < 0 {
+			return fmt.Errorf("duration must not be negative, found '%s'", value)
+		}
+	case MarkerArgSize:
+		if _, err := ParseByteSize(raw); err != nil {
+			return err
+		}
+	case MarkerArgJSON:
+		var decoded interface{}
+		if err := MarkerJSON(raw, &decoded); err != nil {
+			return err
+		}
+	}
+
+	if len(a.Enum) > 0 {
+		values := []string{value}
+		if a.Type == MarkerArgList {
+			values = MarkerList(raw)
+		}
+		for _, item := range values {
+			if !contains(a.Enum, item) {
+				return fmt.Errorf("invalid value '%s' (valid: %s)", item, strings.Join(a.Enum, ", "))
+			}
+		}
+	}
+	return nil
+}
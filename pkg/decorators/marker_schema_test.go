@@ -0,0 +1,96 @@
+package decorators
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMarkerArgs(t *testing.T) {
+	assert.NoError(t, validateArgumentValues("RateLimit", []string{"limit=100", `window="1m"`, "type=redis", "by=user"}))
+	assert.NoError(t, validateArgumentValues("Auth", []string{"roles=[admin, ops]", "required=false"}))
+	assert.NoError(t, validateArgumentValues("RateLimit", []string{"100", "1m"}), "positional arguments are left to the marker")
+
+	assert.EqualError(t, validateArgumentValues("RateLimit", []string{`limit="abc"`}), "limit: expected an integer, found 'abc'")
+	assert.EqualError(t, validateArgumentValues("RateLimit", []string{"type=disk"}), "type: invalid value 'disk' (valid: memory, redis)")
+	assert.NoError(t, validateArgumentValues("Cache", []string{"backend=memcached"}), "cache backends can be registered at runtime")
+	assert.EqualError(t, validateArgumentValues("Auth", []string{"required=maybe"}), "required: expected true or false, found 'maybe'")
+	assert.EqualError(t, validateArgumentValues("Subscribe", []string{"topic=orders"}), "missing required argument 'group'")
+	assert.ErrorContains(t, validateArgumentValues("Proxy", []string{"target=http://x", "discovery=etcd"}), "discovery: invalid value 'etcd'")
+}
+
+func TestMarkerArg_Check(t *testing.T) {
+	tests := []struct {
+		arg   MarkerArg
+		value string
+		err   string
+	}{
+		{MarkerArg{Type: MarkerArgNumber}, "0.5", ""},
+		{MarkerArg{Type: MarkerArgNumber}, "half", "expected a number, found 'half'"},
+		{MarkerArg{Type: MarkerArgJSON}, `{"rps": 10}`, ""},
+		{MarkerArg{Type: MarkerArgJSON}, `{rps}`, "invalid JSON value"},
+		{MarkerArg{Type: MarkerArgSize}, "10MB", ""},
+		{MarkerArg{Type: MarkerArgList, Enum: []string{"a", "b"}}, "[a, c]", "invalid value 'c' (valid: a, b)"},
+		{MarkerArg{Enum: []string{"a", "b"}}, `"b"`, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.arg.check(tt.value)
+		if tt.err == "" {
+			assert.NoError(t, err, tt.value)
+		} else {
+			assert.ErrorContains(t, err, tt.err, tt.value)
+		}
+	}
+}
+
+func TestParseDirectory_ArgumentTypeErrorPointsToAnnotation(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "handlers.go"), `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @RateLimit(limit="abc", window=1m)
+func ListUsers(c *gin.Context) {}
+`)
+
+	_, err := ParseDirectory(dir)
+	var multiple *MultipleValidationError
+	require.True(t, errors.As(err, &multiple), "%v", err)
+	require.Len(t, multiple.Errors, 1)
+	assert.Equal(t, 6, multiple.Errors[0].Line)
+	assert.Contains(t, multiple.Errors[0].Message, "limit: expected an integer, found 'abc'")
+}
+
+func TestMarkerArgsFromPluginLiteral(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "stripe.go"), `package stripe
+
+import "github.com/RodolfoBonis/deco/pkg/decorators"
+
+var _ = decorators.MarkerConfig{
+	Name: "Stripe",
+	Args: []decorators.MarkerArg{
+		{Name: "secret", Required: true},
+		{Name: "tolerance", Type: decorators.MarkerArgDuration},
+		{Name: "mode", Type: "string", Enum: []string{"live", "test"}},
+	},
+}
+`)
+	configs, err := scanMarkerPlugin(root, "example.com/stripe")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, []MarkerArg{
+		{Name: "secret", Required: true},
+		{Name: "tolerance", Type: MarkerArgDuration},
+		{Name: "mode", Type: MarkerArgString, Enum: []string{"live", "test"}},
+	}, configs[0].Args)
+
+	writeTestFile(t, filepath.Join(root, "stripe.go"), "package stripe\n\nvar _ = MarkerConfig{Name: \"Stripe\", Args: []MarkerArg{{Name: \"x\", Type: kind}}}\n")
+	_, err = scanMarkerPlugin(root, "example.com/stripe")
+	assert.ErrorContains(t, err, "MarkerArg.Type must be a string or MarkerArg* constant")
+}
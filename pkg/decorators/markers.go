@@ -1,10 +1,12 @@
 package decorators
 
 import (
+	"errors"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,25 +20,45 @@ type MarkerConfig struct {
 	Pattern     *regexp.Regexp                      // Regex to detect the marker
 	Factory     func(args []string) gin.HandlerFunc // Factory to create middleware
 	Description string                              // Marker description
+	Package     string                              // import path of the plugin package declaring the marker (see LoadMarkerPlugins)
+	Args        []MarkerArg                         // key=value arguments checked at generation time
 }
 
-// global markers registry
+// global markers registry, guarded by markersLock
 var markers = make(map[string]MarkerConfig)
 
 // init registers default markers automatically
 func init() {
 	initDefaultMarkers()
+	attachBuiltinMarkerArgs()
 }
 
 // RegisterMarker registers a new marker in the framework
 func RegisterMarker(config MarkerConfig) {
+	markersLock.Lock()
 	markers[config.Name] = config
+	markersVersion++
+	markersLock.Unlock()
 	LogVerbose("Marker registered: %s", config.Name)
 }
 
-// GetMarkers returns all registered markers
+// GetMarkers returns a copy of the registered markers; changes to it do not affect the registry
 func GetMarkers() map[string]MarkerConfig {
-	return markers
+	markersLock.Lock()
+	defer markersLock.Unlock()
+	registry := make(map[string]MarkerConfig, len(markers))
+	for name, config := range markers {
+		registry[name] = config
+	}
+	return registry
+}
+
+// GetMarker returns a registered marker by name
+func GetMarker(name string) (MarkerConfig, bool) {
+	markersLock.Lock()
+	defer markersLock.Unlock()
+	config, ok := markers[name]
+	return config, ok
 }
 
 // initDefaultMarkers registers framework default markers
@@ -210,6 +232,96 @@ func initDefaultMarkers() {
 		Factory: createSecurityMiddleware,
 	})
 
+	RegisterMarker(MarkerConfig{
+		Name:    "MaxResponseSize",
+		Pattern: regexp.MustCompile(`@MaxResponseSize\s*\(([^)]*)\)`),
+		Factory: createMaxResponseSizeMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "MaxBodySize",
+		Pattern: regexp.MustCompile(`@MaxBodySize\s*\(([^)]*)\)`),
+		Factory: createMaxBodySizeMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "SlowThreshold",
+		Pattern: regexp.MustCompile(`@SlowThreshold\s*\(([^)]*)\)`),
+		Factory: createSlowThresholdMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "NoAccessLog",
+		Pattern: regexp.MustCompile(`@NoAccessLog\s*\(([^)]*)\)`),
+		Factory: createNoAccessLogMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Mock",
+		Pattern: regexp.MustCompile(`@Mock\s*\(([^)]*)\)`),
+		Factory: createMockMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Dedupe",
+		Pattern: regexp.MustCompile(`@Dedupe\s*\(([^)]*)\)`),
+		Factory: createDedupeMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "SagaStep",
+		Pattern: regexp.MustCompile(`@SagaStep\s*\(([^)]*)\)`),
+		Factory: createSagaStepMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "RequireHeader",
+		Pattern: regexp.MustCompile(`@RequireHeader\s*\(([^)]*)\)`),
+		Factory: createRequireHeaderMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "FileParam",
+		Pattern: regexp.MustCompile(`@FileParam\s*\(([^)]*)\)`),
+		Factory: createFileParamMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Transactional",
+		Pattern: regexp.MustCompile(`@Transactional\s*\(([^)]*)\)`),
+		Factory: createTransactionalMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Compress",
+		Pattern: regexp.MustCompile(`@Compress\s*\(([^)]*)\)`),
+		Factory: createCompressMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Log",
+		Pattern: regexp.MustCompile(`@Log\s*\(([^)]*)\)`),
+		Factory: createLogMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Envelope",
+		Pattern: regexp.MustCompile(`@Envelope\s*\(([^)]*)\)`),
+		Factory: createEnvelopeMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Sensitive",
+		Pattern: regexp.MustCompile(`@Sensitive\s*\(([^)]*)\)`),
+		Factory: createSensitiveMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "FieldACL",
+		Pattern: regexp.MustCompile(`@FieldACL\s*\(([^)]*)\)`),
+		Factory: createFieldACLMiddleware,
+	})
+
 	RegisterMarker(MarkerConfig{
 		Name:    "CORS",
 		Pattern: regexp.MustCompile(`@CORS\s*\(([^)]*)\)`),
@@ -228,6 +340,24 @@ func initDefaultMarkers() {
 		Factory: createWebSocketMiddleware,
 	})
 
+	RegisterMarker(MarkerConfig{
+		Name:    "SSE",
+		Pattern: regexp.MustCompile(`@SSE\s*\(([^)]*)\)`),
+		Factory: createSSEMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Deprecated",
+		Pattern: regexp.MustCompile(`@Deprecated\s*\(([^)]*)\)`),
+		Factory: createDeprecatedMiddleware,
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "CircuitBreaker",
+		Pattern: regexp.MustCompile(`@CircuitBreaker\s*\(([^)]*)\)`),
+		Factory: createCircuitBreakerMiddleware,
+	})
+
 	// Documentation markers
 	RegisterMarker(MarkerConfig{
 		Name:    "Group",
@@ -241,24 +371,91 @@ func initDefaultMarkers() {
 		Factory: nil, // Does not generate middleware
 	})
 
+	RegisterMarker(MarkerConfig{
+		Name:    "RequestBody",
+		Pattern: regexp.MustCompile(`@RequestBody\s*\(([^)]*)\)`),
+		Factory: nil, // Does not generate middleware
+	})
+
 	RegisterMarker(MarkerConfig{
 		Name:    "Description",
 		Pattern: regexp.MustCompile(`@Description\s*\(([^)]*)\)`),
 		Factory: nil, // Does not generate middleware
 	})
 
+	RegisterMarker(MarkerConfig{
+		Name:    "Doc",
+		Pattern: regexp.MustCompile(`@Doc\s*\(([^)]*)\)`),
+		Factory: nil, // Does not generate middleware
+	})
+
 	RegisterMarker(MarkerConfig{
 		Name:    "Summary",
 		Pattern: regexp.MustCompile(`@Summary\s*\(([^)]*)\)`),
 		Factory: nil, // Does not generate middleware
 	})
 
+	// Translated @Summary/@Description, e.g. @Summary.pt-BR("Listar pedidos")
+	RegisterMarker(MarkerConfig{
+		Name:    "SummaryTranslation",
+		Pattern: regexp.MustCompile(`@Summary\.[A-Za-z]{2,3}(?:-[A-Za-z0-9]+)*\s*\(([^)]*)\)`),
+		Factory: nil, // Does not generate middleware
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "DescriptionTranslation",
+		Pattern: regexp.MustCompile(`@Description\.[A-Za-z]{2,3}(?:-[A-Za-z0-9]+)*\s*\(([^)]*)\)`),
+		Factory: nil, // Does not generate middleware
+	})
+
 	RegisterMarker(MarkerConfig{
 		Name:    "Schema",
 		Pattern: regexp.MustCompile(`@Schema\s*\(([^)]*)\)`),
 		Factory: nil, // Documentation only - does not generate middleware
 	})
 
+	RegisterMarker(MarkerConfig{
+		Name:    "Controller",
+		Pattern: regexp.MustCompile(`@Controller\s*\(([^)]*)\)`),
+		Factory: nil, // Read from the type declaration - the generated code instantiates the struct
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Subscribe",
+		Pattern: regexp.MustCompile(`@Subscribe\s*\(([^)]*)\)`),
+		Factory: nil, // Registers a message consumer - does not generate middleware
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "GRPC",
+		Pattern: regexp.MustCompile(`@GRPC\s*\(([^)]*)\)`),
+		Factory: nil, // Exposes the route over gRPC - does not generate middleware
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Provide",
+		Pattern: regexp.MustCompile(`@Provide\s*\(([^)]*)\)`),
+		Factory: nil, // The generated code references the factory function directly
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "SchemaVersion",
+		Pattern: regexp.MustCompile(`@SchemaVersion\s*\(([^)]*)\)`),
+		Factory: nil, // Documentation only - does not generate middleware
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "MiddlewareOrder",
+		Pattern: regexp.MustCompile(`@MiddlewareOrder\s*\(([^)]*)\)`),
+		Factory: nil, // Orders the other middlewares of the route
+	})
+
+	RegisterMarker(MarkerConfig{
+		Name:    "Version",
+		Pattern: regexp.MustCompile(`@Version\s*\(([^)]*)\)`),
+		Factory: nil, // Registers the route once per version - does not generate middleware
+	})
+
 	RegisterMarker(MarkerConfig{
 		Name:    "Tag",
 		Pattern: regexp.MustCompile(`@Tag\s*\(([^)]*)\)`),
@@ -272,34 +469,93 @@ func initDefaultMarkers() {
 	})
 }
 
-// createAuthMiddleware creates authentication middleware
+// createAuthMiddleware creates authentication middleware. With auth.jwt configured the bearer token is
+// verified and role/roles are enforced against its roles claim; otherwise only its presence is checked.
+// required=false lets requests without a token through anonymously.
 func createAuthMiddleware(args []string) gin.HandlerFunc {
 	var role string
+	var roles []string
+	required := true
+	cacheTTL := time.Duration(-1) // auth.cache_ttl unless the route sets cache=
 	if len(args) > 0 && args[0] != "" {
-		role = parseKeyValue(args[0], "role")
+		joined := strings.Join(args, ",")
+		role = parseKeyValue(joined, "role")
+		roles = MarkerList(parseKeyValue(joined, "roles"))
+		required = parseKeyValue(joined, "required") != "false"
+		if value := parseKeyValue(joined, "cache"); value != "" {
+			if ttl, err := ParseDurationLiteral(value); err == nil {
+				cacheTTL = ttl
+			}
+		}
 	}
 
 	return gin.HandlerFunc(func(c *gin.Context) {
+		verifier := GetJWTVerifier()
 		token := c.GetHeader("Authorization")
 		if token == "" {
+			if !required {
+				c.Next()
+				return
+			}
 			c.JSON(401, gin.H{"error": "Token de autorização requerido"})
 			c.Abort()
 			return
 		}
 
-		// Basic token validation (in production use JWT)
 		if !strings.HasPrefix(token, "Bearer ") {
 			c.JSON(401, gin.H{"error": "Token inválido"})
 			c.Abort()
 			return
 		}
 
-		// If role specified, validate
+		if verifier == nil {
+			// No key source configured: the token is not verified
+			if role != "" {
+				c.Set("user_role", role)
+			}
+			if len(roles) > 0 {
+				c.Set("user_roles", roles)
+			}
+			c.Set("authenticated", true)
+			c.Next()
+			return
+		}
+
+		ttl := cacheTTL
+		if ttl < 0 {
+			ttl = defaultAuthCacheTTL()
+		}
+		claims, err := verifyBearerToken(c.Request.Context(), verifier, strings.TrimPrefix(token, "Bearer "), ttl)
+		if err != nil && !errors.Is(err, ErrInvalidToken) {
+			c.JSON(503, gin.H{"error": "Verificação do token indisponível", "message": err.Error()})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.JSON(401, gin.H{"error": "Token inválido", "message": err.Error()})
+			c.Abort()
+			return
+		}
+
+		tokenRoles := claims.Strings(verifier.config.RolesClaim)
+		if (role != "" && !containsAny(tokenRoles, []string{role})) || (len(roles) > 0 && !containsAny(tokenRoles, roles)) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+			c.JSON(403, gin.H{"error": "Permissão insuficiente"})
+			c.Abort()
+			return
+		}
+
+		c.Set("claims", claims)
+		if user := claims.String(verifier.config.UserClaim); user != "" {
+			c.Set("user_id", user)
+		}
+		c.Set("user_roles", tokenRoles)
 		if role != "" {
-			// Role validation logic (simulated)
 			c.Set("user_role", role)
+		} else if len(tokenRoles) > 0 {
+			c.Set("user_role", tokenRoles[0])
 		}
-
 		c.Set("authenticated", true)
 		c.Next()
 	})
@@ -307,15 +563,7 @@ func createAuthMiddleware(args []string) gin.HandlerFunc {
 
 // createCacheMiddleware creates cache middleware
 func createCacheMiddleware(args []string) gin.HandlerFunc {
-	duration, cacheType, keyGen := ParseCacheArgs(args)
-
-	config := &CacheConfig{
-		Type:       cacheType,
-		DefaultTTL: duration.String(),
-		MaxSize:    1000,
-	}
-
-	return CacheMiddleware(config, keyGen)
+	return CacheWith(parseCacheOptions(args))
 }
 
 // createCORSMiddleware creates CORS middleware
@@ -341,11 +589,14 @@ func createCORSMiddleware(args []string) gin.HandlerFunc {
 
 // parseKeyValue extracts value from a key=value string
 func parseKeyValue(input, key string) string {
-	pairs := strings.Split(input, ",")
+	pairs, err := splitMarkerArguments(input)
+	if err != nil {
+		pairs = strings.Split(input, ",")
+	}
 	for _, pair := range pairs {
-		kv := strings.Split(strings.TrimSpace(pair), "=")
-		if len(kv) == 2 && strings.TrimSpace(kv[0]) == key {
-			return strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+		name, value, found := strings.Cut(pair, "=")
+		if found && strings.TrimSpace(name) == key {
+			return MarkerValue(value)
 		}
 	}
 	return ""
@@ -370,7 +621,7 @@ func createValidateJSONMiddleware(args []string) gin.HandlerFunc {
 	}
 	return gin.HandlerFunc(func(c *gin.Context) {
 		var data map[string]interface{}
-		if err := c.ShouldBindJSON(&data); err != nil {
+		if err := bindJSONBody(c, &data); err != nil {
 			response := ValidationResponse{
 				Error:   "validation_failed",
 				Message: "Invalid JSON format",
@@ -575,7 +826,7 @@ func createCacheByEndpointMiddleware(args []string) gin.HandlerFunc {
 
 // createRateLimitByIPMiddleware creates IP-based rate limiting middleware with customizable limit via args
 func createRateLimitByIPMiddleware(args []string) gin.HandlerFunc {
-	config := DefaultConfig().RateLimit
+	config := rateLimitConfig()
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "limit=") {
 			v := strings.TrimPrefix(arg, "limit=")
@@ -589,7 +840,7 @@ func createRateLimitByIPMiddleware(args []string) gin.HandlerFunc {
 
 // createRateLimitByUserMiddleware creates user-based rate limiting middleware with customizable limit via args
 func createRateLimitByUserMiddleware(args []string) gin.HandlerFunc {
-	config := DefaultConfig().RateLimit
+	config := rateLimitConfig()
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "limit=") {
 			v := strings.TrimPrefix(arg, "limit=")
@@ -603,7 +854,7 @@ func createRateLimitByUserMiddleware(args []string) gin.HandlerFunc {
 
 // createRateLimitByEndpointMiddleware creates endpoint-based rate limiting middleware with customizable limit via args
 func createRateLimitByEndpointMiddleware(args []string) gin.HandlerFunc {
-	config := DefaultConfig().RateLimit
+	config := rateLimitConfig()
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "limit=") {
 			v := strings.TrimPrefix(arg, "limit=")
@@ -661,3 +912,14 @@ func createSecurityMiddleware(args []string) gin.HandlerFunc {
 
 	return SecureInternalEndpoints(config)
 }
+
+// createMaxResponseSizeMiddleware creates response size cap middleware
+func createMaxResponseSizeMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseMaxResponseSizeArgs(args)
+	if err != nil {
+		// Rejected during generation; invalid hand-written calls disable the cap
+		LogSilent("⚠️  %v", err)
+		config = ResponseSizeConfig{}
+	}
+	return MaxResponseSizeMiddleware(config)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"sync"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -57,6 +58,36 @@ func TestGetMarkers(t *testing.T) {
 	assert.Equal(t, "Auth", authMarker.Name)
 	assert.NotNil(t, authMarker.Pattern)
 	assert.NotNil(t, authMarker.Factory)
+
+	// The registry is not changed through the returned map
+	delete(markers, "Auth")
+	config, ok := GetMarker("Auth")
+	assert.True(t, ok)
+	assert.Equal(t, "Auth", config.Name)
+	_, ok = GetMarker("NoSuchMarker")
+	assert.False(t, ok)
+}
+
+func TestRegisterMarkerConcurrentScan(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterMarker(MarkerConfig{Name: fmt.Sprintf("ConcurrentMarker%d", i), Pattern: regexp.MustCompile(fmt.Sprintf(`@ConcurrentMarker%d\s*\(([^)]*)\)`, i))})
+		}(i)
+		go func() {
+			defer wg.Done()
+			getMarkerScanner()
+			GetMarkers()
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 4; i++ {
+		_, ok := GetMarker(fmt.Sprintf("ConcurrentMarker%d", i))
+		assert.True(t, ok)
+	}
 }
 
 func TestDefaultMarkers_Registration(t *testing.T) {
@@ -1,6 +1,7 @@
 package decorators
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ type MetricsCollector struct {
 	httpRequestSize     *prometheus.HistogramVec
 	httpResponseSize    *prometheus.HistogramVec
 	httpActiveRequests  *prometheus.GaugeVec
+	responseSizeLimited *prometheus.CounterVec
 
 	// Middleware metrics
 	middlewareExecutionTime *prometheus.HistogramVec
@@ -38,6 +40,10 @@ type MetricsCollector struct {
 	validationErrors *prometheus.CounterVec
 	validationTime   *prometheus.HistogramVec
 
+	// Subscriber metrics
+	subscriberMessages *prometheus.CounterVec
+	subscriberDuration *prometheus.HistogramVec
+
 	// System metrics
 	gorutines       prometheus.Gauge
 	memoryAllocated prometheus.Gauge
@@ -60,6 +66,11 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 		return defaultMetricsCollector
 	}
 
+	sizeBuckets := config.SizeBuckets
+	if len(sizeBuckets) == 0 {
+		sizeBuckets = prometheus.ExponentialBuckets(100, 10, 5)
+	}
+
 	collector := &MetricsCollector{
 		// HTTP metrics
 		httpRequestsTotal: prometheus.NewCounterVec(
@@ -89,7 +100,7 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 				Subsystem: config.Subsystem,
 				Name:      "http_request_size_bytes",
 				Help:      "Size of HTTP requests in bytes",
-				Buckets:   prometheus.ExponentialBuckets(100, 10, 5),
+				Buckets:   sizeBuckets,
 			},
 			[]string{"method", "endpoint"},
 		),
@@ -100,7 +111,7 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 				Subsystem: config.Subsystem,
 				Name:      "http_response_size_bytes",
 				Help:      "Size of HTTP responses in bytes",
-				Buckets:   prometheus.ExponentialBuckets(100, 10, 5),
+				Buckets:   sizeBuckets,
 			},
 			[]string{"method", "endpoint", "status"},
 		),
@@ -115,6 +126,16 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 			[]string{"method", "endpoint"},
 		),
 
+		responseSizeLimited: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "response_size_limit_exceeded_total",
+				Help:      "Total number of responses exceeding their configured size limit",
+			},
+			[]string{"method", "endpoint", "action"},
+		),
+
 		// Middleware metrics
 		middlewareExecutionTime: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
@@ -211,6 +232,28 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 			[]string{"validation_type"},
 		),
 
+		// Subscriber metrics
+		subscriberMessages: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "subscriber_messages_total",
+				Help:      "Total number of consumed messages by outcome",
+			},
+			[]string{"topic", "group", "result"},
+		),
+
+		subscriberDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "subscriber_processing_seconds",
+				Help:      "Time spent processing consumed messages, retries included",
+				Buckets:   config.Buckets,
+			},
+			[]string{"topic", "group"},
+		),
+
 		// System metrics
 		gorutines: prometheus.NewGauge(
 			prometheus.GaugeOpts{
@@ -240,6 +283,7 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 			collector.httpRequestSize,
 			collector.httpResponseSize,
 			collector.httpActiveRequests,
+			collector.responseSizeLimited,
 			collector.middlewareExecutionTime,
 			collector.middlewareErrors,
 			collector.cacheHits,
@@ -249,8 +293,11 @@ func InitMetrics(config *MetricsConfig) *MetricsCollector {
 			collector.rateLimitExceeded,
 			collector.validationErrors,
 			collector.validationTime,
+			collector.subscriberMessages,
+			collector.subscriberDuration,
 			collector.gorutines,
 			collector.memoryAllocated,
+			newStatsCollector(config.Namespace, config.Subsystem),
 		}
 
 		for _, metric := range metrics {
@@ -298,9 +345,13 @@ func MetricsMiddleware(config *MetricsConfig) gin.HandlerFunc {
 
 		defaultMetricsCollector.httpActiveRequests.WithLabelValues(method, endpoint).Inc()
 
-		// Register request size
+		// Register request size, counting the body when its length is unknown (chunked)
+		var bodyCounter *countingReadCloser
 		if c.Request.ContentLength > 0 {
 			defaultMetricsCollector.httpRequestSize.WithLabelValues(method, endpoint).Observe(float64(c.Request.ContentLength))
+		} else if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			bodyCounter = &countingReadCloser{ReadCloser: c.Request.Body}
+			c.Request.Body = bodyCounter
 		}
 
 		// Capture response
@@ -321,6 +372,9 @@ func MetricsMiddleware(config *MetricsConfig) gin.HandlerFunc {
 		defaultMetricsCollector.httpRequestsTotal.WithLabelValues(method, endpoint, status, "unknown").Inc()
 		defaultMetricsCollector.httpRequestDuration.WithLabelValues(method, endpoint, status).Observe(duration.Seconds())
 		defaultMetricsCollector.httpResponseSize.WithLabelValues(method, endpoint, status).Observe(float64(writer.size))
+		if bodyCounter != nil && bodyCounter.count > 0 {
+			defaultMetricsCollector.httpRequestSize.WithLabelValues(method, endpoint).Observe(float64(bodyCounter.count))
+		}
 
 		// Decrement active requests
 		defaultMetricsCollector.httpActiveRequests.WithLabelValues(method, endpoint).Dec()
@@ -345,6 +399,18 @@ func (w *metricsResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// countingReadCloser counts bytes read from a request body
+type countingReadCloser struct {
+	io.ReadCloser
+	count int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
 // getEndpointPattern extracts endpoint pattern
 func getEndpointPattern(c *gin.Context) string {
 	// Use FullPath() if available, otherwise use Path
@@ -364,6 +430,25 @@ func getEndpointPattern(c *gin.Context) string {
 	return path
 }
 
+// RecordResponseSizeExceeded records a response over its size limit
+func RecordResponseSizeExceeded(method, endpoint, action string) {
+	metricsInitMutex.RLock()
+	defer metricsInitMutex.RUnlock()
+	if defaultMetricsCollector != nil {
+		defaultMetricsCollector.responseSizeLimited.WithLabelValues(method, endpoint, action).Inc()
+	}
+}
+
+// RecordSubscriberMessage records the outcome of a consumed message
+func RecordSubscriberMessage(topic, group, result string, duration time.Duration) {
+	metricsInitMutex.RLock()
+	defer metricsInitMutex.RUnlock()
+	if defaultMetricsCollector != nil {
+		defaultMetricsCollector.subscriberMessages.WithLabelValues(topic, group, result).Inc()
+		defaultMetricsCollector.subscriberDuration.WithLabelValues(topic, group).Observe(duration.Seconds())
+	}
+}
+
 // RecordCacheHit registra hit de cache
 func RecordCacheHit(cacheType, keyType string) {
 	metricsInitMutex.RLock()
@@ -536,6 +621,7 @@ func GetMetricsInfo(config *MetricsConfig) MetricsInfo {
 		"http_request_size_bytes",
 		"http_response_size_bytes",
 		"http_active_requests",
+		"response_size_limit_exceeded_total",
 		"middleware_execution_time_seconds",
 		"middleware_errors_total",
 		"cache_hits_total",
@@ -547,6 +633,18 @@ func GetMetricsInfo(config *MetricsConfig) MetricsInfo {
 		"validation_time_seconds",
 		"goroutines",
 		"memory_allocated_bytes",
+		"uptime_seconds",
+		"route_requests_total",
+		"route_errors_total",
+		"cache_store_hits_total",
+		"cache_store_misses_total",
+		"cache_store_entries",
+		"rate_limit_allowed_total",
+		"rate_limit_rejected_total",
+		"websocket_connections",
+		"sse_clients",
+		"proxy_requests_total",
+		"proxy_failures_total",
 	}
 
 	return MetricsInfo{
@@ -35,6 +35,7 @@ func TestInitMetrics(t *testing.T) {
 	assert.NotNil(t, collector.httpRequestSize)
 	assert.NotNil(t, collector.httpResponseSize)
 	assert.NotNil(t, collector.httpActiveRequests)
+	assert.NotNil(t, collector.responseSizeLimited)
 	assert.NotNil(t, collector.middlewareExecutionTime)
 	assert.NotNil(t, collector.middlewareErrors)
 	assert.NotNil(t, collector.cacheHits)
@@ -0,0 +1,245 @@
+package decorators
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MiddlewareDebugPath endpoint describing the middleware chain of a route
+const MiddlewareDebugPath = "/decorators/debug/middlewares"
+
+// DefaultMiddlewareTimingSamples number of recent requests kept per route in debug mode
+const DefaultMiddlewareTimingSamples = 20
+
+// MiddlewareChainEntry a middleware of a route chain, in execution order
+type MiddlewareChainEntry struct {
+	Order       int                    `json:"order"`
+	Name        string                 `json:"name"`
+	Args        map[string]interface{} `json:"args,omitempty"`
+	Description string                 `json:"description,omitempty"`
+}
+
+// MiddlewareTiming time spent inside a single middleware, excluding the handlers it called
+type MiddlewareTiming struct {
+	Name       string  `json:"name"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// MiddlewareTrace per-middleware timing of one request
+type MiddlewareTrace struct {
+	Timestamp   time.Time          `json:"timestamp"`
+	Status      int                `json:"status"`
+	TotalMS     float64            `json:"total_ms"`
+	Middlewares []MiddlewareTiming `json:"middlewares"`
+}
+
+// MiddlewareChainReport response of the middleware debug endpoint
+type MiddlewareChainReport struct {
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	FuncName    string                 `json:"func_name"`
+	Middlewares []MiddlewareChainEntry `json:"middlewares"`
+	Timing      bool                   `json:"timing_enabled"`
+	Recent      []MiddlewareTrace      `json:"recent,omitempty"`
+}
+
+var (
+	middlewareTraces        = make(map[string][]MiddlewareTrace)
+	middlewareTracesMutex   sync.RWMutex
+	middlewareTimingSamples = DefaultMiddlewareTimingSamples
+)
+
+// SetMiddlewareTimingSamples sets how many recent requests are kept per route (<= 0 restores the default)
+func SetMiddlewareTimingSamples(samples int) {
+	if samples <= 0 {
+		samples = DefaultMiddlewareTimingSamples
+	}
+	middlewareTracesMutex.Lock()
+	middlewareTimingSamples = samples
+	middlewareTracesMutex.Unlock()
+}
+
+// routeKey identifies a route in the timing store
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// middlewareChain returns the ordered middleware chain of a route, with resolved arguments
+func middlewareChain(route *RouteEntry) []MiddlewareChainEntry {
+	// Generated routes carry one MiddlewareInfo per middleware; manual registrations fall back to function names
+	described := len(route.MiddlewareInfo) == len(route.Middlewares)
+
+	chain := make([]MiddlewareChainEntry, 0, len(route.Middlewares))
+	for i, middleware := range route.Middlewares {
+		entry := MiddlewareChainEntry{Order: i + 1, Name: handlerName(middleware)}
+		if described {
+			info := route.MiddlewareInfo[i]
+			entry.Name = info.Name
+			entry.Args = info.Args
+			entry.Description = info.Description
+		}
+		chain = append(chain, entry)
+	}
+	return chain
+}
+
+// handlerName returns the function name of a handler
+func handlerName(handler gin.HandlerFunc) string {
+	if handler == nil {
+		return "unknown"
+	}
+	if fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
+
+// middlewareTimingKey context key of the per-request timing
+const middlewareTimingKey = "deco.middlewareTiming"
+
+// requestTiming start/end of each handler of the chain for one request
+type requestTiming struct {
+	starts []time.Time
+	ends   []time.Time
+}
+
+// instrumentRoute wraps the route chain so each middleware and the handler are timed
+func instrumentRoute(route *RouteEntry) []gin.HandlerFunc {
+	names := make([]string, 0, len(route.Middlewares)+1)
+	for _, entry := range middlewareChain(route) {
+		names = append(names, entry.Name)
+	}
+	names = append(names, "handler")
+
+	handlers := make([]gin.HandlerFunc, 0, len(names))
+	handlers = append(handlers, route.Middlewares...)
+	handlers = append(handlers, route.Handler)
+
+	key := routeKey(route.Method, route.Path)
+	wrapped := make([]gin.HandlerFunc, len(handlers))
+	for i, handler := range handlers {
+		wrapped[i] = timedHandler(i, handler)
+	}
+
+	// The first wrapper owns the per-request state and records the trace once the chain finished
+	first := wrapped[0]
+	wrapped[0] = func(c *gin.Context) {
+		current := &requestTiming{starts: make([]time.Time, len(handlers)), ends: make([]time.Time, len(handlers))}
+		c.Set(middlewareTimingKey, current)
+		first(c)
+		c.Next()
+		recordMiddlewareTrace(key, names, current, c.Writer.Status())
+	}
+
+	return wrapped
+}
+
+// timedHandler records the start and end of a handler in the request timing
+func timedHandler(index int, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(middlewareTimingKey)
+		timing, ok := value.(*requestTiming)
+		if !ok || index >= len(timing.starts) {
+			handler(c)
+			return
+		}
+		timing.starts[index] = time.Now()
+		handler(c)
+		timing.ends[index] = time.Now()
+	}
+}
+
+// recordMiddlewareTrace converts handler windows into self times and stores the trace
+func recordMiddlewareTrace(key string, names []string, timing *requestTiming, status int) {
+	trace := MiddlewareTrace{Timestamp: timing.starts[0], Status: status}
+	if !timing.ends[0].IsZero() {
+		var last time.Time
+		for _, end := range timing.ends {
+			if end.After(last) {
+				last = end
+			}
+		}
+		trace.TotalMS = durationMS(last.Sub(timing.starts[0]))
+	}
+
+	for i, name := range names {
+		if timing.starts[i].IsZero() {
+			break // chain aborted before this handler
+		}
+		trace.Middlewares = append(trace.Middlewares, MiddlewareTiming{Name: name, DurationMS: durationMS(selfDuration(timing, i))})
+	}
+
+	middlewareTracesMutex.Lock()
+	defer middlewareTracesMutex.Unlock()
+	traces := append(middlewareTraces[key], trace)
+	if len(traces) > middlewareTimingSamples {
+		traces = traces[len(traces)-middlewareTimingSamples:]
+	}
+	middlewareTraces[key] = traces
+}
+
+// selfDuration subtracts the handlers that ran nested inside handler i (via c.Next) from its window
+func selfDuration(timing *requestTiming, i int) time.Duration {
+	self := timing.ends[i].Sub(timing.starts[i])
+	var childEnd time.Time
+	for j := i + 1; j < len(timing.starts) && !timing.starts[j].IsZero(); j++ {
+		if !timing.starts[j].Before(timing.ends[i]) {
+			break
+		}
+		if timing.starts[j].Before(childEnd) {
+			continue // nested deeper, already part of a direct child
+		}
+		self -= timing.ends[j].Sub(timing.starts[j])
+		childEnd = timing.ends[j]
+	}
+	if self < 0 {
+		return 0
+	}
+	return self
+}
+
+// durationMS converts a duration to milliseconds with microsecond precision
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// recentMiddlewareTraces returns a copy of the stored traces of a route
+func recentMiddlewareTraces(method, path string) []MiddlewareTrace {
+	middlewareTracesMutex.RLock()
+	defer middlewareTracesMutex.RUnlock()
+	traces := middlewareTraces[routeKey(method, path)]
+	return append([]MiddlewareTrace(nil), traces...)
+}
+
+// MiddlewareChainHandler describes the middleware chain of ?method=&path= (path as registered, e.g. /users/:id)
+func MiddlewareChainHandler(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'path' is required"})
+		return
+	}
+	method := strings.ToUpper(c.DefaultQuery("method", http.MethodGet))
+
+	for _, route := range GetRoutes() {
+		if route.Method != method || route.Path != path {
+			continue
+		}
+		c.JSON(http.StatusOK, MiddlewareChainReport{
+			Method:      route.Method,
+			Path:        route.Path,
+			FuncName:    route.FuncName,
+			Middlewares: middlewareChain(&route),
+			Timing:      gin.IsDebugging(),
+			Recent:      recentMiddlewareTraces(route.Method, route.Path),
+		})
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "route not found", "method": method, "path": path})
+}
@@ -0,0 +1,128 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func sleepingMiddleware(delay time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		time.Sleep(delay)
+		c.Next()
+	}
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	route := &RouteEntry{
+		Method:      "GET",
+		Path:        "/users/:id",
+		Handler:     func(c *gin.Context) {},
+		Middlewares: []gin.HandlerFunc{sleepingMiddleware(0), sleepingMiddleware(0)},
+		MiddlewareInfo: []MiddlewareInfo{
+			{Name: "Auth", Args: map[string]interface{}{"role": "admin"}},
+			{Name: "Cache", Args: map[string]interface{}{"ttl": "5m"}},
+		},
+	}
+
+	chain := middlewareChain(route)
+	assert.Equal(t, []MiddlewareChainEntry{
+		{Order: 1, Name: "Auth", Args: map[string]interface{}{"role": "admin"}},
+		{Order: 2, Name: "Cache", Args: map[string]interface{}{"ttl": "5m"}},
+	}, chain)
+
+	// Without matching metadata the function names are reported
+	route.MiddlewareInfo = nil
+	chain = middlewareChain(route)
+	assert.Contains(t, chain[0].Name, "sleepingMiddleware")
+}
+
+func TestInstrumentRoute(t *testing.T) {
+	route := &RouteEntry{
+		Method:  "GET",
+		Path:    "/instrumented",
+		Handler: func(c *gin.Context) { time.Sleep(2 * time.Millisecond); c.Status(http.StatusNoContent) },
+		Middlewares: []gin.HandlerFunc{
+			sleepingMiddleware(5 * time.Millisecond),
+			func(c *gin.Context) { time.Sleep(time.Millisecond) }, // relies on gin to continue the chain
+		},
+		MiddlewareInfo: []MiddlewareInfo{{Name: "Slow"}, {Name: "Fast"}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(route.Path, instrumentRoute(route)...)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instrumented", http.NoBody))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	traces := recentMiddlewareTraces("GET", "/instrumented")
+	if assert.Len(t, traces, 1) {
+		trace := traces[0]
+		assert.Equal(t, http.StatusNoContent, trace.Status)
+		if assert.Len(t, trace.Middlewares, 3) {
+			assert.Equal(t, "Slow", trace.Middlewares[0].Name)
+			assert.Equal(t, "handler", trace.Middlewares[2].Name)
+			// Self time excludes the nested handlers
+			assert.GreaterOrEqual(t, trace.Middlewares[0].DurationMS, 5.0)
+			assert.Less(t, trace.Middlewares[0].DurationMS, trace.TotalMS)
+			assert.GreaterOrEqual(t, trace.Middlewares[2].DurationMS, 2.0)
+		}
+		assert.GreaterOrEqual(t, trace.TotalMS, 8.0)
+	}
+}
+
+func TestMiddlewareTraceSamples(t *testing.T) {
+	SetMiddlewareTimingSamples(2)
+	defer SetMiddlewareTimingSamples(0)
+
+	route := &RouteEntry{Method: "GET", Path: "/sampled", Handler: func(c *gin.Context) {}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(route.Path, instrumentRoute(route)...)
+
+	for i := 0; i < 5; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sampled", http.NoBody))
+	}
+	assert.Len(t, recentMiddlewareTraces("GET", "/sampled"), 2)
+}
+
+func TestMiddlewareChainHandler(t *testing.T) {
+	RegisterRouteWithMeta(&RouteEntry{
+		Method:         "POST",
+		Path:           "/debug-chain/:id",
+		Handler:        func(c *gin.Context) {},
+		Middlewares:    []gin.HandlerFunc{sleepingMiddleware(0)},
+		MiddlewareInfo: []MiddlewareInfo{{Name: "RateLimit", Args: map[string]interface{}{"limit": "10"}}},
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(MiddlewareDebugPath, MiddlewareChainHandler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MiddlewareDebugPath+"?method=post&path=/debug-chain/:id", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report MiddlewareChainReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "POST", report.Method)
+	if assert.Len(t, report.Middlewares, 1) {
+		assert.Equal(t, "RateLimit", report.Middlewares[0].Name)
+		assert.Equal(t, "10", report.Middlewares[0].Args["limit"])
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MiddlewareDebugPath+"?path=/missing", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MiddlewareDebugPath, http.NoBody))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
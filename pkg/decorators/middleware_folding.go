@@ -0,0 +1,122 @@
+package decorators
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// goStringLiteral string literals of generated calls, ignored when looking for references to time
+var goStringLiteral = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// durationUnits units used to write durations as Go literals, largest first
+var durationUnits = []struct {
+	unit time.Duration
+	name string
+}{
+	{time.Hour, "time.Hour"},
+	{time.Minute, "time.Minute"},
+	{time.Second, "time.Second"},
+	{time.Millisecond, "time.Millisecond"},
+	{time.Microsecond, "time.Microsecond"},
+}
+
+// goDurationLiteral writes d as Go source, e.g. 90 * time.Second
+func goDurationLiteral(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	for _, unit := range durationUnits {
+		if d%unit.unit != 0 {
+			continue
+		}
+		if d == unit.unit {
+			return unit.name
+		}
+		return fmt.Sprintf("%d * %s", d/unit.unit, unit.name)
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// foldMiddlewareCall generates a typed constructor call for markers whose arguments can be parsed at
+// generation time, so the generated code does not parse argument strings when the engine starts.
+// Arguments that do not parse keep the string wrapper, which reports them at runtime as before.
+func foldMiddlewareCall(marker MarkerInstance) (string, bool) {
+	switch marker.Name {
+	case "Cache":
+		options := parseCacheOptions(marker.Args)
+		fields := []string{
+			"TTL: " + goDurationLiteral(options.TTL),
+			fmt.Sprintf("Backend: %q", options.Backend),
+			fmt.Sprintf("Key: %q", options.Key),
+		}
+		if options.Encrypt {
+			fields = append(fields, "Encrypt: true")
+		}
+		if options.EncryptionKey != "" {
+			fields = append(fields, fmt.Sprintf("EncryptionKey: %q", options.EncryptionKey))
+		}
+		if len(options.Vary) > 0 {
+			fields = append(fields, fmt.Sprintf("Vary: %#v", options.Vary))
+		}
+		if options.MaxKeys > 0 {
+			fields = append(fields, fmt.Sprintf("MaxKeys: %d", options.MaxKeys))
+		}
+		if options.ETag != "" {
+			fields = append(fields, fmt.Sprintf("ETag: %q", options.ETag))
+		}
+		return fmt.Sprintf("deco.CacheWith(deco.CacheOptions{%s})", strings.Join(fields, ", ")), true
+
+	case "RateLimit":
+		options := parseRateLimitOptions(marker.Args)
+		fields := []string{fmt.Sprintf("Limit: %d, Window: %s", options.Limit, goDurationLiteral(options.Window))}
+		// Backend, algorithm and burst left out are resolved from rate_limit at runtime
+		if options.Backend != "" {
+			fields = append(fields, fmt.Sprintf("Backend: %q", options.Backend))
+		}
+		fields = append(fields, fmt.Sprintf("Key: %q", options.Key))
+		if options.Algorithm != "" {
+			fields = append(fields, fmt.Sprintf("Algorithm: %q", options.Algorithm))
+		}
+		if options.Burst != 0 {
+			fields = append(fields, fmt.Sprintf("Burst: %d", options.Burst))
+		}
+		return fmt.Sprintf("deco.RateLimitWith(deco.RateLimitOptions{%s})", strings.Join(fields, ", ")), true
+
+	case "MaxResponseSize":
+		config, err := parseMaxResponseSizeArgs(marker.Args)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("deco.MaxResponseSizeMiddleware(deco.ResponseSizeConfig{MaxBytes: %d, Action: %q})", config.MaxBytes, config.Action), true
+
+	case "MaxBodySize":
+		limit, err := parseMaxBodySizeArgs(marker.Args)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("deco.MaxBodySizeMiddleware(%d)", limit), true
+
+	case "SlowThreshold":
+		threshold, err := parseSlowThresholdArgs(marker.Args)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("deco.SlowThresholdMiddleware(%s)", goDurationLiteral(threshold)), true
+	}
+
+	return "", false
+}
+
+// middlewareCallImports imports required by the folded middleware calls of the routes
+func middlewareCallImports(routes []*RouteMeta) []string {
+	for _, route := range routes {
+		for _, call := range route.MiddlewareCalls {
+			if strings.Contains(goStringLiteral.ReplaceAllString(call, `""`), "time.") {
+				return []string{`"time"`}
+			}
+		}
+	}
+	return nil
+}
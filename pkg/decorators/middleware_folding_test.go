@@ -0,0 +1,97 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoDurationLiteral(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                      "0",
+		time.Minute:            "time.Minute",
+		5 * time.Minute:        "5 * time.Minute",
+		90 * time.Second:       "90 * time.Second",
+		800 * time.Millisecond: "800 * time.Millisecond",
+		36 * time.Hour:         "36 * time.Hour",
+		1500 * time.Nanosecond: "time.Duration(1500)",
+		250 * time.Microsecond: "250 * time.Microsecond",
+	}
+	for duration, expected := range cases {
+		assert.Equal(t, expected, goDurationLiteral(duration), duration.String())
+	}
+}
+
+func TestFoldMiddlewareCall(t *testing.T) {
+	cases := []struct {
+		marker   MarkerInstance
+		expected string
+	}{
+		{
+			MarkerInstance{Name: "Cache"},
+			`deco.CacheWith(deco.CacheOptions{TTL: 5 * time.Minute, Backend: "memory", Key: "url"})`,
+		},
+		{
+			MarkerInstance{Name: "Cache", Args: []string{`duration="1h"`, "by=user", "backend=redis", "encrypt=true", "encryption_key=cache"}},
+			`deco.CacheWith(deco.CacheOptions{TTL: time.Hour, Backend: "redis", Key: "user", Encrypt: true, EncryptionKey: "cache"})`,
+		},
+		{
+			MarkerInstance{Name: "RateLimit", Args: []string{"limit=10", "window=30s", "key=endpoint"}},
+			`deco.RateLimitWith(deco.RateLimitOptions{Limit: 10, Window: 30 * time.Second, Key: "endpoint"})`,
+		},
+		{
+			MarkerInstance{Name: "RateLimit", Args: []string{"limit=10", "type=redis", "algorithm=token_bucket", "burst=20"}},
+			`deco.RateLimitWith(deco.RateLimitOptions{Limit: 10, Window: time.Minute, Backend: "redis", Key: "ip", Algorithm: "token_bucket", Burst: 20})`,
+		},
+		{
+			MarkerInstance{Name: "SlowThreshold", Args: []string{`"800ms"`}},
+			`deco.SlowThresholdMiddleware(800 * time.Millisecond)`,
+		},
+		{
+			MarkerInstance{Name: "MaxResponseSize", Args: []string{"1KB", "action=log"}},
+			`deco.MaxResponseSizeMiddleware(deco.ResponseSizeConfig{MaxBytes: 1024, Action: "log"})`,
+		},
+	}
+	for _, tc := range cases {
+		call, ok := foldMiddlewareCall(tc.marker)
+		assert.True(t, ok, tc.marker.Name)
+		assert.Equal(t, tc.expected, call)
+	}
+
+	// Arguments that do not parse keep the string wrapper
+	assert.Equal(t, `deco.CreateSlowThresholdMiddleware("soon")`, generateMiddlewareCall(MarkerInstance{Name: "SlowThreshold", Args: []string{"soon"}}))
+	_, ok := foldMiddlewareCall(MarkerInstance{Name: "Auth", Args: []string{"role=admin"}})
+	assert.False(t, ok)
+}
+
+func TestMiddlewareCallImports(t *testing.T) {
+	routes := []*RouteMeta{{MiddlewareCalls: []string{`deco.CreateMockMiddleware("body=\"time.Now\"")`}}}
+	assert.Empty(t, middlewareCallImports(routes))
+
+	routes = append(routes, &RouteMeta{MiddlewareCalls: []string{`deco.SlowThresholdMiddleware(2 * time.Second)`}})
+	assert.Equal(t, []string{`"time"`}, middlewareCallImports(routes))
+}
+
+func TestFoldedRateLimitMatchesWrapper(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	args := []string{"limit=1", "window=1m"}
+	for name, middleware := range map[string]gin.HandlerFunc{
+		"wrapper": createRateLimitMiddlewareInternal(args),
+		"folded":  RateLimitWith(RateLimitOptions{Limit: 1, Window: time.Minute, Backend: "memory", Key: "ip"}),
+	} {
+		router := gin.New()
+		router.GET("/limited", middleware, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		codes := make([]int, 2)
+		for i := range codes {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/limited", http.NoBody))
+			codes[i] = w.Code
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes, name)
+	}
+}
@@ -0,0 +1,116 @@
+package decorators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultMiddlewareOrder relative order of markers in the chain, whatever their order in the comment:
+// authentication rejects a request before the rate limit counts it, the cache only answers requests
+// that passed both and, running inside compression, stores uncompressed bodies that every client can
+// be served; @Log also sees uncompressed bodies and logs cache hits, and @Envelope wraps the cached
+// bodies as well as fresh ones. generation.middleware_order replaces it, @MiddlewareOrder overrides it
+// per route.
+var DefaultMiddlewareOrder = []string{"Auth", "RateLimit", "Compress", "Log", "Envelope", "Cache"}
+
+// parseMiddlewareOrderArgs parses @MiddlewareOrder(Auth, RateLimit, Cache)
+func parseMiddlewareOrderArgs(args []string) ([]string, error) {
+	var order []string
+	for _, arg := range args {
+		name := strings.TrimPrefix(MarkerValue(arg), "@")
+		if name == "" {
+			continue
+		}
+		if _, ok := GetMarker(name); !ok {
+			return nil, fmt.Errorf("@MiddlewareOrder: unknown marker '%s'", name)
+		}
+		if contains(order, name) {
+			return nil, fmt.Errorf("@MiddlewareOrder: marker '%s' is listed twice", name)
+		}
+		order = append(order, name)
+	}
+	if len(order) < 2 {
+		return nil, fmt.Errorf("@MiddlewareOrder needs at least two markers, e.g. @MiddlewareOrder(Auth, Cache)")
+	}
+	return order, nil
+}
+
+// validateMiddlewareOrder checks generation.middleware_order
+func validateMiddlewareOrder(order []string) error {
+	seen := make(map[string]bool, len(order))
+	for _, name := range order {
+		name = strings.TrimPrefix(name, "@")
+		if name == "" {
+			return fmt.Errorf("generation.middleware_order: empty marker name")
+		}
+		if seen[name] {
+			return fmt.Errorf("generation.middleware_order: marker '%s' is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// applyMiddlewareOrder sorts the middlewares of each route by the configured order, or DefaultMiddlewareOrder
+func applyMiddlewareOrder(routes []*RouteMeta, order []string) {
+	if len(order) == 0 {
+		order = DefaultMiddlewareOrder
+	}
+	for _, route := range routes {
+		routeOrder := order
+		for _, marker := range route.Markers {
+			if marker.Name != "MiddlewareOrder" {
+				continue
+			}
+			// Arguments were validated during parsing
+			if custom, err := parseMiddlewareOrderArgs(marker.Args); err == nil {
+				routeOrder = custom
+			}
+		}
+		orderRouteMiddlewares(route, routeOrder)
+	}
+}
+
+// orderRouteMiddlewares reorders the listed markers among the positions they take in the chain; markers
+// that are not listed keep their position. MiddlewareInfo stays aligned with the chain: @Provide entries,
+// then one entry per middleware call.
+func orderRouteMiddlewares(route *RouteMeta, order []string) {
+	providers := min(len(route.Providers), len(route.MiddlewareInfo))
+	info := route.MiddlewareInfo[providers:]
+	if len(info) != len(route.MiddlewareCalls) {
+		LogVerbose("⚠️  %s: middleware chain and documentation differ, order left as declared", route.FuncName)
+		return
+	}
+
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[strings.TrimPrefix(name, "@")] = i
+	}
+
+	var slots []int
+	for i, middleware := range info {
+		if _, ok := rank[middleware.Name]; ok {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) < 2 {
+		return
+	}
+
+	type entry struct {
+		call string
+		info MiddlewareInfo
+	}
+	entries := make([]entry, len(slots))
+	for i, slot := range slots {
+		entries[i] = entry{call: route.MiddlewareCalls[slot], info: info[slot]}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return rank[entries[i].info.Name] < rank[entries[j].info.Name]
+	})
+	for i, slot := range slots {
+		route.MiddlewareCalls[slot] = entries[i].call
+		info[slot] = entries[i].info
+	}
+}
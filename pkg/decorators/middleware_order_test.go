@@ -0,0 +1,102 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const middlewareOrderSource = `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/reports")
+// @Cache(ttl=1m)
+// @SlowThreshold(threshold=2s)
+// @RateLimit(limit=10, window=1m)
+// @Auth(role=admin)
+// @Tag("reports")
+func GetReports(c *gin.Context) {}
+`
+
+func TestExtractMarkers_SourceOrder(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reports.go"), []byte(middlewareOrderSource), 0o600))
+
+	// The registry is a map: every parse must still return the markers as written
+	for i := 0; i < 20; i++ {
+		routes, err := ParseDirectory(dir)
+		require.NoError(t, err)
+		require.Len(t, routes, 1)
+		names := make([]string, 0, len(routes[0].Markers))
+		for _, marker := range routes[0].Markers {
+			names = append(names, marker.Name)
+		}
+		require.Equal(t, []string{"Cache", "SlowThreshold", "RateLimit", "Auth", "Tag"}, names)
+	}
+}
+
+func TestOrderRouteMiddlewares(t *testing.T) {
+	route := &RouteMeta{
+		Providers:       []ProviderBinding{{Name: "db"}},
+		MiddlewareCalls: []string{"cache()", "slow()", "auth()", "ratelimit()"},
+		MiddlewareInfo:  []MiddlewareInfo{{Name: "Provide"}, {Name: "Cache"}, {Name: "SlowThreshold"}, {Name: "Auth"}, {Name: "RateLimit"}},
+	}
+	orderRouteMiddlewares(route, DefaultMiddlewareOrder)
+	assert.Equal(t, []string{"auth()", "slow()", "ratelimit()", "cache()"}, route.MiddlewareCalls, "unlisted markers keep their position")
+	assert.Equal(t, []MiddlewareInfo{{Name: "Provide"}, {Name: "Auth"}, {Name: "SlowThreshold"}, {Name: "RateLimit"}, {Name: "Cache"}}, route.MiddlewareInfo)
+}
+
+func TestParseMiddlewareOrderArgs(t *testing.T) {
+	order, err := parseMiddlewareOrderArgs([]string{"Cache", "@Auth"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cache", "Auth"}, order)
+
+	for _, args := range [][]string{{"Cache"}, {"Cache", "Cache"}, {"Cache", "Memoize"}} {
+		_, err := parseMiddlewareOrderArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+
+	assert.NoError(t, validateMiddlewareOrder([]string{"Auth", "Cache"}))
+	assert.ErrorContains(t, validateMiddlewareOrder([]string{"Auth", "@Auth"}), "listed twice")
+}
+
+func TestGenerateInitFile_MiddlewareOrder(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath := filepath.Join(dir, "reports.go")
+	require.NoError(t, os.WriteFile(sourcePath, []byte(middlewareOrderSource), 0o600))
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	chain := func(config *Config) []string {
+		require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", config))
+		generated, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		var calls []string
+		for _, line := range strings.Split(string(generated), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "deco.") {
+				calls = append(calls, line[len("deco."):strings.Index(line, "(")])
+			}
+		}
+		return calls
+	}
+
+	assert.Equal(t, []string{"CreateAuthMiddleware", "SlowThresholdMiddleware", "RateLimitWith", "CacheWith"}, chain(DefaultConfig()))
+
+	config := DefaultConfig()
+	config.Generate.MiddlewareOrder = []string{"SlowThreshold", "Cache", "Auth"}
+	assert.Equal(t, []string{"SlowThresholdMiddleware", "CacheWith", "RateLimitWith", "CreateAuthMiddleware"}, chain(config))
+
+	// @MiddlewareOrder wins over the configuration
+	source := strings.Replace(middlewareOrderSource, `// @Tag("reports")`, `// @MiddlewareOrder(RateLimit, Auth)`, 1)
+	require.NoError(t, os.WriteFile(sourcePath, []byte(source), 0o600))
+	assert.Equal(t, []string{"CacheWith", "SlowThresholdMiddleware", "RateLimitWith", "CreateAuthMiddleware"}, chain(config))
+}
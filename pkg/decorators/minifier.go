@@ -220,22 +220,40 @@ import ("github.com/gin-gonic/gin"
 {{- end }}
 )
 func init() {
+{{- range .Controllers }}
+{{ .Var }}:={{ .Value }}
+{{- end }}
 {{- range .Routes }}
-deco.RegisterRouteWithMeta(deco.RouteEntry{Method:"{{ .Method }}",Path:"{{ .Path }}",Handler:{{ if eq $.PackageName "deco" }}{{ .PackageName }}.{{ .FuncName }}{{ else }}{{ .FuncName }}{{ end }},
-{{- if .MiddlewareCalls }}
+{{- $route := . }}
+{{- range $version := routeVersions $route }}
+{{- with $route }}
+deco.RegisterRouteWithMeta(deco.RouteEntry{Method:"{{ .Method }}",Path:"{{ .Path }}",Handler:{{ if .TypedHandler }}decorators.Typed({{ end }}{{ $.Handler . }}{{ if .TypedHandler }}){{ end }},
+{{- if or .Providers .MiddlewareCalls }}
 Middlewares:[]gin.HandlerFunc{
+{{- range .Providers }}
+decorators.Provide("{{ .Name }}",{{ if $.ExternalHandlers }}{{ $route.PackageName }}.{{ end }}{{ .Factory }},{{ .Cache }}),
+{{- end }}
 {{- range .MiddlewareCalls }}
 {{ . }},
 {{- end }}
 },
 {{- end }}
 FuncName:"{{ .FuncName }}",PackageName:"{{ .PackageName }}",
+{{- if .OperationID }}
+OperationID:"{{ .OperationID }}",
+{{- end }}
 {{- if .Description }}
 Description:"{{ .Description }}",
 {{- end }}
 {{- if .Summary }}
 Summary:"{{ .Summary }}",
 {{- end }}
+{{- if .Deprecation }}
+Deprecation:&deco.DeprecationInfo{Message:"{{ .Deprecation.Message }}",Sunset:"{{ .Deprecation.Sunset }}",Link:"{{ .Deprecation.Link }}",Headers:{{ .Deprecation.Headers }}},
+{{- end }}
+{{- if $version }}
+Version:"{{ $version }}",
+{{- end }}
 {{- if .Tags }}
 Tags:[]string{
 {{- range .Tags }}
@@ -273,6 +291,8 @@ Responses:[]decorators.ResponseInfo{
 {{- end }}
 })
 {{- end }}
+{{- end }}
+{{- end }}
 }
 var GeneratedMetadata=map[string]interface{}{"routes_count":{{ len .Routes }},"generated_at":"{{ .GeneratedAt }}","package_name":"{{ .PackageName }}"}
 `
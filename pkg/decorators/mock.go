@@ -0,0 +1,231 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MockHeader response header flagging canned @Mock responses
+const MockHeader = "X-Deco-Mock"
+
+// MockConfig canned response returned by @Mock instead of the handler
+type MockConfig struct {
+	Status      int
+	Body        string
+	ContentType string
+	AllowProd   bool // keep the mock in binaries built with -tags prod
+}
+
+// mockArgs parsed @Mock arguments, before bodyFrom is resolved
+type mockArgs struct {
+	config   MockConfig
+	bodyFrom string
+}
+
+// parseMockArgs parses @Mock(status=200, bodyFrom="UserResponse.example"), body="...", contentType= and allowProd=
+func parseMockArgs(args []string) (mockArgs, error) {
+	parsed := mockArgs{config: MockConfig{Status: http.StatusOK}}
+	hasBody := false
+
+	for i, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			if i != 0 {
+				return parsed, fmt.Errorf("@Mock: unexpected argument '%s'", arg)
+			}
+			key, value = "status", key
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch key {
+		case "status":
+			status, err := strconv.Atoi(value)
+			if err != nil || status < 100 || status > 599 {
+				return parsed, fmt.Errorf("@Mock: invalid status '%s'", value)
+			}
+			parsed.config.Status = status
+		case "bodyFrom":
+			if value == "" {
+				return parsed, fmt.Errorf("@Mock: bodyFrom must name a schema, e.g. \"UserResponse.example\"")
+			}
+			parsed.bodyFrom = value
+		case "body":
+			parsed.config.Body = value
+			hasBody = true
+		case "contentType":
+			parsed.config.ContentType = value
+		case "allowProd":
+			allow, err := strconv.ParseBool(value)
+			if err != nil {
+				return parsed, fmt.Errorf("@Mock: invalid allowProd '%s'", value)
+			}
+			parsed.config.AllowProd = allow
+		default:
+			return parsed, fmt.Errorf("@Mock: unknown argument '%s' (valid: status, bodyFrom, body, contentType, allowProd)", key)
+		}
+	}
+
+	if hasBody && parsed.bodyFrom != "" {
+		return parsed, fmt.Errorf("@Mock: use either body or bodyFrom, not both")
+	}
+	return parsed, nil
+}
+
+// resolveMockConfig parses @Mock arguments and resolves bodyFrom against the registered schemas
+func resolveMockConfig(args []string) (MockConfig, error) {
+	parsed, err := parseMockArgs(args)
+	if err != nil {
+		return parsed.config, err
+	}
+
+	if parsed.bodyFrom != "" {
+		body, err := mockBodyFromSchema(parsed.bodyFrom)
+		if err != nil {
+			return parsed.config, err
+		}
+		parsed.config.Body = body
+		if parsed.config.ContentType == "" {
+			parsed.config.ContentType = "application/json"
+		}
+	}
+
+	return parsed.config, nil
+}
+
+// mockBodyFromSchema renders the example of a schema reference such as "UserResponse.example"
+func mockBodyFromSchema(reference string) (string, error) {
+	name, field, _ := strings.Cut(reference, ".")
+	if field != "" && field != "example" {
+		return "", fmt.Errorf("@Mock: bodyFrom '%s' must reference a schema example, e.g. \"%s.example\"", reference, name)
+	}
+
+	schema := GetSchema(name)
+	if schema == nil {
+		return "", fmt.Errorf("@Mock: bodyFrom references unknown schema '%s' (missing @Schema?)", name)
+	}
+
+	body, err := json.Marshal(schemaExample(schema, map[string]bool{}))
+	if err != nil {
+		return "", fmt.Errorf("@Mock: error rendering example of '%s': %v", name, err)
+	}
+	return string(body), nil
+}
+
+// schemaExample builds an example object from the property examples of a schema.
+// Properties without an example get a zero value of their type; nested schemas are expanded once.
+func schemaExample(schema *SchemaInfo, seen map[string]bool) interface{} {
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if seen[schema.Name] {
+		return map[string]interface{}{}
+	}
+	seen[schema.Name] = true
+	defer delete(seen, schema.Name)
+
+	example := make(map[string]interface{}, len(schema.Properties))
+	for name, property := range schema.Properties {
+		example[name] = propertyExample(property, seen)
+	}
+	return example
+}
+
+// propertyExample returns the example of a property, expanding nested schemas
+func propertyExample(property *PropertyInfo, seen map[string]bool) interface{} {
+	if property.Example != nil {
+		return property.Example
+	}
+
+	switch property.Type {
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		if property.Items == nil {
+			return []interface{}{}
+		}
+		itemType := property.Items.Name
+		if ref := strings.TrimPrefix(property.Items.Ref, "#/components/schemas/"); ref != property.Items.Ref {
+			itemType = ref
+		}
+		if nested := GetSchema(itemType); nested != nil {
+			return []interface{}{schemaExample(nested, seen)}
+		}
+		return []interface{}{}
+	}
+
+	if nested := GetSchema(strings.TrimPrefix(property.GoType, "*")); nested != nil {
+		return schemaExample(nested, seen)
+	}
+	return map[string]interface{}{}
+}
+
+// MockMiddleware returns the canned response and skips the handler.
+// Binaries built with -tags prod run the real handler unless AllowProd is set.
+func MockMiddleware(config MockConfig) gin.HandlerFunc {
+	if prodBuild && !config.AllowProd {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	if config.Status == 0 {
+		config.Status = http.StatusOK
+	}
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return func(c *gin.Context) {
+		c.Header(MockHeader, "true")
+		if config.Body == "" {
+			c.AbortWithStatus(config.Status)
+			return
+		}
+		c.Data(config.Status, contentType, []byte(config.Body))
+		c.Abort()
+	}
+}
+
+// generateMockCall generates the @Mock middleware call with the resolved body embedded
+func generateMockCall(marker MarkerInstance) string {
+	config, err := resolveMockConfig(marker.Args)
+	if err != nil {
+		// Reported by processMiddlewares; keep the generated code compilable
+		return `deco.MockMiddleware(deco.MockConfig{})`
+	}
+	return fmt.Sprintf(`deco.MockMiddleware(deco.MockConfig{Status: %d, Body: %q, ContentType: %q, AllowProd: %t})`,
+		config.Status, config.Body, config.ContentType, config.AllowProd)
+}
+
+// createMockMiddleware creates @Mock middleware from raw arguments (bodyFrom needs the schemas to be registered)
+func createMockMiddleware(args []string) gin.HandlerFunc {
+	config, err := resolveMockConfig(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return MockMiddleware(config)
+}
+
+// validateMockMarkers resolves every @Mock of a route so invalid ones fail generation
+func validateMockMarkers(route *RouteMeta) error {
+	for _, marker := range route.Markers {
+		if marker.Name != "Mock" {
+			continue
+		}
+		if _, err := resolveMockConfig(marker.Args); err != nil {
+			return err
+		}
+		LogNormal("🎭 %s %s returns a @Mock response (handler %s is not called)", route.Method, route.Path, route.FuncName)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MockServerOptions options of NewMockServer
+type MockServerOptions struct {
+	Seed int64 // seed of the values generated from the schemas; the same seed gives the same bodies
+	CORS bool  // allow every origin and answer preflight requests, for frontends served from elsewhere
+}
+
+// mockServer serves example responses for the operations of a spec
+type mockServer struct {
+	spec    *OpenAPISpec
+	options MockServerOptions
+
+	mu        sync.Mutex // the payload generator is not safe for concurrent use
+	generator *SpecPayloadGenerator
+}
+
+// NewMockServer returns a handler answering the operations of spec with example responses, for frontends
+// developed before the handlers are implemented (deco mock). The response is the first 2xx one declared,
+// or the one of the status asked with "Prefer: code=404"; its body is the example of @Response(example=...)
+// ("Prefer: example=name" picks a named one), or a value generated from its schema. Path parameters fill the
+// top-level properties of the same name. Responses carry the X-Deco-Mock header; unknown paths get 404 and
+// unknown methods of a known path 405.
+func NewMockServer(spec *OpenAPISpec, options MockServerOptions) http.Handler {
+	return &mockServer{spec: spec, options: options, generator: NewSpecPayloadGenerator(spec, options.Seed)}
+}
+
+// ServeHTTP answers a request with the example response of its operation
+func (s *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.options.CORS {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(s.allowedMethods(r.URL.Path), ", "))
+			if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	w.Header().Set(MockHeader, "true")
+
+	match, ok := MatchSpecOperation(s.spec, r.Method, r.URL.Path)
+	if !ok && r.Method == http.MethodHead {
+		// HEAD is answered as the GET of the path, without the body
+		match, ok = MatchSpecOperation(s.spec, http.MethodGet, r.URL.Path)
+	}
+	if !ok {
+		if allowed := s.allowedMethods(r.URL.Path); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeMockError(w, http.StatusMethodNotAllowed, "method_not_allowed", fmt.Sprintf("%s is not declared for %s", r.Method, r.URL.Path))
+			return
+		}
+		writeMockError(w, http.StatusNotFound, "not_found", fmt.Sprintf("no operation matches %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	preferences := parsePrefer(r.Header.Get("Prefer"))
+	status, response := mockResponse(match.Operation, preferences["code"])
+	if response == nil || len(response.Content) == 0 || r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+
+	contentType := mockContentType(response.Content)
+	media := response.Content[contentType]
+	body, err := s.mockBody(media, preferences["example"], contentType, match.PathParams)
+	if err != nil {
+		writeMockError(w, http.StatusInternalServerError, "invalid_example", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// allowedMethods methods declared for a concrete path, HEAD included with GET
+func (s *mockServer) allowedMethods(path string) []string {
+	var allowed []string
+	for _, method := range []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+		if _, ok := MatchSpecOperation(s.spec, method, path); ok {
+			allowed = append(allowed, method)
+		} else if method == http.MethodHead {
+			if _, ok := MatchSpecOperation(s.spec, http.MethodGet, path); ok {
+				allowed = append(allowed, method)
+			}
+		}
+	}
+	return allowed
+}
+
+// mockResponse status and response of an operation: the one of code when given, else the first 2xx, else
+// the first declared. A code the operation does not declare uses its default response, if any.
+func mockResponse(operation *OpenAPIOperation, code string) (int, *OpenAPIResponse) {
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err == nil && status >= 100 && status <= 599 {
+			if response, ok := operation.Responses[code]; ok {
+				return status, &response
+			}
+			if response, ok := operation.Responses["default"]; ok {
+				return status, &response
+			}
+			return status, nil
+		}
+	}
+
+	codes := make([]string, 0, len(operation.Responses))
+	for declared := range operation.Responses {
+		if _, err := strconv.Atoi(declared); err == nil {
+			codes = append(codes, declared)
+		}
+	}
+	sort.Strings(codes)
+	for _, declared := range codes {
+		if strings.HasPrefix(declared, "2") {
+			status, _ := strconv.Atoi(declared)
+			response := operation.Responses[declared]
+			return status, &response
+		}
+	}
+	if len(codes) > 0 {
+		status, _ := strconv.Atoi(codes[0])
+		response := operation.Responses[codes[0]]
+		return status, &response
+	}
+	if response, ok := operation.Responses["default"]; ok {
+		return http.StatusOK, &response
+	}
+	return http.StatusOK, nil
+}
+
+// mockContentType media type of the response body: JSON when declared, else the first one
+func mockContentType(content map[string]MediaType) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	return types[0]
+}
+
+// mockBody serializes the example of a media type — the named one when asked, else its example, else a
+// value generated from its schema — with the path parameters set on it
+func (s *mockServer) mockBody(media MediaType, exampleName, contentType string, pathParams map[string]string) ([]byte, error) {
+	var value interface{}
+	switch {
+	case exampleName != "" && media.Examples[exampleName].Value != nil:
+		value = media.Examples[exampleName].Value
+	case media.Example != nil:
+		value = media.Example
+	case len(media.Examples) > 0:
+		names := make([]string, 0, len(media.Examples))
+		for name := range media.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		value = media.Examples[names[0]].Value
+	default:
+		s.mu.Lock()
+		value = s.generator.ResponseValue(media.Schema, "")
+		s.mu.Unlock()
+	}
+
+	// @Response(example=...) holds the example as written, JSON or plain text
+	if text, ok := value.(string); ok {
+		if !strings.Contains(contentType, "json") {
+			return []byte(text), nil
+		}
+		var decoded interface{}
+		if json.Unmarshal([]byte(text), &decoded) == nil {
+			value = decoded
+		}
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		value = withPathParams(object, pathParams)
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("example is not serializable: %v", err)
+	}
+	return body, nil
+}
+
+// withPathParams returns a copy of object whose properties named after a path parameter hold its value,
+// as a number when the property was one
+func withPathParams(object map[string]interface{}, pathParams map[string]string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		copied[key] = value
+	}
+	for name, raw := range pathParams {
+		current, exists := copied[name]
+		if !exists {
+			continue
+		}
+		copied[name] = raw
+		switch current.(type) {
+		case float64, int, int64:
+			if number, err := strconv.ParseFloat(raw, 64); err == nil {
+				copied[name] = number
+			}
+		}
+	}
+	return copied
+}
+
+// parsePrefer reads the preferences of a Prefer header ("code=404, example=empty")
+func parsePrefer(header string) map[string]string {
+	preferences := make(map[string]string)
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		preferences[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return preferences
+}
+
+// writeMockError writes an error of the mock server itself
+func writeMockError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code, "message": message})
+}
@@ -0,0 +1,117 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTestSpec spec with an operation holding examples and one answered from its schema
+func mockTestSpec() *OpenAPISpec {
+	spec := payloadTestSpec()
+	spec.Paths = map[string]OpenAPIPath{
+		"/users/{id}": {
+			"get": {Responses: map[string]OpenAPIResponse{
+				"200": {Content: map[string]MediaType{"application/json": {
+					Schema: &OpenAPISchema{Ref: "#/components/schemas/User"},
+					Examples: map[string]Example{
+						"admin": {Value: `{"id": 1, "name": "Ana", "role": "admin"}`},
+					},
+				}}},
+				"404": {Content: map[string]MediaType{"application/json": {
+					Example: map[string]interface{}{"error": "not_found"},
+				}}},
+			}},
+			"delete": {Responses: map[string]OpenAPIResponse{"204": {Description: "Deleted"}}},
+		},
+		"/users": {
+			"post": {Responses: map[string]OpenAPIResponse{
+				"201": {Content: map[string]MediaType{"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/User"}}}},
+			}},
+		},
+	}
+	return spec
+}
+
+func serveMock(t *testing.T, handler http.Handler, method, target string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestMockServer_Examples(t *testing.T) {
+	server := NewMockServer(mockTestSpec(), MockServerOptions{Seed: 1})
+
+	w := serveMock(t, server, http.MethodGet, "/users/42", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(MockHeader))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var user map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Equal(t, "Ana", user["name"], "the example written in @Response is decoded")
+	assert.Equal(t, float64(42), user["id"], "path parameters fill the properties of the same name")
+
+	w = serveMock(t, server, http.MethodGet, "/users/7", map[string]string{"Prefer": "code=404"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error": "not_found"}`, w.Body.String())
+
+	w = serveMock(t, server, http.MethodDelete, "/users/7", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = serveMock(t, server, http.MethodHead, "/users/7", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String(), "HEAD is answered as GET without the body")
+}
+
+func TestMockServer_GeneratedBody(t *testing.T) {
+	w := serveMock(t, NewMockServer(mockTestSpec(), MockServerOptions{Seed: 1}), http.MethodPost, "/users", nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var user map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Contains(t, user, "id", "read-only properties are part of responses")
+	assert.Contains(t, []interface{}{"admin", "viewer"}, user["role"])
+
+	again := serveMock(t, NewMockServer(mockTestSpec(), MockServerOptions{Seed: 1}), http.MethodPost, "/users", nil)
+	assert.Equal(t, w.Body.String(), again.Body.String(), "the seed fixes the bodies")
+}
+
+func TestMockServer_UnknownRoutes(t *testing.T) {
+	server := NewMockServer(mockTestSpec(), MockServerOptions{})
+
+	w := serveMock(t, server, http.MethodGet, "/orders", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"not_found"`)
+
+	w = serveMock(t, server, http.MethodPut, "/users/1", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, DELETE", w.Header().Get("Allow"))
+}
+
+func TestMockServer_CORS(t *testing.T) {
+	preflight := map[string]string{"Origin": "http://localhost:3000", "Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "Content-Type"}
+
+	w := serveMock(t, NewMockServer(mockTestSpec(), MockServerOptions{CORS: true}), http.MethodOptions, "/users", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+
+	w = serveMock(t, NewMockServer(mockTestSpec(), MockServerOptions{}), http.MethodOptions, "/users", preflight)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestParsePrefer(t *testing.T) {
+	assert.Equal(t, map[string]string{"code": "404", "example": "empty"}, parsePrefer(`code=404, example="empty"`))
+	assert.Empty(t, parsePrefer(""))
+}
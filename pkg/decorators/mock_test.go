@@ -0,0 +1,160 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func registerMockSchemas(t *testing.T) {
+	ClearSchemas()
+	t.Cleanup(ClearSchemas)
+
+	RegisterSchema(&SchemaInfo{
+		Name: "AddressResponse",
+		Type: "object",
+		Properties: map[string]*PropertyInfo{
+			"city": {Name: "city", Type: "string", Example: "Lisbon"},
+		},
+	})
+	RegisterSchema(&SchemaInfo{
+		Name: "UserResponse",
+		Type: "object",
+		Properties: map[string]*PropertyInfo{
+			"id":      {Name: "id", Type: "integer", Example: int64(42)},
+			"name":    {Name: "name", Type: "string", Example: "Ana"},
+			"active":  {Name: "active", Type: "boolean"},
+			"address": {Name: "address", Type: "object", GoType: "*AddressResponse"},
+			"tags":    {Name: "tags", Type: "array", Items: &PropertyInfo{Type: "string"}},
+		},
+	})
+}
+
+func TestParseMockArgs(t *testing.T) {
+	parsed, err := parseMockArgs([]string{"status=201", `bodyFrom="UserResponse.example"`})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, parsed.config.Status)
+	assert.Equal(t, "UserResponse.example", parsed.bodyFrom)
+
+	parsed, err = parseMockArgs([]string{"204"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, parsed.config.Status)
+
+	parsed, err = parseMockArgs([]string{"allowProd=true"})
+	assert.NoError(t, err)
+	assert.True(t, parsed.config.AllowProd)
+
+	for _, args := range [][]string{
+		{"status=abc"},
+		{"status=700"},
+		{"delay=1s"},
+		{"body=x", "bodyFrom=UserResponse"},
+		{"allowProd=maybe"},
+		{"status=200", "UserResponse"},
+	} {
+		_, err := parseMockArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestMockBodyFromSchema(t *testing.T) {
+	registerMockSchemas(t)
+
+	body, err := mockBodyFromSchema("UserResponse.example")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":42,"name":"Ana","active":false,"address":{"city":"Lisbon"},"tags":[]}`, body)
+
+	_, err = mockBodyFromSchema("MissingResponse.example")
+	assert.Error(t, err)
+
+	_, err = mockBodyFromSchema("UserResponse.name")
+	assert.Error(t, err)
+}
+
+func TestMockMiddleware(t *testing.T) {
+	if prodBuild {
+		t.Skip("mocks are disabled in prod builds")
+	}
+
+	handlerCalled := false
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id", MockMiddleware(MockConfig{Status: http.StatusAccepted, Body: `{"id":1}`}), func(c *gin.Context) {
+		handlerCalled = true
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody))
+
+	assert.False(t, handlerCalled)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(MockHeader))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestGenerateMockCall(t *testing.T) {
+	registerMockSchemas(t)
+
+	call := generateMiddlewareCall(MarkerInstance{Name: "Mock", Args: []string{"status=200", `bodyFrom="AddressResponse.example"`}})
+	assert.Equal(t, `deco.MockMiddleware(deco.MockConfig{Status: 200, Body: "{\"city\":\"Lisbon\"}", ContentType: "application/json", AllowProd: false})`, call)
+}
+
+func TestParseDirectory_MockFromSchemaExample(t *testing.T) {
+	ClearSchemas()
+	t.Cleanup(ClearSchemas)
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Schema()
+type UserResponse struct {
+	ID   int    ` + "`json:\"id\" example:\"7\"`" + `
+	Name string ` + "`json:\"name\" example:\"Ana\"`" + `
+}
+
+// @Route("GET", "/users/:id")
+// @Mock(status=200, bodyFrom="UserResponse.example")
+func GetUser(c *gin.Context) {}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, routes, 1) && assert.Len(t, routes[0].MiddlewareCalls, 1) {
+		assert.Contains(t, routes[0].MiddlewareCalls[0], `Body: "{\"id\":7,\"name\":\"Ana\"}"`)
+	}
+
+	// Unknown schemas fail parsing instead of generating an empty mock
+	source = `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/orders")
+// @Mock(bodyFrom="OrderResponse.example")
+func ListOrders(c *gin.Context) {}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+	_, err = ParseDirectory(dir)
+	assert.Error(t, err)
+}
+
+func TestOpenAPIFlagsMockedRoutes(t *testing.T) {
+	route := RouteEntry{
+		Method:         "GET",
+		Path:           "/mocked",
+		FuncName:       "Mocked",
+		MiddlewareInfo: []MiddlewareInfo{{Name: "Mock", Args: map[string]interface{}{"status": "200"}}},
+	}
+
+	operation := convertRouteToOperation(&route, &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{}})
+	assert.Contains(t, operation.Extensions, "x-deco-mock")
+	assert.Contains(t, operation.Description, "Mocked")
+}
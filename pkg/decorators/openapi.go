@@ -2,8 +2,10 @@ package decorators
 
 import (
 	"fmt"
+	"html"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,7 @@ type OpenAPISpec struct {
 	Security     []SecurityRequirement  `json:"security,omitempty"`
 	Tags         []OpenAPITag           `json:"tags,omitempty"`
 	ExternalDocs *ExternalDocs          `json:"externalDocs,omitempty"`
+	Webhooks     map[string]OpenAPIPath `json:"webhooks,omitempty"` // OpenAPI 3.1 only
 }
 
 // OpenAPIInfo basic API information
@@ -51,6 +54,7 @@ type OpenAPIServer struct {
 	URL         string                    `json:"url"`
 	Description string                    `json:"description,omitempty"`
 	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+	Environment string                    `json:"x-environment,omitempty"` // environment name used by the generated SDKs
 }
 
 // ServerVariable server variable
@@ -94,6 +98,7 @@ type OpenAPIParameter struct {
 	Example         interface{}          `json:"example,omitempty"`
 	Examples        map[string]Example   `json:"examples,omitempty"`
 	Content         map[string]MediaType `json:"content,omitempty"`
+	CatchAll        bool                 `json:"x-catch-all,omitempty"` // CatchAllExtension
 }
 
 // OpenAPIRequestBody corpo da request
@@ -157,6 +162,10 @@ type OpenAPISchema struct {
 	Deprecated           bool                      `json:"deprecated,omitempty"`
 	Discriminator        *Discriminator            `json:"discriminator,omitempty"`
 	Ref                  string                    `json:"$ref,omitempty"`
+	SchemaVersion        string                    `json:"x-schema-version,omitempty"` // from @SchemaVersion
+	RemovedIn            string                    `json:"x-removed-in,omitempty"`     // version removing a deprecated property
+	Sensitive            string                    `json:"x-sensitive,omitempty"`      // "mask" or "encrypt" (@Sensitive)
+	ACL                  []string                  `json:"x-acl,omitempty"`            // roles or scopes allowed to read the property (@FieldACL)
 }
 
 // OpenAPIComponents reusable components
@@ -275,16 +284,88 @@ type Discriminator struct {
 
 // GenerateOpenAPISpec generates complete OpenAPI 3.0 specification
 func GenerateOpenAPISpec(config *Config) *OpenAPISpec {
-	routes := GetRoutes()
-	groups := GetGroups()
+	return buildOpenAPISpec(config, documentedRoutes(GetRoutes(), specVersioning(config), ""), GetGroups())
+}
+
+// GenerateVersionOpenAPISpec generates the OpenAPI 3.0 specification of an API version: its @Version
+// routes and the unversioned ones
+func GenerateVersionOpenAPISpec(config *Config, version string) *OpenAPISpec {
+	spec := buildOpenAPISpec(config, documentedRoutes(GetRoutes(), specVersioning(config), version), GetGroups())
+	spec.Info.Version = version
+	return spec
+}
+
+// GenerateOpenAPISpecFromMeta builds the specification from parsed route metadata, without a running registry
+func GenerateOpenAPISpecFromMeta(config *Config, metas []*RouteMeta) *OpenAPISpec {
+	routes := make([]RouteEntry, 0, len(metas))
+	groups := make(map[string]*GroupInfo)
+	versioning := specVersioning(config)
 
+	for _, meta := range metas {
+		if meta.Method == "" || meta.Path == "" {
+			continue
+		}
+		// Like the generated code, a @Version route is registered once per version
+		for _, version := range routeVersions(meta) {
+			entry := routeEntryFromMeta(meta)
+			entry.Version = version
+			entry.Path = versioning.Path(&entry)
+			routes = append(routes, entry)
+		}
+		if meta.Group != nil {
+			groups[meta.Group.Name] = meta.Group
+		}
+	}
+
+	return buildOpenAPISpec(config, documentedRoutes(routes, versioning, ""), groups)
+}
+
+// specVersioning versioning section of the specification
+func specVersioning(config *Config) VersioningConfig {
+	if config == nil {
+		return versioningConfig()
+	}
+	return config.Versioning
+}
+
+// routeEntryFromMeta mirrors what RegisterRouteWithMeta records for a generated route
+func routeEntryFromMeta(meta *RouteMeta) RouteEntry {
+	entry := RouteEntry{
+		Method:         meta.Method,
+		Path:           meta.Path,
+		FuncName:       meta.FuncName,
+		PackageName:    meta.PackageName,
+		FileName:       meta.FileName,
+		Description:    meta.Description,
+		Summary:        meta.Summary,
+		Tags:           append([]string(nil), meta.Tags...),
+		MiddlewareInfo: meta.MiddlewareInfo,
+		Parameters:     meta.Parameters,
+		Group:          meta.Group,
+		Responses:      meta.Responses,
+		OperationID:    meta.OperationID,
+		Deprecation:    meta.Deprecation,
+	}
+
+	if entry.Group != nil {
+		if entry.Group.Prefix != "" && !strings.HasPrefix(entry.Path, entry.Group.Prefix) {
+			entry.Path = entry.Group.Prefix + entry.Path
+		}
+		entry.Tags = append(entry.Tags, entry.Group.Name)
+	}
+
+	return entry
+}
+
+// buildOpenAPISpec assembles the specification for a set of routes and groups
+func buildOpenAPISpec(config *Config, routes []RouteEntry, groups map[string]*GroupInfo) *OpenAPISpec {
 	spec := createBaseSpec(config)
 	configureSpecInfo(spec, config)
 	configureSpecServers(spec, config)
 	configureSpecSecurity(spec, config)
 	configureSpecComponents(spec)
 	configureSpecTags(spec, groups)
-	configureSpecPaths(spec, routes)
+	configureSpecPaths(spec, routes, config)
 
 	return spec
 }
@@ -375,7 +456,23 @@ func configureLicense(spec *OpenAPISpec, config *Config) {
 }
 
 func configureSpecServers(spec *OpenAPISpec, config *Config) {
-	if config == nil || config.OpenAPI.Host == "" {
+	if config == nil {
+		return
+	}
+
+	// Named environments take precedence over host/schemes
+	if len(config.OpenAPI.Servers) > 0 {
+		for _, server := range config.OpenAPI.Servers {
+			spec.Servers = append(spec.Servers, OpenAPIServer{
+				URL:         server.URL,
+				Description: server.Description,
+				Environment: server.Name,
+			})
+		}
+		return
+	}
+
+	if config.OpenAPI.Host == "" {
 		return
 	}
 
@@ -411,18 +508,46 @@ func configureSpecTags(spec *OpenAPISpec, groups map[string]*GroupInfo) {
 	}
 }
 
-func configureSpecPaths(spec *OpenAPISpec, routes []RouteEntry) {
+func configureSpecPaths(spec *OpenAPISpec, routes []RouteEntry, config *Config) {
+	idConfig := OperationIDConfig{}
+	if config != nil {
+		idConfig = config.OpenAPI.OperationID
+	}
+	idGenerator, err := NewOperationIDGenerator(idConfig)
+	if err != nil {
+		LogSilent("⚠️  %v, using default operationIds", err)
+		idGenerator, _ = NewOperationIDGenerator(OperationIDConfig{})
+	}
+	owners := make(map[string][]string)
+
 	for i := range routes {
 		route := &routes[i]
-		path := route.Path
+		path := openAPIPathTemplate(route.Path)
 
 		if spec.Paths[path] == nil {
 			spec.Paths[path] = make(OpenAPIPath)
 		}
 
 		operation := convertRouteToOperation(route, spec.Components)
+		if hasFieldACL(route) {
+			fieldACLOperation(operation, spec.Components)
+		}
+		if envelope, ok := routeEnvelope(route, config); ok {
+			envelopeOperation(operation, envelope)
+		}
+		// Generated routes carry the operationId their logs and traces use
+		operation.OperationID = route.OperationID
+		if operation.OperationID == "" {
+			operation.OperationID = idGenerator.Generate(route.Method, route.Path, route.FuncName, route.PackageName)
+		}
+		owners[operation.OperationID] = append(owners[operation.OperationID], route.Method+" "+route.Path)
 		spec.Paths[path][strings.ToLower(route.Method)] = operation
 	}
+
+	// Generation fails on collisions; specs built at runtime can only report them
+	if err := operationIDCollisionError(owners); err != nil {
+		LogSilent("❌ %v", err)
+	}
 }
 
 // convertRouteToOperation converts RouteEntry to OpenAPIOperation
@@ -441,14 +566,27 @@ func convertRouteToOperation(route *RouteEntry, components *OpenAPIComponents) *
 	}
 	operation.Tags = append(operation.Tags, route.Tags...)
 
+	// @Deprecated routes stay documented, with the replacement and the sunset date
+	if route.Deprecation != nil {
+		operation.Deprecated = true
+		operation.Description = strings.TrimSpace(operation.Description + "\n\n" + route.Deprecation.notice())
+		if route.Deprecation.Sunset != "" {
+			operation.Extensions["x-sunset"] = route.Deprecation.Sunset
+		}
+	}
+
 	// Separate body parameters from other parameters
 	var bodyParams []ParameterInfo
+	var fileParams []ParameterInfo
 	var otherParams []ParameterInfo
 
 	for _, param := range route.Parameters {
-		if param.Location == "body" {
+		switch param.Location {
+		case "body":
 			bodyParams = append(bodyParams, param)
-		} else {
+		case "file":
+			fileParams = append(fileParams, param)
+		default:
 			otherParams = append(otherParams, param)
 		}
 	}
@@ -458,10 +596,15 @@ func convertRouteToOperation(route *RouteEntry, components *OpenAPIComponents) *
 		operation.Parameters = append(operation.Parameters, convertToOpenAPIParameter(&param, components))
 	}
 
+	addCatchAllParameter(operation, route)
+
 	// Process request body if there are body parameters
 	if len(bodyParams) > 0 {
 		operation.RequestBody = createRequestBodyFromParameters(bodyParams, components)
 	}
+	if len(fileParams) > 0 {
+		operation.RequestBody = multipartRequestBody(operation.RequestBody, fileParams)
+	}
 
 	// Process responses with schema support
 	if len(route.Responses) > 0 {
@@ -492,11 +635,27 @@ func convertRouteToOperation(route *RouteEntry, components *OpenAPIComponents) *
 		operation.Extensions["x-middlewares"] = middlewares
 	}
 
+	// Message types of WebSocket routes, used by the SDK generators
+	if extension := websocketExtension(route); extension != nil {
+		operation.Extensions[WebSocketExtension] = extension
+	}
+
+	// Event stream of @SSE routes
+	if extension := sseExtension(route); extension != nil {
+		operation.Extensions[SSEExtension] = extension
+		operation.Responses["200"] = sseResponse(extension)
+	}
+
 	// Add rate limiting if present
 	for _, mw := range route.MiddlewareInfo {
 		if mw.Name == "RateLimit" {
 			operation.Extensions["x-rate-limit"] = mw.Args
 		}
+		// Flag canned responses so consumers know the handler is not implemented yet
+		if mw.Name == "Mock" {
+			operation.Extensions["x-deco-mock"] = mw.Args
+			operation.Description = strings.TrimSpace("⚠️ Mocked: returns a canned @Mock response. " + operation.Description)
+		}
 	}
 
 	return operation
@@ -515,10 +674,11 @@ func createRequestBodyFromParameters(params []ParameterInfo, _ *OpenAPIComponent
 
 	// Check if any parameter references an existing schema
 	for _, param := range params {
+		contentType := requestBodyContentType(param)
 		schemaRef := findSchemaByName(param.Type)
 		if schemaRef != nil {
 			// Reference existing schema
-			requestBody.Content["application/json"] = MediaType{
+			requestBody.Content[contentType] = MediaType{
 				Schema: &OpenAPISchema{
 					Ref: fmt.Sprintf("#/components/schemas/%s", param.Type),
 				},
@@ -532,7 +692,7 @@ func createRequestBodyFromParameters(params []ParameterInfo, _ *OpenAPIComponent
 			if param.Example != "" {
 				mediaType.Example = param.Example
 			}
-			requestBody.Content["application/json"] = mediaType
+			requestBody.Content[contentType] = mediaType
 		}
 	}
 
@@ -625,10 +785,15 @@ func findSchemaByPattern(pattern string) *SchemaInfo {
 		return schema
 	}
 
-	// Then try pattern match
-	for name, schema := range schemas {
+	// Then try pattern match, in name order so the same handlers always give the same spec
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
 		if strings.Contains(name, pattern) {
-			return schema
+			return schemas[name]
 		}
 	}
 	return nil
@@ -647,6 +812,9 @@ func convertToOpenAPIParameter(param *ParameterInfo, _ *OpenAPIComponents) OpenA
 	if param.Example != "" {
 		openAPIParam.Example = param.Example
 	}
+	if param.Pattern != "" {
+		openAPIParam.Schema.Pattern = param.Pattern
+	}
 
 	return openAPIParam
 }
@@ -723,7 +891,7 @@ func generateOperationID(route *RouteEntry) string {
 	operationID := strings.ToLower(route.Method) + caser.String(cleanPath)
 
 	if route.FuncName != "" {
-		operationID = route.FuncName
+		operationID = operationFuncName(route.FuncName)
 	}
 
 	return operationID
@@ -785,10 +953,11 @@ func addRegisteredSchemas(components *OpenAPIComponents) {
 // convertSchemaInfoToOpenAPISchema converts SchemaInfo to OpenAPISchema
 func convertSchemaInfoToOpenAPISchema(schemaInfo *SchemaInfo) *OpenAPISchema {
 	schema := &OpenAPISchema{
-		Type:        schemaInfo.Type,
-		Description: schemaInfo.Description,
-		Properties:  make(map[string]*OpenAPISchema),
-		Required:    schemaInfo.Required,
+		Type:          schemaInfo.Type,
+		Description:   schemaInfo.Description,
+		Properties:    make(map[string]*OpenAPISchema),
+		Required:      schemaInfo.Required,
+		SchemaVersion: schemaInfo.Version,
 	}
 
 	// Add example if provided
@@ -853,6 +1022,17 @@ func convertSchemaInfoToOpenAPISchema(schemaInfo *SchemaInfo) *OpenAPISchema {
 			}
 		}
 
+		// Deprecated properties stay documented until the version that removes them
+		if propInfo.Deprecated {
+			propSchema.Deprecated = true
+			propSchema.RemovedIn = propInfo.RemovedIn
+			propSchema.Description = strings.TrimSpace(propSchema.Description + " " + deprecationNotice(propInfo))
+		}
+
+		propSchema.Sensitive = propInfo.Sensitive
+		propSchema.ACL = propInfo.ACL
+		propSchema.Nullable = strings.HasPrefix(propInfo.GoType, "*") // pointer fields may be null
+
 		schema.Properties[propName] = propSchema
 	}
 
@@ -867,6 +1047,19 @@ func OpenAPIJSONHandler(config *Config) gin.HandlerFunc {
 	}
 }
 
+// OpenAPIVersionHandler serves the OpenAPI 3.0 documentation of the version named by the path, e.g.
+// /decorators/openapi/v2.json
+func OpenAPIVersionHandler(config *Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, found := strings.CutSuffix(c.Param("version"), ".json")
+		if !found || !contains(APIVersions(), version) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown_version", "versions": APIVersions()})
+			return
+		}
+		c.JSON(http.StatusOK, GenerateVersionOpenAPISpec(config, version))
+	}
+}
+
 // OpenAPIYAMLHandler serves OpenAPI 3.0 documentation in YAML
 func OpenAPIYAMLHandler(config *Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -876,11 +1069,15 @@ func OpenAPIYAMLHandler(config *Config) gin.HandlerFunc {
 }
 
 // SwaggerUIHandler creates Swagger UI handler with customizable settings via config
-func SwaggerUIHandler(_ *Config) gin.HandlerFunc {
+func SwaggerUIHandler(config *Config) gin.HandlerFunc {
+	branding := newDocsBranding(config)
+
 	return func(c *gin.Context) {
-		// Use config to customize Swagger UI settings
-		swaggerURL := "/decorators/openapi.json"
-		// Don't add BasePath for internal endpoints
+		// The spec is served next to the Swagger UI, under docs.base_path
+		swaggerURL := DefaultDocsBasePath + "/openapi.json"
+		if config != nil {
+			swaggerURL = config.Docs.Path("openapi.json")
+		}
 
 		// Customize Swagger UI HTML based on config
 		htmlTemplate := `
@@ -888,9 +1085,9 @@ func SwaggerUIHandler(_ *Config) gin.HandlerFunc {
 <html lang="en">
 <head>
     <meta charset="UTF-8">
-    <title>API Documentation</title>
+    <title>{{TITLE}}</title>
     <link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@4.15.5/swagger-ui.css" />
-</head>
+{{BRANDING}}</head>
 <body>
     <div id="swagger-ui"></div>
     <script src="https://unpkg.com/swagger-ui-dist@4.15.5/swagger-ui-bundle.js"></script>
@@ -924,11 +1121,19 @@ func SwaggerUIHandler(_ *Config) gin.HandlerFunc {
 </body>
 </html>`
 
-		// Replace placeholder with actual URL
-		html := strings.Replace(htmlTemplate, "{{SWAGGER_URL}}", swaggerURL, 1)
+		// Replace placeholders with actual URL and docs.branding
+		title := "API Documentation"
+		if branding.Title != defaultDocsTitle {
+			title = branding.Title + " - " + title
+		}
+		page := strings.NewReplacer(
+			"{{SWAGGER_URL}}", swaggerURL,
+			"{{TITLE}}", html.EscapeString(title),
+			"{{BRANDING}}", branding.swaggerUIHead(),
+		).Replace(htmlTemplate)
 
 		c.Header("Content-Type", "text/html; charset=utf-8")
-		c.String(http.StatusOK, html)
+		c.String(http.StatusOK, page)
 	}
 }
 
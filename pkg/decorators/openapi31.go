@@ -0,0 +1,189 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI31Path endpoint serving the specification in OpenAPI 3.1
+const OpenAPI31Path = "/decorators/openapi31.json"
+
+// OpenAPI versions of openapi.spec_version
+const (
+	OpenAPISpecVersion30 = "3.0"
+	OpenAPISpecVersion31 = "3.1"
+)
+
+// jsonSchemaDialect31 default dialect of the schemas of an OpenAPI 3.1 document
+const jsonSchemaDialect31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// frameworkEventTypes event types published to events.sink, documented as webhooks
+var frameworkEventTypes = []struct {
+	eventType string
+	summary   string
+}{
+	{EventEngineStarted, "Engine started serving"},
+	{EventEngineStopping, "Engine shutting down"},
+	{EventRouteDisabled, "Route disabled"},
+	{EventConfigReloaded, "Configuration reloaded"},
+	{EventCircuitBreakerOpened, "Circuit breaker opened"},
+}
+
+// GenerateOpenAPI31Spec generates the specification as an OpenAPI 3.1 document: schemas follow
+// JSON Schema 2020-12 (type arrays instead of nullable, numeric exclusive bounds, examples) and,
+// with events enabled, the CloudEvents posted to events.sink are listed under webhooks
+func GenerateOpenAPI31Spec(config *Config) map[string]interface{} {
+	spec := GenerateOpenAPISpec(config)
+	if config != nil && config.Events.Enabled {
+		addEventWebhooks(spec)
+	}
+	return ConvertOpenAPI31(spec)
+}
+
+// ConvertOpenAPI31 converts an OpenAPI 3.0 specification to an OpenAPI 3.1 document
+func ConvertOpenAPI31(spec *OpenAPISpec) map[string]interface{} {
+	data, _ := json.Marshal(spec)
+	var document map[string]interface{}
+	_ = json.Unmarshal(data, &document)
+
+	document["openapi"] = "3.1.0"
+	document["jsonSchemaDialect"] = jsonSchemaDialect31
+	upgradeNode31(document)
+	return document
+}
+
+// upgradeNode31 converts the schemas found below an OpenAPI node; examples are user data and
+// are left untouched
+func upgradeNode31(node interface{}) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			switch key {
+			case "example", "examples":
+			case "schema":
+				upgradeSchema31(child)
+			case "schemas":
+				if schemas, ok := child.(map[string]interface{}); ok {
+					for _, schema := range schemas {
+						upgradeSchema31(schema)
+					}
+				}
+			default:
+				upgradeNode31(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			upgradeNode31(child)
+		}
+	}
+}
+
+// upgradeSchema31 rewrites the OpenAPI 3.0 keywords of a schema and its subschemas
+func upgradeSchema31(node interface{}) {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if schemaType, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{schemaType, "null"}
+		} else if ref, ok := schema["$ref"]; ok {
+			delete(schema, "$ref")
+			schema["anyOf"] = []interface{}{map[string]interface{}{"$ref": ref}, map[string]interface{}{"type": "null"}}
+		}
+	}
+	delete(schema, "nullable")
+
+	upgradeExclusiveBound31(schema, "exclusiveMinimum", "minimum")
+	upgradeExclusiveBound31(schema, "exclusiveMaximum", "maximum")
+
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+
+	for _, key := range []string{"properties", "patternProperties"} {
+		if properties, ok := schema[key].(map[string]interface{}); ok {
+			for _, property := range properties {
+				upgradeSchema31(property)
+			}
+		}
+	}
+	for _, key := range []string{"items", "not", "additionalProperties"} {
+		upgradeSchema31(schema[key])
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if schemas, ok := schema[key].([]interface{}); ok {
+			for _, subschema := range schemas {
+				upgradeSchema31(subschema)
+			}
+		}
+	}
+}
+
+// upgradeExclusiveBound31 turns the boolean exclusive bound of OpenAPI 3.0 into the numeric one
+func upgradeExclusiveBound31(schema map[string]interface{}, exclusive, bound string) {
+	isExclusive, ok := schema[exclusive].(bool)
+	if !ok {
+		return
+	}
+	delete(schema, exclusive)
+	if !isExclusive {
+		return
+	}
+	value, ok := schema[bound]
+	if !ok {
+		value = 0 // omitted because it is zero
+	}
+	delete(schema, bound)
+	schema[exclusive] = value
+}
+
+// addEventWebhooks documents the framework events as webhooks posting a CloudEvent
+func addEventWebhooks(spec *OpenAPISpec) {
+	spec.Components.Schemas["CloudEvent"] = &OpenAPISchema{
+		Type:        "object",
+		Description: "CloudEvents 1.0 event in structured mode",
+		Required:    []string{"specversion", "id", "source", "type", "time"},
+		Properties: map[string]*OpenAPISchema{
+			"specversion":     {Type: "string", Example: "1.0"},
+			"id":              {Type: "string"},
+			"source":          {Type: "string"},
+			"type":            {Type: "string"},
+			"subject":         {Type: "string"},
+			"time":            {Type: "string", Format: "date-time"},
+			"datacontenttype": {Type: "string", Example: "application/json"},
+			"data":            {Type: "object"},
+		},
+	}
+
+	spec.Webhooks = make(map[string]OpenAPIPath, len(frameworkEventTypes))
+	for _, event := range frameworkEventTypes {
+		spec.Webhooks[event.eventType] = OpenAPIPath{
+			"post": &OpenAPIOperation{
+				Summary:     event.summary,
+				Description: "Posted to events.sink with type " + event.eventType,
+				RequestBody: &OpenAPIRequestBody{
+					Required: true,
+					Content: map[string]MediaType{
+						CloudEventsContentType: {Schema: &OpenAPISchema{Ref: "#/components/schemas/CloudEvent"}},
+					},
+				},
+				Responses: map[string]OpenAPIResponse{
+					"2XX": {Description: "Event accepted"},
+				},
+			},
+		}
+	}
+}
+
+// OpenAPI31Handler serves the OpenAPI 3.1 documentation in JSON
+func OpenAPI31Handler(config *Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, GenerateOpenAPI31Spec(config))
+	}
+}
@@ -0,0 +1,121 @@
+package decorators
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertOpenAPI31(t *testing.T) {
+	spec := &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    OpenAPIInfo{Title: "Test", Version: "1.0.0"},
+		Paths: map[string]OpenAPIPath{
+			"/users": {"get": &OpenAPIOperation{
+				Parameters: []OpenAPIParameter{{Name: "page", In: "query", Schema: &OpenAPISchema{Type: "integer", Minimum: 1, ExclusiveMinimum: true}}},
+				Responses: map[string]OpenAPIResponse{"200": {
+					Description: "OK",
+					Content: map[string]MediaType{"application/json": {
+						Schema:  &OpenAPISchema{Ref: "#/components/schemas/User"},
+						Example: map[string]interface{}{"nullable": true},
+					}},
+				}},
+			}},
+		},
+		Components: &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+			"User": {Type: "object", Properties: map[string]*OpenAPISchema{
+				"nickname": {Type: "string", Nullable: true, Example: "ana"},
+				"manager":  {Ref: "#/components/schemas/User", Nullable: true},
+				"tags":     {Type: "array", Items: &OpenAPISchema{Type: "string", Nullable: true}},
+				"score":    {Type: "number", Maximum: 10, ExclusiveMaximum: true},
+			}},
+		}},
+	}
+
+	document := ConvertOpenAPI31(spec)
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	var converted struct {
+		OpenAPI           string `json:"openapi"`
+		JSONSchemaDialect string `json:"jsonSchemaDialect"`
+		Paths             map[string]map[string]struct {
+			Parameters []struct {
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Example map[string]interface{} `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(data, &converted))
+
+	assert.Equal(t, "3.1.0", converted.OpenAPI)
+	assert.Equal(t, jsonSchemaDialect31, converted.JSONSchemaDialect)
+
+	properties := converted.Components.Schemas["User"].Properties
+	assert.Equal(t, []interface{}{"string", "null"}, properties["nickname"]["type"])
+	assert.Equal(t, []interface{}{"ana"}, properties["nickname"]["examples"])
+	assert.NotContains(t, properties["nickname"], "nullable")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/User"},
+		map[string]interface{}{"type": "null"},
+	}, properties["manager"]["anyOf"])
+	assert.Equal(t, []interface{}{"string", "null"}, properties["tags"]["items"].(map[string]interface{})["type"])
+	assert.Equal(t, float64(10), properties["score"]["exclusiveMaximum"])
+	assert.NotContains(t, properties["score"], "maximum")
+
+	operation := converted.Paths["/users"]["get"]
+	assert.Equal(t, float64(1), operation.Parameters[0].Schema["exclusiveMinimum"])
+	assert.NotContains(t, operation.Parameters[0].Schema, "minimum")
+	// Examples are data, not schemas
+	assert.Equal(t, true, operation.Responses["200"].Content["application/json"].Example["nullable"])
+
+	// The 3.0 specification is not modified
+	assert.True(t, spec.Components.Schemas["User"].Properties["nickname"].Nullable)
+}
+
+func TestGenerateOpenAPI31SpecWebhooks(t *testing.T) {
+	config := DefaultConfig()
+	document := GenerateOpenAPI31Spec(config)
+	assert.NotContains(t, document, "webhooks", "without events there are no webhooks")
+
+	config.Events.Enabled = true
+	document = GenerateOpenAPI31Spec(config)
+	webhooks, ok := document["webhooks"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Len(t, webhooks, len(frameworkEventTypes))
+	post := webhooks[EventEngineStarted].(map[string]interface{})["post"].(map[string]interface{})
+	content := post["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+	assert.Contains(t, content, CloudEventsContentType)
+
+	schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Contains(t, schemas, "CloudEvent")
+}
+
+func TestPointerPropertiesAreNullable(t *testing.T) {
+	schema := convertSchemaInfoToOpenAPISchema(&SchemaInfo{
+		Name: "Profile",
+		Type: "object",
+		Properties: map[string]*PropertyInfo{
+			"bio":  {Name: "bio", Type: "string", GoType: "*string"},
+			"name": {Name: "name", Type: "string", GoType: "string"},
+		},
+	})
+	assert.True(t, schema.Properties["bio"].Nullable)
+	assert.False(t, schema.Properties["name"].Nullable)
+}
+
+func TestOpenAPIConfigSpecVersion(t *testing.T) {
+	assert.NoError(t, OpenAPIConfig{SpecVersion: "3.1"}.validate())
+	assert.NoError(t, OpenAPIConfig{}.validate())
+	assert.Error(t, OpenAPIConfig{SpecVersion: "3.2"}.validate())
+}
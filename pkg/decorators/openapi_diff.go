@@ -0,0 +1,317 @@
+package decorators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Directions a schema is read in: requests are written by the clients, responses read by them
+const (
+	schemaUsageRequest = 1 << iota
+	schemaUsageResponse
+)
+
+// SpecChange one difference between two specs, as seen by the clients of the API
+type SpecChange struct {
+	Kind     string `json:"kind"`     // e.g. "endpoint-removed", "parameter-added", "type-changed"
+	Location string `json:"location"` // "GET /users/{id}", "GET /users query.page", "User.email"
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// SpecChangeReport endpoints and models added, removed or changed between two specs, with the detailed
+// changes and whether they break existing clients
+type SpecChangeReport struct {
+	Diff    *SpecDiff    `json:"diff"`
+	Changes []SpecChange `json:"changes"`
+}
+
+// Breaking changes that break existing clients
+func (r *SpecChangeReport) Breaking() []SpecChange {
+	var breaking []SpecChange
+	for _, change := range r.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// specComparison state of CompareOpenAPISpecs
+type specComparison struct {
+	previous, current *OpenAPISpec
+	usage             map[string]int // component schema -> schemaUsage* bits, in either spec
+	changes           []SpecChange
+}
+
+// CompareOpenAPISpecs reports the changes from previous to current. Breaking changes are those an
+// existing client notices: a removed endpoint or success response, a new required parameter, body or
+// request property, a changed type, a removed request enum value, a removed or no longer required
+// response property, or authentication added to an open endpoint.
+func CompareOpenAPISpecs(previous, current *OpenAPISpec) *SpecChangeReport {
+	comparison := &specComparison{previous: previous, current: current, usage: make(map[string]int)}
+	comparison.collectUsage(previous)
+	comparison.collectUsage(current)
+
+	diff := DiffOpenAPISpecs(previous, current)
+	for _, endpoint := range diff.AddedEndpoints {
+		comparison.add("endpoint-added", endpoint, false, "endpoint added")
+	}
+	for _, endpoint := range diff.RemovedEndpoints {
+		comparison.add("endpoint-removed", endpoint, true, "endpoint removed")
+	}
+	previousOperations, currentOperations := specOperations(previous), specOperations(current)
+	for _, endpoint := range diff.ChangedEndpoints {
+		comparison.compareOperation(endpoint, previousOperations[endpoint].(*OpenAPIOperation), currentOperations[endpoint].(*OpenAPIOperation))
+	}
+
+	for _, model := range diff.AddedModels {
+		comparison.add("model-added", model, false, "model added")
+	}
+	for _, model := range diff.RemovedModels {
+		comparison.add("model-removed", model, false, "model removed")
+	}
+	for _, model := range diff.ChangedModels {
+		comparison.compareSchema(model, previous.Components.Schemas[model], current.Components.Schemas[model], comparison.usage[model])
+	}
+
+	sort.SliceStable(comparison.changes, func(i, j int) bool {
+		return comparison.changes[i].Location < comparison.changes[j].Location
+	})
+	return &SpecChangeReport{Diff: diff, Changes: comparison.changes}
+}
+
+// add records a change
+func (c *specComparison) add(kind, location string, breaking bool, format string, args ...interface{}) {
+	c.changes = append(c.changes, SpecChange{Kind: kind, Location: location, Message: fmt.Sprintf(format, args...), Breaking: breaking})
+}
+
+// compareOperation compares the parameters, body, responses and security of an endpoint
+func (c *specComparison) compareOperation(endpoint string, previous, current *OpenAPIOperation) {
+	if !previous.Deprecated && current.Deprecated {
+		c.add("endpoint-deprecated", endpoint, false, "endpoint deprecated")
+	}
+
+	previousParams := make(map[string]OpenAPIParameter, len(previous.Parameters))
+	for _, param := range previous.Parameters {
+		previousParams[param.In+"."+param.Name] = param
+	}
+	for _, param := range current.Parameters {
+		key := param.In + "." + param.Name
+		location := endpoint + " " + key
+		old, existed := previousParams[key]
+		delete(previousParams, key)
+		switch {
+		case !existed && param.Required:
+			c.add("parameter-added", location, true, "new required %s parameter", param.In)
+		case !existed:
+			c.add("parameter-added", location, false, "new optional %s parameter", param.In)
+		case !old.Required && param.Required:
+			c.add("parameter-required", location, true, "parameter became required")
+			c.compareSchema(location, old.Schema, param.Schema, schemaUsageRequest)
+		default:
+			c.compareSchema(location, old.Schema, param.Schema, schemaUsageRequest)
+		}
+	}
+	for key := range previousParams {
+		c.add("parameter-removed", endpoint+" "+key, false, "parameter removed")
+	}
+
+	location := endpoint + " request"
+	switch {
+	case previous.RequestBody == nil && current.RequestBody != nil:
+		c.add("request-body-added", location, current.RequestBody.Required, "request body added")
+	case previous.RequestBody != nil && current.RequestBody == nil:
+		c.add("request-body-removed", location, false, "request body removed")
+	case previous.RequestBody != nil:
+		if !previous.RequestBody.Required && current.RequestBody.Required {
+			c.add("request-body-required", location, true, "request body became required")
+		}
+		c.compareSchema(location, jsonMediaSchema(previous.RequestBody.Content), jsonMediaSchema(current.RequestBody.Content), schemaUsageRequest)
+	}
+
+	for status, response := range previous.Responses {
+		location := endpoint + " " + status
+		updated, exists := current.Responses[status]
+		if !exists {
+			c.add("response-removed", location, strings.HasPrefix(status, "2"), "response removed")
+			continue
+		}
+		c.compareSchema(location, jsonMediaSchema(response.Content), jsonMediaSchema(updated.Content), schemaUsageResponse)
+	}
+	for status := range current.Responses {
+		if _, existed := previous.Responses[status]; !existed {
+			c.add("response-added", endpoint+" "+status, false, "response added")
+		}
+	}
+
+	previousSecurity, currentSecurity := c.operationSecurity(c.previous, previous), c.operationSecurity(c.current, current)
+	switch {
+	case len(previousSecurity) == 0 && len(currentSecurity) > 0:
+		c.add("security-added", endpoint, true, "endpoint now requires authentication")
+	case len(previousSecurity) > 0 && len(currentSecurity) == 0:
+		c.add("security-removed", endpoint, false, "endpoint no longer requires authentication")
+	}
+}
+
+// operationSecurity requirements of an operation, the spec ones when it declares none
+func (c *specComparison) operationSecurity(spec *OpenAPISpec, operation *OpenAPIOperation) []SecurityRequirement {
+	if operation.Security != nil || spec == nil {
+		return operation.Security
+	}
+	return spec.Security
+}
+
+// compareSchema compares two schemas read in the usage directions. References are compared by name:
+// the component schemas are compared on their own.
+func (c *specComparison) compareSchema(location string, previous, current *OpenAPISchema, usage int) {
+	if previous == nil || current == nil {
+		return
+	}
+	if previous.Ref != "" || current.Ref != "" {
+		if previous.Ref != current.Ref {
+			c.add("type-changed", location, true, "type changed from %s to %s", diffSchemaName(previous), diffSchemaName(current))
+		}
+		return
+	}
+	if previous.Type != "" && current.Type != "" && previous.Type != current.Type {
+		c.add("type-changed", location, true, "type changed from %s to %s", previous.Type, current.Type)
+		return
+	}
+	if previous.Format != "" && current.Format != "" && previous.Format != current.Format {
+		c.add("format-changed", location, true, "format changed from %s to %s", previous.Format, current.Format)
+	}
+
+	currentEnum := make(map[string]bool, len(current.Enum))
+	for _, value := range current.Enum {
+		currentEnum[fmt.Sprint(value)] = true
+	}
+	previousEnum := make(map[string]bool, len(previous.Enum))
+	for _, value := range previous.Enum {
+		previousEnum[fmt.Sprint(value)] = true
+		if len(current.Enum) > 0 && !currentEnum[fmt.Sprint(value)] {
+			c.add("enum-value-removed", location, usage&schemaUsageRequest != 0, "enum value '%v' removed", value)
+		}
+	}
+	for _, value := range current.Enum {
+		if len(previous.Enum) > 0 && !previousEnum[fmt.Sprint(value)] {
+			c.add("enum-value-added", location, false, "enum value '%v' added", value)
+		}
+	}
+
+	previousRequired, currentRequired := stringSet(previous.Required), stringSet(current.Required)
+	for _, name := range sortedSchemaProperties(previous) {
+		property := location + "." + name
+		updated, exists := current.Properties[name]
+		switch {
+		case !exists:
+			c.add("property-removed", property, usage&schemaUsageResponse != 0, "property removed")
+			continue
+		case !previousRequired[name] && currentRequired[name]:
+			c.add("property-required", property, usage&schemaUsageRequest != 0, "property became required")
+		case previousRequired[name] && !currentRequired[name]:
+			c.add("property-optional", property, usage&schemaUsageResponse != 0, "property became optional")
+		}
+		c.compareSchema(property, previous.Properties[name], updated, usage)
+	}
+	for _, name := range sortedSchemaProperties(current) {
+		if _, existed := previous.Properties[name]; existed {
+			continue
+		}
+		if currentRequired[name] {
+			c.add("property-added", location+"."+name, usage&schemaUsageRequest != 0, "new required property")
+		} else {
+			c.add("property-added", location+"."+name, false, "new optional property")
+		}
+	}
+
+	c.compareSchema(location+"[]", previous.Items, current.Items, usage)
+}
+
+// collectUsage marks the component schemas read by the requests and responses of spec, following the
+// references between components
+func (c *specComparison) collectUsage(spec *OpenAPISpec) {
+	if spec == nil {
+		return
+	}
+	for _, path := range spec.Paths {
+		for _, operation := range path {
+			for _, param := range operation.Parameters {
+				c.markUsage(spec, param.Schema, schemaUsageRequest)
+			}
+			if operation.RequestBody != nil {
+				for _, media := range operation.RequestBody.Content {
+					c.markUsage(spec, media.Schema, schemaUsageRequest)
+				}
+			}
+			for _, response := range operation.Responses {
+				for _, media := range response.Content {
+					c.markUsage(spec, media.Schema, schemaUsageResponse)
+				}
+			}
+		}
+	}
+}
+
+// markUsage marks the components referenced by schema with usage
+func (c *specComparison) markUsage(spec *OpenAPISpec, schema *OpenAPISchema, usage int) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if c.usage[name]&usage != 0 {
+			return
+		}
+		c.usage[name] |= usage
+		if spec.Components != nil {
+			c.markUsage(spec, spec.Components.Schemas[name], usage)
+		}
+		return
+	}
+	for _, property := range schema.Properties {
+		c.markUsage(spec, property, usage)
+	}
+	for _, group := range [][]*OpenAPISchema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, part := range group {
+			c.markUsage(spec, part, usage)
+		}
+	}
+	c.markUsage(spec, schema.Items, usage)
+}
+
+// jsonMediaSchema schema of the JSON media type of a content map
+func jsonMediaSchema(content map[string]MediaType) *OpenAPISchema {
+	return content["application/json"].Schema
+}
+
+// diffSchemaName name of the type of a schema, for messages
+func diffSchemaName(schema *OpenAPISchema) string {
+	if schema.Ref != "" {
+		return strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+	}
+	if schema.Type == "" {
+		return "any"
+	}
+	return schema.Type
+}
+
+// sortedSchemaProperties property names of a schema, sorted
+func sortedSchemaProperties(schema *OpenAPISchema) []string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stringSet set of the values
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
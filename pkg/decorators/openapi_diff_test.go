@@ -0,0 +1,120 @@
+package decorators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// specChange change of the report at location, nil when none
+func specChange(report *SpecChangeReport, kind, location string) *SpecChange {
+	for i, change := range report.Changes {
+		if change.Kind == kind && change.Location == location {
+			return &report.Changes[i]
+		}
+	}
+	return nil
+}
+
+func TestCompareOpenAPISpecs(t *testing.T) {
+	userBody := map[string]MediaType{"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/User"}}}
+	previous := changelogSpec(map[string]OpenAPIPath{
+		"/users": {
+			"get": {Parameters: []OpenAPIParameter{
+				{Name: "page", In: "query", Schema: &OpenAPISchema{Type: "integer"}},
+				{Name: "sort", In: "query", Schema: &OpenAPISchema{Type: "string", Enum: []interface{}{"name", "age"}}},
+			}, Responses: map[string]OpenAPIResponse{"200": {Content: userBody}}},
+			"post": {RequestBody: &OpenAPIRequestBody{Content: map[string]MediaType{
+				"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/NewUser"}},
+			}}, Responses: map[string]OpenAPIResponse{"201": {Content: userBody}}},
+		},
+		"/health": {"get": {}},
+	}, map[string]*OpenAPISchema{
+		"User": {Type: "object", Required: []string{"id"}, Properties: map[string]*OpenAPISchema{
+			"id":      {Type: "integer"},
+			"email":   {Type: "string"},
+			"address": {Ref: "#/components/schemas/Address"},
+		}},
+		"NewUser": {Type: "object", Properties: map[string]*OpenAPISchema{"email": {Type: "string"}}},
+		"Address": {Type: "object", Properties: map[string]*OpenAPISchema{"city": {Type: "string", Format: "email"}}},
+	})
+	current := changelogSpec(map[string]OpenAPIPath{
+		"/users": {
+			"get": {Deprecated: true, Parameters: []OpenAPIParameter{
+				{Name: "page", In: "query", Required: true, Schema: &OpenAPISchema{Type: "string"}},
+				{Name: "sort", In: "query", Schema: &OpenAPISchema{Type: "string", Enum: []interface{}{"name", "email"}}},
+				{Name: "limit", In: "query", Schema: &OpenAPISchema{Type: "integer"}},
+			}, Responses: map[string]OpenAPIResponse{"200": {Content: userBody}, "400": {}}},
+			"post": {RequestBody: &OpenAPIRequestBody{Required: true, Content: map[string]MediaType{
+				"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/NewUser"}},
+			}}, Security: []SecurityRequirement{{"BearerAuth": {}}}},
+		},
+		"/users/{id}": {"get": {Responses: map[string]OpenAPIResponse{"200": {Content: userBody}}}},
+	}, map[string]*OpenAPISchema{
+		"User": {Type: "object", Properties: map[string]*OpenAPISchema{
+			"id":      {Type: "integer"},
+			"name":    {Type: "string"},
+			"address": {Ref: "#/components/schemas/Address"},
+		}},
+		"NewUser": {Type: "object", Required: []string{"email", "name"}, Properties: map[string]*OpenAPISchema{
+			"email": {Type: "string"},
+			"name":  {Type: "string"},
+		}},
+		"Address": {Type: "object", Properties: map[string]*OpenAPISchema{"city": {Type: "string", Format: "uuid"}}},
+	})
+
+	report := CompareOpenAPISpecs(previous, current)
+	assert.Equal(t, []string{"GET /users/{id}"}, report.Diff.AddedEndpoints)
+
+	cases := []struct {
+		kind, location string
+		breaking       bool
+	}{
+		{"endpoint-removed", "GET /health", true},
+		{"endpoint-added", "GET /users/{id}", false},
+		{"endpoint-deprecated", "GET /users", false},
+		{"parameter-required", "GET /users query.page", true},
+		{"type-changed", "GET /users query.page", true},
+		{"enum-value-removed", "GET /users query.sort", true},
+		{"enum-value-added", "GET /users query.sort", false},
+		{"parameter-added", "GET /users query.limit", false},
+		{"response-added", "GET /users 400", false},
+		{"request-body-required", "POST /users request", true},
+		{"response-removed", "POST /users 201", true},
+		{"security-added", "POST /users", true},
+		{"property-removed", "User.email", true},
+		{"property-optional", "User.id", true},
+		{"property-added", "User.name", false},
+		{"property-required", "NewUser.email", true},
+		{"property-added", "NewUser.name", true},
+		{"format-changed", "Address.city", true},
+	}
+	for _, tc := range cases {
+		change := specChange(report, tc.kind, tc.location)
+		if assert.NotNil(t, change, "%s %s", tc.kind, tc.location) {
+			assert.Equal(t, tc.breaking, change.Breaking, "%s %s", tc.kind, tc.location)
+		}
+	}
+	assert.Len(t, report.Changes, len(cases))
+	assert.Len(t, report.Breaking(), 12)
+}
+
+func TestCompareOpenAPISpecs_Usage(t *testing.T) {
+	// Tag is only written by the clients: a removed property is harmless, a new required one is not
+	body := map[string]MediaType{"application/json": {Schema: &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Ref: "#/components/schemas/Tag"}}}}
+	paths := map[string]OpenAPIPath{"/tags": {"put": {RequestBody: &OpenAPIRequestBody{Content: body}}}}
+	previous := changelogSpec(paths, map[string]*OpenAPISchema{
+		"Tag": {Type: "object", Properties: map[string]*OpenAPISchema{"label": {Type: "string"}}},
+	})
+	current := changelogSpec(paths, map[string]*OpenAPISchema{
+		"Tag": {Type: "object", Required: []string{"color"}, Properties: map[string]*OpenAPISchema{"color": {Type: "string"}}},
+	})
+
+	report := CompareOpenAPISpecs(previous, current)
+	require.Len(t, report.Changes, 2)
+	assert.False(t, specChange(report, "property-removed", "Tag.label").Breaking)
+	assert.True(t, specChange(report, "property-added", "Tag.color").Breaking)
+
+	assert.Empty(t, CompareOpenAPISpecs(previous, previous).Changes)
+}
@@ -0,0 +1,207 @@
+package decorators
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Operation ID strategies
+const (
+	OperationIDStrategyDefault    = ""           // same as funcName
+	OperationIDStrategyFuncName   = "funcName"   // handler function name
+	OperationIDStrategyMethodPath = "methodPath" // camel case method + path, e.g. putUsersById
+	OperationIDStrategyTemplate   = "template"   // custom text/template
+)
+
+// OperationIDData data available to custom operationId templates
+type OperationIDData struct {
+	Method      string
+	Path        string
+	FuncName    string
+	PackageName string
+	PathCamel   string // path segments in camel case, e.g. UsersById
+}
+
+// OperationIDGenerator builds operationIds according to a strategy
+type OperationIDGenerator struct {
+	strategy string
+	tmpl     *template.Template
+}
+
+// NewOperationIDGenerator creates a generator validating the configured strategy
+func NewOperationIDGenerator(config OperationIDConfig) (*OperationIDGenerator, error) {
+	generator := &OperationIDGenerator{strategy: config.Strategy}
+
+	switch config.Strategy {
+	case OperationIDStrategyDefault, OperationIDStrategyFuncName, OperationIDStrategyMethodPath:
+	case OperationIDStrategyTemplate:
+		if config.Template == "" {
+			return nil, fmt.Errorf("operation_id.template is required for the template strategy")
+		}
+		tmpl, err := template.New("operation_id").Funcs(template.FuncMap{
+			"lower": strings.ToLower,
+			"upper": strings.ToUpper,
+			"title": capitalize,
+			"camel": camelIdentifier,
+		}).Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation_id template: %v", err)
+		}
+		generator.tmpl = tmpl
+	default:
+		return nil, fmt.Errorf("invalid operation_id strategy '%s' (valid: funcName, methodPath, template)", config.Strategy)
+	}
+
+	return generator, nil
+}
+
+// Generate returns the operationId for a route
+func (g *OperationIDGenerator) Generate(method, path, funcName, pkgName string) string {
+	funcName = operationFuncName(funcName)
+	switch g.strategy {
+	case OperationIDStrategyDefault, OperationIDStrategyFuncName:
+		if funcName != "" {
+			return funcName
+		}
+		return methodPathOperationID(method, path)
+	case OperationIDStrategyMethodPath:
+		return methodPathOperationID(method, path)
+	case OperationIDStrategyTemplate:
+		var buf bytes.Buffer
+		data := OperationIDData{
+			Method:      method,
+			Path:        path,
+			FuncName:    funcName,
+			PackageName: pkgName,
+			PathCamel:   pathCamel(path),
+		}
+		if err := g.tmpl.Execute(&buf, data); err != nil || strings.TrimSpace(buf.String()) == "" {
+			return methodPathOperationID(method, path)
+		}
+		return strings.TrimSpace(buf.String())
+	}
+
+	return methodPathOperationID(method, path)
+}
+
+// operationFuncName handler name as used in operationIds: controller methods drop the dot,
+// UserController.Get -> UserControllerGet
+func operationFuncName(funcName string) string {
+	return strings.ReplaceAll(funcName, ".", "")
+}
+
+// methodPathOperationID builds ids such as putUsersById from the method and path
+func methodPathOperationID(method, path string) string {
+	return strings.ToLower(method) + pathCamel(path)
+}
+
+// pathCamel converts a route path to camel case, turning parameters into "By<Name>"
+func pathCamel(path string) string {
+	var result strings.Builder
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		isParam := strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") ||
+			(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+		name := strings.Trim(segment, ":*{}")
+		if isParam {
+			result.WriteString("By")
+		}
+		result.WriteString(capitalize(camelIdentifier(name)))
+	}
+	return result.String()
+}
+
+// camelIdentifier joins words separated by non-alphanumeric characters in camel case
+func camelIdentifier(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i := 1; i < len(words); i++ {
+		words[i] = capitalize(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+// capitalize upper-cases the first letter of a string
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// assignOperationIDs records the operationId of each route, on the path it is served at, for the
+// generated registrations
+func assignOperationIDs(routes []*RouteMeta, config OperationIDConfig) error {
+	generator, err := NewOperationIDGenerator(config)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		if route.Method == "" || route.Path == "" {
+			continue
+		}
+		path := route.Path
+		if route.Group != nil && route.Group.Prefix != "" && !strings.HasPrefix(path, route.Group.Prefix) {
+			path = route.Group.Prefix + path
+		}
+		route.OperationID = routeOperationID(generator, route, path)
+	}
+	return nil
+}
+
+// routeOperationID the operationId of a route served at path. The further methods and paths of a handler
+// bound several times would share the id of its first one under the funcName strategy, so they are
+// suffixed with their method and path (HealthHeadHealthz).
+func routeOperationID(generator *OperationIDGenerator, route *RouteMeta, path string) string {
+	id := generator.Generate(route.Method, path, route.FuncName, route.PackageName)
+	if route.Binding > 0 && id == operationFuncName(route.FuncName) {
+		id += capitalize(methodPathOperationID(route.Method, path))
+	}
+	return id
+}
+
+// DetectOperationIDCollisions fails when two routes would produce the same operationId
+func DetectOperationIDCollisions(routes []*RouteMeta, config OperationIDConfig) error {
+	generator, err := NewOperationIDGenerator(config)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string][]string)
+	for _, route := range routes {
+		if route.Method == "" || route.Path == "" {
+			continue
+		}
+		id := routeOperationID(generator, route, route.Path)
+		owners[id] = append(owners[id], fmt.Sprintf("%s %s (%s:%d)", route.Method, route.Path, route.FileName, route.Line))
+	}
+
+	return operationIDCollisionError(owners)
+}
+
+// operationIDCollisionError reports every operationId claimed by more than one route
+func operationIDCollisionError(owners map[string][]string) error {
+	var messages []string
+	for id, locations := range owners {
+		if len(locations) < 2 {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("duplicate operationId '%s': %s", id, strings.Join(locations, ", ")))
+	}
+
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return fmt.Errorf("operationId collisions found:\n%s", strings.Join(messages, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationIDStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   OperationIDConfig
+		method   string
+		path     string
+		funcName string
+		expected string
+	}{
+		{"default uses func name", OperationIDConfig{}, "GET", "/users", "ListUsers", "ListUsers"},
+		{"default method path fallback", OperationIDConfig{}, "PUT", "/users/{id}", "", "putUsersById"},
+		{"func name", OperationIDConfig{Strategy: "funcName"}, "GET", "/users", "ListUsers", "ListUsers"},
+		{"func name fallback", OperationIDConfig{Strategy: "funcName"}, "GET", "/users", "", "getUsers"},
+		{"method path braces", OperationIDConfig{Strategy: "methodPath"}, "PUT", "/users/{id}", "UpdateUser", "putUsersById"},
+		{"method path gin params", OperationIDConfig{Strategy: "methodPath"}, "GET", "/api/v1/user-groups/:group_id/members", "", "getApiV1UserGroupsByGroupIdMembers"},
+		{"template", OperationIDConfig{Strategy: "template", Template: "{{ .PackageName }}{{ title .FuncName }}"}, "GET", "/users", "list", "handlersList"},
+		{"template path camel", OperationIDConfig{Strategy: "template", Template: "{{ lower .Method }}{{ .PathCamel }}V2"}, "DELETE", "/users/:id", "", "deleteUsersByIdV2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewOperationIDGenerator(tt.config)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, generator.Generate(tt.method, tt.path, tt.funcName, "handlers"))
+		})
+	}
+}
+
+func TestNewOperationIDGeneratorErrors(t *testing.T) {
+	_, err := NewOperationIDGenerator(OperationIDConfig{Strategy: "random"})
+	assert.Error(t, err)
+
+	_, err = NewOperationIDGenerator(OperationIDConfig{Strategy: "template"})
+	assert.Error(t, err)
+
+	_, err = NewOperationIDGenerator(OperationIDConfig{Strategy: "template", Template: "{{ .Method"})
+	assert.Error(t, err)
+}
+
+func TestDetectOperationIDCollisions(t *testing.T) {
+	routes := []*RouteMeta{
+		{Method: "PUT", Path: "/users/{id}", FuncName: "UpdateUser", FileName: "users.go", Line: 10},
+		{Method: "PUT", Path: "/users/:id", FuncName: "ReplaceUser", FileName: "legacy.go", Line: 20},
+		{Method: "", Path: "", FuncName: "ChatHandler"},
+	}
+
+	assert.NoError(t, DetectOperationIDCollisions(routes, OperationIDConfig{}))
+
+	err := DetectOperationIDCollisions(routes, OperationIDConfig{Strategy: "methodPath"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "putUsersById")
+	assert.Contains(t, err.Error(), "users.go:10")
+	assert.Contains(t, err.Error(), "legacy.go:20")
+
+	assert.Error(t, DetectOperationIDCollisions(routes, OperationIDConfig{Strategy: "invalid"}))
+}
+
+func TestDetectOperationIDCollisions_RouteBindings(t *testing.T) {
+	routes := []*RouteMeta{
+		{Method: "GET", Path: "/health", FuncName: "Health"},
+		{Method: "HEAD", Path: "/health", FuncName: "Health", Binding: 1},
+	}
+	assert.NoError(t, DetectOperationIDCollisions(routes, OperationIDConfig{}))
+	assert.NoError(t, DetectOperationIDCollisions(routes, OperationIDConfig{Strategy: "methodPath"}))
+
+	assert.NoError(t, assignOperationIDs(routes, OperationIDConfig{}))
+	assert.Equal(t, "Health", routes[0].OperationID)
+	assert.Equal(t, "HealthHeadHealth", routes[1].OperationID)
+}
+
+func TestConfigureSpecPathsOperationIDStrategy(t *testing.T) {
+	config := DefaultConfig()
+	config.OpenAPI.OperationID = OperationIDConfig{Strategy: "methodPath"}
+
+	spec := createBaseSpec(config)
+	configureSpecPaths(spec, []RouteEntry{
+		{Method: "GET", Path: "/items/:id", FuncName: "GetItem"},
+		{Method: "GET", Path: "/items/{id}", FuncName: "GetItemLegacy"},
+	}, config)
+
+	// Collisions are reported, never renamed
+	assert.Equal(t, "getItemsById", spec.Paths["/items/:id"]["get"].OperationID)
+	assert.Equal(t, "getItemsById", spec.Paths["/items/{id}"]["get"].OperationID)
+
+	spec = createBaseSpec(nil)
+	configureSpecPaths(spec, []RouteEntry{{Method: "PUT", Path: "/users/{id}"}}, nil)
+	assert.Equal(t, "putUsersById", spec.Paths["/users/{id}"]["put"].OperationID)
+}
+
+func TestGenerateFromTemplateFailsOnOperationIDCollisions(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	sourceDir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/items/:id")
+func GetItem(c *gin.Context) {}
+
+// @Route("GET", "/items/{id}")
+func GetItemLegacy(c *gin.Context) {}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceDir, "items.go"), []byte(source), 0o600))
+	templatePath := filepath.Join(t.TempDir(), "custom.tmpl")
+	assert.NoError(t, os.WriteFile(templatePath, []byte("package {{ .PackageName }}\n"), 0o600))
+
+	config := DefaultConfig()
+	config.Generate.DisableCache = true
+	config.OpenAPI.OperationID = OperationIDConfig{Strategy: "methodPath"}
+
+	outputPath := filepath.Join(t.TempDir(), "out.go")
+	err := GenerateFromTemplateWithConfig(sourceDir, templatePath, outputPath, "deco", config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "getItemsById")
+	_, statErr := os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(statErr))
+}
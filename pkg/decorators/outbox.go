@@ -0,0 +1,479 @@
+package decorators
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Outbox event states
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusPublished = "published"
+	OutboxStatusDead      = "dead" // gave up after max_attempts
+)
+
+// maxOutboxBackoff upper bound of the delay between two publish attempts
+const maxOutboxBackoff = 10 * time.Minute
+
+// outboxTablePattern valid outbox table names (optionally schema-qualified)
+var outboxTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// OutboxEvent event stored in the outbox until it is published
+type OutboxEvent struct {
+	ID        int64
+	Topic     string
+	Key       string // partition/ordering key, optional
+	Payload   []byte
+	Headers   map[string]string // broker message headers, optional
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// Publisher delivers outbox events to the message broker (Kafka, NATS, ...).
+// Delivery is at-least-once: consumers can use the event ID to drop duplicates.
+type Publisher interface {
+	Publish(ctx context.Context, event *OutboxEvent) error
+}
+
+// PublisherFunc adapts a function to Publisher
+type PublisherFunc func(ctx context.Context, event *OutboxEvent) error
+
+// Publish calls f(ctx, event)
+func (f PublisherFunc) Publish(ctx context.Context, event *OutboxEvent) error {
+	return f(ctx, event)
+}
+
+// SQLExecutor runs statements; satisfied by *sql.Tx and *sql.DB
+type SQLExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// OutboxStore persistence of outbox events
+type OutboxStore interface {
+	// Add stores a pending event using exec, normally the handler's transaction
+	Add(ctx context.Context, exec SQLExecutor, event *OutboxEvent) error
+	// Pending returns up to limit events due for publishing, oldest first
+	Pending(ctx context.Context, limit int) ([]*OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt; dead events are not retried anymore
+	MarkFailed(ctx context.Context, id int64, attempts int, nextAttempt time.Time, lastError string, dead bool) error
+}
+
+// withDefaults fills unset fields with DefaultConfig values
+func (o OutboxConfig) withDefaults() OutboxConfig {
+	defaults := DefaultConfig().Outbox
+	if o.Table == "" {
+		o.Table = defaults.Table
+	}
+	if o.Dialect == "" {
+		o.Dialect = defaults.Dialect
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaults.BatchSize
+	}
+	if o.PollInterval == "" {
+		o.PollInterval = defaults.PollInterval
+	}
+	if o.MaxAttempts == 0 {
+		o.MaxAttempts = defaults.MaxAttempts
+	}
+	if o.RetryBackoff == "" {
+		o.RetryBackoff = defaults.RetryBackoff
+	}
+	return o
+}
+
+// validate validates the outbox configuration
+func (o OutboxConfig) validate() error {
+	if o.Table != "" && !outboxTablePattern.MatchString(o.Table) {
+		return fmt.Errorf("invalid outbox.table '%s'", o.Table)
+	}
+	switch o.Dialect {
+	case "", "postgres", "mysql", "sqlite":
+	default:
+		return fmt.Errorf("invalid outbox.dialect '%s' (valid: postgres, mysql, sqlite)", o.Dialect)
+	}
+	if o.BatchSize < 0 || o.MaxAttempts < 0 {
+		return fmt.Errorf("outbox.batch_size and outbox.max_attempts must not be negative")
+	}
+	for name, value := range map[string]string{"poll_interval": o.PollInterval, "retry_backoff": o.RetryBackoff} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("invalid outbox.%s '%s'", name, value)
+		}
+	}
+	return nil
+}
+
+// Outbox writes events in the caller's transaction and publishes them in the background,
+// so an event is never lost when the transaction commits nor sent when it rolls back.
+type Outbox struct {
+	store        OutboxStore
+	publisher    Publisher
+	batchSize    int
+	maxAttempts  int
+	pollInterval time.Duration
+	retryBackoff time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOutbox creates an outbox dispatching the events of store through publisher
+func NewOutbox(store OutboxStore, publisher Publisher, config OutboxConfig) (*Outbox, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config = config.withDefaults()
+
+	pollInterval, _ := time.ParseDuration(config.PollInterval)
+	retryBackoff, _ := time.ParseDuration(config.RetryBackoff)
+	return &Outbox{
+		store:        store,
+		publisher:    publisher,
+		batchSize:    config.BatchSize,
+		maxAttempts:  config.MaxAttempts,
+		pollInterval: pollInterval,
+		retryBackoff: retryBackoff,
+	}, nil
+}
+
+// Enqueue records an event in the transaction exec; it is published after the commit.
+// Payloads other than []byte are encoded as JSON.
+func (o *Outbox) Enqueue(ctx context.Context, exec SQLExecutor, topic, key string, payload interface{}) error {
+	if topic == "" {
+		return fmt.Errorf("outbox: topic is required")
+	}
+
+	data, ok := payload.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("outbox: error encoding payload: %v", err)
+		}
+	}
+
+	// Consumers continue the trace of the request that produced the event
+	headers := make(map[string]string)
+	InjectContext(ctx, propagation.MapCarrier(headers))
+	if len(headers) == 0 {
+		headers = nil
+	}
+
+	return o.store.Add(ctx, exec, &OutboxEvent{Topic: topic, Key: key, Payload: data, Headers: headers, CreatedAt: time.Now()})
+}
+
+// DispatchOnce publishes one batch of due events and returns how many were published
+func (o *Outbox) DispatchOnce(ctx context.Context) (int, error) {
+	events, err := o.store.Pending(ctx, o.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("outbox: error loading pending events: %v", err)
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := o.publisher.Publish(ctx, event); err != nil {
+			attempts := event.Attempts + 1
+			dead := attempts >= o.maxAttempts
+			if dead {
+				LogNormal("❌ Outbox: event %d (%s) dropped after %d attempts: %v", event.ID, event.Topic, attempts, err)
+			}
+			if markErr := o.store.MarkFailed(ctx, event.ID, attempts, time.Now().Add(o.backoff(attempts)), err.Error(), dead); markErr != nil {
+				return published, fmt.Errorf("outbox: error recording failed attempt: %v", markErr)
+			}
+			continue
+		}
+
+		if err := o.store.MarkPublished(ctx, event.ID); err != nil {
+			return published, fmt.Errorf("outbox: error marking event %d as published: %v", event.ID, err)
+		}
+		published++
+	}
+	return published, nil
+}
+
+// backoff delay before the next attempt, doubled per failed attempt
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.retryBackoff
+	for i := 1; i < attempts && delay < maxOutboxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxOutboxBackoff)
+}
+
+// Start runs the dispatcher in the background until Stop is called
+func (o *Outbox) Start() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.stop != nil {
+		return
+	}
+	o.stop = make(chan struct{})
+	o.done = make(chan struct{})
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(o.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			// Drain full batches right away, then wait for the next poll
+			for {
+				n, err := o.DispatchOnce(context.Background())
+				if err != nil {
+					LogSilent("⚠️  %v", err)
+				}
+				if err != nil || n < o.batchSize {
+					break
+				}
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(o.stop, o.done)
+}
+
+// Stop stops the dispatcher and waits for the current batch to finish
+func (o *Outbox) Stop() {
+	o.mu.Lock()
+	stop, done := o.stop, o.done
+	o.stop, o.done = nil, nil
+	o.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// SQLOutboxStore stores outbox events in a database table
+type SQLOutboxStore struct {
+	db      *sql.DB
+	queries outboxQueries
+}
+
+// outboxQueries statements of an outbox table in one SQL dialect
+type outboxQueries struct {
+	create    string
+	insert    string
+	pending   string
+	published string
+	failed    string
+}
+
+// newOutboxQueries builds the statements of table for dialect
+func newOutboxQueries(table, dialect string) outboxQueries {
+	// Placeholders are $n in postgres and ? elsewhere
+	p := func(n int) string {
+		if dialect == "postgres" {
+			return fmt.Sprintf("$%d", n)
+		}
+		return "?"
+	}
+
+	idColumn, payloadType := "BIGSERIAL PRIMARY KEY", "BYTEA"
+	switch dialect {
+	case "mysql":
+		idColumn, payloadType = "BIGINT AUTO_INCREMENT PRIMARY KEY", "LONGBLOB"
+	case "sqlite":
+		idColumn, payloadType = "INTEGER PRIMARY KEY AUTOINCREMENT", "BLOB"
+	}
+
+	return outboxQueries{
+		create: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id %s,
+	topic VARCHAR(255) NOT NULL,
+	event_key VARCHAR(255) NOT NULL,
+	payload %s NOT NULL,
+	headers TEXT,
+	status VARCHAR(16) NOT NULL,
+	attempts INT NOT NULL,
+	last_error TEXT,
+	next_attempt_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	published_at TIMESTAMP NULL
+)`, table, idColumn, payloadType),
+		insert: fmt.Sprintf(`INSERT INTO %s (topic, event_key, payload, headers, status, attempts, next_attempt_at, created_at) VALUES (%s, %s, %s, %s, '%s', 0, %s, %s)`,
+			table, p(1), p(2), p(3), p(4), OutboxStatusPending, p(5), p(6)),
+		pending: fmt.Sprintf(`SELECT id, topic, event_key, payload, headers, attempts, created_at FROM %s WHERE status = '%s' AND next_attempt_at <= %s ORDER BY id LIMIT %s`,
+			table, OutboxStatusPending, p(1), p(2)),
+		published: fmt.Sprintf(`UPDATE %s SET status = '%s', published_at = %s WHERE id = %s`,
+			table, OutboxStatusPublished, p(1), p(2)),
+		failed: fmt.Sprintf(`UPDATE %s SET status = %s, attempts = %s, next_attempt_at = %s, last_error = %s WHERE id = %s`,
+			table, p(1), p(2), p(3), p(4), p(5)),
+	}
+}
+
+// NewSQLOutboxStore creates an outbox store over db; call CreateTable or create the table in a migration
+func NewSQLOutboxStore(db *sql.DB, config OutboxConfig) (*SQLOutboxStore, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	config = config.withDefaults()
+	return &SQLOutboxStore{db: db, queries: newOutboxQueries(config.Table, config.Dialect)}, nil
+}
+
+// CreateTable creates the outbox table if it does not exist
+func (s *SQLOutboxStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, s.queries.create)
+	return err
+}
+
+// Add inserts a pending event with exec (the handler's transaction); a nil exec uses the database directly
+func (s *SQLOutboxStore) Add(ctx context.Context, exec SQLExecutor, event *OutboxEvent) error {
+	if exec == nil {
+		exec = s.db
+	}
+	var headers sql.NullString
+	if len(event.Headers) > 0 {
+		data, err := json.Marshal(event.Headers)
+		if err != nil {
+			return err
+		}
+		headers = sql.NullString{String: string(data), Valid: true}
+	}
+	_, err := exec.ExecContext(ctx, s.queries.insert, event.Topic, event.Key, event.Payload, headers, event.CreatedAt, event.CreatedAt)
+	return err
+}
+
+// Pending returns up to limit events due for publishing
+func (s *SQLOutboxStore) Pending(ctx context.Context, limit int) ([]*OutboxEvent, error) {
+	rows, err := s.db.QueryContext(ctx, s.queries.pending, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*OutboxEvent
+	for rows.Next() {
+		event := &OutboxEvent{}
+		var headers sql.NullString
+		if err := rows.Scan(&event.ID, &event.Topic, &event.Key, &event.Payload, &headers, &event.Attempts, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if headers.Valid && headers.String != "" {
+			if err := json.Unmarshal([]byte(headers.String), &event.Headers); err != nil {
+				return nil, fmt.Errorf("invalid headers of outbox event %d: %v", event.ID, err)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkPublished marks an event as published
+func (s *SQLOutboxStore) MarkPublished(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, s.queries.published, time.Now(), id)
+	return err
+}
+
+// MarkFailed records a failed publish attempt
+func (s *SQLOutboxStore) MarkFailed(ctx context.Context, id int64, attempts int, nextAttempt time.Time, lastError string, dead bool) error {
+	status := OutboxStatusPending
+	if dead {
+		status = OutboxStatusDead
+	}
+	_, err := s.db.ExecContext(ctx, s.queries.failed, status, attempts, nextAttempt, lastError, id)
+	return err
+}
+
+// MemoryOutboxStore in-memory outbox store for development and tests; it is not transactional
+type MemoryOutboxStore struct {
+	mu     sync.Mutex
+	nextID int64
+	events map[int64]*memoryOutboxEntry
+}
+
+// memoryOutboxEntry event and delivery state kept by MemoryOutboxStore
+type memoryOutboxEntry struct {
+	event       OutboxEvent
+	status      string
+	nextAttempt time.Time
+}
+
+// NewMemoryOutboxStore creates an in-memory outbox store
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{events: make(map[int64]*memoryOutboxEntry)}
+}
+
+// Add stores a pending event; exec is ignored
+func (m *MemoryOutboxStore) Add(_ context.Context, _ SQLExecutor, event *OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	stored := *event
+	stored.ID = m.nextID
+	m.events[stored.ID] = &memoryOutboxEntry{event: stored, status: OutboxStatusPending, nextAttempt: stored.CreatedAt}
+	return nil
+}
+
+// Pending returns up to limit events due for publishing, oldest first
+func (m *MemoryOutboxStore) Pending(_ context.Context, limit int) ([]*OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var events []*OutboxEvent
+	for id := int64(1); id <= m.nextID && len(events) < limit; id++ {
+		entry, exists := m.events[id]
+		if exists && entry.status == OutboxStatusPending && !entry.nextAttempt.After(now) {
+			event := entry.event
+			events = append(events, &event)
+		}
+	}
+	return events, nil
+}
+
+// MarkPublished marks an event as published
+func (m *MemoryOutboxStore) MarkPublished(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.events[id]; exists {
+		entry.status = OutboxStatusPublished
+	}
+	return nil
+}
+
+// MarkFailed records a failed publish attempt
+func (m *MemoryOutboxStore) MarkFailed(_ context.Context, id int64, attempts int, nextAttempt time.Time, lastError string, dead bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.events[id]; exists {
+		entry.event.Attempts = attempts
+		entry.event.LastError = lastError
+		entry.nextAttempt = nextAttempt
+		if dead {
+			entry.status = OutboxStatusDead
+		}
+	}
+	return nil
+}
+
+// Status returns the state of an event ("" when unknown)
+func (m *MemoryOutboxStore) Status(id int64) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.events[id]; exists {
+		return entry.status
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_DispatchOnce(t *testing.T) {
+	store := NewMemoryOutboxStore()
+	var published []*OutboxEvent
+	outbox, err := NewOutbox(store, PublisherFunc(func(_ context.Context, event *OutboxEvent) error {
+		published = append(published, event)
+		return nil
+	}), OutboxConfig{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, outbox.Enqueue(ctx, nil, "user.created", "42", map[string]int{"id": 42}))
+	require.NoError(t, outbox.Enqueue(ctx, nil, "user.deleted", "", []byte("raw")))
+	assert.Error(t, outbox.Enqueue(ctx, nil, "", "", nil))
+
+	n, err := outbox.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.Len(t, published, 2)
+	assert.Equal(t, "user.created", published[0].Topic)
+	assert.JSONEq(t, `{"id":42}`, string(published[0].Payload))
+	assert.Equal(t, []byte("raw"), published[1].Payload)
+	assert.Equal(t, OutboxStatusPublished, store.Status(published[0].ID))
+
+	// Published events are not sent again
+	n, err = outbox.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestOutbox_RetriesAndGivesUp(t *testing.T) {
+	store := NewMemoryOutboxStore()
+	attempts := 0
+	outbox, err := NewOutbox(store, PublisherFunc(func(context.Context, *OutboxEvent) error {
+		attempts++
+		return errors.New("broker unavailable")
+	}), OutboxConfig{MaxAttempts: 2, RetryBackoff: "1ms"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, outbox.Enqueue(ctx, nil, "user.created", "", "{}"))
+
+	_, err = outbox.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, OutboxStatusPending, store.Status(1))
+
+	// Not due until the backoff elapses
+	time.Sleep(5 * time.Millisecond)
+	_, err = outbox.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, OutboxStatusDead, store.Status(1))
+
+	_, err = outbox.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	outbox, err := NewOutbox(NewMemoryOutboxStore(), nil, OutboxConfig{RetryBackoff: "1s"})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, outbox.backoff(1))
+	assert.Equal(t, 4*time.Second, outbox.backoff(3))
+	assert.Equal(t, maxOutboxBackoff, outbox.backoff(50))
+}
+
+func TestOutbox_StartStop(t *testing.T) {
+	store := NewMemoryOutboxStore()
+	sent := make(chan string, 1)
+	outbox, err := NewOutbox(store, PublisherFunc(func(_ context.Context, event *OutboxEvent) error {
+		sent <- event.Topic
+		return nil
+	}), OutboxConfig{PollInterval: "5ms"})
+	require.NoError(t, err)
+
+	outbox.Start()
+	defer outbox.Stop()
+	require.NoError(t, outbox.Enqueue(context.Background(), nil, "order.paid", "", "{}"))
+
+	select {
+	case topic := <-sent:
+		assert.Equal(t, "order.paid", topic)
+	case <-time.After(time.Second):
+		t.Fatal("event was not dispatched")
+	}
+}
+
+func TestNewOutboxQueries(t *testing.T) {
+	postgres := newOutboxQueries("deco_outbox", "postgres")
+	assert.Contains(t, postgres.insert, "VALUES ($1, $2, $3, $4, 'pending', 0, $5, $6)")
+	assert.Contains(t, postgres.create, "BIGSERIAL PRIMARY KEY")
+	assert.Contains(t, postgres.pending, "LIMIT $2")
+
+	mysql := newOutboxQueries("events.outbox", "mysql")
+	assert.True(t, strings.HasPrefix(mysql.create, "CREATE TABLE IF NOT EXISTS events.outbox"))
+	assert.Contains(t, mysql.failed, "WHERE id = ?")
+	assert.NotContains(t, mysql.insert, "$")
+}
+
+func TestOutboxConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Outbox.validate())
+	assert.NoError(t, OutboxConfig{}.validate())
+
+	for _, config := range []OutboxConfig{
+		{Table: "outbox; DROP TABLE users"},
+		{Dialect: "oracle"},
+		{PollInterval: "often"},
+		{RetryBackoff: "-1s"},
+		{MaxAttempts: -1},
+	} {
+		assert.Error(t, config.validate(), "config %+v", config)
+		_, err := NewSQLOutboxStore(nil, config)
+		assert.Error(t, err)
+	}
+}
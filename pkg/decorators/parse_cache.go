@@ -0,0 +1,340 @@
+package decorators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// parseCacheVersion invalidates cached results when the on-disk entry format changes.
+// Changes to the extraction logic itself are covered by extractorVersion.
+const parseCacheVersion = 8
+
+// decoModulePath is used to find the deco version in the build info
+const decoModulePath = "github.com/RodolfoBonis/deco"
+
+var (
+	extractorVersionOnce  sync.Once
+	extractorVersionValue string
+)
+
+// FileParseResult holds everything extracted from a single source file
+type FileParseResult struct {
+	Package     string            `json:"package"`
+	Routes      []*RouteMeta      `json:"routes,omitempty"`
+	Entities    []*EntityMeta     `json:"entities,omitempty"`
+	Controllers []*ControllerInfo `json:"controllers,omitempty"`
+	Errors      []ValidationError `json:"errors,omitempty"`
+}
+
+// parseCacheFileName name of the cache file in the cache directory
+const parseCacheFileName = "cache.json"
+
+// parseCacheEntry cached result of one source file, kept serialized so each lookup returns a copy
+// the generation can modify
+type parseCacheEntry struct {
+	Hash   string          `json:"hash"`
+	Result json.RawMessage `json:"result"`
+}
+
+// parseCacheIndex is the on-disk representation of the cache: the results of every parsed file,
+// keyed by absolute path
+type parseCacheIndex struct {
+	Version int                        `json:"version"`
+	Files   map[string]parseCacheEntry `json:"files"`
+}
+
+// ParseCache caches per-file extraction results keyed by content hash. The results are kept in memory,
+// so repeated generations in one process (deco dev) only hash the files, and are persisted in a single
+// cache.json written once per generation.
+type ParseCache struct {
+	dir    string
+	mutex  sync.Mutex
+	files  map[string]parseCacheEntry // nil until loaded
+	dirty  bool
+	hits   int
+	misses int
+}
+
+var (
+	sharedParseCaches     = make(map[string]*ParseCache)
+	sharedParseCachesLock sync.Mutex
+)
+
+// NewParseCache creates a parse cache stored in dir
+func NewParseCache(dir string) *ParseCache {
+	return &ParseCache{dir: dir}
+}
+
+// Dir returns the cache directory
+func (pc *ParseCache) Dir() string {
+	return pc.dir
+}
+
+// Path returns the cache file
+func (pc *ParseCache) Path() string {
+	return filepath.Join(pc.dir, parseCacheFileName)
+}
+
+// load reads the cache file on first use; a missing, corrupt or outdated file starts an empty cache
+func (pc *ParseCache) load() {
+	if pc.files != nil {
+		return
+	}
+	pc.files = make(map[string]parseCacheEntry)
+
+	data, err := os.ReadFile(pc.Path())
+	if err != nil {
+		return
+	}
+	var index parseCacheIndex
+	if err := json.Unmarshal(data, &index); err != nil || index.Version != parseCacheVersion {
+		return
+	}
+	for file, entry := range index.Files {
+		if len(entry.Result) > 0 {
+			pc.files[file] = entry
+		}
+	}
+}
+
+// Lookup returns the cached result for a file if its content is unchanged
+func (pc *ParseCache) Lookup(fileName string, content []byte) (*FileParseResult, bool) {
+	if pc == nil {
+		return nil, false
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.load()
+
+	var result FileParseResult
+	entry, ok := pc.files[absPath(fileName)]
+	if !ok || entry.Hash != contentHash(content) || json.Unmarshal(entry.Result, &result) != nil {
+		pc.misses++
+		return nil, false
+	}
+
+	pc.hits++
+	return &result, true
+}
+
+// Store records the result for a file keyed by its content hash; Save persists it
+func (pc *ParseCache) Store(fileName string, content []byte, result *FileParseResult) {
+	if pc == nil {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		LogVerbose("⚠️  Could not serialize parse cache for %s: %v", fileName, err)
+		return
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.load()
+
+	pc.files[absPath(fileName)] = parseCacheEntry{Hash: contentHash(content), Result: data}
+	pc.dirty = true
+}
+
+// Save writes the cache file when results changed since it was loaded
+func (pc *ParseCache) Save() {
+	if pc == nil {
+		return
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	if !pc.dirty {
+		return
+	}
+
+	if err := os.MkdirAll(pc.dir, 0o755); err != nil {
+		LogVerbose("⚠️  Could not create parse cache directory %s: %v", pc.dir, err)
+		return
+	}
+	data, err := json.Marshal(parseCacheIndex{Version: parseCacheVersion, Files: pc.files})
+	if err != nil {
+		LogVerbose("⚠️  Could not serialize parse cache %s: %v", pc.Path(), err)
+		return
+	}
+
+	// Written to a temporary file and renamed, so a concurrent generation never reads half a cache
+	tmp := pc.Path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		LogVerbose("⚠️  Could not write parse cache %s: %v", pc.Path(), err)
+		return
+	}
+	if err := os.Rename(tmp, pc.Path()); err != nil {
+		LogVerbose("⚠️  Could not write parse cache %s: %v", pc.Path(), err)
+		return
+	}
+	pc.dirty = false
+}
+
+// Stats returns the number of cache hits and misses
+func (pc *ParseCache) Stats() (hits, misses int) {
+	if pc == nil {
+		return 0, 0
+	}
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.hits, pc.misses
+}
+
+// ResetStats zeroes the hit and miss counters, so each generation reports its own
+func (pc *ParseCache) ResetStats() {
+	if pc == nil {
+		return
+	}
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.hits, pc.misses = 0, 0
+}
+
+// Clear removes all cached entries and the cache file
+func (pc *ParseCache) Clear() error {
+	if pc == nil {
+		return nil
+	}
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.files = make(map[string]parseCacheEntry)
+	pc.dirty = false
+	if err := os.Remove(pc.Path()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error clearing parse cache: %v", err)
+	}
+	return nil
+}
+
+// Prune removes entries for files of rootDir that no longer exist, e.g. after a delete or rename
+func (pc *ParseCache) Prune(rootDir string, files []string) int {
+	if pc == nil {
+		return 0
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.load()
+
+	keep := make(map[string]bool, len(files))
+	for _, file := range files {
+		keep[absPath(file)] = true
+	}
+	root := absPath(rootDir)
+
+	pruned := 0
+	for file := range pc.files {
+		if filepath.Dir(file) == root && !keep[file] {
+			delete(pc.files, file)
+			pruned++
+		}
+	}
+	if pruned > 0 {
+		pc.dirty = true
+	}
+	return pruned
+}
+
+// absPath returns the absolute form of path, or path itself when it cannot be resolved
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
+
+// contentHash hashes file content together with the registered markers and the extractor version,
+// since all of them affect extraction
+func contentHash(content []byte) string {
+	hasher := sha256.New()
+	hasher.Write(content)
+	hasher.Write([]byte(strings.Join(registeredMarkerNames(), ",")))
+	hasher.Write([]byte(extractorVersion()))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// extractorVersion identifies the extraction code: the released deco module version when available,
+// otherwise a hash of the running binary, so development builds never reuse stale results
+func extractorVersion() string {
+	extractorVersionOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			modules := append([]*debug.Module{&info.Main}, info.Deps...)
+			for _, module := range modules {
+				if module.Path != decoModulePath || module.Replace != nil {
+					continue
+				}
+				if module.Version != "" && module.Version != "(devel)" {
+					extractorVersionValue = module.Version + module.Sum
+					return
+				}
+			}
+		}
+		extractorVersionValue = executableHash()
+	})
+	return extractorVersionValue
+}
+
+// executableHash hashes the running binary
+func executableHash() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// registeredMarkerNames returns the sorted names of registered markers
+func registeredMarkerNames() []string {
+	registered := GetMarkers()
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseCacheForConfig returns the parse cache configured for an output path, or nil when disabled.
+// The cache of a directory is shared by the generations of the process, so deco dev keeps the
+// results in memory between reloads.
+func parseCacheForConfig(config *Config, outputPath string) *ParseCache {
+	if config == nil || config.Generate.DisableCache {
+		return nil
+	}
+	dir := config.Generate.CacheDir
+	if dir == "" {
+		dir = filepath.Dir(outputPath)
+	}
+
+	sharedParseCachesLock.Lock()
+	defer sharedParseCachesLock.Unlock()
+	cache, ok := sharedParseCaches[absPath(dir)]
+	if !ok {
+		cache = NewParseCache(dir)
+		sharedParseCaches[absPath(dir)] = cache
+	}
+	cache.ResetStats()
+	return cache
+}
@@ -0,0 +1,200 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cachedHandlerSource = `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// CachedItem is returned by the items endpoint
+// @Schema()
+type CachedItem struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+// GetCachedItems lists items
+// @Route("GET", "/cached/items")
+// @Cache(duration="1m")
+func GetCachedItems(c *gin.Context) {}
+`
+
+func TestParseDirectoryWithCache(t *testing.T) {
+	sourceDir := t.TempDir()
+	handlerFile := filepath.Join(sourceDir, "items.go")
+	assert.NoError(t, os.WriteFile(handlerFile, []byte(cachedHandlerSource), 0o600))
+
+	cache := NewParseCache(filepath.Join(t.TempDir(), "cache"))
+
+	// First run populates the cache
+	routes, err := ParseDirectoryWithCache(sourceDir, cache)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	hits, misses := cache.Stats()
+	assert.Equal(t, 0, hits)
+	assert.Equal(t, 1, misses)
+
+	// Second run reuses the cached result, including schemas and middlewares
+	ClearSchemas()
+	routes, err = ParseDirectoryWithCache(sourceDir, cache)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "/cached/items", routes[0].Path)
+	assert.Len(t, routes[0].MiddlewareCalls, 1)
+	assert.NotNil(t, GetSchema("CachedItem"))
+	hits, _ = cache.Stats()
+	assert.Equal(t, 1, hits)
+
+	// Changing the file invalidates its entry
+	changed := []byte(cachedHandlerSource + "\n// trailing comment\n")
+	assert.NoError(t, os.WriteFile(handlerFile, changed, 0o600))
+	_, err = ParseDirectoryWithCache(sourceDir, cache)
+	assert.NoError(t, err)
+	_, misses = cache.Stats()
+	assert.Equal(t, 2, misses)
+
+	// The results are persisted in one file and reused by a new process
+	reloaded := NewParseCache(cache.Dir())
+	routes, err = ParseDirectoryWithCache(sourceDir, reloaded)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	hits, misses = reloaded.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 0, misses)
+
+	assert.NoError(t, cache.Clear())
+	_, err = os.Stat(cache.Path())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseCacheLookupReturnsCopies(t *testing.T) {
+	cache := NewParseCache(t.TempDir())
+	content := []byte("package handlers")
+	cache.Store("items.go", content, &FileParseResult{Routes: []*RouteMeta{{Path: "/items"}}})
+
+	first, ok := cache.Lookup("items.go", content)
+	assert.True(t, ok)
+	first.Routes[0].Path = "/changed"
+
+	second, ok := cache.Lookup("items.go", content)
+	assert.True(t, ok)
+	assert.Equal(t, "/items", second.Routes[0].Path)
+}
+
+func TestParseCacheNil(t *testing.T) {
+	var cache *ParseCache
+
+	result, ok := cache.Lookup("file.go", []byte("package x"))
+	assert.Nil(t, result)
+	assert.False(t, ok)
+
+	cache.Store("file.go", []byte("package x"), &FileParseResult{})
+	hits, misses := cache.Stats()
+	assert.Zero(t, hits)
+	assert.Zero(t, misses)
+	assert.NoError(t, cache.Clear())
+}
+
+func TestParseCacheForConfig(t *testing.T) {
+	config := DefaultConfig()
+	cache := parseCacheForConfig(config, filepath.Join("out", ".deco", "init_decorators.go"))
+	assert.Equal(t, filepath.Join("out", ".deco"), cache.Dir())
+	assert.Equal(t, filepath.Join("out", ".deco", "cache.json"), cache.Path())
+	assert.Same(t, cache, parseCacheForConfig(config, filepath.Join("out", ".deco", "other.go")), "shared by the generations of the process")
+
+	config.Generate.CacheDir = "custom-cache"
+	assert.Equal(t, "custom-cache", parseCacheForConfig(config, "init.go").Dir())
+
+	config.Generate.DisableCache = true
+	assert.Nil(t, parseCacheForConfig(config, "init.go"))
+	assert.Nil(t, parseCacheForConfig(nil, "init.go"))
+}
+
+func TestContentHashIncludesMarkers(t *testing.T) {
+	content := []byte("package handlers")
+	before := contentHash(content)
+	assert.Equal(t, before, contentHash(content))
+
+	RegisterMarker(MarkerConfig{Name: "CacheHashProbe", Pattern: routeRegex})
+	defer delete(markers, "CacheHashProbe")
+
+	assert.NotEqual(t, before, contentHash(content))
+}
+
+func TestParseCachePrunesDeletedFiles(t *testing.T) {
+	sourceDir := t.TempDir()
+	keptFile := filepath.Join(sourceDir, "items.go")
+	removedFile := filepath.Join(sourceDir, "old.go")
+	assert.NoError(t, os.WriteFile(keptFile, []byte(cachedHandlerSource), 0o600))
+	assert.NoError(t, os.WriteFile(removedFile, []byte("package handlers\n"), 0o600))
+
+	cache := NewParseCache(filepath.Join(t.TempDir(), "cache"))
+	_, err := ParseDirectoryWithCache(sourceDir, cache)
+	assert.NoError(t, err)
+
+	_, cached := cache.Lookup(removedFile, []byte("package handlers\n"))
+	assert.True(t, cached)
+
+	// Entries of other directories sharing the cache are left alone
+	otherFile := filepath.Join(t.TempDir(), "other.go")
+	cache.Store(otherFile, []byte("package other"), &FileParseResult{Package: "other"})
+
+	assert.NoError(t, os.Remove(removedFile))
+	_, err = ParseDirectoryWithCache(sourceDir, cache)
+	assert.NoError(t, err)
+
+	_, cached = cache.Lookup(keptFile, []byte(cachedHandlerSource))
+	assert.True(t, cached)
+	_, cached = cache.Lookup(otherFile, []byte("package other"))
+	assert.True(t, cached)
+	_, cached = cache.Lookup(removedFile, []byte("package handlers\n"))
+	assert.False(t, cached)
+}
+
+func TestExtractorVersion(t *testing.T) {
+	assert.NotEmpty(t, extractorVersion())
+	assert.Equal(t, extractorVersion(), extractorVersion())
+}
+
+func TestCreateGitignoreAddsMissingEntries(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), ".deco")
+	assert.NoError(t, os.MkdirAll(outputDir, 0o755))
+	gitignorePath := filepath.Join(outputDir, ".gitignore")
+	assert.NoError(t, os.WriteFile(gitignorePath, []byte("*.go\nsourcemap.json"), 0o600))
+
+	outputPath := filepath.Join(outputDir, "init_decorators.go")
+	assert.NoError(t, createGitignoreIfNeeded(outputPath))
+
+	content, err := os.ReadFile(gitignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "*.go\nsourcemap.json\ncache.json\n", string(content))
+
+	// Running again does not duplicate entries
+	assert.NoError(t, createGitignoreIfNeeded(outputPath))
+	again, _ := os.ReadFile(gitignorePath)
+	assert.Equal(t, string(content), string(again))
+}
+
+func TestGenerateFromTemplateUsesParseCache(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	sourceDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceDir, "items.go"), []byte(cachedHandlerSource), 0o600))
+
+	templatePath := filepath.Join(t.TempDir(), "custom.tmpl")
+	assert.NoError(t, os.WriteFile(templatePath, []byte("package {{ .PackageName }}\n"), 0o600))
+
+	outputPath := filepath.Join(t.TempDir(), ".deco", "init_decorators.go")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(outputPath), 0o755))
+	assert.NoError(t, GenerateFromTemplate(sourceDir, templatePath, outputPath, "deco"))
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(outputPath), "cache.json"))
+	assert.NoError(t, err)
+}
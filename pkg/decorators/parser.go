@@ -5,71 +5,149 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	// Regex to extract route: @Route("METHOD", "path")
+	// Regex to extract route: @Route("METHOD", "path") or @Route("GET,HEAD", "path")
 	routeRegex = regexp.MustCompile(`@Route\s*\(\s*"([^"]+)"\s*,\s*"([^"]+)"\s*\)`)
+	// Any @Route call, to report those routeRegex does not match
+	routeCallRegex = regexp.MustCompile(`@Route\s*\(`)
 )
 
 // ParseDirectory analyzes a directory and extracts route metadata
 func ParseDirectory(rootDir string) ([]*RouteMeta, error) {
+	return ParseDirectoryWithCache(rootDir, nil)
+}
+
+// ParseDirectoryWithCache analyzes a directory reusing cached per-file results when the content is unchanged
+func ParseDirectoryWithCache(rootDir string, cache *ParseCache) ([]*RouteMeta, error) {
+	routes, _, err := parseDirectory(rootDir, cache)
+	return routes, err
+}
+
+// ParseDirectoryWithSchemas analyzes a directory like ParseDirectory and also returns the schemas of
+// the entities it declares, in source order
+func ParseDirectoryWithSchemas(rootDir string) ([]*RouteMeta, []*SchemaInfo, error) {
+	return parseDirectory(rootDir, nil)
+}
+
+// parseDirectory parses the routes and entity schemas of a directory, registering the schemas
+func parseDirectory(rootDir string, cache *ParseCache) ([]*RouteMeta, []*SchemaInfo, error) {
 	var routes []*RouteMeta
+	var schemas []*SchemaInfo
 	var parseErrors []ValidationError
+	controllers := make(map[string]*ControllerInfo)
 
-	// Parse directory
-	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, rootDir, nil, parser.ParseComments)
+	files, err := listGoFiles(rootDir)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing do directory %s: %v", rootDir, err)
+		return nil, nil, fmt.Errorf("error parsing do directory %s: %v", rootDir, err)
 	}
 
-	// Process each package
-	for pkgName, pkg := range pkgs {
-		// Process each file in package
-		for fileName, file := range pkg.Files {
-			fileRoutes, errs := parseFileWithValidation(fset, fileName, file, pkgName)
+	// Marker plugin packages blank-imported by the handlers register their markers first
+	if err := LoadMarkerPlugins(rootDir, blankImportedPackages(files)); err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+
+	// Process each file in the directory
+	for _, fileName := range files {
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing do directory %s: %v", rootDir, err)
+		}
+
+		result, cached := cache.Lookup(fileName, content)
+		if !cached {
+			file, err := parser.ParseFile(fset, fileName, content, parser.ParseComments)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error parsing do directory %s: %v", rootDir, err)
+			}
+
+			result = extractFileResult(fset, fileName, file, file.Name.Name)
+			cache.Store(fileName, content, result)
+		}
 
-			routes = append(routes, fileRoutes...)
-			parseErrors = append(parseErrors, errs...)
+		schemas = append(schemas, registerEntitySchemas(result.Entities)...)
+		routes = append(routes, result.Routes...)
+		parseErrors = append(parseErrors, result.Errors...)
+		for _, controller := range result.Controllers {
+			controllers[result.Package+"."+controller.Type] = controller
 		}
 	}
 
+	// Drop entries of files that were deleted or renamed
+	if pruned := cache.Prune(rootDir, files); pruned > 0 {
+		LogVerbose("🗃️  Parse cache: pruned %d stale entries", pruned)
+	}
+	cache.Save()
+
 	// Report any parsing errors found
 	if len(parseErrors) > 0 {
-		return routes, &MultipleValidationError{Errors: parseErrors}
+		return routes, schemas, &MultipleValidationError{Errors: parseErrors}
 	}
 
+	// Request and response types from the type-checked package when it loads
+	inferPackageTypes(rootDir, routes)
+
+	// Methods of @Controller structs take the prefix of their type
+	applyControllers(routes, controllers)
+
 	// Process middlewares for each route
 	for _, route := range routes {
 		if err := processMiddlewares(route); err != nil {
-			return nil, fmt.Errorf("error processing middlewares para %s: %v", route.FuncName, err)
+			return nil, nil, fmt.Errorf("error processing middlewares para %s: %v", route.FuncName, err)
 		}
 	}
 
-	return routes, nil
+	return routes, schemas, nil
+}
+
+// listGoFiles lists the Go files of a directory in a stable order
+func listGoFiles(rootDir string) ([]string, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		files = append(files, filepath.Join(rootDir, entry.Name()))
+	}
+
+	sort.Strings(files)
+	return files, nil
 }
 
 // parseFileWithValidation analyzes a specific file and validates decorators
 func parseFileWithValidation(fset *token.FileSet, fileName string, file *ast.File, pkgName string) ([]*RouteMeta, []ValidationError) {
-	var routes []*RouteMeta
-	var parseErrors []ValidationError
+	result := extractFileResult(fset, fileName, file, pkgName)
+	registerEntitySchemas(result.Entities)
+	return result.Routes, result.Errors
+}
+
+// extractFileResult extracts routes and schema entities from a file without side effects
+func extractFileResult(fset *token.FileSet, fileName string, file *ast.File, pkgName string) *FileParseResult {
+	result := &FileParseResult{Package: pkgName}
 
 	// Process each declaration in the file
 	for _, decl := range file.Decls {
 		// Look for functions
 		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			route, err := parseFunctionWithValidation(fset, fileName, funcDecl, pkgName)
-			if route != nil {
-				routes = append(routes, route)
-			}
+			routes, err := parseFunctionRoutes(fset, fileName, funcDecl, pkgName)
+			result.Routes = append(result.Routes, routes...)
 			if err != nil {
-				parseErrors = append(parseErrors, *err)
+				result.Errors = append(result.Errors, *err)
 			}
 		}
 
@@ -77,19 +155,48 @@ func parseFileWithValidation(fset *token.FileSet, fileName string, file *ast.Fil
 		if genDecl, ok := decl.(*ast.GenDecl); ok {
 			entity := parseEntityFromStruct(fset, fileName, genDecl, pkgName)
 			if entity != nil {
-				// Convert entity to schema and register it
-				schema := convertEntityToSchema(entity)
-				RegisterSchema(schema)
-				LogVerbose("Schema detected and registered: %s", schema.Name)
+				result.Entities = append(result.Entities, entity)
+			}
+
+			// Structs whose methods are handlers
+			controller, err := parseControllerFromType(fset, fileName, genDecl)
+			if controller != nil {
+				result.Controllers = append(result.Controllers, controller)
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, *err)
 			}
 		}
 	}
 
-	return routes, parseErrors
+	return result
+}
+
+// registerEntitySchemas converts entities to schemas, registers and returns them
+func registerEntitySchemas(entities []*EntityMeta) []*SchemaInfo {
+	schemas := make([]*SchemaInfo, 0, len(entities))
+	for _, entity := range entities {
+		schema := convertEntityToSchema(entity)
+		RegisterSchema(schema)
+		LogVerbose("Schema detected and registered: %s", schema.Name)
+		schemas = append(schemas, schema)
+	}
+	return schemas
 }
 
-// parseFunctionWithValidation analyzes a function and extracts metadata with validation
+// parseFunctionWithValidation analyzes a function and extracts metadata with validation; a handler bound
+// to several methods or paths is returned with its first binding
 func parseFunctionWithValidation(fset *token.FileSet, fileName string, funcDecl *ast.FuncDecl, pkgName string) (*RouteMeta, *ValidationError) {
+	routes, err := parseFunctionRoutes(fset, fileName, funcDecl, pkgName)
+	if len(routes) == 0 {
+		return nil, err
+	}
+	return routes[0], err
+}
+
+// parseFunctionRoutes analyzes a function and returns one route per method and path it is bound to:
+// @Route("GET,HEAD", "/health") and repeated @Route lines register the same handler several times
+func parseFunctionRoutes(fset *token.FileSet, fileName string, funcDecl *ast.FuncDecl, pkgName string) ([]*RouteMeta, *ValidationError) {
 	// Check if it has comments
 	if funcDecl.Doc == nil {
 		return nil, nil
@@ -119,9 +226,9 @@ func parseFunctionWithValidation(fset *token.FileSet, fileName string, funcDecl
 	}
 
 	// Look for @Route
-	routeMatches := routeRegex.FindStringSubmatch(commentText)
+	routeMatches := routeRegex.FindAllStringSubmatchIndex(commentText, -1)
 
-	if len(routeMatches) != 3 {
+	if len(routeMatches) == 0 || len(routeMatches) < len(routeCallRegex.FindAllStringIndex(commentText, -1)) {
 		if strings.Contains(commentText, "@Route") {
 			pos := fset.Position(funcDecl.Pos())
 			return nil, &ValidationError{
@@ -143,65 +250,117 @@ func parseFunctionWithValidation(fset *token.FileSet, fileName string, funcDecl
 			}
 		}
 
-		// If it has @WebSocket with args but no @Route, create a WebSocket-only meta
-		if hasWebSocketWithArgs {
+		// If it has @WebSocket with args or @Subscribe but no @Route, create a handler-only meta
+		if hasWebSocketWithArgs || hasSubscribe(markers) {
 			route := &RouteMeta{
-				Method:      "", // No HTTP method for pure WebSocket handlers
-				Path:        "", // No HTTP path for pure WebSocket handlers
-				FuncName:    funcDecl.Name.Name,
+				Method:      "", // No HTTP method for pure WebSocket handlers and consumers
+				Path:        "", // No HTTP path for pure WebSocket handlers and consumers
+				FuncName:    handlerFuncName(funcDecl),
 				PackageName: pkgName,
 				FileName:    filepath.Base(fileName),
+				FilePath:    fileName,
+				Line:        fset.Position(funcDecl.Pos()).Line,
 				Markers:     markers,
+				Controller:  handlerController(funcDecl),
 			}
-			return route, nil
+			return []*RouteMeta{route}, nil
 		}
 		return nil, nil // Not a handler
 	}
 
-	method := routeMatches[1]
-	path := routeMatches[2]
-	funcName := funcDecl.Name.Name
+	funcName := handlerFuncName(funcDecl)
 
-	// Validate method
-	validMethods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
-	if !contains(validMethods, method) {
+	if hasSubscribe(markers) {
 		pos := fset.Position(funcDecl.Pos())
 		return nil, &ValidationError{
 			File:    filepath.Base(fileName),
 			Line:    pos.Line,
-			Message: fmt.Sprintf("Invalid HTTP method '%s' in function %s. Valid methods: %v", method, funcName, validMethods),
-			Code:    "INVALID_HTTP_METHOD",
+			Message: fmt.Sprintf("@Subscribe cannot be combined with @Route in function %s: consumers are not HTTP handlers", funcName),
+			Code:    "INVALID_SUBSCRIBE",
 		}
 	}
 
-	// Validate path
-	if !strings.HasPrefix(path, "/") {
-		pos := fset.Position(funcDecl.Pos())
-		return nil, &ValidationError{
-			File:    filepath.Base(fileName),
-			Line:    pos.Line,
-			Message: fmt.Sprintf("Invalid path '%s' in function %s. Path must start with '/'", path, funcName),
-			Code:    "INVALID_PATH",
+	var routes []*RouteMeta
+	bound := make(map[string]bool)
+	for _, match := range routeMatches {
+		methods, path := commentText[match[2]:match[3]], commentText[match[4]:match[5]]
+		line := routeDecoratorLine(fset, funcDecl, commentText, match[0])
+
+		// Validate path
+		if !strings.HasPrefix(path, "/") {
+			return nil, &ValidationError{
+				File:    filepath.Base(fileName),
+				Line:    line,
+				Message: fmt.Sprintf("Invalid path '%s' in function %s. Path must start with '/'", path, funcName),
+				Code:    "INVALID_PATH",
+			}
 		}
-	}
 
-	// Markers already extracted above
+		// {id:int} segments become gin parameters checked at runtime
+		path, pathParams, pathErr := parseTypedPath(path)
+		if pathErr != nil {
+			return nil, &ValidationError{
+				File:    filepath.Base(fileName),
+				Line:    line,
+				Message: fmt.Sprintf("Invalid path in function %s: %v", funcName, pathErr),
+				Code:    "INVALID_PATH",
+			}
+		}
+
+		for _, method := range strings.Split(methods, ",") {
+			method = strings.TrimSpace(method)
 
-	route := &RouteMeta{
-		Method:      method,
-		Path:        path,
-		FuncName:    funcName,
-		PackageName: pkgName,
-		FileName:    filepath.Base(fileName),
-		Markers:     markers,
+			// Validate method
+			validMethods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
+			if !contains(validMethods, method) {
+				return nil, &ValidationError{
+					File:    filepath.Base(fileName),
+					Line:    line,
+					Message: fmt.Sprintf("Invalid HTTP method '%s' in function %s. Valid methods: %v", method, funcName, validMethods),
+					Code:    "INVALID_HTTP_METHOD",
+				}
+			}
+
+			// The same method and path listed twice is registered once
+			if bound[method+" "+path] {
+				continue
+			}
+			bound[method+" "+path] = true
+
+			route := &RouteMeta{
+				Method:      method,
+				Path:        path,
+				PathParams:  pathParams,
+				FuncName:    funcName,
+				PackageName: pkgName,
+				FileName:    filepath.Base(fileName),
+				FilePath:    fileName,
+				Line:        line,
+				Markers:     append([]MarkerInstance(nil), markers...),
+				Binding:     len(routes),
+				Controller:  handlerController(funcDecl),
+			}
+			inferHandlerTypes(route, funcDecl)
+			routes = append(routes, route)
+		}
 	}
 
-	return route, nil
+	return routes, nil
+}
+
+// hasSubscribe checks if the markers declare a message consumer
+func hasSubscribe(markers []MarkerInstance) bool {
+	for _, marker := range markers {
+		if marker.Name == "Subscribe" {
+			return true
+		}
+	}
+	return false
 }
 
 // hasDecoratorAnnotations checks if comment text contains any decorator annotations
 func hasDecoratorAnnotations(commentText string) bool {
-	decorators := []string{"@Route", "@Middleware", "@Response", "@RequestBody", "@Schema", "@Summary", "@Description", "@Tag", "@Validate", "@WebSocket", "@WebSocketStats"}
+	decorators := []string{"@Route", "@Middleware", "@Response", "@RequestBody", "@Schema", "@Summary", "@Description", "@Tag", "@Validate", "@WebSocket", "@WebSocketStats", "@SSE", "@Subscribe"}
 	for _, decorator := range decorators {
 		if strings.Contains(commentText, decorator) {
 			return true
@@ -212,7 +371,8 @@ func hasDecoratorAnnotations(commentText string) bool {
 
 // validateDecoratorSyntax validates the overall syntax of decorators in comments
 func validateDecoratorSyntax(fset *token.FileSet, fileName string, funcDecl *ast.FuncDecl, commentText string) *ValidationError {
-	pos := fset.Position(funcDecl.Pos())
+	// Lines are counted from the first comment line
+	pos := fset.Position(funcDecl.Doc.Pos())
 
 	// Check for common syntax errors
 	lines := strings.Split(commentText, "\n")
@@ -269,59 +429,118 @@ func extractMarkersWithValidation(fset *token.FileSet, fileName string, funcDecl
 	var markers []MarkerInstance
 	pos := fset.Position(funcDecl.Pos())
 
-	// Look for each registered marker
-	for name, config := range GetMarkers() {
-		matches := config.Pattern.FindAllStringSubmatch(commentText, -1)
-		for _, match := range matches {
-			marker := MarkerInstance{
-				Name: name,
-				Raw:  match[0],
+	// Markers are kept in source order, whatever the order of the registry
+	found := getMarkerScanner().scan(commentText)
+
+	for _, hit := range found {
+		name, loc := hit.name, hit.loc
+		// The pattern stops at the first ')'; quoted values and nested parentheses may contain more
+		if len(loc) >= 4 && loc[2] >= 0 && loc[3] < len(commentText) && commentText[loc[3]] == ')' {
+			if end := markerArgumentsEnd(commentText, loc[2]); end > loc[3] {
+				loc = append([]int{loc[0], end + 1, loc[2], end}, loc[4:]...)
 			}
+		}
+		match := submatchStrings(commentText, loc)
+		marker := MarkerInstance{
+			Name: name,
+			Raw:  match[0],
+			Line: commentLine(fset, funcDecl.Doc, commentText, loc[0]),
+		}
 
-			// Extract arguments if they exist
-			if len(match) > 1 && match[1] != "" {
-				args, err := parseArgumentsWithValidation(match[1], name)
-				if err != nil {
-					return nil, &ValidationError{
-						File:    filepath.Base(fileName),
-						Line:    pos.Line,
-						Message: fmt.Sprintf("Error in @%s decorator arguments: %s", name, err.Error()),
-						Code:    "INVALID_ARGUMENTS",
-					}
+		// Extract arguments if they exist
+		if len(match) > 1 && match[1] != "" {
+			args, err := parseArgumentsWithValidation(match[1], name)
+			if err != nil {
+				line := marker.Line
+				if line == 0 {
+					line = pos.Line
+				}
+				return nil, &ValidationError{
+					File:    filepath.Base(fileName),
+					Line:    line,
+					Message: fmt.Sprintf("Error in @%s decorator arguments: %s", name, err.Error()),
+					Code:    "INVALID_ARGUMENTS",
 				}
-				marker.Args = args
 			}
-
-			markers = append(markers, marker)
+			marker.Args = args
 		}
+
+		markers = append(markers, marker)
 	}
 
 	return markers, nil
 }
 
+// submatchStrings converts a submatch index slice into the matched strings
+func submatchStrings(text string, loc []int) []string {
+	match := make([]string, len(loc)/2)
+	for i := range match {
+		if loc[2*i] >= 0 {
+			match[i] = text[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return match
+}
+
+// commentLine returns the source line of an offset inside the joined comment text.
+// The text at the offset is located in the raw comments, so multi-line /* */ comments map correctly.
+func commentLine(fset *token.FileSet, doc *ast.CommentGroup, commentText string, offset int) int {
+	if doc == nil || len(doc.List) == 0 {
+		return 0
+	}
+
+	needle := commentText[offset:]
+	if end := strings.IndexByte(needle, '\n'); end >= 0 {
+		needle = needle[:end]
+	}
+	if needle == "" {
+		return fset.Position(doc.Pos()).Line
+	}
+
+	// Skip earlier occurrences of the same text so repeated markers keep their own lines
+	skip := strings.Count(commentText[:offset], needle)
+	for _, comment := range doc.List {
+		searchFrom := 0
+		for {
+			idx := strings.Index(comment.Text[searchFrom:], needle)
+			if idx < 0 {
+				break
+			}
+			idx += searchFrom
+			if skip == 0 {
+				return fset.Position(comment.Pos()).Line + strings.Count(comment.Text[:idx], "\n")
+			}
+			skip--
+			searchFrom = idx + len(needle)
+		}
+	}
+
+	return fset.Position(doc.Pos()).Line
+}
+
+// routeDecoratorLine returns the line of the @Route decorator at offset of the comment text, falling back
+// to the function declaration
+func routeDecoratorLine(fset *token.FileSet, funcDecl *ast.FuncDecl, commentText string, offset int) int {
+	if line := commentLine(fset, funcDecl.Doc, commentText, offset); line > 0 {
+		return line
+	}
+	return fset.Position(funcDecl.Pos()).Line
+}
+
 // parseArgumentsWithValidation converts argument string to slice with validation
 func parseArgumentsWithValidation(argsStr, decoratorName string) ([]string, error) {
 	if argsStr == "" {
 		return nil, nil
 	}
 
-	var args []string
-	parts := strings.Split(argsStr, ",")
-	for _, part := range parts {
-		arg := strings.TrimSpace(part)
-		if arg != "" {
-			// Remove quotes if present
-			if (strings.HasPrefix(arg, "\"") && strings.HasSuffix(arg, "\"")) ||
-				(strings.HasPrefix(arg, "'") && strings.HasSuffix(arg, "'")) {
-				arg = arg[1 : len(arg)-1]
-			}
-
-			// Validate argument is not empty after processing
-			if arg == "" {
-				return nil, fmt.Errorf("empty argument found")
-			}
-
-			args = append(args, arg)
+	args, err := splitMarkerArguments(argsStr)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		// Validate argument is not empty after processing
+		if arg == "" {
+			return nil, fmt.Errorf("empty argument found")
 		}
 	}
 
@@ -330,9 +549,136 @@ func parseArgumentsWithValidation(argsStr, decoratorName string) ([]string, erro
 		return nil, err
 	}
 
+	// Validate argument values that would otherwise only fail at runtime
+	if err := validateArgumentValues(decoratorName, args); err != nil {
+		return nil, err
+	}
+
 	return args, nil
 }
 
+// validateArgumentValues validates argument values for specific decorators
+func validateArgumentValues(decoratorName string, args []string) error {
+	if err := validateMarkerArgs(decoratorName, args); err != nil {
+		return err
+	}
+
+	switch decoratorName {
+	case "Cache":
+		if _, _, err := parseCacheEncryptionArgs(args); err != nil {
+			return err
+		}
+	case "SlowThreshold":
+		if _, err := parseSlowThresholdArgs(args); err != nil {
+			return err
+		}
+	case "MaxResponseSize":
+		if _, err := parseMaxResponseSizeArgs(args); err != nil {
+			return err
+		}
+	case "MaxBodySize":
+		if _, err := parseMaxBodySizeArgs(args); err != nil {
+			return err
+		}
+	case "NoAccessLog":
+		if len(args) > 0 {
+			return fmt.Errorf("@NoAccessLog takes no arguments, found %d", len(args))
+		}
+	case "Mock":
+		// bodyFrom is resolved once all schemas are registered, in processMiddlewares
+		if _, err := parseMockArgs(args); err != nil {
+			return err
+		}
+	case "Dedupe":
+		if _, err := parseDedupeArgs(args); err != nil {
+			return err
+		}
+	case "Subscribe":
+		if _, err := parseSubscribeArgs(args); err != nil {
+			return err
+		}
+	case "GRPC":
+		if _, err := parseGRPCArgs(args, ""); err != nil {
+			return err
+		}
+	case "Provide":
+		if _, err := parseProvideArgs(args); err != nil {
+			return err
+		}
+	case "SagaStep":
+		if _, err := parseSagaStepArgs(args); err != nil {
+			return err
+		}
+	case "RequireHeader":
+		if _, err := parseRequireHeaderArgs(args); err != nil {
+			return err
+		}
+	case "RequestBody":
+		if _, err := parseRequestBodyArgs(args); err != nil {
+			return err
+		}
+	case "FileParam":
+		if _, err := parseFileParamArgs(args); err != nil {
+			return err
+		}
+	case "Transactional":
+		if _, err := parseTransactionalArgs(args); err != nil {
+			return err
+		}
+	case "Compress":
+		if _, err := parseCompressArgs(args); err != nil {
+			return err
+		}
+	case "Log":
+		if _, err := parseLogArgs(args); err != nil {
+			return err
+		}
+	case "Envelope":
+		if _, err := parseEnvelopeArgs(args); err != nil {
+			return err
+		}
+	case "Deprecated":
+		if _, err := parseDeprecatedArgs(args); err != nil {
+			return err
+		}
+	case "MiddlewareOrder":
+		if _, err := parseMiddlewareOrderArgs(args); err != nil {
+			return err
+		}
+	case "Version":
+		if _, err := parseVersionArgs(args); err != nil {
+			return err
+		}
+	case "Param":
+		if pattern := parseParameterInfo(args).Pattern; pattern != "" {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("@Param: invalid pattern '%s': %v", pattern, err)
+			}
+		}
+	case "FieldACL":
+		if err := validateFieldACLMarker(args); err != nil {
+			return err
+		}
+	case "Sensitive":
+		if _, err := parseSensitiveArgs(args); err != nil {
+			return err
+		}
+	case "SSE":
+		if _, err := parseSSEArgs(args); err != nil {
+			return err
+		}
+	case "CircuitBreaker":
+		if _, err := parseCircuitBreakerArgs(args); err != nil {
+			return err
+		}
+	case "Doc":
+		if _, err := parseDocArgs(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateArgumentCount validates the number of arguments for specific decorators
 func validateArgumentCount(decoratorName string, args []string) error {
 	switch decoratorName {
@@ -381,6 +727,11 @@ func parseArguments(argsStr string) []string {
 		return nil
 	}
 
+	// Generated arguments were validated at generation time; anything else falls back to plain commas
+	if args, err := splitMarkerArguments(argsStr); err == nil {
+		return args
+	}
+
 	var args []string
 	parts := strings.Split(argsStr, ",")
 	for _, part := range parts {
@@ -402,10 +753,66 @@ func processMiddlewares(route *RouteMeta) error {
 	var responses []ResponseInfo
 	var groupInfo *GroupInfo
 
+	if err := validateMockMarkers(route); err != nil {
+		return err
+	}
+
+	if err := validateSubscribeMarker(route); err != nil {
+		return err
+	}
+
+	if err := validateGRPCMarker(route); err != nil {
+		return err
+	}
+
+	if err := validateVersionMarkers(route); err != nil {
+		return err
+	}
+
+	if err := validateProvideMarkers(route); err != nil {
+		return err
+	}
+
 	// Process each marker
+	route.Providers, route.Versions = nil, nil
 	for _, marker := range route.Markers {
 		processMarker(marker, route, &middlewareCalls, &middlewareInfo, &parameters, &tags, &responses, &groupInfo)
 	}
+	parameters, responses = applyInferredTypes(route, parameters, responses)
+
+	// Path parameters are checked before the rest of the chain
+	parameters, pathConstraints, err := typedPathParams(route, parameters)
+	if err != nil {
+		return err
+	}
+	if call := pathParamsMiddlewareCall(pathConstraints); call != "" {
+		middlewareCalls = append([]string{call}, middlewareCalls...)
+		middlewareInfo = append([]MiddlewareInfo{{Name: "PathParams", Description: getMiddlewareDescription("PathParams")}}, middlewareInfo...)
+	}
+
+	// @Doc files are read after the parse cache so markdown edits are picked up
+	if err := loadDocMarkers(route); err != nil {
+		return err
+	}
+
+	// @Sensitive and @FieldACL read the schemas of the body and responses, so they run once all markers are known
+	for _, marker := range route.Markers {
+		switch marker.Name {
+		case "Sensitive":
+			processSensitiveMarker(marker, route, parameters, responses, &middlewareCalls, &middlewareInfo)
+		case "FieldACL":
+			processFieldACLMarker(route, responses, &middlewareCalls, &middlewareInfo)
+		}
+	}
+
+	// Providers come first in the chain, so every middleware can use them too
+	if len(route.Providers) > 0 {
+		providerInfo := make([]MiddlewareInfo, 0, len(route.Providers)+len(middlewareInfo))
+		for _, binding := range route.Providers {
+			providerInfo = append(providerInfo, binding.providerInfo())
+		}
+		middlewareInfo = append(providerInfo, middlewareInfo...)
+	}
 
 	route.MiddlewareCalls = middlewareCalls
 	route.MiddlewareInfo = middlewareInfo
@@ -417,15 +824,35 @@ func processMiddlewares(route *RouteMeta) error {
 	return nil
 }
 
+// validateSubscribeMarker rejects @Subscribe without arguments, which skips argument validation during parsing
+func validateSubscribeMarker(route *RouteMeta) error {
+	for _, marker := range route.Markers {
+		if marker.Name == "Subscribe" {
+			if _, err := parseSubscribeArgs(marker.Args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // processMarker processes a single marker to reduce complexity
 func processMarker(marker MarkerInstance, route *RouteMeta, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo, parameters *[]ParameterInfo, tags *[]string, responses *[]ResponseInfo, groupInfo **GroupInfo) {
 	switch marker.Name {
-	case "Auth", "Cache", "RateLimit", "Metrics", "CORS", "WebSocketStats", "Proxy", "Security":
+	case "Auth", "Cache", "RateLimit", "Metrics", "CORS", "WebSocketStats", "Proxy", "Security", "MaxResponseSize", "MaxBodySize", "SlowThreshold", "NoAccessLog", "Mock", "Dedupe", "SagaStep", "SSE", "CircuitBreaker", "Transactional", "Compress", "Log", "Envelope":
 		processTraditionalMiddleware(marker, middlewareCalls, middlewareInfo)
 	case "WebSocket":
 		processWebSocketMarker(marker, route, middlewareCalls, middlewareInfo)
 	case "Group":
 		*groupInfo = processGroupMarker(marker)
+	case "RequireHeader":
+		processRequireHeaderMarker(marker, middlewareCalls, middlewareInfo, parameters)
+	case "FileParam":
+		processFileParamMarker(marker, middlewareCalls, middlewareInfo, parameters)
+	case "RequestBody":
+		processRequestBodyMarker(marker, parameters)
+	case "Deprecated":
+		processDeprecatedMarker(marker, route, middlewareCalls, middlewareInfo)
 	case "Param":
 		processParamMarker(marker, parameters)
 	case "Tag":
@@ -436,6 +863,28 @@ func processMarker(marker MarkerInstance, route *RouteMeta, middlewareCalls *[]s
 		processDescriptionMarker(marker, route)
 	case "Summary":
 		processSummaryMarker(marker, route)
+	case "Doc":
+		// Loaded by loadDocMarkers once @Description is known
+	case "MiddlewareOrder":
+		// Applied by applyMiddlewareOrder once the whole chain is known
+	case "Version":
+		processVersionMarker(marker, route)
+	case "SummaryTranslation", "DescriptionTranslation":
+		processTranslationMarker(marker, route)
+	case "Subscribe":
+		// Arguments were validated during parsing
+		route.Subscription, _ = parseSubscribeArgs(marker.Args)
+	case "GRPC":
+		// Arguments were validated by validateGRPCMarker; the gRPC method calls the first @Route binding
+		if route.Binding == 0 {
+			route.GRPC, _ = parseGRPCArgs(marker.Args, route.FuncName)
+		}
+	case "Provide":
+		// Arguments were validated by validateProvideMarkers
+		binding, _ := parseProvideArgs(marker.Args)
+		route.Providers = append(route.Providers, *binding)
+	default:
+		processPluginMarker(marker, middlewareCalls, middlewareInfo)
 	}
 }
 
@@ -512,10 +961,49 @@ func processParamMarker(marker MarkerInstance, parameters *[]ParameterInfo) {
 	}
 }
 
+// processRequireHeaderMarker adds the header check and documents the header as a parameter
+func processRequireHeaderMarker(marker MarkerInstance, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo, parameters *[]ParameterInfo) {
+	processTraditionalMiddleware(marker, middlewareCalls, middlewareInfo)
+	// Arguments were validated during parsing
+	if config, err := parseRequireHeaderArgs(marker.Args); err == nil {
+		*parameters = append(*parameters, config.parameterInfo())
+	}
+}
+
+// processFileParamMarker adds the file validation and documents the file as a multipart field
+func processFileParamMarker(marker MarkerInstance, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo, parameters *[]ParameterInfo) {
+	processTraditionalMiddleware(marker, middlewareCalls, middlewareInfo)
+	// Arguments were validated during parsing
+	if config, err := parseFileParamArgs(marker.Args); err == nil {
+		*parameters = append(*parameters, config.parameterInfo())
+	}
+}
+
+// processRequestBodyMarker documents the request body with its content type
+func processRequestBodyMarker(marker MarkerInstance, parameters *[]ParameterInfo) {
+	if param, err := parseRequestBodyArgs(marker.Args); err == nil {
+		*parameters = append(*parameters, param)
+	}
+}
+
+// processDeprecatedMarker marks the operation as deprecated and, unless headers=false, adds the
+// Deprecation/Sunset headers
+func processDeprecatedMarker(marker MarkerInstance, route *RouteMeta, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo) {
+	// Arguments were validated during parsing
+	info, err := parseDeprecatedArgs(marker.Args)
+	if err != nil {
+		return
+	}
+	route.Deprecation = info
+	if info.Headers {
+		processTraditionalMiddleware(marker, middlewareCalls, middlewareInfo)
+	}
+}
+
 // processTagMarker processes tag marker
 func processTagMarker(marker MarkerInstance, tags *[]string) {
 	if len(marker.Args) > 0 {
-		tag := strings.Trim(marker.Args[0], `"`)
+		tag := MarkerValue(marker.Args[0])
 		*tags = append(*tags, tag)
 	}
 }
@@ -531,14 +1019,14 @@ func processResponseMarker(marker MarkerInstance, responses *[]ResponseInfo) {
 // processDescriptionMarker processes description marker
 func processDescriptionMarker(marker MarkerInstance, route *RouteMeta) {
 	if len(marker.Args) > 0 {
-		route.Description = strings.Trim(marker.Args[0], `"`)
+		route.Description = MarkerValue(marker.Args[0])
 	}
 }
 
 // processSummaryMarker processes summary marker
 func processSummaryMarker(marker MarkerInstance, route *RouteMeta) {
 	if len(marker.Args) > 0 {
-		route.Summary = strings.Trim(marker.Args[0], `"`)
+		route.Summary = MarkerValue(marker.Args[0])
 	}
 }
 
@@ -550,11 +1038,11 @@ func parseArgsToMap(args []string) map[string]interface{} {
 		if strings.Contains(arg, "=") {
 			parts := strings.SplitN(arg, "=", 2)
 			key := strings.TrimSpace(parts[0])
-			value := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+			value := MarkerValue(parts[1])
 			result[key] = value
 		} else {
 			// Argument without key, use as "value"
-			result["value"] = strings.Trim(arg, `"`)
+			result["value"] = MarkerValue(arg)
 		}
 	}
 
@@ -569,7 +1057,7 @@ func parseParameterInfo(args []string) ParameterInfo {
 		if strings.Contains(arg, "=") {
 			parts := strings.SplitN(arg, "=", 2)
 			key := strings.TrimSpace(parts[0])
-			value := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+			value := MarkerValue(parts[1])
 
 			switch key {
 			case "name":
@@ -584,6 +1072,8 @@ func parseParameterInfo(args []string) ParameterInfo {
 				param.Description = value
 			case "example":
 				param.Example = value
+			case "pattern":
+				param.Pattern = value
 			}
 		}
 	}
@@ -599,7 +1089,7 @@ func parseResponseInfo(args []string) ResponseInfo {
 		if strings.Contains(arg, "=") {
 			parts := strings.SplitN(arg, "=", 2)
 			key := strings.TrimSpace(parts[0])
-			value := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+			value := MarkerValue(parts[1])
 
 			switch key {
 			case "code":
@@ -617,20 +1107,41 @@ func parseResponseInfo(args []string) ResponseInfo {
 	return response
 }
 
+// middlewareDescriptions default descriptions of the built-in middlewares
+var middlewareDescriptions = map[string]string{
+	"Auth":            "Middleware de autenticação e autorização",
+	"Cache":           "Middleware de cache de responses",
+	"RateLimit":       "Middleware de limitação de taxa",
+	"Metrics":         "Middleware de coleta de métricas",
+	"CORS":            "Middleware de Cross-Origin Resource Sharing",
+	"WebSocket":       "Middleware de upgrade para conexão WebSocket",
+	"WebSocketStats":  "Middleware de estatísticas WebSocket",
+	"Proxy":           "Middleware de proxy reverso com service discovery e load balancing",
+	"MaxResponseSize": "Middleware de limite de tamanho de response",
+	"MaxBodySize":     "Middleware de limite de tamanho do body da requisição",
+	"SlowThreshold":   "Middleware de detecção de requests lentas",
+	"NoAccessLog":     "Remove a rota do access log",
+	"Mock":            "Resposta simulada (o handler não é executado)",
+	"Dedupe":          "Deduplica entregas repetidas (webhooks)",
+	"SagaStep":        "Executa a rota como passo de uma saga",
+	"Provide":         "Constrói uma dependência nomeada por request",
+	"RequireHeader":   "Exige um header na requisição, opcionalmente com formato",
+	"FileParam":       "Valida um arquivo enviado em multipart/form-data (tamanho e tipo)",
+	"Sensitive":       "Mascara e criptografa campos sensíveis da resposta e decripta os da requisição",
+	"FieldACL":        "Remove da resposta os campos restritos por papel (tag acl)",
+	"SSE":             "Transmite os eventos de um canal como Server-Sent Events",
+	"CircuitBreaker":  "Circuit breaker da rota: responde 503 enquanto o circuito está aberto",
+	"Deprecated":      "Rota depreciada: envia os headers Deprecation, Sunset e Link da rota substituta",
+	"PathParams":      "Confere o tipo e o formato dos parâmetros de caminho",
+	"Transactional":   "Executa a rota em uma transação: commit em respostas 2xx, rollback em erros e panics",
+	"Compress":        "Comprime a resposta (gzip, brotli) conforme o Accept-Encoding",
+	"Log":             "Registra a requisição em log estruturado (método, caminho, status, latência e corpos mascarados)",
+	"Envelope":        "Envolve as respostas JSON em {\"data\", \"meta\", \"error\"}",
+}
+
 // getMiddlewareDescription returns default description for middlewares
 func getMiddlewareDescription(name string) string {
-	descriptions := map[string]string{
-		"Auth":           "Middleware de autenticação e autorização",
-		"Cache":          "Middleware de cache de responses",
-		"RateLimit":      "Middleware de limitação de taxa",
-		"Metrics":        "Middleware de coleta de métricas",
-		"CORS":           "Middleware de Cross-Origin Resource Sharing",
-		"WebSocket":      "Middleware de upgrade para conexão WebSocket",
-		"WebSocketStats": "Middleware de estatísticas WebSocket",
-		"Proxy":          "Middleware de proxy reverso com service discovery e load balancing",
-	}
-
-	if desc, exists := descriptions[name]; exists {
+	if desc, exists := middlewareDescriptions[name]; exists {
 		return desc
 	}
 	return fmt.Sprintf("Middleware %s", name)
@@ -638,6 +1149,10 @@ func getMiddlewareDescription(name string) string {
 
 // generateMiddlewareCall generates Go call for a middleware
 func generateMiddlewareCall(marker MarkerInstance) string {
+	if call, ok := foldMiddlewareCall(marker); ok {
+		return call
+	}
+
 	switch marker.Name {
 	case "Auth":
 		if len(marker.Args) > 0 {
@@ -692,6 +1207,53 @@ func generateMiddlewareCall(marker MarkerInstance) string {
 			return fmt.Sprintf(`deco.CreateSecurityMiddleware(%q)`, strings.Join(marker.Args, ","))
 		}
 		return `deco.CreateSecurityMiddleware("")`
+
+	case "MaxResponseSize":
+		return fmt.Sprintf(`deco.CreateMaxResponseSizeMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "MaxBodySize":
+		return fmt.Sprintf(`deco.CreateMaxBodySizeMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "SlowThreshold":
+		return fmt.Sprintf(`deco.CreateSlowThresholdMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "NoAccessLog":
+		return `deco.CreateNoAccessLogMiddleware("")`
+
+	case "Mock":
+		return generateMockCall(marker)
+
+	case "Dedupe":
+		return fmt.Sprintf(`deco.CreateDedupeMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "SagaStep":
+		return fmt.Sprintf(`deco.CreateSagaStepMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "RequireHeader":
+		return fmt.Sprintf(`deco.CreateRequireHeaderMiddleware(%q)`, strings.Join(marker.Args, ","))
+	case "FileParam":
+		return fmt.Sprintf(`deco.CreateFileParamMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "SSE":
+		return fmt.Sprintf(`deco.CreateSSEMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "CircuitBreaker":
+		return fmt.Sprintf(`deco.CreateCircuitBreakerMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "Deprecated":
+		return fmt.Sprintf(`deco.CreateDeprecatedMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "Transactional":
+		return fmt.Sprintf(`deco.CreateTransactionalMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "Compress":
+		return fmt.Sprintf(`deco.CreateCompressMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "Log":
+		return fmt.Sprintf(`deco.CreateLogMiddleware(%q)`, strings.Join(marker.Args, ","))
+
+	case "Envelope":
+		return fmt.Sprintf(`deco.CreateEnvelopeMiddleware(%q)`, strings.Join(marker.Args, ","))
 	}
 
 	return ""
@@ -700,62 +1262,188 @@ func generateMiddlewareCall(marker MarkerInstance) string {
 // CreateAuthMiddleware creates auth middleware (wrapper for generation)
 func CreateAuthMiddleware(args string) func(c *gin.Context) {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["Auth"]
+	config, _ := GetMarker("Auth")
 	return config.Factory(argsSlice)
 }
 
 // CreateCacheMiddleware creates cache middleware (wrapper for generation)
 func CreateCacheMiddleware(args string) func(c *gin.Context) {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["Cache"]
+	config, _ := GetMarker("Cache")
 	return config.Factory(argsSlice)
 }
 
 // CreateRateLimitMiddleware creates rate limit middleware (wrapper for generation)
 func CreateRateLimitMiddleware(args string) func(c *gin.Context) {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["RateLimit"]
+	config, _ := GetMarker("RateLimit")
 	return config.Factory(argsSlice)
 }
 
 // CreateMetricsMiddleware creates metrics middleware (wrapper for generation)
 func CreateMetricsMiddleware(args string) func(c *gin.Context) {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["Metrics"]
+	config, _ := GetMarker("Metrics")
 	return config.Factory(argsSlice)
 }
 
 // CreateCORSMiddleware creates CORS middleware (wrapper for generation)
 func CreateCORSMiddleware(args string) func(c *gin.Context) {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["CORS"]
+	config, _ := GetMarker("CORS")
 	return config.Factory(argsSlice)
 }
 
 // CreateWebSocketMiddleware creates WebSocket middleware (wrapper for generation)
 func CreateWebSocketMiddleware(args string) gin.HandlerFunc {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["WebSocket"]
+	config, _ := GetMarker("WebSocket")
 	return config.Factory(argsSlice)
 }
 
 // CreateWebSocketStatsMiddleware creates WebSocket stats middleware (wrapper for generation)
 func CreateWebSocketStatsMiddleware(args string) gin.HandlerFunc {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["WebSocketStats"]
+	config, _ := GetMarker("WebSocketStats")
 	return config.Factory(argsSlice)
 }
 
 // CreateProxyMiddleware creates proxy middleware (wrapper for generation)
 func CreateProxyMiddleware(args string) gin.HandlerFunc {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["Proxy"]
+	config, _ := GetMarker("Proxy")
 	return config.Factory(argsSlice)
 }
 
 // CreateSecurityMiddleware creates security middleware (wrapper for generation)
 func CreateSecurityMiddleware(args string) gin.HandlerFunc {
 	argsSlice := parseArguments(args)
-	config := GetMarkers()["Security"]
+	config, _ := GetMarker("Security")
+	return config.Factory(argsSlice)
+}
+
+// CreateMaxResponseSizeMiddleware creates response size cap middleware (wrapper for generation)
+func CreateMaxResponseSizeMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("MaxResponseSize")
+	return config.Factory(argsSlice)
+}
+
+// CreateMaxBodySizeMiddleware creates request body size cap middleware (wrapper for generation)
+func CreateMaxBodySizeMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("MaxBodySize")
+	return config.Factory(argsSlice)
+}
+
+// CreateSlowThresholdMiddleware creates slow request detection middleware (wrapper for generation)
+func CreateSlowThresholdMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("SlowThreshold")
+	return config.Factory(argsSlice)
+}
+
+// CreateNoAccessLogMiddleware creates the access log opt-out middleware (wrapper for generation)
+func CreateNoAccessLogMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("NoAccessLog")
+	return config.Factory(argsSlice)
+}
+
+// CreateMockMiddleware creates canned response middleware (wrapper for generation)
+func CreateMockMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Mock")
+	return config.Factory(argsSlice)
+}
+
+// CreateDedupeMiddleware creates duplicate delivery middleware (wrapper for generation)
+func CreateDedupeMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Dedupe")
+	return config.Factory(argsSlice)
+}
+
+// CreateSagaStepMiddleware creates saga step middleware (wrapper for generation)
+func CreateSagaStepMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("SagaStep")
+	return config.Factory(argsSlice)
+}
+
+// CreateRequireHeaderMiddleware creates required header middleware (wrapper for generation)
+func CreateRequireHeaderMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("RequireHeader")
+	return config.Factory(argsSlice)
+}
+
+// CreateFileParamMiddleware creates file upload validation middleware (wrapper for generation)
+func CreateFileParamMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("FileParam")
+	return config.Factory(argsSlice)
+}
+
+// CreateSSEMiddleware creates Server-Sent Events middleware (wrapper for generation)
+func CreateSSEMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("SSE")
+	return config.Factory(argsSlice)
+}
+
+// CreateCircuitBreakerMiddleware creates route circuit breaker middleware (wrapper for generation)
+func CreateCircuitBreakerMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("CircuitBreaker")
+	return config.Factory(argsSlice)
+}
+
+// CreateDeprecatedMiddleware creates deprecated route middleware (wrapper for generation)
+func CreateDeprecatedMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Deprecated")
+	return config.Factory(argsSlice)
+}
+
+// CreateTransactionalMiddleware creates transaction middleware (wrapper for generation)
+func CreateTransactionalMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Transactional")
+	return config.Factory(argsSlice)
+}
+
+// CreateCompressMiddleware creates response compression middleware (wrapper for generation)
+func CreateCompressMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Compress")
+	return config.Factory(argsSlice)
+}
+
+// CreateLogMiddleware creates structured request logging middleware (wrapper for generation)
+func CreateLogMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Log")
+	return config.Factory(argsSlice)
+}
+
+// CreateEnvelopeMiddleware creates response envelope middleware (wrapper for generation)
+func CreateEnvelopeMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Envelope")
+	return config.Factory(argsSlice)
+}
+
+// CreateFieldACLMiddleware creates response field access control middleware (wrapper for generation)
+func CreateFieldACLMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("FieldACL")
+	return config.Factory(argsSlice)
+}
+
+// CreateSensitiveMiddleware creates sensitive field middleware (wrapper for generation)
+func CreateSensitiveMiddleware(args string) gin.HandlerFunc {
+	argsSlice := parseArguments(args)
+	config, _ := GetMarker("Sensitive")
 	return config.Factory(argsSlice)
 }
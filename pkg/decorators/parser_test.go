@@ -1,9 +1,13 @@
 package decorators
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHasDecoratorAnnotations(t *testing.T) {
@@ -72,8 +76,69 @@ func TestGetMiddlewareDescription(t *testing.T) {
 
 func TestGenerateMiddlewareCall(t *testing.T) {
 	// Test generating middleware call
-	marker := MarkerInstance{Name: "Cache", Args: []string{"ttl=5m"}}
+	marker := MarkerInstance{Name: "Cache", Args: []string{"ttl=10m"}}
 	call := generateMiddlewareCall(marker)
 	assert.Contains(t, call, "Cache")
-	assert.Contains(t, call, "ttl=5m")
+	assert.Contains(t, call, "TTL: 10 * time.Minute")
+}
+
+func TestParseDirectory_MultipleRouteBindings(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET, HEAD", "/health")
+// @Route("GET", "/healthz")
+// @Route("GET", "/health")
+// @GRPC(service="Health", method="Check")
+func Health(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "health.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, routes, 3, "GET /health is bound once")
+	for i, expected := range []struct {
+		method, path string
+		line         int
+	}{{"GET", "/health", 5}, {"HEAD", "/health", 5}, {"GET", "/healthz", 6}} {
+		assert.Equal(t, expected.method, routes[i].Method)
+		assert.Equal(t, expected.path, routes[i].Path)
+		assert.Equal(t, expected.line, routes[i].Line)
+		assert.Equal(t, i, routes[i].Binding)
+		assert.Equal(t, "Health", routes[i].FuncName)
+	}
+	assert.NotNil(t, routes[0].GRPC)
+	assert.Nil(t, routes[1].GRPC, "the gRPC method calls the first binding")
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	// Each binding is registered with its own operationId
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", nil))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(generated), `FuncName:    "Health"`))
+	assert.Contains(t, string(generated), `OperationID: "Health",`)
+	assert.Contains(t, string(generated), `OperationID: "HealthHeadHealth",`)
+	assert.Contains(t, string(generated), `OperationID: "HealthGetHealthz",`)
+}
+
+func TestParseDirectory_InvalidRouteBindings(t *testing.T) {
+	for name, route := range map[string]string{
+		"INVALID_HTTP_METHOD":  `@Route("GET,FETCH", "/health")`,
+		"INVALID_PATH":         `@Route("GET", "health")`,
+		"INVALID_ROUTE_SYNTAX": `@Route("GET", "/health")` + "\n// @Route(\"GET\")",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			source := "package handlers\n\nimport \"github.com/gin-gonic/gin\"\n\n// " + route + "\nfunc Health(c *gin.Context) {}\n"
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "health.go"), []byte(source), 0o600))
+			_, err := ParseDirectory(dir)
+			require.Error(t, err)
+		})
+	}
 }
@@ -0,0 +1,229 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pathParamTypes types of typed path segments, e.g. /users/{id:int}, and the ParameterInfo type documenting them
+var pathParamTypes = map[string]string{
+	"int":    "int64",
+	"float":  "float64",
+	"bool":   "bool",
+	"uuid":   "uuid.UUID",
+	"string": "string",
+}
+
+// uuidPattern value of uuid path parameters
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// typedPathSegment segment {name} or {name:type} of a route path
+var typedPathSegment = regexp.MustCompile(`^\{([A-Za-z_][A-Za-z0-9_]*)(?::([A-Za-z]+))?\}$`)
+
+// PathParamConstraint check of a path parameter value: its type, a pattern, or both
+type PathParamConstraint struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`     // int, float, bool, uuid or a @Param Go type
+	Pattern  string `json:"pattern,omitempty"`  // regular expression the whole value must match
+	NotFound bool   `json:"notFound,omitempty"` // 404 on mismatch, as the route does not match; 400 otherwise
+}
+
+// parseTypedPath converts the {name} and {name:type} segments of a route path to gin parameters,
+// returning the gin path and the constraints of the typed segments
+func parseTypedPath(path string) (string, []PathParamConstraint, error) {
+	if !strings.Contains(path, "{") {
+		return path, nil, nil
+	}
+
+	segments := strings.Split(path, "/")
+	var constraints []PathParamConstraint
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{") {
+			continue
+		}
+		match := typedPathSegment.FindStringSubmatch(segment)
+		if match == nil {
+			return "", nil, fmt.Errorf("invalid path segment '%s' in '%s', use {name} or {name:type}", segment, path)
+		}
+		segments[i] = ":" + match[1]
+		if match[2] == "" || match[2] == "string" {
+			continue
+		}
+		if _, ok := pathParamTypes[match[2]]; !ok {
+			return "", nil, fmt.Errorf("unknown type '%s' of path parameter '%s' (valid: int, float, bool, uuid, string)", match[2], match[1])
+		}
+		constraints = append(constraints, PathParamConstraint{Name: match[1], Type: match[2], NotFound: true})
+	}
+	return strings.Join(segments, "/"), constraints, nil
+}
+
+// pathParamNames names of the parameters of a gin path
+func pathParamNames(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// typedPathParams declares the typed segments of @Route as path parameters and validates the @Param path
+// parameters, returning the constraints checked at runtime: typed segments first, then @Param patterns and types
+func typedPathParams(route *RouteMeta, parameters []ParameterInfo) ([]ParameterInfo, []PathParamConstraint, error) {
+	names := pathParamNames(route.Path)
+	declared := make(map[string]int)
+	var constraints []PathParamConstraint
+
+	for i, param := range parameters {
+		if param.Location != "path" {
+			continue
+		}
+		if !contains(names, param.Name) {
+			return nil, nil, fmt.Errorf("@Param '%s' is a path parameter, but %s has no :%s segment", param.Name, route.Path, param.Name)
+		}
+		declared[param.Name] = i
+	}
+
+	for _, constraint := range route.PathParams {
+		constraints = append(constraints, constraint)
+		if i, ok := declared[constraint.Name]; ok {
+			// The segment type wins over a @Param without one, so the spec matches the runtime check
+			parameters[i].Type = pathParamTypes[constraint.Type]
+			parameters[i].Required = true
+			continue
+		}
+		parameters = append(parameters, ParameterInfo{Name: constraint.Name, Type: pathParamTypes[constraint.Type], Location: "path", Required: true})
+	}
+
+	for _, param := range parameters {
+		if param.Location != "path" {
+			continue
+		}
+		constraint := PathParamConstraint{Name: param.Name, Pattern: param.Pattern}
+		if pathParamCheckable(param.Type) && !pathParamChecked(route.PathParams, param.Name) {
+			constraint.Type = param.Type
+		}
+		if constraint.Type != "" || constraint.Pattern != "" {
+			constraints = append(constraints, constraint)
+		}
+	}
+	return parameters, constraints, nil
+}
+
+// pathParamChecked reports whether a typed segment already checks the parameter
+func pathParamChecked(constraints []PathParamConstraint, name string) bool {
+	for _, constraint := range constraints {
+		if constraint.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pathParamCheckable reports whether values of a type can be checked, for segment and @Param types
+func pathParamCheckable(paramType string) bool {
+	switch paramType {
+	case "int", "int32", "int64", "integer", "float", "float32", "float64", "number", "bool", "boolean", "uuid", "uuid.UUID":
+		return true
+	}
+	return false
+}
+
+// matchesType reports whether value satisfies the type of the constraint
+func (p PathParamConstraint) matchesType(value string) bool {
+	var err error
+	switch p.Type {
+	case "int", "int64", "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "int32":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "float", "float64", "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "float32":
+		_, err = strconv.ParseFloat(value, 32)
+	case "bool", "boolean":
+		_, err = strconv.ParseBool(value)
+	case "uuid", "uuid.UUID":
+		return uuidPattern.MatchString(value)
+	}
+	return err == nil
+}
+
+// goLiteral writes the constraint as Go source for the generated code
+func (p PathParamConstraint) goLiteral() string {
+	fields := []string{fmt.Sprintf("Name: %q", p.Name)}
+	if p.Type != "" {
+		fields = append(fields, fmt.Sprintf("Type: %q", p.Type))
+	}
+	if p.Pattern != "" {
+		fields = append(fields, fmt.Sprintf("Pattern: %q", p.Pattern))
+	}
+	if p.NotFound {
+		fields = append(fields, "NotFound: true")
+	}
+	return fmt.Sprintf("deco.PathParamConstraint{%s}", strings.Join(fields, ", "))
+}
+
+// pathParamsMiddlewareCall generated call checking the constraints, "" without constraints
+func pathParamsMiddlewareCall(constraints []PathParamConstraint) string {
+	if len(constraints) == 0 {
+		return ""
+	}
+	literals := make([]string, len(constraints))
+	for i, constraint := range constraints {
+		literals[i] = constraint.goLiteral()
+	}
+	return fmt.Sprintf("deco.PathParamsMiddleware(%s)", strings.Join(literals, ", "))
+}
+
+// PathParamsMiddleware checks the path parameters of the route: a typed segment that does not match
+// answers 404, as the route does not match; a @Param pattern or type that does not match answers
+// the standard validation 400
+func PathParamsMiddleware(constraints ...PathParamConstraint) gin.HandlerFunc {
+	patterns := make([]*regexp.Regexp, len(constraints))
+	for i, constraint := range constraints {
+		if constraint.Pattern == "" {
+			continue
+		}
+		pattern, err := regexp.Compile("^(?:" + constraint.Pattern + ")$")
+		if err != nil {
+			LogSilent("⚠️  path parameter %s: invalid pattern '%s': %v", constraint.Name, constraint.Pattern, err)
+			continue
+		}
+		patterns[i] = pattern
+	}
+
+	return func(c *gin.Context) {
+		for i, constraint := range constraints {
+			value := c.Param(constraint.Name)
+			typeOK := constraint.matchesType(value)
+			if typeOK && (patterns[i] == nil || patterns[i].MatchString(value)) {
+				continue
+			}
+
+			if constraint.NotFound {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not_found", "message": fmt.Sprintf("no route matches %s %s", c.Request.Method, c.Request.URL.Path)})
+				return
+			}
+			field := ValidationField{Field: constraint.Name, Value: value, Tag: "pattern", Param: constraint.Pattern,
+				Message: fmt.Sprintf("Path parameter %s must match %s", constraint.Name, constraint.Pattern)}
+			if !typeOK {
+				field.Tag, field.Param = "type", constraint.Type
+				field.Message = fmt.Sprintf("Path parameter %s must be of type %s", constraint.Name, constraint.Type)
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, ValidationResponse{
+				Error:   "validation_failed",
+				Message: "Invalid path parameters",
+				Fields:  []ValidationField{field},
+			})
+			return
+		}
+		c.Next()
+	}
+}
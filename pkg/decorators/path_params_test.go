@@ -0,0 +1,117 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypedPath(t *testing.T) {
+	path, constraints, err := parseTypedPath("/orgs/{org}/users/{id:int}/files/{file:uuid}")
+	require.NoError(t, err)
+	assert.Equal(t, "/orgs/:org/users/:id/files/:file", path)
+	assert.Equal(t, []PathParamConstraint{
+		{Name: "id", Type: "int", NotFound: true},
+		{Name: "file", Type: "uuid", NotFound: true},
+	}, constraints)
+
+	path, constraints, err = parseTypedPath("/users/:id")
+	require.NoError(t, err)
+	assert.Equal(t, "/users/:id", path)
+	assert.Nil(t, constraints)
+
+	for _, invalid := range []string{"/users/{id:long}", "/users/{id", "/users/v{id}"} {
+		_, _, err := parseTypedPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPathParamsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id/orders/:code", PathParamsMiddleware(
+		PathParamConstraint{Name: "id", Type: "int", NotFound: true},
+		PathParamConstraint{Name: "code", Pattern: "[A-Z]{3}[0-9]+"},
+	), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	send := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("/users/42/orders/ABC1").Code)
+	assert.Equal(t, http.StatusNotFound, send("/users/abc/orders/ABC1").Code)
+
+	w := send("/users/42/orders/ABC1x")
+	require.Equal(t, http.StatusBadRequest, w.Code, "the pattern matches the whole value")
+	var response ValidationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Fields, 1)
+	assert.Equal(t, ValidationField{Field: "code", Value: "ABC1x", Tag: "pattern", Param: "[A-Z]{3}[0-9]+",
+		Message: "Path parameter code must match [A-Z]{3}[0-9]+"}, response.Fields[0])
+}
+
+func TestTypedPathParams(t *testing.T) {
+	route := &RouteMeta{Method: "GET", Path: "/users/:id/posts/:slug", PathParams: []PathParamConstraint{{Name: "id", Type: "int", NotFound: true}}}
+	parameters, constraints, err := typedPathParams(route, []ParameterInfo{
+		{Name: "id", Location: "path", Description: "User ID"},
+		{Name: "slug", Type: "string", Location: "path", Pattern: "[a-z-]+"},
+		{Name: "page", Type: "int", Location: "query"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ParameterInfo{Name: "id", Type: "int64", Location: "path", Required: true, Description: "User ID"}, parameters[0])
+	assert.Equal(t, []PathParamConstraint{
+		{Name: "id", Type: "int", NotFound: true},
+		{Name: "slug", Pattern: "[a-z-]+"},
+	}, constraints)
+	assert.Equal(t, "integer", convertToOpenAPIParameter(&parameters[0], nil).Schema.Type)
+
+	_, _, err = typedPathParams(route, []ParameterInfo{{Name: "user_id", Location: "path"}})
+	assert.ErrorContains(t, err, "has no :user_id segment")
+}
+
+func TestGenerateInitFile_TypedPath(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users/{id:int}")
+// @Param(name="id", location="path", description="User ID")
+// @Cache(ttl=1m)
+func GetUser(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig()))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `Path:        "/users/:id",`)
+	assert.Contains(t, string(generated), "Middlewares: []gin.HandlerFunc{\n\t\t\tdeco.PathParamsMiddleware(deco.PathParamConstraint{Name: \"id\", Type: \"int\", NotFound: true}),\n\t\t\tdeco.CacheWith(")
+	assert.Contains(t, string(generated), `Type:        "int64",`)
+
+	// Unknown segment types fail the generation
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(`package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users/{id:long}")
+func GetUser(c *gin.Context) {}
+`), 0o600))
+	err = GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig())
+	assert.ErrorContains(t, err, "unknown type 'long'")
+}
@@ -0,0 +1,243 @@
+package decorators
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response size limit actions
+const (
+	ResponseSizeActionReject = "reject" // replace oversized responses with a 500 error
+	ResponseSizeActionLog    = "log"    // deliver oversized responses and only log/count them
+)
+
+// ResponseSizeConfig configuration for per-route response size caps
+type ResponseSizeConfig struct {
+	MaxBytes int64  `json:"max_bytes"`
+	Action   string `json:"action"`
+}
+
+// parseMaxResponseSizeArgs parses @MaxResponseSize arguments: a positional size ("5MB") or size=/action=
+func parseMaxResponseSizeArgs(args []string) (ResponseSizeConfig, error) {
+	config := ResponseSizeConfig{Action: ResponseSizeActionReject}
+
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if strings.HasPrefix(arg, "action=") {
+			config.Action = strings.Trim(strings.TrimPrefix(arg, "action="), `"'`)
+			if config.Action != ResponseSizeActionReject && config.Action != ResponseSizeActionLog {
+				return config, fmt.Errorf("@MaxResponseSize: invalid action '%s' (valid: reject, log)", config.Action)
+			}
+			continue
+		}
+
+		size, err := ParseByteSize(strings.TrimPrefix(arg, "size="))
+		if err != nil {
+			return config, fmt.Errorf("@MaxResponseSize: %v", err)
+		}
+		config.MaxBytes = size
+	}
+
+	if config.MaxBytes <= 0 {
+		return config, fmt.Errorf("@MaxResponseSize requires a positive size such as \"5MB\"")
+	}
+
+	return config, nil
+}
+
+// MaxResponseSizeMiddleware caps the response body size of a route
+func MaxResponseSizeMiddleware(config ResponseSizeConfig) gin.HandlerFunc {
+	if config.Action == "" {
+		config.Action = ResponseSizeActionReject
+	}
+
+	return func(c *gin.Context) {
+		if config.MaxBytes <= 0 {
+			c.Next()
+			return
+		}
+
+		method := c.Request.Method
+		endpoint := getEndpointPattern(c)
+
+		if config.Action == ResponseSizeActionLog {
+			writer := &sizeLimitLogWriter{ResponseWriter: c.Writer, limit: config.MaxBytes}
+			c.Writer = writer
+			c.Next()
+			if writer.exceeded {
+				RecordResponseSizeExceeded(method, endpoint, config.Action)
+				LogNormal("⚠️  Response for %s %s exceeded size limit: %d > %d bytes", method, endpoint, writer.Size(), config.MaxBytes)
+			}
+			return
+		}
+
+		original := c.Writer
+		writer := &sizeLimitBufferWriter{ResponseWriter: original, limit: config.MaxBytes, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		// Streamed (flushed or hijacked) responses were already sent, so the limit can only be reported
+		if writer.streaming {
+			if writer.exceeded {
+				RecordResponseSizeExceeded(method, endpoint, ResponseSizeActionLog)
+				LogNormal("⚠️  Streamed response for %s %s exceeded size limit of %d bytes", method, endpoint, config.MaxBytes)
+			}
+			return
+		}
+
+		if writer.exceeded {
+			RecordResponseSizeExceeded(method, endpoint, config.Action)
+			LogNormal("⚠️  Response for %s %s rejected: exceeds size limit of %d bytes", method, endpoint, config.MaxBytes)
+			original.Header().Del("Content-Length")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "response_too_large",
+				"message": fmt.Sprintf("Response exceeds the limit of %d bytes", config.MaxBytes),
+			})
+			return
+		}
+
+		if writer.wroteHeader || writer.buffer.Len() > 0 {
+			original.WriteHeader(writer.status)
+			original.WriteHeaderNow()
+			if writer.buffer.Len() > 0 {
+				_, _ = original.Write(writer.buffer.Bytes())
+			}
+		}
+	}
+}
+
+// sizeLimitBufferWriter buffers the response until the handler finishes or the limit is exceeded.
+// Flushing or hijacking switches it to pass-through, since the status must be sent at that point.
+type sizeLimitBufferWriter struct {
+	gin.ResponseWriter
+	buffer      bytes.Buffer
+	limit       int64
+	status      int
+	written     int64
+	wroteHeader bool
+	exceeded    bool
+	streaming   bool
+}
+
+func (w *sizeLimitBufferWriter) Write(data []byte) (int, error) {
+	if w.streaming {
+		w.written += int64(len(data))
+		if w.written > w.limit {
+			w.exceeded = true
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	if w.exceeded {
+		return len(data), nil
+	}
+	if int64(w.buffer.Len()+len(data)) > w.limit {
+		w.exceeded = true
+		w.buffer.Reset()
+		return len(data), nil
+	}
+	return w.buffer.Write(data)
+}
+
+func (w *sizeLimitBufferWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *sizeLimitBufferWriter) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+}
+
+func (w *sizeLimitBufferWriter) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.wroteHeader = true
+}
+
+func (w *sizeLimitBufferWriter) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *sizeLimitBufferWriter) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	if !w.wroteHeader && w.buffer.Len() == 0 {
+		return -1
+	}
+	return w.buffer.Len()
+}
+
+func (w *sizeLimitBufferWriter) Written() bool {
+	if w.streaming {
+		return w.ResponseWriter.Written()
+	}
+	return w.wroteHeader || w.buffer.Len() > 0
+}
+
+func (w *sizeLimitBufferWriter) Flush() {
+	w.startStreaming()
+	w.ResponseWriter.Flush()
+}
+
+func (w *sizeLimitBufferWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.startStreaming()
+	return w.ResponseWriter.Hijack()
+}
+
+// startStreaming sends the buffered status and body and passes later writes through
+func (w *sizeLimitBufferWriter) startStreaming() {
+	if w.streaming {
+		return
+	}
+	w.streaming = true
+	if w.exceeded {
+		// The buffered body was dropped; nothing consistent can be streamed anymore
+		w.written = w.limit + 1
+		return
+	}
+	if w.wroteHeader || w.buffer.Len() > 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	w.written = int64(w.buffer.Len())
+	if w.buffer.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+}
+
+// sizeLimitLogWriter passes the response through while tracking whether the limit was exceeded
+type sizeLimitLogWriter struct {
+	gin.ResponseWriter
+	limit    int64
+	exceeded bool
+}
+
+func (w *sizeLimitLogWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	if int64(w.ResponseWriter.Size()) > w.limit {
+		w.exceeded = true
+	}
+	return n, err
+}
+
+func (w *sizeLimitLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,180 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		hasError bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"10KB", 10 * 1024, false},
+		{"5MB", 5 * 1024 * 1024, false},
+		{`"5MB"`, 5 * 1024 * 1024, false},
+		{"1.5k", 1536, false},
+		{"2G", 2 * 1024 * 1024 * 1024, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"-1MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := ParseByteSize(tt.input)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
+
+func newResponseSizeRouter(middleware gin.HandlerFunc, body string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/items", middleware, func(c *gin.Context) {
+		c.String(http.StatusCreated, body)
+	})
+	return router
+}
+
+func TestMaxResponseSizeMiddleware_WithinLimit(t *testing.T) {
+	router := newResponseSizeRouter(MaxResponseSizeMiddleware(ResponseSizeConfig{MaxBytes: 16}), "small")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "small", w.Body.String())
+}
+
+func TestMaxResponseSizeMiddleware_Reject(t *testing.T) {
+	router := newResponseSizeRouter(MaxResponseSizeMiddleware(ResponseSizeConfig{MaxBytes: 8}), strings.Repeat("x", 64))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "response_too_large")
+	assert.NotContains(t, w.Body.String(), "xxxx")
+}
+
+func TestMaxResponseSizeMiddleware_LogOnly(t *testing.T) {
+	body := strings.Repeat("x", 64)
+	router := newResponseSizeRouter(MaxResponseSizeMiddleware(ResponseSizeConfig{MaxBytes: 8, Action: ResponseSizeActionLog}), body)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, body, w.Body.String())
+}
+
+func TestCreateMaxResponseSizeMiddleware(t *testing.T) {
+	router := newResponseSizeRouter(CreateMaxResponseSizeMiddleware(`"8B"`), strings.Repeat("x", 64))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	router = newResponseSizeRouter(CreateMaxResponseSizeMiddleware(`size="8B",action="log"`), strings.Repeat("x", 64))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", http.NoBody))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestGenerateMaxResponseSizeCall(t *testing.T) {
+	call := generateMiddlewareCall(MarkerInstance{Name: "MaxResponseSize", Args: []string{"5MB"}})
+	assert.Equal(t, `deco.MaxResponseSizeMiddleware(deco.ResponseSizeConfig{MaxBytes: 5242880, Action: "reject"})`, call)
+}
+
+func TestMaxResponseSizeMiddleware_Streaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", MaxResponseSizeMiddleware(ResponseSizeConfig{MaxBytes: 8}), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+		_, _ = c.Writer.WriteString("tick")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString(strings.Repeat("x", 16))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", http.NoBody))
+
+	// Once flushed the buffered status is committed and the body is streamed, not rejected
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "tick"+strings.Repeat("x", 16), w.Body.String())
+}
+
+func TestParseMaxResponseSizeArgs(t *testing.T) {
+	config, err := parseMaxResponseSizeArgs([]string{`size="1KB"`, `action="log"`})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), config.MaxBytes)
+	assert.Equal(t, ResponseSizeActionLog, config.Action)
+
+	_, err = parseMaxResponseSizeArgs([]string{"1KB", "action=warn"})
+	assert.Error(t, err)
+
+	_, err = parseMaxResponseSizeArgs([]string{"action=log"})
+	assert.Error(t, err)
+
+	_, err = parseArgumentsWithValidation(`"5MB", action="rejct"`, "MaxResponseSize")
+	assert.Error(t, err)
+}
+
+func TestMetricsMiddleware_ChunkedRequestSize(t *testing.T) {
+	metricsInitMutex.Lock()
+	savedCollector, savedInitialized := defaultMetricsCollector, metricsInitialized
+	defaultMetricsCollector = nil
+	metricsInitialized = false
+	metricsInitMutex.Unlock()
+	defer func() {
+		metricsInitMutex.Lock()
+		defaultMetricsCollector, metricsInitialized = savedCollector, savedInitialized
+		metricsInitMutex.Unlock()
+	}()
+
+	config := &MetricsConfig{Enabled: true, Namespace: "test", Subsystem: "payload", SizeBuckets: []float64{10, 100}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricsMiddleware(config))
+	router.POST("/upload", func(c *gin.Context) {
+		data, _ := c.GetRawData()
+		c.String(http.StatusOK, "%d", len(data))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("chunked-body"))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "12", w.Body.String())
+
+	// The body length is unknown up front, so it must be counted while read
+	expected := `
+# HELP test_payload_http_request_size_bytes Size of HTTP requests in bytes
+# TYPE test_payload_http_request_size_bytes histogram
+test_payload_http_request_size_bytes_bucket{endpoint="/upload",method="POST",le="10"} 0
+test_payload_http_request_size_bytes_bucket{endpoint="/upload",method="POST",le="100"} 1
+test_payload_http_request_size_bytes_bucket{endpoint="/upload",method="POST",le="+Inf"} 1
+test_payload_http_request_size_bytes_sum{endpoint="/upload",method="POST"} 12
+test_payload_http_request_size_bytes_count{endpoint="/upload",method="POST"} 1
+`
+	metricsInitMutex.RLock()
+	collector := defaultMetricsCollector
+	metricsInitMutex.RUnlock()
+	assert.NoError(t, testutil.CollectAndCompare(collector.httpRequestSize, strings.NewReader(expected)))
+}
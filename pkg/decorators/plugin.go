@@ -15,18 +15,34 @@ type RouteMeta struct {
 	FuncName        string           // GetUsers
 	PackageName     string           // handlers
 	FileName        string           // user_handlers.go
+	FilePath        string           // path of the source file as parsed
+	Line            int              // line of the @Route decorator
 	Markers         []MarkerInstance // found marker instances
 	MiddlewareCalls []string         // generated middleware calls
 
 	// Documentation information
-	Description       string           `json:"description"`
-	Summary           string           `json:"summary"`
-	Tags              []string         `json:"tags"`
-	MiddlewareInfo    []MiddlewareInfo `json:"middlewareInfo"`
-	Parameters        []ParameterInfo  `json:"parameters"`
-	Group             *GroupInfo       `json:"group,omitempty"`
-	Responses         []ResponseInfo   `json:"responses,omitempty"`         // Updated to use ResponseInfo
-	WebSocketHandlers []string         `json:"websocketHandlers,omitempty"` // WebSocket message types this function handles
+	Description       string                `json:"description"`
+	Summary           string                `json:"summary"`
+	Tags              []string              `json:"tags"`
+	MiddlewareInfo    []MiddlewareInfo      `json:"middlewareInfo"`
+	Parameters        []ParameterInfo       `json:"parameters"`
+	Group             *GroupInfo            `json:"group,omitempty"`
+	Responses         []ResponseInfo        `json:"responses,omitempty"`         // Updated to use ResponseInfo
+	WebSocketHandlers []string              `json:"websocketHandlers,omitempty"` // WebSocket message types this function handles
+	Subscription      *SubscriptionInfo     `json:"subscription,omitempty"`      // @Subscribe consumer configuration
+	GRPC              *GRPCBinding          `json:"grpc,omitempty"`              // @GRPC method of the route
+	Providers         []ProviderBinding     `json:"providers,omitempty"`         // @Provide dependencies, first in the chain
+	OperationID       string                `json:"operationId,omitempty"`       // operationId of the route, set by the generator
+	Deprecation       *DeprecationInfo      `json:"deprecation,omitempty"`       // @Deprecated
+	Versions          []string              `json:"versions,omitempty"`          // @Version, the route is registered once per version
+	Binding           int                   `json:"binding,omitempty"`           // index among the methods and paths of the handler's @Route
+	PathParams        []PathParamConstraint `json:"pathParams,omitempty"`        // typed segments of the @Route path, e.g. {id:int}
+	TypedHandler      bool                  `json:"typedHandler,omitempty"`      // func(c *gin.Context, req Req) (Res, error), wrapped with Typed
+	InferredRequest   string                `json:"inferredRequest,omitempty"`   // request body type shown by the handler
+	InferredResponses []ResponseInfo        `json:"inferredResponses,omitempty"` // responses shown by the handler
+	Controller        *ControllerInfo       `json:"controller,omitempty"`        // struct of the handler method, nil for functions
+
+	Translations map[string]RouteTranslation `json:"translations,omitempty"` // @Summary.<locale>/@Description.<locale> by locale
 }
 
 // MarkerInstance represents a marker instance found
@@ -34,6 +50,7 @@ type MarkerInstance struct {
 	Name string   // Auth, Cache, etc.
 	Args []string // parsed arguments
 	Raw  string   // original comment text
+	Line int      // source line where the marker was found
 }
 
 // GenData data passed to generation template
@@ -45,6 +62,20 @@ type GenData struct {
 	GeneratedAt string                 // generation timestamp
 }
 
+// ExternalHandlers reports whether the handlers live outside the generated package, being referenced
+// qualified by their package ("handlers.GetUser") and imported
+func (d *GenData) ExternalHandlers() bool {
+	if d.PackageName == DefaultPackageName {
+		return true
+	}
+	for _, route := range d.Routes {
+		if route.PackageName != "" && route.PackageName != d.PackageName {
+			return true
+		}
+	}
+	return false
+}
+
 // Hooks for extensibility
 type (
 	// ParserHook executed after parsing routes
@@ -148,7 +179,7 @@ func getRequiredImports(data *GenData) []string {
 
 // shouldAddHandlersImport checks if handlers import should be added
 func shouldAddHandlersImport(data *GenData) bool {
-	return data.PackageName == "deco" && len(data.Routes) > 0
+	return data.ExternalHandlers() && len(data.Routes) > 0
 }
 
 // buildHandlersImport builds the handlers import path
@@ -201,6 +201,15 @@ func TestShouldAddHandlersImport(t *testing.T) {
 	assert.IsType(t, false, shouldAdd)
 }
 
+func TestGenData_ExternalHandlers(t *testing.T) {
+	routes := []*RouteMeta{{PackageName: "handlers", FuncName: "GetUser"}}
+
+	assert.True(t, (&GenData{PackageName: "deco", Routes: routes}).ExternalHandlers())
+	assert.True(t, (&GenData{PackageName: "gen", Routes: routes}).ExternalHandlers(), "generation.package other than the handlers")
+	assert.False(t, (&GenData{PackageName: "handlers", Routes: routes}).ExternalHandlers(), "generated next to the handlers")
+	assert.True(t, shouldAddHandlersImport(&GenData{PackageName: "gen", Routes: routes}))
+}
+
 func TestBuildHandlersImport(t *testing.T) {
 	// Test building handlers import
 	data := &GenData{
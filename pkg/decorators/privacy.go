@@ -0,0 +1,284 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultPIIReplacement value that replaces personal data scrubbed by the privacy policy
+const DefaultPIIReplacement = "[PII]"
+
+// Built-in PII detectors
+const (
+	PIIDetectorEmail = "email" // e-mail addresses
+	PIIDetectorCard  = "card"  // payment card numbers (Luhn-checked)
+	PIIDetectorCPF   = "cpf"   // Brazilian CPF numbers (check digits verified)
+)
+
+// piiDetector matches personal data in free text; check discards false positives
+type piiDetector struct {
+	pattern *regexp.Regexp
+	check   func(match string) bool
+}
+
+// piiDetectors built-in detectors by name
+var piiDetectors = map[string]piiDetector{
+	PIIDetectorEmail: {pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	PIIDetectorCard:  {pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`), check: luhnValid},
+	PIIDetectorCPF:   {pattern: regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`), check: cpfValid},
+}
+
+// PIIPolicy central policy scrubbing personal data from logs, trace attributes, audit events and
+// recorded bodies. Fields whose name matches a pattern ("password", "*_token") are replaced as a
+// whole; any other string is searched for e-mails, card numbers, CPFs and custom patterns.
+// A nil policy leaves values untouched.
+type PIIPolicy struct {
+	fields      []string
+	detectors   []piiDetector
+	replacement string
+}
+
+var (
+	piiPolicy      *PIIPolicy
+	piiPolicyMutex sync.RWMutex
+)
+
+// validate checks the privacy section of the configuration
+func (p PrivacyConfig) validate() error {
+	_, err := NewPIIPolicy(p)
+	return err
+}
+
+// NewPIIPolicy compiles the privacy configuration; an empty detector list enables all built-in detectors
+func NewPIIPolicy(config PrivacyConfig) (*PIIPolicy, error) {
+	policy := &PIIPolicy{replacement: config.Replacement}
+	if policy.replacement == "" {
+		policy.replacement = DefaultPIIReplacement
+	}
+
+	for _, field := range config.Fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if _, err := path.Match(field, ""); err != nil || field == "" {
+			return nil, fmt.Errorf("invalid privacy.fields pattern '%s'", field)
+		}
+		policy.fields = append(policy.fields, field)
+	}
+
+	detectors := config.Detectors
+	if len(detectors) == 0 {
+		detectors = []string{PIIDetectorEmail, PIIDetectorCard, PIIDetectorCPF}
+	}
+	for _, name := range detectors {
+		detector, ok := piiDetectors[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid privacy.detectors '%s' (valid: email, card, cpf)", name)
+		}
+		policy.detectors = append(policy.detectors, detector)
+	}
+
+	for _, expr := range config.Patterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid privacy.patterns '%s': %v", expr, err)
+		}
+		policy.detectors = append(policy.detectors, piiDetector{pattern: pattern})
+	}
+
+	return policy, nil
+}
+
+// ConfigurePrivacy installs the policy of the privacy section; a disabled section removes it
+func ConfigurePrivacy(config PrivacyConfig) error {
+	var policy *PIIPolicy
+	if config.Enabled {
+		compiled, err := NewPIIPolicy(config)
+		if err != nil {
+			return err
+		}
+		policy = compiled
+	}
+
+	piiPolicyMutex.Lock()
+	defer piiPolicyMutex.Unlock()
+	piiPolicy = policy
+	return nil
+}
+
+// GetPIIPolicy returns the installed policy (nil when privacy is disabled)
+func GetPIIPolicy() *PIIPolicy {
+	piiPolicyMutex.RLock()
+	defer piiPolicyMutex.RUnlock()
+	return piiPolicy
+}
+
+// SensitiveField reports whether a field name matches one of the configured patterns
+func (p *PIIPolicy) SensitiveField(name string) bool {
+	if p == nil {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, pattern := range p.fields {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrubString replaces personal data found in free text
+func (p *PIIPolicy) ScrubString(value string) string {
+	if p == nil || value == "" {
+		return value
+	}
+	for _, detector := range p.detectors {
+		value = detector.pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if detector.check != nil && !detector.check(match) {
+				return match
+			}
+			return p.replacement
+		})
+	}
+	return value
+}
+
+// ScrubField scrubs the value of a named field (log field, header, query parameter, attribute)
+func (p *PIIPolicy) ScrubField(name, value string) string {
+	if p.SensitiveField(name) && value != "" {
+		return p.replacement
+	}
+	return p.ScrubString(value)
+}
+
+// ScrubURL scrubs the path and the query parameters of a request URI
+func (p *PIIPolicy) ScrubURL(uri string) string {
+	if p == nil || uri == "" {
+		return uri
+	}
+	rawPath, rawQuery, hasQuery := strings.Cut(uri, "?")
+	rawPath = p.ScrubString(rawPath)
+	if !hasQuery {
+		return rawPath
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawPath + "?" + p.ScrubString(rawQuery)
+	}
+	for name, values := range query {
+		for i := range values {
+			values[i] = p.ScrubField(name, values[i])
+		}
+	}
+	return rawPath + "?" + query.Encode()
+}
+
+// ScrubJSON returns a copy of a JSON document with sensitive fields replaced and personal data
+// removed from string values. Bodies that are not JSON are scrubbed as text.
+func (p *PIIPolicy) ScrubJSON(data []byte) []byte {
+	if p == nil || len(bytes.TrimSpace(data)) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return []byte(p.ScrubString(string(data)))
+	}
+
+	scrubbed, err := json.Marshal(p.scrubValue("", document))
+	if err != nil {
+		return []byte(p.ScrubString(string(data)))
+	}
+	return scrubbed
+}
+
+// scrubValue walks a decoded JSON value; name is the key holding it
+func (p *PIIPolicy) scrubValue(name string, value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = p.scrubValue(key, child)
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = p.scrubValue(name, child)
+		}
+		return typed
+	case string:
+		return p.ScrubField(name, typed)
+	default:
+		if name != "" && p.SensitiveField(name) {
+			return p.replacement
+		}
+		return value
+	}
+}
+
+// digitsOf returns the digits of a number written with separators
+func digitsOf(value string) []int {
+	digits := make([]int, 0, len(value))
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	return digits
+}
+
+// luhnValid verifies the Luhn checksum of a card number
+func luhnValid(value string) bool {
+	digits := digitsOf(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// cpfValid verifies the two check digits of a CPF
+func cpfValid(value string) bool {
+	digits := digitsOf(value)
+	if len(digits) != 11 {
+		return false
+	}
+	allEqual := true
+	for _, digit := range digits[1:] {
+		allEqual = allEqual && digit == digits[0]
+	}
+	if allEqual {
+		return false
+	}
+
+	for position := 9; position <= 10; position++ {
+		sum := 0
+		for i := 0; i < position; i++ {
+			sum += digits[i] * (position + 1 - i)
+		}
+		check := sum * 10 % 11
+		if check == 10 {
+			check = 0
+		}
+		if check != digits[position] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,304 @@
+package decorators
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyPathPrefix prefix of the data-subject (export and erasure) endpoints
+const PrivacyPathPrefix = "/decorators/privacy"
+
+// Data-subject request types
+const (
+	PrivacyJobExport = "export"
+	PrivacyJobDelete = "delete"
+)
+
+// Data-subject job states
+const (
+	PrivacyJobPending   = "pending"
+	PrivacyJobRunning   = "running"
+	PrivacyJobCompleted = "completed"
+	PrivacyJobFailed    = "failed"
+)
+
+// privacyJobRetention how long finished jobs stay queryable
+const privacyJobRetention = 24 * time.Hour
+
+// DataDomain export and erasure callbacks of one data domain (e.g. "orders", "profile").
+// Either callback may be nil when the domain does not support the operation.
+type DataDomain struct {
+	Export func(ctx context.Context, subjectID string) (interface{}, error)
+	Erase  func(ctx context.Context, subjectID string) error
+}
+
+// PrivacyJob asynchronous data-subject request dispatched to the registered domains
+type PrivacyJob struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"` // "export" or "delete"
+	SubjectID   string                 `json:"subject_id"`
+	Domains     []string               `json:"domains"`
+	Status      string                 `json:"status"`
+	Data        map[string]interface{} `json:"data,omitempty"`   // exported data by domain
+	Errors      map[string]string      `json:"errors,omitempty"` // failures by domain
+	CreatedAt   time.Time              `json:"created_at"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+}
+
+// PrivacyAudit audit event of a data-subject request
+type PrivacyAudit struct {
+	Event     string    `json:"event"` // "privacy_request"
+	JobID     string    `json:"job_id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	SubjectID string    `json:"subject_id"`
+	Domains   []string  `json:"domains"`
+	Errors    []string  `json:"errors,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var (
+	dataDomains      = make(map[string]DataDomain)
+	privacyJobs      = make(map[string]*PrivacyJob)
+	privacyMutex     sync.RWMutex
+	privacyAuditFunc func(PrivacyAudit)
+)
+
+// RegisterDataDomain registers the export and erasure callbacks of a data domain
+func RegisterDataDomain(name string, domain DataDomain) {
+	privacyMutex.Lock()
+	defer privacyMutex.Unlock()
+	dataDomains[name] = domain
+}
+
+// SetPrivacyAuditHandler registers a callback for data-subject request events (nil restores logging only)
+func SetPrivacyAuditHandler(handler func(PrivacyAudit)) {
+	privacyMutex.Lock()
+	defer privacyMutex.Unlock()
+	privacyAuditFunc = handler
+}
+
+// GetPrivacyJob returns a copy of a data-subject job
+func GetPrivacyJob(id string) (PrivacyJob, bool) {
+	privacyMutex.RLock()
+	defer privacyMutex.RUnlock()
+	job, ok := privacyJobs[id]
+	if !ok {
+		return PrivacyJob{}, false
+	}
+	return *job, true
+}
+
+// StartPrivacyJob validates a data-subject request and runs it in the background.
+// An empty domain list selects every registered domain supporting the operation.
+func StartPrivacyJob(jobType, subjectID string, domains []string) (PrivacyJob, error) {
+	if jobType != PrivacyJobExport && jobType != PrivacyJobDelete {
+		return PrivacyJob{}, fmt.Errorf("invalid privacy job type '%s' (valid: export, delete)", jobType)
+	}
+	if subjectID == "" {
+		return PrivacyJob{}, fmt.Errorf("subject_id is required")
+	}
+
+	privacyMutex.Lock()
+	selected, err := selectDataDomains(jobType, domains)
+	if err != nil {
+		privacyMutex.Unlock()
+		return PrivacyJob{}, err
+	}
+	pruneFinishedPrivacyJobs(time.Now())
+	job := &PrivacyJob{
+		ID:        newPrivacyJobID(),
+		Type:      jobType,
+		SubjectID: subjectID,
+		Domains:   selected,
+		Status:    PrivacyJobPending,
+		CreatedAt: time.Now(),
+	}
+	privacyJobs[job.ID] = job
+	snapshot := *job
+	privacyMutex.Unlock()
+
+	reportPrivacyJob(snapshot)
+	go runPrivacyJob(job.ID)
+	return snapshot, nil
+}
+
+// selectDataDomains resolves the requested domains; the caller holds privacyMutex
+func selectDataDomains(jobType string, requested []string) ([]string, error) {
+	supports := func(domain DataDomain) bool {
+		if jobType == PrivacyJobExport {
+			return domain.Export != nil
+		}
+		return domain.Erase != nil
+	}
+
+	var selected []string
+	if len(requested) == 0 {
+		for name, domain := range dataDomains {
+			if supports(domain) {
+				selected = append(selected, name)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no data domain supports %s", jobType)
+		}
+		sort.Strings(selected)
+		return selected, nil
+	}
+
+	for _, name := range requested {
+		domain, ok := dataDomains[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown data domain '%s'", name)
+		}
+		if !supports(domain) {
+			return nil, fmt.Errorf("data domain '%s' does not support %s", name, jobType)
+		}
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+// runPrivacyJob dispatches a job to its domains, recording per-domain results
+func runPrivacyJob(id string) {
+	privacyMutex.Lock()
+	job := privacyJobs[id]
+	job.Status = PrivacyJobRunning
+	jobType, subjectID := job.Type, job.SubjectID
+	domains := make([]DataDomain, len(job.Domains))
+	for i, name := range job.Domains {
+		domains[i] = dataDomains[name]
+	}
+	names := append([]string(nil), job.Domains...)
+	privacyMutex.Unlock()
+
+	ctx := context.Background()
+	data := make(map[string]interface{})
+	failures := make(map[string]string)
+	for i, domain := range domains {
+		var err error
+		if jobType == PrivacyJobExport {
+			var exported interface{}
+			if exported, err = domain.Export(ctx, subjectID); err == nil {
+				data[names[i]] = exported
+			}
+		} else {
+			err = domain.Erase(ctx, subjectID)
+		}
+		if err != nil {
+			failures[names[i]] = err.Error()
+		}
+	}
+
+	privacyMutex.Lock()
+	completedAt := time.Now()
+	job.CompletedAt = &completedAt
+	job.Status = PrivacyJobCompleted
+	if len(failures) > 0 {
+		job.Status, job.Errors = PrivacyJobFailed, failures
+	}
+	if len(data) > 0 {
+		job.Data = data
+	}
+	snapshot := *job
+	privacyMutex.Unlock()
+
+	reportPrivacyJob(snapshot)
+}
+
+// pruneFinishedPrivacyJobs drops jobs finished before the retention window; the caller holds privacyMutex
+func pruneFinishedPrivacyJobs(now time.Time) {
+	for id, job := range privacyJobs {
+		if job.CompletedAt != nil && now.Sub(*job.CompletedAt) > privacyJobRetention {
+			delete(privacyJobs, id)
+		}
+	}
+}
+
+// newPrivacyJobID returns a random job identifier
+func newPrivacyJobID() string {
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// reportPrivacyJob logs and dispatches the audit event of a job state change.
+// Exported data never reaches the audit trail, only the domains involved.
+func reportPrivacyJob(job PrivacyJob) {
+	event := PrivacyAudit{
+		Event:     "privacy_request",
+		JobID:     job.ID,
+		Type:      job.Type,
+		Status:    job.Status,
+		SubjectID: GetPIIPolicy().ScrubField("subject_id", job.SubjectID),
+		Domains:   job.Domains,
+		Timestamp: time.Now(),
+	}
+	for domain, message := range job.Errors {
+		event.Errors = append(event.Errors, domain+": "+message)
+	}
+	sort.Strings(event.Errors)
+	if data, err := json.Marshal(event); err == nil {
+		LogNormal("🛡️  %s", data)
+	}
+
+	privacyMutex.RLock()
+	handler := privacyAuditFunc
+	privacyMutex.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// privacyJobRequest body of the export and delete endpoints
+type privacyJobRequest struct {
+	SubjectID string   `json:"subject_id" binding:"required"`
+	Domains   []string `json:"domains,omitempty"`
+}
+
+// RegisterPrivacyRoutes registers the data-subject endpoints under /decorators/privacy.
+// They return personal data and erase it, so callers are expected to pass security middlewares.
+func RegisterPrivacyRoutes(r gin.IRouter, middlewares ...gin.HandlerFunc) {
+	group := r.Group(PrivacyPathPrefix, middlewares...)
+	group.POST("/export", privacyJobHandler(PrivacyJobExport))
+	group.POST("/delete", privacyJobHandler(PrivacyJobDelete))
+	group.GET("/jobs/:id", privacyJobStatusHandler)
+}
+
+// privacyJobHandler POST /decorators/privacy/{export,delete} {"subject_id": "...", "domains": [...]}
+func privacyJobHandler(jobType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request privacyJobRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+			return
+		}
+
+		job, err := StartPrivacyJob(jobType, request.SubjectID, request.Domains)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid_domains", "message": err.Error()})
+			return
+		}
+		c.Header("Location", PrivacyPathPrefix+"/jobs/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+// privacyJobStatusHandler GET /decorators/privacy/jobs/:id
+func privacyJobStatusHandler(c *gin.Context) {
+	job, ok := GetPrivacyJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job_not_found", "message": "unknown or expired privacy job"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
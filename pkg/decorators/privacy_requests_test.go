@@ -0,0 +1,145 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetDataDomains() {
+	privacyMutex.Lock()
+	defer privacyMutex.Unlock()
+	dataDomains = make(map[string]DataDomain)
+	privacyJobs = make(map[string]*PrivacyJob)
+}
+
+func privacyRequest(t *testing.T, router *gin.Engine, method, path, body string) (int, PrivacyJob) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var job PrivacyJob
+	if w.Code < 300 {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	}
+	return w.Code, job
+}
+
+func waitPrivacyJob(t *testing.T, router *gin.Engine, id string) PrivacyJob {
+	t.Helper()
+	var job PrivacyJob
+	require.Eventually(t, func() bool {
+		_, job = privacyRequest(t, router, http.MethodGet, PrivacyPathPrefix+"/jobs/"+id, "")
+		return job.CompletedAt != nil
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestPrivacyRoutes_Export(t *testing.T) {
+	resetDataDomains()
+	defer resetDataDomains()
+	RegisterDataDomain("profile", DataDomain{
+		Export: func(ctx context.Context, subjectID string) (interface{}, error) {
+			return map[string]string{"id": subjectID, "name": "Ana"}, nil
+		},
+	})
+	RegisterDataDomain("orders", DataDomain{
+		Export: func(ctx context.Context, subjectID string) (interface{}, error) { return []int{1, 2}, nil },
+		Erase:  func(ctx context.Context, subjectID string) error { return nil },
+	})
+
+	var mu sync.Mutex
+	var events []PrivacyAudit
+	SetPrivacyAuditHandler(func(event PrivacyAudit) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	defer SetPrivacyAuditHandler(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterPrivacyRoutes(router)
+
+	code, job := privacyRequest(t, router, http.MethodPost, PrivacyPathPrefix+"/export", `{"subject_id": "u-42"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, PrivacyJobExport, job.Type)
+	assert.Equal(t, []string{"orders", "profile"}, job.Domains)
+
+	job = waitPrivacyJob(t, router, job.ID)
+	assert.Equal(t, PrivacyJobCompleted, job.Status)
+	assert.Equal(t, "Ana", job.Data["profile"].(map[string]interface{})["name"])
+	assert.Len(t, job.Data["orders"], 2)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 2)
+	assert.Equal(t, PrivacyJobPending, events[0].Status)
+	assert.Equal(t, PrivacyJobCompleted, events[1].Status)
+	assert.Equal(t, "u-42", events[1].SubjectID)
+}
+
+func TestPrivacyRoutes_Delete(t *testing.T) {
+	resetDataDomains()
+	defer resetDataDomains()
+	var erased []string
+	var mu sync.Mutex
+	RegisterDataDomain("profile", DataDomain{Export: func(ctx context.Context, subjectID string) (interface{}, error) { return nil, nil }})
+	RegisterDataDomain("orders", DataDomain{Erase: func(ctx context.Context, subjectID string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		erased = append(erased, subjectID)
+		return nil
+	}})
+	RegisterDataDomain("billing", DataDomain{Erase: func(ctx context.Context, subjectID string) error {
+		return errors.New("legal hold")
+	}})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterPrivacyRoutes(router)
+
+	code, _ := privacyRequest(t, router, http.MethodPost, PrivacyPathPrefix+"/delete", `{"subject_id": "u-42", "domains": ["profile"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "profile cannot erase")
+	code, _ = privacyRequest(t, router, http.MethodPost, PrivacyPathPrefix+"/delete", `{"domains": ["orders"]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = privacyRequest(t, router, http.MethodGet, PrivacyPathPrefix+"/jobs/nope", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, job := privacyRequest(t, router, http.MethodPost, PrivacyPathPrefix+"/delete", `{"subject_id": "u-42"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, []string{"billing", "orders"}, job.Domains)
+
+	job = waitPrivacyJob(t, router, job.ID)
+	assert.Equal(t, PrivacyJobFailed, job.Status)
+	assert.Equal(t, map[string]string{"billing": "legal hold"}, job.Errors)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"u-42"}, erased)
+}
+
+func TestPruneFinishedPrivacyJobs(t *testing.T) {
+	resetDataDomains()
+	defer resetDataDomains()
+	old := time.Now().Add(-2 * privacyJobRetention)
+	privacyJobs["old"] = &PrivacyJob{ID: "old", CompletedAt: &old}
+	privacyJobs["running"] = &PrivacyJob{ID: "running", CreatedAt: old}
+
+	pruneFinishedPrivacyJobs(time.Now())
+	_, found := GetPrivacyJob("old")
+	assert.False(t, found)
+	_, found = GetPrivacyJob("running")
+	assert.True(t, found)
+}
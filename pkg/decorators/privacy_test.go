@@ -0,0 +1,110 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIIPolicy_ScrubString(t *testing.T) {
+	policy, err := NewPIIPolicy(PrivacyConfig{Patterns: []string{`\+55 ?\d{2} ?\d{4,5}-?\d{4}`}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "contact [PII] now", policy.ScrubString("contact ana@example.com now"))
+	assert.Equal(t, "card [PII]", policy.ScrubString("card 4111 1111 1111 1111"))
+	assert.Equal(t, "order 4111111111111112", policy.ScrubString("order 4111111111111112"), "fails the Luhn check")
+	assert.Equal(t, "cpf [PII]", policy.ScrubString("cpf 529.982.247-25"))
+	assert.Equal(t, "id 52998224726", policy.ScrubString("id 52998224726"), "wrong check digits")
+	assert.Equal(t, "call [PII]", policy.ScrubString("call +55 11 91234-5678"))
+
+	var nilPolicy *PIIPolicy
+	assert.Equal(t, "ana@example.com", nilPolicy.ScrubString("ana@example.com"))
+	assert.Equal(t, []byte(`{"a":1}`), nilPolicy.ScrubJSON([]byte(`{"a":1}`)))
+
+	only, err := NewPIIPolicy(PrivacyConfig{Detectors: []string{PIIDetectorCPF}, Replacement: "***"})
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com ***", only.ScrubString("ana@example.com 52998224725"))
+}
+
+func TestPIIPolicy_ScrubFields(t *testing.T) {
+	policy, err := NewPIIPolicy(PrivacyConfig{Fields: []string{"password", "*_token"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "[PII]", policy.ScrubField("Password", "hunter2"))
+	assert.Equal(t, "[PII]", policy.ScrubField("refresh_token", "abc"))
+	assert.Equal(t, "Ana", policy.ScrubField("name", "Ana"))
+
+	assert.Equal(t, "/users/[PII]/orders?access_token=%5BPII%5D&page=2",
+		policy.ScrubURL("/users/ana@example.com/orders?page=2&access_token=abc"))
+
+	scrubbed := policy.ScrubJSON([]byte(`{"user":{"email":"ana@example.com","password":"x","pin_token":1234},"items":[{"note":"card 4111111111111111"}],"total":10.5}`))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(scrubbed, &decoded))
+	user := decoded["user"].(map[string]interface{})
+	assert.Equal(t, "[PII]", user["email"])
+	assert.Equal(t, "[PII]", user["password"])
+	assert.Equal(t, "[PII]", user["pin_token"])
+	assert.Equal(t, "card [PII]", decoded["items"].([]interface{})[0].(map[string]interface{})["note"])
+	assert.Equal(t, 10.5, decoded["total"])
+
+	assert.Equal(t, "name=[PII]", string(policy.ScrubJSON([]byte("name=ana@example.com"))))
+}
+
+func TestPrivacyConfig_Validate(t *testing.T) {
+	for _, config := range []PrivacyConfig{
+		{Detectors: []string{"phone"}},
+		{Patterns: []string{"("}},
+		{Fields: []string{"[a"}},
+	} {
+		assert.Error(t, config.validate(), "config %+v", config)
+	}
+
+	config := DefaultConfig()
+	config.Privacy = PrivacyConfig{Enabled: true, Detectors: []string{"ssn"}}
+	assert.Error(t, config.Validate())
+}
+
+func TestPrivacy_AppliedToLogsAndCaptures(t *testing.T) {
+	require.NoError(t, ConfigurePrivacy(PrivacyConfig{Enabled: true, Fields: []string{"token"}}))
+	defer ConfigurePrivacy(PrivacyConfig{})
+
+	var logged bytes.Buffer
+	logger, err := NewAccessLoggerWithWriter(AccessLogConfig{Format: AccessLogFormatJSON}, &logged)
+	require.NoError(t, err)
+
+	var capture *BodyCapture
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(logger.Middleware())
+	router.POST("/signup", func(c *gin.Context) {
+		capture = CaptureBodies(c)
+		var body map[string]interface{}
+		require.NoError(t, c.ShouldBindJSON(&body))
+		c.JSON(http.StatusCreated, gin.H{"email": body["email"], "id": 7})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/signup?token=abc&ref=ana@example.com", strings.NewReader(`{"email":"ana@example.com","cpf":"529.982.247-25"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	assert.NotContains(t, logged.String(), "ana@example.com")
+	assert.NotContains(t, logged.String(), "abc")
+	assert.Contains(t, logged.String(), "/signup?")
+
+	require.NotNil(t, capture)
+	assert.JSONEq(t, `{"email":"[PII]","cpf":"[PII]"}`, string(capture.RedactedRequestBody()))
+	assert.JSONEq(t, `{"email":"[PII]","id":7}`, string(capture.RedactedResponseBody()))
+	assert.Contains(t, w.Body.String(), "ana@example.com", "the client still receives the real response")
+
+	require.NoError(t, ConfigurePrivacy(PrivacyConfig{Enabled: false, Fields: []string{"token"}}))
+	assert.Nil(t, GetPIIPolicy())
+}
@@ -0,0 +1,330 @@
+package decorators
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfilingPathPrefix prefix of the pprof endpoints
+const ProfilingPathPrefix = "/decorators/debug/pprof"
+
+// ProfileExporter continuously ships profiles to an external profiling backend
+type ProfileExporter interface {
+	Start() error
+	Stop() error
+}
+
+// ProfileExporterFactory creates an exporter from the profiling configuration
+type ProfileExporterFactory func(config ProfilingConfig) (ProfileExporter, error)
+
+var (
+	profileExporters = map[string]ProfileExporterFactory{
+		"pyroscope": newPyroscopeExporter,
+		"parca":     newParcaExporter,
+	}
+	profileExportersMux sync.RWMutex
+
+	activeProfileExporter    ProfileExporter
+	activeProfileExporterMux sync.Mutex
+)
+
+// RegisterProfileExporter registers a profiling backend usable as profiling.exporter
+func RegisterProfileExporter(name string, factory ProfileExporterFactory) {
+	profileExportersMux.Lock()
+	defer profileExportersMux.Unlock()
+	profileExporters[name] = factory
+}
+
+// getProfileExporterFactory returns the factory registered under name
+func getProfileExporterFactory(name string) (ProfileExporterFactory, bool) {
+	profileExportersMux.RLock()
+	defer profileExportersMux.RUnlock()
+	factory, exists := profileExporters[name]
+	return factory, exists
+}
+
+// validate checks the profiling section of the configuration
+func (p ProfilingConfig) validate() error {
+	if p.UploadInterval != "" {
+		interval, err := time.ParseDuration(p.UploadInterval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid profiling.upload_interval '%s'", p.UploadInterval)
+		}
+	}
+
+	if p.Exporter == "" {
+		return nil
+	}
+	if _, exists := getProfileExporterFactory(p.Exporter); !exists {
+		return fmt.Errorf("unknown profiling.exporter '%s'", p.Exporter)
+	}
+	if p.Exporter == "pyroscope" && p.ServerAddress == "" {
+		return fmt.Errorf("profiling.server_address is required for the pyroscope exporter")
+	}
+
+	return nil
+}
+
+// uploadInterval returns the configured collection window
+func (p ProfilingConfig) uploadInterval() time.Duration {
+	interval, err := time.ParseDuration(p.UploadInterval)
+	if err != nil || interval <= 0 {
+		return 15 * time.Second
+	}
+	return interval
+}
+
+// RegisterProfilingRoutes mounts the pprof endpoints under /decorators/debug/pprof, guarded by the given middlewares
+func RegisterProfilingRoutes(r gin.IRouter, middlewares ...gin.HandlerFunc) {
+	group := r.Group(ProfilingPathPrefix, middlewares...)
+
+	index := gin.WrapF(pprof.Index)
+	group.GET("", index)
+	group.GET("/", index)
+	group.GET("/:profile", func(c *gin.Context) {
+		switch profile := c.Param("profile"); profile {
+		case "cmdline":
+			pprof.Cmdline(c.Writer, c.Request)
+		case "profile":
+			pprof.Profile(c.Writer, c.Request)
+		case "symbol":
+			pprof.Symbol(c.Writer, c.Request)
+		case "trace":
+			pprof.Trace(c.Writer, c.Request)
+		default:
+			// pprof.Index only resolves names under /debug/pprof/, so named profiles are served directly
+			pprof.Handler(profile).ServeHTTP(c.Writer, c.Request)
+		}
+	})
+	group.POST("/symbol", gin.WrapF(pprof.Symbol))
+}
+
+// StartProfiling starts the configured profile exporter, replacing any running one
+func StartProfiling(config ProfilingConfig) error {
+	if config.Exporter == "" {
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	factory, _ := getProfileExporterFactory(config.Exporter)
+	exporter, err := factory(config)
+	if err != nil {
+		return fmt.Errorf("error creating %s profile exporter: %v", config.Exporter, err)
+	}
+
+	StopProfiling()
+	if err := exporter.Start(); err != nil {
+		return fmt.Errorf("error starting %s profile exporter: %v", config.Exporter, err)
+	}
+
+	activeProfileExporterMux.Lock()
+	activeProfileExporter = exporter
+	activeProfileExporterMux.Unlock()
+
+	LogVerbose("📈 Continuous profiling started (%s)", config.Exporter)
+	return nil
+}
+
+// StopProfiling stops the running profile exporter, if any
+func StopProfiling() {
+	activeProfileExporterMux.Lock()
+	exporter := activeProfileExporter
+	activeProfileExporter = nil
+	activeProfileExporterMux.Unlock()
+
+	if exporter == nil {
+		return
+	}
+	if err := exporter.Stop(); err != nil {
+		LogSilent("⚠️  Error stopping profile exporter: %v", err)
+	}
+}
+
+// pyroscopeExporter pushes CPU and heap profiles to the Pyroscope ingest API
+type pyroscopeExporter struct {
+	config   ProfilingConfig
+	interval time.Duration
+	client   *http.Client
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newPyroscopeExporter creates the built-in Pyroscope exporter
+func newPyroscopeExporter(config ProfilingConfig) (ProfileExporter, error) {
+	if _, err := url.Parse(config.ServerAddress); err != nil || config.ServerAddress == "" {
+		return nil, fmt.Errorf("invalid server address '%s'", config.ServerAddress)
+	}
+	interval := config.uploadInterval()
+	return &pyroscopeExporter{
+		config:   config,
+		interval: interval,
+		client:   &http.Client{Timeout: interval},
+	}, nil
+}
+
+// Start begins collecting profiles in the background
+func (e *pyroscopeExporter) Start() error {
+	if e.stop != nil {
+		return fmt.Errorf("exporter already started")
+	}
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	go e.run()
+	return nil
+}
+
+// Stop ends collection, uploading the profile of the current window
+func (e *pyroscopeExporter) Stop() error {
+	if e.stop == nil {
+		return nil
+	}
+	close(e.stop)
+	<-e.done
+	e.stop = nil
+	return nil
+}
+
+// run collects one CPU profile per interval and uploads it together with a heap snapshot
+func (e *pyroscopeExporter) run() {
+	defer close(e.done)
+
+	for {
+		from := time.Now()
+		var cpu bytes.Buffer
+		// StartCPUProfile fails while another CPU profile (e.g. /pprof/profile) is running; skip that window
+		cpuActive := runtimepprof.StartCPUProfile(&cpu) == nil
+
+		stopped := false
+		select {
+		case <-e.stop:
+			stopped = true
+		case <-time.After(e.interval):
+		}
+
+		if cpuActive {
+			runtimepprof.StopCPUProfile()
+		}
+		until := time.Now()
+
+		if cpuActive && cpu.Len() > 0 {
+			if err := e.upload("cpu", from, until, &cpu); err != nil {
+				LogVerbose("⚠️  Pyroscope CPU profile upload failed: %v", err)
+			}
+		}
+
+		var heap bytes.Buffer
+		if err := runtimepprof.Lookup("heap").WriteTo(&heap, 0); err == nil {
+			if err := e.upload("alloc_objects", from, until, &heap); err != nil {
+				LogVerbose("⚠️  Pyroscope heap profile upload failed: %v", err)
+			}
+		}
+
+		if stopped {
+			return
+		}
+	}
+}
+
+// upload sends a pprof-encoded profile to /ingest
+func (e *pyroscopeExporter) upload(kind string, from, until time.Time, profile io.Reader) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, profile); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("name", e.applicationName(kind))
+	query.Set("from", fmt.Sprintf("%d", from.Unix()))
+	query.Set("until", fmt.Sprintf("%d", until.Unix()))
+	query.Set("format", "pprof")
+	query.Set("spyName", "gospy")
+	query.Set("sampleRate", "100")
+
+	endpoint := strings.TrimSuffix(e.config.ServerAddress, "/") + "/ingest?" + query.Encode()
+	req, err := http.NewRequest(http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if e.config.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+e.config.AuthToken)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// applicationName builds the Pyroscope series name, e.g. "app.cpu{env=prod,region=eu}"
+func (e *pyroscopeExporter) applicationName(kind string) string {
+	name := e.config.AppName + "." + kind
+	if len(e.config.Tags) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(e.config.Tags))
+	for key := range e.config.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labels := make([]string, 0, len(keys))
+	for _, key := range keys {
+		labels = append(labels, key+"="+e.config.Tags[key])
+	}
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
+
+// parcaExporter prepares the process for Parca, which scrapes the pprof endpoints (pull model)
+type parcaExporter struct {
+	config ProfilingConfig
+}
+
+// newParcaExporter creates the built-in Parca exporter
+func newParcaExporter(config ProfilingConfig) (ProfileExporter, error) {
+	return &parcaExporter{config: config}, nil
+}
+
+// Start enables the block and mutex profiles Parca scrapes next to CPU and heap
+func (e *parcaExporter) Start() error {
+	runtime.SetBlockProfileRate(int(time.Millisecond))
+	runtime.SetMutexProfileFraction(100)
+	LogNormal("📈 Parca can scrape profiles from %s (enable profiling.enabled and allow the scraper in the security config)", ProfilingPathPrefix)
+	return nil
+}
+
+// Stop disables the extra runtime profiles
+func (e *parcaExporter) Stop() error {
+	runtime.SetBlockProfileRate(0)
+	runtime.SetMutexProfileFraction(0)
+	return nil
+}
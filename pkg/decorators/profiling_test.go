@@ -0,0 +1,148 @@
+package decorators
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProfilingRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterProfilingRoutes(router, SecureInternalEndpoints(DefaultSecurityConfig()))
+	return router
+}
+
+func TestRegisterProfilingRoutes(t *testing.T) {
+	router := newProfilingRouter()
+
+	tests := []struct {
+		path     string
+		contains string
+	}{
+		{ProfilingPathPrefix + "/", "heap"},
+		{ProfilingPathPrefix + "/heap?debug=1", "heap profile"},
+		{ProfilingPathPrefix + "/goroutine?debug=1", "goroutine profile"},
+		{ProfilingPathPrefix + "/cmdline", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			req.RemoteAddr = "127.0.0.1:12345"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), tt.contains)
+		})
+	}
+}
+
+func TestRegisterProfilingRoutes_Security(t *testing.T) {
+	router := newProfilingRouter()
+
+	req := httptest.NewRequest(http.MethodGet, ProfilingPathPrefix+"/heap", http.NoBody)
+	req.RemoteAddr = "203.0.113.10:12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProfilingConfigValidate(t *testing.T) {
+	config := DefaultConfig()
+	assert.NoError(t, config.Validate())
+
+	config.Profiling.Exporter = "pyroscope"
+	assert.Error(t, config.Validate(), "pyroscope requires a server address")
+
+	config.Profiling.ServerAddress = "http://pyroscope:4040"
+	assert.NoError(t, config.Validate())
+
+	config.Profiling.UploadInterval = "often"
+	assert.Error(t, config.Validate())
+
+	config.Profiling.UploadInterval = "10s"
+	config.Profiling.Exporter = "unknown"
+	assert.Error(t, config.Validate())
+}
+
+type fakeProfileExporter struct {
+	started, stopped bool
+}
+
+func (e *fakeProfileExporter) Start() error { e.started = true; return nil }
+func (e *fakeProfileExporter) Stop() error  { e.stopped = true; return nil }
+
+func TestRegisterProfileExporter(t *testing.T) {
+	exporter := &fakeProfileExporter{}
+	RegisterProfileExporter("fake", func(ProfilingConfig) (ProfileExporter, error) { return exporter, nil })
+	defer func() {
+		profileExportersMux.Lock()
+		delete(profileExporters, "fake")
+		profileExportersMux.Unlock()
+	}()
+
+	assert.NoError(t, StartProfiling(ProfilingConfig{Exporter: "fake"}))
+	assert.True(t, exporter.started)
+
+	StopProfiling()
+	assert.True(t, exporter.stopped)
+}
+
+func TestPyroscopeExporterUpload(t *testing.T) {
+	var mu sync.Mutex
+	var names []string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("profile")
+		if assert.NoError(t, err) {
+			data, _ := io.ReadAll(file)
+			assert.NotEmpty(t, data)
+		}
+		mu.Lock()
+		names = append(names, r.URL.Query().Get("name"))
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+		assert.Equal(t, "pprof", r.URL.Query().Get("format"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := newPyroscopeExporter(ProfilingConfig{
+		ServerAddress:  server.URL,
+		AuthToken:      "secret",
+		AppName:        "orders",
+		UploadInterval: "20ms",
+		Tags:           map[string]string{"region": "eu", "env": "prod"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, exporter.Start())
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, exporter.Stop())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "Bearer secret", auth)
+	assert.Contains(t, names, "orders.alloc_objects{env=prod,region=eu}")
+	for _, name := range names {
+		assert.True(t, strings.HasPrefix(name, "orders."))
+	}
+}
+
+func TestApplyDefaultsProfiling(t *testing.T) {
+	config := &Config{Profiling: ProfilingConfig{Enabled: true}}
+	applyDefaults(config)
+
+	assert.True(t, config.Profiling.Enabled)
+	assert.Equal(t, "gin-decorators", config.Profiling.AppName)
+	assert.Equal(t, "15s", config.Profiling.UploadInterval)
+}
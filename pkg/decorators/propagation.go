@@ -0,0 +1,113 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// W3C propagators used whether or not tracing is enabled
+var (
+	traceContextPropagator = propagation.TraceContext{}
+	baggagePropagator      = propagation.Baggage{}
+)
+
+// baggage keys forwarded to upstreams; nil forwards every key
+var (
+	baggageAllowlist      map[string]bool
+	baggageAllowlistMutex sync.RWMutex
+)
+
+// SetBaggageAllowlist restricts the baggage forwarded by InjectContext to the given keys;
+// an empty list forwards all baggage
+func SetBaggageAllowlist(keys []string) {
+	baggageAllowlistMutex.Lock()
+	defer baggageAllowlistMutex.Unlock()
+
+	if len(keys) == 0 {
+		baggageAllowlist = nil
+		return
+	}
+	baggageAllowlist = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		baggageAllowlist[key] = true
+	}
+}
+
+// InjectContext writes the W3C trace context and the allowed baggage of ctx to carrier
+// (outbound request headers, message headers...)
+func InjectContext(ctx context.Context, carrier propagation.TextMapCarrier) {
+	traceContextPropagator.Inject(ctx, carrier)
+	baggagePropagator.Inject(baggage.ContextWithBaggage(ctx, allowedBaggage(ctx)), carrier)
+}
+
+// ExtractContext reads the W3C trace context and baggage of carrier into ctx. Values already in
+// ctx win, so a span started by @Telemetry is not replaced by its remote parent.
+func ExtractContext(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = traceContextPropagator.Extract(ctx, carrier)
+	}
+	if baggage.FromContext(ctx).Len() == 0 {
+		ctx = baggagePropagator.Extract(ctx, carrier)
+	}
+	return ctx
+}
+
+// allowedBaggage returns the baggage of ctx restricted to the allowlist
+func allowedBaggage(ctx context.Context) baggage.Baggage {
+	bag := baggage.FromContext(ctx)
+
+	baggageAllowlistMutex.RLock()
+	defer baggageAllowlistMutex.RUnlock()
+	if baggageAllowlist == nil {
+		return bag
+	}
+	for _, member := range bag.Members() {
+		if !baggageAllowlist[member.Key()] {
+			bag = bag.DeleteMember(member.Key())
+		}
+	}
+	return bag
+}
+
+// PropagationMiddleware makes the trace context and baggage of the incoming request available
+// to handlers and outbound calls, without starting spans (see @Telemetry for tracing)
+func PropagationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(ExtractContext(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header)))
+		c.Next()
+	}
+}
+
+// propagatingTransport injects the context of each request into its headers
+type propagatingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip injects trace context and baggage and delegates to the base transport
+func (t *propagatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	InjectContext(req.Context(), propagation.HeaderCarrier(req.Header))
+	return t.base.RoundTrip(req)
+}
+
+// PropagatingTransport wraps base (nil uses http.DefaultTransport) so that outbound requests
+// carry the trace context and allowed baggage of their context
+func PropagatingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &propagatingTransport{base: base}
+}
+
+// NewHTTPClient creates an HTTP client for calls to other services that propagates the trace
+// context and allowed baggage; build requests with http.NewRequestWithContext(c.Request.Context(), ...)
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: PropagatingTransport(nil)}
+}
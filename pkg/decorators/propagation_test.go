@@ -0,0 +1,109 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// propagatedContext returns a context carrying a remote span and the given baggage
+func propagatedContext(t *testing.T, bag string) context.Context {
+	t.Helper()
+	headers := http.Header{}
+	headers.Set("traceparent", testTraceparent)
+	headers.Set("baggage", bag)
+	return ExtractContext(context.Background(), propagation.HeaderCarrier(headers))
+}
+
+func TestInjectContext_BaggageAllowlist(t *testing.T) {
+	ctx := propagatedContext(t, "tenant=acme,session=secret")
+
+	headers := http.Header{}
+	InjectContext(ctx, propagation.HeaderCarrier(headers))
+	assert.Equal(t, testTraceparent, headers.Get("traceparent"))
+	assert.Contains(t, headers.Get("baggage"), "session=secret")
+
+	SetBaggageAllowlist([]string{"tenant"})
+	defer SetBaggageAllowlist(nil)
+
+	headers = http.Header{}
+	InjectContext(ctx, propagation.HeaderCarrier(headers))
+	assert.Equal(t, "tenant=acme", headers.Get("baggage"))
+	// The context itself keeps all members
+	assert.Equal(t, "secret", baggage.FromContext(ctx).Member("session").Value())
+}
+
+func TestExtractContext_KeepsLocalSpan(t *testing.T) {
+	local := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), local)
+
+	headers := http.Header{}
+	headers.Set("traceparent", testTraceparent)
+	ctx = ExtractContext(ctx, propagation.HeaderCarrier(headers))
+	assert.Equal(t, local.TraceID(), trace.SpanContextFromContext(ctx).TraceID())
+}
+
+func TestNewHTTPClient_Propagates(t *testing.T) {
+	var received http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/orders", PropagationMiddleware(), func(c *gin.Context) {
+		req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, upstream.URL, nil)
+		require.NoError(t, err)
+		resp, err := NewHTTPClient(0).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("traceparent", testTraceparent)
+	req.Header.Set("baggage", "tenant=acme")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, received)
+	assert.Equal(t, testTraceparent, received.Get("traceparent"))
+	assert.Equal(t, "tenant=acme", received.Get("baggage"))
+}
+
+func TestOutbox_PropagatesContext(t *testing.T) {
+	var published *OutboxEvent
+	outbox, err := NewOutbox(NewMemoryOutboxStore(), PublisherFunc(func(_ context.Context, event *OutboxEvent) error {
+		published = event
+		return nil
+	}), OutboxConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, outbox.Enqueue(propagatedContext(t, "tenant=acme"), nil, "order.placed", "1", nil))
+	_, err = outbox.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, published)
+	assert.Equal(t, testTraceparent, published.Headers["traceparent"])
+	assert.Equal(t, "tenant=acme", published.Headers["baggage"])
+
+	// Consumers continue the trace
+	consumer := &subscriptionConsumer{entry: &SubscriptionEntry{Handler: func(ctx context.Context, _ *Message) error {
+		assert.Equal(t, "acme", baggage.FromContext(ctx).Member("tenant").Value())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+		return nil
+	}}}
+	require.NoError(t, consumer.deliver(context.Background(), &Message{Headers: published.Headers}))
+}
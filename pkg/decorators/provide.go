@@ -0,0 +1,152 @@
+package decorators
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Provide("repo", factory="NewUserRepo") builds a named dependency for each request of the route.
+// The factory, a func(c *gin.Context) (T, error) of the handler package, runs on the first
+// deco.Provided[T](c, "repo") of the request; with cache=false it runs on every call.
+
+// providerKeyPrefix prefix of the context keys holding the providers of a request
+const providerKeyPrefix = "deco.provider."
+
+// ProviderBinding dependency declared with @Provide
+type ProviderBinding struct {
+	Name    string `json:"name"`
+	Factory string `json:"factory"`
+	Cache   bool   `json:"cache"`
+}
+
+// parseProvideArgs parses @Provide arguments: a positional name, factory= and cache=
+func parseProvideArgs(args []string) (*ProviderBinding, error) {
+	binding := &ProviderBinding{Cache: true}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			if binding.Name != "" {
+				return nil, fmt.Errorf("@Provide: unexpected argument '%s'", arg)
+			}
+			binding.Name = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			binding.Name = value
+		case "factory":
+			binding.Factory = value
+		case "cache":
+			cache, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("@Provide: invalid cache '%s' (expected true or false)", value)
+			}
+			binding.Cache = cache
+		default:
+			return nil, fmt.Errorf("@Provide: unknown argument '%s' (valid: name, factory, cache)", strings.TrimSpace(key))
+		}
+	}
+
+	if binding.Name == "" {
+		return nil, fmt.Errorf("@Provide requires a dependency name, e.g. @Provide(\"repo\", factory=\"NewUserRepo\")")
+	}
+	if binding.Factory == "" {
+		return nil, fmt.Errorf("@Provide(\"%s\") requires a factory, e.g. factory=\"NewUserRepo\"", binding.Name)
+	}
+	if !grpcIdentifier.MatchString(binding.Factory) {
+		return nil, fmt.Errorf("@Provide: factory '%s' must be a function of the handler package", binding.Factory)
+	}
+	return binding, nil
+}
+
+// validateProvideMarkers rejects @Provide without arguments and names provided twice
+func validateProvideMarkers(route *RouteMeta) error {
+	seen := make(map[string]bool)
+	for _, marker := range route.Markers {
+		if marker.Name != "Provide" {
+			continue
+		}
+		binding, err := parseProvideArgs(marker.Args)
+		if err != nil {
+			return err
+		}
+		if seen[binding.Name] {
+			return fmt.Errorf("%s: dependency '%s' is provided twice", route.FuncName, binding.Name)
+		}
+		seen[binding.Name] = true
+	}
+	return nil
+}
+
+// providerInfo documents a provider in the middleware chain
+func (p ProviderBinding) providerInfo() MiddlewareInfo {
+	return MiddlewareInfo{
+		Name:        "Provide",
+		Args:        map[string]interface{}{"name": p.Name, "factory": p.Factory, "cache": strconv.FormatBool(p.Cache)},
+		Description: getMiddlewareDescription("Provide"),
+	}
+}
+
+// provider builds one dependency of a request
+type provider struct {
+	build func(c *gin.Context) (interface{}, error)
+	cache bool
+
+	mu    sync.Mutex
+	built bool
+	value interface{}
+	err   error
+}
+
+// get returns the dependency, built once per request when cached
+func (p *provider) get(c *gin.Context) (interface{}, error) {
+	if !p.cache {
+		return p.build(c)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.built {
+		p.value, p.err = p.build(c)
+		p.built = true
+	}
+	return p.value, p.err
+}
+
+// Provide makes the dependency built by factory available to the request as name; the generated
+// code calls it for each @Provide of a route
+func Provide[T any](name string, factory func(c *gin.Context) (T, error), cache bool) gin.HandlerFunc {
+	build := func(c *gin.Context) (interface{}, error) {
+		return factory(c)
+	}
+	return func(c *gin.Context) {
+		c.Set(providerKeyPrefix+name, &provider{build: build, cache: cache})
+		c.Next()
+	}
+}
+
+// Provided returns the dependency name of the request, building it on first use
+func Provided[T any](c *gin.Context, name string) (T, error) {
+	var zero T
+	value, ok := c.Get(providerKeyPrefix + name)
+	if !ok {
+		return zero, fmt.Errorf("dependency '%s' is not provided to %s (missing @Provide)", name, c.FullPath())
+	}
+
+	dependency, err := value.(*provider).get(c)
+	if err != nil {
+		return zero, fmt.Errorf("dependency '%s': %v", name, err)
+	}
+	typed, ok := dependency.(T)
+	if !ok {
+		return zero, fmt.Errorf("dependency '%s' is %T, not %v", name, dependency, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return typed, nil
+}
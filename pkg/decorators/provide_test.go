@@ -0,0 +1,144 @@
+package decorators
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProvideArgs(t *testing.T) {
+	binding, err := parseProvideArgs([]string{`"repo"`, `factory="NewUserRepo"`})
+	require.NoError(t, err)
+	assert.Equal(t, &ProviderBinding{Name: "repo", Factory: "NewUserRepo", Cache: true}, binding)
+
+	binding, err = parseProvideArgs([]string{`name="clock"`, `factory="NewClock"`, "cache=false"})
+	require.NoError(t, err)
+	assert.False(t, binding.Cache)
+
+	for _, args := range [][]string{
+		{`factory="NewUserRepo"`},
+		{`"repo"`},
+		{`"repo"`, `factory="repos.New"`},
+		{`"repo"`, `factory="NewUserRepo"`, "cache=sometimes"},
+		{`"repo"`, `factory="NewUserRepo"`, `scope="request"`},
+	} {
+		_, err := parseProvideArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+type testUserRepo struct{ tenant string }
+
+func TestProvided(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	builds := 0
+	newRepo := func(c *gin.Context) (*testUserRepo, error) {
+		builds++
+		return &testUserRepo{tenant: c.GetHeader("X-Tenant")}, nil
+	}
+	failing := func(c *gin.Context) (int, error) { return 0, errors.New("pool exhausted") }
+
+	router := gin.New()
+	router.GET("/users", Provide("repo", newRepo, true), Provide("broken", failing, true), func(c *gin.Context) {
+		first, err := Provided[*testUserRepo](c, "repo")
+		require.NoError(t, err)
+		second, _ := Provided[*testUserRepo](c, "repo")
+		assert.Same(t, first, second, "cached per request")
+
+		_, err = Provided[string](c, "repo")
+		assert.ErrorContains(t, err, "not string")
+		_, err = Provided[int](c, "broken")
+		assert.ErrorContains(t, err, "pool exhausted")
+		_, err = Provided[int](c, "missing")
+		assert.ErrorContains(t, err, "missing @Provide")
+
+		c.String(http.StatusOK, first.tenant)
+	})
+
+	for _, tenant := range []string{"acme", "globex"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+		req.Header.Set("X-Tenant", tenant)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tenant, w.Body.String())
+	}
+	assert.Equal(t, 2, builds, "one build per request")
+}
+
+func TestProvidedWithoutCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	builds := 0
+	newClock := func(c *gin.Context) (int, error) {
+		builds++
+		return builds, nil
+	}
+
+	router := gin.New()
+	router.GET("/now", Provide("clock", newClock, false), func(c *gin.Context) {
+		first, _ := Provided[int](c, "clock")
+		second, _ := Provided[int](c, "clock")
+		assert.NotEqual(t, first, second)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/now", http.NoBody))
+	assert.Equal(t, 2, builds)
+}
+
+func TestGenerateInitFile_Provide(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Provide("repo", factory="NewUserRepo")
+// @Provide("clock", factory="NewClock", cache=false)
+// @Cache(ttl=1m)
+func ListUsers(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	config := DefaultConfig()
+	config.Metrics.SlowThreshold = "2s"
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", config))
+
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `decorators.Provide("repo", NewUserRepo, true),
+			decorators.Provide("clock", NewClock, false),
+			deco.SlowThresholdMiddleware(2 * time.Second),`)
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	applySlowThresholdDefault(routes, "2s")
+	names := make([]string, 0, len(routes[0].MiddlewareInfo))
+	for _, info := range routes[0].MiddlewareInfo {
+		names = append(names, info.Name)
+	}
+	assert.Equal(t, []string{"Provide", "Provide", "SlowThreshold", "Cache"}, names)
+
+	// A name provided twice fails generation
+	source = `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Provide("repo", factory="NewUserRepo")
+// @Provide("repo", factory="NewCachedUserRepo")
+func ListUsers(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+	_, err = ParseDirectory(dir)
+	assert.ErrorContains(t, err, "provided twice")
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // ProxyConfig configuration for proxy middleware
@@ -67,6 +70,11 @@ type ProxyManager struct {
 	httpClient     *http.Client
 	config         ProxyConfig
 	mu             sync.RWMutex
+
+	// Counters of /decorators/stats
+	requests atomic.Int64 // requests forwarded or refused
+	failures atomic.Int64 // requests answered with a 5xx by the proxy or the upstream
+	retries  atomic.Int64 // attempts after the first
 }
 
 // LoadBalancer interface for different load balancing algorithms
@@ -103,6 +111,69 @@ func clearProxyManagers() {
 	proxyManagers = make(map[string]*ProxyManager)
 }
 
+// ProxyStats counters of the requests forwarded by a @Proxy upstream
+type ProxyStats struct {
+	Name           string               `json:"name"` // service, or target URL
+	Requests       int64                `json:"requests"`
+	Failures       int64                `json:"failures"`
+	Retries        int64                `json:"retries"`
+	FailureRate    float64              `json:"failure_rate"`
+	CircuitBreaker string               `json:"circuit_breaker"`
+	Instances      []ProxyInstanceStats `json:"instances"`
+}
+
+// ProxyInstanceStats state of an instance of a @Proxy upstream
+type ProxyInstanceStats struct {
+	URL          string `json:"url"`
+	Healthy      bool   `json:"healthy"`
+	ActiveConns  int    `json:"active_conns"`
+	FailureCount int    `json:"failure_count"`
+}
+
+// ProxyManagerStats returns the counters of the @Proxy upstreams, sorted by name
+func ProxyManagerStats() []ProxyStats {
+	proxyManagersMu.RLock()
+	managers := make([]*ProxyManager, 0, len(proxyManagers))
+	for _, manager := range proxyManagers {
+		managers = append(managers, manager)
+	}
+	proxyManagersMu.RUnlock()
+
+	stats := make([]ProxyStats, 0, len(managers))
+	for _, manager := range managers {
+		name := MarkerValue(manager.config.Service)
+		if name == "" {
+			name = MarkerValue(manager.config.Target)
+		}
+		entry := ProxyStats{
+			Name:           name,
+			Requests:       manager.requests.Load(),
+			Failures:       manager.failures.Load(),
+			Retries:        manager.retries.Load(),
+			CircuitBreaker: manager.circuitBreaker.GetState(),
+		}
+		if entry.Requests > 0 {
+			entry.FailureRate = float64(entry.Failures) / float64(entry.Requests)
+		}
+
+		manager.mu.RLock()
+		for _, instance := range manager.instances {
+			instance.mu.RLock()
+			entry.Instances = append(entry.Instances, ProxyInstanceStats{
+				URL:          instance.URL,
+				Healthy:      instance.Healthy,
+				ActiveConns:  instance.ActiveConns,
+				FailureCount: instance.FailureCount,
+			})
+			instance.mu.RUnlock()
+		}
+		manager.mu.RUnlock()
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
 // Default configurations
 const (
 	DefaultTimeout          = "10s"
@@ -234,7 +305,7 @@ func getOrCreateProxyManager(config *ProxyConfig) *ProxyManager {
 // NewProxyManager creates a new proxy manager
 func NewProxyManager(config *ProxyConfig) *ProxyManager {
 	// Parse timeouts
-	timeout, _ := time.ParseDuration(config.Timeout)
+	timeout, _ := ParseDurationLiteral(config.Timeout)
 	if timeout == 0 {
 		timeout = 10 * time.Second
 	}
@@ -306,6 +377,13 @@ func (pm *ProxyManager) initializeInstances() {
 
 // Forward forwards the request to the selected instance
 func (pm *ProxyManager) Forward(c *gin.Context, config *ProxyConfig) {
+	pm.requests.Add(1)
+	defer func() {
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			pm.failures.Add(1)
+		}
+	}()
+
 	// Check circuit breaker
 	if pm.circuitBreaker.IsOpen() {
 		c.JSON(503, gin.H{"error": "Service temporarily unavailable"})
@@ -339,6 +417,13 @@ func (pm *ProxyManager) Forward(c *gin.Context, config *ProxyConfig) {
 		}
 	}
 
+	// Trace context and baggage are re-injected so the baggage allowlist applies
+	ctx := ExtractContext(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	for _, field := range append(traceContextPropagator.Fields(), baggagePropagator.Fields()...) {
+		req.Header.Del(field)
+	}
+	InjectContext(ctx, propagation.HeaderCarrier(req.Header))
+
 	// Add custom headers
 	for key, value := range config.Headers {
 		req.Header.Set(key, value)
@@ -380,6 +465,7 @@ func (pm *ProxyManager) Forward(c *gin.Context, config *ProxyConfig) {
 		instance.mu.Unlock()
 
 		if attempt < config.Retries {
+			pm.retries.Add(1)
 			// Calculate delay
 			delay := pm.calculateRetryDelay(attempt, config)
 			time.Sleep(delay)
@@ -451,7 +537,7 @@ func (pm *ProxyManager) buildTargetURL(instance *ProxyInstance, c *gin.Context)
 
 // calculateRetryDelay calculates delay for retry attempts
 func (pm *ProxyManager) calculateRetryDelay(attempt int, config *ProxyConfig) time.Duration {
-	baseDelay, _ := time.ParseDuration(config.RetryDelay)
+	baseDelay, _ := ParseDurationLiteral(config.RetryDelay)
 	if baseDelay == 0 {
 		baseDelay = time.Second
 	}
@@ -470,7 +556,7 @@ func (pm *ProxyManager) startHealthChecks() {
 		return
 	}
 
-	interval, _ := time.ParseDuration(pm.config.HealthInterval)
+	interval, _ := ParseDurationLiteral(pm.config.HealthInterval)
 	if interval == 0 {
 		interval = 30 * time.Second
 	}
@@ -509,7 +595,7 @@ func (pm *ProxyManager) startServiceDiscovery() {
 		return
 	}
 
-	interval, _ := time.ParseDuration(pm.config.HealthInterval)
+	interval, _ := ParseDurationLiteral(pm.config.HealthInterval)
 	if interval == 0 {
 		interval = 30 * time.Second
 	}
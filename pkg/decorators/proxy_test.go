@@ -551,3 +551,37 @@ func TestParseProxyConfig_MalformedArgs(t *testing.T) {
 	assert.Equal(t, DefaultTimeout, config.Timeout)
 	assert.Equal(t, DefaultRetries, config.Retries)
 }
+
+func TestProxyManagerStats(t *testing.T) {
+	clearProxyManagers()
+	defer clearProxyManagers()
+
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	middleware := createProxyMiddleware([]string{"target=" + server.URL, "retries=1", "retry_delay=1ms"})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/orders", middleware)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", http.NoBody))
+	failing = false
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", http.NoBody))
+
+	stats := ProxyManagerStats()
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, server.URL, stats[0].Name)
+		assert.Equal(t, int64(2), stats[0].Requests)
+		assert.Equal(t, int64(1), stats[0].Failures, "the upstream 503 is passed on")
+		assert.Equal(t, int64(1), stats[0].Retries)
+		assert.Equal(t, 0.5, stats[0].FailureRate)
+		assert.Len(t, stats[0].Instances, 1)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,6 +23,7 @@ type RateLimiter interface {
 
 // MemoryRateLimiter local in-memory implementation
 type MemoryRateLimiter struct {
+	mu      sync.Mutex
 	buckets map[string]*TokenBucket
 }
 
@@ -33,9 +37,17 @@ type TokenBucket struct {
 
 // RedisRateLimiter distributed implementation with Redis
 type RedisRateLimiter struct {
-	client *redis.Client
+	client    *redis.Client
+	algorithm string // RateLimitSlidingWindow or RateLimitTokenBucket
+	burst     int    // capacity of the token bucket; zero is the limit
 }
 
+// Algorithms of the Redis rate limiter (rate_limit.algorithm, @RateLimit(algorithm=...))
+const (
+	RateLimitSlidingWindow = "sliding_window" // at most limit requests in any window of time
+	RateLimitTokenBucket   = "token_bucket"   // bursts of up to burst requests, refilled at limit per window
+)
+
 // RateLimitResponse response when rate limit is exceeded
 type RateLimitResponse struct {
 	Error      string `json:"error"`
@@ -63,10 +75,18 @@ var (
 	}
 
 	EndpointKeyGenerator = func(c *gin.Context) string {
-		return fmt.Sprintf("ratelimit:endpoint:%s:%s:%s", c.Request.Method, c.FullPath(), c.ClientIP())
+		return joinKey("ratelimit:endpoint:", c.Request.Method, ":", c.FullPath(), ":", c.ClientIP())
 	}
 )
 
+// Rate limit headers in canonical form, so setting them does not allocate the key per request
+var (
+	headerRateLimitLimit     = http.CanonicalHeaderKey("X-RateLimit-Limit")
+	headerRateLimitRemaining = http.CanonicalHeaderKey("X-RateLimit-Remaining")
+	headerRateLimitReset     = http.CanonicalHeaderKey("X-RateLimit-Reset")
+	headerRateLimitWindow    = http.CanonicalHeaderKey("X-RateLimit-Window")
+)
+
 // NewMemoryRateLimiter creates an in-memory rate limiter
 func NewMemoryRateLimiter() *MemoryRateLimiter {
 	return &MemoryRateLimiter{
@@ -83,6 +103,9 @@ func (m *MemoryRateLimiter) Allow(ctx context.Context, key string, limit int, wi
 	default:
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	now := time.Now()
 
 	bucket, exists := m.buckets[key]
@@ -131,12 +154,28 @@ func (m *MemoryRateLimiter) Reset(ctx context.Context, key string) error {
 	default:
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	delete(m.buckets, key)
 	return nil
 }
 
-// NewRedisRateLimiter creates a distributed rate limiter with Redis
+// NewRedisRateLimiter creates a distributed rate limiter with Redis, counting with a sliding window
 func NewRedisRateLimiter(config RedisConfig) (*RedisRateLimiter, error) {
+	return NewRedisRateLimiterWith(config, RateLimitSlidingWindow, 0)
+}
+
+// NewRedisRateLimiterWith creates a distributed rate limiter with Redis using the given algorithm; burst
+// is the capacity of the token bucket (zero: the limit) and is ignored by the sliding window
+func NewRedisRateLimiterWith(config RedisConfig, algorithm string, burst int) (*RedisRateLimiter, error) {
+	if err := validateRateLimitAlgorithm(algorithm); err != nil {
+		return nil, err
+	}
+	if algorithm == "" {
+		algorithm = RateLimitSlidingWindow
+	}
+
 	client := redis.NewClient(&redis.Options{
 		Addr:     config.Address,
 		Password: config.Password,
@@ -152,9 +191,77 @@ func NewRedisRateLimiter(config RedisConfig) (*RedisRateLimiter, error) {
 		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
 	}
 
-	return &RedisRateLimiter{client: client}, nil
+	return &RedisRateLimiter{client: client, algorithm: algorithm, burst: burst}, nil
 }
 
+// slidingWindowScript keeps one sorted-set member per accepted request, scored by the instant it leaves
+// the window. The clock is the Redis one, so replicas with skewed clocks share the same window.
+// KEYS[1] key, ARGV[1] limit, ARGV[2] window (ms), ARGV[3] unique member; returns {allowed, remaining,
+// retry after (ms)}
+var slidingWindowScript = redis.NewScript(`
+	redis.replicate_commands()
+	local key = KEYS[1]
+	local limit = tonumber(ARGV[1])
+	local window = tonumber(ARGV[2])
+	local clock = redis.call('TIME')
+	local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+	-- Drop the requests that left the window
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now)
+
+	local count = redis.call('ZCARD', key)
+	if count >= limit then
+		local retry_after = window
+		local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+		if oldest[2] then
+			retry_after = tonumber(oldest[2]) - now
+		end
+		return {0, 0, retry_after}
+	end
+
+	redis.call('ZADD', key, now + window, ARGV[3])
+	redis.call('PEXPIRE', key, window)
+	return {1, limit - count - 1, 0}
+`)
+
+// tokenBucketScript refills the bucket of a key at rate tokens per millisecond up to its capacity and
+// takes one token per request, with the Redis clock.
+// KEYS[1] key, ARGV[1] capacity, ARGV[2] rate; returns {allowed, remaining, retry after (ms)}
+var tokenBucketScript = redis.NewScript(`
+	redis.replicate_commands()
+	local key = KEYS[1]
+	local capacity = tonumber(ARGV[1])
+	local rate = tonumber(ARGV[2])
+	local clock = redis.call('TIME')
+	local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+	local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+	local tokens = tonumber(bucket[1]) or capacity
+	local last = tonumber(bucket[2]) or now
+	tokens = math.min(capacity, tokens + math.max(0, now - last) * rate)
+
+	local allowed = 0
+	local retry_after = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	else
+		retry_after = math.ceil((1 - tokens) / rate)
+	end
+
+	-- The bucket expires once full again: a missing key is a full bucket
+	local refill = math.ceil((capacity - tokens) / rate)
+	redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', now, 'capacity', capacity, 'full_at', now + refill)
+	redis.call('PEXPIRE', key, math.max(refill, 1))
+	return {allowed, math.floor(tokens), retry_after}
+`)
+
+// rateLimitInstance and rateLimitSequence make the sliding window members unique across replicas
+var (
+	rateLimitInstance = generateEventID()[:12]
+	rateLimitSequence atomic.Uint64
+)
+
 // Allow checks if the request can proceed (Redis implementation)
 func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, retryAfter time.Duration, err error) {
 	// Use context for timeout and cancellation
@@ -164,51 +271,39 @@ func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, win
 	default:
 	}
 
-	// Lua script for atomic rate limiting operation
-	script := `
-		local key = KEYS[1]
-		local limit = tonumber(ARGV[1])
-		local window = tonumber(ARGV[2])
-		local current_time = tonumber(ARGV[3])
-		
-		-- Get current information
-		local bucket = redis.call('HMGET', key, 'count', 'reset_time')
-		local count = tonumber(bucket[1]) or 0
-		local reset_time = tonumber(bucket[2]) or current_time
-		
-		-- If window time has passed, reset
-		if current_time >= reset_time then
-			count = 0
-			reset_time = current_time + window
-		end
-		
-		-- Check if request can be made
-		if count >= limit then
-			local retry_after = reset_time - current_time
-			return {0, count, retry_after}
-		end
-		
-		-- Increment counter
-		count = count + 1
-		redis.call('HMSET', key, 'count', count, 'reset_time', reset_time)
-		redis.call('EXPIRE', key, math.ceil(window))
-		
-		local remaining = limit - count
-		return {1, remaining, 0}
-	`
-
-	now := time.Now().Unix()
-	windowSeconds := int64(window.Seconds())
-
-	result, err := r.client.Eval(ctx, script, []string{key}, limit, windowSeconds, now).Result()
+	// Nothing passes a zero limit, and the bucket would never refill
+	if limit <= 0 {
+		return false, 0, window, nil
+	}
+
+	windowMillis := window.Milliseconds()
+	if windowMillis < 1 {
+		windowMillis = 1
+	}
+
+	var result interface{}
+	if r.algorithm == RateLimitTokenBucket {
+		capacity := r.burst
+		if capacity <= 0 {
+			capacity = limit
+		}
+		rate := strconv.FormatFloat(float64(limit)/float64(windowMillis), 'g', -1, 64)
+		result, err = tokenBucketScript.Run(ctx, r.client, []string{key}, capacity, rate).Result()
+	} else {
+		member := rateLimitInstance + ":" + strconv.FormatUint(rateLimitSequence.Add(1), 36)
+		result, err = slidingWindowScript.Run(ctx, r.client, []string{key}, limit, windowMillis, member).Result()
+	}
 	if err != nil {
 		return false, 0, 0, fmt.Errorf("redis rate limiting error: %v", err)
 	}
 
-	values := result.([]interface{})
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return false, 0, 0, fmt.Errorf("redis rate limiting error: unexpected reply %v", result)
+	}
 	allowed = values[0].(int64) == 1
 	remaining = int(values[1].(int64))
-	retryAfter = time.Duration(values[2].(int64)) * time.Second
+	retryAfter = time.Duration(values[2].(int64)) * time.Millisecond
 
 	return allowed, remaining, retryAfter, nil
 }
@@ -225,22 +320,121 @@ func (r *RedisRateLimiter) Reset(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
-// RateLimitMiddleware creates rate limiting middleware
-func RateLimitMiddleware(config *RateLimitConfig, keyGen KeyGeneratorFunc) gin.HandlerFunc {
-	var limiter RateLimiter
-	var err error
+// Close closes the Redis connection
+func (r *RedisRateLimiter) Close() error {
+	return r.client.Close()
+}
 
-	// Choose implementation based on configuration
-	if config.Type == "redis" {
-		redisConfig := DefaultConfig().Redis
-		limiter, err = NewRedisRateLimiter(redisConfig)
-		if err != nil {
-			// Fallback to memory if Redis fails
-			limiter = NewMemoryRateLimiter()
+// rateLimitSettings rate_limit section used by the rate-limit middlewares, set by ConfigureRateLimit
+var rateLimitSettings atomic.Pointer[RateLimitConfig]
+
+// ConfigureRateLimit sets the rate_limit section: @RateLimitByIP/User/Endpoint use it as is and @RateLimit
+// takes from it the backend, algorithm and burst it does not set. Middlewares created earlier keep theirs.
+func ConfigureRateLimit(config RateLimitConfig) error {
+	if err := validateRateLimitAlgorithm(config.Algorithm); err != nil {
+		return err
+	}
+	rateLimitSettings.Store(&config)
+	return nil
+}
+
+// rateLimitConfig rate_limit of ConfigureRateLimit, or the default one
+func rateLimitConfig() RateLimitConfig {
+	if config := rateLimitSettings.Load(); config != nil {
+		return *config
+	}
+	return DefaultConfig().RateLimit
+}
+
+// validateRateLimitAlgorithm checks an algorithm; empty is the sliding window
+func validateRateLimitAlgorithm(algorithm string) error {
+	switch algorithm {
+	case "", RateLimitSlidingWindow, RateLimitTokenBucket:
+		return nil
+	}
+	return fmt.Errorf("invalid rate_limit.algorithm '%s' (valid: sliding_window, token_bucket)", algorithm)
+}
+
+// newRateLimiter creates and registers the limiter of a backend, falling back to memory when Redis is
+// unavailable; algorithm and burst apply to Redis
+func newRateLimiter(backend, algorithm string, burst int) RateLimiter {
+	var limiter RateLimiter = NewMemoryRateLimiter()
+	if backend == "redis" {
+		redisLimiter, err := NewRedisRateLimiterWith(GetRedisConfig(), algorithm, burst)
+		if err == nil {
+			limiter = redisLimiter
+		} else {
+			LogSilent("⚠️  Rate limit: Redis unavailable (%v), limits are per instance", err)
 		}
+	}
+	registerAdminRateLimiter(limiter)
+	return limiter
+}
+
+// RateLimitStats decisions of the rate limiters of a backend
+type RateLimitStats struct {
+	Backend       string  `json:"backend"` // memory or redis
+	Allowed       int64   `json:"allowed"`
+	Rejected      int64   `json:"rejected"`
+	RejectionRate float64 `json:"rejection_rate"`
+}
+
+// rateLimitCounter decisions of the limiters of a backend, for RateLimitDecisionStats
+type rateLimitCounter struct {
+	allowed, rejected atomic.Int64
+}
+
+// rateLimitCounters counters by backend
+var rateLimitCounters sync.Map
+
+// rateLimiterBackend name of the backend of a limiter
+func rateLimiterBackend(limiter RateLimiter) string {
+	switch limiter.(type) {
+	case *MemoryRateLimiter:
+		return "memory"
+	case *RedisRateLimiter:
+		return "redis"
+	default:
+		return "custom"
+	}
+}
+
+// rateLimitCounterOf returns the counter of a backend, resolved once per middleware so the hot path
+// does not allocate
+func rateLimitCounterOf(limiter RateLimiter) *rateLimitCounter {
+	value, _ := rateLimitCounters.LoadOrStore(rateLimiterBackend(limiter), &rateLimitCounter{})
+	return value.(*rateLimitCounter)
+}
+
+// record counts a request allowed or rejected
+func (r *rateLimitCounter) record(allowed bool) {
+	if allowed {
+		r.allowed.Add(1)
 	} else {
-		limiter = NewMemoryRateLimiter()
+		r.rejected.Add(1)
 	}
+}
+
+// RateLimitDecisionStats returns the requests allowed and rejected by the rate limiters, by backend
+func RateLimitDecisionStats() []RateLimitStats {
+	var stats []RateLimitStats
+	rateLimitCounters.Range(func(key, value interface{}) bool {
+		counter := value.(*rateLimitCounter)
+		entry := RateLimitStats{Backend: key.(string), Allowed: counter.allowed.Load(), Rejected: counter.rejected.Load()}
+		if total := entry.Allowed + entry.Rejected; total > 0 {
+			entry.RejectionRate = float64(entry.Rejected) / float64(total)
+		}
+		stats = append(stats, entry)
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Backend < stats[j].Backend })
+	return stats
+}
+
+// RateLimitMiddleware creates rate limiting middleware
+func RateLimitMiddleware(config *RateLimitConfig, keyGen KeyGeneratorFunc) gin.HandlerFunc {
+	limiter := newRateLimiter(config.Type, config.Algorithm, config.BurstSize)
+	limitHeader, decisions := strconv.Itoa(config.DefaultRPS), rateLimitCounterOf(limiter)
 
 	return func(c *gin.Context) {
 		if !config.Enabled {
@@ -266,10 +460,11 @@ func RateLimitMiddleware(config *RateLimitConfig, keyGen KeyGeneratorFunc) gin.H
 		}
 
 		// Add informative headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(config.DefaultRPS))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		c.Header(headerRateLimitLimit, limitHeader)
+		c.Header(headerRateLimitRemaining, strconv.Itoa(remaining))
+		c.Header(headerRateLimitReset, strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
 
+		decisions.record(allowed)
 		if !allowed {
 			c.Header("Retry-After", strconv.FormatInt(int64(retryAfter.Seconds()), 10))
 
@@ -312,18 +507,14 @@ func CustomRateLimit(limit int, window time.Duration, keyGen KeyGeneratorFunc, r
 		DefaultRPS: limit,
 	}
 
-	// Create specific limiter based on type
-	var limiter RateLimiter
-	if rateLimiterType == "redis" {
-		redisConfig := DefaultConfig().Redis
-		if redisLimiter, err := NewRedisRateLimiter(redisConfig); err == nil {
-			limiter = redisLimiter
-		} else {
-			limiter = NewMemoryRateLimiter()
-		}
-	} else {
-		limiter = NewMemoryRateLimiter()
-	}
+	// Create specific limiter based on type, with the algorithm of rate_limit
+	settings := rateLimitConfig()
+	limiter := newRateLimiter(rateLimiterType, settings.Algorithm, settings.BurstSize)
+	decisions := rateLimitCounterOf(limiter)
+
+	// Values of the headers and of the 429 body are fixed per route
+	limitHeader, windowHeader := strconv.Itoa(limit), window.String()
+	exceededMessage := fmt.Sprintf("Request rate exceeded. Limit: %d per %v", limit, window)
 
 	return func(c *gin.Context) {
 		if !config.Enabled {
@@ -353,16 +544,17 @@ func CustomRateLimit(limit int, window time.Duration, keyGen KeyGeneratorFunc, r
 		}
 
 		// Add informative headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Window", window.String())
+		c.Header(headerRateLimitLimit, limitHeader)
+		c.Header(headerRateLimitRemaining, strconv.Itoa(remaining))
+		c.Header(headerRateLimitWindow, windowHeader)
 
+		decisions.record(allowed)
 		if !allowed {
 			c.Header("Retry-After", strconv.FormatInt(int64(retryAfter.Seconds()), 10))
 
 			response := RateLimitResponse{
 				Error:      "rate_limit_exceeded",
-				Message:    fmt.Sprintf("Request rate exceeded. Limit: %d per %v", limit, window),
+				Message:    exceededMessage,
 				Limit:      limit,
 				Remaining:  0,
 				RetryAfter: int(retryAfter.Seconds()),
@@ -376,12 +568,19 @@ func CustomRateLimit(limit int, window time.Duration, keyGen KeyGeneratorFunc, r
 	}
 }
 
-// ParseRateLimitArgs parses @RateLimit decorator arguments
-func ParseRateLimitArgs(args []string) (limit int, window time.Duration, rateLimiterType string, keyGen KeyGeneratorFunc) {
-	limit = 100                // default
-	window = time.Minute       // default
-	rateLimiterType = "memory" // default
-	keyGen = IPKeyGenerator    // default
+// RateLimitOptions @RateLimit arguments, folded into the generated code as a literal
+type RateLimitOptions struct {
+	Limit     int
+	Window    time.Duration
+	Backend   string // memory or redis; empty is rate_limit.type
+	Key       string // ip, user or endpoint
+	Algorithm string // sliding_window or token_bucket (Redis); empty is rate_limit.algorithm
+	Burst     int    // token bucket capacity; zero is rate_limit.burst_size
+}
+
+// parseRateLimitOptions parses @RateLimit decorator arguments; invalid values keep the defaults
+func parseRateLimitOptions(args []string) RateLimitOptions {
+	options := RateLimitOptions{Limit: 100, Window: time.Minute, Key: "ip"}
 
 	for _, arg := range args {
 		if strings.Contains(arg, "=") {
@@ -392,46 +591,83 @@ func ParseRateLimitArgs(args []string) (limit int, window time.Duration, rateLim
 			switch key {
 			case "limit", "rps":
 				if parsed, err := strconv.Atoi(value); err == nil {
-					limit = parsed
+					options.Limit = parsed
 				}
 			case "window":
-				if parsed, err := time.ParseDuration(value); err == nil {
-					window = parsed
+				if parsed, err := ParseDurationLiteral(value); err == nil {
+					options.Window = parsed
 				}
 			case "type":
-				rateLimiterType = value
+				options.Backend = value
+			case "algorithm":
+				if validateRateLimitAlgorithm(value) == nil {
+					options.Algorithm = value
+				}
+			case "burst":
+				if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+					options.Burst = parsed
+				}
 			case "key", "by":
 				switch value {
-				case "ip":
-					keyGen = IPKeyGenerator
-				case "user":
-					keyGen = UserKeyGenerator
-				case "endpoint":
-					keyGen = EndpointKeyGenerator
+				case "ip", "user", "endpoint":
+					options.Key = value
 				}
 			}
 		}
 	}
 
-	return limit, window, rateLimiterType, keyGen
+	return options
+}
+
+// resolve fills the backend, algorithm and burst the route does not set from rate_limit
+func (o RateLimitOptions) resolve() RateLimitOptions {
+	settings := rateLimitConfig()
+	if o.Backend == "" {
+		o.Backend = settings.Type
+	}
+	if o.Algorithm == "" {
+		o.Algorithm = settings.Algorithm
+	}
+	if o.Burst == 0 {
+		o.Burst = settings.BurstSize
+	}
+	return o
+}
+
+// keyGenerator key generator of the Key option
+func (o RateLimitOptions) keyGenerator() KeyGeneratorFunc {
+	switch o.Key {
+	case "user":
+		return UserKeyGenerator
+	case "endpoint":
+		return EndpointKeyGenerator
+	default:
+		return IPKeyGenerator
+	}
+}
+
+// ParseRateLimitArgs parses @RateLimit decorator arguments
+func ParseRateLimitArgs(args []string) (limit int, window time.Duration, rateLimiterType string, keyGen KeyGeneratorFunc) {
+	options := parseRateLimitOptions(args).resolve()
+	return options.Limit, options.Window, options.Backend, options.keyGenerator()
 }
 
 // createRateLimitMiddlewareInternal creates rate limiting middleware (for markers.go)
 func createRateLimitMiddlewareInternal(args []string) gin.HandlerFunc {
-	limit, window, rateLimiterType, keyGen := ParseRateLimitArgs(args)
+	return RateLimitWith(parseRateLimitOptions(args))
+}
 
-	// Create specific limiter
-	var limiter RateLimiter
-	if rateLimiterType == "redis" {
-		redisConfig := DefaultConfig().Redis
-		if redisLimiter, err := NewRedisRateLimiter(redisConfig); err == nil {
-			limiter = redisLimiter
-		} else {
-			limiter = NewMemoryRateLimiter()
-		}
-	} else {
-		limiter = NewMemoryRateLimiter()
-	}
+// RateLimitWith creates the @RateLimit middleware from arguments already parsed, as emitted by the
+// generator
+func RateLimitWith(options RateLimitOptions) gin.HandlerFunc {
+	options = options.resolve()
+	limit, window, keyGen := options.Limit, options.Window, options.keyGenerator()
+	limiter := newRateLimiter(options.Backend, options.Algorithm, options.Burst)
+	decisions := rateLimitCounterOf(limiter)
+
+	// Values of the headers and of the 429 body are fixed per route
+	limitHeader, windowHeader := strconv.Itoa(limit), window.String()
+	exceededMessage := fmt.Sprintf("Request rate exceeded. Limit: %d per %v", limit, window)
 
 	return func(c *gin.Context) {
 		key := keyGen(c)
@@ -449,16 +685,17 @@ func createRateLimitMiddlewareInternal(args []string) gin.HandlerFunc {
 		}
 
 		// Informative headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Window", window.String())
+		c.Header(headerRateLimitLimit, limitHeader)
+		c.Header(headerRateLimitRemaining, strconv.Itoa(remaining))
+		c.Header(headerRateLimitWindow, windowHeader)
 
+		decisions.record(allowed)
 		if !allowed {
 			c.Header("Retry-After", strconv.FormatInt(int64(retryAfter.Seconds()), 10))
 
 			response := RateLimitResponse{
 				Error:      "rate_limit_exceeded",
-				Message:    fmt.Sprintf("Request rate exceeded. Limit: %d per %v", limit, window),
+				Message:    exceededMessage,
 				Limit:      limit,
 				Remaining:  0,
 				RetryAfter: int(retryAfter.Seconds()),
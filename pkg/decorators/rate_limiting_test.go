@@ -580,3 +580,102 @@ func TestRedisRateLimiter_Reset(t *testing.T) {
 
 	assert.NoError(t, err, "Reset should not return error")
 }
+
+func BenchmarkRateLimitMiddleware(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/items/:id", createRateLimitMiddlewareInternal([]string{"limit=1000000000", "window=1m"}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+	writer := &discardWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		writer.serve(engine, req)
+	}
+}
+
+func TestRateLimitHotPathAllocations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/items/:id", createRateLimitMiddlewareInternal([]string{"limit=1000000000", "window=1m"}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/items/1", http.NoBody)
+	writer := &discardWriter{header: http.Header{}}
+	writer.serve(engine, req)
+
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { writer.serve(engine, req) }), 7.0)
+}
+
+func TestParseRateLimitOptions_AlgorithmAndBurst(t *testing.T) {
+	options := parseRateLimitOptions([]string{"limit=10", "type=redis", "algorithm=token_bucket", "burst=25"})
+	assert.Equal(t, RateLimitOptions{
+		Limit: 10, Window: time.Minute, Backend: "redis", Key: "ip", Algorithm: RateLimitTokenBucket, Burst: 25,
+	}, options)
+
+	options = parseRateLimitOptions([]string{"algorithm=leaky", "burst=-1"})
+	assert.Empty(t, options.Algorithm, "invalid values keep the defaults")
+	assert.Zero(t, options.Burst)
+}
+
+func TestConfigureRateLimit(t *testing.T) {
+	defer rateLimitSettings.Store(nil)
+
+	resolved := parseRateLimitOptions(nil).resolve()
+	assert.Equal(t, "memory", resolved.Backend)
+	assert.Equal(t, RateLimitSlidingWindow, resolved.Algorithm)
+
+	assert.NoError(t, ConfigureRateLimit(RateLimitConfig{Type: "redis", Algorithm: RateLimitTokenBucket, BurstSize: 50}))
+	resolved = parseRateLimitOptions(nil).resolve()
+	assert.Equal(t, "redis", resolved.Backend, "rate_limit.type selects the backend of @RateLimit")
+	assert.Equal(t, RateLimitTokenBucket, resolved.Algorithm)
+	assert.Equal(t, 50, resolved.Burst)
+
+	resolved = parseRateLimitOptions([]string{"type=memory", "algorithm=sliding_window", "burst=5"}).resolve()
+	assert.Equal(t, RateLimitOptions{
+		Limit: 100, Window: time.Minute, Backend: "memory", Key: "ip", Algorithm: RateLimitSlidingWindow, Burst: 5,
+	}, resolved, "the route wins")
+
+	assert.Error(t, ConfigureRateLimit(RateLimitConfig{Algorithm: "leaky"}))
+	config := DefaultConfig()
+	config.RateLimit.Algorithm = "leaky"
+	assert.Error(t, config.Validate())
+}
+
+func TestNewRedisRateLimiterWith(t *testing.T) {
+	_, err := NewRedisRateLimiterWith(RedisConfig{Address: "invalid:6379"}, "leaky", 0)
+	assert.ErrorContains(t, err, "invalid rate_limit.algorithm")
+
+	// An unreachable Redis falls back to a limiter of the instance
+	defer redisSettings.Store(nil)
+	ConfigureRedis(RedisConfig{Address: "127.0.0.1:1"})
+	assert.IsType(t, &MemoryRateLimiter{}, newRateLimiter("redis", RateLimitTokenBucket, 10))
+}
+
+func TestRateLimitDecisionStats(t *testing.T) {
+	memoryStats := func() RateLimitStats {
+		for _, stats := range RateLimitDecisionStats() {
+			if stats.Backend == "memory" {
+				return stats
+			}
+		}
+		return RateLimitStats{}
+	}
+	before := memoryStats()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/limited", RateLimitWith(RateLimitOptions{Limit: 2, Window: time.Minute, Backend: "memory", Key: "ip"}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/limited", http.NoBody))
+	}
+
+	after := memoryStats()
+	assert.Equal(t, int64(2), after.Allowed-before.Allowed)
+	assert.Equal(t, int64(1), after.Rejected-before.Rejected)
+	assert.Greater(t, after.RejectionRate, 0.0)
+}
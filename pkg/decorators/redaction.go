@@ -0,0 +1,160 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultRedactionReplacement value that replaces redacted fields
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// Redactor masks fields of JSON documents selected by JSON path rules.
+//
+// Rules are dot-separated paths, optionally prefixed with "$.": "user.password",
+// "cards[*].number", "items.0.token". "*" matches any key or array index, "**" any
+// number of levels, and a bare field name such as "password" matches at any depth.
+type Redactor struct {
+	rules       [][]string
+	replacement string
+}
+
+// NewRedactor compiles redaction rules; an empty replacement uses DefaultRedactionReplacement
+func NewRedactor(rules []string, replacement string) (*Redactor, error) {
+	if replacement == "" {
+		replacement = DefaultRedactionReplacement
+	}
+
+	redactor := &Redactor{replacement: replacement}
+	for _, rule := range rules {
+		segments, err := parseRedactionRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		redactor.rules = append(redactor.rules, segments)
+	}
+	return redactor, nil
+}
+
+// parseRedactionRule splits a rule into path segments
+func parseRedactionRule(rule string) ([]string, error) {
+	path := strings.TrimSpace(rule)
+	if path == "$" || path == "" {
+		return nil, fmt.Errorf("invalid redaction rule '%s': empty path", rule)
+	}
+
+	anchored := strings.HasPrefix(path, "$")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	// Bracket indexes are equivalent to dotted segments: a[*].b == a.*.b
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		segment = strings.Trim(strings.TrimSpace(segment), `"'`)
+		if segment == "" {
+			return nil, fmt.Errorf("invalid redaction rule '%s': empty segment", rule)
+		}
+		segments = append(segments, segment)
+	}
+
+	if !anchored && len(segments) == 1 && segments[0] != "**" {
+		segments = append([]string{"**"}, segments...)
+	}
+	return segments, nil
+}
+
+// Empty reports whether the redactor has no rules
+func (r *Redactor) Empty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// RedactJSON returns a copy of a JSON document with matching fields replaced.
+// Bodies that cannot be parsed (not JSON, or truncated by the capture limit) are
+// replaced as a whole, so a rule can never be bypassed by a malformed body.
+func (r *Redactor) RedactJSON(data []byte) []byte {
+	if r.Empty() || len(bytes.TrimSpace(data)) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return []byte(r.replacement)
+	}
+
+	redacted, err := json.Marshal(r.RedactValue(document))
+	if err != nil {
+		return []byte(r.replacement)
+	}
+	return redacted
+}
+
+// RedactValue redacts a decoded JSON value (maps, slices and scalars) in place and returns it
+func (r *Redactor) RedactValue(value interface{}) interface{} {
+	if r.Empty() {
+		return value
+	}
+	return r.redact(value, nil)
+}
+
+// redact walks value, replacing children whose path matches a rule
+func (r *Redactor) redact(value interface{}, path []string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			childPath := append(path[:len(path):len(path)], key)
+			if r.matches(childPath) {
+				typed[key] = r.replacement
+				continue
+			}
+			typed[key] = r.redact(child, childPath)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if r.matches(childPath) {
+				typed[i] = r.replacement
+				continue
+			}
+			typed[i] = r.redact(child, childPath)
+		}
+	}
+	return value
+}
+
+// matches reports whether any rule selects path
+func (r *Redactor) matches(path []string) bool {
+	for _, rule := range r.rules {
+		if matchRedactionPath(rule, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRedactionPath matches a rule against a concrete path, "**" spanning any number of segments
+func matchRedactionPath(rule, path []string) bool {
+	if len(rule) == 0 {
+		return len(path) == 0
+	}
+
+	if rule[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchRedactionPath(rule[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+	if rule[0] != "*" && !strings.EqualFold(rule[0], path[0]) {
+		return false
+	}
+	return matchRedactionPath(rule[1:], path[1:])
+}
@@ -0,0 +1,78 @@
+package decorators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_RedactJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "bare field at any depth",
+			rules:    []string{"password"},
+			input:    `{"password":"a","user":{"Password":"b","name":"ana"}}`,
+			expected: `{"password":"[REDACTED]","user":{"Password":"[REDACTED]","name":"ana"}}`,
+		},
+		{
+			name:     "anchored path",
+			rules:    []string{"$.token"},
+			input:    `{"token":"a","nested":{"token":"b"}}`,
+			expected: `{"nested":{"token":"b"},"token":"[REDACTED]"}`,
+		},
+		{
+			name:     "array wildcard",
+			rules:    []string{"cards[*].number"},
+			input:    `{"cards":[{"number":"4111","brand":"visa"},{"number":"5500"}]}`,
+			expected: `{"cards":[{"brand":"visa","number":"[REDACTED]"},{"number":"[REDACTED]"}]}`,
+		},
+		{
+			name:     "array index and subtree",
+			rules:    []string{"*.items.0", "**.secrets"},
+			input:    `[{"items":[{"id":1},{"id":2}],"meta":{"secrets":{"k":"v"}}}]`,
+			expected: `[{"items":["[REDACTED]",{"id":2}],"meta":{"secrets":"[REDACTED]"}}]`,
+		},
+		{
+			name:     "numbers are preserved",
+			rules:    []string{"ssn"},
+			input:    `{"amount":12345678901234567890,"ssn":123}`,
+			expected: `{"amount":12345678901234567890,"ssn":"[REDACTED]"}`,
+		},
+		{
+			name:     "malformed bodies are replaced",
+			rules:    []string{"password"},
+			input:    `{"password":"trunc`,
+			expected: `[REDACTED]`,
+		},
+		{
+			name:     "no rules keeps the body",
+			rules:    nil,
+			input:    `not json`,
+			expected: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewRedactor(tt.rules, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(redactor.RedactJSON([]byte(tt.input))))
+		})
+	}
+}
+
+func TestNewRedactor_InvalidRules(t *testing.T) {
+	for _, rule := range []string{"", "$", "user..password", "$."} {
+		_, err := NewRedactor([]string{rule}, "")
+		assert.Error(t, err, rule)
+	}
+
+	var nilRedactor *Redactor
+	assert.True(t, nilRedactor.Empty())
+	assert.Equal(t, []byte(`{"a":1}`), nilRedactor.RedactJSON([]byte(`{"a":1}`)))
+}
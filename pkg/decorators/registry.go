@@ -1,6 +1,7 @@
 package decorators
 
 import (
+	"context"
 	"log"
 	"reflect"
 	"strings"
@@ -17,6 +18,11 @@ type ParameterInfo struct {
 	Required    bool   `json:"required"`
 	Description string `json:"description"`
 	Example     string `json:"example"`
+	Pattern     string `json:"pattern,omitempty"`      // regular expression the value must match
+	ContentType string `json:"content_type,omitempty"` // media type of a body (@RequestBody)
+	Multiple    bool   `json:"multiple,omitempty"`     // file field accepting several files (@FileParam)
+	MaxSize     string `json:"max_size,omitempty"`     // largest accepted file, e.g. 5MB (@FileParam)
+	Accept      string `json:"accept,omitempty"`       // comma-separated media types of a file (@FileParam)
 }
 
 // ResponseInfo represents information of a route response
@@ -51,6 +57,12 @@ type RouteEntry struct {
 	Group             *GroupInfo        `json:"group,omitempty"`
 	Responses         []ResponseInfo    `json:"responses,omitempty"`         // Updated to use ResponseInfo
 	WebSocketHandlers []string          `json:"websocketHandlers,omitempty"` // WebSocket message types this function handles
+	GRPC              *GRPCBinding      `json:"grpc,omitempty"`              // @GRPC method of the route
+	OperationID       string            `json:"operation_id,omitempty"`      // operationId generated for the route
+	Deprecation       *DeprecationInfo  `json:"deprecation,omitempty"`       // @Deprecated
+	Version           string            `json:"version,omitempty"`           // version of a @Version route, served as versioning configures
+
+	Translations map[string]RouteTranslation `json:"translations,omitempty"` // summary and description by locale
 }
 
 // global route registry with mutex protection
@@ -127,6 +139,17 @@ func RegisterRouteWithMeta(entry *RouteEntry) {
 	}
 
 	registryMutex.Lock()
+	// The same handler registered twice for a method and path (an init run twice, a binding repeated by
+	// hand) is kept once, as Gin would refuse the second registration
+	for _, existing := range routes {
+		if existing.Method == entry.Method && existing.Path == entry.Path && existing.Version == entry.Version &&
+			existing.FuncName == entry.FuncName && existing.PackageName == entry.PackageName &&
+			reflect.ValueOf(existing.Handler).Pointer() == reflect.ValueOf(entry.Handler).Pointer() {
+			registryMutex.Unlock()
+			LogVerbose("Route already registered: %s %s -> %s", entry.Method, entry.Path, entry.FuncName)
+			return
+		}
+	}
 	routes = append(routes, *entry)
 	registryMutex.Unlock()
 
@@ -138,6 +161,16 @@ func Default() *gin.Engine {
 	return DefaultWithSecurity(nil)
 }
 
+// NewEngine creates an Engine with all registered routes: Default with lifecycle hooks and graceful shutdown
+func NewEngine() *Engine {
+	return NewEngineWithSecurity(nil)
+}
+
+// NewEngineWithSecurity creates an Engine with security configuration for internal endpoints
+func NewEngineWithSecurity(securityConfig *SecurityConfig) *Engine {
+	return newEngine(DefaultWithSecurity(securityConfig))
+}
+
 // DefaultWithSecurity creates a gin.Engine with security configuration for internal endpoints
 func DefaultWithSecurity(securityConfig *SecurityConfig) *gin.Engine {
 	r := gin.Default()
@@ -151,33 +184,203 @@ func DefaultWithSecurity(securityConfig *SecurityConfig) *gin.Engine {
 	securityMiddleware := SecureInternalEndpoints(securityConfig)
 
 	// Register documentation routes with security
-	config := DefaultConfig()
-	r.GET("/decorators/docs", securityMiddleware, DocsHandler)
-	r.GET("/decorators/docs.json", securityMiddleware, DocsJSONHandler)
-	r.GET("/decorators/openapi.json", securityMiddleware, OpenAPIJSONHandler(config))
-	r.GET("/decorators/openapi.yaml", securityMiddleware, OpenAPIYAMLHandler(config))
-	r.GET("/decorators/swagger-ui", securityMiddleware, SwaggerUIHandler(config))
-	r.GET("/decorators/swagger", securityMiddleware, SwaggerRedirectHandler)
-
-	// Register all framework routes
-	registryMutex.RLock()
-	routesCopy := make([]RouteEntry, len(routes))
-	copy(routesCopy, routes)
-	registryMutex.RUnlock()
+	config, err := LoadConfig("")
+	if err != nil {
+		LogVerbose("Error loading config, using default: %v", err)
+		config = DefaultConfig()
+	}
 
-	for i := range routesCopy {
-		route := &routesCopy[i]
-		// Combine middlewares + main handler
-		handlers := make([]gin.HandlerFunc, 0, len(route.Middlewares)+1)
-		handlers = append(handlers, route.Middlewares...)
-		handlers = append(handlers, route.Handler)
-		r.Handle(route.Method, route.Path, handlers...)
+	if err := ConfigureBodyCapture(config.Capture); err != nil {
+		LogSilent("⚠️  Invalid body_capture configuration: %v", err)
+	}
+	if err := ConfigurePrivacy(config.Privacy); err != nil {
+		LogSilent("⚠️  Invalid privacy configuration: %v", err)
+	}
+	ConfigureRedis(config.Redis)
+	if err := ConfigureAuth(config.resolvedAuth()); err != nil {
+		LogSilent("⚠️  Invalid auth configuration: %v", err)
+	}
+	if err := ConfigureRevocation(config.Auth.Revocation, config.Redis); err != nil {
+		LogSilent("⚠️  Invalid auth.revocation configuration: %v", err)
+	}
+	if err := ConfigureCacheKeys(config.Cache.Keys); err != nil {
+		LogSilent("⚠️  Invalid cache.keys configuration: %v", err)
+	}
+	if err := ConfigureCacheETag(config.Cache.ETag); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	if err := ConfigureRateLimit(config.RateLimit); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	if err := ConfigureWebSocket(config.WebSocket); err != nil {
+		LogSilent("⚠️  Invalid websocket configuration: %v", err)
+	}
+	if err := ConfigureCompression(config.Compress); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	if err := ConfigureVersioning(config.Versioning); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	if err := ConfigureSerialization(config.Serialize); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	if err := ConfigureLogging(config.Logging); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	ConfigureEnvelope(config.Envelope)
+
+	// Every route is counted for the consolidated statistics (StatsPath)
+	r.Use(RouteStatsMiddleware())
+
+	// Request bodies are capped on every route (limits.max_body_size); routes override it with @MaxBodySize
+	if limit, err := config.Limits.maxBodySize(); err != nil {
+		LogSilent("⚠️  %v", err)
+	} else if limit > 0 {
+		r.Use(DefaultMaxBodySizeMiddleware(limit))
+	}
+
+	// Access log is opt-in (access_log.enabled); routes opt out with @NoAccessLog
+	if config.AccessLog.Enabled {
+		r.Use(AccessLogMiddleware(config.AccessLog))
+	}
+
+	// Response compression is opt-in for every route (compression.enabled); routes opt in with @Compress
+	if config.Compress.Enabled {
+		r.Use(CompressMiddleware(CompressionConfig{}))
+	}
+
+	// Structured request logs are opt-in for every route (logging.enabled); routes opt in with @Log.
+	// Inside compression, so logged response bodies are the uncompressed ones.
+	if config.Logging.Enabled {
+		r.Use(LogMiddleware(LoggingConfig{}))
+	}
+
+	// Response envelopes are opt-in for every route (envelope.enabled); routes opt in with @Envelope
+	if config.Envelope.Enabled {
+		r.Use(EnvelopeMiddleware(EnvelopeConfig{}))
+	}
+
+	// Response conformance checking is a development aid (dev.check_responses)
+	if config.Dev.CheckResponses && !prodBuild {
+		r.Use(ResponseConformanceMiddleware())
+		r.GET(ConformanceDebugPath, securityMiddleware, ConformanceReportsHandler)
+	}
+
+	// Traffic learning is a development and staging aid (dev.learn)
+	if config.Dev.Learn && !prodBuild {
+		r.Use(TrafficLearningMiddleware())
+		r.GET(LearnDebugPath, securityMiddleware, LearnReportHandler)
 	}
 
+	// Documentation endpoints, under docs.base_path
+	registerDocsRoutes(r, config, securityMiddleware)
+	r.GET(MiddlewareDebugPath, securityMiddleware, MiddlewareChainHandler)
+	r.GET(CircuitBreakersPath, securityMiddleware, CircuitBreakersHandler)
+	r.GET(StatsPath, securityMiddleware, StatsHandler)
+
+	// Profiling endpoints are opt-in (profiling.enabled)
+	if config.Profiling.Enabled {
+		RegisterProfilingRoutes(r, securityMiddleware)
+		if err := StartProfiling(config.Profiling); err != nil {
+			LogSilent("⚠️  Error starting continuous profiling: %v", err)
+		}
+	}
+
+	// Baggage forwarded by @Proxy, NewHTTPClient and the outbox
+	SetBaggageAllowlist(config.Telemetry.BaggageAllowlist)
+
+	// CloudEvents are opt-in (events.enabled)
+	if config.Events.Enabled {
+		if err := ConfigureEvents(config.Events); err != nil {
+			LogSilent("⚠️  Error configuring events: %v", err)
+		}
+	}
+
+	// Admin endpoints are opt-in (admin.enabled)
+	if config.Admin.Enabled {
+		adminMiddlewares := []gin.HandlerFunc{securityMiddleware}
+		if config.Admin.AuthRole != "" {
+			adminMiddlewares = append(adminMiddlewares, createAuthMiddleware([]string{"role=" + config.Admin.AuthRole}))
+		}
+		RegisterAdminRoutes(r, adminMiddlewares...)
+	}
+
+	// Data-subject endpoints are opt-in (privacy.endpoints)
+	if config.Privacy.Endpoints {
+		privacyMiddlewares := []gin.HandlerFunc{securityMiddleware}
+		if config.Privacy.AuthRole != "" {
+			privacyMiddlewares = append(privacyMiddlewares, createAuthMiddleware([]string{"role=" + config.Privacy.AuthRole}))
+		}
+		RegisterPrivacyRoutes(r, privacyMiddlewares...)
+	}
+
+	// Revoke endpoint for operators (auth.revocation.endpoint)
+	if config.Auth.Revocation.Enabled && config.Auth.Revocation.Endpoint {
+		revokeMiddlewares := []gin.HandlerFunc{securityMiddleware}
+		if config.Auth.Revocation.AuthRole != "" {
+			revokeMiddlewares = append(revokeMiddlewares, createAuthMiddleware([]string{"role=" + config.Auth.Revocation.AuthRole}))
+		}
+		r.POST(RevokePath, append(revokeMiddlewares, RevokeHandler)...)
+	}
+
+	// Deployment changelog is opt-in (changelog.enabled)
+	if config.Changelog.Enabled {
+		if _, err := RecordAPIDeployment(config); err != nil {
+			LogSilent("⚠️  Error recording API changelog: %v", err)
+		}
+		r.GET(ChangelogPath, securityMiddleware, ChangelogHandler)
+	}
+
+	// Register all framework routes, the versions of @Version routes as versioning configures
+	routesCopy := GetRoutes()
+	for _, mount := range versionMounts(routesCopy, versioningConfig(), routeHandlers) {
+		r.Handle(mount.method, mount.path, mount.handlers...)
+	}
+
+	// gRPC server of the @GRPC routes is opt-in (grpc.enabled)
+	if config.GRPC.Enabled {
+		if err := StartGRPCServer(r, config.GRPC); err != nil {
+			LogSilent("⚠️  Error starting gRPC server: %v", err)
+		}
+	}
+
+	// Dependencies are initialized before serving traffic; readiness reports them
+	r.GET(ReadinessPath, securityMiddleware, ReadinessHandler)
+	warmup := prepareInitializers()
+	if config.Warmup.Background {
+		go runInitializers(context.Background(), warmup, config.Warmup)
+	} else {
+		runInitializers(context.Background(), warmup, config.Warmup)
+	}
+
+	// Message consumers declared with @Subscribe start with the engine
+	if err := StartSubscriptions(context.Background()); err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+
+	EmitEvent(EventEngineStarted, "", map[string]interface{}{"routes": len(routesCopy)})
 	LogNormal("Framework gin-decorators inicializado com %d routes", len(routesCopy))
 	return r
 }
 
+// routeHandlers gin chain of a registered route
+func routeHandlers(route *RouteEntry) []gin.HandlerFunc {
+	// The route identity comes first so every middleware logs and traces with it
+	identity := RouteIdentityMiddleware(route.routeIdentity())
+
+	// In debug mode each middleware is timed for the middleware debug endpoint
+	if gin.IsDebugging() {
+		return append([]gin.HandlerFunc{identity}, instrumentRoute(route)...)
+	}
+
+	// Combine middlewares + main handler
+	handlers := make([]gin.HandlerFunc, 0, len(route.Middlewares)+2)
+	handlers = append(handlers, identity)
+	handlers = append(handlers, route.Middlewares...)
+	handlers = append(handlers, route.Handler)
+	return handlers
+}
+
 // GetRoutes returns all registered routes (used for documentation)
 func GetRoutes() []RouteEntry {
 	registryMutex.RLock()
@@ -186,6 +389,12 @@ func GetRoutes() []RouteEntry {
 	// Return a copy to avoid race conditions
 	routesCopy := make([]RouteEntry, len(routes))
 	copy(routesCopy, routes)
+
+	// Versioned routes are served under their version prefix with the path strategy
+	versioning := versioningConfig()
+	for i := range routesCopy {
+		routesCopy[i].Path = versioning.Path(&routesCopy[i])
+	}
 	return routesCopy
 }
 
@@ -1,6 +1,7 @@
 package decorators
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -70,6 +71,21 @@ func TestRegisterRoute(t *testing.T) {
 	assert.NotNil(t, routes[0].Handler)
 }
 
+func TestRegisterRouteWithMeta_Duplicate(t *testing.T) {
+	routes = nil
+	defer func() { routes = nil }()
+
+	handler := func(c *gin.Context) {}
+	RegisterRouteWithMeta(&RouteEntry{Method: "GET", Path: "/health", Handler: handler, FuncName: "Health"})
+	RegisterRouteWithMeta(&RouteEntry{Method: "HEAD", Path: "/health", Handler: handler, FuncName: "Health"})
+	RegisterRouteWithMeta(&RouteEntry{Method: "GET", Path: "/health", Handler: handler, FuncName: "Health"})
+	assert.Len(t, routes, 2, "the same handler, method and path is registered once")
+
+	// A different handler on the same method and path is kept, for Gin to reject
+	RegisterRouteWithMeta(&RouteEntry{Method: "GET", Path: "/health", Handler: func(c *gin.Context) {}, FuncName: "Health"})
+	assert.Len(t, routes, 3)
+}
+
 func TestRegisterRouteWithMeta(t *testing.T) {
 	// Clear existing routes
 	routes = nil
@@ -155,6 +171,14 @@ func TestDefault(t *testing.T) {
 	assert.IsType(t, &gin.Engine{}, engine)
 }
 
+func TestNewEngine(t *testing.T) {
+	// The lifecycle engine wraps the engine Default builds
+	engine := NewEngine()
+	assert.NotNil(t, engine)
+	assert.NotNil(t, engine.Engine)
+	engine.OnStartup(func(context.Context) error { return nil })
+}
+
 func TestDefaultWithSecurity(t *testing.T) {
 	// Test creating default engine with security
 	securityConfig := &SecurityConfig{
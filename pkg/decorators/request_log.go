@@ -0,0 +1,280 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestLogKey context key of the @Log settings of the request, held by the outermost logging middleware
+const requestLogKey = "deco.requestLog"
+
+// requestLogLevels levels of logging.level and @Log(level=...)
+var requestLogLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// StructuredLogger receives the request records of @Log. slog is used by default; other logging
+// libraries plug in with an adapter, e.g. zap:
+//
+//	deco.SetStructuredLogger(deco.StructuredLoggerFunc(func(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+//		fields := make([]zap.Field, len(attrs))
+//		for i, attr := range attrs {
+//			fields[i] = zap.Any(attr.Key, attr.Value.Any())
+//		}
+//		logger.Log(zapcore.Level(level/4), msg, fields...)
+//	}))
+type StructuredLogger interface {
+	LogRequest(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// StructuredLoggerFunc adapts a function to StructuredLogger
+type StructuredLoggerFunc func(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+
+// LogRequest calls f
+func (f StructuredLoggerFunc) LogRequest(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	f(ctx, level, msg, attrs...)
+}
+
+// SlogLogger StructuredLogger writing to an slog logger
+func SlogLogger(logger *slog.Logger) StructuredLogger {
+	return StructuredLoggerFunc(func(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+		logger.LogAttrs(ctx, level, msg, attrs...)
+	})
+}
+
+var (
+	structuredLogger      StructuredLogger
+	structuredLoggerMutex sync.RWMutex
+
+	loggingSettings atomic.Pointer[loggingState]
+)
+
+// loggingState logging section compiled by ConfigureLogging
+type loggingState struct {
+	config   LoggingConfig
+	level    slog.Level
+	redactor *Redactor
+}
+
+// SetStructuredLogger sets the logger receiving the records of @Log; nil restores slog.Default()
+func SetStructuredLogger(logger StructuredLogger) {
+	structuredLoggerMutex.Lock()
+	defer structuredLoggerMutex.Unlock()
+	structuredLogger = logger
+}
+
+// requestLogger logger of SetStructuredLogger, or slog.Default()
+func requestLogger() StructuredLogger {
+	structuredLoggerMutex.RLock()
+	logger := structuredLogger
+	structuredLoggerMutex.RUnlock()
+	if logger == nil {
+		return SlogLogger(slog.Default())
+	}
+	return logger
+}
+
+// ConfigureLogging sets the defaults of @Log from the logging section
+func ConfigureLogging(config LoggingConfig) error {
+	if err := config.validate("logging"); err != nil {
+		return err
+	}
+	redactor, err := NewRedactor(config.Redact, "")
+	if err != nil {
+		return fmt.Errorf("invalid logging.redact: %v", err)
+	}
+	level := slog.LevelInfo
+	if config.Level != "" {
+		level = requestLogLevels[config.Level]
+	}
+	loggingSettings.Store(&loggingState{config: config, level: level, redactor: redactor})
+	return nil
+}
+
+// loggingConfig logging of ConfigureLogging, or the default one
+func loggingConfig() *loggingState {
+	if state := loggingSettings.Load(); state != nil {
+		return state
+	}
+	return &loggingState{level: slog.LevelInfo}
+}
+
+// validate checks the settings, section naming them in the errors ("logging" or "@Log")
+func (c LoggingConfig) validate(section string) error {
+	if _, ok := requestLogLevels[c.Level]; c.Level != "" && !ok {
+		return fmt.Errorf("invalid %s level '%s' (valid: debug, info, warn, error)", section, c.Level)
+	}
+	for _, rule := range c.Redact {
+		if _, err := parseRedactionRule(rule); err != nil {
+			return fmt.Errorf("invalid %s redact: %v", section, err)
+		}
+	}
+	return nil
+}
+
+// parseLogArgs parses @Log(level="info", redact="password,token", bodies=true)
+func parseLogArgs(args []string) (LoggingConfig, error) {
+	var config LoggingConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@Log: unexpected argument '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "level":
+			config.Level = strings.ToLower(value)
+		case "redact":
+			config.Redact = splitMarkerList(value)
+		case "bodies":
+			bodies, err := strconv.ParseBool(value)
+			if err != nil {
+				return config, fmt.Errorf("@Log: invalid bodies '%s' (expected true or false)", value)
+			}
+			config.Bodies = bodies
+		default:
+			return config, fmt.Errorf("@Log: unknown argument '%s' (valid: level, redact, bodies)", key)
+		}
+	}
+	return config, config.validate("@Log")
+}
+
+// requestLogSettings settings of the record of a request
+type requestLogSettings struct {
+	level     slog.Level
+	bodies    bool
+	redactors []*Redactor
+}
+
+// redact applies the redaction rules of the logging section and of the route to a captured body
+func (s *requestLogSettings) redact(body []byte) []byte {
+	for _, redactor := range s.redactors {
+		body = redactor.RedactJSON(body)
+	}
+	return body
+}
+
+// LogMiddleware writes a structured record of each request (@Log) with its method, path, route, status
+// and latency, and with bodies its request and response bodies after redaction. Unset settings come from
+// the logging section; the redaction rules of body_capture, logging and the route all apply. 4xx responses
+// are logged at warn and 5xx at error at least. When logging.enabled also logs every route, a single
+// record is written with the settings of the route.
+func LogMiddleware(config LoggingConfig) gin.HandlerFunc {
+	redactor, err := NewRedactor(config.Redact, "")
+	if err != nil {
+		// Bodies are never logged with rules that could not be applied
+		LogSilent("⚠️  @Log: %v", err)
+	}
+	bodiesAllowed := err == nil
+
+	return func(c *gin.Context) {
+		global := loggingConfig()
+		settings := &requestLogSettings{
+			level:     global.level,
+			bodies:    bodiesAllowed && (config.Bodies || global.config.Bodies),
+			redactors: []*Redactor{global.redactor, redactor},
+		}
+		if level, ok := requestLogLevels[config.Level]; ok {
+			settings.level = level
+		}
+		if settings.bodies {
+			CaptureBodies(c)
+		}
+
+		if outer, ok := c.Get(requestLogKey); ok {
+			if outerSettings, ok := outer.(*requestLogSettings); ok {
+				*outerSettings = *settings
+				c.Next()
+				return
+			}
+		}
+
+		c.Set(requestLogKey, settings)
+		start := time.Now()
+		c.Next()
+		writeRequestLog(c, settings, time.Since(start))
+	}
+}
+
+// writeRequestLog sends the record of a finished request to the structured logger
+func writeRequestLog(c *gin.Context, settings *requestLogSettings, latency time.Duration) {
+	ctx := c.Request.Context()
+	status := c.Writer.Status()
+	level := settings.level
+	switch {
+	case status >= 500 && level < slog.LevelError:
+		level = slog.LevelError
+	case status >= 400 && level < slog.LevelWarn:
+		level = slog.LevelWarn
+	}
+
+	path := c.Request.URL.Path
+	if policy := GetPIIPolicy(); policy != nil {
+		path = policy.ScrubURL(path)
+	}
+
+	attrs := make([]slog.Attr, 0, 12)
+	attrs = append(attrs,
+		slog.String("method", c.Request.Method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Float64("latency_ms", durationMS(latency)),
+		slog.String("client_ip", c.ClientIP()),
+	)
+	if route := c.FullPath(); route != "" {
+		attrs = append(attrs, slog.String("route", route))
+	}
+	if identity, ok := GetRouteIdentity(c); ok && identity.OperationID != "" {
+		attrs = append(attrs, slog.String("operation_id", identity.OperationID))
+	}
+	if requestID := c.GetHeader("X-Request-ID"); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if len(c.Errors) > 0 {
+		attrs = append(attrs, slog.String("error", c.Errors.Last().Error()))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		attrs = append(attrs,
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	if capture, ok := GetBodyCapture(c); ok && settings.bodies {
+		if body := settings.redact(capture.RedactedRequestBody()); len(body) > 0 {
+			attrs = append(attrs, slog.String("request_body", string(body)))
+		}
+		if body := settings.redact(capture.RedactedResponseBody()); len(body) > 0 {
+			attrs = append(attrs, slog.String("response_body", string(body)))
+		}
+	}
+
+	requestLogger().LogRequest(ctx, level, "http request", attrs...)
+}
+
+// createLogMiddleware creates structured request logging middleware
+func createLogMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseLogArgs(args)
+	if err != nil {
+		// Rejected during generation; invalid hand-written calls use the global settings
+		LogSilent("⚠️  %v", err)
+		config = LoggingConfig{}
+	}
+	return LogMiddleware(config)
+}
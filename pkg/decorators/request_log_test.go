@@ -0,0 +1,167 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureRequestLogs sends the records of @Log to a JSON slog handler and returns its output
+func captureRequestLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetStructuredLogger(SlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	t.Cleanup(func() { SetStructuredLogger(nil) })
+	return &buf
+}
+
+// requestLogRecords decoded records of a JSON slog output
+func requestLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+// logTestRouter router echoing the JSON body of POST /login with a token, behind middlewares
+func logTestRouter(middlewares ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares...)
+	router.POST("/login", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user": body["user"], "token": "secret-token"})
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(context.DeadlineExceeded)
+		c.Status(http.StatusServiceUnavailable)
+	})
+	return router
+}
+
+func TestLogMiddleware(t *testing.T) {
+	buf := captureRequestLogs(t)
+	router := logTestRouter(LogMiddleware(LoggingConfig{Level: "debug"}))
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"ana","password":"hunter2"}`))
+	req.Header.Set("X-Request-ID", "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", http.NoBody))
+
+	records := requestLogRecords(t, buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "http request", records[0]["msg"])
+	assert.Equal(t, "DEBUG", records[0]["level"])
+	assert.Equal(t, "POST", records[0]["method"])
+	assert.Equal(t, "/login", records[0]["path"])
+	assert.Equal(t, "/login", records[0]["route"])
+	assert.Equal(t, float64(http.StatusOK), records[0]["status"])
+	assert.Equal(t, "req-1", records[0]["request_id"])
+	assert.Contains(t, records[0], "latency_ms")
+	assert.NotContains(t, records[0], "request_body", "bodies are opt-in")
+
+	assert.Equal(t, "ERROR", records[1]["level"], "5xx are logged at error")
+	assert.Equal(t, context.DeadlineExceeded.Error(), records[1]["error"])
+}
+
+func TestLogMiddleware_Bodies(t *testing.T) {
+	buf := captureRequestLogs(t)
+	router := logTestRouter(LogMiddleware(LoggingConfig{Bodies: true, Redact: []string{"password", "token"}}))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"ana","password":"hunter2"}`)))
+
+	records := requestLogRecords(t, buf)
+	require.Len(t, records, 1)
+	assert.JSONEq(t, `{"user":"ana","password":"[REDACTED]"}`, records[0]["request_body"].(string))
+	assert.JSONEq(t, `{"user":"ana","token":"[REDACTED]"}`, records[0]["response_body"].(string))
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "secret-token")
+}
+
+func TestLogMiddleware_GlobalAndRoute(t *testing.T) {
+	defer loggingSettings.Store(nil)
+	require.NoError(t, ConfigureLogging(LoggingConfig{Enabled: true, Level: "warn", Redact: []string{"password"}}))
+	buf := captureRequestLogs(t)
+
+	// The global layer and @Log write a single record, with the settings of the route
+	router := logTestRouter(LogMiddleware(LoggingConfig{}), LogMiddleware(LoggingConfig{Level: "info", Bodies: true}))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"ana","password":"hunter2"}`)))
+
+	records := requestLogRecords(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.JSONEq(t, `{"user":"ana","password":"[REDACTED]"}`, records[0]["request_body"].(string), "the rules of the logging section apply")
+
+	// Without a route level, the level of the logging section is used
+	buf.Reset()
+	logTestRouter(LogMiddleware(LoggingConfig{})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{}`)))
+	assert.Equal(t, "WARN", requestLogRecords(t, buf)[0]["level"])
+}
+
+func TestStructuredLoggerFunc(t *testing.T) {
+	var levels []slog.Level
+	var keys []string
+	SetStructuredLogger(StructuredLoggerFunc(func(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+		levels = append(levels, level)
+		for _, attr := range attrs {
+			keys = append(keys, attr.Key)
+		}
+	}))
+	defer SetStructuredLogger(nil)
+
+	logTestRouter(LogMiddleware(LoggingConfig{})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`not json`)))
+	assert.Equal(t, []slog.Level{slog.LevelWarn}, levels, "4xx are logged at warn")
+	assert.Subset(t, keys, []string{"method", "path", "status", "latency_ms", "client_ip", "route"})
+}
+
+func TestConfigureLogging(t *testing.T) {
+	defer loggingSettings.Store(nil)
+
+	assert.ErrorContains(t, ConfigureLogging(LoggingConfig{Level: "trace"}), "invalid logging level 'trace'")
+	assert.ErrorContains(t, ConfigureLogging(LoggingConfig{Redact: []string{"a..b"}}), "invalid logging redact")
+	require.NoError(t, ConfigureLogging(LoggingConfig{Level: "error"}))
+	assert.Equal(t, slog.LevelError, loggingConfig().level)
+}
+
+func TestParseLogArgs(t *testing.T) {
+	config, err := parseLogArgs([]string{`level="INFO"`, `redact="password,token"`, "bodies=true"})
+	require.NoError(t, err)
+	assert.Equal(t, LoggingConfig{Level: "info", Redact: []string{"password", "token"}, Bodies: true}, config)
+
+	for _, args := range [][]string{{"level=trace"}, {"bodies=sometimes"}, {`redact="a..b"`}, {"format=json"}, {"info"}} {
+		_, err := parseLogArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_Logging(t *testing.T) {
+	path := t.TempDir() + "/.deco.yaml"
+	writeTestFile(t, path, "version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\nlogging:\n  enabled: true\n  level: debug\n  bodies: true\n  redact: [password]\n")
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, LoggingConfig{Enabled: true, Level: "debug", Bodies: true, Redact: []string{"password"}}, config.Logging)
+
+	writeTestFile(t, path, "version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\nlogging:\n  level: verbose\n")
+	_, err = LoadConfig(path)
+	assert.Error(t, err)
+}
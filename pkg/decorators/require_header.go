@@ -0,0 +1,103 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireHeaderConfig configuration of @RequireHeader
+type RequireHeaderConfig struct {
+	Name        string
+	Pattern     *regexp.Regexp // nil only checks presence
+	Description string
+}
+
+// parseRequireHeaderArgs parses @RequireHeader("X-API-Version", pattern="^2\\.", description="...")
+func parseRequireHeaderArgs(args []string) (RequireHeaderConfig, error) {
+	var config RequireHeaderConfig
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			if config.Name != "" {
+				return config, fmt.Errorf("@RequireHeader: unexpected argument '%s'", arg)
+			}
+			config.Name = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "pattern":
+			pattern, err := regexp.Compile(value)
+			if err != nil {
+				return config, fmt.Errorf("@RequireHeader: invalid pattern '%s': %v", value, err)
+			}
+			config.Pattern = pattern
+		case "description":
+			config.Description = value
+		default:
+			return config, fmt.Errorf("@RequireHeader: unknown argument '%s' (valid: pattern, description)", key)
+		}
+	}
+
+	if config.Name == "" {
+		return config, fmt.Errorf("@RequireHeader requires a header name, e.g. @RequireHeader(\"X-API-Version\")")
+	}
+	return config, nil
+}
+
+// parameterInfo documents the header as a required parameter
+func (r RequireHeaderConfig) parameterInfo() ParameterInfo {
+	param := ParameterInfo{Name: r.Name, Type: "string", Location: "header", Required: true, Description: r.Description}
+	if r.Pattern != nil {
+		param.Pattern = r.Pattern.String()
+	}
+	return param
+}
+
+// RequireHeaderMiddleware rejects requests without the header, or whose value does not match
+// the pattern, with the standard validation 400 response
+func RequireHeaderMiddleware(config RequireHeaderConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(config.Name)
+
+		var field *ValidationField
+		switch {
+		case value == "":
+			field = &ValidationField{Field: config.Name, Tag: "required", Message: fmt.Sprintf("Header %s is required", config.Name)}
+		case config.Pattern != nil && !config.Pattern.MatchString(value):
+			field = &ValidationField{
+				Field:   config.Name,
+				Value:   value,
+				Tag:     "pattern",
+				Param:   config.Pattern.String(),
+				Message: fmt.Sprintf("Header %s must match %s", config.Name, config.Pattern),
+			}
+		}
+
+		if field != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ValidationResponse{
+				Error:   "validation_failed",
+				Message: "Invalid request headers",
+				Fields:  []ValidationField{*field},
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// createRequireHeaderMiddleware creates @RequireHeader middleware
+func createRequireHeaderMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseRequireHeaderArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return RequireHeaderMiddleware(config)
+}
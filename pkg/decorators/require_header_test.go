@@ -0,0 +1,79 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequireHeaderArgs(t *testing.T) {
+	config, err := parseRequireHeaderArgs([]string{"X-API-Version", `pattern="^2\\."`, `description="API major version"`})
+	require.NoError(t, err)
+	assert.Equal(t, "X-API-Version", config.Name)
+	assert.Equal(t, `^2\.`, config.Pattern.String())
+	assert.Equal(t, "API major version", config.Description)
+
+	config, err = parseRequireHeaderArgs([]string{`"X-Tenant"`})
+	require.NoError(t, err)
+	assert.Nil(t, config.Pattern)
+
+	for _, args := range [][]string{nil, {"pattern=^2"}, {"X-A", "X-B"}, {"X-A", "pattern=["}, {"X-A", "format=uuid"}} {
+		_, err := parseRequireHeaderArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestRequireHeaderMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/orders", createRequireHeaderMiddleware([]string{"X-API-Version", `pattern="^2\\."`}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		if version != "" {
+			req.Header.Set("X-API-Version", version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("2.1").Code)
+
+	for version, tag := range map[string]string{"": "required", "1.9": "pattern", "2x": "pattern"} {
+		w := send(version)
+		require.Equal(t, http.StatusBadRequest, w.Code, "version %q", version)
+		var response ValidationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "validation_failed", response.Error)
+		require.Len(t, response.Fields, 1)
+		assert.Equal(t, "X-API-Version", response.Fields[0].Field)
+		assert.Equal(t, tag, response.Fields[0].Tag)
+	}
+}
+
+func TestRequireHeaderMarker(t *testing.T) {
+	args, err := parseArgumentsWithValidation(`"X-API-Version", pattern="^2\\."`, "RequireHeader")
+	require.NoError(t, err)
+
+	call := generateMiddlewareCall(MarkerInstance{Name: "RequireHeader", Args: args})
+	assert.Equal(t, `deco.CreateRequireHeaderMiddleware("X-API-Version,pattern=\"^2\\\\.\"")`, call)
+	assert.Error(t, validateArgumentValues("RequireHeader", nil))
+
+	route := &RouteMeta{Method: "GET", Path: "/orders", Markers: []MarkerInstance{{Name: "RequireHeader", Args: args}}}
+	require.NoError(t, processMiddlewares(route))
+	assert.Len(t, route.MiddlewareCalls, 1)
+	require.Len(t, route.Parameters, 1)
+	assert.Equal(t, ParameterInfo{Name: "X-API-Version", Type: "string", Location: "header", Required: true, Pattern: `^2\.`}, route.Parameters[0])
+
+	param := convertToOpenAPIParameter(&route.Parameters[0], nil)
+	assert.Equal(t, "header", param.In)
+	assert.Equal(t, `^2\.`, param.Schema.Pattern)
+}
@@ -0,0 +1,336 @@
+package decorators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// Token revocation (auth.revocation): logout revokes one token, "log out everywhere" revokes every
+// token issued to a subject so far. @Auth checks the list on every request.
+
+// Revocation stores (auth.revocation.store)
+const (
+	RevocationStoreMemory = "memory"
+	RevocationStoreRedis  = "redis"
+)
+
+// RevokePath endpoint revoking a token or a subject (auth.revocation.endpoint)
+const RevokePath = "/decorators/auth/revoke"
+
+// ErrRevocationDisabled returned by the revoke helpers when no TokenRevocationStore is installed
+var ErrRevocationDisabled = errors.New("token revocation is not enabled (auth.revocation.enabled)")
+
+// TokenRevocationStore revocation list tokens and subjects are added to
+type TokenRevocationStore interface {
+	RevocationList
+	// RevokeToken revokes the token with the id until it expires (zero keeps it revoked)
+	RevokeToken(ctx context.Context, id string, expires time.Time) error
+	// RevokeSubject revokes the tokens of the subject issued up to at, remembered for ttl
+	RevokeSubject(ctx context.Context, subject string, at time.Time, ttl time.Duration) error
+}
+
+var (
+	revocationSubjectTTL = 720 * time.Hour
+	revocationMutex      sync.RWMutex
+)
+
+// TokenID identifies a token in the revocation list: its "jti", or the hash of the claims when it has none
+func TokenID(claims JWTClaims) string {
+	if id := claims.String("jti"); id != "" {
+		return id
+	}
+	data, _ := json.Marshal(claims) // map keys are sorted, the same token always hashes the same
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// revokedBySubject reports whether a token was issued before its subject was revoked at; tokens
+// without "iat" cannot prove otherwise
+func revokedBySubject(claims JWTClaims, at time.Time) bool {
+	issued, ok, err := claims.time("iat")
+	if !ok || err != nil {
+		return true
+	}
+	return !issued.After(at)
+}
+
+// MemoryRevocationList revocation list kept in memory, for single-instance deployments and tests
+type MemoryRevocationList struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time // id → expiry, zero when kept forever
+	subjects map[string]memorySubjectRevocation
+	now      func() time.Time
+}
+
+// memorySubjectRevocation subject revoked at a time, forgotten after expires
+type memorySubjectRevocation struct {
+	at      time.Time
+	expires time.Time
+}
+
+// NewMemoryRevocationList creates an empty in-memory revocation list
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]memorySubjectRevocation),
+		now:      time.Now,
+	}
+}
+
+// IsRevoked implements RevocationList
+func (m *MemoryRevocationList) IsRevoked(_ context.Context, claims JWTClaims) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+
+	if expires, ok := m.tokens[TokenID(claims)]; ok && (expires.IsZero() || now.Before(expires)) {
+		return true, nil
+	}
+	if subject, ok := m.subjects[claims.Subject()]; ok && now.Before(subject.expires) {
+		return revokedBySubject(claims, subject.at), nil
+	}
+	return false, nil
+}
+
+// RevokeToken implements TokenRevocationStore
+func (m *MemoryRevocationList) RevokeToken(_ context.Context, id string, expires time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	m.tokens[id] = expires
+	return nil
+}
+
+// RevokeSubject implements TokenRevocationStore
+func (m *MemoryRevocationList) RevokeSubject(_ context.Context, subject string, at time.Time, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	m.subjects[subject] = memorySubjectRevocation{at: at.Truncate(time.Second), expires: m.now().Add(ttl)}
+	return nil
+}
+
+// sweep drops the revocations of expired tokens
+func (m *MemoryRevocationList) sweep() {
+	now := m.now()
+	for id, expires := range m.tokens {
+		if !expires.IsZero() && !now.Before(expires) {
+			delete(m.tokens, id)
+		}
+	}
+	for subject, revocation := range m.subjects {
+		if !now.Before(revocation.expires) {
+			delete(m.subjects, subject)
+		}
+	}
+}
+
+// RedisRevocationList revocation list shared by every instance: one key per revoked token, expiring
+// with the token, and one key per revoked subject holding the revocation time
+type RedisRevocationList struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisRevocationList connects the revocation list to Redis
+func NewRedisRevocationList(config RedisConfig, prefix string) (*RedisRevocationList, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Address,
+		Password: config.Password,
+		DB:       config.DB,
+		PoolSize: config.PoolSize,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	return &RedisRevocationList{client: client, prefix: prefix}, nil
+}
+
+// IsRevoked implements RevocationList
+func (r *RedisRevocationList) IsRevoked(ctx context.Context, claims JWTClaims) (bool, error) {
+	keys := []string{r.prefix + "token:" + TokenID(claims)}
+	if subject := claims.Subject(); subject != "" {
+		keys = append(keys, r.prefix+"subject:"+subject)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil {
+		return true, nil
+	}
+	if len(values) > 1 && values[1] != nil {
+		at, err := strconv.ParseInt(fmt.Sprint(values[1]), 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid subject revocation: %v", err)
+		}
+		return revokedBySubject(claims, time.Unix(at, 0)), nil
+	}
+	return false, nil
+}
+
+// RevokeToken implements TokenRevocationStore
+func (r *RedisRevocationList) RevokeToken(ctx context.Context, id string, expires time.Time) error {
+	var ttl time.Duration
+	if !expires.IsZero() {
+		if ttl = time.Until(expires); ttl <= 0 {
+			return nil // already expired
+		}
+	}
+	return r.client.Set(ctx, r.prefix+"token:"+id, "1", ttl).Err()
+}
+
+// RevokeSubject implements TokenRevocationStore
+func (r *RedisRevocationList) RevokeSubject(ctx context.Context, subject string, at time.Time, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+"subject:"+subject, at.Unix(), ttl).Err()
+}
+
+// Close closes the Redis connection
+func (r *RedisRevocationList) Close() error {
+	return r.client.Close()
+}
+
+// ConfigureRevocation installs the revocation list of auth.revocation; when disabled the list installed
+// with UseRevocationList is kept
+func ConfigureRevocation(config RevocationConfig, redisConfig RedisConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	var store TokenRevocationStore
+	switch config.Store {
+	case RevocationStoreRedis:
+		list, err := NewRedisRevocationList(redisConfig, config.Prefix)
+		if err != nil {
+			return fmt.Errorf("revocation store: %v", err)
+		}
+		store = list
+	default:
+		store = NewMemoryRevocationList()
+	}
+
+	revocationMutex.Lock()
+	if config.SubjectTTL != "" {
+		revocationSubjectTTL, _ = ParseDurationLiteral(config.SubjectTTL)
+	}
+	revocationMutex.Unlock()
+	UseRevocationList(store)
+	return nil
+}
+
+// revocationStore returns the installed list when tokens can be added to it
+func revocationStore() (TokenRevocationStore, error) {
+	store, ok := GetRevocationList().(TokenRevocationStore)
+	if !ok {
+		return nil, ErrRevocationDisabled
+	}
+	return store, nil
+}
+
+// RevokeToken revokes a verified token until it expires, e.g. on logout
+func RevokeToken(ctx context.Context, claims JWTClaims) error {
+	store, err := revocationStore()
+	if err != nil {
+		return err
+	}
+	expires, _, _ := claims.time("exp")
+	return store.RevokeToken(ctx, TokenID(claims), expires)
+}
+
+// RevokeSubject revokes every token issued to the subject so far ("log out everywhere")
+func RevokeSubject(ctx context.Context, subject string) error {
+	if subject == "" {
+		return fmt.Errorf("subject is required")
+	}
+	store, err := revocationStore()
+	if err != nil {
+		return err
+	}
+	revocationMutex.RLock()
+	ttl := revocationSubjectTTL
+	revocationMutex.RUnlock()
+	return store.RevokeSubject(ctx, subject, time.Now(), ttl)
+}
+
+// RevokeRequestToken revokes the token @Auth verified for the request, for logout handlers
+func RevokeRequestToken(c *gin.Context) error {
+	claims, ok := JWTClaimsFrom(c)
+	if !ok {
+		return fmt.Errorf("the request has no verified token (@Auth with auth.jwt)")
+	}
+	return RevokeToken(c.Request.Context(), claims)
+}
+
+// revokeRequest body of POST /decorators/auth/revoke, with either a token or a subject
+type revokeRequest struct {
+	Token   string `json:"token,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// RevokeHandler POST /decorators/auth/revoke {"token": "..."} or {"subject": "..."}
+func RevokeHandler(c *gin.Context) {
+	var request revokeRequest
+	if err := c.ShouldBindJSON(&request); err != nil || (request.Token == "") == (request.Subject == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "send either a token or a subject"})
+		return
+	}
+	if _, err := revocationStore(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "revocation_disabled", "message": err.Error()})
+		return
+	}
+
+	if request.Subject != "" {
+		if err := RevokeSubject(c.Request.Context(), request.Subject); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "revocation_failed", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"revoked": "subject", "subject": request.Subject})
+		return
+	}
+
+	verifier := GetJWTVerifier()
+	if verifier == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "auth.jwt has no key source to verify the token"})
+		return
+	}
+	claims, err := verifier.Verify(c.Request.Context(), request.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_token", "message": err.Error()})
+		return
+	}
+	if err := RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "revocation_failed", "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": "token", "id": TokenID(claims)})
+}
+
+// validate checks the revocation configuration
+func (r RevocationConfig) validate() error {
+	if r.Store != "" && r.Store != RevocationStoreMemory && r.Store != RevocationStoreRedis {
+		return fmt.Errorf("invalid auth.revocation.store '%s' (valid: memory, redis)", r.Store)
+	}
+	if r.SubjectTTL != "" {
+		if ttl, err := ParseDurationLiteral(r.SubjectTTL); err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid auth.revocation.subject_ttl '%s'", r.SubjectTTL)
+		}
+	}
+	return nil
+}
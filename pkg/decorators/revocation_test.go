@@ -0,0 +1,106 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevocationList(t *testing.T) {
+	list := NewMemoryRevocationList()
+	now := time.Unix(1_700_000_000, 0)
+	list.now = func() time.Time { return now }
+	ctx := context.Background()
+	unix := func(at time.Time) json.Number { return json.Number(strconv.FormatInt(at.Unix(), 10)) }
+
+	token := JWTClaims{"sub": "42", "jti": "a", "iat": unix(now)}
+	require.NoError(t, list.RevokeToken(ctx, TokenID(token), now.Add(time.Minute)))
+	revoked, err := list.IsRevoked(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, _ = list.IsRevoked(ctx, JWTClaims{"sub": "42", "jti": "b", "iat": unix(now)})
+	assert.False(t, revoked, "other tokens of the subject stay valid")
+
+	now = now.Add(2 * time.Minute)
+	revoked, _ = list.IsRevoked(ctx, token)
+	assert.False(t, revoked, "the revocation ends with the token")
+
+	require.NoError(t, list.RevokeSubject(ctx, "42", now, time.Hour))
+	revoked, _ = list.IsRevoked(ctx, JWTClaims{"sub": "42", "iat": unix(now.Add(-time.Minute))})
+	assert.True(t, revoked, "issued before the subject was revoked")
+	revoked, _ = list.IsRevoked(ctx, JWTClaims{"sub": "42", "iat": unix(now.Add(time.Minute))})
+	assert.False(t, revoked, "issued after a new login")
+	revoked, _ = list.IsRevoked(ctx, JWTClaims{"sub": "42"})
+	assert.True(t, revoked, "without iat the token cannot prove it is newer")
+
+	assert.Equal(t, TokenID(JWTClaims{"sub": "1"}), TokenID(JWTClaims{"sub": "1"}))
+	assert.True(t, strings.HasPrefix(TokenID(JWTClaims{"sub": "1"}), "sha256:"))
+}
+
+func TestRevokeHelpersAndHandler(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	require.NoError(t, ConfigureAuth(AuthConfig{JWT: JWTConfig{SecretKey: "jwt-secret"}}))
+	defer func() { _ = ConfigureAuth(AuthConfig{}) }()
+	defer UseRevocationList(nil)
+
+	UseRevocationList(nil)
+	assert.ErrorIs(t, RevokeSubject(context.Background(), "42"), ErrRevocationDisabled)
+	require.NoError(t, ConfigureRevocation(RevocationConfig{Enabled: true}, RedisConfig{}))
+	assert.Error(t, ConfigureRevocation(RevocationConfig{Enabled: true, Store: "disk"}, RedisConfig{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", createAuthMiddleware([]string{"cache=1m"}), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/logout", createAuthMiddleware(nil), func(c *gin.Context) {
+		if err := RevokeRequestToken(c); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	router.POST(RevokePath, RevokeHandler)
+
+	issued := time.Now().Add(-time.Minute).Unix()
+	sign := func(jti string) string {
+		return signTestJWT(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "42", "jti": jti, "iat": issued}, hs256([]byte("s3cr3t")))
+	}
+	send := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first, second := sign("first"), sign("second")
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/me", first, "").Code)
+	assert.Equal(t, http.StatusNoContent, send(http.MethodPost, "/logout", first, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/me", first, "").Code, "the cached decision is not reused after logout")
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/me", second, "").Code)
+
+	w := send(http.MethodPost, RevokePath, "", `{"token": "`+second+`"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"second"`)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/me", second, "").Code)
+
+	third := sign("third")
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/me", third, "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, RevokePath, "", `{"subject": "42"}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/me", third, "").Code, "every token of the subject is revoked")
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, RevokePath, "", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, RevokePath, "", `{"token": "forged"}`).Code)
+}
@@ -0,0 +1,211 @@
+package decorators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of the route graph nodes
+const (
+	GraphNodeGroup      = "group"
+	GraphNodeRoute      = "route"
+	GraphNodeMiddleware = "middleware"
+	GraphNodeUpstream   = "upstream"
+	GraphNodeSchema     = "schema"
+)
+
+// RouteGraphNode node of the route dependency graph
+type RouteGraphNode struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Kind  string `json:"kind"`
+}
+
+// RouteGraphEdge dependency between two nodes, with an optional label (e.g. the response status)
+type RouteGraphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+}
+
+// RouteGraph groups → routes → middlewares → upstreams, and the schemas of the routes
+type RouteGraph struct {
+	Nodes []RouteGraphNode `json:"nodes"`
+	Edges []RouteGraphEdge `json:"edges"`
+
+	index map[string]string // kind+label -> node ID
+	edges map[RouteGraphEdge]bool
+}
+
+// BuildRouteGraph builds the dependency graph of parsed routes. Middlewares with the same arguments
+// and schemas are shared between routes; @Proxy middlewares point to their target or service.
+func BuildRouteGraph(routes []*RouteMeta) *RouteGraph {
+	graph := &RouteGraph{index: make(map[string]string), edges: make(map[RouteGraphEdge]bool)}
+
+	sorted := make([]*RouteMeta, 0, len(routes))
+	for _, route := range routes {
+		if route.Method != "" && route.Path != "" {
+			sorted = append(sorted, route)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	for _, route := range sorted {
+		routeID := graph.node(GraphNodeRoute, route.Method+" "+route.Path)
+		if route.Group != nil {
+			graph.edge(graph.node(GraphNodeGroup, route.Group.Name), routeID, "")
+		}
+
+		for _, mw := range route.MiddlewareInfo {
+			mwID := graph.node(GraphNodeMiddleware, middlewareGraphLabel(mw))
+			graph.edge(routeID, mwID, "")
+			if mw.Name == "Proxy" {
+				for _, upstream := range proxyUpstreams(mw.Args) {
+					graph.edge(mwID, graph.node(GraphNodeUpstream, upstream), "")
+				}
+			}
+		}
+
+		for _, param := range route.Parameters {
+			if param.Location == "body" && param.Type != "" {
+				graph.edge(routeID, graph.node(GraphNodeSchema, graphSchemaName(param.Type)), "body")
+			}
+		}
+		for _, response := range route.Responses {
+			if response.Type != "" {
+				graph.edge(routeID, graph.node(GraphNodeSchema, graphSchemaName(response.Type)), response.Code)
+			}
+		}
+	}
+	return graph
+}
+
+// node returns the ID of a node, adding it on first use
+func (g *RouteGraph) node(kind, label string) string {
+	key := kind + "\x00" + label
+	if id, exists := g.index[key]; exists {
+		return id
+	}
+	id := fmt.Sprintf("n%d", len(g.Nodes)+1)
+	g.index[key] = id
+	g.Nodes = append(g.Nodes, RouteGraphNode{ID: id, Label: label, Kind: kind})
+	return id
+}
+
+// edge adds an edge once
+func (g *RouteGraph) edge(from, to, label string) {
+	edge := RouteGraphEdge{From: from, To: to, Label: label}
+	if !g.edges[edge] {
+		g.edges[edge] = true
+		g.Edges = append(g.Edges, edge)
+	}
+}
+
+// middlewareGraphLabel Name(key=value, ...) with sorted arguments
+func middlewareGraphLabel(mw MiddlewareInfo) string {
+	if len(mw.Args) == 0 {
+		return mw.Name
+	}
+	args := make([]string, 0, len(mw.Args))
+	for key, value := range mw.Args {
+		args = append(args, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(args)
+	return mw.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// proxyUpstreams targets of a @Proxy: the URL(s), or the discovered service
+func proxyUpstreams(args map[string]interface{}) []string {
+	var upstreams []string
+	if target, ok := args["target"].(string); ok && target != "" {
+		upstreams = append(upstreams, target)
+	}
+	if targets, ok := args["targets"].(string); ok && targets != "" {
+		for _, target := range strings.Split(targets, ",") {
+			upstreams = append(upstreams, strings.TrimSpace(target))
+		}
+	}
+	if service, ok := args["service"].(string); ok && service != "" {
+		upstreams = append(upstreams, "service:"+service)
+	}
+	return upstreams
+}
+
+// graphSchemaName schema of a Go type name ("[]*models.User" -> "User")
+func graphSchemaName(typeName string) string {
+	typeName = strings.TrimLeft(typeName, "[]*")
+	if _, name, qualified := strings.Cut(typeName, "."); qualified {
+		return name
+	}
+	return typeName
+}
+
+// DOT renders the graph in Graphviz format
+func (g *RouteGraph) DOT() string {
+	shapes := map[string]string{
+		GraphNodeGroup:      "folder",
+		GraphNodeRoute:      "box",
+		GraphNodeMiddleware: "ellipse",
+		GraphNodeUpstream:   "cylinder",
+		GraphNodeSchema:     "note",
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph routes {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", node.ID, dotQuote(node.Label), shapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", edge.From, edge.To, dotQuote(edge.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func (g *RouteGraph) Mermaid() string {
+	shapes := map[string][2]string{
+		GraphNodeGroup:      {"[[", "]]"},
+		GraphNodeRoute:      {"[", "]"},
+		GraphNodeMiddleware: {"([", "])"},
+		GraphNodeUpstream:   {"[(", ")]"},
+		GraphNodeSchema:     {">", "]"},
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		shape := shapes[node.Kind]
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", node.ID, shape[0], mermaidEscape(node.Label), shape[1])
+	}
+	for _, edge := range g.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", edge.From, mermaidEscape(edge.Label), edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", edge.From, edge.To)
+		}
+	}
+	return b.String()
+}
+
+// dotQuote quotes a Graphviz string
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// mermaidEscape escapes a Mermaid label written between quotes
+func mermaidEscape(value string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(value)
+}
@@ -0,0 +1,74 @@
+package decorators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphTestRoutes() []*RouteMeta {
+	group := &GroupInfo{Name: "orders", Prefix: "/orders"}
+	auth := MiddlewareInfo{Name: "Auth", Args: map[string]interface{}{"role": "admin"}}
+	return []*RouteMeta{
+		{
+			Method: "POST", Path: "/orders", Group: group,
+			MiddlewareInfo: []MiddlewareInfo{auth},
+			Parameters:     []ParameterInfo{{Name: "body", Location: "body", Type: "CreateOrderRequest"}},
+			Responses:      []ResponseInfo{{Code: "201", Type: "*models.Order"}, {Code: "400", Description: "Invalid"}},
+		},
+		{
+			Method: "GET", Path: "/orders", Group: group,
+			MiddlewareInfo: []MiddlewareInfo{auth, {Name: "Proxy", Args: map[string]interface{}{"service": "billing"}}},
+			Responses:      []ResponseInfo{{Code: "200", Type: "[]Order"}},
+		},
+		{FuncName: "OnOrderCreated", Subscription: &SubscriptionInfo{Topic: "order.created"}},
+	}
+}
+
+func TestBuildRouteGraph(t *testing.T) {
+	graph := BuildRouteGraph(graphTestRoutes())
+
+	labels := make(map[string]string)
+	for _, node := range graph.Nodes {
+		labels[node.ID] = node.Kind + ":" + node.Label
+	}
+	var edges []string
+	for _, edge := range graph.Edges {
+		edges = append(edges, labels[edge.From]+" -> "+labels[edge.To]+" "+edge.Label)
+	}
+
+	require.Len(t, graph.Nodes, 8)
+	assert.Equal(t, []string{
+		"group:orders -> route:GET /orders ",
+		"route:GET /orders -> middleware:Auth(role=admin) ",
+		"route:GET /orders -> middleware:Proxy(service=billing) ",
+		"middleware:Proxy(service=billing) -> upstream:service:billing ",
+		"route:GET /orders -> schema:Order 200",
+		"group:orders -> route:POST /orders ",
+		"route:POST /orders -> middleware:Auth(role=admin) ",
+		"route:POST /orders -> schema:CreateOrderRequest body",
+		"route:POST /orders -> schema:Order 201",
+	}, edges)
+}
+
+func TestRouteGraphRender(t *testing.T) {
+	graph := BuildRouteGraph(graphTestRoutes()[:1])
+
+	dot := graph.DOT()
+	assert.Contains(t, dot, "digraph routes {")
+	assert.Contains(t, dot, `n1 [label="POST /orders", shape=box];`)
+	assert.Contains(t, dot, `n2 [label="orders", shape=folder];`)
+	assert.Contains(t, dot, `n2 -> n1;`)
+	assert.Contains(t, dot, `n1 -> n5 [label="201"];`)
+
+	mermaid := graph.Mermaid()
+	assert.Contains(t, mermaid, "flowchart LR\n")
+	assert.Contains(t, mermaid, `n2[["orders"]]`)
+	assert.Contains(t, mermaid, `n3(["Auth(role=admin)"])`)
+	assert.Contains(t, mermaid, `n4>"CreateOrderRequest"]`)
+	assert.Contains(t, mermaid, `n1 -->|"body"| n4`)
+
+	assert.Equal(t, `"say \"hi\""`, dotQuote(`say "hi"`))
+	assert.Equal(t, "say #quot;hi#quot;", mermaidEscape(`say "hi"`))
+}
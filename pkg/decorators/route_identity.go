@@ -0,0 +1,113 @@
+package decorators
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// routeIdentityKey gin context key of the RouteIdentity of the matched route
+const routeIdentityKey = "deco.route"
+
+// routeIdentityContextKey request context key of the RouteIdentity, for code that only has a context
+type routeIdentityContextKey struct{}
+
+// RouteIdentity identity of the route serving a request: its template instead of the raw path, so logs,
+// traces and metrics keep a bounded cardinality
+type RouteIdentity struct {
+	Method      string   `json:"method"`
+	Route       string   `json:"route"` // "/users/:id"
+	Group       string   `json:"group,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	OperationID string   `json:"operation_id,omitempty"`
+}
+
+// routeIdentity identity of a registered route
+func (r *RouteEntry) routeIdentity() RouteIdentity {
+	identity := RouteIdentity{Method: r.Method, Route: r.Path, Tags: r.Tags, OperationID: r.OperationID}
+	if r.Group != nil {
+		identity.Group = r.Group.Name
+	}
+	return identity
+}
+
+// LogAttrs fields of the identity for slog
+func (r RouteIdentity) LogAttrs() []slog.Attr {
+	attrs := []slog.Attr{slog.String("http.method", r.Method), slog.String("http.route", r.Route)}
+	if r.Group != "" {
+		attrs = append(attrs, slog.String("route.group", r.Group))
+	}
+	if len(r.Tags) > 0 {
+		attrs = append(attrs, slog.String("route.tags", strings.Join(r.Tags, ",")))
+	}
+	if r.OperationID != "" {
+		attrs = append(attrs, slog.String("operation_id", r.OperationID))
+	}
+	return attrs
+}
+
+// spanAttributes attributes of the identity for spans
+func (r RouteIdentity) spanAttributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("http.route", r.Route)}
+	if r.Group != "" {
+		attrs = append(attrs, attribute.String("deco.route.group", r.Group))
+	}
+	if len(r.Tags) > 0 {
+		attrs = append(attrs, attribute.StringSlice("deco.route.tags", r.Tags))
+	}
+	if r.OperationID != "" {
+		attrs = append(attrs, attribute.String("deco.operation_id", r.OperationID))
+	}
+	return attrs
+}
+
+// RouteIdentityMiddleware records the identity of the route on the request, first in the chain of each
+// registered route: the access log, the spans of @Telemetry and RequestLogger carry it
+func RouteIdentityMiddleware(identity RouteIdentity) gin.HandlerFunc {
+	attrs := identity.spanAttributes()
+	return func(c *gin.Context) {
+		c.Set(routeIdentityKey, &identity)
+		ctx := context.WithValue(c.Request.Context(), routeIdentityContextKey{}, &identity)
+		c.Request = c.Request.WithContext(ctx)
+
+		// Spans started before the route chain, e.g. by an engine-wide tracing middleware
+		AddSpanAttributes(ctx, attrs...)
+		c.Next()
+	}
+}
+
+// GetRouteIdentity identity of the route serving the request
+func GetRouteIdentity(c *gin.Context) (*RouteIdentity, bool) {
+	value, exists := c.Get(routeIdentityKey)
+	if !exists {
+		return nil, false
+	}
+	identity, ok := value.(*RouteIdentity)
+	return identity, ok
+}
+
+// RouteIdentityFromContext identity of the route serving the request of ctx
+func RouteIdentityFromContext(ctx context.Context) (*RouteIdentity, bool) {
+	identity, ok := ctx.Value(routeIdentityContextKey{}).(*RouteIdentity)
+	return identity, ok
+}
+
+// RequestLogger slog.Default with the identity of the route serving the request of ctx
+//
+//	decorators.RequestLogger(c.Request.Context()).Info("order created", "order_id", order.ID)
+func RequestLogger(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	identity, ok := RouteIdentityFromContext(ctx)
+	if !ok {
+		return logger
+	}
+	attrs := identity.LogAttrs()
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return logger.With(args...)
+}
@@ -0,0 +1,82 @@
+package decorators
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteIdentityMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(previous)
+
+	accessLog, err := NewAccessLoggerWithWriter(AccessLogConfig{Format: AccessLogFormatJSON}, &buf)
+	require.NoError(t, err)
+
+	route := &RouteEntry{
+		Method: http.MethodGet, Path: "/api/users/:id", Tags: []string{"users", "public"},
+		Group: &GroupInfo{Name: "api", Prefix: "/api"}, OperationID: "getUser",
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(accessLog.Middleware())
+	engine.GET(route.Path, RouteIdentityMiddleware(route.routeIdentity()), func(c *gin.Context) {
+		identity, ok := GetRouteIdentity(c)
+		require.True(t, ok)
+		assert.Equal(t, "getUser", identity.OperationID)
+		RequestLogger(c.Request.Context()).Info("user loaded", "user_id", c.Param("id"))
+		c.Status(http.StatusOK)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/42", nil))
+
+	decoder := json.NewDecoder(&buf)
+	var logLine, accessLine map[string]interface{}
+	require.NoError(t, decoder.Decode(&logLine))
+	require.NoError(t, decoder.Decode(&accessLine))
+	assert.Equal(t, "user loaded", logLine["msg"])
+	assert.Equal(t, "/api/users/:id", logLine["http.route"], "the template, not the raw path")
+	assert.Equal(t, "api", logLine["route.group"])
+	assert.Equal(t, "users,public", logLine["route.tags"])
+	assert.Equal(t, "getUser", logLine["operation_id"])
+	assert.Equal(t, "42", logLine["user_id"])
+	assert.Equal(t, "getUser", accessLine["operation_id"])
+
+	// Outside a route the default logger is returned as is
+	assert.Same(t, slog.Default(), RequestLogger(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
+
+func TestGenerateInitFile_OperationID(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/:id")
+// @Group("orders")
+func GetOrder(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders.go"), []byte(source), 0o600))
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	config := DefaultConfig()
+	config.OpenAPI.OperationID.Strategy = OperationIDStrategyMethodPath
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", config))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), `OperationID: "getOrdersById",`, "computed on the path with the group prefix")
+}
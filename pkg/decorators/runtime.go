@@ -41,7 +41,7 @@ func init() {
 	}
 
 	// Parse routes
-	routes, err := ParseDirectory(handlersDir)
+	routes, err := ParseDirectoryWithCache(handlersDir, parseCacheForConfig(config, config.Generate.OutputPath()))
 	if err != nil {
 		LogSilent("gin-decorators: Error in automatic parsing: %v", err)
 		return
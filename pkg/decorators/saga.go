@@ -0,0 +1,334 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SagaIDHeader request header carrying the saga instance of a @SagaStep request
+const SagaIDHeader = "X-Saga-ID"
+
+// defaultSagaTTL how long saga states are kept in the store
+const defaultSagaTTL = 7 * 24 * time.Hour
+
+// sagaMemorySize saga instances kept by the default in-memory store
+const sagaMemorySize = 10000
+
+// Saga statuses
+const (
+	SagaStatusRunning      = "running"
+	SagaStatusCompleted    = "completed"
+	SagaStatusCompensated  = "compensated"
+	SagaStatusCompensating = "compensating"
+	SagaStatusFailed       = "failed" // a compensation failed; needs manual intervention
+)
+
+// SagaState persisted state of a saga instance
+type SagaState struct {
+	ID         string                 `json:"id"`
+	Saga       string                 `json:"saga"`
+	Status     string                 `json:"status"`
+	Completed  []string               `json:"completed"` // completed steps, in execution order
+	FailedStep string                 `json:"failed_step,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"` // shared by steps and compensations
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// SagaAction step or compensation of a saga
+type SagaAction func(ctx context.Context, state *SagaState) error
+
+// sagaStep step of a saga; a nil action marks a step executed by a @SagaStep route
+type sagaStep struct {
+	name       string
+	action     SagaAction
+	compensate SagaAction
+}
+
+// Saga multi-step business transaction: when a step fails, the compensations of the
+// completed steps run in reverse order. State is persisted after every step.
+type Saga struct {
+	name  string
+	steps []sagaStep
+	store CacheStore
+	ttl   time.Duration
+}
+
+// saga registry used by @SagaStep
+var (
+	sagas      = make(map[string]*Saga)
+	sagasMutex sync.RWMutex
+)
+
+// NewSaga creates a saga persisted in memory; use WithStore for a shared store such as Redis
+func NewSaga(name string) *Saga {
+	return &Saga{name: name, store: NewMemoryCache(sagaMemorySize), ttl: defaultSagaTTL}
+}
+
+// WithStore persists saga states in store for ttl (0 keeps the default of 7 days)
+func (s *Saga) WithStore(store CacheStore, ttl time.Duration) *Saga {
+	s.store = store
+	if ttl > 0 {
+		s.ttl = ttl
+	}
+	return s
+}
+
+// Step adds a step. A nil action declares a step executed over HTTP by a @SagaStep route;
+// compensate may be nil for steps that need no undo.
+func (s *Saga) Step(name string, action, compensate SagaAction) *Saga {
+	s.steps = append(s.steps, sagaStep{name: name, action: action, compensate: compensate})
+	return s
+}
+
+// Name returns the saga name
+func (s *Saga) Name() string {
+	return s.name
+}
+
+// RegisterSaga makes a saga available to @SagaStep routes
+func RegisterSaga(saga *Saga) {
+	sagasMutex.Lock()
+	defer sagasMutex.Unlock()
+	sagas[saga.name] = saga
+}
+
+// GetSaga returns a registered saga
+func GetSaga(name string) *Saga {
+	sagasMutex.RLock()
+	defer sagasMutex.RUnlock()
+	return sagas[name]
+}
+
+// Run executes the in-process steps of a new saga instance in order, compensating on failure.
+// Steps already completed by a previous run of the same id are skipped.
+func (s *Saga) Run(ctx context.Context, id string, data map[string]interface{}) (*SagaState, error) {
+	state, err := s.begin(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range s.steps {
+		if step.action == nil || contains(state.Completed, step.name) {
+			continue
+		}
+		if err := step.action(ctx, state); err != nil {
+			if compensationErr := s.abort(ctx, state, step.name, err); compensationErr != nil {
+				return state, compensationErr
+			}
+			return state, fmt.Errorf("saga %s: step %s failed: %v", s.name, step.name, err)
+		}
+		if err := s.completeStep(ctx, state, step.name); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// State returns the persisted state of a saga instance, nil when unknown
+func (s *Saga) State(ctx context.Context, id string) (*SagaState, error) {
+	entry, err := s.store.Get(ctx, s.key(id))
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	var state SagaState
+	if err := json.Unmarshal(entry.Data, &state); err != nil {
+		return nil, fmt.Errorf("saga %s: invalid state of %s: %v", s.name, id, err)
+	}
+	return &state, nil
+}
+
+// Compensate undoes the completed steps of a saga instance in reverse order
+func (s *Saga) Compensate(ctx context.Context, id string) (*SagaState, error) {
+	state, err := s.State(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("saga %s: unknown instance %s", s.name, id)
+	}
+	return state, s.compensate(ctx, state)
+}
+
+// begin loads a saga instance, creating it when new; finished instances cannot run again
+func (s *Saga) begin(ctx context.Context, id string, data map[string]interface{}) (*SagaState, error) {
+	state, err := s.State(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = &SagaState{ID: id, Saga: s.name, Status: SagaStatusRunning, Completed: []string{}, Data: data}
+		return state, s.save(ctx, state)
+	}
+	if state.Status != SagaStatusRunning {
+		return state, fmt.Errorf("saga %s: instance %s is %s", s.name, id, state.Status)
+	}
+	return state, nil
+}
+
+// completeStep records a completed step; the saga completes with its last step
+func (s *Saga) completeStep(ctx context.Context, state *SagaState, step string) error {
+	if !contains(state.Completed, step) {
+		state.Completed = append(state.Completed, step)
+	}
+	if len(state.Completed) == len(s.steps) {
+		state.Status = SagaStatusCompleted
+	}
+	return s.save(ctx, state)
+}
+
+// abort records a failed step and compensates the completed ones
+func (s *Saga) abort(ctx context.Context, state *SagaState, step string, cause error) error {
+	state.FailedStep = step
+	state.Error = cause.Error()
+	return s.compensate(ctx, state)
+}
+
+// compensate runs the compensations of the completed steps in reverse order
+func (s *Saga) compensate(ctx context.Context, state *SagaState) error {
+	state.Status = SagaStatusCompensating
+	if err := s.save(ctx, state); err != nil {
+		return err
+	}
+
+	for i := len(state.Completed) - 1; i >= 0; i-- {
+		step := s.step(state.Completed[i])
+		if step == nil || step.compensate == nil {
+			continue
+		}
+		if err := step.compensate(ctx, state); err != nil {
+			state.Status = SagaStatusFailed
+			state.Error = fmt.Sprintf("compensation of %s failed: %v", step.name, err)
+			_ = s.save(ctx, state)
+			return fmt.Errorf("saga %s: %s", s.name, state.Error)
+		}
+	}
+
+	state.Status = SagaStatusCompensated
+	return s.save(ctx, state)
+}
+
+// step returns a step by name
+func (s *Saga) step(name string) *sagaStep {
+	for i := range s.steps {
+		if s.steps[i].name == name {
+			return &s.steps[i]
+		}
+	}
+	return nil
+}
+
+// save persists a saga state
+func (s *Saga) save(ctx context.Context, state *SagaState) error {
+	state.UpdatedAt = time.Now()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("saga %s: error encoding state: %v", s.name, err)
+	}
+	entry := &CacheEntry{Data: data, Status: http.StatusOK, ExpiresAt: time.Now().Add(s.ttl)}
+	if err := s.store.Set(ctx, s.key(state.ID), entry, s.ttl); err != nil {
+		return fmt.Errorf("saga %s: error saving state: %v", s.name, err)
+	}
+	return nil
+}
+
+// key store key of a saga instance
+func (s *Saga) key(id string) string {
+	return "saga:" + s.name + ":" + id
+}
+
+// SagaStepConfig configuration of @SagaStep
+type SagaStepConfig struct {
+	Saga string
+	Step string
+}
+
+// parseSagaStepArgs parses @SagaStep(saga="checkout", step="reserve-stock")
+func parseSagaStepArgs(args []string) (SagaStepConfig, error) {
+	var config SagaStepConfig
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			return config, fmt.Errorf("@SagaStep: expected key=value, found '%s'", arg)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "saga":
+			config.Saga = value
+		case "step":
+			config.Step = value
+		default:
+			return config, fmt.Errorf("@SagaStep: unknown argument '%s' (valid: saga, step)", key)
+		}
+	}
+
+	if config.Saga == "" || config.Step == "" {
+		return config, fmt.Errorf("@SagaStep requires saga and step, e.g. saga=\"checkout\", step=\"reserve-stock\"")
+	}
+	return config, nil
+}
+
+// SagaStepMiddleware runs a route as a step of the saga instance in the X-Saga-ID header:
+// a successful response (< 400) completes the step, an error response compensates the
+// steps completed so far. The saga must be registered with RegisterSaga.
+func SagaStepMiddleware(config SagaStepConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(SagaIDHeader)
+		if id == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "missing_saga_id",
+				"message": fmt.Sprintf("Header %s is required", SagaIDHeader),
+			})
+			return
+		}
+
+		saga := GetSaga(config.Saga)
+		if saga == nil || saga.step(config.Step) == nil {
+			LogNormal("❌ @SagaStep: step %s of saga %s is not registered", config.Step, config.Saga)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "saga_not_registered"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		state, err := saga.begin(ctx, id, nil)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if state != nil {
+				status = http.StatusConflict
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": "saga_not_running", "message": err.Error()})
+			return
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusBadRequest {
+			err = saga.completeStep(ctx, state, config.Step)
+		} else {
+			err = saga.abort(ctx, state, config.Step, fmt.Errorf("HTTP %d", status))
+		}
+		if err != nil {
+			LogNormal("❌ @SagaStep %s/%s: %v", config.Saga, id, err)
+		}
+	}
+}
+
+// createSagaStepMiddleware creates @SagaStep middleware
+func createSagaStepMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseSagaStepArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return SagaStepMiddleware(config)
+}
@@ -0,0 +1,139 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSaga builds a saga whose actions and compensations append to log
+func recordingSaga(name string, log *[]string, failAt string) *Saga {
+	action := func(step string) SagaAction {
+		return func(context.Context, *SagaState) error {
+			if step == failAt {
+				return errors.New("out of stock")
+			}
+			*log = append(*log, step)
+			return nil
+		}
+	}
+	compensate := func(step string) SagaAction {
+		return func(context.Context, *SagaState) error {
+			*log = append(*log, "undo "+step)
+			return nil
+		}
+	}
+	return NewSaga(name).
+		Step("charge", action("charge"), compensate("charge")).
+		Step("reserve", action("reserve"), compensate("reserve")).
+		Step("ship", action("ship"), nil)
+}
+
+func TestSaga_Run(t *testing.T) {
+	var log []string
+	saga := recordingSaga("checkout", &log, "")
+
+	state, err := saga.Run(context.Background(), "order-1", map[string]interface{}{"order": "1"})
+	require.NoError(t, err)
+	assert.Equal(t, SagaStatusCompleted, state.Status)
+	assert.Equal(t, []string{"charge", "reserve", "ship"}, log)
+
+	persisted, err := saga.State(context.Background(), "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"charge", "reserve", "ship"}, persisted.Completed)
+	assert.Equal(t, "1", persisted.Data["order"])
+
+	// Finished instances do not run again
+	_, err = saga.Run(context.Background(), "order-1", nil)
+	assert.Error(t, err)
+}
+
+func TestSaga_CompensatesInReverseOrder(t *testing.T) {
+	var log []string
+	saga := recordingSaga("checkout", &log, "ship")
+
+	state, err := saga.Run(context.Background(), "order-2", nil)
+	assert.EqualError(t, err, "saga checkout: step ship failed: out of stock")
+	assert.Equal(t, SagaStatusCompensated, state.Status)
+	assert.Equal(t, "ship", state.FailedStep)
+	assert.Equal(t, []string{"charge", "reserve", "undo reserve", "undo charge"}, log)
+}
+
+func TestSaga_FailedCompensation(t *testing.T) {
+	saga := NewSaga("transfer").
+		Step("debit", func(context.Context, *SagaState) error { return nil }, func(context.Context, *SagaState) error {
+			return errors.New("ledger offline")
+		}).
+		Step("credit", func(context.Context, *SagaState) error { return errors.New("account closed") }, nil)
+
+	state, err := saga.Run(context.Background(), "t-1", nil)
+	assert.Error(t, err)
+	assert.Equal(t, SagaStatusFailed, state.Status)
+	assert.Contains(t, state.Error, "ledger offline")
+}
+
+func TestParseSagaStepArgs(t *testing.T) {
+	config, err := parseSagaStepArgs([]string{`saga="checkout"`, `step="reserve"`})
+	require.NoError(t, err)
+	assert.Equal(t, SagaStepConfig{Saga: "checkout", Step: "reserve"}, config)
+
+	for _, args := range [][]string{nil, {"saga=checkout"}, {"step=reserve"}, {"saga=checkout", "step=reserve", "timeout=1s"}} {
+		_, err := parseSagaStepArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestSagaStepMiddleware(t *testing.T) {
+	var log []string
+	saga := NewSaga("booking").
+		Step("flight", nil, func(context.Context, *SagaState) error {
+			log = append(log, "cancel flight")
+			return nil
+		}).
+		Step("hotel", nil, nil)
+	RegisterSaga(saga)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/flights", SagaStepMiddleware(SagaStepConfig{Saga: "booking", Step: "flight"}), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	router.POST("/hotels", SagaStepMiddleware(SagaStepConfig{Saga: "booking", Step: "hotel"}), func(c *gin.Context) {
+		c.Status(http.StatusConflict)
+	})
+
+	send := func(path, sagaID string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if sagaID != "" {
+			req.Header.Set(SagaIDHeader, sagaID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, send("/flights", ""))
+	assert.Equal(t, http.StatusCreated, send("/flights", "trip-1"))
+	assert.Equal(t, http.StatusConflict, send("/hotels", "trip-1"))
+
+	state, err := saga.State(context.Background(), "trip-1")
+	require.NoError(t, err)
+	assert.Equal(t, SagaStatusCompensated, state.Status)
+	assert.Equal(t, "hotel", state.FailedStep)
+	assert.Equal(t, []string{"cancel flight"}, log)
+
+	// A compensated saga rejects further steps
+	assert.Equal(t, http.StatusConflict, send("/flights", "trip-1"))
+}
+
+func TestSagaStepMarker(t *testing.T) {
+	call := generateMiddlewareCall(MarkerInstance{Name: "SagaStep", Args: []string{"saga=checkout", "step=reserve"}})
+	assert.Equal(t, `deco.CreateSagaStepMiddleware("saga=checkout,step=reserve")`, call)
+	assert.Error(t, validateArgumentValues("SagaStep", []string{"saga=checkout"}))
+}
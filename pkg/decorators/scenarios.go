@@ -0,0 +1,533 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioFile declarative HTTP scenarios (deco test scenarios.yaml)
+type ScenarioFile struct {
+	Target    string            `yaml:"target,omitempty"`    // base URL of the server, overridden by --target
+	Variables map[string]string `yaml:"variables,omitempty"` // initial variables of every scenario
+	Scenarios []Scenario        `yaml:"scenarios"`
+}
+
+// Scenario steps run in order; a failed step ends its scenario
+type Scenario struct {
+	Name  string         `yaml:"name"`
+	Steps []ScenarioStep `yaml:"steps"`
+}
+
+// ScenarioStep one request, its assertions and the variables it captures for the next steps.
+// ${name} in the path, query, headers, body and expected values is replaced by a variable.
+type ScenarioStep struct {
+	Name    string            `yaml:"name,omitempty"`
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Query   map[string]string `yaml:"query,omitempty"`
+	Body    interface{}       `yaml:"body,omitempty"`    // sent as JSON; a string is sent as is
+	Expect  ScenarioExpect    `yaml:"expect,omitempty"`  // assertions on the response
+	Capture map[string]string `yaml:"capture,omitempty"` // variable -> JSON path of the body ("$.id") or "header:Name"
+}
+
+// ScenarioExpect assertions of a step
+type ScenarioExpect struct {
+	Status       int                    `yaml:"status,omitempty"`
+	Headers      map[string]string      `yaml:"headers,omitempty"`
+	BodyContains string                 `yaml:"body_contains,omitempty"`
+	JSON         map[string]interface{} `yaml:"json,omitempty"` // JSON path -> expected value
+}
+
+// ScenarioStepResult outcome of a step
+type ScenarioStepResult struct {
+	Name     string        `json:"name"`
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Route    string        `json:"route,omitempty"` // registered route serving the step
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Failures []string      `json:"failures,omitempty"`
+}
+
+// ScenarioResult outcome of a scenario
+type ScenarioResult struct {
+	Name   string               `json:"name"`
+	Passed bool                 `json:"passed"`
+	Steps  []ScenarioStepResult `json:"steps"`
+}
+
+// ScenarioReport outcome of a scenario file
+type ScenarioReport struct {
+	Scenarios []ScenarioResult `json:"scenarios"`
+	Passed    int              `json:"passed"`
+	Failed    int              `json:"failed"`
+	Uncovered []string         `json:"uncovered,omitempty"` // routes no step called, "GET /users/:id"
+}
+
+// ScenarioRoute route the steps are matched against
+type ScenarioRoute struct {
+	Method string
+	Path   string // "/users/:id" or "/users/{id}"
+}
+
+// ScenarioRunner runs scenarios through Do. With Routes set, every step must call one of them and the
+// routes no step called are reported.
+type ScenarioRunner struct {
+	BaseURL   string
+	Do        func(*http.Request) (*http.Response, error)
+	Routes    []ScenarioRoute
+	Variables map[string]string // over the variables of the file (--var)
+}
+
+// NewHandlerScenarioRunner runs scenarios in process against handler (e.g. the engine of deco.Default),
+// matching the steps against the registered routes
+func NewHandlerScenarioRunner(handler http.Handler) *ScenarioRunner {
+	runner := &ScenarioRunner{
+		BaseURL: "http://deco.test",
+		Do: func(req *http.Request) (*http.Response, error) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			return recorder.Result(), nil
+		},
+	}
+	for _, route := range GetRoutes() {
+		runner.Routes = append(runner.Routes, ScenarioRoute{Method: route.Method, Path: route.Path})
+	}
+	return runner
+}
+
+// NewHTTPScenarioRunner runs scenarios against a running server
+func NewHTTPScenarioRunner(baseURL string, timeout time.Duration) *ScenarioRunner {
+	client := &http.Client{Timeout: timeout}
+	return &ScenarioRunner{BaseURL: strings.TrimSuffix(baseURL, "/"), Do: client.Do}
+}
+
+// SpecScenarioRoutes routes of an OpenAPI document, for runners against a running server
+func SpecScenarioRoutes(spec *OpenAPISpec) []ScenarioRoute {
+	var routes []ScenarioRoute
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			routes = append(routes, ScenarioRoute{Method: strings.ToUpper(method), Path: path})
+		}
+	}
+	return routes
+}
+
+// LoadScenarioFile reads and checks a scenario file
+func LoadScenarioFile(path string) (*ScenarioFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenarios: %v", err)
+	}
+	var file ScenarioFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid scenarios %s: %v", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenarios %s: %v", path, err)
+	}
+	return &file, nil
+}
+
+// validate checks every step has a request
+func (f *ScenarioFile) validate() error {
+	if len(f.Scenarios) == 0 {
+		return fmt.Errorf("no scenarios")
+	}
+	for i, scenario := range f.Scenarios {
+		if scenario.Name == "" {
+			return fmt.Errorf("scenario %d has no name", i+1)
+		}
+		if len(scenario.Steps) == 0 {
+			return fmt.Errorf("scenario '%s' has no steps", scenario.Name)
+		}
+		for j, step := range scenario.Steps {
+			if step.Method == "" || !strings.HasPrefix(step.Path, "/") {
+				return fmt.Errorf("step %d of '%s' needs a method and a path starting with /", j+1, scenario.Name)
+			}
+			for variable, source := range step.Capture {
+				if !strings.HasPrefix(source, "$") && !strings.HasPrefix(source, "header:") {
+					return fmt.Errorf("capture '%s' of step %d of '%s' must be a JSON path ($.id) or header:Name", variable, j+1, scenario.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Run runs the scenarios of file in order
+func (r *ScenarioRunner) Run(ctx context.Context, file *ScenarioFile) *ScenarioReport {
+	report := &ScenarioReport{}
+	covered := make(map[string]bool)
+	for _, scenario := range file.Scenarios {
+		result := r.runScenario(ctx, file, scenario, covered)
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Scenarios = append(report.Scenarios, result)
+	}
+	for _, route := range r.Routes {
+		if key := route.Method + " " + route.Path; !covered[key] {
+			report.Uncovered = append(report.Uncovered, key)
+		}
+	}
+	sort.Strings(report.Uncovered)
+	return report
+}
+
+// runScenario runs the steps until one fails
+func (r *ScenarioRunner) runScenario(ctx context.Context, file *ScenarioFile, scenario Scenario, covered map[string]bool) ScenarioResult {
+	variables := make(map[string]interface{}, len(file.Variables)+len(r.Variables))
+	for name, value := range file.Variables {
+		variables[name] = value
+	}
+	for name, value := range r.Variables {
+		variables[name] = value
+	}
+
+	result := ScenarioResult{Name: scenario.Name, Passed: true}
+	for i, step := range scenario.Steps {
+		stepResult := r.runStep(ctx, step, variables, covered)
+		if stepResult.Name == "" {
+			stepResult.Name = fmt.Sprintf("step %d", i+1)
+		}
+		result.Steps = append(result.Steps, stepResult)
+		if len(stepResult.Failures) > 0 {
+			result.Passed = false
+			break
+		}
+	}
+	return result
+}
+
+// runStep sends the request of a step, checks its expectations and captures its variables
+func (r *ScenarioRunner) runStep(ctx context.Context, step ScenarioStep, variables map[string]interface{}, covered map[string]bool) ScenarioStepResult {
+	result := ScenarioStepResult{Name: step.Name, Method: strings.ToUpper(step.Method)}
+	fail := func(format string, args ...interface{}) ScenarioStepResult {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		return result
+	}
+
+	expand := func(value string) string {
+		expanded, err := expandScenarioVariables(value, variables)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		}
+		return expanded
+	}
+	result.Path = expand(step.Path)
+	if len(r.Routes) > 0 {
+		route, ok := matchScenarioRoute(r.Routes, result.Method, result.Path)
+		if !ok {
+			return fail("no route serves %s %s", result.Method, result.Path)
+		}
+		result.Route = route.Method + " " + route.Path
+		covered[result.Route] = true
+	}
+
+	query := url.Values{}
+	for name, value := range step.Query {
+		query.Set(name, expand(value))
+	}
+	target := r.BaseURL + result.Path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	body, contentType, err := scenarioBody(step.Body, variables)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if len(result.Failures) > 0 {
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, result.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return fail("invalid request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range step.Headers {
+		req.Header.Set(name, expand(value))
+	}
+
+	start := time.Now()
+	resp, err := r.Do(req)
+	result.Duration = time.Since(start)
+	if err != nil {
+		return fail("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fail("error reading response: %v", err)
+	}
+	result.Status = resp.StatusCode
+
+	result.Failures = append(result.Failures, checkScenarioExpect(step.Expect, resp, data, variables)...)
+	if len(result.Failures) > 0 {
+		return result
+	}
+	for variable, source := range step.Capture {
+		value, err := captureScenarioValue(source, resp.Header, data)
+		if err != nil {
+			return fail("capture %s: %v", variable, err)
+		}
+		variables[variable] = value
+	}
+	return result
+}
+
+// scenarioBody encodes the body of a step: strings as is, anything else as JSON
+func scenarioBody(body interface{}, variables map[string]interface{}) ([]byte, string, error) {
+	if body == nil {
+		return nil, "", nil
+	}
+	expanded, err := expandScenarioValue(body, variables)
+	if err != nil {
+		return nil, "", err
+	}
+	if text, ok := expanded.(string); ok {
+		return []byte(text), "", nil
+	}
+	data, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid body: %v", err)
+	}
+	return data, "application/json", nil
+}
+
+// checkScenarioExpect compares the response with the expectations of a step
+func checkScenarioExpect(expect ScenarioExpect, resp *http.Response, body []byte, variables map[string]interface{}) []string {
+	var failures []string
+	if expect.Status != 0 && resp.StatusCode != expect.Status {
+		failures = append(failures, fmt.Sprintf("status = %d, want %d (body: %s)", resp.StatusCode, expect.Status, truncateScenarioBody(body)))
+	}
+	for name, want := range expect.Headers {
+		want, err := expandScenarioVariables(want, variables)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if got := resp.Header.Get(name); got != want {
+			failures = append(failures, fmt.Sprintf("header %s = %q, want %q", name, got, want))
+		}
+	}
+	if expect.BodyContains != "" {
+		want, err := expandScenarioVariables(expect.BodyContains, variables)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else if !strings.Contains(string(body), want) {
+			failures = append(failures, fmt.Sprintf("body does not contain %q (body: %s)", want, truncateScenarioBody(body)))
+		}
+	}
+	if len(expect.JSON) == 0 {
+		return failures
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return append(failures, fmt.Sprintf("body is not JSON: %v", err))
+	}
+	paths := make([]string, 0, len(expect.JSON))
+	for path := range expect.JSON {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		want, err := expandScenarioValue(expect.JSON[path], variables)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		got, err := jsonPathValue(document, path)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if !jsonEqual(got, want) {
+			failures = append(failures, fmt.Sprintf("%s = %s, want %s", path, jsonText(got), jsonText(want)))
+		}
+	}
+	return failures
+}
+
+// captureScenarioValue reads a captured variable from the response
+func captureScenarioValue(source string, header http.Header, body []byte) (interface{}, error) {
+	if name, ok := strings.CutPrefix(source, "header:"); ok {
+		value := header.Get(strings.TrimSpace(name))
+		if value == "" {
+			return nil, fmt.Errorf("header %s is not set", name)
+		}
+		return value, nil
+	}
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("body is not JSON: %v", err)
+	}
+	return jsonPathValue(document, source)
+}
+
+// scenarioVariablePattern ${name} references
+var scenarioVariablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// expandScenarioVariables replaces the ${name} references of a string
+func expandScenarioVariables(value string, variables map[string]interface{}) (string, error) {
+	var missing []string
+	expanded := scenarioVariablePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		name := scenarioVariablePattern.FindStringSubmatch(reference)[1]
+		variable, ok := variables[name]
+		if !ok {
+			missing = append(missing, name)
+			return reference
+		}
+		if text, isText := variable.(string); isText {
+			return text
+		}
+		return jsonText(variable)
+	})
+	if len(missing) > 0 {
+		return expanded, fmt.Errorf("undefined variable %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// expandScenarioValue replaces the references in the strings of a YAML value; a string that is a
+// single reference takes the captured value with its JSON type
+func expandScenarioValue(value interface{}, variables map[string]interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		if match := scenarioVariablePattern.FindStringSubmatch(typed); match != nil && match[0] == typed {
+			if variable, ok := variables[match[1]]; ok {
+				return variable, nil
+			}
+		}
+		return expandScenarioVariables(typed, variables)
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			value, err := expandScenarioValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			expanded[key] = value
+		}
+		return expanded, nil
+	case []interface{}:
+		expanded := make([]interface{}, len(typed))
+		for i, item := range typed {
+			value, err := expandScenarioValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			expanded[i] = value
+		}
+		return expanded, nil
+	}
+	return value, nil
+}
+
+// jsonPathValue value at a JSON path: "$", "$.user.name", "$.items[0].id", "$.items.length()"
+func jsonPathValue(document interface{}, path string) (interface{}, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSON path must start with $")
+	}
+	rest := strings.NewReplacer("[", ".", "]", "").Replace(strings.TrimPrefix(path, "$"))
+	current := document
+	for _, segment := range strings.Split(rest, ".") {
+		segment = strings.Trim(segment, `"'`)
+		if segment == "" {
+			continue
+		}
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			if segment == "length()" {
+				current = float64(len(typed))
+				continue
+			}
+			value, ok := typed[segment]
+			if !ok {
+				return nil, fmt.Errorf("field %s not found", segment)
+			}
+			current = value
+		case []interface{}:
+			if segment == "length()" {
+				current = float64(len(typed))
+				continue
+			}
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, fmt.Errorf("index %s out of range (%d items)", segment, len(typed))
+			}
+			current = typed[index]
+		default:
+			return nil, fmt.Errorf("%s is not an object or array", segment)
+		}
+	}
+	return current, nil
+}
+
+// jsonEqual compares two values by their JSON encoding, so YAML integers equal JSON numbers
+func jsonEqual(a, b interface{}) bool {
+	var left, right interface{}
+	if json.Unmarshal([]byte(jsonText(a)), &left) != nil || json.Unmarshal([]byte(jsonText(b)), &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// jsonText JSON encoding of a value
+func jsonText(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// truncateScenarioBody body quoted in failures
+func truncateScenarioBody(body []byte) string {
+	if len(body) > 200 {
+		return string(body[:200]) + "..."
+	}
+	return string(body)
+}
+
+// matchScenarioRoute route serving method and a concrete path; static segments win over parameters
+func matchScenarioRoute(routes []ScenarioRoute, method, path string) (ScenarioRoute, bool) {
+	path, _, _ = strings.Cut(path, "?")
+	segments := splitSpecPath(path)
+	var best ScenarioRoute
+	bestParams := -1
+	for _, route := range routes {
+		if !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		params, ok := matchSpecPath(splitSpecPath(route.Path), segments)
+		if ok && (bestParams < 0 || len(params) < bestParams) {
+			best, bestParams = route, len(params)
+		}
+	}
+	return best, bestParams >= 0
+}
@@ -0,0 +1,152 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenarios = `
+variables:
+  token: secret
+scenarios:
+  - name: create and fetch a user
+    steps:
+      - name: create
+        method: POST
+        path: /users
+        headers:
+          Authorization: Bearer ${token}
+        body:
+          name: Ana
+          tags: [admin]
+        expect:
+          status: 201
+          json:
+            $.name: Ana
+            $.tags.length(): 1
+        capture:
+          user_id: $.id
+          location: header:Location
+      - name: fetch
+        method: GET
+        path: /users/${user_id}
+        expect:
+          status: 200
+          headers:
+            X-User: ${user_id}
+          json:
+            $.id: ${user_id}
+            $.tags[0]: admin
+  - name: missing user
+    steps:
+      - method: GET
+        path: /users/404
+        expect:
+          status: 200
+      - method: GET
+        path: /users/1
+`
+
+// scenarioTestEngine users API of the scenario tests
+func scenarioTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/users", func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer secret" {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		var user map[string]interface{}
+		_ = c.ShouldBindJSON(&user)
+		user["id"] = 7
+		c.Header("Location", "/users/7")
+		c.JSON(http.StatusCreated, user)
+	})
+	engine.GET("/users/:id", func(c *gin.Context) {
+		if c.Param("id") != "7" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.Header("X-User", "7")
+		c.JSON(http.StatusOK, gin.H{"id": 7, "tags": []string{"admin"}})
+	})
+	engine.DELETE("/users/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return engine
+}
+
+func TestScenarioRunner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenarios.yaml")
+	writeTestFile(t, path, testScenarios)
+	file, err := LoadScenarioFile(path)
+	require.NoError(t, err)
+
+	runner := NewHandlerScenarioRunner(scenarioTestEngine())
+	runner.Routes = []ScenarioRoute{{"POST", "/users"}, {"GET", "/users/:id"}, {"DELETE", "/users/:id"}}
+	report := runner.Run(context.Background(), file)
+
+	require.Len(t, report.Scenarios, 2)
+	created := report.Scenarios[0]
+	assert.True(t, created.Passed, "%+v", created.Steps)
+	assert.Equal(t, "/users/7", created.Steps[1].Path, "captured variables feed the next steps")
+	assert.Equal(t, "GET /users/:id", created.Steps[1].Route)
+
+	missing := report.Scenarios[1]
+	assert.False(t, missing.Passed)
+	require.Len(t, missing.Steps, 1, "a failed step ends the scenario")
+	assert.Contains(t, missing.Steps[0].Failures[0], "status = 404, want 200")
+
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, []string{"DELETE /users/:id"}, report.Uncovered)
+}
+
+func TestScenarioRunner_UnknownRoute(t *testing.T) {
+	runner := NewHandlerScenarioRunner(scenarioTestEngine())
+	runner.Routes = []ScenarioRoute{{"GET", "/users/{id}"}}
+	report := runner.Run(context.Background(), &ScenarioFile{Scenarios: []Scenario{{
+		Name:  "typo",
+		Steps: []ScenarioStep{{Method: "GET", Path: "/user/7"}, {Method: "GET", Path: "/users/${nobody}"}},
+	}}})
+	assert.Equal(t, []string{"no route serves GET /user/7"}, report.Scenarios[0].Steps[0].Failures)
+
+	report = runner.Run(context.Background(), &ScenarioFile{Scenarios: []Scenario{{
+		Name: "undefined", Steps: []ScenarioStep{{Method: "GET", Path: "/users/${nobody}"}},
+	}}})
+	assert.Equal(t, []string{"undefined variable nobody"}, report.Scenarios[0].Steps[0].Failures)
+}
+
+func TestLoadScenarioFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"empty.yaml":   "scenarios: []",
+		"path.yaml":    "scenarios:\n  - name: a\n    steps:\n      - method: GET\n        path: users",
+		"capture.yaml": "scenarios:\n  - name: a\n    steps:\n      - method: GET\n        path: /users\n        capture:\n          id: id",
+		"unknown.yaml": "scenarios:\n  - name: a\n    steps:\n      - method: GET\n        path: /users\n        expect:\n          code: 200",
+	} {
+		path := filepath.Join(dir, name)
+		writeTestFile(t, path, content)
+		_, err := LoadScenarioFile(path)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestJSONPathValue(t *testing.T) {
+	document := map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": float64(1)}}}
+	value, err := jsonPathValue(document, "$.items[0].id")
+	require.NoError(t, err)
+	assert.True(t, jsonEqual(value, 1))
+
+	_, err = jsonPathValue(document, "$.items[3].id")
+	assert.Error(t, err)
+	_, err = jsonPathValue(document, "items")
+	assert.Error(t, err)
+	value, _ = jsonPathValue(document, "$")
+	assert.True(t, strings.HasPrefix(jsonText(value), `{"items"`))
+}
@@ -0,0 +1,116 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MigrationReportFileName report of schema versions and deprecated fields, written next to the generated file
+const MigrationReportFileName = "schema_migrations.md"
+
+// MigrationReportPath returns the migration report path for a generated file
+func MigrationReportPath(outputPath string) string {
+	return filepath.Join(filepath.Dir(outputPath), MigrationReportFileName)
+}
+
+// deprecationNotice human-readable deprecation of a property
+func deprecationNotice(property *PropertyInfo) string {
+	if property.RemovedIn != "" {
+		return fmt.Sprintf("Deprecated: will be removed in %s.", property.RemovedIn)
+	}
+	return "Deprecated."
+}
+
+// BuildMigrationReport lists versioned schemas and their deprecated fields, grouped by removal version.
+// It returns "" when no schema is versioned or deprecates a field.
+func BuildMigrationReport(schemas map[string]*SchemaInfo) string {
+	names := make([]string, 0, len(schemas))
+	for name, schema := range schemas {
+		if schema.Version != "" || len(deprecatedProperties(schema)) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var report strings.Builder
+	report.WriteString("# Schema Migrations\n\n")
+	report.WriteString("Generated by deco from @SchemaVersion and deprecated/removedIn struct tags.\n\n")
+	report.WriteString("| Schema | Version | Deprecated fields |\n|---|---|---|\n")
+	for _, name := range names {
+		schema := schemas[name]
+		version := schema.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(&report, "| %s | %s | %d |\n", name, version, len(deprecatedProperties(schema)))
+	}
+
+	// Removals grouped by version, so clients see what breaks in each release
+	removals := make(map[string][]string)
+	for _, name := range names {
+		for _, property := range deprecatedProperties(schemas[name]) {
+			removedIn := property.RemovedIn
+			if removedIn == "" {
+				removedIn = "unscheduled"
+			}
+			entry := fmt.Sprintf("- `%s.%s`", name, property.Name)
+			if property.Description != "" {
+				entry += " - " + property.Description
+			}
+			removals[removedIn] = append(removals[removedIn], entry)
+		}
+	}
+
+	versions := make([]string, 0, len(removals))
+	for version := range removals {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		if version == "unscheduled" {
+			report.WriteString("\n## Deprecated, removal not scheduled\n\n")
+		} else {
+			fmt.Fprintf(&report, "\n## Removed in %s\n\n", version)
+		}
+		report.WriteString(strings.Join(removals[version], "\n") + "\n")
+	}
+
+	return report.String()
+}
+
+// deprecatedProperties returns the deprecated properties of a schema, sorted by name
+func deprecatedProperties(schema *SchemaInfo) []*PropertyInfo {
+	var properties []*PropertyInfo
+	for _, property := range schema.Properties {
+		if property.Deprecated {
+			properties = append(properties, property)
+		}
+	}
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties
+}
+
+// WriteMigrationReport writes the migration report of the registered schemas next to the generated file.
+// A stale report is removed when nothing is versioned or deprecated anymore.
+func WriteMigrationReport(outputPath string) error {
+	path := MigrationReportPath(outputPath)
+	report := BuildMigrationReport(GetSchemas())
+	if report == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing migration report: %v", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(report), 0o600); err != nil {
+		return fmt.Errorf("error writing migration report: %v", err)
+	}
+	LogVerbose("📑 Schema migration report written to %s", path)
+	return nil
+}
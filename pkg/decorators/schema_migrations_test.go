@@ -0,0 +1,88 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseVersionedSchemas(t *testing.T) {
+	ClearSchemas()
+	t.Cleanup(ClearSchemas)
+
+	dir := t.TempDir()
+	source := `package handlers
+
+// @Schema()
+// @SchemaVersion("2")
+type UserResponse struct {
+	ID       int    ` + "`json:\"id\"`" + `
+	Name     string ` + "`json:\"name\"`" + `
+	FullName string ` + "`json:\"full_name\" deprecated:\"true\" removedIn:\"v3\"`" + ` // use name
+	Login    string ` + "`json:\"login\" deprecated:\"true\"`" + `
+}
+
+// @SchemaVersion("1")
+type OrderResponse struct {
+	Code string ` + "`json:\"code\" removedIn:\"v2\"`" + `
+}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(source), 0o600))
+	_, err := ParseDirectory(dir)
+	assert.NoError(t, err)
+}
+
+func TestSchemaVersionAndDeprecatedFields(t *testing.T) {
+	parseVersionedSchemas(t)
+
+	user := GetSchema("UserResponse")
+	if assert.NotNil(t, user) {
+		assert.Equal(t, "2", user.Version)
+		assert.True(t, user.Properties["full_name"].Deprecated)
+		assert.Equal(t, "v3", user.Properties["full_name"].RemovedIn)
+		assert.True(t, user.Properties["login"].Deprecated)
+		assert.False(t, user.Properties["name"].Deprecated)
+	}
+
+	// @SchemaVersion alone marks a schema; removedIn implies deprecated
+	order := GetSchema("OrderResponse")
+	if assert.NotNil(t, order) {
+		assert.True(t, order.Properties["code"].Deprecated)
+	}
+
+	openAPI := convertSchemaInfoToOpenAPISchema(user)
+	assert.Equal(t, "2", openAPI.SchemaVersion)
+	fullName := openAPI.Properties["full_name"]
+	assert.True(t, fullName.Deprecated)
+	assert.Equal(t, "v3", fullName.RemovedIn)
+	assert.Equal(t, "use name Deprecated: will be removed in v3.", fullName.Description)
+}
+
+func TestBuildMigrationReport(t *testing.T) {
+	parseVersionedSchemas(t)
+
+	report := BuildMigrationReport(GetSchemas())
+	assert.Contains(t, report, "| UserResponse | 2 | 2 |")
+	assert.Contains(t, report, "## Removed in v2\n\n- `OrderResponse.code`\n")
+	assert.Contains(t, report, "## Removed in v3\n\n- `UserResponse.full_name` - use name\n")
+	assert.Contains(t, report, "## Deprecated, removal not scheduled\n\n- `UserResponse.login`\n")
+	assert.Less(t, strings.Index(report, "v2"), strings.Index(report, "v3"))
+
+	assert.Empty(t, BuildMigrationReport(map[string]*SchemaInfo{"Plain": {Name: "Plain"}}))
+}
+
+func TestWriteMigrationReport(t *testing.T) {
+	parseVersionedSchemas(t)
+	outputPath := filepath.Join(t.TempDir(), "init_decorators.go")
+
+	assert.NoError(t, WriteMigrationReport(outputPath))
+	assert.FileExists(t, MigrationReportPath(outputPath))
+
+	// Stale reports are removed once nothing is versioned
+	ClearSchemas()
+	assert.NoError(t, WriteMigrationReport(outputPath))
+	assert.NoFileExists(t, MigrationReportPath(outputPath))
+}
@@ -1,9 +1,11 @@
 package decorators
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -66,8 +68,8 @@ func parseEntityFromStruct(_ *token.FileSet, fileName string, structDecl *ast.Ge
 	}
 	commentText := strings.Join(comments, "\n")
 
-	// Look for @Schema marker
-	schemaRegex := regexp.MustCompile(`@Schema\s*\(([^)]*)\)`)
+	// Look for @Schema marker (@SchemaVersion implies it)
+	schemaRegex := regexp.MustCompile(`@Schema(Version)?\s*\(([^)]*)\)`)
 	if !schemaRegex.MatchString(commentText) {
 		return nil // Not a schema struct
 	}
@@ -89,6 +91,9 @@ func parseEntityFromStruct(_ *token.FileSet, fileName string, structDecl *ast.Ge
 					if marker.Name == "Description" && len(marker.Args) > 0 {
 						entity.Description = strings.Trim(marker.Args[0], `"`)
 					}
+					if marker.Name == "SchemaVersion" {
+						entity.Version = parseSchemaVersion(entity.Name, marker.Args)
+					}
 				}
 
 				return entity
@@ -125,6 +130,34 @@ func parseStructFields(structType *ast.StructType) []FieldMeta {
 				if validateTag := extractValidateTag(tagValue); validateTag != "" {
 					fieldMeta.Validation = validateTag
 				}
+
+				// Extract example tags
+				if example, ok := extractExampleTag(tagValue); ok {
+					fieldMeta.Example = parseExampleValue(example, fieldMeta.Type)
+				}
+
+				// Extract deprecation tags; removedIn implies deprecated
+				if deprecated, ok := lookupStructTag(tagValue, "deprecated"); ok {
+					fieldMeta.Deprecated, _ = strconv.ParseBool(deprecated)
+				}
+				if removedIn, ok := lookupStructTag(tagValue, "removedIn"); ok && removedIn != "" {
+					fieldMeta.RemovedIn = removedIn
+					fieldMeta.Deprecated = true
+				}
+
+				// Extract sensitive tag, applied by @Sensitive
+				if sensitive, ok := lookupStructTag(tagValue, "sensitive"); ok {
+					if sensitive != SensitiveMask && sensitive != SensitiveEncrypt {
+						LogSilent("⚠️  %s: invalid sensitive tag '%s' (valid: mask, encrypt)", name.Name, sensitive)
+					} else {
+						fieldMeta.Sensitive = sensitive
+					}
+				}
+
+				// Extract acl tag, applied by @FieldACL
+				if acl, ok := lookupStructTag(tagValue, "acl"); ok {
+					fieldMeta.ACL = splitMarkerList(acl)
+				}
 			}
 
 			// Extract field comment/description
@@ -172,6 +205,53 @@ func extractJSONTag(tag string) string {
 	return ""
 }
 
+// extractExampleTag extracts the example tag from struct tag
+func extractExampleTag(tag string) (string, bool) {
+	return lookupStructTag(tag, "example")
+}
+
+// lookupStructTag looks a key up in a struct tag literal as found in the AST
+func lookupStructTag(tag, key string) (string, bool) {
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		unquoted = strings.Trim(tag, "`")
+	}
+	return reflect.StructTag(unquoted).Lookup(key)
+}
+
+// parseSchemaVersion reads @SchemaVersion("2"); invalid markers are reported and ignored
+func parseSchemaVersion(schemaName string, args []string) string {
+	if len(args) != 1 || strings.Trim(args[0], `"' `) == "" {
+		LogSilent("⚠️  %s: @SchemaVersion expects a single version, e.g. @SchemaVersion(\"2\")", schemaName)
+		return ""
+	}
+	return strings.Trim(args[0], `"' `)
+}
+
+// parseExampleValue converts an example tag to the field's JSON type (JSON literals are kept as-is)
+func parseExampleValue(raw, goType string) interface{} {
+	switch mapGoTypeToOpenAPIType(goType) {
+	case "integer":
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value
+		}
+	case "number":
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case "array", "object":
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value
+		}
+	}
+	return raw
+}
+
 // extractValidateTag extracts validation tag from struct tag
 func extractValidateTag(tag string) string {
 	validateRegex := regexp.MustCompile(`validate:"([^"]*)"`)
@@ -189,6 +269,7 @@ func convertEntityToSchema(entity *EntityMeta) *SchemaInfo {
 		Description: entity.Description,
 		Type:        "object",
 		Properties:  make(map[string]*PropertyInfo),
+		Version:     entity.Version,
 		PackageName: entity.PackageName,
 		FileName:    entity.FileName,
 	}
@@ -200,6 +281,12 @@ func convertEntityToSchema(entity *EntityMeta) *SchemaInfo {
 			Name:        getFieldNameForJSON(&field),
 			Type:        mapGoTypeToOpenAPIType(field.Type),
 			Description: field.Description,
+			Example:     field.Example,
+			GoType:      field.Type,
+			Deprecated:  field.Deprecated,
+			RemovedIn:   field.RemovedIn,
+			Sensitive:   field.Sensitive,
+			ACL:         field.ACL,
 		}
 
 		// Set format if applicable
@@ -0,0 +1,118 @@
+package decorators
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SecretProvider source of the keys used by the framework (cache encryption, ...), e.g. a Vault or KMS client
+type SecretProvider interface {
+	// Secret returns the value of a named secret, e.g. "cache-encryption-key"
+	Secret(ctx context.Context, name string) ([]byte, error)
+}
+
+// EnvSecretProvider default provider reading secrets from environment variables: the name is
+// upper-cased with '-' and '.' replaced by '_' (cache-encryption-key -> CACHE_ENCRYPTION_KEY).
+// Values are base64-encoded; values that are not valid base64 are used as is.
+type EnvSecretProvider struct{}
+
+// Secret reads the environment variable of a secret
+func (EnvSecretProvider) Secret(_ context.Context, name string) ([]byte, error) {
+	variable := strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
+	value, set := os.LookupEnv(variable)
+	if !set || value == "" {
+		return nil, fmt.Errorf("secret '%s' not found (environment variable %s is not set)", name, variable)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return decoded, nil
+	}
+	return []byte(value), nil
+}
+
+var (
+	secretProviderMutex sync.RWMutex
+	secretProvider      SecretProvider = EnvSecretProvider{}
+)
+
+// UseSecretProvider replaces the provider the framework reads its keys from (environment variables by default)
+func UseSecretProvider(provider SecretProvider) {
+	secretProviderMutex.Lock()
+	defer secretProviderMutex.Unlock()
+	if provider == nil {
+		provider = EnvSecretProvider{}
+	}
+	secretProvider = provider
+}
+
+// GetSecret reads a secret from the current provider
+func GetSecret(ctx context.Context, name string) ([]byte, error) {
+	secretProviderMutex.RLock()
+	provider := secretProvider
+	secretProviderMutex.RUnlock()
+	return provider.Secret(ctx, name)
+}
+
+// secretCipher AES-GCM cipher whose key (16, 24 or 32 bytes) is read from the secret provider on first use,
+// so providers registered after the generated init code are honored
+type secretCipher struct {
+	keyName string
+
+	mu   sync.Mutex
+	aead cipher.AEAD
+}
+
+// load returns the AEAD, reading the key until it is available
+func (s *secretCipher) load(ctx context.Context) (cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.aead != nil {
+		return s.aead, nil
+	}
+
+	key, err := GetSecret(ctx, s.keyName)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secret '%s': %v", s.keyName, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s.aead = aead
+	return aead, nil
+}
+
+// seal encrypts plaintext bound to additionalData, returning nonce || ciphertext
+func (s *secretCipher) seal(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the output of seal
+func (s *secretCipher) open(ctx context.Context, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
@@ -0,0 +1,49 @@
+package decorators
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSecretProvider map[string][]byte
+
+func (p staticSecretProvider) Secret(_ context.Context, name string) ([]byte, error) {
+	if value, exists := p[name]; exists {
+		return value, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("CACHE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+	t.Setenv("APP_SIGNING_KEY", "not base64!")
+
+	value, err := EnvSecretProvider{}.Secret(context.Background(), "cache-encryption-key")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", string(value))
+
+	value, err = EnvSecretProvider{}.Secret(context.Background(), "app.signing-key")
+	require.NoError(t, err)
+	assert.Equal(t, "not base64!", string(value))
+
+	_, err = EnvSecretProvider{}.Secret(context.Background(), "missing-key")
+	assert.EqualError(t, err, "secret 'missing-key' not found (environment variable MISSING_KEY is not set)")
+}
+
+func TestUseSecretProvider(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"db-password": []byte("s3cret")})
+	defer UseSecretProvider(nil)
+
+	value, err := GetSecret(context.Background(), "db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(value))
+
+	UseSecretProvider(nil)
+	_, err = GetSecret(context.Background(), "db-password")
+	assert.Error(t, err)
+}
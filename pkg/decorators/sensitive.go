@@ -0,0 +1,491 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Actions of the `sensitive` struct tag: sensitive:"mask" or sensitive:"encrypt"
+const (
+	SensitiveMask    = "mask"
+	SensitiveEncrypt = "encrypt"
+)
+
+// DefaultFieldEncryptionKey secret holding the AES key of encrypted fields (16, 24 or 32 bytes)
+const DefaultFieldEncryptionKey = "field-encryption-key"
+
+// sensitiveCiphertextPrefix marks encrypted field values: "enc:v1:" + base64(nonce || ciphertext)
+const sensitiveCiphertextPrefix = "enc:v1:"
+
+// sensitiveMaskValue replacement of masked fields
+const sensitiveMaskValue = "********"
+
+// SensitiveConfig fields handled by @Sensitive, as JSON path rules (see Redactor)
+type SensitiveConfig struct {
+	Mask    []string // response fields replaced by a fixed mask
+	Encrypt []string // response fields encrypted, request fields decrypted
+	KeyName string   // secret name of the key, defaults to DefaultFieldEncryptionKey
+}
+
+// SensitiveAccess audit event of sensitive fields handed to a handler (decrypted) or to a client (encrypted)
+type SensitiveAccess struct {
+	Event     string    `json:"event"`
+	Direction string    `json:"direction"` // "request" or "response"
+	Action    string    `json:"action"`    // "decrypt", "encrypt" or "mask"
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	Fields    []string  `json:"fields"`
+	UserID    string    `json:"user_id,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var (
+	sensitiveAuditHandler    func(SensitiveAccess)
+	sensitiveAuditHandlerMux sync.RWMutex
+)
+
+// SetSensitiveAuditHandler registers a callback for sensitive field access events (nil restores logging only)
+func SetSensitiveAuditHandler(handler func(SensitiveAccess)) {
+	sensitiveAuditHandlerMux.Lock()
+	defer sensitiveAuditHandlerMux.Unlock()
+	sensitiveAuditHandler = handler
+}
+
+// parseSensitiveArgs reads @Sensitive(mask=email|phone, encrypt=ssn|$.card.number, key=pii-key)
+func parseSensitiveArgs(args []string) (SensitiveConfig, error) {
+	var config SensitiveConfig
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@Sensitive: invalid argument '%s' (expected mask=, encrypt= or key=)", arg)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "mask", "encrypt":
+			var rules []string
+			for _, rule := range strings.Split(value, "|") {
+				if rule = strings.TrimSpace(rule); rule == "" {
+					continue
+				}
+				if _, err := parseRedactionRule(rule); err != nil {
+					return config, fmt.Errorf("@Sensitive: %v", err)
+				}
+				rules = append(rules, rule)
+			}
+			if strings.TrimSpace(key) == "mask" {
+				config.Mask = append(config.Mask, rules...)
+			} else {
+				config.Encrypt = append(config.Encrypt, rules...)
+			}
+		case "key":
+			config.KeyName = value
+		default:
+			return config, fmt.Errorf("@Sensitive: unknown argument '%s' (valid: mask, encrypt, key)", strings.TrimSpace(key))
+		}
+	}
+	return config, nil
+}
+
+// processSensitiveMarker adds the @Sensitive middleware once the route's @Param and @Response types are known:
+// fields tagged sensitive:"..." in their schemas are added to the explicit rules, then baked into the call
+func processSensitiveMarker(marker MarkerInstance, route *RouteMeta, parameters []ParameterInfo, responses []ResponseInfo, middlewareCalls *[]string, middlewareInfo *[]MiddlewareInfo) {
+	config, err := parseSensitiveArgs(marker.Args)
+	if err != nil {
+		LogSilent("⚠️  %s: %v", route.FuncName, err)
+		return
+	}
+
+	var types []string
+	for _, param := range parameters {
+		if param.Location == "body" {
+			types = append(types, param.Type)
+		}
+	}
+	for _, response := range responses {
+		types = append(types, response.Type)
+	}
+	for _, typeName := range types {
+		mask, encrypt := sensitiveSchemaRules(typeName, "$", make(map[string]bool))
+		config.Mask = appendUnique(config.Mask, mask...)
+		config.Encrypt = appendUnique(config.Encrypt, encrypt...)
+	}
+	if len(config.Mask) == 0 && len(config.Encrypt) == 0 {
+		LogSilent("⚠️  %s: @Sensitive found no fields (tag them with sensitive:\"mask\" or sensitive:\"encrypt\")", route.FuncName)
+	}
+
+	var args []string
+	if len(config.Mask) > 0 {
+		args = append(args, "mask="+strings.Join(config.Mask, "|"))
+	}
+	if len(config.Encrypt) > 0 {
+		args = append(args, "encrypt="+strings.Join(config.Encrypt, "|"))
+	}
+	if config.KeyName != "" {
+		args = append(args, "key="+config.KeyName)
+	}
+
+	*middlewareCalls = append(*middlewareCalls, fmt.Sprintf(`deco.CreateSensitiveMiddleware(%q)`, strings.Join(args, ",")))
+	*middlewareInfo = append(*middlewareInfo, MiddlewareInfo{
+		Name:        marker.Name,
+		Args:        parseArgsToMap(args),
+		Description: getMiddlewareDescription(marker.Name),
+	})
+}
+
+// sensitiveSchemaRules anchored rules of the sensitive fields of a Go type ("User", "[]User"), following nested schemas
+func sensitiveSchemaRules(typeName, path string, visited map[string]bool) (mask, encrypt []string) {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if itemType, isSlice := strings.CutPrefix(typeName, "[]"); isSlice {
+		return sensitiveSchemaRules(itemType, path+".*", visited)
+	}
+	if _, name, qualified := strings.Cut(typeName, "."); qualified {
+		typeName = name
+	}
+
+	schema := GetSchema(typeName)
+	if schema == nil || visited[typeName] {
+		return nil, nil
+	}
+	visited[typeName] = true
+	defer delete(visited, typeName)
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property := schema.Properties[name]
+		fieldPath := path + "." + name
+		switch property.Sensitive {
+		case SensitiveMask:
+			mask = append(mask, fieldPath)
+			continue
+		case SensitiveEncrypt:
+			encrypt = append(encrypt, fieldPath)
+			continue
+		}
+		if property.GoType != "" {
+			nestedMask, nestedEncrypt := sensitiveSchemaRules(property.GoType, fieldPath, visited)
+			mask = append(mask, nestedMask...)
+			encrypt = append(encrypt, nestedEncrypt...)
+		}
+	}
+	return mask, encrypt
+}
+
+// appendUnique appends the values not yet in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// sensitiveFields compiled @Sensitive configuration
+type sensitiveFields struct {
+	mask    [][]string
+	encrypt [][]string
+	cipher  *secretCipher
+}
+
+// SensitiveMiddleware masks and encrypts the configured fields of JSON responses and transparently
+// decrypts encrypted fields ("enc:v1:..." values) of JSON request bodies before the handler reads them.
+// Every request or response carrying such fields is reported as a SensitiveAccess audit event.
+func SensitiveMiddleware(config SensitiveConfig) gin.HandlerFunc {
+	if config.KeyName == "" {
+		config.KeyName = DefaultFieldEncryptionKey
+	}
+	fields := &sensitiveFields{cipher: &secretCipher{keyName: config.KeyName}}
+	for _, rule := range config.Mask {
+		if segments, err := parseRedactionRule(rule); err == nil {
+			fields.mask = append(fields.mask, segments)
+		}
+	}
+	for _, rule := range config.Encrypt {
+		if segments, err := parseRedactionRule(rule); err == nil {
+			fields.encrypt = append(fields.encrypt, segments)
+		}
+	}
+
+	return func(c *gin.Context) {
+		if len(fields.encrypt) > 0 && c.Request.Body != nil && strings.Contains(c.ContentType(), "json") {
+			decrypted, err := fields.decryptRequest(c)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error":   "invalid_encrypted_field",
+					"message": err.Error(),
+				})
+				return
+			}
+			if len(decrypted) > 0 {
+				reportSensitiveAccess(c, "request", "decrypt", decrypted)
+			}
+		}
+
+		if len(fields.mask) == 0 && len(fields.encrypt) == 0 {
+			c.Next()
+			return
+		}
+
+		// The response is buffered (without a size limit) so fields can be rewritten before it is sent
+		original := c.Writer
+		writer := &sizeLimitBufferWriter{ResponseWriter: original, limit: math.MaxInt64, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.streaming {
+			LogNormal("⚠️  Streamed response of %s %s was sent without @Sensitive processing", c.Request.Method, getEndpointPattern(c))
+			return
+		}
+		if !writer.wroteHeader && writer.buffer.Len() == 0 {
+			return
+		}
+
+		body := writer.buffer.Bytes()
+		if strings.Contains(original.Header().Get("Content-Type"), "json") {
+			var err error
+			if body, err = fields.protectResponse(c, body); err != nil {
+				LogNormal("⚠️  @Sensitive on %s %s: %v", c.Request.Method, getEndpointPattern(c), err)
+				original.Header().Del("Content-Length")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":   "sensitive_field_protection_failed",
+					"message": "Response contains sensitive fields that could not be protected",
+				})
+				return
+			}
+			original.Header().Del("Content-Length")
+		}
+
+		original.WriteHeader(writer.status)
+		original.WriteHeaderNow()
+		if len(body) > 0 {
+			_, _ = original.Write(body)
+		}
+	}
+}
+
+// decryptRequest replaces encrypted values of the request body with their plaintext, returning their paths
+func (f *sensitiveFields) decryptRequest(c *gin.Context) ([]string, error) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	document, ok := decodeSensitiveJSON(data)
+	if !ok {
+		return nil, nil // the handler reports malformed bodies
+	}
+
+	var decrypted []string
+	var decryptErr error
+	document = walkSensitiveFields(document, nil, f.encrypt, func(path []string, value interface{}) interface{} {
+		text, isString := value.(string)
+		encoded, isEncrypted := strings.CutPrefix(text, sensitiveCiphertextPrefix)
+		if !isString || !isEncrypted || decryptErr != nil {
+			return value
+		}
+		plaintext, err := f.decrypt(c.Request.Context(), encoded, path[len(path)-1])
+		if err != nil {
+			decryptErr = fmt.Errorf("field %s: %v", formatSensitivePath(path), err)
+			return value
+		}
+		decrypted = append(decrypted, formatSensitivePath(path))
+		return plaintext
+	})
+	if decryptErr != nil {
+		return nil, decryptErr
+	}
+	if len(decrypted) == 0 {
+		return nil, nil
+	}
+
+	rewritten, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(rewritten))
+	c.Request.ContentLength = int64(len(rewritten))
+	c.Request.Header.Set("Content-Length", strconv.Itoa(len(rewritten)))
+	return decrypted, nil
+}
+
+// protectResponse masks and encrypts the configured fields of a JSON response body
+func (f *sensitiveFields) protectResponse(c *gin.Context, body []byte) ([]byte, error) {
+	document, ok := decodeSensitiveJSON(body)
+	if !ok {
+		return body, nil
+	}
+
+	var masked, encrypted []string
+	document = walkSensitiveFields(document, nil, f.mask, func(path []string, value interface{}) interface{} {
+		if value == nil {
+			return nil
+		}
+		masked = append(masked, formatSensitivePath(path))
+		return sensitiveMaskValue
+	})
+
+	var encryptErr error
+	document = walkSensitiveFields(document, nil, f.encrypt, func(path []string, value interface{}) interface{} {
+		if value == nil || encryptErr != nil {
+			return value
+		}
+		ciphertext, err := f.encryptValue(c.Request.Context(), value, path[len(path)-1])
+		if err != nil {
+			encryptErr = err
+			return value
+		}
+		encrypted = append(encrypted, formatSensitivePath(path))
+		return ciphertext
+	})
+	if encryptErr != nil {
+		return nil, encryptErr
+	}
+	if len(masked) == 0 && len(encrypted) == 0 {
+		return body, nil
+	}
+
+	if len(encrypted) > 0 {
+		reportSensitiveAccess(c, "response", "encrypt", encrypted)
+	}
+	if len(masked) > 0 {
+		reportSensitiveAccess(c, "response", "mask", masked)
+	}
+	return json.Marshal(document)
+}
+
+// encryptValue seals the JSON encoding of a value, bound to its field name
+func (f *sensitiveFields) encryptValue(ctx context.Context, value interface{}, field string) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := f.cipher.seal(ctx, plaintext, []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return sensitiveCiphertextPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens an encrypted field value, restoring its JSON type
+func (f *sensitiveFields) decrypt(ctx context.Context, encoded, field string) (interface{}, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext encoding")
+	}
+	plaintext, err := f.cipher.open(ctx, sealed, []byte(field))
+	if err != nil {
+		return nil, err
+	}
+	value, ok := decodeSensitiveJSON(plaintext)
+	if !ok {
+		return nil, fmt.Errorf("invalid plaintext")
+	}
+	return value, nil
+}
+
+// decodeSensitiveJSON decodes a JSON document keeping numbers exact
+func decodeSensitiveJSON(data []byte) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, false
+	}
+	return document, true
+}
+
+// walkSensitiveFields replaces the values selected by rules with the result of transform
+func walkSensitiveFields(value interface{}, path []string, rules [][]string, transform func(path []string, value interface{}) interface{}) interface{} {
+	matches := func(childPath []string) bool {
+		for _, rule := range rules {
+			if matchRedactionPath(rule, childPath) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			childPath := append(path[:len(path):len(path)], key)
+			if matches(childPath) {
+				typed[key] = transform(childPath, child)
+				continue
+			}
+			typed[key] = walkSensitiveFields(child, childPath, rules, transform)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if matches(childPath) {
+				typed[i] = transform(childPath, child)
+				continue
+			}
+			typed[i] = walkSensitiveFields(child, childPath, rules, transform)
+		}
+	}
+	return value
+}
+
+// formatSensitivePath formats a concrete path as "$.cards.0.number"
+func formatSensitivePath(path []string) string {
+	return "$." + strings.Join(path, ".")
+}
+
+// reportSensitiveAccess logs and dispatches an audit event
+func reportSensitiveAccess(c *gin.Context, direction, action string, fields []string) {
+	sort.Strings(fields)
+	event := SensitiveAccess{
+		Event:     "sensitive_access",
+		Direction: direction,
+		Action:    action,
+		Method:    c.Request.Method,
+		Endpoint:  getEndpointPattern(c),
+		Fields:    fields,
+		UserID:    GetPIIPolicy().ScrubField("user_id", c.GetString("user_id")),
+		RequestID: c.GetHeader("X-Request-ID"),
+		Timestamp: time.Now(),
+	}
+	if data, err := json.Marshal(event); err == nil {
+		LogNormal("🔐 %s", data)
+	}
+
+	sensitiveAuditHandlerMux.RLock()
+	handler := sensitiveAuditHandler
+	sensitiveAuditHandlerMux.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// createSensitiveMiddleware creates the @Sensitive middleware from its (generated) arguments
+func createSensitiveMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseSensitiveArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	return SensitiveMiddleware(config)
+}
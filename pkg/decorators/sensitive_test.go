@@ -0,0 +1,132 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSensitiveArgs(t *testing.T) {
+	config, err := parseSensitiveArgs([]string{"mask=email|phone", "encrypt=$.card.number", "key=pii-key"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "phone"}, config.Mask)
+	assert.Equal(t, []string{"$.card.number"}, config.Encrypt)
+	assert.Equal(t, "pii-key", config.KeyName)
+
+	config, err = parseSensitiveArgs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, config.Mask)
+
+	for _, args := range [][]string{{"email"}, {"hide=email"}, {"mask=$"}} {
+		_, err := parseSensitiveArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+	assert.Error(t, validateArgumentValues("Sensitive", []string{"hide=email"}))
+}
+
+func TestSensitiveMarker_SchemaRules(t *testing.T) {
+	ClearSchemas()
+	defer ClearSchemas()
+	registerEntitySchemas([]*EntityMeta{
+		{Name: "Customer", Fields: []FieldMeta{
+			{Name: "Name", Type: "string", JSONTag: "name"},
+			{Name: "Email", Type: "string", JSONTag: "email", Sensitive: SensitiveMask},
+			{Name: "SSN", Type: "string", JSONTag: "ssn", Sensitive: SensitiveEncrypt},
+			{Name: "Card", Type: "*Card", JSONTag: "card"},
+		}},
+		{Name: "Card", Fields: []FieldMeta{{Name: "Number", Type: "string", JSONTag: "number", Sensitive: SensitiveEncrypt}}},
+	})
+
+	mask, encrypt := sensitiveSchemaRules("[]models.Customer", "$", make(map[string]bool))
+	assert.Equal(t, []string{"$.*.email"}, mask)
+	assert.Equal(t, []string{"$.*.card.number", "$.*.ssn"}, encrypt)
+
+	route := &RouteMeta{Method: "POST", Path: "/customers", FuncName: "CreateCustomer", Markers: []MarkerInstance{
+		{Name: "Sensitive", Args: []string{"mask=phone"}},
+		{Name: "Param", Args: []string{"name=customer", "type=Customer", "location=body"}},
+		{Name: "Response", Args: []string{"code=201", "description=Created", "type=Customer"}},
+	}}
+	require.NoError(t, processMiddlewares(route))
+	require.Len(t, route.MiddlewareCalls, 1)
+	assert.Equal(t, `deco.CreateSensitiveMiddleware("mask=phone|$.email,encrypt=$.card.number|$.ssn")`, route.MiddlewareCalls[0])
+	assert.Equal(t, "Sensitive", route.MiddlewareInfo[0].Name)
+
+	schema := convertSchemaInfoToOpenAPISchema(GetSchema("Customer"))
+	assert.Equal(t, SensitiveEncrypt, schema.Properties["ssn"].Sensitive)
+}
+
+func TestSensitiveMiddleware(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{DefaultFieldEncryptionKey: []byte("0123456789abcdef")})
+	defer UseSecretProvider(nil)
+	var events []SensitiveAccess
+	SetSensitiveAuditHandler(func(event SensitiveAccess) { events = append(events, event) })
+	defer SetSensitiveAuditHandler(nil)
+
+	var received map[string]interface{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	sensitive := createSensitiveMiddleware([]string{"mask=$.email", "encrypt=$.ssn|$.card.number"})
+	router.GET("/customers/1", sensitive, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "Ana", "email": "ana@example.com", "ssn": "123-45-6789", "card": gin.H{"number": 4111}})
+	})
+	router.PUT("/customers/1", sensitive, func(c *gin.Context) {
+		require.NoError(t, c.ShouldBindJSON(&received))
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers/1", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Ana", body["name"])
+	assert.Equal(t, sensitiveMaskValue, body["email"])
+	ssn := body["ssn"].(string)
+	assert.True(t, strings.HasPrefix(ssn, sensitiveCiphertextPrefix))
+	assert.NotContains(t, w.Body.String(), "123-45-6789")
+
+	// Encrypted values sent back are decrypted before the handler binds them, keeping their JSON type
+	card := body["card"].(map[string]interface{})["number"].(string)
+	request := `{"name":"Ana","ssn":"` + ssn + `","card":{"number":"` + card + `"}}`
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/customers/1", strings.NewReader(request))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "123-45-6789", received["ssn"])
+	assert.Equal(t, float64(4111), received["card"].(map[string]interface{})["number"])
+
+	// A ciphertext moved to another field does not decrypt
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPut, "/customers/1", strings.NewReader(`{"ssn":"`+card+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_encrypted_field")
+
+	require.Len(t, events, 3)
+	assert.Equal(t, []string{"response", "encrypt"}, []string{events[0].Direction, events[0].Action})
+	assert.Equal(t, []string{"$.card.number", "$.ssn"}, events[0].Fields)
+	assert.Equal(t, []string{"response", "mask"}, []string{events[1].Direction, events[1].Action})
+	assert.Equal(t, []string{"request", "decrypt"}, []string{events[2].Direction, events[2].Action})
+	assert.Equal(t, "/customers/1", events[2].Endpoint)
+}
+
+func TestSensitiveMiddleware_MissingKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/customers/1", createSensitiveMiddleware([]string{"encrypt=ssn"}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ssn": "123-45-6789"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers/1", http.NoBody))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "123-45-6789")
+}
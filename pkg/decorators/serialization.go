@@ -0,0 +1,169 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Names of the JSON engines of serialization.engine; only stdlib is built in
+const (
+	JSONEngineStdlib   = "stdlib"
+	JSONEngineSonic    = "sonic"
+	JSONEngineJsoniter = "jsoniter"
+)
+
+// streamJSONFlushEvery items written by StreamJSON between flushes
+const streamJSONFlushEvery = 100
+
+// JSONEngine encoder and decoder of the JSON handled by the framework: request bodies of @ValidateJSON,
+// cache entries and the RenderJSON and StreamJSON helpers. Engines must produce the same JSON as
+// encoding/json, as cache entries are shared between instances.
+type JSONEngine struct {
+	Marshal   func(v interface{}) ([]byte, error)
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+var (
+	jsonEngines = map[string]JSONEngine{
+		JSONEngineStdlib: {Marshal: json.Marshal, Unmarshal: json.Unmarshal},
+	}
+	jsonEnginesMux sync.RWMutex
+
+	serializationEngine atomic.Pointer[JSONEngine]
+)
+
+// RegisterJSONEngine registers an engine usable as serialization.engine, e.g. sonic:
+//
+//	deco.RegisterJSONEngine(deco.JSONEngineSonic, deco.JSONEngine{Marshal: sonic.Marshal, Unmarshal: sonic.Unmarshal})
+func RegisterJSONEngine(name string, engine JSONEngine) {
+	jsonEnginesMux.Lock()
+	defer jsonEnginesMux.Unlock()
+	jsonEngines[name] = engine
+}
+
+// getJSONEngine returns the engine registered under name
+func getJSONEngine(name string) (JSONEngine, bool) {
+	jsonEnginesMux.RLock()
+	defer jsonEnginesMux.RUnlock()
+	engine, exists := jsonEngines[name]
+	return engine, exists
+}
+
+// ConfigureSerialization selects the JSON engine of serialization.engine, which must be registered
+// before the engine is created
+func ConfigureSerialization(config SerializationConfig) error {
+	name := config.Engine
+	if name == "" {
+		name = JSONEngineStdlib
+	}
+	engine, exists := getJSONEngine(name)
+	if !exists {
+		jsonEnginesMux.RLock()
+		names := make([]string, 0, len(jsonEngines))
+		for registered := range jsonEngines {
+			names = append(names, registered)
+		}
+		jsonEnginesMux.RUnlock()
+		sort.Strings(names)
+		return fmt.Errorf("serialization.engine '%s' is not registered (registered: %s); register it with RegisterJSONEngine", name, strings.Join(names, ", "))
+	}
+	if engine.Marshal == nil || engine.Unmarshal == nil {
+		return fmt.Errorf("serialization.engine '%s' needs Marshal and Unmarshal", name)
+	}
+	serializationEngine.Store(&engine)
+	return nil
+}
+
+// jsonEngine engine of ConfigureSerialization, or encoding/json
+func jsonEngine() JSONEngine {
+	if engine := serializationEngine.Load(); engine != nil {
+		return *engine
+	}
+	return jsonEngines[JSONEngineStdlib]
+}
+
+// jsonMarshal encodes v with the configured engine
+func jsonMarshal(v interface{}) ([]byte, error) {
+	return jsonEngine().Marshal(v)
+}
+
+// jsonUnmarshal decodes data into v with the configured engine
+func jsonUnmarshal(data []byte, v interface{}) error {
+	return jsonEngine().Unmarshal(data, v)
+}
+
+// bindJSONBody decodes the request body into v with the configured engine
+func bindJSONBody(c *gin.Context, v interface{}) error {
+	if c.Request == nil || c.Request.Body == nil {
+		return fmt.Errorf("invalid request")
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	return jsonUnmarshal(body, v)
+}
+
+// RenderJSON writes value as the JSON response with the configured engine; a value the engine cannot
+// encode aborts with 500 and the error attached to the context
+func RenderJSON(c *gin.Context, status int, value interface{}) {
+	data, err := jsonMarshal(value)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", data)
+}
+
+// StreamJSON writes the items as a JSON array one at a time, flushing as it goes, so a large list is
+// never held in memory whole. The status is sent with the first item: an error before it is returned
+// with nothing written, so the handler can still answer; an error after it ends the response, with the
+// array left unterminated so clients do not take the partial list for a complete one.
+func StreamJSON[T any](c *gin.Context, status int, items iter.Seq2[T, error]) error {
+	engine := jsonEngine()
+	written := 0
+	for item, err := range items {
+		if err != nil {
+			return err
+		}
+		data, err := engine.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		separator := ","
+		if written == 0 {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(status)
+			separator = "["
+		}
+		if _, err := c.Writer.WriteString(separator); err != nil {
+			return err
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		written++
+		// The first item is flushed at once, so the response is known to be streamed (@Envelope, @Compress)
+		if written == 1 || written%streamJSONFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+
+	if written == 0 {
+		c.Data(status, "application/json; charset=utf-8", []byte("[]"))
+		return nil
+	}
+	_, err := c.Writer.WriteString("]")
+	return err
+}
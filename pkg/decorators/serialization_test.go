@@ -0,0 +1,134 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJSONEngine encoding/json counting its calls
+func countingJSONEngine(marshals, unmarshals *int) JSONEngine {
+	return JSONEngine{
+		Marshal: func(v interface{}) ([]byte, error) {
+			*marshals++
+			return json.Marshal(v)
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			*unmarshals++
+			return json.Unmarshal(data, v)
+		},
+	}
+}
+
+func TestConfigureSerialization(t *testing.T) {
+	defer func() { require.NoError(t, ConfigureSerialization(SerializationConfig{})) }()
+
+	err := ConfigureSerialization(SerializationConfig{Engine: JSONEngineSonic})
+	assert.ErrorContains(t, err, "serialization.engine 'sonic' is not registered (registered: stdlib")
+
+	RegisterJSONEngine("incomplete", JSONEngine{Marshal: json.Marshal})
+	assert.ErrorContains(t, ConfigureSerialization(SerializationConfig{Engine: "incomplete"}), "needs Marshal and Unmarshal")
+
+	var marshals, unmarshals int
+	RegisterJSONEngine("counting", countingJSONEngine(&marshals, &unmarshals))
+	require.NoError(t, ConfigureSerialization(SerializationConfig{Engine: "counting"}))
+
+	// Cache entries and validated bodies go through the engine
+	UseSecretProvider(staticSecretProvider{"cache-key": []byte("0123456789abcdef0123456789abcdef")})
+	defer UseSecretProvider(nil)
+	store := NewEncryptedCacheStore(NewMemoryCache(10), "cache-key")
+	require.NoError(t, store.Set(context.Background(), "/users/1", &CacheEntry{Data: []byte("value"), Status: 200}, time.Minute))
+	entry, err := store.Get(context.Background(), "/users/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), entry.Data)
+	assert.Equal(t, 1, marshals)
+	assert.Equal(t, 1, unmarshals)
+
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", ValidateJSON(request{}, &ValidationConfig{}), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ana"}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Positive(t, unmarshals)
+
+	before := marshals
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RenderJSON(c, http.StatusOK, gin.H{"ok": true})
+	assert.Equal(t, before+1, marshals)
+	assert.JSONEq(t, `{"ok": true}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestRenderJSON_Unencodable(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RenderJSON(c, http.StatusOK, make(chan int))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Len(t, c.Errors, 1)
+}
+
+// seqOf yields items, then err when given
+func seqOf[T any](items []T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+func TestStreamJSON(t *testing.T) {
+	type user struct {
+		ID int `json:"id"`
+	}
+	users := make([]user, 250)
+	for i := range users {
+		users[i] = user{ID: i}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	require.NoError(t, StreamJSON(c, http.StatusOK, seqOf(users, nil)))
+	var decoded []user
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+	assert.Equal(t, users, decoded)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	require.NoError(t, StreamJSON(c, http.StatusOK, seqOf([]user{}, nil)))
+	assert.Equal(t, "[]", w.Body.String())
+
+	// An error before the first item leaves the response to the handler
+	failure := errors.New("database unavailable")
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	assert.ErrorIs(t, StreamJSON(c, http.StatusOK, seqOf([]user{}, failure)), failure)
+	assert.False(t, c.Writer.Written())
+
+	// After it, the array is left open
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	assert.ErrorIs(t, StreamJSON(c, http.StatusOK, seqOf(users[:2], failure)), failure)
+	assert.Equal(t, `[{"id":0},{"id":1}`, w.Body.String())
+}
@@ -0,0 +1,172 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlowRequestEvent structured event logged when a request exceeds its threshold
+type SlowRequestEvent struct {
+	Event      string    `json:"event"`
+	Method     string    `json:"method"`
+	Endpoint   string    `json:"endpoint"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	DurationMS float64   `json:"duration_ms"`
+	Threshold  string    `json:"threshold"`
+	TraceID    string    `json:"trace_id,omitempty"`
+	SpanID     string    `json:"span_id,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+var (
+	slowRequestsTotal     *prometheus.CounterVec
+	slowRequestsOnce      sync.Once
+	slowRequestHandler    func(SlowRequestEvent)
+	slowRequestHandlerMux sync.RWMutex
+)
+
+// slowRequestsCounter returns the deco_slow_requests_total counter, registering it on first use
+func slowRequestsCounter() *prometheus.CounterVec {
+	slowRequestsOnce.Do(func() {
+		counter := prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "deco_slow_requests_total",
+				Help: "Total number of requests slower than their configured threshold",
+			},
+			[]string{"method", "endpoint"},
+		)
+		if err := prometheus.Register(counter); err != nil {
+			if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				counter = are.ExistingCollector.(*prometheus.CounterVec)
+			}
+		}
+		slowRequestsTotal = counter
+	})
+	return slowRequestsTotal
+}
+
+// SetSlowRequestHandler registers a callback for slow request events (nil restores logging only)
+func SetSlowRequestHandler(handler func(SlowRequestEvent)) {
+	slowRequestHandlerMux.Lock()
+	defer slowRequestHandlerMux.Unlock()
+	slowRequestHandler = handler
+}
+
+// SlowThresholdMiddleware flags requests slower than threshold
+func SlowThresholdMiddleware(threshold time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if threshold <= 0 {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+
+		if duration < threshold {
+			return
+		}
+
+		event := SlowRequestEvent{
+			Event:      "slow_request",
+			Method:     c.Request.Method,
+			Endpoint:   getEndpointPattern(c),
+			Path:       c.Request.URL.Path,
+			Status:     c.Writer.Status(),
+			DurationMS: float64(duration.Microseconds()) / 1000,
+			Threshold:  threshold.String(),
+			RequestID:  c.GetHeader("X-Request-ID"),
+			Timestamp:  start,
+		}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			event.TraceID = spanContext.TraceID().String()
+			event.SpanID = spanContext.SpanID().String()
+		}
+
+		reportSlowRequest(event)
+	}
+}
+
+// reportSlowRequest counts, logs and dispatches a slow request event
+func reportSlowRequest(event SlowRequestEvent) {
+	slowRequestsCounter().WithLabelValues(event.Method, event.Endpoint).Inc()
+	event.Path = GetPIIPolicy().ScrubURL(event.Path)
+
+	if data, err := json.Marshal(event); err == nil {
+		LogNormal("🐢 %s", data)
+	}
+
+	slowRequestHandlerMux.RLock()
+	handler := slowRequestHandler
+	slowRequestHandlerMux.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// parseSlowThresholdArgs parses @SlowThreshold arguments: a positional duration ("800ms") or threshold=
+func parseSlowThresholdArgs(args []string) (time.Duration, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("@SlowThreshold requires exactly 1 argument (duration), found %d", len(args))
+	}
+
+	value := strings.Trim(strings.TrimPrefix(strings.TrimSpace(args[0]), "threshold="), `"'`)
+	threshold, err := ParseDurationLiteral(value)
+	if err != nil || threshold <= 0 {
+		return 0, fmt.Errorf("@SlowThreshold requires a positive duration such as \"800ms\", found '%s'", value)
+	}
+
+	return threshold, nil
+}
+
+// createSlowThresholdMiddleware creates slow request detection middleware
+// Invalid arguments are rejected during generation; here they only disable detection
+func createSlowThresholdMiddleware(args []string) gin.HandlerFunc {
+	threshold, err := parseSlowThresholdArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+	}
+	return SlowThresholdMiddleware(threshold)
+}
+
+// applySlowThresholdDefault adds the global slow threshold to routes without @SlowThreshold
+func applySlowThresholdDefault(routes []*RouteMeta, threshold string) {
+	if threshold == "" {
+		return
+	}
+
+	for _, route := range routes {
+		if route.Method == "" || route.Path == "" || hasMarker(route, "SlowThreshold") {
+			continue
+		}
+		marker := MarkerInstance{Name: "SlowThreshold", Args: []string{threshold}}
+		route.MiddlewareCalls = append([]string{generateMiddlewareCall(marker)}, route.MiddlewareCalls...)
+		// MiddlewareInfo stays aligned with the chain in order: @Provide entries, then the middleware calls
+		providers := min(len(route.Providers), len(route.MiddlewareInfo))
+		route.MiddlewareInfo = append(route.MiddlewareInfo[:providers:providers], append([]MiddlewareInfo{{
+			Name:        marker.Name,
+			Args:        parseArgsToMap(marker.Args),
+			Description: getMiddlewareDescription(marker.Name),
+		}}, route.MiddlewareInfo[providers:]...)...)
+	}
+}
+
+// hasMarker reports whether a route declares the named marker
+func hasMarker(route *RouteMeta, name string) bool {
+	for _, marker := range route.Markers {
+		if marker.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlowRouter(middleware gin.HandlerFunc, delay time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/reports/:id", middleware, func(c *gin.Context) {
+		time.Sleep(delay)
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestSlowThresholdMiddleware(t *testing.T) {
+	var events []SlowRequestEvent
+	SetSlowRequestHandler(func(event SlowRequestEvent) { events = append(events, event) })
+	defer SetSlowRequestHandler(nil)
+
+	counter := slowRequestsCounter().WithLabelValues(http.MethodGet, "/reports/:id")
+	before := testutil.ToFloat64(counter)
+
+	// Fast request is ignored
+	router := newSlowRouter(SlowThresholdMiddleware(time.Second), 0)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/1", http.NoBody))
+	assert.Empty(t, events)
+
+	// Slow request is reported
+	router = newSlowRouter(SlowThresholdMiddleware(time.Millisecond), 5*time.Millisecond)
+	req := httptest.NewRequest(http.MethodGet, "/reports/1", http.NoBody)
+	req.Header.Set("X-Request-ID", "req-123")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if assert.Len(t, events, 1) {
+		event := events[0]
+		assert.Equal(t, "slow_request", event.Event)
+		assert.Equal(t, "/reports/:id", event.Endpoint)
+		assert.Equal(t, "/reports/1", event.Path)
+		assert.Equal(t, http.StatusOK, event.Status)
+		assert.Equal(t, "1ms", event.Threshold)
+		assert.Equal(t, "req-123", event.RequestID)
+		assert.GreaterOrEqual(t, event.DurationMS, 5.0)
+	}
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestCreateSlowThresholdMiddleware(t *testing.T) {
+	var count int
+	SetSlowRequestHandler(func(SlowRequestEvent) { count++ })
+	defer SetSlowRequestHandler(nil)
+
+	router := newSlowRouter(CreateSlowThresholdMiddleware(`"1ms"`), 3*time.Millisecond)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/1", http.NoBody))
+	assert.Equal(t, 1, count)
+
+	// Invalid durations disable detection instead of failing
+	router = newSlowRouter(CreateSlowThresholdMiddleware("threshold=soon"), 3*time.Millisecond)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/1", http.NoBody))
+	assert.Equal(t, 1, count)
+}
+
+func TestApplySlowThresholdDefault(t *testing.T) {
+	routes := []*RouteMeta{
+		{Method: "GET", Path: "/a", MiddlewareCalls: []string{`deco.CreateAuthMiddleware("")`}},
+		{
+			Method: "GET", Path: "/b",
+			Markers:         []MarkerInstance{{Name: "SlowThreshold", Args: []string{"2s"}}},
+			MiddlewareCalls: []string{`deco.CreateSlowThresholdMiddleware("2s")`},
+		},
+	}
+
+	applySlowThresholdDefault(routes, "")
+	assert.Len(t, routes[0].MiddlewareCalls, 1)
+
+	applySlowThresholdDefault(routes, "500ms")
+	assert.Equal(t, []string{
+		`deco.SlowThresholdMiddleware(500 * time.Millisecond)`,
+		`deco.CreateAuthMiddleware("")`,
+	}, routes[0].MiddlewareCalls)
+	assert.Len(t, routes[0].MiddlewareInfo, 1)
+	assert.Equal(t, []string{`deco.CreateSlowThresholdMiddleware("2s")`}, routes[1].MiddlewareCalls)
+}
+
+func TestGenerateFromTemplateSlowThresholdDefault(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	sourceDir := t.TempDir()
+	source := "package handlers\n\nimport \"github.com/gin-gonic/gin\"\n\n// @Route(\"GET\", \"/reports\")\nfunc ListReports(c *gin.Context) {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "reports.go"), []byte(source), 0o600))
+	templatePath := filepath.Join(t.TempDir(), "custom.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{ range .Routes }}{{ range .MiddlewareCalls }}{{ . }}\n{{ end }}{{ end }}"), 0o600))
+
+	config := DefaultConfig()
+	config.Generate.DisableCache = true
+	config.Metrics.SlowThreshold = "2s"
+	outputPath := filepath.Join(t.TempDir(), "out.go")
+	require.NoError(t, GenerateFromTemplateWithConfig(sourceDir, templatePath, outputPath, "deco", config))
+
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "deco.SlowThresholdMiddleware(2 * time.Second)\n", string(generated))
+}
+
+func TestConfigValidateSlowThreshold(t *testing.T) {
+	config := DefaultConfig()
+	config.Metrics.SlowThreshold = "800ms"
+	assert.NoError(t, config.Validate())
+
+	config.Metrics.SlowThreshold = "slow"
+	assert.Error(t, config.Validate())
+}
+
+func TestSlowThresholdArgumentValidation(t *testing.T) {
+	args, err := parseArgumentsWithValidation(`"800ms"`, "SlowThreshold")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"800ms"}, args)
+
+	_, err = parseArgumentsWithValidation(`threshold="1s"`, "SlowThreshold")
+	assert.NoError(t, err)
+
+	_, err = parseArgumentsWithValidation(`"soon"`, "SlowThreshold")
+	assert.Error(t, err)
+
+	_, err = parseArgumentsWithValidation(`"-1s"`, "SlowThreshold")
+	assert.Error(t, err)
+}
+
+func TestApplyDefaultsKeepsSlowThreshold(t *testing.T) {
+	config := &Config{Metrics: MetricsConfig{SlowThreshold: "750ms"}}
+	applyDefaults(config)
+
+	assert.Equal(t, "750ms", config.Metrics.SlowThreshold)
+	assert.Equal(t, DefaultConfig().Metrics.Endpoint, config.Metrics.Endpoint)
+	assert.Equal(t, DefaultConfig().Metrics.Namespace, config.Metrics.Namespace)
+}
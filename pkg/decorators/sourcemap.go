@@ -0,0 +1,265 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceMapFileName is the name of the source map written next to the generated file
+const SourceMapFileName = "sourcemap.json"
+
+// SourceMap maps regions of the generated file back to the handler source
+type SourceMap struct {
+	Version   int              `json:"version"`
+	Generated string           `json:"generated"`
+	Entries   []SourceMapEntry `json:"entries"`
+}
+
+// SourceMapEntry describes one generated registration block
+type SourceMapEntry struct {
+	Generated string            `json:"generated,omitempty"` // shard holding the block (generation.split), empty for the generated file
+	StartLine int               `json:"start_line"`
+	EndLine   int               `json:"end_line"`
+	FuncName  string            `json:"func_name"`
+	Method    string            `json:"method,omitempty"`
+	Path      string            `json:"path,omitempty"`
+	File      string            `json:"file"`
+	Line      int               `json:"line"`
+	Decorator string            `json:"decorator,omitempty"`
+	Markers   []SourceMapMarker `json:"markers,omitempty"`
+}
+
+// SourceMapMarker links a decorator to the generated line it produced
+type SourceMapMarker struct {
+	Name          string `json:"name"`
+	Raw           string `json:"raw"`
+	Line          int    `json:"line"`
+	GeneratedLine int    `json:"generated_line,omitempty"`
+}
+
+// SourceMapPath returns the source map location for a generated file
+func SourceMapPath(outputPath string) string {
+	return filepath.Join(filepath.Dir(outputPath), SourceMapFileName)
+}
+
+// BuildSourceMap builds the source map by locating each route's registration block in the generated code
+func BuildSourceMap(generated string, routes []*RouteMeta) *SourceMap {
+	sm := &SourceMap{Version: 1}
+	lines := strings.Split(generated, "\n")
+	used := make(map[*RouteMeta]bool)
+
+	for i := 0; i < len(lines); i++ {
+		if !strings.Contains(lines[i], "RegisterRouteWithMeta(") {
+			continue
+		}
+
+		start := blockStart(lines, i)
+		end := blockEnd(lines, i)
+		block := lines[start : end+1]
+
+		route := findRouteForBlock(block, routes, used)
+		if route == nil {
+			i = end
+			continue
+		}
+		used[route] = true
+
+		entry := SourceMapEntry{
+			StartLine: start + 1,
+			EndLine:   end + 1,
+			FuncName:  route.FuncName,
+			Method:    route.Method,
+			Path:      route.Path,
+			File:      sourceFilePath(route),
+			Line:      route.Line,
+		}
+
+		for _, marker := range route.Markers {
+			smMarker := SourceMapMarker{Name: marker.Name, Raw: marker.Raw, Line: marker.Line}
+			if call := generateMiddlewareCall(marker); call != "" {
+				for j, line := range block {
+					if strings.Contains(line, call) {
+						smMarker.GeneratedLine = start + j + 1
+						break
+					}
+				}
+			}
+			entry.Markers = append(entry.Markers, smMarker)
+		}
+
+		if route.Method != "" {
+			entry.Decorator = fmt.Sprintf("@Route(%q, %q)", route.Method, route.Path)
+		}
+
+		sm.Entries = append(sm.Entries, entry)
+		i = end
+	}
+
+	return sm
+}
+
+// blockStart walks back over the comments and WebSocket registrations preceding a block
+func blockStart(lines []string, i int) int {
+	start := i
+	for start > 0 {
+		prev := strings.TrimSpace(lines[start-1])
+		if strings.HasPrefix(prev, "//") || strings.Contains(prev, "RegisterWebSocketHandler(") ||
+			(prev == "" && start-2 >= 0 && strings.Contains(lines[start-2], "RegisterWebSocketHandler(")) {
+			start--
+			continue
+		}
+		break
+	}
+	return start
+}
+
+// blockEnd finds the line closing a registration call by balancing braces and parentheses
+func blockEnd(lines []string, i int) int {
+	depth := 0
+	for j := i; j < len(lines); j++ {
+		depth += bracketDelta(lines[j])
+		if depth <= 0 {
+			return j
+		}
+	}
+	return len(lines) - 1
+}
+
+// bracketDelta returns the net bracket depth change of a line, ignoring string literals
+func bracketDelta(line string) int {
+	delta := 0
+	inString := false
+	for k := 0; k < len(line); k++ {
+		switch ch := line[k]; {
+		case inString && ch == '\\':
+			k++
+		case ch == '"':
+			inString = !inString
+		case inString:
+		case ch == '(' || ch == '{':
+			delta++
+		case ch == ')' || ch == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+// findRouteForBlock identifies which route produced a generated block
+func findRouteForBlock(block []string, routes []*RouteMeta, used map[*RouteMeta]bool) *RouteMeta {
+	text := strings.Join(block, "\n")
+	var candidate *RouteMeta
+	for _, route := range routes {
+		if used[route] || !strings.Contains(text, fmt.Sprintf("%q", route.FuncName)) {
+			continue
+		}
+		// Handlers bound to several methods share the path of their blocks
+		if route.Path != "" && strings.Contains(text, fmt.Sprintf("%q", route.Path)) && strings.Contains(text, fmt.Sprintf("%q", route.Method)) {
+			return route
+		}
+		if candidate == nil {
+			candidate = route
+		}
+	}
+	return candidate
+}
+
+// sourceFilePath returns the best known path of the route's source file
+func sourceFilePath(route *RouteMeta) string {
+	if route.FilePath != "" {
+		return route.FilePath
+	}
+	return route.FileName
+}
+
+// WriteSourceMap reads the generated file and writes its source map alongside it
+func WriteSourceMap(outputPath string, routes []*RouteMeta) error {
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("error reading generated file %s: %v", outputPath, err)
+	}
+
+	sm := BuildSourceMap(string(content), routes)
+	sm.Generated = outputPath
+	for _, shard := range GeneratedFiles(outputPath)[1:] {
+		content, err := os.ReadFile(shard)
+		if err != nil {
+			return fmt.Errorf("error reading generated file %s: %v", shard, err)
+		}
+		for _, entry := range BuildSourceMap(string(content), routes).Entries {
+			entry.Generated = filepath.Base(shard)
+			sm.Entries = append(sm.Entries, entry)
+		}
+	}
+
+	data, err := json.MarshalIndent(sm, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing source map: %v", err)
+	}
+
+	if err := os.WriteFile(SourceMapPath(outputPath), data, 0o600); err != nil {
+		return fmt.Errorf("error writing source map: %v", err)
+	}
+
+	return nil
+}
+
+// LoadSourceMap loads a source map from disk
+func LoadSourceMap(path string) (*SourceMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading source map %s: %v", path, err)
+	}
+
+	var sm SourceMap
+	if err := json.Unmarshal(data, &sm); err != nil {
+		return nil, fmt.Errorf("error parsing source map %s: %v", path, err)
+	}
+
+	return &sm, nil
+}
+
+// Lookup returns the entry covering a generated line and, when known, the marker that produced it
+func (sm *SourceMap) Lookup(generatedLine int) (*SourceMapEntry, *SourceMapMarker) {
+	return sm.LookupIn("", generatedLine)
+}
+
+// LookupIn is Lookup for a line of a shard (the base name of the file), or of the generated file when empty
+func (sm *SourceMap) LookupIn(generated string, generatedLine int) (*SourceMapEntry, *SourceMapMarker) {
+	for i := range sm.Entries {
+		entry := &sm.Entries[i]
+		if entry.Generated != generated || generatedLine < entry.StartLine || generatedLine > entry.EndLine {
+			continue
+		}
+		for j := range entry.Markers {
+			if entry.Markers[j].GeneratedLine == generatedLine {
+				return entry, &entry.Markers[j]
+			}
+		}
+		return entry, nil
+	}
+	return nil, nil
+}
+
+// Describe formats the source location of a generated line for error messages
+func (sm *SourceMap) Describe(generatedLine int) string {
+	return sm.DescribeIn("", generatedLine)
+}
+
+// DescribeIn is Describe for a line of a shard, or of the generated file when generated is empty
+func (sm *SourceMap) DescribeIn(generated string, generatedLine int) string {
+	entry, marker := sm.LookupIn(generated, generatedLine)
+	if entry == nil {
+		return ""
+	}
+	if marker != nil {
+		return fmt.Sprintf("%s:%d: %s (handler %s)", entry.File, marker.Line, marker.Raw, entry.FuncName)
+	}
+	if entry.Decorator != "" {
+		return fmt.Sprintf("%s:%d: %s (handler %s)", entry.File, entry.Line, entry.Decorator, entry.FuncName)
+	}
+	return fmt.Sprintf("%s:%d: handler %s", entry.File, entry.Line, entry.FuncName)
+}
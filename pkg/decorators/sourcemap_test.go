@@ -0,0 +1,186 @@
+package decorators
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSourceMap(t *testing.T) {
+	routes := []*RouteMeta{
+		{
+			Method:   "GET",
+			Path:     "/users",
+			FuncName: "ListUsers",
+			FilePath: "handlers/users.go",
+			Line:     10,
+			Markers: []MarkerInstance{
+				{Name: "Auth", Args: []string{`role="admin"`}, Raw: `@Auth(role="admin")`, Line: 11},
+				{Name: "Summary", Args: []string{`"List (all) users"`}, Raw: `@Summary("List (all) users")`, Line: 12},
+			},
+		},
+		{
+			Method:   "POST",
+			Path:     "/users",
+			FuncName: "CreateUser",
+			FileName: "users.go",
+			Line:     20,
+		},
+	}
+
+	generated := strings.Join([]string{
+		"func init() {",
+		"	// GET /users -> ListUsers",
+		"	decorators.RegisterRouteWithMeta(&decorators.RouteEntry{",
+		`		Method:      "GET",`,
+		`		Path:        "/users",`,
+		"		Middlewares: []gin.HandlerFunc{",
+		`			deco.CreateAuthMiddleware("role=\"admin\""),`,
+		"		},",
+		`		FuncName:    "ListUsers",`,
+		`		Summary:     "List (all users",`,
+		"	})",
+		"	// POST /users -> CreateUser",
+		"	decorators.RegisterRouteWithMeta(&decorators.RouteEntry{",
+		`		Method:      "POST",`,
+		`		Path:        "/users",`,
+		`		FuncName:    "CreateUser",`,
+		"	})",
+		"}",
+	}, "\n")
+
+	sm := BuildSourceMap(generated, routes)
+	assert.Len(t, sm.Entries, 2)
+
+	first := sm.Entries[0]
+	assert.Equal(t, "ListUsers", first.FuncName)
+	assert.Equal(t, 2, first.StartLine)
+	assert.Equal(t, 11, first.EndLine)
+	assert.Equal(t, "handlers/users.go", first.File)
+	assert.Equal(t, `@Route("GET", "/users")`, first.Decorator)
+	assert.Len(t, first.Markers, 2)
+	assert.Equal(t, 7, first.Markers[0].GeneratedLine)
+	assert.Equal(t, 0, first.Markers[1].GeneratedLine)
+
+	second := sm.Entries[1]
+	assert.Equal(t, "CreateUser", second.FuncName)
+	assert.Equal(t, 12, second.StartLine)
+	assert.Equal(t, 17, second.EndLine)
+	assert.Equal(t, "users.go", second.File)
+}
+
+func TestSourceMapLookup(t *testing.T) {
+	sm := &SourceMap{
+		Entries: []SourceMapEntry{
+			{
+				StartLine: 5, EndLine: 15, FuncName: "ListUsers", File: "users.go", Line: 10,
+				Decorator: `@Route("GET", "/users")`,
+				Markers:   []SourceMapMarker{{Name: "Auth", Raw: `@Auth(role="admin")`, Line: 11, GeneratedLine: 8}},
+			},
+		},
+	}
+
+	entry, marker := sm.Lookup(8)
+	assert.NotNil(t, entry)
+	assert.NotNil(t, marker)
+	assert.Equal(t, "Auth", marker.Name)
+
+	entry, marker = sm.Lookup(6)
+	assert.NotNil(t, entry)
+	assert.Nil(t, marker)
+
+	entry, _ = sm.Lookup(40)
+	assert.Nil(t, entry)
+
+	assert.Equal(t, `users.go:11: @Auth(role="admin") (handler ListUsers)`, sm.Describe(8))
+	assert.Equal(t, `users.go:10: @Route("GET", "/users") (handler ListUsers)`, sm.Describe(6))
+	assert.Equal(t, "", sm.Describe(40))
+}
+
+func TestWriteAndLoadSourceMap(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	tempDir := t.TempDir()
+	handlerFile := filepath.Join(tempDir, "handlers.go")
+	content := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// GetItems lists items
+// @Route("GET", "/items")
+// @Auth(role="user")
+func GetItems(c *gin.Context) {}
+`
+	assert.NoError(t, os.WriteFile(handlerFile, []byte(content), 0o600))
+
+	outputPath := filepath.Join(tempDir, ".deco", "init_decorators.go")
+	assert.NoError(t, GenerateInitFileWithConfig(tempDir, outputPath, "deco", nil))
+
+	sm, err := LoadSourceMap(SourceMapPath(outputPath))
+	assert.NoError(t, err)
+	if !assert.Len(t, sm.Entries, 1) {
+		return
+	}
+
+	entry := sm.Entries[0]
+	assert.Equal(t, "GetItems", entry.FuncName)
+	assert.Equal(t, handlerFile, entry.File)
+	assert.Equal(t, 6, entry.Line)
+	assert.Len(t, entry.Markers, 1)
+	assert.Equal(t, 7, entry.Markers[0].Line)
+	assert.NotZero(t, entry.Markers[0].GeneratedLine)
+
+	assert.Contains(t, sm.Describe(entry.Markers[0].GeneratedLine), `handlers.go:7: @Auth(role="user")`)
+}
+
+func TestLoadSourceMapErrors(t *testing.T) {
+	_, err := LoadSourceMap(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	invalid := filepath.Join(t.TempDir(), "sourcemap.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte("{"), 0o600))
+	_, err = LoadSourceMap(invalid)
+	assert.Error(t, err)
+}
+
+func TestCommentLineBlockComments(t *testing.T) {
+	src := `package handlers
+
+/*
+GetItems lists items
+@Route("GET", "/items")
+@Auth(role="user")
+*/
+// @Cache(duration="1m")
+// @Cache(duration="1m")
+func GetItems() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "items.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	funcDecl := file.Decls[0].(*ast.FuncDecl)
+	commentText := funcDecl.Doc.Text()
+
+	lineOf := func(text string, n int) int {
+		offset := -1
+		for i := 0; i <= n; i++ {
+			offset += strings.Index(commentText[offset+1:], text) + 1
+		}
+		return commentLine(fset, funcDecl.Doc, commentText, offset)
+	}
+
+	assert.Equal(t, 5, lineOf("@Route", 0))
+	assert.Equal(t, 6, lineOf("@Auth", 0))
+	assert.Equal(t, 8, lineOf("@Cache", 0))
+	assert.Equal(t, 9, lineOf("@Cache", 1))
+	assert.Equal(t, 5, routeDecoratorLine(fset, funcDecl, commentText, strings.Index(commentText, "@Route")))
+}
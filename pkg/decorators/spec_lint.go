@@ -0,0 +1,566 @@
+package decorators
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSpecLintRulesFile is the ruleset file looked up in the project root (the config file directory)
+const DefaultSpecLintRulesFile = "spec_lint.rules"
+
+// LintSeverity severity of a lint rule
+type LintSeverity string
+
+// Lint severities
+const (
+	LintSeverityError LintSeverity = "error"
+	LintSeverityWarn  LintSeverity = "warn"
+	LintSeverityInfo  LintSeverity = "info"
+	LintSeverityOff   LintSeverity = "off"
+)
+
+// LintIssue a problem found in the generated specification
+type LintIssue struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Location string       `json:"location"` // e.g. "GET /users"
+	Message  string       `json:"message"`
+}
+
+// String formats the issue for CLI output
+func (i LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", i.Severity, i.Location, i.Message, i.Rule)
+}
+
+// LintCheck inspects a specification and reports issues for a rule
+type LintCheck func(spec *OpenAPISpec, options map[string]string) []LintIssue
+
+// LintRule a named check with its default severity
+type LintRule struct {
+	Name        string
+	Description string
+	Severity    LintSeverity
+	Options     map[string]string
+	// OptionValues restricts option values when set; options missing from Options and OptionValues are rejected
+	OptionValues map[string][]string
+	Check        LintCheck
+}
+
+// LintRuleset set of rules applied to a specification
+type LintRuleset struct {
+	Rules map[string]*LintRule
+}
+
+// lintRuleFile is the YAML format of spec_lint.rules
+type lintRuleFile struct {
+	Extends string                   `yaml:"extends"` // "default" (default) or "none"
+	Rules   map[string]lintRuleEntry `yaml:"rules"`
+}
+
+// lintRuleEntry accepts either a severity string or a mapping with options
+type lintRuleEntry struct {
+	Severity LintSeverity      `yaml:"severity"`
+	Options  map[string]string `yaml:"options"`
+}
+
+// UnmarshalYAML allows `rule: warn` shorthand
+func (e *lintRuleEntry) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		e.Severity = LintSeverity(value.Value)
+		return nil
+	}
+	type plain lintRuleEntry
+	return value.Decode((*plain)(e))
+}
+
+// global lint rules registry
+var (
+	lintRules      = make(map[string]*LintRule)
+	lintRulesMutex sync.RWMutex
+)
+
+// init registers default lint rules
+func init() {
+	initDefaultLintRules()
+}
+
+// RegisterLintRule registers a custom lint rule available to all rulesets
+func RegisterLintRule(rule *LintRule) {
+	lintRulesMutex.Lock()
+	defer lintRulesMutex.Unlock()
+	lintRules[rule.Name] = rule
+}
+
+// DefaultLintRuleset returns a ruleset with every registered rule at its default severity
+func DefaultLintRuleset() *LintRuleset {
+	lintRulesMutex.RLock()
+	defer lintRulesMutex.RUnlock()
+
+	ruleset := &LintRuleset{Rules: make(map[string]*LintRule)}
+	for name, rule := range lintRules {
+		copied := *rule
+		copied.Options = copyStringMap(rule.Options)
+		ruleset.Rules[name] = &copied
+	}
+	return ruleset
+}
+
+// LoadLintRuleset loads a ruleset file, applying overrides on top of the default rules
+func LoadLintRuleset(path string) (*LintRuleset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lint rules %s: %v", path, err)
+	}
+
+	var file lintRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing lint rules %s: %v", path, err)
+	}
+
+	ruleset := DefaultLintRuleset()
+	switch file.Extends {
+	case "", "default":
+	case "none":
+		for _, rule := range ruleset.Rules {
+			rule.Severity = LintSeverityOff
+		}
+	default:
+		return nil, fmt.Errorf("invalid extends '%s' in %s (valid: default, none)", file.Extends, path)
+	}
+
+	for name, entry := range file.Rules {
+		rule, exists := ruleset.Rules[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown lint rule '%s' in %s", name, path)
+		}
+		if entry.Severity != "" {
+			if !validLintSeverity(entry.Severity) {
+				return nil, fmt.Errorf("invalid severity '%s' for rule '%s'", entry.Severity, name)
+			}
+			rule.Severity = entry.Severity
+		}
+		for key, value := range entry.Options {
+			if err := validateLintOption(rule, key, value); err != nil {
+				return nil, fmt.Errorf("%v in %s", err, path)
+			}
+			rule.Options[key] = value
+		}
+	}
+
+	return ruleset, nil
+}
+
+// LintSpec runs all enabled rules against a specification
+func LintSpec(spec *OpenAPISpec, ruleset *LintRuleset) []LintIssue {
+	if ruleset == nil {
+		ruleset = DefaultLintRuleset()
+	}
+
+	var issues []LintIssue
+	for _, rule := range ruleset.Rules {
+		if rule.Severity == LintSeverityOff || rule.Check == nil {
+			continue
+		}
+		for _, issue := range rule.Check(spec, rule.Options) {
+			issue.Rule = rule.Name
+			issue.Severity = rule.Severity
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Location != issues[j].Location {
+			return issues[i].Location < issues[j].Location
+		}
+		return issues[i].Rule < issues[j].Rule
+	})
+
+	return issues
+}
+
+// LintRoutes builds the specification for parsed routes and lints it using the configuration
+func LintRoutes(routes []*RouteMeta, config *Config) ([]LintIssue, error) {
+	ruleset, err := lintRulesetForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return LintSpec(GenerateOpenAPISpecFromMeta(config, routes), ruleset), nil
+}
+
+// lintRulesetForConfig resolves the ruleset configured in spec_lint
+func lintRulesetForConfig(config *Config) (*LintRuleset, error) {
+	rulesFile := config.SpecLint.RulesFile
+	if rulesFile == "" {
+		rulesFile = DefaultSpecLintRulesFile
+	}
+	rulesFile = config.ResolvePath(rulesFile)
+
+	if _, err := os.Stat(rulesFile); err != nil {
+		if config.SpecLint.RulesFile != "" && config.SpecLint.RulesFile != DefaultSpecLintRulesFile {
+			return nil, fmt.Errorf("lint rules file not found: %s", rulesFile)
+		}
+		return DefaultLintRuleset(), nil
+	}
+
+	return LoadLintRuleset(rulesFile)
+}
+
+// runSpecLint lints generated routes and fails when issues reach the configured threshold
+func runSpecLint(routes []*RouteMeta, config *Config) error {
+	if !config.SpecLint.IsEnabled() {
+		return nil
+	}
+
+	issues, err := LintRoutes(routes, config)
+	if err != nil {
+		return err
+	}
+
+	failing := 0
+	for _, issue := range issues {
+		if lintIssueFails(issue.Severity, config.SpecLint.FailOn) {
+			failing++
+			LogSilent("❌ %s", issue.String())
+		} else if issue.Severity == LintSeverityInfo {
+			LogVerbose("ℹ️  %s", issue.String())
+		} else {
+			LogNormal("⚠️  %s", issue.String())
+		}
+	}
+
+	if failing > 0 {
+		return fmt.Errorf("spec lint failed with %d issue(s)", failing)
+	}
+
+	return nil
+}
+
+// lintIssueFails checks a severity against the fail_on threshold
+func lintIssueFails(severity LintSeverity, failOn string) bool {
+	switch failOn {
+	case "never":
+		return false
+	case "warn":
+		return severity == LintSeverityError || severity == LintSeverityWarn
+	default:
+		return severity == LintSeverityError
+	}
+}
+
+// validateLintOption checks an option override against the rule's known options and values
+func validateLintOption(rule *LintRule, key, value string) error {
+	allowed, restricted := rule.OptionValues[key]
+	if _, known := rule.Options[key]; !known && !restricted {
+		return fmt.Errorf("unknown option '%s' for rule '%s'", key, rule.Name)
+	}
+	if !restricted {
+		return nil
+	}
+	for _, candidate := range allowed {
+		if value == candidate {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s '%s' for rule '%s' (valid: %s)", key, value, rule.Name, strings.Join(allowed, ", "))
+}
+
+// validLintSeverity checks if a severity is known
+func validLintSeverity(severity LintSeverity) bool {
+	switch severity {
+	case LintSeverityError, LintSeverityWarn, LintSeverityInfo, LintSeverityOff:
+		return true
+	}
+	return false
+}
+
+// copyStringMap copies a string map, never returning nil
+func copyStringMap(source map[string]string) map[string]string {
+	copied := make(map[string]string, len(source))
+	for key, value := range source {
+		copied[key] = value
+	}
+	return copied
+}
+
+// lintOperation is a spec operation with its location
+type lintOperation struct {
+	location  string
+	path      string
+	operation *OpenAPIOperation
+}
+
+// sortedOperations returns the spec operations in a stable order
+func sortedOperations(spec *OpenAPISpec) []lintOperation {
+	var operations []lintOperation
+	for path, item := range spec.Paths {
+		for method, operation := range item {
+			operations = append(operations, lintOperation{
+				location:  fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+				path:      path,
+				operation: operation,
+			})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].location < operations[j].location })
+	return operations
+}
+
+// initDefaultLintRules registers the built-in ruleset
+func initDefaultLintRules() {
+	RegisterLintRule(&LintRule{
+		Name:        "operation-summary",
+		Description: "Operations should have a summary",
+		Severity:    LintSeverityWarn,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				if strings.TrimSpace(op.operation.Summary) == "" {
+					issues = append(issues, LintIssue{Location: op.location, Message: "operation has no summary"})
+				}
+			}
+			return issues
+		},
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "operation-description",
+		Description: "Operations should have a description",
+		Severity:    LintSeverityWarn,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				if strings.TrimSpace(op.operation.Description) == "" {
+					issues = append(issues, LintIssue{Location: op.location, Message: "operation has no description"})
+				}
+			}
+			return issues
+		},
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "operation-tags",
+		Description: "Operations should have at least one tag",
+		Severity:    LintSeverityInfo,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				if len(op.operation.Tags) == 0 {
+					issues = append(issues, LintIssue{Location: op.location, Message: "operation has no tags"})
+				}
+			}
+			return issues
+		},
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "error-response-schema",
+		Description: "4xx responses should reference a declared schema",
+		Severity:    LintSeverityWarn,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				codes := make([]string, 0, len(op.operation.Responses))
+				for code := range op.operation.Responses {
+					codes = append(codes, code)
+				}
+				sort.Strings(codes)
+
+				for _, code := range codes {
+					if !strings.HasPrefix(code, "4") {
+						continue
+					}
+					if !responseHasSchemaRef(op.operation.Responses[code]) {
+						issues = append(issues, LintIssue{
+							Location: op.location,
+							Message:  fmt.Sprintf("response %s has no schema", code),
+						})
+					}
+				}
+			}
+			return issues
+		},
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "path-naming",
+		Description: "Path segments should follow one naming style (option style: consistent, kebab-case, snake_case, camelCase)",
+		Severity:    LintSeverityWarn,
+		Options:     map[string]string{"style": "consistent"},
+		OptionValues: map[string][]string{
+			"style": {"consistent", "kebab-case", "snake_case", "camelCase"},
+		},
+		Check: checkPathNaming,
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "operation-id-casing",
+		Description: "OperationIds should share one casing (option style: consistent, camelCase, PascalCase)",
+		Severity:    LintSeverityWarn,
+		Options:     map[string]string{"style": "consistent"},
+		OptionValues: map[string][]string{
+			"style": {"consistent", "camelCase", "PascalCase"},
+		},
+		Check: checkOperationIDCasing,
+	})
+
+	RegisterLintRule(&LintRule{
+		Name:        "parameter-description",
+		Description: "Parameters should have a description",
+		Severity:    LintSeverityInfo,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				for _, param := range op.operation.Parameters {
+					if strings.TrimSpace(param.Description) == "" {
+						issues = append(issues, LintIssue{
+							Location: op.location,
+							Message:  fmt.Sprintf("parameter '%s' has no description", param.Name),
+						})
+					}
+				}
+			}
+			return issues
+		},
+	})
+}
+
+// responseHasSchemaRef checks whether a response references a component schema
+func responseHasSchemaRef(response OpenAPIResponse) bool {
+	for _, media := range response.Content {
+		if media.Schema != nil && (media.Schema.Ref != "" || (media.Schema.Items != nil && media.Schema.Items.Ref != "")) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	kebabSegment = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)+$`)
+	snakeSegment = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)+$`)
+	camelSegment = regexp.MustCompile(`^[a-z][a-z0-9]*([A-Z][a-z0-9]*)+$`)
+	lowerSegment = regexp.MustCompile(`^[a-z0-9.]+$`)
+	camelCaseID  = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	pascalCaseID = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+)
+
+// segmentStyle classifies a static path segment; single lowercase words fit every style
+func segmentStyle(segment string) string {
+	switch {
+	case lowerSegment.MatchString(segment):
+		return ""
+	case kebabSegment.MatchString(segment):
+		return "kebab-case"
+	case snakeSegment.MatchString(segment):
+		return "snake_case"
+	case camelSegment.MatchString(segment):
+		return "camelCase"
+	}
+	return "mixed"
+}
+
+// checkPathNaming reports path segments that break the expected naming style
+func checkPathNaming(spec *OpenAPISpec, options map[string]string) []LintIssue {
+	expected := options["style"]
+	type segmentUse struct {
+		path, segment, style string
+	}
+
+	var uses []segmentUse
+	counts := make(map[string]int)
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, segment := range strings.Split(path, "/") {
+			if segment == "" || strings.ContainsAny(segment, ":*{") {
+				continue
+			}
+			style := segmentStyle(segment)
+			if style == "" {
+				continue
+			}
+			uses = append(uses, segmentUse{path, segment, style})
+			counts[style]++
+		}
+	}
+
+	if expected == "" || expected == "consistent" {
+		// The most common style wins; ties are resolved alphabetically
+		best := ""
+		for style, count := range counts {
+			if style != "mixed" && (count > counts[best] || (count == counts[best] && style < best)) {
+				best = style
+			}
+		}
+		expected = best
+	}
+
+	// Nothing to compare against when every styled segment is mixed
+	if expected == "" {
+		return nil
+	}
+
+	var issues []LintIssue
+	reported := make(map[string]bool)
+	for _, use := range uses {
+		if use.style == expected || reported[use.path+use.segment] {
+			continue
+		}
+		reported[use.path+use.segment] = true
+		issues = append(issues, LintIssue{
+			Location: use.path,
+			Message:  fmt.Sprintf("segment '%s' is not %s", use.segment, expected),
+		})
+	}
+	return issues
+}
+
+// checkOperationIDCasing reports operationIds that break the expected casing
+func checkOperationIDCasing(spec *OpenAPISpec, options map[string]string) []LintIssue {
+	operations := sortedOperations(spec)
+	styleOf := func(id string) string {
+		switch {
+		case camelCaseID.MatchString(id):
+			return "camelCase"
+		case pascalCaseID.MatchString(id):
+			return "PascalCase"
+		}
+		return "other"
+	}
+
+	expected := options["style"]
+	if expected == "" || expected == "consistent" {
+		counts := make(map[string]int)
+		for _, op := range operations {
+			counts[styleOf(op.operation.OperationID)]++
+		}
+		if counts["PascalCase"] > counts["camelCase"] {
+			expected = "PascalCase"
+		} else {
+			expected = "camelCase"
+		}
+	}
+
+	var issues []LintIssue
+	for _, op := range operations {
+		if op.operation.OperationID == "" {
+			continue
+		}
+		if styleOf(op.operation.OperationID) != expected {
+			issues = append(issues, LintIssue{
+				Location: op.location,
+				Message:  fmt.Sprintf("operationId '%s' is not %s", op.operation.OperationID, expected),
+			})
+		}
+	}
+	return issues
+}
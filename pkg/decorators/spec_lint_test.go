@@ -0,0 +1,294 @@
+package decorators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintTestSpec() *OpenAPISpec {
+	return &OpenAPISpec{
+		Paths: map[string]OpenAPIPath{
+			"/user-profiles": {
+				"get": &OpenAPIOperation{
+					OperationID: "listUserProfiles",
+					Summary:     "List profiles",
+					Description: "Lists all profiles",
+					Tags:        []string{"profiles"},
+					Responses: map[string]OpenAPIResponse{
+						"200": {Description: "OK"},
+						"404": {Description: "Not found", Content: map[string]MediaType{
+							"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/ErrorResponse"}},
+						}},
+					},
+				},
+			},
+			"/order-items/{id}": {
+				"get": &OpenAPIOperation{
+					OperationID: "getOrderItem",
+					Summary:     "Get item",
+					Description: "Gets an item",
+					Tags:        []string{"orders"},
+					Responses:   map[string]OpenAPIResponse{"200": {Description: "OK"}},
+				},
+			},
+			"/shipping_rates": {
+				"post": &OpenAPIOperation{
+					OperationID: "CreateShippingRate",
+					Parameters:  []OpenAPIParameter{{Name: "dry_run", In: "query"}},
+					Responses: map[string]OpenAPIResponse{
+						"400": {Description: "Bad request", Content: map[string]MediaType{
+							"application/json": {Schema: &OpenAPISchema{Type: "object"}},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func issuesByRule(issues []LintIssue) map[string][]LintIssue {
+	byRule := make(map[string][]LintIssue)
+	for _, issue := range issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+	return byRule
+}
+
+func TestLintSpecDefaultRuleset(t *testing.T) {
+	issues := LintSpec(lintTestSpec(), nil)
+	byRule := issuesByRule(issues)
+
+	assert.Len(t, byRule["operation-summary"], 1)
+	assert.Len(t, byRule["operation-description"], 1)
+	assert.Len(t, byRule["operation-tags"], 1)
+	assert.Equal(t, "POST /shipping_rates", byRule["operation-tags"][0].Location)
+	assert.Equal(t, LintSeverityInfo, byRule["operation-tags"][0].Severity)
+
+	assert.Len(t, byRule["error-response-schema"], 1)
+	assert.Contains(t, byRule["error-response-schema"][0].Message, "400")
+
+	assert.Len(t, byRule["path-naming"], 1)
+	assert.Contains(t, byRule["path-naming"][0].Message, "shipping_rates")
+
+	assert.Len(t, byRule["operation-id-casing"], 1)
+	assert.Contains(t, byRule["operation-id-casing"][0].Message, "CreateShippingRate")
+
+	assert.Len(t, byRule["parameter-description"], 1)
+}
+
+func TestLoadLintRuleset(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "spec_lint.rules")
+	content := `extends: default
+rules:
+  operation-description: error
+  operation-tags: off
+  path-naming:
+    severity: error
+    options:
+      style: snake_case
+`
+	assert.NoError(t, os.WriteFile(rulesFile, []byte(content), 0o600))
+
+	ruleset, err := LoadLintRuleset(rulesFile)
+	assert.NoError(t, err)
+	assert.Equal(t, LintSeverityError, ruleset.Rules["operation-description"].Severity)
+	assert.Equal(t, LintSeverityOff, ruleset.Rules["operation-tags"].Severity)
+	assert.Equal(t, "snake_case", ruleset.Rules["path-naming"].Options["style"])
+
+	byRule := issuesByRule(LintSpec(lintTestSpec(), ruleset))
+	assert.Empty(t, byRule["operation-tags"])
+	assert.Len(t, byRule["path-naming"], 2)
+	assert.Equal(t, LintSeverityError, byRule["path-naming"][0].Severity)
+
+	// Defaults are not modified by overrides
+	assert.Equal(t, "consistent", DefaultLintRuleset().Rules["path-naming"].Options["style"])
+}
+
+func TestLoadLintRulesetExtendsNone(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "spec_lint.rules")
+	assert.NoError(t, os.WriteFile(rulesFile, []byte("extends: none\nrules:\n  operation-summary: warn\n"), 0o600))
+
+	ruleset, err := LoadLintRuleset(rulesFile)
+	assert.NoError(t, err)
+
+	issues := LintSpec(lintTestSpec(), ruleset)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "operation-summary", issues[0].Rule)
+}
+
+func TestLoadLintRulesetErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadLintRuleset(filepath.Join(dir, "missing.rules"))
+	assert.Error(t, err)
+
+	cases := map[string]string{
+		"unknown.rules":  "rules:\n  no-such-rule: warn\n",
+		"severity.rules": "rules:\n  operation-summary: fatal\n",
+		"extends.rules":  "extends: strict\n",
+		"invalid.rules":  "rules: [",
+	}
+	for name, content := range cases {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadLintRuleset(path)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRegisterLintRule(t *testing.T) {
+	RegisterLintRule(&LintRule{
+		Name:     "test-no-delete",
+		Severity: LintSeverityError,
+		Check: func(spec *OpenAPISpec, _ map[string]string) []LintIssue {
+			var issues []LintIssue
+			for _, op := range sortedOperations(spec) {
+				if op.location[:6] == "DELETE" {
+					issues = append(issues, LintIssue{Location: op.location, Message: "DELETE is not allowed"})
+				}
+			}
+			return issues
+		},
+	})
+	defer func() {
+		lintRulesMutex.Lock()
+		delete(lintRules, "test-no-delete")
+		lintRulesMutex.Unlock()
+	}()
+
+	spec := &OpenAPISpec{Paths: map[string]OpenAPIPath{
+		"/items/{id}": {"delete": &OpenAPIOperation{OperationID: "deleteItem", Responses: map[string]OpenAPIResponse{}}},
+	}}
+	byRule := issuesByRule(LintSpec(spec, DefaultLintRuleset()))
+	assert.Len(t, byRule["test-no-delete"], 1)
+}
+
+func TestRunSpecLint(t *testing.T) {
+	routes := []*RouteMeta{
+		{Method: "GET", Path: "/items", FuncName: "ListItems"},
+	}
+
+	config := DefaultConfig()
+	config.SpecLint.RulesFile = filepath.Join(t.TempDir(), "missing.rules")
+	assert.Error(t, runSpecLint(routes, config))
+
+	config = DefaultConfig()
+	assert.NoError(t, runSpecLint(routes, config))
+
+	config.SpecLint.FailOn = "warn"
+	assert.Error(t, runSpecLint(routes, config))
+
+	config.SpecLint.FailOn = "never"
+	assert.NoError(t, runSpecLint(routes, config))
+
+	config.SpecLint.FailOn = "warn"
+	disabled := false
+	config.SpecLint.Enabled = &disabled
+	assert.NoError(t, runSpecLint(routes, config))
+}
+
+func TestGenerateFromTemplateRunsSpecLint(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	sourceDir := t.TempDir()
+	source := "package handlers\n\nimport \"github.com/gin-gonic/gin\"\n\n// @Route(\"GET\", \"/items\")\nfunc ListItems(c *gin.Context) {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "items.go"), []byte(source), 0o600))
+	templatePath := filepath.Join(t.TempDir(), "custom.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("package {{ .PackageName }}\n"), 0o600))
+
+	config := DefaultConfig()
+	config.Generate.DisableCache = true
+	config.SpecLint.FailOn = "warn"
+	outputPath := filepath.Join(t.TempDir(), "out.go")
+	err := GenerateFromTemplateWithConfig(sourceDir, templatePath, outputPath, "deco", config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec lint failed")
+	_, statErr := os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(statErr))
+
+	config.SpecLint.FailOn = "never"
+	assert.NoError(t, GenerateFromTemplateWithConfig(sourceDir, templatePath, outputPath, "deco", config))
+}
+
+func TestGenerateOpenAPISpecFromMeta(t *testing.T) {
+	metas := []*RouteMeta{
+		{Method: "GET", Path: "/list", FuncName: "List", Group: &GroupInfo{Name: "items", Prefix: "/items"}},
+		{FuncName: "ChatHandler", WebSocketHandlers: []string{"chat"}},
+	}
+
+	spec := GenerateOpenAPISpecFromMeta(DefaultConfig(), metas)
+	assert.Len(t, spec.Paths, 1)
+	operation := spec.Paths["/items/list"]["get"]
+	if assert.NotNil(t, operation) {
+		assert.Contains(t, operation.Tags, "items")
+	}
+	assert.Equal(t, "/list", metas[0].Path)
+}
+
+func TestSpecLintConfigFromFile(t *testing.T) {
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, ".deco.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("spec_lint:\n  enabled: false\n"), 0o600))
+
+	config, err := LoadConfig(configPath)
+	assert.NoError(t, err)
+	assert.False(t, config.SpecLint.IsEnabled())
+	assert.Equal(t, "error", config.SpecLint.FailOn)
+
+	// Linting stays on by default when the section is absent
+	assert.NoError(t, os.WriteFile(configPath, []byte("version: \"1.0\"\n"), 0o600))
+	config, err = LoadConfig(configPath)
+	assert.NoError(t, err)
+	assert.True(t, config.SpecLint.IsEnabled())
+
+	// The default rules file is looked up next to the config file, not in the working directory
+	rules := "rules:\n  operation-summary: error\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, DefaultSpecLintRulesFile), []byte(rules), 0o600))
+	ruleset, err := lintRulesetForConfig(config)
+	assert.NoError(t, err)
+	assert.Equal(t, LintSeverityError, ruleset.Rules["operation-summary"].Severity)
+}
+
+func TestConfigValidateSpecLintFailOn(t *testing.T) {
+	config := DefaultConfig()
+	for _, failOn := range []string{"error", "warn", "never"} {
+		config.SpecLint.FailOn = failOn
+		assert.NoError(t, config.Validate())
+	}
+
+	config.SpecLint.FailOn = "warning"
+	assert.Error(t, config.Validate())
+}
+
+func TestLoadLintRulesetValidatesOptions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "spec_lint.rules")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	_, err := LoadLintRuleset(write("rules:\n  path-naming:\n    options:\n      style: kebab\n"))
+	assert.Error(t, err)
+
+	_, err = LoadLintRuleset(write("rules:\n  path-naming:\n    options:\n      case: kebab-case\n"))
+	assert.Error(t, err)
+
+	ruleset, err := LoadLintRuleset(write("rules:\n  path-naming:\n    options:\n      style: snake_case\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "snake_case", ruleset.Rules["path-naming"].Options["style"])
+}
+
+func TestCheckPathNamingAllMixed(t *testing.T) {
+	spec := &OpenAPISpec{Paths: map[string]OpenAPIPath{
+		"/User_Profiles": {"get": &OpenAPIOperation{}},
+		"/Order-Items":   {"get": &OpenAPIOperation{}},
+	}}
+	assert.Empty(t, checkPathNaming(spec, map[string]string{"style": "consistent"}))
+}
@@ -0,0 +1,178 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SpecOperationMatch operation of an OpenAPI spec matched against a concrete request
+type SpecOperationMatch struct {
+	Method     string
+	Template   string // path as declared in the spec, e.g. "/users/{id}"
+	Operation  *OpenAPIOperation
+	PathParams map[string]string
+}
+
+// LoadOpenAPISpec reads a spec written by /decorators/openapi.json or the generator
+func LoadOpenAPISpec(path string) (*OpenAPISpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spec %s: %v", path, err)
+	}
+	return ParseOpenAPISpec(data)
+}
+
+// ParseOpenAPISpec decodes a JSON OpenAPI document
+func ParseOpenAPISpec(data []byte) (*OpenAPISpec, error) {
+	var spec OpenAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %v", err)
+	}
+	if spec.Paths == nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: no paths")
+	}
+	return &spec, nil
+}
+
+// MatchSpecOperation finds the operation serving method and a concrete path such as "/users/42".
+// Both "{id}" and ":id" parameters are understood; static segments win over parameters.
+func MatchSpecOperation(spec *OpenAPISpec, method, requestPath string) (*SpecOperationMatch, bool) {
+	method = strings.ToLower(method)
+	requestPath, _, _ = strings.Cut(requestPath, "?")
+	segments := splitSpecPath(requestPath)
+
+	var best *SpecOperationMatch
+	bestParams := -1
+	templates := make([]string, 0, len(spec.Paths))
+	for template := range spec.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	for _, template := range templates {
+		operation := spec.Paths[template][method]
+		if operation == nil {
+			continue
+		}
+		params, ok := matchSpecPath(ginTemplateSegments(template, operation), segments)
+		if !ok {
+			continue
+		}
+		if best == nil || len(params) < bestParams {
+			best = &SpecOperationMatch{Method: strings.ToUpper(method), Template: template, Operation: operation, PathParams: params}
+			bestParams = len(params)
+		}
+	}
+	return best, best != nil
+}
+
+// splitSpecPath splits a path into its non-empty segments
+func splitSpecPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// matchSpecPath matches template segments against concrete ones, collecting path parameters
+func matchSpecPath(template, segments []string) (map[string]string, bool) {
+	params := make(map[string]string)
+	for i, part := range template {
+		// Catch-all parameters (*path) take the rest of the path
+		if strings.HasPrefix(part, "*") {
+			params[strings.TrimPrefix(part, "*")] = strings.Join(segments[min(i, len(segments)):], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		switch {
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			params[strings.Trim(part, "{}")] = segments[i]
+		case strings.HasPrefix(part, ":"):
+			params[strings.TrimPrefix(part, ":")] = segments[i]
+		case part != segments[i]:
+			return nil, false
+		}
+	}
+	if len(template) != len(segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+// MissingParameters returns the required query and header parameters that were not provided
+func (m *SpecOperationMatch) MissingParameters(query, headers map[string]string) []OpenAPIParameter {
+	var missing []OpenAPIParameter
+	for _, param := range m.Operation.Parameters {
+		if !param.Required {
+			continue
+		}
+		switch param.In {
+		case "query":
+			if _, ok := query[param.Name]; !ok {
+				missing = append(missing, param)
+			}
+		case "header":
+			if !hasHeader(headers, param.Name) {
+				missing = append(missing, param)
+			}
+		}
+	}
+	return missing
+}
+
+// hasHeader looks a header up case-insensitively
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresBody reports whether the operation declares a required request body
+func (m *SpecOperationMatch) RequiresBody() bool {
+	return m.Operation.RequestBody != nil && m.Operation.RequestBody.Required
+}
+
+// ResponseType name of the schema documented for a status code ("UserResponse", "[]UserResponse"), or "" when untyped
+func (m *SpecOperationMatch) ResponseType(status int) string {
+	response, ok := m.Operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = m.Operation.Responses[fmt.Sprintf("%dXX", status/100)]
+	}
+	if !ok {
+		response, ok = m.Operation.Responses["default"]
+	}
+	if !ok {
+		return ""
+	}
+
+	media, ok := response.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return ""
+	}
+	return specSchemaName(media.Schema)
+}
+
+// specSchemaName describes a schema by its reference or type
+func specSchemaName(schema *OpenAPISchema) string {
+	if schema.Ref != "" {
+		return schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		if item := specSchemaName(schema.Items); item != "" {
+			return "[]" + item
+		}
+	}
+	return schema.Type
+}
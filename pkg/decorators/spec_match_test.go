@@ -0,0 +1,90 @@
+package decorators
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func specMatchFixture() *OpenAPISpec {
+	return &OpenAPISpec{Paths: map[string]OpenAPIPath{
+		"/users/{id}": {"get": {
+			Parameters: []OpenAPIParameter{
+				{Name: "id", In: "path", Required: true},
+				{Name: "fields", In: "query", Required: true},
+				{Name: "X-Tenant", In: "header", Required: true},
+				{Name: "page", In: "query"},
+			},
+			Responses: map[string]OpenAPIResponse{
+				"200": {Content: map[string]MediaType{"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/UserResponse"}}}},
+			},
+		}},
+		"/users/me": {"get": {Responses: map[string]OpenAPIResponse{
+			"default": {Content: map[string]MediaType{"application/json": {Schema: &OpenAPISchema{
+				Type: "array", Items: &OpenAPISchema{Ref: "#/components/schemas/UserResponse"},
+			}}}},
+		}}},
+		"/files/*path": {"get": {}},
+		"/orders/:id":  {"post": {RequestBody: &OpenAPIRequestBody{Required: true}}},
+	}}
+}
+
+func TestMatchSpecOperation(t *testing.T) {
+	spec := specMatchFixture()
+
+	match, ok := MatchSpecOperation(spec, "GET", "/users/42?fields=name")
+	if assert.True(t, ok) {
+		assert.Equal(t, "/users/{id}", match.Template)
+		assert.Equal(t, map[string]string{"id": "42"}, match.PathParams)
+	}
+
+	match, ok = MatchSpecOperation(spec, "get", "/users/me")
+	if assert.True(t, ok) {
+		assert.Equal(t, "/users/me", match.Template, "static segments win over parameters")
+	}
+
+	match, ok = MatchSpecOperation(spec, "GET", "/files/a/b.txt")
+	if assert.True(t, ok) {
+		assert.Equal(t, "a/b.txt", match.PathParams["path"])
+	}
+
+	match, ok = MatchSpecOperation(spec, "POST", "/orders/7")
+	if assert.True(t, ok) {
+		assert.Equal(t, "7", match.PathParams["id"])
+		assert.True(t, match.RequiresBody())
+	}
+
+	_, ok = MatchSpecOperation(spec, "DELETE", "/users/42")
+	assert.False(t, ok)
+	_, ok = MatchSpecOperation(spec, "GET", "/users/42/posts")
+	assert.False(t, ok)
+}
+
+func TestSpecOperationMatch_MissingParametersAndResponseType(t *testing.T) {
+	spec := specMatchFixture()
+
+	match, _ := MatchSpecOperation(spec, "GET", "/users/42")
+	missing := match.MissingParameters(map[string]string{}, map[string]string{"x-tenant": "acme"})
+	if assert.Len(t, missing, 1) {
+		assert.Equal(t, "fields", missing[0].Name)
+	}
+	assert.Equal(t, "UserResponse", match.ResponseType(200))
+	assert.Equal(t, "", match.ResponseType(404))
+
+	match, _ = MatchSpecOperation(spec, "GET", "/users/me")
+	assert.Equal(t, "[]UserResponse", match.ResponseType(200))
+}
+
+func TestParseOpenAPISpec(t *testing.T) {
+	data, err := json.Marshal(specMatchFixture())
+	assert.NoError(t, err)
+
+	spec, err := ParseOpenAPISpec(data)
+	assert.NoError(t, err)
+	_, ok := MatchSpecOperation(spec, "GET", "/users/1")
+	assert.True(t, ok)
+
+	_, err = ParseOpenAPISpec([]byte(`{"openapi": "3.0.3"}`))
+	assert.Error(t, err)
+}
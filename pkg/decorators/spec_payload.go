@@ -0,0 +1,265 @@
+package decorators
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// specPayloadMaxDepth nesting at which generated objects stop expanding, so recursive schemas end
+const specPayloadMaxDepth = 4
+
+// Pools of the generated strings
+var (
+	specPayloadNames = []string{"Ana Souza", "Bruno Lima", "Carla Dias", "Diego Rocha", "Elisa Melo", "Felipe Costa"}
+	specPayloadWords = []string{"alpha", "bravo", "delta", "echo", "kilo", "lima", "nova", "orion", "sierra", "tango"}
+)
+
+// SpecPayloadGenerator generates values that satisfy the schemas of an OpenAPI spec: the example, enum or
+// default of a schema first, then a value shaped by its format, bounds and the name of the property it
+// fills ("email", "name", "phone"). Patterns are not followed. Not safe for concurrent use.
+type SpecPayloadGenerator struct {
+	components *OpenAPIComponents
+	rng        *rand.Rand
+	sequence   int
+}
+
+// NewSpecPayloadGenerator creates a generator for the schemas of spec; the same seed gives the same values
+func NewSpecPayloadGenerator(spec *OpenAPISpec, seed int64) *SpecPayloadGenerator {
+	return &SpecPayloadGenerator{components: spec.Components, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Value generates a request value of schema, without its readOnly properties; name is the property or
+// parameter it fills
+func (g *SpecPayloadGenerator) Value(schema *OpenAPISchema, name string) interface{} {
+	return g.value(schema, name, 0, false)
+}
+
+// ResponseValue generates a response value of schema, without its writeOnly properties
+func (g *SpecPayloadGenerator) ResponseValue(schema *OpenAPISchema, name string) interface{} {
+	return g.value(schema, name, 0, true)
+}
+
+// value generates a value of schema at a nesting depth, for a response or a request
+func (g *SpecPayloadGenerator) value(schema *OpenAPISchema, name string, depth int, response bool) interface{} {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved := g.resolve(schema.Ref)
+		if resolved == nil {
+			return map[string]interface{}{}
+		}
+		schema = resolved
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Enum) > 0:
+		return schema.Enum[g.rng.Intn(len(schema.Enum))]
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.AllOf) > 0:
+		merged := make(map[string]interface{})
+		for _, part := range schema.AllOf {
+			if object, ok := g.value(part, name, depth, response).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return g.value(schema.OneOf[g.rng.Intn(len(schema.OneOf))], name, depth, response)
+	case len(schema.AnyOf) > 0:
+		return g.value(schema.AnyOf[g.rng.Intn(len(schema.AnyOf))], name, depth, response)
+	}
+
+	switch schema.Type {
+	case "string":
+		return g.stringValue(schema, name)
+	case "integer":
+		return g.integerValue(schema)
+	case "number":
+		return g.numberValue(schema)
+	case "boolean":
+		return g.rng.Intn(2) == 0
+	case "array":
+		if depth >= specPayloadMaxDepth || schema.Items == nil {
+			return []interface{}{}
+		}
+		count := g.between(max(schema.MinItems, 1), schema.MaxItems, 3)
+		items := make([]interface{}, count)
+		for i := range items {
+			items[i] = g.value(schema.Items, name, depth+1, response)
+		}
+		return items
+	}
+
+	object := make(map[string]interface{}, len(schema.Properties))
+	if depth >= specPayloadMaxDepth {
+		return object
+	}
+	// Sorted, so a seed always draws the same values
+	names := make([]string, 0, len(schema.Properties))
+	for property := range schema.Properties {
+		names = append(names, property)
+	}
+	sort.Strings(names)
+	for _, property := range names {
+		if (!response && schema.Properties[property].ReadOnly) || (response && schema.Properties[property].WriteOnly) {
+			continue
+		}
+		object[property] = g.value(schema.Properties[property], property, depth+1, response)
+	}
+	return object
+}
+
+// resolve schema of a "#/components/schemas/Name" reference
+func (g *SpecPayloadGenerator) resolve(ref string) *OpenAPISchema {
+	if g.components == nil {
+		return nil
+	}
+	return g.components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+}
+
+// next sequence number of the generated identifiers, so they do not repeat
+func (g *SpecPayloadGenerator) next() int {
+	g.sequence++
+	return g.sequence
+}
+
+// between random count in [low, high], high defaulting to fallback
+func (g *SpecPayloadGenerator) between(low, high, fallback int) int {
+	if high <= 0 {
+		high = max(fallback, low)
+	}
+	if high <= low {
+		return low
+	}
+	return low + g.rng.Intn(high-low+1)
+}
+
+// stringValue string of the format of schema, else one fitting the property name, within the length bounds
+func (g *SpecPayloadGenerator) stringValue(schema *OpenAPISchema, name string) string {
+	value := g.formattedString(schema.Format)
+	if value == "" {
+		value = g.namedString(name)
+	}
+	if value == "" {
+		words := make([]string, g.between(1, 0, 2))
+		for i := range words {
+			words[i] = specPayloadWords[g.rng.Intn(len(specPayloadWords))]
+		}
+		value = strings.Join(words, " ")
+	}
+
+	if schema.MaxLength > 0 && len(value) > schema.MaxLength {
+		value = value[:schema.MaxLength]
+	}
+	if len(value) < schema.MinLength {
+		value += strings.Repeat("x", schema.MinLength-len(value))
+	}
+	return value
+}
+
+// formattedString value of a string format, empty when the format is unknown
+func (g *SpecPayloadGenerator) formattedString(format string) string {
+	switch format {
+	case "email":
+		return g.email()
+	case "uuid":
+		id := make([]byte, 16)
+		g.rng.Read(id)
+		id[6] = id[6]&0x0f | 0x40
+		id[8] = id[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+	case "date-time":
+		return g.pastTime().Format(time.RFC3339)
+	case "date":
+		return g.pastTime().Format("2006-01-02")
+	case "uri", "url":
+		return fmt.Sprintf("https://example.com/resources/%d", g.next())
+	case "hostname":
+		return fmt.Sprintf("host-%d.example.com", g.next())
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+g.rng.Intn(254))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+g.rng.Intn(0xffff))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(specPayloadWords[g.rng.Intn(len(specPayloadWords))]))
+	case "password":
+		return fmt.Sprintf("S3cret!%d", g.next())
+	}
+	return ""
+}
+
+// namedString value fitting a property name, empty when the name says nothing
+func (g *SpecPayloadGenerator) namedString(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "email"):
+		return g.email()
+	case strings.Contains(lower, "phone"):
+		return fmt.Sprintf("+1555%07d", g.rng.Intn(10000000))
+	case strings.Contains(lower, "url"):
+		return fmt.Sprintf("https://example.com/resources/%d", g.next())
+	case strings.HasSuffix(lower, "name"):
+		return specPayloadNames[g.rng.Intn(len(specPayloadNames))]
+	case lower == "id" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(name, "Id"):
+		return strconv.Itoa(g.next())
+	}
+	return ""
+}
+
+// email address with a unique local part
+func (g *SpecPayloadGenerator) email() string {
+	first, _, _ := strings.Cut(specPayloadNames[g.rng.Intn(len(specPayloadNames))], " ")
+	return fmt.Sprintf("%s.%d@example.com", strings.ToLower(first), g.next())
+}
+
+// pastTime instant within the last year, at second precision
+func (g *SpecPayloadGenerator) pastTime() time.Time {
+	return time.Now().UTC().Add(-time.Duration(g.rng.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+// bounds range of a numeric schema; without bounds, 1 to 1000
+func (g *SpecPayloadGenerator) bounds(schema *OpenAPISchema) (low, high float64) {
+	low, high = schema.Minimum, schema.Maximum
+	if low == 0 && high == 0 {
+		return 1, 1000
+	}
+	if high <= low {
+		high = low + 1000
+	}
+	return low, high
+}
+
+// integerValue integer within the bounds of schema
+func (g *SpecPayloadGenerator) integerValue(schema *OpenAPISchema) int64 {
+	low, high := g.bounds(schema)
+	first, last := int64(math.Ceil(low)), int64(math.Floor(high))
+	if schema.ExclusiveMinimum && float64(first) == low {
+		first++
+	}
+	if schema.ExclusiveMaximum && float64(last) == high {
+		last--
+	}
+	if last <= first {
+		return first
+	}
+	return first + g.rng.Int63n(last-first+1)
+}
+
+// numberValue number within the bounds of schema, with two decimals
+func (g *SpecPayloadGenerator) numberValue(schema *OpenAPISchema) float64 {
+	low, high := g.bounds(schema)
+	value := math.Round((low+g.rng.Float64()*(high-low))*100) / 100
+	return math.Min(math.Max(value, low), high)
+}
@@ -0,0 +1,86 @@
+package decorators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// payloadTestSpec spec with a recursive schema and the usual string formats
+func payloadTestSpec() *OpenAPISpec {
+	return &OpenAPISpec{Components: &OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+		"User": {Type: "object", Properties: map[string]*OpenAPISchema{
+			"id":        {Type: "integer", ReadOnly: true},
+			"name":      {Type: "string", MaxLength: 5},
+			"email":     {Type: "string", Format: "email"},
+			"age":       {Type: "integer", Minimum: 18, Maximum: 21},
+			"score":     {Type: "number", Minimum: 0.5, Maximum: 1},
+			"role":      {Type: "string", Enum: []interface{}{"admin", "viewer"}},
+			"country":   {Type: "string", Example: "BR"},
+			"createdAt": {Type: "string", Format: "date-time"},
+			"manager":   {Ref: "#/components/schemas/User"},
+			"tags":      {Type: "array", Items: &OpenAPISchema{Type: "string", MinLength: 3}, MinItems: 2, MaxItems: 2},
+		}},
+	}}}
+}
+
+func TestSpecPayloadGenerator(t *testing.T) {
+	spec := payloadTestSpec()
+	generator := NewSpecPayloadGenerator(spec, 1)
+	user, ok := generator.Value(&OpenAPISchema{Ref: "#/components/schemas/User"}, "").(map[string]interface{})
+	require.True(t, ok)
+
+	assert.NotContains(t, user, "id", "read-only properties are not sent")
+	assert.LessOrEqual(t, len(user["name"].(string)), 5)
+	assert.Regexp(t, `^[a-z]+\.\d+@example\.com$`, user["email"])
+	assert.GreaterOrEqual(t, user["age"], int64(18))
+	assert.LessOrEqual(t, user["age"], int64(21))
+	assert.InDelta(t, 0.75, user["score"], 0.25)
+	assert.Contains(t, []interface{}{"admin", "viewer"}, user["role"])
+	assert.Equal(t, "BR", user["country"])
+	_, err := time.Parse(time.RFC3339, user["createdAt"].(string))
+	assert.NoError(t, err)
+	require.Len(t, user["tags"], 2)
+	assert.GreaterOrEqual(t, len(user["tags"].([]interface{})[0].(string)), 3)
+
+	// Recursive references stop at the depth limit
+	depth := 0
+	for manager, ok := user["manager"].(map[string]interface{}); ok && len(manager) > 0; manager, ok = manager["manager"].(map[string]interface{}) {
+		depth++
+	}
+	assert.Equal(t, specPayloadMaxDepth-1, depth)
+
+	again := NewSpecPayloadGenerator(spec, 1).Value(&OpenAPISchema{Ref: "#/components/schemas/User"}, "")
+	assert.Equal(t, user["email"], again.(map[string]interface{})["email"], "the seed fixes the values")
+}
+
+func TestSpecPayloadGenerator_Composition(t *testing.T) {
+	generator := NewSpecPayloadGenerator(payloadTestSpec(), 1)
+	merged := generator.Value(&OpenAPISchema{AllOf: []*OpenAPISchema{
+		{Type: "object", Properties: map[string]*OpenAPISchema{"a": {Type: "boolean"}}},
+		{Type: "object", Properties: map[string]*OpenAPISchema{"userId": {Type: "string"}}},
+	}}, "")
+	assert.Contains(t, merged, "a")
+	assert.Regexp(t, `^\d+$`, merged.(map[string]interface{})["userId"])
+
+	assert.Equal(t, map[string]interface{}{}, generator.Value(&OpenAPISchema{Ref: "#/components/schemas/Missing"}, ""))
+	assert.Regexp(t, `^[0-9a-f-]{36}$`, generator.Value(&OpenAPISchema{Type: "string", Format: "uuid"}, ""))
+	assert.Equal(t, int64(6), generator.Value(&OpenAPISchema{Type: "integer", Minimum: 5, Maximum: 6, ExclusiveMinimum: true}, ""))
+}
+
+func TestSpecPayloadGenerator_ResponseValue(t *testing.T) {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{
+		"id":       {Type: "integer", ReadOnly: true},
+		"password": {Type: "string", WriteOnly: true},
+	}}
+	generator := NewSpecPayloadGenerator(&OpenAPISpec{}, 1)
+	request := generator.Value(schema, "").(map[string]interface{})
+	assert.Contains(t, request, "password")
+	assert.NotContains(t, request, "id")
+
+	response := generator.ResponseValue(schema, "").(map[string]interface{})
+	assert.Contains(t, response, "id")
+	assert.NotContains(t, response, "password", "write-only properties are not returned")
+}
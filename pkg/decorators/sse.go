@@ -0,0 +1,381 @@
+package decorators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Server-Sent Events: @SSE streams the events published to a channel of the broker
+//
+//	// @Route("GET", "/orders/:id/events")
+//	// @SSE(channel="orders:{id}", events="status,shipped", type="OrderEvent")
+//	func OrderEvents(c *gin.Context) {}
+//
+//	deco.GetSSEBroker().Publish("orders:42", deco.SSEEvent{Event: "status", Data: order})
+
+// SSEExtension OpenAPI extension describing the event stream of an @SSE route
+const SSEExtension = "x-sse"
+
+// defaultSSEHeartbeat interval of the keep-alive comments sent to idle clients
+const defaultSSEHeartbeat = 15 * time.Second
+
+// sseConfigKey context key of the @SSE configuration of the route
+const sseConfigKey = "deco_sse_config"
+
+// SSEConfig configuration of @SSE
+type SSEConfig struct {
+	Channel   string        // broker channel; {param} placeholders take the path parameter
+	Events    []string      // event names sent on the stream (documentation)
+	Type      string        // schema of the event data (documentation)
+	Heartbeat time.Duration // 0 disables the keep-alive comments
+}
+
+// parseSSEArgs parses @SSE(channel="orders:{id}", events="status,shipped", type="OrderEvent", heartbeat="15s")
+func parseSSEArgs(args []string) (SSEConfig, error) {
+	config := SSEConfig{Heartbeat: defaultSSEHeartbeat}
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			if config.Channel != "" {
+				return config, fmt.Errorf("@SSE: unexpected argument '%s'", arg)
+			}
+			config.Channel = MarkerValue(arg)
+			continue
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "channel":
+			config.Channel = value
+		case "events":
+			config.Events = splitMarkerList(value)
+		case "type":
+			config.Type = value
+		case "heartbeat":
+			heartbeat, err := ParseDurationLiteral(value)
+			if err != nil {
+				return config, fmt.Errorf("@SSE: invalid heartbeat '%s': %v", value, err)
+			}
+			config.Heartbeat = heartbeat
+		default:
+			return config, fmt.Errorf("@SSE: unknown argument '%s' (valid: channel, events, type, heartbeat)", key)
+		}
+	}
+	return config, nil
+}
+
+// splitMarkerList splits a comma-separated marker value, dropping empty items
+func splitMarkerList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// channelFor resolves the {param} placeholders of the channel with the path parameters of the request
+func (s SSEConfig) channelFor(c *gin.Context) string {
+	channel := s.Channel
+	for _, param := range c.Params {
+		channel = strings.ReplaceAll(channel, "{"+param.Key+"}", param.Value)
+	}
+	return channel
+}
+
+// SSEEvent event sent to the clients of a channel. Data is written as is when it is a string or
+// []byte and as JSON otherwise.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  interface{}
+	Retry time.Duration // reconnection delay suggested to the client
+}
+
+// SSEClient connection subscribed to a channel
+type SSEClient struct {
+	ID      string
+	Channel string
+	Events  chan SSEEvent
+
+	dropped atomic.Int64
+}
+
+// Dropped returns the number of events the client missed because its buffer was full
+func (c *SSEClient) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// SSEBroker fans out published events to the clients subscribed to each channel
+type SSEBroker struct {
+	clients map[string]map[*SSEClient]struct{}
+	buffer  int
+	nextID  atomic.Int64
+	mu      sync.RWMutex
+}
+
+// defaultSSEBroker broker used by @SSE
+var (
+	defaultSSEBroker     *SSEBroker
+	defaultSSEBrokerOnce sync.Once
+)
+
+// NewSSEBroker creates a broker; each client buffers up to buffer events before new ones are dropped
+func NewSSEBroker(buffer int) *SSEBroker {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	return &SSEBroker{clients: make(map[string]map[*SSEClient]struct{}), buffer: buffer}
+}
+
+// GetSSEBroker returns the broker used by @SSE routes
+func GetSSEBroker() *SSEBroker {
+	defaultSSEBrokerOnce.Do(func() {
+		defaultSSEBroker = NewSSEBroker(64)
+	})
+	return defaultSSEBroker
+}
+
+// Subscribe registers a client on channel; Unsubscribe must be called when it disconnects
+func (b *SSEBroker) Subscribe(channel string) *SSEClient {
+	client := &SSEClient{
+		ID:      "sse-" + strconv.FormatInt(b.nextID.Add(1), 10),
+		Channel: channel,
+		Events:  make(chan SSEEvent, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.clients[channel] == nil {
+		b.clients[channel] = make(map[*SSEClient]struct{})
+	}
+	b.clients[channel][client] = struct{}{}
+	return client
+}
+
+// Unsubscribe removes the client from its channel
+func (b *SSEBroker) Unsubscribe(client *SSEClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients[client.Channel], client)
+	if len(b.clients[client.Channel]) == 0 {
+		delete(b.clients, client.Channel)
+	}
+}
+
+// Publish sends the event to the clients of channel and returns how many received it; clients
+// whose buffer is full miss the event instead of slowing the publisher down
+func (b *SSEBroker) Publish(channel string, event SSEEvent) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	delivered := 0
+	for client := range b.clients[channel] {
+		select {
+		case client.Events <- event:
+			delivered++
+		default:
+			client.dropped.Add(1)
+		}
+	}
+	return delivered
+}
+
+// Broadcast sends the event to the clients of every channel
+func (b *SSEBroker) Broadcast(event SSEEvent) int {
+	b.mu.RLock()
+	channels := make([]string, 0, len(b.clients))
+	for channel := range b.clients {
+		channels = append(channels, channel)
+	}
+	b.mu.RUnlock()
+
+	delivered := 0
+	for _, channel := range channels {
+		delivered += b.Publish(channel, event)
+	}
+	return delivered
+}
+
+// Clients returns the number of clients subscribed to channel
+func (b *SSEBroker) Clients(channel string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.clients[channel])
+}
+
+// Stats returns the number of clients per channel
+func (b *SSEBroker) Stats() map[string]int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	stats := make(map[string]int, len(b.clients))
+	for channel, clients := range b.clients {
+		stats[channel] = len(clients)
+	}
+	return stats
+}
+
+// writeSSEEvent writes the event in the text/event-stream format
+func writeSSEEvent(w io.Writer, event SSEEvent) error {
+	var data string
+	switch value := event.Data.(type) {
+	case string:
+		data = value
+	case []byte:
+		data = string(value)
+	case nil:
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("error encoding SSE event: %v", err)
+		}
+		data = string(encoded)
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Event)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// setSSEHeaders sets the streaming headers; the content type is only set when the stream starts, so
+// error responses written by the handler keep theirs
+func setSSEHeaders(c *gin.Context) {
+	header := c.Writer.Header()
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // disables proxy buffering (nginx)
+}
+
+// SSEStream streams the events of the route's @SSE channel until the client disconnects. Handlers of
+// @SSE routes with a channel do not need to call it: an empty handler streams the channel.
+func SSEStream(c *gin.Context) {
+	config := SSEConfig{Heartbeat: defaultSSEHeartbeat}
+	if value, ok := c.Get(sseConfigKey); ok {
+		config = value.(SSEConfig)
+	}
+	SSEStreamChannel(c, GetSSEBroker(), config.channelFor(c), config.Heartbeat)
+}
+
+// SSEStreamChannel streams the events published to channel on broker until the client disconnects,
+// sending a keep-alive comment every heartbeat (0 disables them)
+func SSEStreamChannel(c *gin.Context, broker *SSEBroker, channel string, heartbeat time.Duration) {
+	client := broker.Subscribe(channel)
+	defer broker.Unsubscribe(client)
+
+	setSSEHeaders(c)
+	c.Header("Content-Type", "text/event-stream")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	var ticks <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event := <-client.Events:
+			if err := writeSSEEvent(c.Writer, event); err != nil {
+				LogVerbose("SSE: error writing to %s: %v", client.ID, err)
+				return
+			}
+			c.Writer.Flush()
+		case <-ticks:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// SSEMiddleware sets the streaming headers and, when the handler writes nothing, streams the channel
+func SSEMiddleware(config SSEConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(sseConfigKey, config)
+		setSSEHeaders(c)
+		c.Next()
+
+		if config.Channel != "" && !c.Writer.Written() && !c.IsAborted() {
+			SSEStreamChannel(c, GetSSEBroker(), config.channelFor(c), config.Heartbeat)
+		}
+	}
+}
+
+// createSSEMiddleware creates the @SSE middleware
+func createSSEMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseSSEArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return SSEMiddleware(config)
+}
+
+// sseExtension builds the x-sse extension of a route, or nil when it has no @SSE
+func sseExtension(route *RouteEntry) map[string]interface{} {
+	for _, mw := range route.MiddlewareInfo {
+		if mw.Name != "SSE" {
+			continue
+		}
+		extension := map[string]interface{}{}
+		for _, key := range []string{"channel", "type", "heartbeat"} {
+			if value, ok := mw.Args[key].(string); ok && value != "" {
+				extension[key] = value
+			}
+		}
+		if value, ok := mw.Args["value"].(string); ok && extension["channel"] == nil {
+			extension["channel"] = value
+		}
+		if events, ok := mw.Args["events"].(string); ok {
+			extension["events"] = splitMarkerList(events)
+		}
+		return extension
+	}
+	return nil
+}
+
+// sseResponse documents the 200 response of an @SSE route as an event stream
+func sseResponse(extension map[string]interface{}) OpenAPIResponse {
+	description := "Event stream (text/event-stream)"
+	if events, ok := extension["events"].([]string); ok && len(events) > 0 {
+		description += "; events: " + strings.Join(events, ", ")
+	}
+	if typeName, ok := extension["type"].(string); ok {
+		description += "; data: " + typeName
+	}
+	return OpenAPIResponse{
+		Description: description,
+		Content: map[string]MediaType{
+			"text/event-stream": {Schema: &OpenAPISchema{Type: "string"}},
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package decorators
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSSEArgs(t *testing.T) {
+	config, err := parseSSEArgs([]string{`channel="orders:{id}"`, `events="status, shipped"`, `type="OrderEvent"`, `heartbeat="5s"`})
+	require.NoError(t, err)
+	assert.Equal(t, SSEConfig{Channel: "orders:{id}", Events: []string{"status", "shipped"}, Type: "OrderEvent", Heartbeat: 5 * time.Second}, config)
+
+	config, err = parseSSEArgs([]string{`"news"`})
+	require.NoError(t, err)
+	assert.Equal(t, "news", config.Channel)
+	assert.Equal(t, defaultSSEHeartbeat, config.Heartbeat)
+
+	_, err = parseSSEArgs([]string{`heartbeat="soon"`})
+	assert.Error(t, err)
+	_, err = parseSSEArgs([]string{`topic="news"`})
+	assert.ErrorContains(t, err, "unknown argument 'topic'")
+}
+
+func TestWriteSSEEvent(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, writeSSEEvent(&b, SSEEvent{ID: "7", Event: "status", Data: map[string]string{"status": "paid"}, Retry: 3 * time.Second}))
+	assert.Equal(t, "id: 7\nevent: status\nretry: 3000\ndata: {\"status\":\"paid\"}\n\n", b.String())
+
+	b.Reset()
+	require.NoError(t, writeSSEEvent(&b, SSEEvent{Data: "line 1\nline 2"}))
+	assert.Equal(t, "data: line 1\ndata: line 2\n\n", b.String())
+}
+
+func TestSSEBroker(t *testing.T) {
+	broker := NewSSEBroker(1)
+	first := broker.Subscribe("news")
+	second := broker.Subscribe("news")
+	other := broker.Subscribe("sports")
+	assert.Equal(t, 2, broker.Clients("news"))
+
+	assert.Equal(t, 2, broker.Publish("news", SSEEvent{Data: "a"}))
+	assert.Equal(t, 0, broker.Publish("news", SSEEvent{Data: "b"}), "full buffers drop the event")
+	assert.Equal(t, int64(1), first.Dropped())
+	assert.Equal(t, "a", (<-second.Events).Data)
+
+	assert.Equal(t, 2, broker.Broadcast(SSEEvent{Data: "c"}))
+	assert.Equal(t, "c", (<-other.Events).Data)
+
+	broker.Unsubscribe(first)
+	broker.Unsubscribe(second)
+	assert.Equal(t, map[string]int{"sports": 1}, broker.Stats())
+}
+
+func TestSSEMiddlewareStreamsChannel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/orders/:id/events", createSSEMiddleware([]string{`channel="orders:{id}"`, `heartbeat="0s"`}), func(_ *gin.Context) {})
+	router.GET("/orders/:id/missing", createSSEMiddleware([]string{`channel="orders:{id}"`}), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/orders/42/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	broker := GetSSEBroker()
+	require.Eventually(t, func() bool { return broker.Clients("orders:42") == 1 }, time.Second, 5*time.Millisecond)
+	broker.Publish("orders:42", SSEEvent{Event: "status", Data: "paid"})
+
+	reader := bufio.NewReader(resp.Body)
+	lines := make([]string, 0, 2)
+	for len(lines) < 2 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"event: status\n", "data: paid\n"}, lines)
+
+	cancel()
+	assert.Eventually(t, func() bool { return broker.Clients("orders:42") == 0 }, time.Second, 5*time.Millisecond)
+
+	// Responses written by the handler are left alone
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/42/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestOpenAPISSEExtension(t *testing.T) {
+	routes := []RouteEntry{
+		{
+			Method:   "GET",
+			Path:     "/orders/{id}/events",
+			FuncName: "OrderEvents",
+			MiddlewareInfo: []MiddlewareInfo{{Name: "SSE", Args: map[string]interface{}{
+				"channel": "orders:{id}", "events": "status,shipped", "type": "OrderEvent",
+			}}},
+		},
+	}
+	spec := buildOpenAPISpec(DefaultConfig(), routes, nil)
+
+	operation := spec.Paths["/orders/{id}/events"]["get"]
+	require.NotNil(t, operation)
+	assert.Equal(t, map[string]interface{}{
+		"channel": "orders:{id}",
+		"events":  []string{"status", "shipped"},
+		"type":    "OrderEvent",
+	}, operation.Extensions[SSEExtension])
+	response := operation.Responses["200"]
+	assert.Contains(t, response.Content, "text/event-stream")
+	assert.Contains(t, response.Description, "events: status, shipped")
+}
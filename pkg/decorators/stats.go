@@ -0,0 +1,304 @@
+package decorators
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsPath consolidated statistics of the subsystems: uptime, route counters, cache, rate limit,
+// WebSocket, SSE, proxies, circuit breakers and tracing
+const StatsPath = "/decorators/stats"
+
+// processStartedAt instant the package was loaded, origin of the uptime
+var processStartedAt = time.Now()
+
+// StatsReport document of StatsPath
+type StatsReport struct {
+	StartedAt       time.Time                         `json:"started_at"`
+	UptimeSeconds   float64                           `json:"uptime_seconds"`
+	Requests        RouteStats                        `json:"requests"` // totals of the routes
+	Routes          []RouteStats                      `json:"routes"`
+	Cache           []CacheStoreStats                 `json:"cache"`
+	RateLimit       []RateLimitStats                  `json:"rate_limit"`
+	WebSocket       map[string]interface{}            `json:"websocket,omitempty"` // absent when the hub is not initialized
+	SSE             map[string]int                    `json:"sse"`                 // clients by channel
+	Proxies         []ProxyStats                      `json:"proxies"`
+	CircuitBreakers map[string]map[string]interface{} `json:"circuit_breakers"`
+	Tracing         map[string]interface{}            `json:"tracing"`
+}
+
+// RouteStats counters of a route since the process started
+type RouteStats struct {
+	Method        string  `json:"method,omitempty"`
+	Route         string  `json:"route,omitempty"`
+	Requests      int64   `json:"requests"`
+	ClientErrors  int64   `json:"client_errors"` // 4xx
+	ServerErrors  int64   `json:"server_errors"` // 5xx
+	ErrorRate     float64 `json:"error_rate"`    // share of 5xx
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+
+	durationNanos int64
+}
+
+// CacheStoreStats statistics of a cache store created by @Cache
+type CacheStoreStats struct {
+	Backend string `json:"backend"` // memory, redis or custom
+	CacheStats
+}
+
+// routeCounter counters of a route, updated by RouteStatsMiddleware
+type routeCounter struct {
+	requests, clientErrors, serverErrors, durationNanos atomic.Int64
+}
+
+// counters by route and method
+var (
+	routeCounters      = make(map[string]map[string]*routeCounter)
+	routeCountersMutex sync.RWMutex
+)
+
+// routeCounterOf returns the counter of a route, created on its first request
+func routeCounterOf(method, route string) *routeCounter {
+	routeCountersMutex.RLock()
+	counter := routeCounters[route][method]
+	routeCountersMutex.RUnlock()
+	if counter != nil {
+		return counter
+	}
+
+	routeCountersMutex.Lock()
+	defer routeCountersMutex.Unlock()
+	if routeCounters[route] == nil {
+		routeCounters[route] = make(map[string]*routeCounter)
+	}
+	if routeCounters[route][method] == nil {
+		routeCounters[route][method] = &routeCounter{}
+	}
+	return routeCounters[route][method]
+}
+
+// RouteStatsMiddleware counts the requests, errors and duration of every route for StatsPath; requests
+// matching no route and the /decorators endpoints are not counted
+func RouteStatsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" || strings.HasPrefix(route, "/decorators/") {
+			return
+		}
+		counter := routeCounterOf(c.Request.Method, route)
+		counter.requests.Add(1)
+		counter.durationNanos.Add(int64(time.Since(start)))
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			counter.serverErrors.Add(1)
+		case status >= http.StatusBadRequest:
+			counter.clientErrors.Add(1)
+		}
+	}
+}
+
+// resetRouteStats forgets the route counters (tests)
+func resetRouteStats() {
+	routeCountersMutex.Lock()
+	defer routeCountersMutex.Unlock()
+	routeCounters = make(map[string]map[string]*routeCounter)
+}
+
+// routeStats returns the counters of the routes, sorted by route and method, and their totals
+func routeStats() ([]RouteStats, RouteStats) {
+	routes := []RouteStats{}
+	var total RouteStats
+	routeCountersMutex.RLock()
+	for route, methods := range routeCounters {
+		for method, counter := range methods {
+			stats := RouteStats{
+				Method:        method,
+				Route:         route,
+				Requests:      counter.requests.Load(),
+				ClientErrors:  counter.clientErrors.Load(),
+				ServerErrors:  counter.serverErrors.Load(),
+				durationNanos: counter.durationNanos.Load(),
+			}
+			stats.rates()
+			routes = append(routes, stats)
+
+			total.Requests += stats.Requests
+			total.ClientErrors += stats.ClientErrors
+			total.ServerErrors += stats.ServerErrors
+			total.durationNanos += stats.durationNanos
+		}
+	}
+	routeCountersMutex.RUnlock()
+	total.rates()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Route != routes[j].Route {
+			return routes[i].Route < routes[j].Route
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes, total
+}
+
+// rates derives the error rate and the average duration from the counters
+func (s *RouteStats) rates() {
+	if s.Requests == 0 {
+		return
+	}
+	s.ErrorRate = float64(s.ServerErrors) / float64(s.Requests)
+	s.AvgDurationMs = float64(s.durationNanos) / float64(s.Requests) / float64(time.Millisecond)
+}
+
+// cacheStoreStats statistics of the cache stores created by the middlewares
+func cacheStoreStats() []CacheStoreStats {
+	stores, _ := adminStores()
+	stats := make([]CacheStoreStats, 0, len(stores))
+	for _, store := range stores {
+		stats = append(stats, CacheStoreStats{Backend: cacheStoreBackend(store), CacheStats: store.Stats()})
+	}
+	return stats
+}
+
+// cacheStoreBackend name of the backend of a store, looking through encryption
+func cacheStoreBackend(store CacheStore) string {
+	switch s := store.(type) {
+	case *MemoryCache:
+		return "memory"
+	case *RedisCache:
+		return "redis"
+	case *EncryptedCacheStore:
+		return cacheStoreBackend(s.store)
+	default:
+		return "custom"
+	}
+}
+
+// CollectStats gathers the statistics of every subsystem in one document
+func CollectStats(ctx context.Context) StatsReport {
+	routes, total := routeStats()
+	return StatsReport{
+		StartedAt:       processStartedAt,
+		UptimeSeconds:   time.Since(processStartedAt).Seconds(),
+		Requests:        total,
+		Routes:          routes,
+		Cache:           cacheStoreStats(),
+		RateLimit:       RateLimitDecisionStats(),
+		WebSocket:       websocketStats(ctx),
+		SSE:             GetSSEBroker().Stats(),
+		Proxies:         ProxyManagerStats(),
+		CircuitBreakers: CircuitBreakerStats(),
+		Tracing:         tracingStats(),
+	}
+}
+
+// StatsHandler GET /decorators/stats
+func StatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, CollectStats(c.Request.Context()))
+}
+
+// statsCollector exposes the counters of StatsPath as Prometheus metrics; circuit breakers have their own
+// (deco_circuit_breaker_state)
+type statsCollector struct {
+	uptime, routeRequests, routeErrors   *prometheus.Desc
+	cacheHits, cacheMisses, cacheEntries *prometheus.Desc
+	rateLimitAllowed, rateLimitRejected  *prometheus.Desc
+	websocketConnections, sseClients     *prometheus.Desc
+	proxyRequests, proxyFailures         *prometheus.Desc
+}
+
+// newStatsCollector creates the collector of the StatsPath counters under namespace and subsystem
+func newStatsCollector(namespace, subsystem string) *statsCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+	}
+	return &statsCollector{
+		uptime:               desc("uptime_seconds", "Seconds since the process started"),
+		routeRequests:        desc("route_requests_total", "Requests served by route", "method", "route"),
+		routeErrors:          desc("route_errors_total", "Requests answered with an error by route and class", "method", "route", "class"),
+		cacheHits:            desc("cache_store_hits_total", "Hits of the cache stores by backend", "backend"),
+		cacheMisses:          desc("cache_store_misses_total", "Misses of the cache stores by backend", "backend"),
+		cacheEntries:         desc("cache_store_entries", "Entries of the cache stores by backend", "backend"),
+		rateLimitAllowed:     desc("rate_limit_allowed_total", "Requests allowed by the rate limiters by backend", "backend"),
+		rateLimitRejected:    desc("rate_limit_rejected_total", "Requests rejected by the rate limiters by backend", "backend"),
+		websocketConnections: desc("websocket_connections", "WebSocket connections of this instance"),
+		sseClients:           desc("sse_clients", "SSE clients by channel", "channel"),
+		proxyRequests:        desc("proxy_requests_total", "Requests forwarded by @Proxy upstream", "upstream"),
+		proxyFailures:        desc("proxy_failures_total", "Requests failed by @Proxy upstream", "upstream"),
+	}
+}
+
+// Describe sends the descriptors of the metrics
+func (s *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		s.uptime, s.routeRequests, s.routeErrors, s.cacheHits, s.cacheMisses, s.cacheEntries,
+		s.rateLimitAllowed, s.rateLimitRejected, s.websocketConnections, s.sseClients,
+		s.proxyRequests, s.proxyFailures,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect sends the current values of the counters; WebSocket connections are those of this instance
+func (s *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(s.uptime, prometheus.GaugeValue, time.Since(processStartedAt).Seconds())
+
+	routes, _ := routeStats()
+	for _, route := range routes {
+		ch <- prometheus.MustNewConstMetric(s.routeRequests, prometheus.CounterValue, float64(route.Requests), route.Method, route.Route)
+		ch <- prometheus.MustNewConstMetric(s.routeErrors, prometheus.CounterValue, float64(route.ClientErrors), route.Method, route.Route, "4xx")
+		ch <- prometheus.MustNewConstMetric(s.routeErrors, prometheus.CounterValue, float64(route.ServerErrors), route.Method, route.Route, "5xx")
+	}
+
+	// Stores of the same backend are summed, as they have no name of their own
+	caches := make(map[string]CacheStats)
+	for _, store := range cacheStoreStats() {
+		sum := caches[store.Backend]
+		sum.Hits += store.Hits
+		sum.Misses += store.Misses
+		sum.Size += store.Size
+		caches[store.Backend] = sum
+	}
+	for backend, stats := range caches {
+		ch <- prometheus.MustNewConstMetric(s.cacheHits, prometheus.CounterValue, float64(stats.Hits), backend)
+		ch <- prometheus.MustNewConstMetric(s.cacheMisses, prometheus.CounterValue, float64(stats.Misses), backend)
+		ch <- prometheus.MustNewConstMetric(s.cacheEntries, prometheus.GaugeValue, float64(stats.Size), backend)
+	}
+
+	for _, limiter := range RateLimitDecisionStats() {
+		ch <- prometheus.MustNewConstMetric(s.rateLimitAllowed, prometheus.CounterValue, float64(limiter.Allowed), limiter.Backend)
+		ch <- prometheus.MustNewConstMetric(s.rateLimitRejected, prometheus.CounterValue, float64(limiter.Rejected), limiter.Backend)
+	}
+
+	if hub := defaultHub; hub != nil {
+		hub.mu.RLock()
+		connections := len(hub.connections)
+		hub.mu.RUnlock()
+		ch <- prometheus.MustNewConstMetric(s.websocketConnections, prometheus.GaugeValue, float64(connections))
+	}
+	for channel, clients := range GetSSEBroker().Stats() {
+		ch <- prometheus.MustNewConstMetric(s.sseClients, prometheus.GaugeValue, float64(clients), channel)
+	}
+
+	// Upstreams proxied with different settings share their name
+	proxies := make(map[string]ProxyStats)
+	for _, proxy := range ProxyManagerStats() {
+		sum := proxies[proxy.Name]
+		sum.Requests += proxy.Requests
+		sum.Failures += proxy.Failures
+		proxies[proxy.Name] = sum
+	}
+	for name, stats := range proxies {
+		ch <- prometheus.MustNewConstMetric(s.proxyRequests, prometheus.CounterValue, float64(stats.Requests), name)
+		ch <- prometheus.MustNewConstMetric(s.proxyFailures, prometheus.CounterValue, float64(stats.Failures), name)
+	}
+}
@@ -0,0 +1,105 @@
+package decorators
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsTestRouter router counting its routes, with /users/:id answering the status of its id
+func statsTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RouteStatsMiddleware())
+	router.GET("/users/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "missing":
+			c.Status(http.StatusNotFound)
+		case "broken":
+			c.Status(http.StatusInternalServerError)
+		default:
+			c.Status(http.StatusOK)
+		}
+	})
+	router.GET(StatsPath, StatsHandler)
+	return router
+}
+
+func TestRouteStatsMiddleware(t *testing.T) {
+	resetRouteStats()
+	defer resetRouteStats()
+
+	router := statsTestRouter()
+	for _, target := range []string{"/users/1", "/users/2", "/users/missing", "/users/broken", "/unknown", StatsPath} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, http.NoBody))
+	}
+
+	routes, total := routeStats()
+	require.Len(t, routes, 1, "unmatched requests and the /decorators endpoints are not counted")
+	assert.Equal(t, "GET", routes[0].Method)
+	assert.Equal(t, "/users/:id", routes[0].Route)
+	assert.Equal(t, int64(4), routes[0].Requests)
+	assert.Equal(t, int64(1), routes[0].ClientErrors)
+	assert.Equal(t, int64(1), routes[0].ServerErrors)
+	assert.Equal(t, 0.25, routes[0].ErrorRate)
+	assert.Equal(t, int64(4), total.Requests)
+	assert.Empty(t, total.Route)
+}
+
+func TestStatsHandler(t *testing.T) {
+	resetRouteStats()
+	defer resetRouteStats()
+
+	router := statsTestRouter()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, StatsPath, http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	for _, section := range []string{"started_at", "uptime_seconds", "requests", "routes", "cache", "rate_limit", "sse", "proxies", "circuit_breakers", "tracing"} {
+		assert.Contains(t, report, section)
+	}
+	assert.Equal(t, float64(1), report["requests"].(map[string]interface{})["requests"])
+	assert.Greater(t, report["uptime_seconds"], 0.0)
+}
+
+func TestCacheStoreBackend(t *testing.T) {
+	assert.Equal(t, "memory", cacheStoreBackend(NewMemoryCache(10)))
+	assert.Equal(t, "memory", cacheStoreBackend(&EncryptedCacheStore{store: NewMemoryCache(10)}))
+}
+
+func TestStatsCollector(t *testing.T) {
+	resetRouteStats()
+	defer resetRouteStats()
+
+	router := statsTestRouter()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/broken", http.NoBody))
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(newStatsCollector("deco", "")))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := ""
+			for _, label := range metric.GetLabel() {
+				labels += label.GetName() + "=" + label.GetValue() + ","
+			}
+			values[family.GetName()+"{"+labels+"}"] = metric.GetCounter().GetValue() + metric.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, float64(1), values["deco_route_requests_total{method=GET,route=/users/:id,}"])
+	assert.Equal(t, float64(1), values["deco_route_errors_total{class=5xx,method=GET,route=/users/:id,}"])
+	assert.Contains(t, values, "deco_uptime_seconds{}")
+}
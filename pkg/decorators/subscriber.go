@@ -0,0 +1,252 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Subscription defaults of @Subscribe
+const (
+	defaultSubscribeConcurrency = 1
+	defaultSubscribeRetries     = 3
+	defaultSubscribeBackoff     = time.Second
+)
+
+// Message message delivered to a @Subscribe handler
+type Message struct {
+	Topic   string
+	Key     string
+	Payload []byte
+	Headers map[string]string
+	Attempt int // 1 on the first delivery to the handler, incremented on each retry
+}
+
+// MessageHandler handler of a @Subscribe function
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+// Subscriber broker adapter (Kafka, NATS, ...) used to consume @Subscribe topics.
+// Subscribe blocks until ctx is cancelled, calling deliver for each message of topic
+// in the consumer group; a non-nil error from deliver means the message was not handled
+// and should be redelivered by the broker.
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic, group string, deliver func(ctx context.Context, msg *Message) error) error
+}
+
+// SubscriptionEntry consumer registered by the generated code for a @Subscribe function
+type SubscriptionEntry struct {
+	Topic       string
+	Group       string
+	Concurrency int           // consumers started in the group
+	Retries     int           // in-process retries before dead-lettering
+	Backoff     time.Duration // first retry delay, doubled per retry
+	DLQ         string        // dead letter topic; empty returns the error to the broker
+	Handler     MessageHandler
+	FuncName    string
+	PackageName string
+}
+
+// subscription registry and running consumers
+var (
+	subscriptions       []*SubscriptionEntry
+	subscriber          Subscriber
+	deadLetterPublisher Publisher
+	subscriptionsCancel context.CancelFunc
+	subscriptionsDone   sync.WaitGroup
+	subscriptionsMutex  sync.Mutex
+)
+
+// RegisterSubscription registers a consumer of a topic
+func RegisterSubscription(entry *SubscriptionEntry) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptions = append(subscriptions, entry)
+	LogVerbose("Subscription registrada: %s (%s) -> %s", entry.Topic, entry.Group, entry.FuncName)
+}
+
+// GetSubscriptions returns the registered consumers
+func GetSubscriptions() []*SubscriptionEntry {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	return append([]*SubscriptionEntry(nil), subscriptions...)
+}
+
+// UseSubscriber sets the broker adapter of @Subscribe consumers and the publisher of dead letters
+// (nil disables dead-lettering). Call it before Default so consumers start with the engine.
+func UseSubscriber(s Subscriber, deadLetters Publisher) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriber = s
+	deadLetterPublisher = deadLetters
+}
+
+// StartSubscriptions starts the registered consumers; it is called by Default
+func StartSubscriptions(ctx context.Context) error {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	if len(subscriptions) == 0 || subscriptionsCancel != nil {
+		return nil
+	}
+	if subscriber == nil {
+		return fmt.Errorf("%d @Subscribe consumers registered but no subscriber configured (use UseSubscriber)", len(subscriptions))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	subscriptionsCancel = cancel
+	for _, entry := range subscriptions {
+		consumer := &subscriptionConsumer{entry: entry, deadLetters: deadLetterPublisher}
+		for i := 0; i < max(entry.Concurrency, 1); i++ {
+			subscriptionsDone.Add(1)
+			go func(s Subscriber) {
+				defer subscriptionsDone.Done()
+				if err := s.Subscribe(ctx, entry.Topic, entry.Group, consumer.deliver); err != nil && ctx.Err() == nil {
+					LogNormal("❌ Subscription %s (%s) stopped: %v", entry.Topic, entry.Group, err)
+				}
+			}(subscriber)
+		}
+	}
+
+	LogNormal("Started %d subscriptions", len(subscriptions))
+	return nil
+}
+
+// StopSubscriptions stops the consumers and waits for in-flight messages
+func StopSubscriptions() {
+	subscriptionsMutex.Lock()
+	cancel := subscriptionsCancel
+	subscriptionsCancel = nil
+	subscriptionsMutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		subscriptionsDone.Wait()
+	}
+}
+
+// subscriptionConsumer applies retry, dead-lettering and metrics around a handler
+type subscriptionConsumer struct {
+	entry       *SubscriptionEntry
+	deadLetters Publisher
+}
+
+// deliver handles one message, retrying in-process before dead-lettering it
+func (s *subscriptionConsumer) deliver(ctx context.Context, msg *Message) error {
+	start := time.Now()
+	backoff := s.entry.Backoff
+	ctx = ExtractContext(ctx, propagation.MapCarrier(msg.Headers))
+
+	var err error
+	for attempt := 1; attempt <= s.entry.Retries+1; attempt++ {
+		msg.Attempt = attempt
+		if err = s.entry.Handler(ctx, msg); err == nil {
+			RecordSubscriberMessage(s.entry.Topic, s.entry.Group, "success", time.Since(start))
+			return nil
+		}
+		if attempt > s.entry.Retries {
+			break
+		}
+
+		RecordSubscriberMessage(s.entry.Topic, s.entry.Group, "retry", 0)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	if s.entry.DLQ == "" || s.deadLetters == nil {
+		RecordSubscriberMessage(s.entry.Topic, s.entry.Group, "failed", time.Since(start))
+		return err
+	}
+
+	headers := map[string]string{
+		"x-deco-original-topic": msg.Topic,
+		"x-deco-error":          err.Error(),
+		"x-deco-attempts":       strconv.Itoa(msg.Attempt),
+	}
+	for name, value := range msg.Headers {
+		if _, reserved := headers[name]; !reserved {
+			headers[name] = value
+		}
+	}
+	if dlqErr := s.deadLetters.Publish(ctx, &OutboxEvent{Topic: s.entry.DLQ, Key: msg.Key, Payload: msg.Payload, Headers: headers}); dlqErr != nil {
+		RecordSubscriberMessage(s.entry.Topic, s.entry.Group, "failed", time.Since(start))
+		return fmt.Errorf("dead letter to %s failed: %v (handler error: %v)", s.entry.DLQ, dlqErr, err)
+	}
+
+	LogSilent("⚠️  Message of %s moved to %s after %d attempts: %v", msg.Topic, s.entry.DLQ, msg.Attempt, err)
+	RecordSubscriberMessage(s.entry.Topic, s.entry.Group, "dead_letter", time.Since(start))
+	return nil
+}
+
+// SubscriptionInfo @Subscribe configuration extracted at generation time
+type SubscriptionInfo struct {
+	Topic       string        `json:"topic"`
+	Group       string        `json:"group"`
+	Concurrency int           `json:"concurrency"`
+	Retries     int           `json:"retries"`
+	Backoff     time.Duration `json:"backoff"`
+	DLQ         string        `json:"dlq,omitempty"`
+}
+
+// parseSubscribeArgs parses @Subscribe(topic="user.created", group="emailer", concurrency=4, retries=3, backoff="1s", dlq="user.created.dlq")
+func parseSubscribeArgs(args []string) (*SubscriptionInfo, error) {
+	info := &SubscriptionInfo{
+		Concurrency: defaultSubscribeConcurrency,
+		Retries:     defaultSubscribeRetries,
+		Backoff:     defaultSubscribeBackoff,
+	}
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(strings.TrimSpace(arg), "=")
+		if !found {
+			return nil, fmt.Errorf("@Subscribe: expected key=value, found '%s'", arg)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "topic":
+			info.Topic = value
+		case "group":
+			info.Group = value
+		case "dlq":
+			info.DLQ = value
+		case "concurrency", "retries":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || (key == "concurrency" && n == 0) {
+				return nil, fmt.Errorf("@Subscribe: invalid %s '%s'", key, value)
+			}
+			if key == "concurrency" {
+				info.Concurrency = n
+			} else {
+				info.Retries = n
+			}
+		case "backoff":
+			backoff, err := ParseDurationLiteral(value)
+			if err != nil || backoff < 0 {
+				return nil, fmt.Errorf("@Subscribe: invalid backoff '%s'", value)
+			}
+			info.Backoff = backoff
+		default:
+			return nil, fmt.Errorf("@Subscribe: unknown argument '%s' (valid: topic, group, concurrency, retries, backoff, dlq)", key)
+		}
+	}
+
+	if info.Topic == "" {
+		return nil, fmt.Errorf("@Subscribe requires a topic, e.g. topic=\"user.created\"")
+	}
+	if info.Group == "" {
+		return nil, fmt.Errorf("@Subscribe requires a consumer group, e.g. group=\"emailer\"")
+	}
+	return info, nil
+}
@@ -0,0 +1,200 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubscribeArgs(t *testing.T) {
+	info, err := parseSubscribeArgs([]string{`topic="user.created"`, `group="emailer"`, "concurrency=4", "retries=5", `backoff="250ms"`, `dlq="user.created.dlq"`})
+	require.NoError(t, err)
+	assert.Equal(t, &SubscriptionInfo{
+		Topic: "user.created", Group: "emailer", Concurrency: 4, Retries: 5,
+		Backoff: 250 * time.Millisecond, DLQ: "user.created.dlq",
+	}, info)
+
+	info, err = parseSubscribeArgs([]string{"topic=orders", "group=billing"})
+	require.NoError(t, err)
+	assert.Equal(t, defaultSubscribeConcurrency, info.Concurrency)
+	assert.Equal(t, defaultSubscribeRetries, info.Retries)
+
+	for _, args := range [][]string{
+		nil,
+		{"topic=orders"},
+		{"group=billing"},
+		{"topic=orders", "group=billing", "concurrency=0"},
+		{"topic=orders", "group=billing", "retries=-1"},
+		{"topic=orders", "group=billing", "backoff=soon"},
+		{"topic=orders", "group=billing", "partition=1"},
+	} {
+		_, err := parseSubscribeArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestSubscriptionConsumer_RetriesThenDeadLetters(t *testing.T) {
+	calls := 0
+	var deadLetter *OutboxEvent
+	consumer := &subscriptionConsumer{
+		entry: &SubscriptionEntry{
+			Topic: "user.created", Group: "emailer", Retries: 2, Backoff: time.Millisecond, DLQ: "user.created.dlq",
+			Handler: func(context.Context, *Message) error {
+				calls++
+				return errors.New("smtp down")
+			},
+		},
+		deadLetters: PublisherFunc(func(_ context.Context, event *OutboxEvent) error {
+			deadLetter = event
+			return nil
+		}),
+	}
+
+	err := consumer.deliver(context.Background(), &Message{Topic: "user.created", Key: "42", Payload: []byte(`{}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.NotNil(t, deadLetter)
+	assert.Equal(t, "user.created.dlq", deadLetter.Topic)
+	assert.Equal(t, "42", deadLetter.Key)
+	assert.Equal(t, "smtp down", deadLetter.Headers["x-deco-error"])
+	assert.Equal(t, "3", deadLetter.Headers["x-deco-attempts"])
+}
+
+func TestSubscriptionConsumer_WithoutDLQReturnsError(t *testing.T) {
+	consumer := &subscriptionConsumer{entry: &SubscriptionEntry{
+		Topic: "orders", Group: "billing",
+		Handler: func(_ context.Context, msg *Message) error {
+			if msg.Attempt == 1 {
+				return nil
+			}
+			return errors.New("unexpected retry")
+		},
+	}}
+	assert.NoError(t, consumer.deliver(context.Background(), &Message{Topic: "orders"}))
+
+	consumer.entry.Handler = func(context.Context, *Message) error { return errors.New("invalid payload") }
+	assert.EqualError(t, consumer.deliver(context.Background(), &Message{Topic: "orders"}), "invalid payload")
+}
+
+// fakeSubscriber delivers the queued messages of each topic once per Subscribe call
+type fakeSubscriber struct {
+	mu       sync.Mutex
+	calls    int
+	messages map[string][]*Message
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, topic, _ string, deliver func(context.Context, *Message) error) error {
+	f.mu.Lock()
+	f.calls++
+	messages := f.messages[topic]
+	f.messages[topic] = nil
+	f.mu.Unlock()
+
+	for _, msg := range messages {
+		_ = deliver(ctx, msg)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStartSubscriptions(t *testing.T) {
+	savedSubscriptions := subscriptions
+	defer func() {
+		StopSubscriptions()
+		UseSubscriber(nil, nil)
+		subscriptions = savedSubscriptions
+	}()
+	subscriptions = nil
+
+	received := make(chan string, 1)
+	RegisterSubscription(&SubscriptionEntry{
+		Topic: "user.created", Group: "emailer", Concurrency: 2, FuncName: "SendWelcome",
+		Handler: func(_ context.Context, msg *Message) error {
+			received <- string(msg.Payload)
+			return nil
+		},
+	})
+
+	// Consumers without a broker adapter are reported instead of silently ignored
+	assert.Error(t, StartSubscriptions(context.Background()))
+
+	fake := &fakeSubscriber{messages: map[string][]*Message{"user.created": {{Topic: "user.created", Payload: []byte("ana")}}}}
+	UseSubscriber(fake, nil)
+	require.NoError(t, StartSubscriptions(context.Background()))
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, "ana", payload)
+	case <-time.After(time.Second):
+		t.Fatal("message was not consumed")
+	}
+
+	StopSubscriptions()
+	assert.Equal(t, 2, fake.calls)
+}
+
+func TestParseDirectory_Subscribe(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "context"
+
+// @Subscribe(topic="user.created", group="emailer", retries=1, dlq="user.created.dlq")
+func SendWelcome(ctx context.Context, msg *Message) error { return nil }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "consumers.go"), []byte(source), 0o600))
+
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Empty(t, routes[0].Method)
+	require.NotNil(t, routes[0].Subscription)
+	assert.Equal(t, "user.created", routes[0].Subscription.Topic)
+	assert.Equal(t, 1, routes[0].Subscription.Retries)
+
+	// Consumers are not HTTP handlers
+	source = `package handlers
+
+import "context"
+
+// @Route("POST", "/users")
+// @Subscribe(topic="user.created", group="emailer")
+func SendWelcome(ctx context.Context, msg *Message) error { return nil }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "consumers.go"), []byte(source), 0o600))
+	_, err = ParseDirectory(dir)
+	assert.Error(t, err)
+}
+
+func TestGenerateInitFile_Subscribe(t *testing.T) {
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import "context"
+
+// @Subscribe(topic="user.created", group="emailer", backoff="2s")
+func SendWelcome(ctx context.Context, msg *Message) error { return nil }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "consumers.go"), []byte(source), 0o600))
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", nil))
+
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), "decorators.RegisterSubscription(&decorators.SubscriptionEntry{")
+	assert.Contains(t, string(generated), `Topic:       "user.created"`)
+	assert.Contains(t, string(generated), "Backoff:     2000000000, // 2s")
+	assert.Contains(t, string(generated), "Handler:     SendWelcome,")
+}
@@ -112,6 +112,19 @@ func (tm *TelemetryManager) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ShutdownTelemetry flushes the spans still buffered and stops the exporter of the global manager
+func ShutdownTelemetry(ctx context.Context) error {
+	telemetryMutex.Lock()
+	manager := defaultTelemetryManager
+	defaultTelemetryManager = nil
+	telemetryMutex.Unlock()
+
+	if manager == nil {
+		return nil
+	}
+	return manager.Shutdown(ctx)
+}
+
 // TracingMiddleware main tracing middleware
 func TracingMiddleware(config *TelemetryConfig) gin.HandlerFunc {
 	if !config.Enabled {
@@ -162,10 +175,11 @@ func TracingMiddleware(config *TelemetryConfig) gin.HandlerFunc {
 		ctx, span := manager.tracer.Start(ctx, spanName)
 		defer span.End()
 
-		// Add atributos ao span
+		// Add atributos ao span; values that may carry personal data go through the privacy policy
+		policy := GetPIIPolicy()
 		span.SetAttributes(
 			semconv.HTTPMethod(c.Request.Method),
-			semconv.HTTPTarget(c.Request.URL.Path),
+			semconv.HTTPTarget(policy.ScrubString(c.Request.URL.Path)),
 			semconv.HTTPRoute(c.FullPath()),
 			semconv.HTTPScheme(c.Request.URL.Scheme),
 			attribute.String("http.host", c.Request.Host),
@@ -179,7 +193,16 @@ func TracingMiddleware(config *TelemetryConfig) gin.HandlerFunc {
 		}
 
 		if userID := c.GetString("user_id"); userID != "" {
-			span.SetAttributes(attribute.String("user.id", userID))
+			span.SetAttributes(attribute.String("user.id", policy.ScrubField("user_id", userID)))
+		}
+		if identity, ok := GetRouteIdentity(c); ok {
+			span.SetAttributes(identity.spanAttributes()...)
+		}
+
+		// Bodies come from the shared capture layer, redacted
+		var capture *BodyCapture
+		if config.RecordBodies {
+			capture = CaptureBodies(c)
 		}
 
 		// Update context in request
@@ -196,6 +219,12 @@ func TracingMiddleware(config *TelemetryConfig) gin.HandlerFunc {
 			semconv.HTTPStatusCode(c.Writer.Status()),
 			attribute.Int("http.response.size", c.Writer.Size()),
 		)
+		if capture != nil {
+			span.SetAttributes(
+				attribute.String("http.request.body", string(capture.RedactedRequestBody())),
+				attribute.String("http.response.body", string(capture.RedactedResponseBody())),
+			)
+		}
 
 		// Define span status based on HTTP code
 		if c.Writer.Status() >= 400 {
@@ -372,24 +401,28 @@ func GetTracingInfo(config *TelemetryConfig) TracingInfo {
 // TracingStatsHandler handler for tracing statistics
 func TracingStatsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		stats := map[string]interface{}{
-			"enabled": false,
-		}
-
-		if defaultTelemetryManager != nil {
-			stats["enabled"] = defaultTelemetryManager.config.Enabled
-			stats["service_name"] = defaultTelemetryManager.config.ServiceName
-			stats["service_version"] = defaultTelemetryManager.config.ServiceVersion
-			stats["environment"] = defaultTelemetryManager.config.Environment
-			stats["sample_rate"] = defaultTelemetryManager.config.SampleRate
-		}
-
 		c.JSON(http.StatusOK, gin.H{
-			"tracing_stats": stats,
+			"tracing_stats": tracingStats(),
 		})
 	}
 }
 
+// tracingStats settings of the tracer in use
+func tracingStats() map[string]interface{} {
+	stats := map[string]interface{}{
+		"enabled": false,
+	}
+
+	if defaultTelemetryManager != nil {
+		stats["enabled"] = defaultTelemetryManager.config.Enabled
+		stats["service_name"] = defaultTelemetryManager.config.ServiceName
+		stats["service_version"] = defaultTelemetryManager.config.ServiceVersion
+		stats["environment"] = defaultTelemetryManager.config.Environment
+		stats["sample_rate"] = defaultTelemetryManager.config.SampleRate
+	}
+	return stats
+}
+
 // createTelemetryMiddleware creates telemetry middleware with customizable settings via args
 func createTelemetryMiddleware(args []string) gin.HandlerFunc {
 	config := DefaultConfig().Telemetry
@@ -0,0 +1,90 @@
+package decorators
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// event log records are written to this logger; nil uses slog.Default()
+var (
+	eventLogger      *slog.Logger
+	eventLoggerMutex sync.RWMutex
+)
+
+// SetEventLogger sets the logger receiving the records of Event. Plug an OpenTelemetry logs
+// bridge (e.g. otelslog.NewLogger) to export them over OTLP; nil restores slog.Default().
+func SetEventLogger(logger *slog.Logger) {
+	eventLoggerMutex.Lock()
+	defer eventLoggerMutex.Unlock()
+	eventLogger = logger
+}
+
+// Event records an application event on the active span of the request and emits a log
+// record with the same attributes, correlated to the trace by trace_id and span_id.
+//
+//	deco.Event(c, "order.placed", map[string]interface{}{"order.id": id, "order.total": total})
+func Event(c *gin.Context, name string, attrs map[string]interface{}) {
+	ctx := c.Request.Context()
+
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	spanAttrs := make([]attribute.KeyValue, 0, len(attrs))
+	logAttrs := make([]slog.Attr, 0, len(attrs)+3)
+	for _, key := range keys {
+		spanAttrs = append(spanAttrs, eventAttribute(key, attrs[key]))
+		logAttrs = append(logAttrs, slog.Any(key, attrs[key]))
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		span.AddEvent(name, trace.WithAttributes(spanAttrs...))
+	}
+
+	logAttrs = append(logAttrs, slog.String("event.name", name))
+	if spanContext := span.SpanContext(); spanContext.IsValid() {
+		logAttrs = append(logAttrs,
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	eventLoggerMutex.RLock()
+	logger := eventLogger
+	eventLoggerMutex.RUnlock()
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, name, logAttrs...)
+}
+
+// eventAttribute converts an event attribute to its span attribute
+func eventAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
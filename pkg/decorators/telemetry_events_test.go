@@ -0,0 +1,64 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEvent(t *testing.T) {
+	var logs bytes.Buffer
+	SetEventLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+	defer SetEventLogger(nil)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx, span := tracer.Start(context.Background(), "POST /orders")
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders", nil).WithContext(ctx)
+
+	Event(c, "order.placed", map[string]interface{}{"order.id": "o-1", "order.total": 42.5, "order.items": 3})
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events(), 1)
+	event := spans[0].Events()[0]
+	assert.Equal(t, "order.placed", event.Name)
+	assert.Contains(t, event.Attributes, attribute.String("order.id", "o-1"))
+	assert.Contains(t, event.Attributes, attribute.Int("order.items", 3))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "order.placed", record["msg"])
+	assert.Equal(t, "o-1", record["order.id"])
+	assert.Equal(t, 42.5, record["order.total"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), record["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record["span_id"])
+}
+
+func TestEvent_WithoutSpan(t *testing.T) {
+	var logs bytes.Buffer
+	SetEventLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+	defer SetEventLogger(nil)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	Event(c, "cache.warmed", nil)
+
+	assert.Contains(t, logs.String(), `"event.name":"cache.warmed"`)
+	assert.NotContains(t, logs.String(), "trace_id")
+}
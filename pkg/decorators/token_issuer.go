@@ -0,0 +1,313 @@
+package decorators
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Access and refresh token issuance (auth.tokens) for apps using deco as their auth layer. Access tokens
+// are verified by @Auth; refresh tokens are only accepted by RefreshTokens, which rotates them.
+
+// refreshTokenUse value of the "token_use" claim of refresh tokens, rejected by @Auth
+const refreshTokenUse = "refresh"
+
+// TokenPair tokens returned by the token endpoint of the app
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // seconds until the access token expires
+}
+
+// TokenIssuer signs access and refresh tokens with the HMAC secret of auth.jwt or a private key
+type TokenIssuer struct {
+	config     AuthConfig
+	alg        string
+	key        crypto.Signer // nil signs with the HMAC secret
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	verifier   *JWTVerifier
+	now        func() time.Time
+}
+
+var (
+	tokenIssuer      *TokenIssuer
+	tokenIssuerMutex sync.RWMutex
+)
+
+// NewTokenIssuer builds an issuer from auth.tokens; the private key is read now, the HMAC secret on use
+func NewTokenIssuer(config AuthConfig) (*TokenIssuer, error) {
+	if err := config.Tokens.validate(); err != nil {
+		return nil, err
+	}
+	issuer := &TokenIssuer{config: config, alg: "HS256", accessTTL: 15 * time.Minute, refreshTTL: 720 * time.Hour, now: time.Now}
+	if config.Tokens.AccessTTL != "" {
+		issuer.accessTTL, _ = ParseDurationLiteral(config.Tokens.AccessTTL)
+	}
+	if config.Tokens.RefreshTTL != "" {
+		issuer.refreshTTL, _ = ParseDurationLiteral(config.Tokens.RefreshTTL)
+	}
+
+	verification := JWTConfig{SecretKey: config.JWT.SecretKey, Issuer: config.JWT.Issuer, Audience: config.JWT.Audience, Leeway: config.JWT.Leeway}
+	if config.Tokens.PrivateKey != "" {
+		key, alg, err := loadPrivateKey(config.Tokens.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		issuer.key, issuer.alg = key, alg
+		verification.SecretKey = ""
+	} else if config.JWT.SecretKey == "" {
+		return nil, fmt.Errorf("auth.tokens needs auth.jwt.secret_key or auth.tokens.private_key to sign tokens")
+	}
+
+	verification.Algorithms = []string{issuer.alg}
+	verifier, err := NewJWTVerifier(verification)
+	if err != nil {
+		return nil, err
+	}
+	if issuer.key != nil {
+		verifier.keys = []jwtKey{issuer.publicKey()}
+	}
+	issuer.verifier = verifier
+	return issuer, nil
+}
+
+// loadPrivateKey reads the signing key of a PEM file and picks its algorithm
+func loadPrivateKey(file string) (crypto.Signer, string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("auth.tokens.private_key: %v", err)
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, "", fmt.Errorf("auth.tokens.private_key: %s: no private key found", file)
+		}
+		var key interface{}
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("auth.tokens.private_key: %s: %v", file, err)
+		}
+
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, "RS256", nil
+		case *ecdsa.PrivateKey:
+			switch k.Curve {
+			case elliptic.P256():
+				return k, "ES256", nil
+			case elliptic.P384():
+				return k, "ES384", nil
+			case elliptic.P521():
+				return k, "ES512", nil
+			}
+			return nil, "", fmt.Errorf("auth.tokens.private_key: %s: unsupported curve %s", file, k.Curve.Params().Name)
+		case ed25519.PrivateKey:
+			return k, "EdDSA", nil
+		}
+		return nil, "", fmt.Errorf("auth.tokens.private_key: %s: unsupported key type %T", file, key)
+	}
+}
+
+// publicKey verification key of the private key
+func (t *TokenIssuer) publicKey() jwtKey {
+	return jwtKey{alg: t.alg, key: t.key.Public()}
+}
+
+// Issue signs an access and a refresh token for the subject; claims are added to both (roles, scope...)
+func (t *TokenIssuer) Issue(ctx context.Context, subject string, claims map[string]interface{}) (TokenPair, error) {
+	if subject == "" {
+		return TokenPair{}, fmt.Errorf("subject is required")
+	}
+	now := t.now()
+
+	access, err := t.sign(ctx, t.claims(subject, claims, now, t.accessTTL))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refreshClaims := t.claims(subject, claims, now, t.refreshTTL)
+	refreshClaims["token_use"] = refreshTokenUse
+	refresh, err := t.sign(ctx, refreshClaims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{AccessToken: access, RefreshToken: refresh, TokenType: "Bearer", ExpiresIn: int(t.accessTTL / time.Second)}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair with the same subject and claims; the old refresh
+// token is revoked when a TokenRevocationStore is installed, so it is accepted once
+func (t *TokenIssuer) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	claims, err := t.verifier.Verify(ctx, refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if claims.String("token_use") != refreshTokenUse {
+		return TokenPair{}, fmt.Errorf("%w: not a refresh token", ErrInvalidToken)
+	}
+
+	if list := GetRevocationList(); list != nil {
+		revoked, err := list.IsRevoked(ctx, claims)
+		if err != nil {
+			return TokenPair{}, fmt.Errorf("revocation list unavailable: %w", err)
+		}
+		if revoked {
+			return TokenPair{}, fmt.Errorf("%w: token revoked", ErrInvalidToken)
+		}
+		if store, ok := list.(TokenRevocationStore); ok {
+			expires, _, _ := claims.time("exp")
+			if err := store.RevokeToken(ctx, TokenID(claims), expires); err != nil {
+				return TokenPair{}, fmt.Errorf("revocation list unavailable: %w", err)
+			}
+		}
+	}
+
+	custom := make(map[string]interface{})
+	for name, value := range claims {
+		switch name {
+		case "iss", "aud", "sub", "iat", "nbf", "exp", "jti", "token_use":
+		default:
+			custom[name] = value
+		}
+	}
+	return t.Issue(ctx, claims.Subject(), custom)
+}
+
+// claims registered claims of a token issued now, with the custom ones
+func (t *TokenIssuer) claims(subject string, custom map[string]interface{}, now time.Time, ttl time.Duration) map[string]interface{} {
+	claims := make(map[string]interface{}, len(custom)+6)
+	for name, value := range custom {
+		claims[name] = value
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	claims["sub"] = subject
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	claims["jti"] = hex.EncodeToString(id)
+	if t.config.JWT.Issuer != "" {
+		claims["iss"] = t.config.JWT.Issuer
+	}
+	if len(t.config.JWT.Audience) == 1 {
+		claims["aud"] = t.config.JWT.Audience[0]
+	} else if len(t.config.JWT.Audience) > 1 {
+		claims["aud"] = t.config.JWT.Audience
+	}
+	return claims
+}
+
+// sign serializes the claims as a JWS compact token
+func (t *TokenIssuer) sign(ctx context.Context, claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": t.alg, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("error encoding claims: %v", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	algorithm := jwtAlgorithms[t.alg]
+
+	var signature []byte
+	switch key := t.key.(type) {
+	case nil:
+		secret, err := t.verifier.hmacSecret(ctx)
+		if err != nil {
+			return "", err
+		}
+		mac := hmac.New(algorithm.hash.New, secret)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(input))
+	default:
+		hasher := algorithm.hash.New()
+		hasher.Write([]byte(input))
+		digest := hasher.Sum(nil)
+		if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+			r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest)
+			if err != nil {
+				return "", err
+			}
+			size := (ecKey.Curve.Params().BitSize + 7) / 8
+			signature = make([]byte, 2*size)
+			r.FillBytes(signature[:size])
+			s.FillBytes(signature[size:])
+		} else if signature, err = key.Sign(rand.Reader, digest, algorithm.hash); err != nil {
+			return "", err
+		}
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// GetTokenIssuer returns the issuer configured by ConfigureAuth, nil when auth.tokens cannot sign
+func GetTokenIssuer() *TokenIssuer {
+	tokenIssuerMutex.RLock()
+	defer tokenIssuerMutex.RUnlock()
+	return tokenIssuer
+}
+
+// IssueTokens signs an access and a refresh token for the subject with the configured issuer
+func IssueTokens(ctx context.Context, subject string, claims map[string]interface{}) (TokenPair, error) {
+	issuer := GetTokenIssuer()
+	if issuer == nil {
+		return TokenPair{}, fmt.Errorf("token issuance needs auth.jwt.secret_key or auth.tokens.private_key")
+	}
+	return issuer.Issue(ctx, subject, claims)
+}
+
+// RefreshTokens exchanges a refresh token for a new pair with the configured issuer
+func RefreshTokens(ctx context.Context, refreshToken string) (TokenPair, error) {
+	issuer := GetTokenIssuer()
+	if issuer == nil {
+		return TokenPair{}, fmt.Errorf("token issuance needs auth.jwt.secret_key or auth.tokens.private_key")
+	}
+	return issuer.Refresh(ctx, refreshToken)
+}
+
+// signs reports whether the configuration has a signing key for the issuer
+func (t TokenIssuerConfig) signs(jwt JWTConfig) bool {
+	return t.PrivateKey != "" || jwt.SecretKey != ""
+}
+
+// validate checks the token issuance configuration
+func (t TokenIssuerConfig) validate() error {
+	if t.AccessTTL != "" {
+		if ttl, err := ParseDurationLiteral(t.AccessTTL); err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid auth.tokens.access_ttl '%s'", t.AccessTTL)
+		}
+	}
+	if t.RefreshTTL != "" {
+		if ttl, err := ParseDurationLiteral(t.RefreshTTL); err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid auth.tokens.refresh_ttl '%s'", t.RefreshTTL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package decorators
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIssuerHMAC(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	defer UseRevocationList(nil)
+	config := AuthConfig{
+		JWT:    JWTConfig{SecretKey: "jwt-secret", Issuer: "https://auth.example.com", Audience: []string{"api"}},
+		Tokens: TokenIssuerConfig{AccessTTL: "5m"},
+	}
+	require.NoError(t, ConfigureAuth(config))
+	defer func() { _ = ConfigureAuth(AuthConfig{}) }()
+	ClearAuthCache()
+
+	ctx := context.Background()
+	pair, err := IssueTokens(ctx, "42", map[string]interface{}{"roles": []string{"admin"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", pair.TokenType)
+	assert.Equal(t, 300, pair.ExpiresIn)
+
+	claims, err := verifyBearerToken(ctx, GetJWTVerifier(), pair.AccessToken, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "42", claims.Subject())
+	assert.Equal(t, "https://auth.example.com", claims.String("iss"))
+	assert.Equal(t, []string{"admin"}, claims.Strings("roles"))
+	assert.NotEmpty(t, claims.String("jti"))
+
+	_, err = verifyBearerToken(ctx, GetJWTVerifier(), pair.RefreshToken, 0)
+	assert.ErrorIs(t, err, ErrInvalidToken, "@Auth rejects refresh tokens")
+	_, err = RefreshTokens(ctx, pair.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken, "access tokens cannot be refreshed")
+
+	// Refresh tokens rotate: the old one is accepted once
+	UseRevocationList(NewMemoryRevocationList())
+	refreshed, err := RefreshTokens(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	claims, err = verifyBearerToken(ctx, GetJWTVerifier(), refreshed.AccessToken, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, claims.Strings("roles"), "custom claims are kept")
+	_, err = RefreshTokens(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = IssueTokens(ctx, "", nil)
+	assert.Error(t, err)
+}
+
+func TestTokenIssuerPrivateKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for alg, key := range map[string]interface{}{"RS256": rsaKey, "ES384": ecKey, "EdDSA": edKey} {
+		t.Run(alg, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			require.NoError(t, err)
+			file := filepath.Join(t.TempDir(), "signing.pem")
+			require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+			// Without auth.jwt keys, @Auth accepts the tokens of the issuer's own key
+			require.NoError(t, ConfigureAuth(AuthConfig{Tokens: TokenIssuerConfig{PrivateKey: file}}))
+			defer func() { _ = ConfigureAuth(AuthConfig{}) }()
+			assert.Equal(t, alg, GetTokenIssuer().alg)
+
+			pair, err := IssueTokens(context.Background(), "7", nil)
+			require.NoError(t, err)
+			claims, err := verifyBearerToken(context.Background(), GetJWTVerifier(), pair.AccessToken, 0)
+			require.NoError(t, err)
+			assert.Equal(t, "7", claims.Subject())
+		})
+	}
+
+	_, err = NewTokenIssuer(AuthConfig{})
+	assert.Error(t, err, "nothing to sign with")
+	_, err = NewTokenIssuer(AuthConfig{JWT: JWTConfig{SecretKey: "s"}, Tokens: TokenIssuerConfig{RefreshTTL: "never"}})
+	assert.Error(t, err)
+}
+
+func TestTokenIssuerExpiry(t *testing.T) {
+	UseSecretProvider(staticSecretProvider{"jwt-secret": []byte("s3cr3t")})
+	defer UseSecretProvider(nil)
+	issuer, err := NewTokenIssuer(AuthConfig{JWT: JWTConfig{SecretKey: "jwt-secret"}})
+	require.NoError(t, err)
+	issuer.now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+	pair, err := issuer.Issue(context.Background(), "42", nil)
+	require.NoError(t, err)
+	_, err = issuer.verifier.Verify(context.Background(), pair.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken, "the access token lives 15 minutes by default")
+	_, err = issuer.Refresh(context.Background(), pair.RefreshToken)
+	assert.NoError(t, err, "the refresh token lives 30 days")
+}
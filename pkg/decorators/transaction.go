@@ -0,0 +1,253 @@
+package decorators
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultTxManager name of the manager used by @Transactional without manager=
+const DefaultTxManager = "default"
+
+// txContextKey gin and request context key of the transaction of a @Transactional request
+const txContextKey = "deco.tx"
+
+// txContextKeyType request context key type
+type txContextKeyType struct{}
+
+// Tx transaction begun by a TxManager; *sql.Tx satisfies it
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// TxManager begins the transactions of @Transactional routes. Wrap *sql.DB with NewSQLTxManager;
+// other libraries (GORM, pgx, ...) only need an adapter whose transaction has Commit and Rollback.
+type TxManager interface {
+	Begin(ctx context.Context, options *sql.TxOptions) (Tx, error)
+}
+
+// TxManagerFunc adapts a function to TxManager
+type TxManagerFunc func(ctx context.Context, options *sql.TxOptions) (Tx, error)
+
+// Begin calls f(ctx, options)
+func (f TxManagerFunc) Begin(ctx context.Context, options *sql.TxOptions) (Tx, error) {
+	return f(ctx, options)
+}
+
+// NewSQLTxManager transaction manager of a database/sql pool
+func NewSQLTxManager(db *sql.DB) TxManager {
+	return TxManagerFunc(func(ctx context.Context, options *sql.TxOptions) (Tx, error) {
+		return db.BeginTx(ctx, options)
+	})
+}
+
+var (
+	txManagers    = make(map[string]TxManager)
+	txManagersMux sync.RWMutex
+)
+
+// RegisterTxManager registers the transaction manager used by @Transactional(manager=name);
+// DefaultTxManager is the one of @Transactional()
+func RegisterTxManager(name string, manager TxManager) {
+	txManagersMux.Lock()
+	defer txManagersMux.Unlock()
+	txManagers[name] = manager
+}
+
+// getTxManager returns the manager registered under name
+func getTxManager(name string) (TxManager, bool) {
+	txManagersMux.RLock()
+	defer txManagersMux.RUnlock()
+	manager, exists := txManagers[name]
+	return manager, exists
+}
+
+// txIsolationLevels isolation= values of @Transactional
+var txIsolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read_uncommitted": sql.LevelReadUncommitted,
+	"read_committed":   sql.LevelReadCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"snapshot":         sql.LevelSnapshot,
+	"serializable":     sql.LevelSerializable,
+}
+
+// TransactionalConfig configuration of @Transactional
+type TransactionalConfig struct {
+	Manager   string // RegisterTxManager name, DefaultTxManager when empty
+	Isolation string // a txIsolationLevels key, the database default when empty
+	ReadOnly  bool
+}
+
+// parseTransactionalArgs parses @Transactional(manager=reports, isolation=serializable, readonly=true)
+func parseTransactionalArgs(args []string) (TransactionalConfig, error) {
+	config := TransactionalConfig{Manager: DefaultTxManager}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return config, fmt.Errorf("@Transactional: unexpected argument '%s'", arg)
+		}
+		value = MarkerValue(value)
+
+		switch strings.TrimSpace(key) {
+		case "manager":
+			if value == "" {
+				return config, fmt.Errorf("@Transactional: empty manager")
+			}
+			config.Manager = value
+		case "isolation":
+			if _, ok := txIsolationLevels[value]; !ok {
+				return config, fmt.Errorf("@Transactional: invalid isolation '%s' (valid: default, read_uncommitted, read_committed, repeatable_read, snapshot, serializable)", value)
+			}
+			config.Isolation = value
+		case "readonly":
+			config.ReadOnly = value == "true"
+		default:
+			return config, fmt.Errorf("@Transactional: unknown argument '%s' (valid: manager, isolation, readonly)", key)
+		}
+	}
+	return config, nil
+}
+
+// txOptions options passed to TxManager.Begin
+func (t TransactionalConfig) txOptions() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: txIsolationLevels[t.Isolation], ReadOnly: t.ReadOnly}
+}
+
+// GetTx returns the transaction of a @Transactional request
+func GetTx(c *gin.Context) (Tx, bool) {
+	value, exists := c.Get(txContextKey)
+	if !exists {
+		return nil, false
+	}
+	tx, ok := value.(Tx)
+	return tx, ok
+}
+
+// TxFromContext returns the transaction of a @Transactional request from its context, for repositories
+// that only receive c.Request.Context()
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	tx, ok := ctx.Value(txContextKeyType{}).(Tx)
+	return tx, ok
+}
+
+// SQLTx returns the *sql.Tx of a @Transactional request whose manager is NewSQLTxManager; it also is
+// the SQLExecutor of Outbox.Enqueue
+func SQLTx(c *gin.Context) (*sql.Tx, bool) {
+	tx, exists := GetTx(c)
+	if !exists {
+		return nil, false
+	}
+	sqlTx, ok := tx.(*sql.Tx)
+	return sqlTx, ok
+}
+
+// txResponseWriter holds the response until the transaction ends, so a failed commit still
+// answers 500 instead of a success the database does not reflect
+type txResponseWriter struct {
+	gin.ResponseWriter
+	body []byte
+}
+
+func (w *txResponseWriter) Write(data []byte) (int, error) {
+	w.body = append(w.body, data...)
+	return len(data), nil
+}
+
+func (w *txResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *txResponseWriter) WriteHeaderNow() {}
+
+func (w *txResponseWriter) Flush() {}
+
+// flush sends the held response
+func (w *txResponseWriter) flush() {
+	w.ResponseWriter.WriteHeaderNow()
+	if len(w.body) > 0 {
+		if _, err := w.ResponseWriter.Write(w.body); err != nil {
+			LogVerbose("⚠️  Transactional response not written: %v", err)
+		}
+	}
+}
+
+// TransactionalMiddleware runs the route in a transaction of the registered manager: the transaction is
+// committed when the handler answers 2xx and rolled back on any other status or a panic. The response is
+// held until the commit, so it is not suited to streaming routes.
+func TransactionalMiddleware(config TransactionalConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		manager, exists := getTxManager(config.Manager)
+		if !exists {
+			LogNormal("❌ @Transactional: transaction manager %s is not registered", config.Manager)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "tx_manager_not_registered"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		tx, err := manager.Begin(ctx, config.txOptions())
+		if err != nil {
+			LogNormal("❌ @Transactional: begin failed: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "transaction_unavailable"})
+			return
+		}
+
+		c.Set(txContextKey, tx)
+		request := c.Request
+		c.Request = request.WithContext(context.WithValue(ctx, txContextKeyType{}, tx))
+		original := c.Writer
+		writer := &txResponseWriter{ResponseWriter: original}
+		c.Writer = writer
+		done := false
+		defer func() {
+			c.Writer = original
+			c.Request = request
+			if done {
+				return
+			}
+			// The handler panicked: the recovery middleware answers with the original writer
+			if err := tx.Rollback(); err != nil {
+				LogNormal("❌ @Transactional: rollback failed: %v", err)
+			}
+		}()
+
+		c.Next()
+		done = true
+
+		if status := c.Writer.Status(); status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := tx.Rollback(); err != nil {
+				LogNormal("❌ @Transactional: rollback failed: %v", err)
+			}
+			writer.flush()
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			LogNormal("❌ @Transactional: commit failed: %v", err)
+			_ = c.Error(err)
+			c.Writer = original
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "transaction_failed"})
+			return
+		}
+		writer.flush()
+	}
+}
+
+// createTransactionalMiddleware creates @Transactional middleware
+func createTransactionalMiddleware(args []string) gin.HandlerFunc {
+	config, err := parseTransactionalArgs(args)
+	if err != nil {
+		LogSilent("⚠️  %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	return TransactionalMiddleware(config)
+}
@@ -0,0 +1,125 @@
+package decorators
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTx struct {
+	options    *sql.TxOptions
+	committed  bool
+	rolledBack bool
+	commitErr  error
+}
+
+func (t *fakeTx) Commit() error {
+	t.committed = true
+	return t.commitErr
+}
+
+func (t *fakeTx) Rollback() error {
+	t.rolledBack = true
+	return nil
+}
+
+func TestParseTransactionalArgs(t *testing.T) {
+	config, err := parseTransactionalArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, TransactionalConfig{Manager: DefaultTxManager}, config)
+
+	config, err = parseTransactionalArgs([]string{"manager=reports", "isolation=serializable", "readonly=true"})
+	require.NoError(t, err)
+	assert.Equal(t, TransactionalConfig{Manager: "reports", Isolation: "serializable", ReadOnly: true}, config)
+	assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, config.txOptions())
+
+	for _, args := range [][]string{{"isolation=chaos"}, {"timeout=5s"}, {"reports"}, {"manager="}} {
+		_, err := parseTransactionalArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestTransactionalMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var tx *fakeTx
+	var commitErr error
+	RegisterTxManager("test", TxManagerFunc(func(ctx context.Context, options *sql.TxOptions) (Tx, error) {
+		tx = &fakeTx{options: options, commitErr: commitErr}
+		return tx, nil
+	}))
+
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.POST("/orders/:status", TransactionalMiddleware(TransactionalConfig{Manager: "test", Isolation: "serializable"}), func(c *gin.Context) {
+		current, ok := GetTx(c)
+		require.True(t, ok)
+		fromContext, ok := TxFromContext(c.Request.Context())
+		require.True(t, ok)
+		require.Same(t, current, fromContext)
+
+		switch c.Param("status") {
+		case "created":
+			c.JSON(http.StatusCreated, gin.H{"id": 1})
+		case "invalid":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		default:
+			panic("boom")
+		}
+	})
+	send := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+
+	w := send("/orders/created")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id": 1}`, w.Body.String())
+	assert.True(t, tx.committed)
+	assert.False(t, tx.rolledBack)
+	assert.Equal(t, sql.LevelSerializable, tx.options.Isolation)
+
+	w = send("/orders/invalid")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid"}`, w.Body.String())
+	assert.False(t, tx.committed)
+	assert.True(t, tx.rolledBack)
+
+	w = send("/orders/panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.False(t, tx.committed)
+	assert.True(t, tx.rolledBack)
+
+	// The held success response is replaced when the commit fails
+	commitErr = errors.New("serialization failure")
+	w = send("/orders/created")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "transaction_failed"}`, w.Body.String())
+	assert.True(t, tx.committed)
+}
+
+func TestTransactionalMiddleware_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterTxManager("down", TxManagerFunc(func(ctx context.Context, options *sql.TxOptions) (Tx, error) {
+		return nil, errors.New("connection refused")
+	}))
+
+	for manager, status := range map[string]int{"down": http.StatusServiceUnavailable, "missing": http.StatusInternalServerError} {
+		router := gin.New()
+		called := false
+		router.GET("/", TransactionalMiddleware(TransactionalConfig{Manager: manager}), func(c *gin.Context) { called = true })
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, status, w.Code, manager)
+		assert.False(t, called, manager)
+	}
+}
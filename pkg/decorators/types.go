@@ -66,6 +66,7 @@ type SchemaInfo struct {
 	Properties  map[string]*PropertyInfo `json:"properties,omitempty"`
 	Required    []string                 `json:"required,omitempty"`
 	Example     interface{}              `json:"example,omitempty"`
+	Version     string                   `json:"version,omitempty"` // from @SchemaVersion
 	PackageName string                   `json:"package_name"`
 	FileName    string                   `json:"file_name"`
 }
@@ -85,6 +86,11 @@ type PropertyInfo struct {
 	Maximum     *float64      `json:"maximum,omitempty"`
 	Items       *PropertyInfo `json:"items,omitempty"` // For array types
 	Ref         string        `json:"$ref,omitempty"`  // For schema references
+	GoType      string        `json:"-"`               // Original Go type, used to resolve nested schema examples
+	Deprecated  bool          `json:"deprecated,omitempty"`
+	RemovedIn   string        `json:"removed_in,omitempty"` // version in which a deprecated field goes away
+	Sensitive   string        `json:"sensitive,omitempty"`  // "mask" or "encrypt", handled by @Sensitive
+	ACL         []string      `json:"acl,omitempty"`        // roles or scopes allowed to read the field, enforced by @FieldACL
 }
 
 // EntityMeta represents metadata of an entity/struct extracted from comments
@@ -98,6 +104,7 @@ type EntityMeta struct {
 	// Documentation information
 	Description string                 `json:"description"`
 	Example     map[string]interface{} `json:"example,omitempty"`
+	Version     string                 `json:"version,omitempty"` // from @SchemaVersion
 }
 
 // FieldMeta represents metadata of a struct field
@@ -108,4 +115,8 @@ type FieldMeta struct {
 	Description string      `json:"description"`
 	Example     interface{} `json:"example,omitempty"`
 	Validation  string      `json:"validation,omitempty"` // from validate tags
+	Deprecated  bool        `json:"deprecated,omitempty"` // from deprecated:"true"
+	RemovedIn   string      `json:"removed_in,omitempty"` // from removedIn:"v3"
+	Sensitive   string      `json:"sensitive,omitempty"`  // from sensitive:"mask" or sensitive:"encrypt"
+	ACL         []string    `json:"acl,omitempty"`        // from acl:"admin,hr"
 }
@@ -1,7 +1,6 @@
 package decorators
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -124,7 +123,7 @@ func ValidateJSON(target interface{}, config *ValidationConfig) gin.HandlerFunc
 		}
 
 		// Parse JSON
-		if err := json.Unmarshal(body, newInstance); err != nil {
+		if err := jsonUnmarshal(body, newInstance); err != nil {
 			response := ValidationResponse{
 				Error:   "validation_failed",
 				Message: "Invalid JSON format",
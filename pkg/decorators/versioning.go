@@ -0,0 +1,403 @@
+package decorators
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Versioning strategies (versioning.strategy)
+const (
+	VersioningPath   = "path"
+	VersioningHeader = "header"
+	VersioningQuery  = "query"
+)
+
+// Defaults of the versioning section
+const (
+	DefaultVersionPathPrefix = "/{version}"
+	DefaultVersionHeader     = "API-Version"
+	DefaultVersionQuery      = "version"
+)
+
+const (
+	// apiVersionKey gin context key of the version requested by the client
+	apiVersionKey = "deco_api_version"
+	// versionChainKey gin context key of the registration serving the request, "" for the unversioned one
+	versionChainKey = "deco_version_chain"
+)
+
+var (
+	// versionPattern names accepted by @Version, e.g. v1, v2.1, 2024-06-01
+	versionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+	versioningSettings atomic.Pointer[VersioningConfig]
+)
+
+// parseVersionArgs parses @Version("v1", "v2")
+func parseVersionArgs(args []string) ([]string, error) {
+	var versions []string
+	for _, arg := range args {
+		version := MarkerValue(arg)
+		if !versionPattern.MatchString(version) {
+			return nil, fmt.Errorf("@Version: invalid version '%s' (letters, digits, '.', '_' and '-')", version)
+		}
+		if contains(versions, version) {
+			return nil, fmt.Errorf("@Version: version '%s' is listed twice", version)
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("@Version needs at least one version, e.g. @Version(\"v1\")")
+	}
+	return versions, nil
+}
+
+// validateVersionMarkers rejects @Version without arguments, which skips argument validation during parsing
+func validateVersionMarkers(route *RouteMeta) error {
+	for _, marker := range route.Markers {
+		if marker.Name == "Version" {
+			if _, err := parseVersionArgs(marker.Args); err != nil {
+				return fmt.Errorf("%s:%d: %v", route.FileName, marker.Line, err)
+			}
+		}
+	}
+	return nil
+}
+
+// processVersionMarker adds the versions of the marker to the route
+func processVersionMarker(marker MarkerInstance, route *RouteMeta) {
+	// Arguments were validated by validateVersionMarkers
+	versions, _ := parseVersionArgs(marker.Args)
+	for _, version := range versions {
+		if !contains(route.Versions, version) {
+			route.Versions = append(route.Versions, version)
+		}
+	}
+}
+
+// routeVersions versions a route is registered under by the generated code; "" registers it unversioned
+func routeVersions(route *RouteMeta) []string {
+	if len(route.Versions) == 0 {
+		return []string{""}
+	}
+	return route.Versions
+}
+
+// validate checks the strategy and its settings
+func (v VersioningConfig) validate() error {
+	switch v.Strategy {
+	case "", VersioningPath, VersioningHeader, VersioningQuery:
+	default:
+		return fmt.Errorf("invalid versioning.strategy '%s' (valid: path, header, query)", v.Strategy)
+	}
+	if v.PathPrefix != "" && (!strings.HasPrefix(v.PathPrefix, "/") || !strings.Contains(v.PathPrefix, "{version}")) {
+		return fmt.Errorf("versioning.path_prefix must start with '/' and contain {version}, found '%s'", v.PathPrefix)
+	}
+	if strings.ContainsAny(v.Header, " \t:") {
+		return fmt.Errorf("invalid versioning.header '%s'", v.Header)
+	}
+	if v.Default != "" && !versionPattern.MatchString(v.Default) {
+		return fmt.Errorf("invalid versioning.default '%s'", v.Default)
+	}
+	return nil
+}
+
+// ConfigureVersioning sets how clients select the version of @Version routes (versioning section)
+func ConfigureVersioning(config VersioningConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+	versioningSettings.Store(&config)
+	return nil
+}
+
+// versioningConfig versioning of ConfigureVersioning, or the default one
+func versioningConfig() VersioningConfig {
+	if config := versioningSettings.Load(); config != nil {
+		return *config
+	}
+	return VersioningConfig{}
+}
+
+// strategy configured strategy, path by default
+func (v VersioningConfig) strategy() string {
+	if v.Strategy == "" {
+		return VersioningPath
+	}
+	return v.Strategy
+}
+
+// header name of the version header
+func (v VersioningConfig) header() string {
+	if v.Header == "" {
+		return DefaultVersionHeader
+	}
+	return v.Header
+}
+
+// query name of the version query parameter
+func (v VersioningConfig) query() string {
+	if v.Query == "" {
+		return DefaultVersionQuery
+	}
+	return v.Query
+}
+
+// Path path a route is served at: versioned routes get the version prefix with the path strategy, e.g.
+// "/v2/users" for "/users"
+func (v VersioningConfig) Path(route *RouteEntry) string {
+	if route.Version == "" || v.strategy() != VersioningPath {
+		return route.Path
+	}
+	prefix := v.PathPrefix
+	if prefix == "" {
+		prefix = DefaultVersionPathPrefix
+	}
+	return strings.TrimSuffix(strings.ReplaceAll(prefix, "{version}", route.Version), "/") + route.Path
+}
+
+// requestedVersion version named by the request, "" when there is none
+func (v VersioningConfig) requestedVersion(c *gin.Context) string {
+	switch v.strategy() {
+	case VersioningHeader:
+		return strings.TrimSpace(c.GetHeader(v.header()))
+	case VersioningQuery:
+		return strings.TrimSpace(c.Query(v.query()))
+	}
+	return ""
+}
+
+// parameterInfo documents the version header or query parameter of a version
+func (v VersioningConfig) parameterInfo(version string) (ParameterInfo, bool) {
+	param := ParameterInfo{Type: "string", Description: "API version", Example: version}
+	switch v.strategy() {
+	case VersioningHeader:
+		param.Name, param.Location = v.header(), "header"
+	case VersioningQuery:
+		param.Name, param.Location = v.query(), "query"
+	default:
+		return param, false
+	}
+	return param, true
+}
+
+// APIVersion returns the API version serving the request, "" for unversioned routes. Handlers registered
+// under several versions with @Version("v1", "v2") branch on it.
+func APIVersion(c *gin.Context) string {
+	return c.GetString(apiVersionKey)
+}
+
+// APIVersions returns the versions of the registered routes, oldest first
+func APIVersions() []string {
+	return collectVersions(GetRoutes())
+}
+
+// collectVersions distinct versions of routes, oldest first
+func collectVersions(routes []RouteEntry) []string {
+	var versions []string
+	for _, route := range routes {
+		if route.Version != "" && !contains(versions, route.Version) {
+			versions = append(versions, route.Version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+	return versions
+}
+
+// compareVersions orders versions by their numeric parts ("v2" < "v10", "v1.2" < "v1.10"), then as text
+func compareVersions(a, b string) int {
+	partsA := strings.FieldsFunc(a, isVersionSeparator)
+	partsB := strings.FieldsFunc(b, isVersionSeparator)
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, errA := strconv.Atoi(strings.TrimLeft(partsA[i], "vV"))
+		numberB, errB := strconv.Atoi(strings.TrimLeft(partsB[i], "vV"))
+		switch {
+		case errA == nil && errB == nil && numberA != numberB:
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && partsA[i] != partsB[i]:
+			return strings.Compare(partsA[i], partsB[i])
+		}
+	}
+	return len(partsA) - len(partsB)
+}
+
+// isVersionSeparator separators of the parts of a version
+func isVersionSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '_'
+}
+
+// routeMount gin route serving one or more registered routes
+type routeMount struct {
+	method   string
+	path     string
+	handlers []gin.HandlerFunc
+}
+
+// versionMounts mounts the registered routes, as GetRoutes returns them, with the versioning strategy. The
+// path strategy serves each version under its prefix. The header and query strategies serve the versions of a method and path on a
+// single gin route, which selects the chain of the requested version; an unversioned route on the same
+// path serves the requests naming no version, or one the path does not declare.
+func versionMounts(routes []RouteEntry, config VersioningConfig, chain func(route *RouteEntry) []gin.HandlerFunc) []routeMount {
+	mounts := make([]routeMount, 0, len(routes))
+	if config.strategy() == VersioningPath {
+		for i := range routes {
+			route := &routes[i]
+			handlers := chain(route)
+			if route.Version != "" {
+				handlers = append([]gin.HandlerFunc{servedVersion(route.Version)}, handlers...)
+			}
+			mounts = append(mounts, routeMount{method: route.Method, path: route.Path, handlers: handlers})
+		}
+		return mounts
+	}
+
+	// Registrations sharing a method and path, in registration order
+	var keys []string
+	shared := make(map[string][]*RouteEntry)
+	for i := range routes {
+		key := routes[i].Method + " " + routes[i].Path
+		if _, seen := shared[key]; !seen {
+			keys = append(keys, key)
+		}
+		shared[key] = append(shared[key], &routes[i])
+	}
+
+	for _, key := range keys {
+		entries := shared[key]
+		if len(entries) == 1 && entries[0].Version == "" {
+			mounts = append(mounts, routeMount{method: entries[0].Method, path: entries[0].Path, handlers: chain(entries[0])})
+			continue
+		}
+
+		var versions []string
+		fallback := false
+		var handlers []gin.HandlerFunc
+		for _, route := range entries {
+			if (route.Version == "" && fallback) || (route.Version != "" && contains(versions, route.Version)) {
+				LogSilent("⚠️  %s is registered twice for version '%s', keeping the first", key, route.Version)
+				continue
+			}
+			if route.Version == "" {
+				fallback = true
+			} else {
+				versions = append(versions, route.Version)
+			}
+			for _, handler := range chain(route) {
+				handlers = append(handlers, versionGate(route.Version, handler))
+			}
+		}
+		sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+		handlers = append([]gin.HandlerFunc{versionSelector(config, versions, fallback)}, handlers...)
+		mounts = append(mounts, routeMount{method: entries[0].Method, path: entries[0].Path, handlers: handlers})
+	}
+	return mounts
+}
+
+// servedVersion records the version of a route mounted under its version prefix
+func servedVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionKey, version)
+	}
+}
+
+// versionSelector picks the registration serving the request from the version it names, versioning.default
+// when it names none, then the latest version (versions are sorted oldest first). Versions the path does
+// not declare get 400 unless an unversioned registration serves them.
+func versionSelector(config VersioningConfig, versions []string, fallback bool) gin.HandlerFunc {
+	name := config.header()
+	if config.strategy() == VersioningQuery {
+		name = config.query()
+	}
+
+	return func(c *gin.Context) {
+		if config.strategy() == VersioningHeader {
+			addVary(c.Writer.Header(), name)
+		}
+
+		requested := config.requestedVersion(c)
+		version := requested
+		if version == "" {
+			version = config.Default
+		}
+		switch {
+		case contains(versions, version):
+		case fallback:
+			c.Set(apiVersionKey, requested)
+			c.Set(versionChainKey, "")
+			return
+		case requested == "" && len(versions) > 0:
+			version = versions[len(versions)-1]
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":     "unsupported_version",
+				"message":   fmt.Sprintf("%s '%s' is not supported", name, requested),
+				"supported": versions,
+			})
+			return
+		}
+
+		c.Set(apiVersionKey, version)
+		c.Set(versionChainKey, version)
+		if config.strategy() == VersioningHeader {
+			c.Header(name, version)
+		}
+	}
+}
+
+// versionGate runs handler only on the requests served by the registration of version
+func versionGate(version string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(versionChainKey) == version {
+			handler(c)
+		}
+	}
+}
+
+// documentedRoutes routes of the specification of version, or of every version when version is "", from
+// the served routes. Versioned routes get the version parameter with the header and query strategies.
+// With the path strategy the specification of every version lists them all, the version appended to
+// their operationId; with the header and query strategies the versions of a path share it, so only the
+// latest one, or the one of version, is listed.
+func documentedRoutes(routes []RouteEntry, config VersioningConfig, version string) []RouteEntry {
+	shared := config.strategy() != VersioningPath
+	chosen := make(map[string]string) // method and path -> documented version
+	for _, route := range routes {
+		if version != "" && route.Version != "" && route.Version != version {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		if current, seen := chosen[key]; !seen || current == "" || (route.Version != "" && compareVersions(route.Version, current) > 0) {
+			chosen[key] = route.Version
+		}
+	}
+
+	documented := make([]RouteEntry, 0, len(routes))
+	for _, route := range routes {
+		if version != "" && route.Version != "" && route.Version != version {
+			continue
+		}
+		if shared && chosen[route.Method+" "+route.Path] != route.Version {
+			continue
+		}
+		if route.Version != "" {
+			if param, ok := config.parameterInfo(route.Version); ok {
+				route.Parameters = append(append([]ParameterInfo(nil), route.Parameters...), param)
+			}
+			if version == "" && !shared && route.OperationID != "" {
+				route.OperationID += "_" + strings.NewReplacer(".", "_", "-", "_").Replace(route.Version)
+			}
+		}
+		documented = append(documented, route)
+	}
+	return documented
+}
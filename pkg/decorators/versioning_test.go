@@ -0,0 +1,254 @@
+package decorators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useVersionedRoutes replaces the registry and the versioning settings for the test
+func useVersionedRoutes(t *testing.T, config VersioningConfig, entries ...RouteEntry) {
+	registryMutex.Lock()
+	saved := routes
+	routes = entries
+	registryMutex.Unlock()
+	require.NoError(t, ConfigureVersioning(config))
+	t.Cleanup(func() {
+		registryMutex.Lock()
+		routes = saved
+		registryMutex.Unlock()
+		versioningSettings.Store(nil)
+	})
+}
+
+// versionedRouter mounts the registered routes as Default does
+func versionedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	for _, mount := range versionMounts(GetRoutes(), versioningConfig(), routeHandlers) {
+		router.Handle(mount.method, mount.path, mount.handlers...)
+	}
+	return router
+}
+
+// versionHandler answers the handler name and the version serving the request
+func versionHandler(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, name+":"+APIVersion(c))
+	}
+}
+
+func serveVersioned(router *gin.Engine, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestParseVersionArgs(t *testing.T) {
+	versions, err := parseVersionArgs([]string{`"v1"`, `"v2.1"`, "2024-06-01"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2.1", "2024-06-01"}, versions)
+
+	for _, args := range [][]string{nil, {`""`}, {`"v 1"`}, {`"v1/beta"`}, {`"v1"`, `"v1"`}} {
+		_, err := parseVersionArgs(args)
+		assert.Error(t, err, "args %v", args)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	versions := []string{"v10", "v2", "v1.10", "v1.2", "v1", "beta"}
+	assert.Equal(t, []string{"beta", "v1", "v1.2", "v1.10", "v2", "v10"}, collectVersions(func() []RouteEntry {
+		entries := make([]RouteEntry, 0, len(versions))
+		for _, version := range versions {
+			entries = append(entries, RouteEntry{Version: version})
+		}
+		return entries
+	}()))
+	assert.Equal(t, 0, compareVersions("v2", "v2"))
+	assert.Negative(t, compareVersions("2024-01-31", "2024-06-01"))
+}
+
+func TestVersionMarker(t *testing.T) {
+	route := &RouteMeta{Method: "GET", Path: "/users", FuncName: "ListUsers", Markers: []MarkerInstance{
+		{Name: "Version", Args: []string{`"v1"`, `"v2"`}},
+		{Name: "Version", Args: []string{`"v2"`, `"v3"`}},
+	}}
+	require.NoError(t, processMiddlewares(route))
+	assert.Equal(t, []string{"v1", "v2", "v3"}, route.Versions)
+	assert.Empty(t, route.MiddlewareCalls)
+
+	route = &RouteMeta{Method: "GET", Path: "/users", FileName: "users.go", Markers: []MarkerInstance{{Name: "Version", Line: 7}}}
+	assert.ErrorContains(t, processMiddlewares(route), "users.go:7: @Version needs at least one version")
+
+	_, err := parseArgumentsWithValidation(`"v1", "v 2"`, "Version")
+	assert.Error(t, err)
+}
+
+func TestVersioning_PathStrategy(t *testing.T) {
+	useVersionedRoutes(t, VersioningConfig{},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("list"), Version: "v1"},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("list"), Version: "v2"},
+		RouteEntry{Method: "GET", Path: "/health", Handler: versionHandler("health")},
+	)
+	router := versionedRouter()
+
+	assert.Equal(t, "list:v1", serveVersioned(router, "/v1/users", nil).Body.String())
+	assert.Equal(t, "list:v2", serveVersioned(router, "/v2/users", nil).Body.String())
+	assert.Equal(t, "health:", serveVersioned(router, "/health", nil).Body.String())
+	assert.Equal(t, http.StatusNotFound, serveVersioned(router, "/users", nil).Code)
+
+	// GetRoutes reports the served paths
+	var paths []string
+	for _, route := range GetRoutes() {
+		paths = append(paths, route.Path)
+	}
+	assert.Equal(t, []string{"/v1/users", "/v2/users", "/health"}, paths)
+
+	// A custom prefix
+	require.NoError(t, ConfigureVersioning(VersioningConfig{PathPrefix: "/api/{version}/"}))
+	assert.Equal(t, "list:v2", serveVersioned(versionedRouter(), "/api/v2/users", nil).Body.String())
+}
+
+func TestVersioning_HeaderStrategy(t *testing.T) {
+	markV1 := func(c *gin.Context) { c.Header("X-V1-Middleware", "true") }
+	useVersionedRoutes(t, VersioningConfig{Strategy: VersioningHeader},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("v1-handler"), Middlewares: []gin.HandlerFunc{markV1}, Version: "v1"},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("v2-handler"), Version: "v2"},
+		RouteEntry{Method: "GET", Path: "/health", Handler: versionHandler("health")},
+	)
+	router := versionedRouter()
+
+	w := serveVersioned(router, "/users", http.Header{"Api-Version": {"v1"}})
+	assert.Equal(t, "v1-handler:v1", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("X-V1-Middleware"))
+	assert.Equal(t, "v1", w.Header().Get("API-Version"))
+	assert.Equal(t, "API-Version", w.Header().Get("Vary"))
+
+	// The middlewares of the other versions do not run
+	w = serveVersioned(router, "/users", http.Header{"Api-Version": {"v2"}})
+	assert.Equal(t, "v2-handler:v2", w.Body.String())
+	assert.Empty(t, w.Header().Get("X-V1-Middleware"))
+
+	// Without a version the latest one serves; an undeclared one is rejected
+	assert.Equal(t, "v2-handler:v2", serveVersioned(router, "/users", nil).Body.String())
+	w = serveVersioned(router, "/users", http.Header{"Api-Version": {"v9"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"supported":["v1","v2"]`)
+	assert.Equal(t, "health:", serveVersioned(router, "/health", nil).Body.String())
+
+	// versioning.default serves the requests naming no version
+	require.NoError(t, ConfigureVersioning(VersioningConfig{Strategy: VersioningHeader, Default: "v1"}))
+	assert.Equal(t, "v1-handler:v1", serveVersioned(versionedRouter(), "/users", nil).Body.String())
+}
+
+func TestVersioning_QueryStrategyWithFallback(t *testing.T) {
+	useVersionedRoutes(t, VersioningConfig{Strategy: VersioningQuery, Query: "api"},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("current")},
+		RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("beta"), Version: "beta"},
+	)
+	router := versionedRouter()
+
+	assert.Equal(t, "beta:beta", serveVersioned(router, "/users?api=beta", nil).Body.String())
+	// The unversioned route serves the requests naming no version or an undeclared one
+	assert.Equal(t, "current:", serveVersioned(router, "/users", nil).Body.String())
+	assert.Equal(t, "current:v9", serveVersioned(router, "/users?api=v9", nil).Body.String())
+}
+
+func TestVersioning_OpenAPI(t *testing.T) {
+	users := func(version string) RouteEntry {
+		return RouteEntry{Method: "GET", Path: "/users", Handler: versionHandler("list"), FuncName: "ListUsers", OperationID: "listUsers", Version: version}
+	}
+	useVersionedRoutes(t, VersioningConfig{}, users("v1"), users("v2"), RouteEntry{Method: "GET", Path: "/health", Handler: versionHandler("health"), FuncName: "Health"})
+	assert.Equal(t, []string{"v1", "v2"}, APIVersions())
+
+	spec := GenerateVersionOpenAPISpec(DefaultConfig(), "v2")
+	assert.Equal(t, "v2", spec.Info.Version)
+	assert.Contains(t, spec.Paths, "/v2/users")
+	assert.Contains(t, spec.Paths, "/health")
+	assert.NotContains(t, spec.Paths, "/v1/users")
+	assert.Equal(t, "listUsers", spec.Paths["/v2/users"]["get"].OperationID)
+
+	// The specification of every version lists them all, with unique operationIds
+	spec = GenerateOpenAPISpec(DefaultConfig())
+	assert.Equal(t, "listUsers_v1", spec.Paths["/v1/users"]["get"].OperationID)
+	assert.Equal(t, "listUsers_v2", spec.Paths["/v2/users"]["get"].OperationID)
+
+	// With the header strategy the path is shared and the version is a parameter
+	config := DefaultConfig()
+	config.Versioning = VersioningConfig{Strategy: VersioningHeader}
+	require.NoError(t, ConfigureVersioning(config.Versioning))
+	spec = GenerateVersionOpenAPISpec(config, "v1")
+	require.Contains(t, spec.Paths, "/users")
+	parameters := spec.Paths["/users"]["get"].Parameters
+	require.Len(t, parameters, 1)
+	assert.Equal(t, "API-Version", parameters[0].Name)
+	assert.Equal(t, "header", parameters[0].In)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/openapi/:version", OpenAPIVersionHandler(config))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi/v2.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":"v2"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi/v3.json", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVersioningConfig_Validate(t *testing.T) {
+	assert.NoError(t, VersioningConfig{Strategy: VersioningHeader, Header: "X-API-Version", Default: "v2"}.validate())
+	assert.ErrorContains(t, VersioningConfig{Strategy: "subdomain"}.validate(), "versioning.strategy")
+	assert.ErrorContains(t, VersioningConfig{PathPrefix: "/api"}.validate(), "{version}")
+	assert.ErrorContains(t, VersioningConfig{Header: "API Version"}.validate(), "versioning.header")
+	assert.ErrorContains(t, VersioningConfig{Default: "v 2"}.validate(), "versioning.default")
+}
+
+func TestGenerateInitFile_Version(t *testing.T) {
+	dir := t.TempDir()
+	source := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// @Route("GET", "/users")
+// @Version("v1", "v2")
+func ListUsers(c *gin.Context) {}
+
+// @Route("GET", "/users")
+// @Version("v3")
+func ListUsersV3(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0o600))
+
+	savedParserHooks, savedGeneratorHooks := parserHooks, generatorHooks
+	parserHooks, generatorHooks = nil, nil
+	defer func() { parserHooks, generatorHooks = savedParserHooks, savedGeneratorHooks }()
+
+	outputPath := filepath.Join(dir, ".deco", "init_decorators.go")
+	require.NoError(t, GenerateInitFileWithConfig(dir, outputPath, "handlers", DefaultConfig()))
+	generated, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(generated), "RegisterRouteWithMeta("), "once per version")
+	assert.Contains(t, string(generated), "// GET /users -> ListUsers (v2)")
+	assert.Contains(t, string(generated), `Version:     "v3",`)
+
+	// Disjoint versions of a path are not duplicates
+	routes, err := ParseDirectory(dir)
+	require.NoError(t, err)
+	assert.Empty(t, DuplicateRouteFindings(routes))
+	routes[1].Versions = []string{"v2"}
+	findings := DuplicateRouteFindings(routes)
+	require.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "duplicate route GET /users")
+}
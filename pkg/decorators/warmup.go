@@ -0,0 +1,229 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessPath endpoint reporting whether the dependencies were initialized
+const ReadinessPath = "/decorators/ready"
+
+// Initializer statuses
+const (
+	InitPending = "pending"
+	InitRunning = "running"
+	InitReady   = "ready"
+	InitFailed  = "failed"
+	InitTimeout = "timeout"
+)
+
+// InitStatus state of one initializer of the warm-up phase
+type InitStatus struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+}
+
+// initializer dependency initialized before the engine serves traffic
+type initializer struct {
+	name string
+	init func(ctx context.Context) error
+}
+
+var (
+	initializers  []initializer
+	initStatuses  []InitStatus
+	initializerMu sync.RWMutex
+)
+
+// RegisterInitializer registers a dependency to initialize when the engine starts instead of on the
+// first request (a JWKS fetch, a GeoIP database load, a connection pool, ...). init must honor ctx,
+// which expires after warmup.timeout. Register it before deco.Default().
+func RegisterInitializer(name string, init func(ctx context.Context) error) {
+	initializerMu.Lock()
+	defer initializerMu.Unlock()
+	initializers = append(initializers, initializer{name: name, init: init})
+}
+
+// RunInitializers runs the built-in and registered initializers, at most warmup.parallelism at a
+// time and each bounded by warmup.timeout, and returns their statuses. An initializer that outlives
+// its timeout is reported as timed out and becomes ready if it completes later.
+func RunInitializers(ctx context.Context, config WarmupConfig) []InitStatus {
+	return runInitializers(ctx, prepareInitializers(), config)
+}
+
+// prepareInitializers lists the initializers of the warm-up and marks them pending, so readiness
+// fails until they run
+func prepareInitializers() []initializer {
+	initializerMu.Lock()
+	defer initializerMu.Unlock()
+
+	list := append(builtinInitializers(), initializers...)
+	initStatuses = make([]InitStatus, len(list))
+	for i, init := range list {
+		initStatuses[i] = InitStatus{Name: init.name, Status: InitPending}
+	}
+	return list
+}
+
+// runInitializers runs the prepared initializers
+func runInitializers(ctx context.Context, list []initializer, config WarmupConfig) []InitStatus {
+	if len(list) == 0 {
+		return nil
+	}
+	timeout, err := ParseDurationLiteral(config.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	parallelism := config.Parallelism
+	if parallelism <= 0 {
+		parallelism = 4
+	}
+
+	start := time.Now()
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, init := range list {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			runInitializer(ctx, i, init, timeout)
+		}()
+	}
+	wg.Wait()
+
+	statuses := InitStatuses()
+	failed := 0
+	for _, status := range statuses {
+		if status.Status != InitReady {
+			failed++
+			LogSilent("⚠️  Initializer %s %s: %s", status.Name, status.Status, status.Error)
+		}
+	}
+	LogNormal("Warm-up: %d/%d initializers ready in %v", len(statuses)-failed, len(statuses), time.Since(start).Round(time.Millisecond))
+	return statuses
+}
+
+// runInitializer runs one initializer and records its status at index
+func runInitializer(ctx context.Context, index int, init initializer, timeout time.Duration) {
+	setInitStatus(index, InitStatus{Name: init.name, Status: InitRunning})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- init.init(ctx)
+	}()
+
+	finish := func(err error) {
+		status := InitStatus{Name: init.name, Status: InitReady, Duration: time.Since(start)}
+		if err != nil {
+			status.Status, status.Error = InitFailed, err.Error()
+		}
+		setInitStatus(index, status)
+	}
+
+	select {
+	case err := <-done:
+		finish(err)
+	case <-ctx.Done():
+		setInitStatus(index, InitStatus{Name: init.name, Status: InitTimeout, Error: fmt.Sprintf("not initialized after %v", timeout), Duration: time.Since(start)})
+		go func() {
+			if err := <-done; err == nil {
+				finish(nil)
+			}
+		}()
+	}
+}
+
+// setInitStatus records the status of the initializer at index
+func setInitStatus(index int, status InitStatus) {
+	initializerMu.Lock()
+	defer initializerMu.Unlock()
+	if index < len(initStatuses) {
+		initStatuses[index] = status
+	}
+}
+
+// InitStatuses returns the statuses of the last warm-up, in registration order
+func InitStatuses() []InitStatus {
+	initializerMu.RLock()
+	defer initializerMu.RUnlock()
+	return append([]InitStatus(nil), initStatuses...)
+}
+
+// builtinInitializers initializers of the framework stores: the Redis connections of @Cache and
+// @RateLimit are checked and the JWKS of auth.jwt is fetched so readiness reflects them
+func builtinInitializers() []initializer {
+	stores, limiters := adminStores()
+	var pings []func(ctx context.Context) error
+	for _, store := range stores {
+		if redisStore, ok := store.(*RedisCache); ok {
+			pings = append(pings, func(ctx context.Context) error { return redisStore.client.Ping(ctx).Err() })
+		}
+	}
+	for _, limiter := range limiters {
+		if redisLimiter, ok := limiter.(*RedisRateLimiter); ok {
+			pings = append(pings, func(ctx context.Context) error { return redisLimiter.client.Ping(ctx).Err() })
+		}
+	}
+
+	var builtins []initializer
+	if len(pings) > 0 {
+		builtins = append(builtins, initializer{name: "redis", init: func(ctx context.Context) error {
+			for _, ping := range pings {
+				if err := ping(ctx); err != nil {
+					return fmt.Errorf("redis: %v", err)
+				}
+			}
+			return nil
+		}})
+	}
+	if verifier := GetJWTVerifier(); verifier != nil && verifier.jwks != nil {
+		builtins = append(builtins, initializer{name: "jwks", init: warmJWKS})
+	}
+	return builtins
+}
+
+// validate checks the warm-up configuration
+func (w WarmupConfig) validate() error {
+	if w.Timeout != "" {
+		if timeout, err := ParseDurationLiteral(w.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid warmup.timeout '%s'", w.Timeout)
+		}
+	}
+	if w.Parallelism < 0 {
+		return fmt.Errorf("invalid warmup.parallelism %d", w.Parallelism)
+	}
+	return nil
+}
+
+// ReadinessHandler GET /decorators/ready answers 200 once every initializer is ready and 503 while
+// the warm-up runs or after a failure, listing the initializers either way
+func ReadinessHandler(c *gin.Context) {
+	statuses := InitStatuses()
+	ready := true
+	for _, status := range statuses {
+		ready = ready && status.Status == InitReady
+	}
+
+	code, state := http.StatusOK, "ready"
+	if !ready {
+		code, state = http.StatusServiceUnavailable, "not_ready"
+	}
+	c.JSON(code, gin.H{"status": state, "initializers": statuses})
+}
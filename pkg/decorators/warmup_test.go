@@ -0,0 +1,120 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetInitializers clears the registered initializers after the test
+func resetInitializers(t *testing.T) {
+	t.Cleanup(func() {
+		initializerMu.Lock()
+		initializers, initStatuses = nil, nil
+		initializerMu.Unlock()
+	})
+}
+
+func TestRunInitializers(t *testing.T) {
+	resetInitializers(t)
+
+	var running, peak int32
+	track := func(ctx context.Context) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	RegisterInitializer("jwks", track)
+	RegisterInitializer("geoip", track)
+	RegisterInitializer("pool", track)
+	RegisterInitializer("broken", func(ctx context.Context) error { return errors.New("connection refused") })
+	RegisterInitializer("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	RegisterInitializer("panics", func(ctx context.Context) error { panic("boom") })
+
+	statuses := RunInitializers(context.Background(), WarmupConfig{Timeout: "50ms", Parallelism: 2})
+	require.Len(t, statuses, 6)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+	byName := make(map[string]InitStatus)
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	assert.Equal(t, InitReady, byName["jwks"].Status)
+	assert.Equal(t, InitFailed, byName["broken"].Status)
+	assert.Equal(t, "connection refused", byName["broken"].Error)
+	assert.Equal(t, InitTimeout, byName["slow"].Status)
+	assert.Equal(t, InitFailed, byName["panics"].Status)
+	assert.Contains(t, byName["panics"].Error, "boom")
+}
+
+func TestRunInitializersLateCompletion(t *testing.T) {
+	resetInitializers(t)
+
+	release := make(chan struct{})
+	RegisterInitializer("late", func(ctx context.Context) error {
+		<-release // ignores ctx
+		return nil
+	})
+	statuses := RunInitializers(context.Background(), WarmupConfig{Timeout: "10ms"})
+	assert.Equal(t, InitTimeout, statuses[0].Status)
+
+	close(release)
+	assert.Eventually(t, func() bool { return InitStatuses()[0].Status == InitReady }, time.Second, 5*time.Millisecond)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	resetInitializers(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(ReadinessPath, ReadinessHandler)
+
+	ready := make(chan struct{})
+	RegisterInitializer("jwks", func(ctx context.Context) error {
+		<-ready
+		return nil
+	})
+
+	list := prepareInitializers()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "pending initializers are not ready")
+
+	close(ready)
+	runInitializers(context.Background(), list, WarmupConfig{})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Status       string       `json:"status"`
+		Initializers []InitStatus `json:"initializers"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "ready", body.Status)
+	assert.Equal(t, "jwks", body.Initializers[0].Name)
+}
+
+func TestWarmupConfigValidate(t *testing.T) {
+	assert.NoError(t, WarmupConfig{Timeout: "5s", Parallelism: 2}.validate())
+	assert.Error(t, WarmupConfig{Timeout: "soon"}.validate())
+	assert.Error(t, WarmupConfig{Parallelism: -1}.validate())
+}
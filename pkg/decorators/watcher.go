@@ -295,9 +295,8 @@ func (fw *FileWatcher) matchesGlobPattern(path, pattern string) bool {
 func (fw *FileWatcher) regenerateCode() error {
 	log.Println("🔄 Automatically regenerating code...")
 
-	// Use default configuration for regeneration
-	outputPath := "./.deco/init_decorators.go"
-	packageName := "deco"
+	outputPath := fw.config.Generate.OutputPath()
+	packageName := fw.config.Generate.PackageName()
 
 	wd, err := filepath.Abs(".")
 	if err != nil {
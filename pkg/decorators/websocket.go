@@ -1,6 +1,7 @@
 package decorators
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -56,6 +57,15 @@ type WebSocketHub struct {
 
 	// Configuration
 	config WebSocketConfig
+
+	// Closed by Close to stop the hub loop
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Backplane propagating messages to the other instances (UseBackplane)
+	backplane     WebSocketBackplane
+	stopBackplane context.CancelFunc
+	instanceID    string
 }
 
 // WebSocketMessage represents a WebSocket message
@@ -98,6 +108,8 @@ func InitWebSocket(config WebSocketConfig) *WebSocketHub {
 		register:    make(chan *WebSocketConnection),
 		unregister:  make(chan *WebSocketConnection),
 		config:      config,
+		done:        make(chan struct{}),
+		instanceID:  newWebSocketInstanceID(),
 	}
 
 	// Start router
@@ -111,6 +123,13 @@ func InitWebSocket(config WebSocketConfig) *WebSocketHub {
 	// Register default handlers
 	RegisterDefaultHandlers()
 
+	// Messages propagate to the other instances when websocket.backplane is configured
+	if backplane := getConfiguredBackplane(); backplane != nil {
+		if err := hub.UseBackplane(backplane); err != nil {
+			log.Printf("WebSocket: Error attaching backplane: %v", err)
+		}
+	}
+
 	defaultHub = hub
 	return hub
 }
@@ -133,6 +152,44 @@ func (h *WebSocketHub) run() {
 
 		case <-ticker.C:
 			h.pingConnections()
+
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// Close disconnects every client with a close frame and stops the hub; later connections are refused
+func (h *WebSocketHub) Close() {
+	h.closeOnce.Do(func() {
+		h.mu.Lock()
+		for id, conn := range h.connections {
+			delete(h.connections, id)
+			close(conn.Send) // writePump sends the close frame
+		}
+		h.groups = make(map[string]map[string]*WebSocketConnection)
+		if h.stopBackplane != nil {
+			h.stopBackplane()
+		}
+		h.mu.Unlock()
+		close(h.done)
+		log.Printf("WebSocket: Hub closed")
+	})
+}
+
+// CloseWebSocketHub closes the default hub, if any, and the backplane of websocket.backplane
+func CloseWebSocketHub() {
+	if defaultHub != nil {
+		defaultHub.Close()
+	}
+
+	configuredBackplaneMux.Lock()
+	backplane := configuredBackplane
+	configuredBackplane = nil
+	configuredBackplaneMux.Unlock()
+	if backplane != nil {
+		if err := backplane.Close(); err != nil {
+			log.Printf("WebSocket: Error closing backplane: %v", err)
 		}
 	}
 }
@@ -284,23 +341,33 @@ func (h *WebSocketHub) leaveGroupUnsafe(conn *WebSocketConnection, groupName str
 	}
 }
 
-// Broadcast sends message to all connections
+// Broadcast sends message to all connections, of every instance when a backplane is attached
 func (h *WebSocketHub) Broadcast(message *WebSocketMessage) {
 	message.Timestamp = time.Now()
+	h.publish(message)
 	h.broadcast <- message
 }
 
-// SendToConnection sends message to specific connection
+// SendToConnection sends message to specific connection, looked up on the other instances when it
+// is not connected to this one
 func (h *WebSocketHub) SendToConnection(connID string, message *WebSocketMessage) {
 	message.Target = connID
 	message.Timestamp = time.Now()
+
+	h.mu.RLock()
+	_, local := h.connections[connID]
+	h.mu.RUnlock()
+	if !local {
+		h.publish(message)
+	}
 	h.broadcast <- message
 }
 
-// SendToGroup sends message to group
+// SendToGroup sends message to group, joined on any instance when a backplane is attached
 func (h *WebSocketHub) SendToGroup(groupName string, message *WebSocketMessage) {
 	message.Group = groupName
 	message.Timestamp = time.Now()
+	h.publish(message)
 	h.broadcast <- message
 }
 
@@ -345,7 +412,12 @@ func CreateWebSocketHandler(config *WebSocketConfig) gin.HandlerFunc {
 		}
 
 		// Register connection
-		defaultHub.register <- wsConn
+		select {
+		case defaultHub.register <- wsConn:
+		case <-defaultHub.done:
+			conn.Close()
+			return
+		}
 
 		// Start goroutines
 		go wsConn.writePump()
@@ -356,7 +428,10 @@ func CreateWebSocketHandler(config *WebSocketConfig) gin.HandlerFunc {
 // readPump processes received messages
 func (c *WebSocketConnection) readPump() {
 	defer func() {
-		c.Hub.unregister <- c
+		select {
+		case c.Hub.unregister <- c:
+		case <-c.Hub.done:
+		}
 		c.Conn.Close()
 	}()
 
@@ -567,31 +642,48 @@ func GetWebSocketInfo(config WebSocketConfig) map[string]interface{} {
 // WebSocketStatsHandler handler for WebSocket statistics
 func WebSocketStatsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if defaultHub == nil {
+		stats := websocketStats(c.Request.Context())
+		if stats == nil {
 			c.JSON(http.StatusOK, gin.H{
 				"websocket": "not_initialized",
 			})
 			return
 		}
 
-		defaultHub.mu.RLock()
-		stats := map[string]interface{}{
-			"active_connections": len(defaultHub.connections),
-			"active_groups":      len(defaultHub.groups),
-			"groups":             make(map[string]int),
-		}
-
-		for groupName, group := range defaultHub.groups {
-			stats["groups"].(map[string]int)[groupName] = len(group)
-		}
-		defaultHub.mu.RUnlock()
-
 		c.JSON(http.StatusOK, gin.H{
 			"websocket_stats": stats,
 		})
 	}
 }
 
+// websocketStats connections and groups of the default hub, with the counts of every instance under
+// "cluster" when a backplane is set; nil when the hub is not initialized
+func websocketStats(ctx context.Context) map[string]interface{} {
+	if defaultHub == nil {
+		return nil
+	}
+
+	defaultHub.mu.RLock()
+	groups := make(map[string]int, len(defaultHub.groups))
+	for groupName, group := range defaultHub.groups {
+		groups[groupName] = len(group)
+	}
+	stats := map[string]interface{}{
+		"active_connections": len(defaultHub.connections),
+		"active_groups":      len(defaultHub.groups),
+		"groups":             groups,
+	}
+	defaultHub.mu.RUnlock()
+
+	cluster, err := defaultHub.clusterStats(ctx)
+	if err != nil {
+		stats["cluster_error"] = err.Error()
+	} else if cluster != nil {
+		stats["cluster"] = cluster
+	}
+	return stats
+}
+
 // WebSocketHandlerWrapper converts a WebSocketHandler to gin.HandlerFunc, allowing customization
 func WebSocketHandlerWrapper(handler WebSocketHandler) gin.HandlerFunc {
 	return func(c *gin.Context) {
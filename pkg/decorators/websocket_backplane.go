@@ -0,0 +1,411 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultWebSocketBackplaneChannel channel used when websocket.backplane.channel is empty
+const DefaultWebSocketBackplaneChannel = "deco:websocket"
+
+const (
+	// webSocketStatsInterval how often a hub reports its connection counts to the backplane
+	webSocketStatsInterval = 10 * time.Second
+	// webSocketStatsTTL how long the counts of an instance that stopped reporting are kept
+	webSocketStatsTTL = 30 * time.Second
+)
+
+// WebSocketBackplane propagates hub messages between the instances of a service, so Broadcast,
+// SendToGroup and SendToConnection reach the connections of every replica
+type WebSocketBackplane interface {
+	// Publish sends a payload to every subscribed instance, the publisher included
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe delivers published payloads to handler until ctx is done
+	Subscribe(ctx context.Context, handler func(payload []byte)) error
+	// ReportStats stores the connection counts of an instance for ttl
+	ReportStats(ctx context.Context, stats WebSocketInstanceStats, ttl time.Duration) error
+	// ClusterStats returns the counts of the instances that reported recently
+	ClusterStats(ctx context.Context) ([]WebSocketInstanceStats, error)
+	Close() error
+}
+
+// WebSocketBackplaneFactory creates a backplane from the websocket.backplane configuration
+type WebSocketBackplaneFactory func(config WebSocketBackplaneConfig) (WebSocketBackplane, error)
+
+// WebSocketInstanceStats connection counts of one instance
+type WebSocketInstanceStats struct {
+	Instance          string         `json:"instance"`
+	ActiveConnections int            `json:"active_connections"`
+	Groups            map[string]int `json:"groups"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// webSocketEnvelope message published on the backplane, Origin identifying the publishing hub
+type webSocketEnvelope struct {
+	Origin  string           `json:"origin"`
+	Message WebSocketMessage `json:"message"`
+}
+
+var (
+	webSocketBackplanes = map[string]WebSocketBackplaneFactory{
+		"redis": newRedisWebSocketBackplane,
+	}
+	webSocketBackplanesMux sync.RWMutex
+
+	// configuredBackplane backplane of websocket.backplane, attached to the hubs created by InitWebSocket
+	configuredBackplane    WebSocketBackplane
+	configuredBackplaneMux sync.Mutex
+)
+
+// RegisterWebSocketBackplane registers a backplane usable as websocket.backplane.type, e.g. one
+// backed by NATS
+func RegisterWebSocketBackplane(name string, factory WebSocketBackplaneFactory) {
+	webSocketBackplanesMux.Lock()
+	defer webSocketBackplanesMux.Unlock()
+	webSocketBackplanes[name] = factory
+}
+
+// getWebSocketBackplaneFactory returns the factory registered under name
+func getWebSocketBackplaneFactory(name string) (WebSocketBackplaneFactory, bool) {
+	webSocketBackplanesMux.RLock()
+	defer webSocketBackplanesMux.RUnlock()
+	factory, exists := webSocketBackplanes[name]
+	return factory, exists
+}
+
+// validate checks the websocket section of the configuration
+func (w WebSocketConfig) validate() error {
+	if w.Backplane.Type == "" {
+		return nil
+	}
+	if _, exists := getWebSocketBackplaneFactory(w.Backplane.Type); !exists {
+		return fmt.Errorf("unknown websocket.backplane.type '%s'", w.Backplane.Type)
+	}
+	return nil
+}
+
+// ConfigureWebSocket connects the backplane of websocket.backplane and attaches it to the default hub,
+// or to the hub InitWebSocket creates later. Without a backplane type, broadcasts stay local.
+func ConfigureWebSocket(config WebSocketConfig) error {
+	if config.Backplane.Type == "" {
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	factory, _ := getWebSocketBackplaneFactory(config.Backplane.Type)
+	backplane, err := factory(config.Backplane)
+	if err != nil {
+		return fmt.Errorf("websocket backplane: %v", err)
+	}
+
+	configuredBackplaneMux.Lock()
+	previous := configuredBackplane
+	configuredBackplane = backplane
+	configuredBackplaneMux.Unlock()
+	if previous != nil {
+		_ = previous.Close()
+	}
+
+	if defaultHub != nil {
+		return defaultHub.UseBackplane(backplane)
+	}
+	return nil
+}
+
+// getConfiguredBackplane returns the backplane installed by ConfigureWebSocket, if any
+func getConfiguredBackplane() WebSocketBackplane {
+	configuredBackplaneMux.Lock()
+	defer configuredBackplaneMux.Unlock()
+	return configuredBackplane
+}
+
+// UseBackplane propagates the messages of the hub through backplane and delivers the messages
+// published by the other instances to the local connections
+func (h *WebSocketHub) UseBackplane(backplane WebSocketBackplane) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := backplane.Subscribe(ctx, h.receiveRemote); err != nil {
+		cancel()
+		return err
+	}
+
+	h.mu.Lock()
+	if h.stopBackplane != nil {
+		h.stopBackplane()
+	}
+	h.backplane = backplane
+	h.stopBackplane = cancel
+	h.mu.Unlock()
+
+	go h.reportStats(ctx, backplane)
+	return nil
+}
+
+// getBackplane returns the backplane of the hub, nil when messages stay local
+func (h *WebSocketHub) getBackplane() WebSocketBackplane {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.backplane
+}
+
+// publish sends message to the other instances through the backplane, if any
+func (h *WebSocketHub) publish(message *WebSocketMessage) {
+	backplane := h.getBackplane()
+	if backplane == nil {
+		return
+	}
+
+	payload, err := json.Marshal(webSocketEnvelope{Origin: h.instanceID, Message: *message})
+	if err != nil {
+		log.Printf("WebSocket: Error encoding message for the backplane: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := backplane.Publish(ctx, payload); err != nil {
+		log.Printf("WebSocket: Error publishing to the backplane: %v", err)
+	}
+}
+
+// receiveRemote delivers a message published by another instance to the local connections
+func (h *WebSocketHub) receiveRemote(payload []byte) {
+	var envelope webSocketEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		log.Printf("WebSocket: Error decoding backplane message: %v", err)
+		return
+	}
+	if envelope.Origin == h.instanceID {
+		return // already delivered locally
+	}
+
+	select {
+	case h.broadcast <- &envelope.Message:
+	case <-h.done:
+	}
+}
+
+// localStats connection counts of the hub
+func (h *WebSocketHub) localStats() WebSocketInstanceStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := WebSocketInstanceStats{
+		Instance:          h.instanceID,
+		ActiveConnections: len(h.connections),
+		Groups:            make(map[string]int, len(h.groups)),
+		UpdatedAt:         time.Now(),
+	}
+	for groupName, group := range h.groups {
+		stats.Groups[groupName] = len(group)
+	}
+	return stats
+}
+
+// reportStats reports the connection counts of the hub until ctx is done
+func (h *WebSocketHub) reportStats(ctx context.Context, backplane WebSocketBackplane) {
+	ticker := time.NewTicker(webSocketStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := backplane.ReportStats(ctx, h.localStats(), webSocketStatsTTL); err != nil && ctx.Err() == nil {
+			log.Printf("WebSocket: Error reporting stats to the backplane: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// clusterStats aggregates the counts reported by every instance, the local ones being current
+func (h *WebSocketHub) clusterStats(ctx context.Context) (map[string]interface{}, error) {
+	backplane := h.getBackplane()
+	if backplane == nil {
+		return nil, nil
+	}
+	reported, err := backplane.ClusterStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local := h.localStats()
+	instances := []WebSocketInstanceStats{local}
+	for _, stats := range reported {
+		if stats.Instance != local.Instance {
+			instances = append(instances, stats)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Instance < instances[j].Instance })
+
+	total := 0
+	groups := make(map[string]int)
+	for _, stats := range instances {
+		total += stats.ActiveConnections
+		for groupName, count := range stats.Groups {
+			groups[groupName] += count
+		}
+	}
+	return map[string]interface{}{
+		"instances":          len(instances),
+		"active_connections": total,
+		"active_groups":      len(groups),
+		"groups":             groups,
+		"by_instance":        instances,
+	}, nil
+}
+
+// newWebSocketInstanceID identifies the hub of this process on the backplane
+func newWebSocketInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "deco"
+	}
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}
+
+// RedisWebSocketBackplane backplane using Redis pub/sub, with the counts of each instance stored under
+// "<channel>:instance:<id>"
+type RedisWebSocketBackplane struct {
+	client  *redis.Client
+	channel string
+
+	mu        sync.Mutex
+	instances map[string]bool // instances reported by this process, removed on Close
+}
+
+// newRedisWebSocketBackplane creates the "redis" backplane from the redis settings
+func newRedisWebSocketBackplane(config WebSocketBackplaneConfig) (WebSocketBackplane, error) {
+	return NewRedisWebSocketBackplane(GetRedisConfig(), config.Channel)
+}
+
+// NewRedisWebSocketBackplane connects to Redis, publishing on channel (DefaultWebSocketBackplaneChannel
+// when empty)
+func NewRedisWebSocketBackplane(config RedisConfig, channel string) (*RedisWebSocketBackplane, error) {
+	if channel == "" {
+		channel = DefaultWebSocketBackplaneChannel
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Address,
+		Password: config.Password,
+		DB:       config.DB,
+		PoolSize: config.PoolSize,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	return &RedisWebSocketBackplane{client: client, channel: channel, instances: make(map[string]bool)}, nil
+}
+
+// Publish implements WebSocketBackplane
+func (r *RedisWebSocketBackplane) Publish(ctx context.Context, payload []byte) error {
+	return r.client.Publish(ctx, r.channel, payload).Err()
+}
+
+// Subscribe implements WebSocketBackplane
+func (r *RedisWebSocketBackplane) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	pubsub := r.client.Subscribe(ctx, r.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to %s: %v", r.channel, err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				handler([]byte(message.Payload))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// instanceKey key of the counts of an instance
+func (r *RedisWebSocketBackplane) instanceKey(instance string) string {
+	return r.channel + ":instance:" + instance
+}
+
+// ReportStats implements WebSocketBackplane
+func (r *RedisWebSocketBackplane) ReportStats(ctx context.Context, stats WebSocketInstanceStats, ttl time.Duration) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.instances[stats.Instance] = true
+	r.mu.Unlock()
+	return r.client.Set(ctx, r.instanceKey(stats.Instance), data, ttl).Err()
+}
+
+// ClusterStats implements WebSocketBackplane
+func (r *RedisWebSocketBackplane) ClusterStats(ctx context.Context) ([]WebSocketInstanceStats, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, escapeRedisPattern(r.instanceKey(""))+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]WebSocketInstanceStats, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue // expired between SCAN and MGET
+		}
+		var stats WebSocketInstanceStats
+		if err := json.Unmarshal([]byte(data), &stats); err == nil {
+			instances = append(instances, stats)
+		}
+	}
+	return instances, nil
+}
+
+// Close implements WebSocketBackplane, removing the counts reported by this process
+func (r *RedisWebSocketBackplane) Close() error {
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.instances))
+	for instance := range r.instances {
+		keys = append(keys, r.instanceKey(instance))
+	}
+	r.mu.Unlock()
+
+	if len(keys) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := r.client.Del(ctx, keys...).Err(); err != nil {
+			log.Printf("WebSocket: Error removing backplane stats: %v", err)
+		}
+	}
+	return r.client.Close()
+}
@@ -0,0 +1,223 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackplane in-process backplane shared by the hubs of a test
+type memoryBackplane struct {
+	mu          sync.Mutex
+	subscribers map[int]func(payload []byte)
+	next        int
+	stats       map[string]WebSocketInstanceStats
+	closed      bool
+}
+
+func newMemoryBackplane() *memoryBackplane {
+	return &memoryBackplane{subscribers: make(map[int]func(payload []byte)), stats: make(map[string]WebSocketInstanceStats)}
+}
+
+func (m *memoryBackplane) Publish(ctx context.Context, payload []byte) error {
+	m.mu.Lock()
+	handlers := make([]func(payload []byte), 0, len(m.subscribers))
+	for _, handler := range m.subscribers {
+		handlers = append(handlers, handler)
+	}
+	m.mu.Unlock()
+	for _, handler := range handlers {
+		handler(payload)
+	}
+	return nil
+}
+
+func (m *memoryBackplane) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	m.mu.Lock()
+	id := m.next
+	m.next++
+	m.subscribers[id] = handler
+	m.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		delete(m.subscribers, id)
+		m.mu.Unlock()
+	}()
+	return nil
+}
+
+func (m *memoryBackplane) ReportStats(ctx context.Context, stats WebSocketInstanceStats, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats[stats.Instance] = stats
+	return nil
+}
+
+func (m *memoryBackplane) ClusterStats(ctx context.Context) ([]WebSocketInstanceStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	instances := make([]WebSocketInstanceStats, 0, len(m.stats))
+	for _, stats := range m.stats {
+		instances = append(instances, stats)
+	}
+	return instances, nil
+}
+
+func (m *memoryBackplane) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+// backplaneTestHub creates a hub attached to backplane with one registered connection
+func backplaneTestHub(t *testing.T, backplane WebSocketBackplane, connID string) (*WebSocketHub, *WebSocketConnection) {
+	hub := InitWebSocket(WebSocketConfig{Enabled: true, ReadBuffer: 1024, WriteBuffer: 1024, PingInterval: "54s", PongTimeout: "60s"})
+	t.Cleanup(hub.Close)
+	require.NoError(t, hub.UseBackplane(backplane))
+
+	conn := &WebSocketConnection{ID: connID, Hub: hub, Send: make(chan []byte, 256), Groups: make(map[string]bool)}
+	hub.register <- conn
+	<-conn.Send // welcome
+	return hub, conn
+}
+
+// receive returns the next message of conn, failing after a second
+func receive(t *testing.T, conn *WebSocketConnection) WebSocketMessage {
+	t.Helper()
+	select {
+	case data := <-conn.Send:
+		var message WebSocketMessage
+		require.NoError(t, json.Unmarshal(data, &message))
+		return message
+	case <-time.After(time.Second):
+		t.Fatalf("no message for %s", conn.ID)
+		return WebSocketMessage{}
+	}
+}
+
+// assertNoMessage fails when conn receives a message shortly
+func assertNoMessage(t *testing.T, conn *WebSocketConnection) {
+	t.Helper()
+	select {
+	case data := <-conn.Send:
+		t.Fatalf("unexpected message for %s: %s", conn.ID, data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWebSocketBackplane_PropagatesAcrossHubs(t *testing.T) {
+	backplane := newMemoryBackplane()
+	hubA, connA := backplaneTestHub(t, backplane, "conn-a")
+	hubB, connB := backplaneTestHub(t, backplane, "conn-b")
+	require.NotEqual(t, hubA.instanceID, hubB.instanceID)
+
+	// Broadcast reaches both instances, once each
+	hubA.Broadcast(&WebSocketMessage{Type: "news", Data: "hello"})
+	assert.Equal(t, "hello", receive(t, connA).Data)
+	assert.Equal(t, "hello", receive(t, connB).Data)
+	assertNoMessage(t, connA)
+
+	// A group joined on one instance receives the messages sent from the other
+	require.NoError(t, hubA.JoinGroup("conn-a", "chat"))
+	hubB.SendToGroup("chat", &WebSocketMessage{Type: "chat", Data: "hi"})
+	message := receive(t, connA)
+	assert.Equal(t, "chat", message.Group)
+	assert.Equal(t, "hi", message.Data)
+	assertNoMessage(t, connB)
+
+	// A connection of another instance is reached through the backplane
+	hubB.SendToConnection("conn-a", &WebSocketMessage{Type: "direct", Data: "psst"})
+	assert.Equal(t, "psst", receive(t, connA).Data)
+	assertNoMessage(t, connB)
+
+	// A closed hub stops receiving
+	hubB.Close()
+	hubA.Broadcast(&WebSocketMessage{Type: "news", Data: "bye"})
+	assert.Equal(t, "bye", receive(t, connA).Data)
+}
+
+func TestWebSocketBackplane_ClusterStats(t *testing.T) {
+	backplane := newMemoryBackplane()
+	hubA, _ := backplaneTestHub(t, backplane, "conn-a")
+	require.NoError(t, hubA.JoinGroup("conn-a", "chat"))
+	hubB, _ := backplaneTestHub(t, backplane, "conn-b")
+	require.NoError(t, hubB.JoinGroup("conn-b", "chat"))
+	require.NoError(t, backplane.ReportStats(context.Background(), hubA.localStats(), time.Minute))
+
+	// hubB is the default hub; its own counts are current, hubA's as last reported
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stats", WebSocketStatsHandler())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Stats struct {
+			ActiveConnections int `json:"active_connections"`
+			Cluster           struct {
+				Instances         int            `json:"instances"`
+				ActiveConnections int            `json:"active_connections"`
+				Groups            map[string]int `json:"groups"`
+			} `json:"cluster"`
+		} `json:"websocket_stats"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Stats.ActiveConnections)
+	assert.Equal(t, 2, body.Stats.Cluster.Instances)
+	assert.Equal(t, 2, body.Stats.Cluster.ActiveConnections)
+	assert.Equal(t, map[string]int{"chat": 2}, body.Stats.Cluster.Groups)
+}
+
+func TestConfigureWebSocket(t *testing.T) {
+	backplane := newMemoryBackplane()
+	var configured WebSocketBackplaneConfig
+	RegisterWebSocketBackplane("memory", func(config WebSocketBackplaneConfig) (WebSocketBackplane, error) {
+		configured = config
+		return backplane, nil
+	})
+	defer func() {
+		webSocketBackplanesMux.Lock()
+		delete(webSocketBackplanes, "memory")
+		webSocketBackplanesMux.Unlock()
+	}()
+
+	// Without a type nothing is connected
+	require.NoError(t, ConfigureWebSocket(WebSocketConfig{}))
+	assert.Nil(t, getConfiguredBackplane())
+
+	assert.ErrorContains(t, WebSocketConfig{Backplane: WebSocketBackplaneConfig{Type: "carrier-pigeon"}}.validate(), "unknown websocket.backplane.type")
+	assert.ErrorContains(t, ConfigureWebSocket(WebSocketConfig{Backplane: WebSocketBackplaneConfig{Type: "carrier-pigeon"}}), "carrier-pigeon")
+
+	hub := InitWebSocket(WebSocketConfig{Enabled: true, ReadBuffer: 1024, WriteBuffer: 1024, PingInterval: "54s", PongTimeout: "60s"})
+	require.NoError(t, ConfigureWebSocket(WebSocketConfig{Backplane: WebSocketBackplaneConfig{Type: "memory", Channel: "chat"}}))
+	assert.Equal(t, "chat", configured.Channel)
+	assert.Same(t, backplane, hub.getBackplane().(*memoryBackplane), "attached to the existing default hub")
+
+	// Hubs created later pick it up too; closing releases it
+	later := InitWebSocket(WebSocketConfig{Enabled: true, ReadBuffer: 1024, WriteBuffer: 1024, PingInterval: "54s", PongTimeout: "60s"})
+	assert.NotNil(t, later.getBackplane())
+	hub.Close()
+	CloseWebSocketHub()
+	assert.Nil(t, getConfiguredBackplane())
+	assert.True(t, backplane.closed)
+}
+
+func TestLoadConfig_WebSocketBackplane(t *testing.T) {
+	path := t.TempDir() + "/.deco.yaml"
+	writeTestFile(t, path, "version: \"1.0\"\nhandlers:\n  include: [\"*.go\"]\nwebsocket:\n  backplane:\n    type: redis\n    channel: chat\n")
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, WebSocketBackplaneConfig{Type: "redis", Channel: "chat"}, config.WebSocket.Backplane)
+	assert.Equal(t, 1024, config.WebSocket.ReadBuffer, "defaults still apply")
+}
@@ -0,0 +1,220 @@
+package decorators
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWorkspaceFile the file marking the root of a repository with several services
+const DefaultWorkspaceFile = "deco.workspace.yaml"
+
+// Workspace a repository holding several services, each with its own .deco.yaml:
+//
+//	# deco.workspace.yaml
+//	defaults:          # merged under the .deco.yaml of every service
+//	  redis:
+//	    address: "redis:6379"
+//	services:          # service directories; discovered when omitted
+//	  - services/users
+//	  - services/orders
+type Workspace struct {
+	Defaults yaml.Node `yaml:"defaults"`
+	Services []string  `yaml:"services"`
+
+	file string // loaded workspace file
+	root string // directory of the workspace file
+}
+
+// FindWorkspaceFile returns the workspace file of dir or of its closest parent, stopping at the
+// repository root (the directory holding .git); empty when there is none
+func FindWorkspaceFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, DefaultWorkspaceFile)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadWorkspace loads a workspace file, checking its defaults against the configuration schema
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading workspace %s: %v", path, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, &ConfigValidationError{Issues: []ConfigIssue{{File: path, Message: err.Error()}}}
+	}
+	workspace := &Workspace{file: path, root: filepath.Dir(path)}
+	if len(document.Content) == 0 {
+		return workspace, nil
+	}
+
+	var issues []ConfigIssue
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ConfigValidationError{Issues: []ConfigIssue{{File: path, Line: root.Line, Column: root.Column, Message: "expected object, found " + describeConfigNode(root)}}}
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "defaults":
+			validateConfigNode(getConfigSchema(), value, "defaults", path, &issues)
+		case "services":
+			if value.Kind != yaml.SequenceNode {
+				issues = append(issues, ConfigIssue{File: path, Line: value.Line, Column: value.Column, Path: "services", Message: "expected array, found " + describeConfigNode(value)})
+				continue
+			}
+			for j, service := range value.Content {
+				if service.Kind != yaml.ScalarNode || !filepath.IsLocal(filepath.FromSlash(service.Value)) {
+					issues = append(issues, ConfigIssue{File: path, Line: service.Line, Column: service.Column, Path: fmt.Sprintf("services[%d]", j), Message: "expected a directory inside the workspace"})
+				}
+			}
+		default:
+			issues = append(issues, ConfigIssue{File: path, Line: key.Line, Column: key.Column, Path: key.Value, Message: "unknown key (valid: defaults, services)"})
+		}
+	}
+	if len(issues) > 0 {
+		return nil, &ConfigValidationError{Issues: issues}
+	}
+
+	if err := root.Decode(workspace); err != nil {
+		return nil, fmt.Errorf("error parsing workspace %s: %v", path, err)
+	}
+	return workspace, nil
+}
+
+// Root the directory of the workspace file
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// ConfigFiles returns the .deco.yaml of each service, sorted: those of the listed services, or
+// every one found below the workspace root when none is listed
+func (w *Workspace) ConfigFiles() ([]string, error) {
+	if len(w.Services) == 0 {
+		return DiscoverConfigFiles(w.root)
+	}
+
+	files := make([]string, 0, len(w.Services))
+	for _, service := range w.Services {
+		file := filepath.Join(w.root, filepath.FromSlash(service), ".deco.yaml")
+		if _, err := os.Stat(file); err != nil {
+			return nil, fmt.Errorf("service '%s' of %s has no .deco.yaml", service, w.file)
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// DiscoverConfigFiles returns the .deco.yaml files below root, sorted, skipping hidden directories,
+// vendor, testdata and node_modules
+func DiscoverConfigFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == ".deco.yaml" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error discovering configurations in %s: %v", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// applyWorkspaceDefaults merges the defaults of the workspace enclosing configPath under the
+// configuration file content; values of the file win, objects are merged key by key
+func applyWorkspaceDefaults(data []byte, configPath string) ([]byte, string, error) {
+	workspaceFile := FindWorkspaceFile(filepath.Dir(configPath))
+	if workspaceFile == "" {
+		return data, "", nil
+	}
+	workspace, err := LoadWorkspace(workspaceFile)
+	if err != nil {
+		return nil, "", err
+	}
+	if workspace.Defaults.Kind != yaml.MappingNode {
+		return data, "", nil
+	}
+
+	// The file is checked before merging, so its issues keep their lines
+	if issues := validateConfigSchema(data, configPath); len(issues) > 0 {
+		return nil, "", &ConfigValidationError{Issues: issues}
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, "", fmt.Errorf("error parsing da configuration: %v", err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	merged, err := yaml.Marshal(mergeConfigNodes(copyYAMLNode(&workspace.Defaults), document.Content[0]))
+	if err != nil {
+		return nil, "", fmt.Errorf("error serializing configuration: %v", err)
+	}
+	return merged, workspaceFile, nil
+}
+
+// mergeConfigNodes sets the keys of override on base: objects are merged recursively, other
+// values (scalars, arrays) replace those of base, and null values leave base untouched
+func mergeConfigNodes(base, override *yaml.Node) *yaml.Node {
+	if override.Kind == yaml.ScalarNode && override.Tag == "!!null" {
+		return base
+	}
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		if j := mappingKeyIndex(base, key.Value); j >= 0 {
+			base.Content[j+1] = mergeConfigNodes(base.Content[j+1], value)
+			continue
+		}
+		base.Content = append(base.Content, key, value)
+	}
+	return base
+}
+
+// mappingKeyIndex index of the key node of name in a mapping's content, -1 when absent
+func mappingKeyIndex(mapping *yaml.Node, name string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,119 @@
+package decorators
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workspaceTestRepo creates a repository with a workspace file and the services users and orders
+func workspaceTestRepo(t *testing.T, workspace string) string {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	writeTestFile(t, filepath.Join(root, DefaultWorkspaceFile), workspace)
+	writeTestFile(t, filepath.Join(root, "services/users/.deco.yaml"), "version: \"1.0\"\nopenapi:\n  title: \"Users\"\n")
+	writeTestFile(t, filepath.Join(root, "services/orders/.deco.yaml"), "version: \"1.0\"\nredis:\n  enabled: false\n")
+	return root
+}
+
+func TestLoadConfig_WorkspaceDefaults(t *testing.T) {
+	root := workspaceTestRepo(t, `defaults:
+  handlers:
+    include: ["handlers/*.go", "api/*.go"]
+  redis:
+    enabled: true
+    address: "redis:6379"
+  openapi:
+    title: "Shared"
+    version: "2.0.0"
+`)
+
+	config, err := LoadConfig(filepath.Join(root, "services/users/.deco.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Users", config.OpenAPI.Title, "the service wins")
+	assert.Equal(t, "2.0.0", config.OpenAPI.Version, "objects are merged key by key")
+	assert.Equal(t, []string{"handlers/*.go", "api/*.go"}, config.Handlers.Include)
+	assert.Equal(t, "redis:6379", config.Redis.Address)
+	assert.Equal(t, filepath.Join(root, "services/users"), config.baseDir, "paths stay relative to the service")
+
+	config, err = LoadConfig(filepath.Join(root, "services/orders/.deco.yaml"))
+	require.NoError(t, err)
+	assert.False(t, config.Redis.Enabled)
+	assert.Equal(t, "redis:6379", config.Redis.Address)
+
+	rendered, err := RenderEffectiveConfig(config, nil, "yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), DefaultWorkspaceFile+" + "+filepath.Join(root, "services/orders/.deco.yaml"))
+}
+
+func TestLoadConfig_WorkspaceIssues(t *testing.T) {
+	root := workspaceTestRepo(t, "defaults:\n  redis:\n    adress: \"redis:6379\"\n")
+	_, err := LoadConfig(filepath.Join(root, "services/users/.deco.yaml"))
+	var invalid *ConfigValidationError
+	require.True(t, errors.As(err, &invalid))
+	require.Len(t, invalid.Issues, 1)
+	assert.Equal(t, filepath.Join(root, DefaultWorkspaceFile), invalid.Issues[0].File)
+	assert.Equal(t, "defaults.redis.adress", invalid.Issues[0].Path)
+	assert.Equal(t, 3, invalid.Issues[0].Line)
+
+	// Issues of the service file keep their line
+	root = workspaceTestRepo(t, "defaults:\n  redis:\n    enabled: true\n")
+	writeTestFile(t, filepath.Join(root, "services/users/.deco.yaml"), "version: \"1.0\"\nredis:\n  enabled: sometimes\n")
+	_, err = LoadConfig(filepath.Join(root, "services/users/.deco.yaml"))
+	require.True(t, errors.As(err, &invalid))
+	require.Len(t, invalid.Issues, 1)
+	assert.Equal(t, "redis.enabled", invalid.Issues[0].Path)
+	assert.Equal(t, 3, invalid.Issues[0].Line)
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	root := workspaceTestRepo(t, "services:\n  - services/users\n  - ../elsewhere\nextra: true\n")
+	_, err := LoadWorkspace(filepath.Join(root, DefaultWorkspaceFile))
+	var invalid *ConfigValidationError
+	require.True(t, errors.As(err, &invalid))
+	require.Len(t, invalid.Issues, 2)
+	assert.Equal(t, "services[1]", invalid.Issues[0].Path)
+	assert.Equal(t, "extra", invalid.Issues[1].Path)
+
+	writeTestFile(t, filepath.Join(root, DefaultWorkspaceFile), "services:\n  - services/users\n")
+	workspace, err := LoadWorkspace(filepath.Join(root, DefaultWorkspaceFile))
+	require.NoError(t, err)
+	files, err := workspace.ConfigFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "services/users/.deco.yaml")}, files)
+
+	writeTestFile(t, filepath.Join(root, DefaultWorkspaceFile), "services:\n  - services/billing\n")
+	workspace, err = LoadWorkspace(filepath.Join(root, DefaultWorkspaceFile))
+	require.NoError(t, err)
+	_, err = workspace.ConfigFiles()
+	assert.ErrorContains(t, err, "service 'services/billing'")
+}
+
+func TestWorkspace_DiscoversServices(t *testing.T) {
+	root := workspaceTestRepo(t, "defaults: {}\n")
+	writeTestFile(t, filepath.Join(root, "vendor/lib/.deco.yaml"), "version: \"1.0\"\n")
+	writeTestFile(t, filepath.Join(root, ".cache/.deco.yaml"), "version: \"1.0\"\n")
+
+	workspace, err := LoadWorkspace(filepath.Join(root, DefaultWorkspaceFile))
+	require.NoError(t, err)
+	files, err := workspace.ConfigFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "services/orders/.deco.yaml"),
+		filepath.Join(root, "services/users/.deco.yaml"),
+	}, files)
+}
+
+func TestFindWorkspaceFile(t *testing.T) {
+	root := workspaceTestRepo(t, "defaults: {}\n")
+	assert.Equal(t, filepath.Join(root, DefaultWorkspaceFile), FindWorkspaceFile(filepath.Join(root, "services/users")))
+
+	// The search stops at the repository root
+	nested := filepath.Join(root, "services/users/tools")
+	require.NoError(t, os.MkdirAll(filepath.Join(nested, ".git"), 0o755))
+	assert.Empty(t, FindWorkspaceFile(nested))
+}
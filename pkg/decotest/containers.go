@@ -0,0 +1,138 @@
+package decotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// Container ephemeral dependency started by WithContainers with testcontainers-go. Start runs the
+// image and returns the container, terminated at the end of the test, with the host:port it listens on.
+type Container struct {
+	Name  string
+	Image string
+	Start func(ctx context.Context, image string) (testcontainers.Container, string, error)
+}
+
+// Redis container; WithContainers points the Redis of the framework (cache, rate limiting, @Dedupe) to it
+var Redis = Container{
+	Name:  "redis",
+	Image: "redis:7-alpine",
+	Start: func(ctx context.Context, image string) (testcontainers.Container, string, error) {
+		container, err := redis.Run(ctx, image)
+		if err != nil {
+			return container, "", err
+		}
+		addr, err := container.Endpoint(ctx, "")
+		return container, addr, err
+	},
+}
+
+// Kafka single-node KRaft broker; the address is in Containers.Addr("kafka") for the Subscriber and
+// Publisher adapters of @Subscribe and the outbox
+var Kafka = Container{
+	Name:  "kafka",
+	Image: "confluentinc/confluent-local:7.5.0",
+	Start: func(ctx context.Context, image string) (testcontainers.Container, string, error) {
+		container, err := kafka.Run(ctx, image, kafka.WithClusterID("decotest"))
+		if err != nil {
+			return container, "", err
+		}
+		brokers, err := container.Brokers(ctx)
+		if err != nil {
+			return container, "", err
+		}
+		return container, brokers[0], nil
+	},
+}
+
+// ContainerStartTimeout time a container has to become ready
+var ContainerStartTimeout = 2 * time.Minute
+
+// Containers dependencies started for a test
+type Containers struct {
+	// Config framework configuration pointing to the containers (redis enabled, cache and rate
+	// limiting on Redis when a Redis container runs)
+	Config *decorators.Config
+
+	addrs map[string]string
+}
+
+// Addr host:port of a container by name
+func (c *Containers) Addr(name string) string {
+	return c.addrs[name]
+}
+
+// WithContainers starts the containers for an integration test and wires them into the framework.
+// With a Redis container, ConfigureRedis points the middlewares created afterwards with type=redis
+// (or a Redis-typed Config) to it; Cache.Type and RateLimit.Type are only set on the returned Config,
+// so markers without type=redis keep their in-memory stores. Containers are terminated and the
+// previous Redis restored when the test ends. The test is skipped under -short or when docker is
+// unavailable.
+//
+//	deps := decotest.WithContainers(t, decotest.Redis, decotest.Kafka)
+//	marker, _ := decotest.NewMarker("RateLimit", []string{"limit=1", "window=1m", "type=redis"})
+func WithContainers(t testing.TB, containers ...Container) *Containers {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("decotest: containers are not started with -short")
+	}
+	if err := dockerAvailable(); err != nil {
+		t.Skipf("decotest: docker is not available: %v", err)
+	}
+
+	config := decorators.DefaultConfig()
+	deps := &Containers{Config: config, addrs: make(map[string]string)}
+	for _, container := range containers {
+		addr, err := startContainer(t, container)
+		if err != nil {
+			t.Fatalf("decotest: starting %s: %v", container.Name, err)
+		}
+		deps.addrs[container.Name] = addr
+	}
+
+	if addr, ok := deps.addrs[Redis.Name]; ok {
+		config.Redis.Enabled = true
+		config.Redis.Address = addr
+		config.Cache.Type = "redis"
+		config.RateLimit.Type = "redis"
+
+		previous := decorators.GetRedisConfig()
+		decorators.ConfigureRedis(config.Redis)
+		t.Cleanup(func() { decorators.ConfigureRedis(previous) })
+	}
+	return deps
+}
+
+// startContainer runs a container, terminated at the end of the test, and returns its address
+func startContainer(t testing.TB, container Container) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ContainerStartTimeout)
+	defer cancel()
+
+	started, addr, err := container.Start(ctx, container.Image)
+	testcontainers.CleanupContainer(t, started)
+	return addr, err
+}
+
+// dockerAvailable checks that the docker provider of testcontainers-go answers
+func dockerAvailable() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+	return provider.Health(context.Background())
+}
@@ -0,0 +1,60 @@
+package decotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+func TestWithContainers_Redis(t *testing.T) {
+	deps := WithContainers(t, Redis)
+	assert.Equal(t, deps.Addr("redis"), deps.Config.Redis.Address)
+
+	marker, err := NewMarker("RateLimit", []string{"limit=1", "window=1m", "type=redis"})
+	require.NoError(t, err)
+	marker.Run(httptest.NewRequest(http.MethodGet, "/items", http.NoBody)).AssertStatus(t, http.StatusOK)
+	marker.Run(httptest.NewRequest(http.MethodGet, "/items", http.NoBody)).AssertStatus(t, http.StatusTooManyRequests)
+}
+
+func TestWithContainers_RestoresRedis(t *testing.T) {
+	previous := decorators.RedisConfig{Address: "redis.internal:6380", DB: 2}
+	decorators.ConfigureRedis(previous)
+	defer decorators.ConfigureRedis(decorators.DefaultConfig().Redis)
+
+	t.Run("containers", func(t *testing.T) {
+		deps := WithContainers(t, Redis)
+		assert.Equal(t, deps.Addr("redis"), decorators.GetRedisConfig().Address)
+	})
+	assert.Equal(t, previous, decorators.GetRedisConfig())
+}
+
+func TestWithContainers_RedisRateLimitAcrossReplicas(t *testing.T) {
+	WithContainers(t, Redis)
+
+	for i, algorithm := range []string{"sliding_window", "token_bucket"} {
+		// Two markers are two replicas: each has its own limiter, both count in the same Redis key
+		args := []string{"limit=2", "window=1m", "type=redis", "algorithm=" + algorithm}
+		replicas := make([]*Marker, 2)
+		for j := range replicas {
+			marker, err := NewMarker("RateLimit", args)
+			require.NoError(t, err)
+			replicas[j] = marker
+		}
+
+		request := func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/items", http.NoBody)
+			req.RemoteAddr = fmt.Sprintf("198.51.100.%d:1234", i+1)
+			return req
+		}
+		replicas[0].Run(request()).AssertStatus(t, http.StatusOK)
+		replicas[1].Run(request()).AssertStatus(t, http.StatusOK)
+		replicas[0].Run(request()).AssertStatus(t, http.StatusTooManyRequests)
+		replicas[1].Run(request()).AssertStatus(t, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,164 @@
+// Package decotest helps testing marker factories (built-in or registered with
+// decorators.RegisterMarker) without generating code or wiring a full engine.
+//
+//	result, err := decotest.RunMarker("Cache", []string{"ttl=5m"}, httptest.NewRequest("GET", "/users/1", nil))
+//	require.NoError(t, err)
+//	result.AssertStatus(t, http.StatusOK)
+//	result.AssertHeader(t, "X-Cache", "MISS")
+package decotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// Result outcome of one request through a marker middleware
+type Result struct {
+	Recorder   *httptest.ResponseRecorder
+	Keys       map[string]interface{} // context keys after the chain ran
+	Aborted    bool                   // the middleware aborted the chain
+	NextCalled bool                   // the request reached the handler
+}
+
+// Option customizes how a marker is run
+type Option func(*Marker)
+
+// WithHandler replaces the handler behind the middleware (by default it answers 200 "ok")
+func WithHandler(handler gin.HandlerFunc) Option {
+	return func(m *Marker) { m.handler = handler }
+}
+
+// WithRoute sets the route pattern the request is matched against, e.g. "/users/:id"
+// (by default the request path itself), so path parameters and c.FullPath() are available
+func WithRoute(pattern string) Option {
+	return func(m *Marker) { m.route = pattern }
+}
+
+// WithContext runs setup before the middleware, e.g. to set "user_id" as an @Auth would
+func WithContext(setup gin.HandlerFunc) Option {
+	return func(m *Marker) { m.setup = append(m.setup, setup) }
+}
+
+// Marker middleware built once from a marker factory, to run several requests through it
+// (e.g. a cache miss followed by a hit)
+type Marker struct {
+	middleware gin.HandlerFunc
+	handler    gin.HandlerFunc
+	route      string
+	setup      []gin.HandlerFunc
+}
+
+// NewMarker builds the middleware of a registered marker
+func NewMarker(name string, args []string, opts ...Option) (*Marker, error) {
+	config, ok := decorators.GetMarker(name)
+	if !ok {
+		return nil, fmt.Errorf("marker @%s is not registered", name)
+	}
+	if config.Factory == nil {
+		return nil, fmt.Errorf("marker @%s does not create middleware", name)
+	}
+	return NewFactoryMarker(config.Factory, args, opts...), nil
+}
+
+// NewFactoryMarker builds the middleware of a factory that is not (yet) registered
+func NewFactoryMarker(factory decorators.MarkerFactory, args []string, opts ...Option) *Marker {
+	marker := &Marker{
+		middleware: factory(args),
+		handler:    func(c *gin.Context) { c.String(http.StatusOK, "ok") },
+	}
+	for _, opt := range opts {
+		opt(marker)
+	}
+	return marker
+}
+
+// Run serves a request through the middleware and the handler
+func (m *Marker) Run(req *http.Request) *Result {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	result := &Result{Recorder: httptest.NewRecorder()}
+
+	// The context is recycled once the request is served, so its state is copied while it is still live
+	observe := func(c *gin.Context) {
+		c.Next()
+		result.Aborted = c.IsAborted() && !result.NextCalled
+		result.Keys = make(map[string]interface{}, len(c.Keys))
+		for key, value := range c.Keys {
+			result.Keys[key] = value
+		}
+	}
+	handler := func(c *gin.Context) {
+		result.NextCalled = true
+		m.handler(c)
+	}
+
+	route := m.route
+	if route == "" {
+		route = req.URL.Path
+	}
+	handlers := append([]gin.HandlerFunc{observe}, m.setup...)
+	handlers = append(handlers, m.middleware, handler)
+	engine.Handle(req.Method, route, handlers...)
+	engine.ServeHTTP(result.Recorder, req)
+	return result
+}
+
+// RunMarker builds the middleware of a registered marker and serves one request through it
+func RunMarker(name string, args []string, req *http.Request, opts ...Option) (*Result, error) {
+	marker, err := NewMarker(name, args, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return marker.Run(req), nil
+}
+
+// AssertStatus checks the response status code
+func (r *Result) AssertStatus(t testing.TB, status int) {
+	t.Helper()
+	if r.Recorder.Code != status {
+		t.Errorf("status = %d, want %d (body: %s)", r.Recorder.Code, status, r.Recorder.Body.String())
+	}
+}
+
+// AssertHeader checks a response header
+func (r *Result) AssertHeader(t testing.TB, name, value string) {
+	t.Helper()
+	if got := r.Recorder.Header().Get(name); got != value {
+		t.Errorf("header %s = %q, want %q", name, got, value)
+	}
+}
+
+// AssertContextKey checks a context key set during the chain
+func (r *Result) AssertContextKey(t testing.TB, key string, value interface{}) {
+	t.Helper()
+	got, ok := r.Keys[key]
+	if !ok {
+		t.Errorf("context key %q is not set", key)
+		return
+	}
+	if fmt.Sprint(got) != fmt.Sprint(value) {
+		t.Errorf("context key %q = %v, want %v", key, got, value)
+	}
+}
+
+// AssertAborted checks the middleware stopped the request before the handler
+func (r *Result) AssertAborted(t testing.TB) {
+	t.Helper()
+	if r.NextCalled {
+		t.Errorf("the request reached the handler, want it aborted (status %d)", r.Recorder.Code)
+	}
+}
+
+// AssertNext checks the request reached the handler
+func (r *Result) AssertNext(t testing.TB) {
+	t.Helper()
+	if !r.NextCalled {
+		t.Errorf("the middleware stopped the request with status %d (body: %s)", r.Recorder.Code, r.Recorder.Body.String())
+	}
+}
@@ -0,0 +1,65 @@
+package decotest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMarker_Cache(t *testing.T) {
+	calls := 0
+	marker, err := NewMarker("Cache", []string{"duration=1m", "type=memory"}, WithRoute("/users/:id"), WithHandler(func(c *gin.Context) {
+		calls++
+		c.String(http.StatusOK, "user "+c.Param("id"))
+	}))
+	require.NoError(t, err)
+
+	first := marker.Run(httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody))
+	first.AssertStatus(t, http.StatusOK)
+	first.AssertHeader(t, "X-Cache", "MISS")
+	first.AssertNext(t)
+
+	second := marker.Run(httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody))
+	second.AssertHeader(t, "X-Cache", "HIT")
+	assert.Equal(t, "user 1", second.Recorder.Body.String())
+	assert.Equal(t, 1, calls)
+}
+
+func TestRunMarker_Aborts(t *testing.T) {
+	result, err := RunMarker("RequireHeader", []string{"X-Tenant-ID"}, httptest.NewRequest(http.MethodGet, "/orders", http.NoBody))
+	require.NoError(t, err)
+	result.AssertStatus(t, http.StatusBadRequest)
+	result.AssertAborted(t)
+	assert.True(t, result.Aborted)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	req.Header.Set("X-Tenant-ID", "acme")
+	result, err = RunMarker("RequireHeader", []string{"X-Tenant-ID"}, req)
+	require.NoError(t, err)
+	result.AssertStatus(t, http.StatusOK)
+	result.AssertNext(t)
+}
+
+func TestNewFactoryMarker_ContextKeys(t *testing.T) {
+	factory := func(args []string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set("tenant", args[0]+":"+c.GetString("user_id"))
+			c.Next()
+		}
+	}
+	marker := NewFactoryMarker(factory, []string{"acme"}, WithContext(func(c *gin.Context) { c.Set("user_id", "42") }))
+	result := marker.Run(httptest.NewRequest(http.MethodPost, "/", http.NoBody))
+	result.AssertContextKey(t, "tenant", "acme:42")
+	result.AssertNext(t)
+}
+
+func TestNewMarker_Errors(t *testing.T) {
+	_, err := NewMarker("Nope", nil)
+	assert.ErrorContains(t, err, "not registered")
+	_, err = RunMarker("Summary", nil, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.ErrorContains(t, err, "does not create middleware")
+}
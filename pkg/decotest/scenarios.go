@@ -0,0 +1,43 @@
+package decotest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/deco/pkg/decorators"
+)
+
+// RunScenarios runs the scenarios of a deco test file in process against handler (usually the engine
+// of deco.Default), one subtest per scenario. Steps are matched against the registered routes; the
+// routes no step called are logged.
+//
+//	decotest.RunScenarios(t, "testdata/scenarios.yaml", deco.Default())
+func RunScenarios(t *testing.T, path string, handler http.Handler) *decorators.ScenarioReport {
+	t.Helper()
+	file, err := decorators.LoadScenarioFile(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	report := NewScenarioRunner(handler).Run(context.Background(), file)
+	for _, scenario := range report.Scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			for _, step := range scenario.Steps {
+				for _, failure := range step.Failures {
+					t.Errorf("%s (%s %s): %s", step.Name, step.Method, step.Path, failure)
+				}
+			}
+		})
+	}
+	if len(report.Uncovered) > 0 {
+		t.Logf("routes not called by any scenario: %s", strings.Join(report.Uncovered, ", "))
+	}
+	return report
+}
+
+// NewScenarioRunner in-process runner of the registered routes, to set variables or routes before Run
+func NewScenarioRunner(handler http.Handler) *decorators.ScenarioRunner {
+	return decorators.NewHandlerScenarioRunner(handler)
+}
@@ -0,0 +1,40 @@
+package decotest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScenarios(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/orders", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"id": "o-1"}) })
+	engine.GET("/orders/:id", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"id": c.Param("id")}) })
+
+	path := filepath.Join(t.TempDir(), "scenarios.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+scenarios:
+  - name: order lifecycle
+    steps:
+      - method: POST
+        path: /orders
+        expect:
+          status: 201
+        capture:
+          order: $.id
+      - method: GET
+        path: /orders/${order}
+        expect:
+          json:
+            $.id: o-1
+`), 0o600))
+
+	report := RunScenarios(t, path, engine)
+	assert.Equal(t, 1, report.Passed)
+}
@@ -0,0 +1,287 @@
+package decotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SnapshotDir directory of the snapshots, relative to the package of the test
+var SnapshotDir = filepath.Join("testdata", "snapshots")
+
+// UpdateSnapshotsEnv environment variable that rewrites the snapshots instead of comparing them
+const UpdateSnapshotsEnv = "DECO_UPDATE_SNAPSHOTS"
+
+// Default scrubbing rules: values that change on every run
+var (
+	snapshotUUID      = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	snapshotTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+)
+
+// snapshotContext lines kept around the changes of a snapshot diff
+const snapshotContext = 3
+
+// SnapshotOption customizes Snapshot
+type SnapshotOption func(*snapshotOptions)
+
+type snapshotOptions struct {
+	name    string
+	fields  map[string]bool
+	values  []snapshotRule
+	headers []string
+}
+
+// snapshotRule replaces the matches of a pattern in string values
+type snapshotRule struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}
+
+// ScrubFields replaces the values of the JSON fields with these names, at any depth, by "<scrubbed>"
+func ScrubFields(names ...string) SnapshotOption {
+	return func(o *snapshotOptions) {
+		for _, name := range names {
+			o.fields[name] = true
+		}
+	}
+}
+
+// ScrubValues replaces the matches of pattern in string values by placeholder, e.g. order numbers
+func ScrubValues(pattern *regexp.Regexp, placeholder string) SnapshotOption {
+	return func(o *snapshotOptions) {
+		o.values = append(o.values, snapshotRule{pattern: pattern, placeholder: placeholder})
+	}
+}
+
+// SnapshotHeaders also records these response headers (Content-Type is always recorded)
+func SnapshotHeaders(names ...string) SnapshotOption {
+	return func(o *snapshotOptions) { o.headers = append(o.headers, names...) }
+}
+
+// SnapshotName names the snapshot file instead of the test name
+func SnapshotName(name string) SnapshotOption {
+	return func(o *snapshotOptions) { o.name = name }
+}
+
+// snapshotCalls snapshots taken by each running test, to number the following ones
+var (
+	snapshotCalls   = make(map[testing.TB]int)
+	snapshotCallsMu sync.Mutex
+)
+
+// Snapshot compares the response to the snapshot stored under testdata/snapshots, named after the test
+// (several snapshots in a test are numbered). The response is stored as canonical JSON: status, content
+// type and body with sorted keys, UUIDs and timestamps replaced by placeholders. A missing snapshot is
+// written; set DECO_UPDATE_SNAPSHOTS=1 to rewrite the changed ones.
+//
+//	decotest.Snapshot(t, recorder, decotest.ScrubFields("token"))
+//
+// resp is an *httptest.ResponseRecorder, an *http.Response or a *Result.
+func Snapshot(t testing.TB, resp interface{}, opts ...SnapshotOption) {
+	t.Helper()
+	options := &snapshotOptions{fields: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.values = append(options.values,
+		snapshotRule{pattern: snapshotUUID, placeholder: "<uuid>"},
+		snapshotRule{pattern: snapshotTimestamp, placeholder: "<timestamp>"})
+
+	status, header, body, err := snapshotResponse(resp)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	got, err := canonicalSnapshot(status, header, body, options)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	path := filepath.Join(SnapshotDir, snapshotFileName(t, options.name)+".json")
+	want, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err) || (err == nil && os.Getenv(UpdateSnapshotsEnv) != "" && !bytes.Equal(want, got)):
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("snapshot: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("snapshot: %v", err)
+		}
+		t.Logf("snapshot written: %s", path)
+	case err != nil:
+		t.Fatalf("snapshot: %v", err)
+	case !bytes.Equal(want, got):
+		t.Errorf("response does not match %s (%s=1 to update):\n%s", path, UpdateSnapshotsEnv, snapshotDiff(string(want), string(got)))
+	}
+}
+
+// snapshotResponse status, headers and body of the supported response types
+func snapshotResponse(resp interface{}) (int, http.Header, []byte, error) {
+	switch r := resp.(type) {
+	case *httptest.ResponseRecorder:
+		return r.Code, r.Header(), r.Body.Bytes(), nil
+	case *Result:
+		return r.Recorder.Code, r.Recorder.Header(), r.Recorder.Body.Bytes(), nil
+	case *http.Response:
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		return r.StatusCode, r.Header, body, err
+	default:
+		return 0, nil, nil, fmt.Errorf("unsupported response type %T", resp)
+	}
+}
+
+// canonicalSnapshot indented JSON of the response with sorted keys and the volatile values scrubbed;
+// a body that is not JSON is kept as a string
+func canonicalSnapshot(status int, header http.Header, body []byte, options *snapshotOptions) ([]byte, error) {
+	headers := map[string]interface{}{}
+	for _, name := range append([]string{"Content-Type"}, options.headers...) {
+		if value := header.Get(name); value != "" {
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	var content interface{} = string(body)
+	if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var decoded interface{}
+		if decoder.Decode(&decoded) == nil {
+			content = decoded
+		}
+	}
+
+	snapshot := map[string]interface{}{
+		"status":  status,
+		"headers": scrubSnapshot(headers, options),
+		"body":    scrubSnapshot(content, options),
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scrubSnapshot replaces the scrubbed fields and values; maps are encoded with sorted keys
+func scrubSnapshot(value interface{}, options *snapshotOptions) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if options.fields[key] {
+				v[key] = "<scrubbed>"
+				continue
+			}
+			v[key] = scrubSnapshot(item, options)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrubSnapshot(item, options)
+		}
+	case string:
+		for _, rule := range options.values {
+			v = rule.pattern.ReplaceAllString(v, rule.placeholder)
+		}
+		return v
+	}
+	return value
+}
+
+// snapshotFileName file name of the snapshot: the test name, numbered from the second snapshot
+func snapshotFileName(t testing.TB, name string) string {
+	if name == "" {
+		snapshotCallsMu.Lock()
+		snapshotCalls[t]++
+		calls := snapshotCalls[t]
+		snapshotCallsMu.Unlock()
+		if calls == 1 {
+			t.Cleanup(func() {
+				snapshotCallsMu.Lock()
+				delete(snapshotCalls, t)
+				snapshotCallsMu.Unlock()
+			})
+		}
+
+		name = t.Name()
+		if calls > 1 {
+			name = fmt.Sprintf("%s_%d", name, calls)
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// snapshotDiff line diff of the stored and current snapshots, the changed lines with their context
+func snapshotDiff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	// Longest common subsequence, from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	last := -1
+	for n, l := range lines {
+		near := false
+		for k := max(n-snapshotContext, 0); k <= min(n+snapshotContext, len(lines)-1); k++ {
+			near = near || lines[k].op != ' '
+		}
+		if !near {
+			continue
+		}
+		if last >= 0 && n > last+1 {
+			out.WriteString("  ...\n")
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+		last = n
+	}
+	return out.String()
+}
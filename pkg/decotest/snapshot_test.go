@@ -0,0 +1,96 @@
+package decotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotTB records the failures of Snapshot instead of failing the test
+type snapshotTB struct {
+	*testing.T
+	failures []string
+}
+
+func (s *snapshotTB) Errorf(format string, args ...interface{}) {
+	s.failures = append(s.failures, fmt.Sprintf(format, args...))
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	previous := SnapshotDir
+	SnapshotDir = dir
+	defer func() { SnapshotDir = previous }()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	name := "Ada"
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"name":       name,
+			"id":         "5f8e6a3c-1b2d-4c5e-9f0a-7b8c9d0e1f2a",
+			"created_at": "2026-10-15T10:00:00.123Z",
+			"token":      c.Query("token"),
+			"order":      "ORD-" + c.Param("id"),
+		})
+	})
+	serve := func(id, token string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/"+id+"?token="+token, nil))
+		return recorder
+	}
+	opts := []SnapshotOption{ScrubFields("token"), ScrubValues(regexp.MustCompile(`ORD-\d+`), "<order>")}
+
+	tb := &snapshotTB{T: t}
+	Snapshot(tb, serve("1", "a"), opts...)
+	stored, err := os.ReadFile(filepath.Join(dir, "TestSnapshot.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": 200, "headers": {"Content-Type": "application/json; charset=utf-8"}, "body": {
+		"name": "Ada", "id": "<uuid>", "created_at": "<timestamp>", "token": "<scrubbed>", "order": "<order>"}}`, string(stored))
+
+	// Scrubbed values may change between runs
+	Snapshot(tb, serve("2", "b"), append(opts, SnapshotName("TestSnapshot"))...)
+	assert.Empty(t, tb.failures)
+
+	name = "Grace"
+	Snapshot(tb, serve("3", "c"), append(opts, SnapshotName("TestSnapshot"))...)
+	require.Len(t, tb.failures, 1)
+	assert.Contains(t, tb.failures[0], `-     "name": "Ada",`)
+	assert.Contains(t, tb.failures[0], `+     "name": "Grace",`)
+
+	t.Setenv(UpdateSnapshotsEnv, "1")
+	Snapshot(tb, serve("3", "c"), append(opts, SnapshotName("TestSnapshot"))...)
+	stored, err = os.ReadFile(filepath.Join(dir, "TestSnapshot.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stored), "Grace")
+}
+
+func TestSnapshot_Numbered(t *testing.T) {
+	previous := SnapshotDir
+	SnapshotDir = t.TempDir()
+	defer func() { SnapshotDir = previous }()
+
+	result, err := RunMarker("Cache", []string{"ttl=1m"}, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	require.NoError(t, err)
+	Snapshot(t, result)
+	Snapshot(t, result)
+	assert.FileExists(t, filepath.Join(SnapshotDir, "TestSnapshot_Numbered.json"))
+	assert.FileExists(t, filepath.Join(SnapshotDir, "TestSnapshot_Numbered_2.json"))
+
+	stored, err := os.ReadFile(filepath.Join(SnapshotDir, "TestSnapshot_Numbered.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stored), `"body": "ok"`, "bodies that are not JSON are kept as text")
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	diff := snapshotDiff("a\nb\nc\nd\ne\nf\ng\nh\ni\n", "a\nb\nc\nd\nE\nf\ng\nh\ni\n")
+	assert.Equal(t, "  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n", diff)
+}